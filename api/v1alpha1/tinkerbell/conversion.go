@@ -0,0 +1,9 @@
+package tinkerbell
+
+// Hub marks Hardware as the conversion hub. v1alpha1 is the storage version,
+// all other versions convert to and from it.
+func (*Hardware) Hub() {}
+
+// Hub marks Workflow as the conversion hub. v1alpha1 is the storage version,
+// all other versions convert to and from it.
+func (*Workflow) Hub() {}
@@ -136,6 +136,26 @@ type Interface struct {
 	// +kubebuilder:default=false
 	// +optional
 	DisableDHCP bool `json:"disableDhcp,omitempty"`
+
+	// SwitchPort is the switch port the interface is connected to.
+	// Smee uses it to find the Hardware of a relayed DHCP message when no Hardware has the client's MAC address,
+	// for example after a NIC or motherboard was replaced.
+	// +optional
+	SwitchPort *SwitchPort `json:"switchPort,omitempty"`
+}
+
+// SwitchPort identifies a switch port by the relay agent information (DHCP option 82) that the
+// DHCP relay agent on the switch adds to the messages it relays.
+// Sub-option values that are not printable text are written as lowercase hex, without separators.
+type SwitchPort struct {
+	// CircuitID is the agent circuit ID, sub-option 1. It usually identifies the port of the switch.
+	// +kubebuilder:validation:MinLength=1
+	CircuitID string `json:"circuitID"`
+
+	// RemoteID is the agent remote ID, sub-option 2. It usually identifies the switch.
+	// When empty, the circuit ID alone identifies the port.
+	// +optional
+	RemoteID string `json:"remoteID,omitempty"`
 }
 
 // PXELINUX represents PXELinux configuration, for u-boot "pxelinux.cfg" booting.
@@ -208,6 +228,37 @@ type Netboot struct {
 
 	//+optional
 	RPI *RPI `json:"rpi,omitempty"`
+
+	// HTTPBoot, when defined, boots UEFI HTTP Boot clients without iPXE.
+	//+optional
+	HTTPBoot *HTTPBoot `json:"httpBoot,omitempty"`
+}
+
+// HTTPBootMode is the type of boot file served to UEFI HTTP Boot clients.
+type HTTPBootMode string
+
+const (
+	HTTPBootModeGRUB HTTPBootMode = "grub"
+	HTTPBootModeUKI  HTTPBootMode = "uki"
+)
+
+// HTTPBoot represents UEFI HTTP Boot configuration.
+// UEFI clients that identify as "HTTPClient" in DHCP option 60 are given the URL of a boot file that Smee serves
+// from its HTTP boot asset directory, instead of the URL of an iPXE binary.
+// This allows Secure Boot machines, which refuse to run unsigned iPXE binaries, to network boot.
+type HTTPBoot struct {
+	// Mode is the type of boot file to serve.
+	// "grub" serves a signed shim that loads GRUB from the same directory. GRUB loads a grub.cfg that Smee generates
+	// from the same OSIE kernel, initrd and kernel parameters that the iPXE script uses.
+	// "uki" serves a Unified Kernel Image, which contains the kernel, the initrd and the kernel command line.
+	// +kubebuilder:validation:Enum=grub;uki
+	Mode HTTPBootMode `json:"mode"`
+
+	// BootFile, when defined, overrides the default boot file of the mode and architecture.
+	// The defaults are shimx64.efi and shimaa64.efi for "grub", and uki-x64.efi and uki-aa64.efi for "uki".
+	// It is a path relative to Smee's HTTP boot asset directory.
+	// +optional
+	BootFile string `json:"bootFile,omitempty"`
 }
 
 // Isoboot configuration for booting a client using an ISO image.
@@ -428,31 +479,52 @@ type HardwareStatus struct {
 	// disjoint subtree and neither can clobber the other.
 	//+optional
 	Attributes *HardwareAttributes `json:"attributes,omitempty"`
+
+	// Agent is the liveness of the Agent running on the Hardware, as recorded from its heartbeats by tink-server.
+	//+optional
+	Agent *AgentStatus `json:"agent,omitempty"`
+}
+
+// AgentStatus is the last heartbeat of an Agent.
+type AgentStatus struct {
+	// ID is the ID of the Agent.
+	//+optional
+	ID string `json:"id,omitempty"`
+
+	// LastSeen is when the last heartbeat of the Agent was recorded. Heartbeats are recorded at most once a minute.
+	//+optional
+	LastSeen *metav1.Time `json:"lastSeen,omitempty"`
+
+	// ActionID is the ID of the Action the Agent was running at its last heartbeat. It is empty when the Agent was idle.
+	//+optional
+	ActionID string `json:"actionID,omitempty"`
 }
 
 // HardwareAttributes holds hardware attribute subtrees, one per collection path.
 // Each subtree is written by a single owner and the owners touch disjoint paths,
 // so no merge keys or per-field precedence rules are needed.
 //
-// Only OutOfBand exists today. An InBand sibling, written by tink-server from the
-// Agent-reported attributes that currently land in the
-// "tinkerbell.org/agent-attributes" annotation, is the intended next step — it
-// shares the Attributes type below, and adding it is a purely additive change.
+// OutOfBand is written by Rufio from the BMC, InBand by tink-server from the
+// attributes the Agent reports.
 type HardwareAttributes struct {
 	// OutOfBand holds attributes collected via the BMC (Redfish or vendor API).
 	// Available before the machine boots. IPMI-only BMCs cannot provide this data
 	// and will not populate this field.
 	//+optional
 	OutOfBand *Attributes `json:"outOfBand,omitempty"`
+
+	// InBand holds attributes collected by the Agent from inside the running OS.
+	// It is written once, when the Agent first asks for an Action of a Workflow, alongside
+	// the "tinkerbell.org/agent-attributes" annotation.
+	//+optional
+	InBand *Attributes `json:"inBand,omitempty"`
 }
 
 // Attributes is a source-agnostic description of a machine's hardware, intended to
-// be shared by both the out-of-band and the (not yet implemented) in-band
-// collection paths. It is the superset of what either can report: every field is
+// be shared by both the out-of-band and the in-band collection paths. It is the superset of what either can report: every field is
 // optional, and an absent field means the producing source did not report it, not
 // an error. Field coverage varies by source, and for the out-of-band path also by
-// BMC vendor/protocol. Fields only one source can ever populate are marked as such;
-// those noted "in-band only" are unset until that collector exists.
+// BMC vendor/protocol. Fields only one source can ever populate are marked as such.
 type Attributes struct {
 	// LastUpdated is the time at which this subtree was last refreshed.
 	//+optional
@@ -519,7 +591,7 @@ type Attributes struct {
 	//+optional
 	PSUs []PSU `json:"psus,omitempty"`
 
-	// TPMs lists trusted platform modules. Out-of-band only.
+	// TPMs lists trusted platform modules. In-band reports only the interface type.
 	//+optional
 	TPMs []TPM `json:"tpms,omitempty"`
 
@@ -627,7 +699,8 @@ type BlockDevice struct {
 	//+optional
 	PhysicalBlockSizeBytes int64 `json:"physicalBlockSizeBytes,omitempty"`
 	// SmartStatus is the drive's self-reported SMART health status (e.g. "ok",
-	// "predict-failure"). Out-of-band only.
+	// "predict-failure" out-of-band, "passed" or "failed" in-band). In-band requires
+	// smartctl in the Agent's image.
 	//+optional
 	SmartStatus string `json:"smartStatus,omitempty"`
 	//+optional
@@ -675,6 +748,27 @@ type NetworkPort struct {
 	// EnabledCapabilities lists enabled offloads and features. In-band only.
 	//+optional
 	EnabledCapabilities []string `json:"enabledCapabilities,omitempty"`
+	// LLDPNeighbors lists the switch ports the port is connected to, as advertised in
+	// the LLDP frames received on it. In-band only.
+	//+optional
+	LLDPNeighbors []LLDPNeighbor `json:"lldpNeighbors,omitempty"`
+}
+
+// LLDPNeighbor describes the device on the other end of a link, as advertised in an
+// LLDP frame. In-band only.
+type LLDPNeighbor struct {
+	// ChassisID identifies the neighbour, usually its MAC address.
+	//+optional
+	ChassisID string `json:"chassisID,omitempty"`
+	// PortID identifies the neighbour's port, usually its interface name (e.g. "Ethernet1/12").
+	//+optional
+	PortID string `json:"portID,omitempty"`
+	//+optional
+	PortDescription string `json:"portDescription,omitempty"`
+	//+optional
+	SystemName string `json:"systemName,omitempty"`
+	//+optional
+	SystemDescription string `json:"systemDescription,omitempty"`
 }
 
 // GPUDevice describes a GPU or accelerator device.
@@ -806,7 +900,7 @@ type PSU struct {
 	Status *ComponentStatus `json:"status,omitempty"`
 }
 
-// TPM describes a trusted platform module. Out-of-band only.
+// TPM describes a trusted platform module.
 type TPM struct {
 	//+optional
 	Vendor string `json:"vendor,omitempty"`
@@ -21,6 +21,9 @@ const (
 	WorkflowStateSuccess   = WorkflowState("SUCCESS")
 	WorkflowStateFailed    = WorkflowState("FAILED")
 	WorkflowStateTimeout   = WorkflowState("TIMEOUT")
+	WorkflowStateCanceled  = WorkflowState("CANCELED")
+	// WorkflowStateSkipped is the state of an Action whose If condition evaluated to false.
+	WorkflowStateSkipped = WorkflowState("SKIPPED")
 
 	BootJobFailed           WorkflowConditionType = "BootJobFailed"
 	BootJobComplete         WorkflowConditionType = "BootJobComplete"
@@ -30,6 +33,10 @@ const (
 	ToggleAllowNetbootTrue  WorkflowConditionType = "AllowNetbootTrue"
 	ToggleAllowNetbootFalse WorkflowConditionType = "AllowNetbootFalse"
 	TemplateRenderedSuccess WorkflowConditionType = "TemplateRenderedSuccess"
+	WorkflowCanceled        WorkflowConditionType = "WorkflowCanceled"
+	WorkflowResumed         WorkflowConditionType = "WorkflowResumed"
+	// AgentLost is set when the Agent running a Workflow stopped sending heartbeats.
+	AgentLost WorkflowConditionType = "AgentLost"
 
 	TemplateRenderingSuccessful TemplateRendering = "successful"
 	TemplateRenderingFailed     TemplateRendering = "failed"
@@ -88,6 +95,34 @@ type WorkflowSpec struct {
 	// BootOptions are options that control the booting of Hardware.
 	// These are only applicable when a HardwareRef is provided.
 	BootOptions BootOptions `json:"bootOptions,omitempty,omitzero"`
+
+	// Cancel stops the Workflow. The Agent is told to stop the running Action and the Workflow
+	// is moved to the CANCELED state.
+	// +optional
+	Cancel bool `json:"cancel,omitempty"`
+
+	// Resume re-runs the Workflow starting from an Action. The Action and all Actions after it
+	// are reset and the Workflow is moved back to the RUNNING state. Resume is only applied to
+	// Workflows in a final state (SUCCESS, FAILED, TIMEOUT, or CANCELED) and when Cancel is false.
+	// BootOptions are not run again.
+	// +optional
+	Resume *WorkflowResume `json:"resume,omitempty"`
+}
+
+// WorkflowResume defines where a Workflow is resumed from.
+type WorkflowResume struct {
+	// ActionName is the name of the Action to resume from.
+	ActionName string `json:"actionName"`
+
+	// TaskName is the name of the Task the Action belongs to.
+	// Defaults to the first Task with an Action named ActionName.
+	// +optional
+	TaskName string `json:"taskName,omitempty"`
+
+	// ID distinguishes resume requests for the same Action, a timestamp for example.
+	// Change it to resume from the same Action again.
+	// +optional
+	ID string `json:"id,omitempty"`
 }
 
 // BootOptions are options that control the booting of Hardware.
@@ -183,6 +218,10 @@ type WorkflowStatus struct {
 	// CurrentState tracks where the workflow is in its execution.
 	CurrentState *CurrentState `json:"currentState,omitempty"`
 
+	// LastResume is the last spec.resume that was applied.
+	// +optional
+	LastResume *WorkflowResume `json:"lastResume,omitempty"`
+
 	// Tasks are the tasks to be run by the Agent(s).
 	Tasks []Task `json:"tasks,omitempty"`
 
@@ -237,6 +276,12 @@ type Task struct {
 	Actions     []Action          `json:"actions"`
 	Volumes     []string          `json:"volumes,omitempty"`
 	Environment map[string]string `json:"environment,omitempty"`
+	// DependsOn is a list of Task names that must complete successfully before this Task is started.
+	// When no Task in a Workflow sets DependsOn, Tasks run one after the other in order.
+	// When any Task sets DependsOn, a Task starts as soon as the Tasks it depends on are complete,
+	// so Tasks assigned to different Agents can run at the same time.
+	// +optional
+	DependsOn []string `json:"dependsOn,omitempty"`
 }
 
 // Action represents a workflow action.
@@ -257,8 +302,126 @@ type Action struct {
 	ExecutionStop     *metav1.Time      `json:"executionStop,omitempty"`
 	ExecutionDuration string            `json:"executionDuration,omitempty"`
 	Message           string            `json:"message,omitempty"`
+	// OnTimeout is the command run, using the Action image, when the Action times out.
+	// +optional
+	OnTimeout []string `json:"onTimeout,omitempty"`
+	// OnFailure is the command run, using the Action image, when the Action fails.
+	// +optional
+	OnFailure []string `json:"onFailure,omitempty"`
+	// Hook is the result of running the OnTimeout or OnFailure command.
+	// +optional
+	Hook *ActionHook `json:"hook,omitempty"`
+	// Retries is the maximum number of times the Action is run until it succeeds.
+	// Zero and one both mean the Action is run once.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Retries int64 `json:"retries,omitempty"`
+	// RetryDelay is the number of seconds to wait between runs of the Action.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	RetryDelay int64 `json:"retryDelay,omitempty"`
+	// Attempt is the number of the current, or last, run of the Action. It starts at 1.
+	// +optional
+	Attempt int64 `json:"attempt,omitempty"`
+	// If determines whether the Action is run. It is evaluated by the Tink Server right before the Action is served.
+	// The value is a Go template using "[[" and "]]" as delimiters, so that it is not rendered with the rest of the Template.
+	// The template data has "actions", the results of the Actions in the same Task keyed by Action name,
+	// and "tasks", the results of the Actions in all Tasks keyed by Task name and then Action name.
+	// Each result has a "state", a "message" and "outputs". For example, [[ eq (index .actions "detect-disk").state "SUCCESS" ]].
+	// The rendered value must be "true", "false", or empty. Empty and "true" run the Action, case-insensitive.
+	// Anything else marks the Action as SKIPPED.
+	// +optional
+	If string `json:"if,omitempty"`
+	// Outputs are the key/value pairs the Action wrote to the file in the TINKERBELL_OUTPUT environment variable.
+	// Outputs are available to later Actions, in their If and Environment values, as
+	// [[ (index .actions "detect-disk").outputs.device ]].
+	// +optional
+	Outputs map[string]string `json:"outputs,omitempty"`
+	// Type is how the Action runs. Native Actions run Command directly on the host and have no Image.
+	// Their OnTimeout and OnFailure commands run on the host too.
+	// +kubebuilder:validation:Enum=container;native
+	// +optional
+	Type ActionType `json:"type,omitempty"`
+	// Sandbox restricts what the Action container can do and use. When not set the container runs
+	// with the runtime's defaults, privileged and without limits for all runtimes but kubernetes.
+	// +optional
+	Sandbox *ActionSandbox `json:"sandbox,omitempty"`
+}
+
+// ActionSandbox restricts what an Action container can do and use.
+type ActionSandbox struct {
+	// Resources limits the CPU, memory and processes the container can use.
+	// +optional
+	Resources *ActionResources `json:"resources,omitempty"`
+	// Capabilities are the Linux capabilities added to, and dropped from, the runtime's default set.
+	// They require Privileged to be false, a privileged container has all capabilities.
+	// +optional
+	Capabilities *ActionCapabilities `json:"capabilities,omitempty"`
+	// ReadOnlyRootFilesystem mounts the root filesystem of the container read-only. Volumes keep their own mode.
+	// +optional
+	ReadOnlyRootFilesystem bool `json:"readOnlyRootFilesystem,omitempty"`
+	// Privileged runs the container with all capabilities and access to all host devices.
+	// When not set the runtime's default is used, privileged for all runtimes but kubernetes.
+	// +optional
+	Privileged *bool `json:"privileged,omitempty"`
+}
+
+// ActionResources are the resource limits of an Action container.
+type ActionResources struct {
+	// CPU is the maximum number of CPUs the container can use, as a quantity like 500m or 2.
+	// +optional
+	CPU string `json:"cpu,omitempty"`
+	// Memory is the maximum memory the container can use, as a quantity like 512Mi.
+	// The container is killed when it uses more.
+	// +optional
+	Memory string `json:"memory,omitempty"`
+	// PIDs is the maximum number of processes in the container.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	PIDs int64 `json:"pids,omitempty"`
 }
 
+// ActionCapabilities are the Linux capabilities added to, and dropped from, an Action container.
+// Names are with or without the CAP_ prefix, like NET_ADMIN or CAP_NET_ADMIN. ALL means all capabilities.
+type ActionCapabilities struct {
+	// +optional
+	Add []string `json:"add,omitempty"`
+	// +optional
+	Drop []string `json:"drop,omitempty"`
+}
+
+// ActionHook is the result of running an on-timeout or on-failure hook.
+type ActionHook struct {
+	// Name is the hook that ran, either "on-timeout" or "on-failure".
+	Name ActionHookName `json:"name"`
+	// State is the final state of the hook.
+	State WorkflowState `json:"state,omitempty"`
+	// Message is the message returned from the hook.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// ActionType is how an Action runs.
+type ActionType string
+
+const (
+	// ActionTypeContainer runs the Action in a container started from its image. It is the default.
+	ActionTypeContainer ActionType = "container"
+	// ActionTypeNative runs the Action's command directly on the host, without a container.
+	// The Tink Server only serves native Actions whose command is in its native command allowlist.
+	ActionTypeNative ActionType = "native"
+)
+
+// ActionHookName identifies an Action hook.
+type ActionHookName string
+
+const (
+	// ActionHookOnTimeout runs when an Action times out.
+	ActionHookOnTimeout ActionHookName = "on-timeout"
+	// ActionHookOnFailure runs when an Action fails.
+	ActionHookOnFailure ActionHookName = "on-failure"
+)
+
 // ActionNamespaces defines the Linux namespaces an action container runs in.
 // This mirrors the v1alpha2 API spec.
 type ActionNamespaces struct {
@@ -277,6 +440,50 @@ type ActionNamespaces struct {
 	PID string `json:"pid,omitempty"`
 }
 
+// HasTaskDependencies reports whether any Task in the Workflow declares dependencies on other Tasks.
+func (w *WorkflowStatus) HasTaskDependencies() bool {
+	for _, t := range w.Tasks {
+		if len(t.DependsOn) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// DependenciesComplete reports whether all the Tasks that t depends on are complete.
+// A dependency that does not exist in the Workflow is never complete.
+func (w *WorkflowStatus) DependenciesComplete(t Task) bool {
+	for _, name := range t.DependsOn {
+		found := false
+		for _, dep := range w.Tasks {
+			if dep.Name == name {
+				found = true
+				if !dep.IsComplete() {
+					return false
+				}
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// IsComplete reports whether all Actions in the Task have completed successfully or were skipped.
+func (t Task) IsComplete() bool {
+	for _, a := range t.Actions {
+		if a.State != WorkflowStateSuccess && a.State != WorkflowStateSkipped {
+			return false
+		}
+	}
+
+	return true
+}
+
 // HasCondition checks if the cType condition is present with status cStatus on a bmj.
 func (w *WorkflowStatus) HasCondition(wct WorkflowConditionType, cs metav1.ConditionStatus) bool {
 	for _, c := range w.Conditions {
@@ -47,3 +47,35 @@ func TestSetCondition(t *testing.T) {
 		})
 	}
 }
+
+func TestDependenciesComplete(t *testing.T) {
+	status := &WorkflowStatus{
+		Tasks: []Task{
+			{Name: "a", Actions: []Action{{State: WorkflowStateSuccess}, {State: WorkflowStateSuccess}}},
+			{Name: "b", Actions: []Action{{State: WorkflowStateSuccess}, {State: WorkflowStateRunning}}},
+			{Name: "c", DependsOn: []string{"a"}},
+			{Name: "d", Actions: []Action{{State: WorkflowStateSkipped}, {State: WorkflowStateSuccess}}},
+		},
+	}
+	tests := map[string]struct {
+		task Task
+		want bool
+	}{
+		"no dependencies":         {task: Task{Name: "x"}, want: true},
+		"complete dependency":     {task: Task{Name: "x", DependsOn: []string{"a"}}, want: true},
+		"incomplete dependency":   {task: Task{Name: "x", DependsOn: []string{"a", "b"}}, want: false},
+		"missing dependency":      {task: Task{Name: "x", DependsOn: []string{"missing"}}, want: false},
+		"dependency with no work": {task: Task{Name: "x", DependsOn: []string{"c"}}, want: true},
+		"skipped actions":         {task: Task{Name: "x", DependsOn: []string{"d"}}, want: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := status.DependenciesComplete(tt.task); got != tt.want {
+				t.Errorf("DependenciesComplete() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if !status.HasTaskDependencies() {
+		t.Error("HasTaskDependencies() = false, want true")
+	}
+}
@@ -60,6 +60,33 @@ func (in *Action) DeepCopyInto(out *Action) {
 		in, out := &in.ExecutionStop, &out.ExecutionStop
 		*out = (*in).DeepCopy()
 	}
+	if in.OnTimeout != nil {
+		in, out := &in.OnTimeout, &out.OnTimeout
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.OnFailure != nil {
+		in, out := &in.OnFailure, &out.OnFailure
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Hook != nil {
+		in, out := &in.Hook, &out.Hook
+		*out = new(ActionHook)
+		**out = **in
+	}
+	if in.Outputs != nil {
+		in, out := &in.Outputs, &out.Outputs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Sandbox != nil {
+		in, out := &in.Sandbox, &out.Sandbox
+		*out = new(ActionSandbox)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Action.
@@ -72,6 +99,46 @@ func (in *Action) DeepCopy() *Action {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActionCapabilities) DeepCopyInto(out *ActionCapabilities) {
+	*out = *in
+	if in.Add != nil {
+		in, out := &in.Add, &out.Add
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Drop != nil {
+		in, out := &in.Drop, &out.Drop
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActionCapabilities.
+func (in *ActionCapabilities) DeepCopy() *ActionCapabilities {
+	if in == nil {
+		return nil
+	}
+	out := new(ActionCapabilities)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActionHook) DeepCopyInto(out *ActionHook) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActionHook.
+func (in *ActionHook) DeepCopy() *ActionHook {
+	if in == nil {
+		return nil
+	}
+	out := new(ActionHook)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ActionNamespaces) DeepCopyInto(out *ActionNamespaces) {
 	*out = *in
@@ -87,6 +154,70 @@ func (in *ActionNamespaces) DeepCopy() *ActionNamespaces {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActionResources) DeepCopyInto(out *ActionResources) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActionResources.
+func (in *ActionResources) DeepCopy() *ActionResources {
+	if in == nil {
+		return nil
+	}
+	out := new(ActionResources)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActionSandbox) DeepCopyInto(out *ActionSandbox) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(ActionResources)
+		**out = **in
+	}
+	if in.Capabilities != nil {
+		in, out := &in.Capabilities, &out.Capabilities
+		*out = new(ActionCapabilities)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Privileged != nil {
+		in, out := &in.Privileged, &out.Privileged
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActionSandbox.
+func (in *ActionSandbox) DeepCopy() *ActionSandbox {
+	if in == nil {
+		return nil
+	}
+	out := new(ActionSandbox)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentStatus) DeepCopyInto(out *AgentStatus) {
+	*out = *in
+	if in.LastSeen != nil {
+		in, out := &in.LastSeen, &out.LastSeen
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentStatus.
+func (in *AgentStatus) DeepCopy() *AgentStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AllowNetbootStatus) DeepCopyInto(out *AllowNetbootStatus) {
 	*out = *in
@@ -548,6 +679,21 @@ func (in *GPUDevice) DeepCopy() *GPUDevice {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPBoot) DeepCopyInto(out *HTTPBoot) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPBoot.
+func (in *HTTPBoot) DeepCopy() *HTTPBoot {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPBoot)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Hardware) DeepCopyInto(out *Hardware) {
 	*out = *in
@@ -583,6 +729,11 @@ func (in *HardwareAttributes) DeepCopyInto(out *HardwareAttributes) {
 		*out = new(Attributes)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.InBand != nil {
+		in, out := &in.InBand, &out.InBand
+		*out = new(Attributes)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareAttributes.
@@ -732,6 +883,11 @@ func (in *HardwareStatus) DeepCopyInto(out *HardwareStatus) {
 		*out = new(HardwareAttributes)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Agent != nil {
+		in, out := &in.Agent, &out.Agent
+		*out = new(AgentStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareStatus.
@@ -792,6 +948,11 @@ func (in *Interface) DeepCopyInto(out *Interface) {
 		*out = new(DHCP)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.SwitchPort != nil {
+		in, out := &in.SwitchPort, &out.SwitchPort
+		*out = new(SwitchPort)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Interface.
@@ -834,6 +995,21 @@ func (in *JobStatus) DeepCopy() *JobStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LLDPNeighbor) DeepCopyInto(out *LLDPNeighbor) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LLDPNeighbor.
+func (in *LLDPNeighbor) DeepCopy() *LLDPNeighbor {
+	if in == nil {
+		return nil
+	}
+	out := new(LLDPNeighbor)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Memory) DeepCopyInto(out *Memory) {
 	*out = *in
@@ -1228,6 +1404,11 @@ func (in *Netboot) DeepCopyInto(out *Netboot) {
 		*out = new(RPI)
 		**out = **in
 	}
+	if in.HTTPBoot != nil {
+		in, out := &in.HTTPBoot, &out.HTTPBoot
+		*out = new(HTTPBoot)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Netboot.
@@ -1270,6 +1451,11 @@ func (in *NetworkPort) DeepCopyInto(out *NetworkPort) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.LLDPNeighbors != nil {
+		in, out := &in.LLDPNeighbors, &out.LLDPNeighbors
+		*out = make([]LLDPNeighbor, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPort.
@@ -1422,6 +1608,21 @@ func (in *StorageController) DeepCopy() *StorageController {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwitchPort) DeepCopyInto(out *SwitchPort) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwitchPort.
+func (in *SwitchPort) DeepCopy() *SwitchPort {
+	if in == nil {
+		return nil
+	}
+	out := new(SwitchPort)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TPM) DeepCopyInto(out *TPM) {
 	*out = *in
@@ -1464,6 +1665,11 @@ func (in *Task) DeepCopyInto(out *Task) {
 			(*out)[key] = val
 		}
 	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Task.
@@ -1670,6 +1876,21 @@ func (in *WorkflowList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkflowResume) DeepCopyInto(out *WorkflowResume) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkflowResume.
+func (in *WorkflowResume) DeepCopy() *WorkflowResume {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkflowResume)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkflowRuleSet) DeepCopyInto(out *WorkflowRuleSet) {
 	*out = *in
@@ -1802,6 +2023,11 @@ func (in *WorkflowSpec) DeepCopyInto(out *WorkflowSpec) {
 		}
 	}
 	in.BootOptions.DeepCopyInto(&out.BootOptions)
+	if in.Resume != nil {
+		in, out := &in.Resume, &out.Resume
+		*out = new(WorkflowResume)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkflowSpec.
@@ -1827,6 +2053,11 @@ func (in *WorkflowStatus) DeepCopyInto(out *WorkflowStatus) {
 		*out = new(CurrentState)
 		**out = **in
 	}
+	if in.LastResume != nil {
+		in, out := &in.LastResume, &out.LastResume
+		*out = new(WorkflowResume)
+		**out = **in
+	}
 	if in.Tasks != nil {
 		in, out := &in.Tasks, &out.Tasks
 		*out = make([]Task, len(*in))
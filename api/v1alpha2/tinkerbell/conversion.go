@@ -0,0 +1,109 @@
+package tinkerbell
+
+import (
+	"encoding/json"
+	"fmt"
+
+	v1alpha1 "github.com/tinkerbell/tinkerbell/api/v1alpha1/tinkerbell"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ConversionDataAnnotation holds the JSON encoded spec and status of the object a conversion started from.
+// v1alpha1 and v1alpha2 do not have the same set of fields, so converting from one to the other is lossy.
+// Converting back to the original version uses this annotation to restore the fields the other version cannot represent.
+const ConversionDataAnnotation = "tinkerbell.org/conversion-data"
+
+// conversionData is the content of the ConversionDataAnnotation.
+// +kubebuilder:object:generate=false
+type conversionData struct {
+	Spec   json.RawMessage `json:"spec,omitempty"`
+	Status json.RawMessage `json:"status,omitempty"`
+}
+
+// marshalConversionData stores the spec and status of the source object in the annotations of dst.
+func marshalConversionData(dst *metav1.ObjectMeta, spec, status any) error {
+	s, err := json.Marshal(spec)
+	if err != nil {
+		return fmt.Errorf("error marshaling spec for conversion: %w", err)
+	}
+	st, err := json.Marshal(status)
+	if err != nil {
+		return fmt.Errorf("error marshaling status for conversion: %w", err)
+	}
+	b, err := json.Marshal(conversionData{Spec: s, Status: st})
+	if err != nil {
+		return fmt.Errorf("error marshaling conversion data: %w", err)
+	}
+	if dst.Annotations == nil {
+		dst.Annotations = map[string]string{}
+	}
+	dst.Annotations[ConversionDataAnnotation] = string(b)
+
+	return nil
+}
+
+// unmarshalConversionData restores the spec and status stored by marshalConversionData in the annotations of src.
+// It returns false when src has no conversion data.
+func unmarshalConversionData(src metav1.ObjectMeta, spec, status any) (bool, error) {
+	raw, ok := src.Annotations[ConversionDataAnnotation]
+	if !ok {
+		return false, nil
+	}
+	var d conversionData
+	if err := json.Unmarshal([]byte(raw), &d); err != nil {
+		return false, fmt.Errorf("error unmarshaling conversion data: %w", err)
+	}
+	if len(d.Spec) > 0 {
+		if err := json.Unmarshal(d.Spec, spec); err != nil {
+			return false, fmt.Errorf("error unmarshaling spec from conversion data: %w", err)
+		}
+	}
+	if len(d.Status) > 0 {
+		if err := json.Unmarshal(d.Status, status); err != nil {
+			return false, fmt.Errorf("error unmarshaling status from conversion data: %w", err)
+		}
+	}
+
+	return true, nil
+}
+
+// convertObjectMeta returns a copy of src without the conversion data annotation.
+func convertObjectMeta(src metav1.ObjectMeta) metav1.ObjectMeta {
+	dst := *src.DeepCopy()
+	delete(dst.Annotations, ConversionDataAnnotation)
+	if len(dst.Annotations) == 0 {
+		dst.Annotations = nil
+	}
+
+	return dst
+}
+
+var (
+	toV1Alpha1State = map[State]v1alpha1.WorkflowState{
+		StatePreparing: v1alpha1.WorkflowStatePreparing,
+		StatePending:   v1alpha1.WorkflowStatePending,
+		StateRunning:   v1alpha1.WorkflowStateRunning,
+		StatePost:      v1alpha1.WorkflowStatePost,
+		StateSuccess:   v1alpha1.WorkflowStateSuccess,
+		StateFailed:    v1alpha1.WorkflowStateFailed,
+		StateTimeout:   v1alpha1.WorkflowStateTimeout,
+	}
+	fromV1Alpha1State = map[v1alpha1.WorkflowState]State{
+		v1alpha1.WorkflowStatePreparing: StatePreparing,
+		v1alpha1.WorkflowStatePending:   StatePending,
+		v1alpha1.WorkflowStateRunning:   StateRunning,
+		v1alpha1.WorkflowStatePost:      StatePost,
+		v1alpha1.WorkflowStateSuccess:   StateSuccess,
+		v1alpha1.WorkflowStateFailed:    StateFailed,
+		v1alpha1.WorkflowStateTimeout:   StateTimeout,
+		// v1alpha2 has no canceled state, the closest terminal state is failed.
+		// The original state is restored from the conversion data annotation.
+		v1alpha1.WorkflowStateCanceled: StateFailed,
+		// v1alpha2 does not keep Actions whose If is false, the closest state is success.
+		v1alpha1.WorkflowStateSkipped: StateSuccess,
+	}
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
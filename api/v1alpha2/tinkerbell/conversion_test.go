@@ -0,0 +1,406 @@
+package tinkerbell
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/tinkerbell/tinkerbell/api/v1alpha1/bmc"
+	v1alpha1 "github.com/tinkerbell/tinkerbell/api/v1alpha1/tinkerbell"
+	v1alpha2bmc "github.com/tinkerbell/tinkerbell/api/v1alpha2/tinkerbell/bmc"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var ignoreConversionData = cmpopts.IgnoreFields(metav1.ObjectMeta{}, "Annotations")
+
+func v1alpha1Hardware() *v1alpha1.Hardware {
+	return &v1alpha1.Hardware{
+		ObjectMeta: metav1.ObjectMeta{Name: "machine1", Namespace: "tinkerbell", Labels: map[string]string{"rack": "1"}},
+		Spec: v1alpha1.HardwareSpec{
+			AgentID:    "00:00:00:00:00:01",
+			BMCRef:     &corev1.TypedLocalObjectReference{Name: "bmc1", Kind: "Machine"},
+			References: map[string]v1alpha1.Reference{"config": {Name: "config", Namespace: "tinkerbell", Resource: "configmaps", Version: "v1"}},
+			Interfaces: []v1alpha1.Interface{
+				{
+					Netboot: &v1alpha1.Netboot{
+						AllowPXE:      ptr(true),
+						AllowWorkflow: ptr(true),
+						IPXE:          &v1alpha1.IPXE{URL: "http://example.com/auto.ipxe"},
+						OSIE: &v1alpha1.OSIE{
+							BaseURL:      "http://example.com/hook",
+							Kernel:       "vmlinuz-x86_64",
+							Initrd:       "initramfs-x86_64",
+							KernelParams: []string{"console=ttyS0"},
+						},
+					},
+					DHCP: &v1alpha1.DHCP{
+						MAC:         "00:00:00:00:00:01",
+						Hostname:    "machine1",
+						LeaseTime:   86400,
+						NameServers: []string{"1.1.1.1"},
+						TimeServers: []string{"time.example.com"},
+						Arch:        "x86_64",
+						UEFI:        true,
+						IfaceName:   "eth0",
+						IP: &v1alpha1.IP{
+							Address: "192.168.2.10",
+							Netmask: "255.255.255.0",
+							Gateway: "192.168.2.1",
+						},
+						VLANID: "10",
+					},
+				},
+				{
+					DisableDHCP: true,
+					DHCP:        &v1alpha1.DHCP{MAC: "00:00:00:00:00:02"},
+				},
+			},
+			Metadata: &v1alpha1.HardwareMetadata{
+				Instance: &v1alpha1.MetadataInstance{ID: "machine1", SSHKeys: []string{"ssh-ed25519 AAAA"}},
+			},
+			Disks:    []v1alpha1.Disk{{Device: "/dev/sda"}},
+			UserData: ptr("#cloud-config"),
+		},
+		Status: v1alpha1.HardwareStatus{State: v1alpha1.HardwareReady},
+	}
+}
+
+func TestHardwareConvertFrom(t *testing.T) {
+	want := &Hardware{
+		ObjectMeta: metav1.ObjectMeta{Name: "machine1", Namespace: "tinkerbell", Labels: map[string]string{"rack": "1"}},
+		Spec: HardwareSpec{
+			AgentID:    "00:00:00:00:00:01",
+			Arch:       "x86_64",
+			References: map[string]Reference{"config": {Name: "config", Namespace: "tinkerbell", Resource: "configmaps", Version: "v1"}},
+			Instance: &Instance{
+				OSIE: &OSIE{
+					KernelURL:    "http://example.com/hook/vmlinuz-x86_64",
+					InitrdURL:    "http://example.com/hook/initramfs-x86_64",
+					KernelParams: []string{"console=ttyS0"},
+				},
+				SSHKeys:  []string{"ssh-ed25519 AAAA"},
+				Userdata: ptr("#cloud-config"),
+			},
+			NetworkInterfaces: NetworkInterfaces{
+				"00:00:00:00:00:01": {
+					DHCP: &DHCP{IPv4: &DHCPv4{
+						Hostname:         ptr("machine1"),
+						LeaseTimeSeconds: ptr(int64(86400)),
+						Nameservers:      []Nameserver{"1.1.1.1"},
+						NTPServers:       []Timeserver{"time.example.com"},
+						VLANID:           ptr("10"),
+					}},
+					IPAM:    &IPAM{IPv4: &IP{Address: "192.168.2.10", Gateway: "192.168.2.1", Prefix: "24"}},
+					Netboot: &Netboot{IPXE: &IPXE{URL: "http://example.com/auto.ipxe"}},
+				},
+				"00:00:00:00:00:02": {
+					DHCP:    &DHCP{IPv4: &DHCPv4{Disabled: true}},
+					Netboot: &Netboot{Disabled: true},
+				},
+			},
+			StorageDevices: []StorageDevice{{Name: "/dev/sda"}},
+		},
+	}
+
+	got := &Hardware{}
+	if err := got.ConvertFrom(v1alpha1Hardware()); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, got, ignoreConversionData); diff != "" {
+		t.Errorf("unexpected Hardware (-want +got):\n%s", diff)
+	}
+}
+
+func TestHardwareRoundTrip(t *testing.T) {
+	tests := map[string]struct {
+		hw *v1alpha1.Hardware
+	}{
+		"full":  {hw: v1alpha1Hardware()},
+		"empty": {hw: &v1alpha1.Hardware{ObjectMeta: metav1.ObjectMeta{Name: "empty"}}},
+		"uppercase mac and iso": {hw: &v1alpha1.Hardware{Spec: v1alpha1.HardwareSpec{Interfaces: []v1alpha1.Interface{
+			{
+				DHCP:    &v1alpha1.DHCP{MAC: "AA:BB:CC:DD:EE:FF", IP: &v1alpha1.IP{Address: "fd00::10", Netmask: "64", Family: 6}},
+				Isoboot: &v1alpha1.Isoboot{SourceISO: "http://example.com/hook.iso"},
+			},
+			{Netboot: &v1alpha1.Netboot{AllowPXE: ptr(true)}},
+		}}}},
+		"http boot": {hw: &v1alpha1.Hardware{Spec: v1alpha1.HardwareSpec{Interfaces: []v1alpha1.Interface{
+			{Netboot: &v1alpha1.Netboot{AllowPXE: ptr(true), HTTPBoot: &v1alpha1.HTTPBoot{Mode: v1alpha1.HTTPBootModeUKI, BootFile: "uki/hook-x64.efi"}}},
+		}}}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			hub := &Hardware{}
+			if err := hub.ConvertFrom(tc.hw); err != nil {
+				t.Fatal(err)
+			}
+			got := &v1alpha1.Hardware{}
+			if err := hub.ConvertTo(got); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.hw, got, ignoreConversionData, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("unexpected Hardware after round trip (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestHardwareRoundTripFromV1Alpha2(t *testing.T) {
+	want := &Hardware{
+		ObjectMeta: metav1.ObjectMeta{Name: "machine1", Namespace: "tinkerbell"},
+		Spec: HardwareSpec{
+			Arch: "aarch64",
+			BMC:  &BMC{},
+			Instance: &Instance{
+				LookupID: "machine1",
+				OSIE: &OSIE{
+					KernelURL: "http://example.com/kernel/vmlinuz",
+					InitrdURL: "http://example.com/initrd/initramfs",
+				},
+			},
+			NetworkInterfaces: NetworkInterfaces{
+				"00:00:00:00:00:01": {
+					DHCP: &DHCP{
+						IPv4: &DHCPv4{DomainSearchList: []string{"example.com"}},
+						IPv6: &DHCPv6{},
+					},
+					IPAM: &IPAM{
+						IPv4: &IP{Address: "192.168.2.10", Prefix: "24"},
+						IPv6: &IP{Address: "fd00::10", Prefix: "64"},
+					},
+				},
+			},
+		},
+		Status: HardwareStatus{Conditions: []v1alpha2bmc.Condition{{Type: "Contactable", Status: "True"}}},
+	}
+
+	hub := &v1alpha1.Hardware{}
+	if err := want.DeepCopy().ConvertTo(hub); err != nil {
+		t.Fatal(err)
+	}
+	got := &Hardware{}
+	if err := got.ConvertFrom(hub); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, got, ignoreConversionData, cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("unexpected Hardware after round trip (-want +got):\n%s", diff)
+	}
+}
+
+func v1alpha1Workflow() *v1alpha1.Workflow {
+	start := metav1.Unix(1700000000, 0)
+	return &v1alpha1.Workflow{
+		ObjectMeta: metav1.ObjectMeta{Name: "wf1", Namespace: "tinkerbell"},
+		Spec: v1alpha1.WorkflowSpec{
+			TemplateRef: "debian",
+			HardwareRef: "machine1",
+			HardwareMap: map[string]string{"device_1": "00:00:00:00:00:01"},
+			BootOptions: v1alpha1.BootOptions{
+				ToggleAllowNetboot: true,
+				BootMode:           v1alpha1.BootModeISO,
+				ISOURL:             "http://example.com/hook.iso",
+				CustombootConfig: v1alpha1.CustombootConfig{
+					PreparingActions: []bmc.Action{{PowerAction: ptr(bmc.PowerHardOff)}},
+				},
+			},
+		},
+		Status: v1alpha1.WorkflowStatus{
+			AgentID:           "00:00:00:00:00:01",
+			State:             v1alpha1.WorkflowStateRunning,
+			TemplateRendering: v1alpha1.TemplateRenderingSuccessful,
+			GlobalTimeout:     600,
+			CurrentState: &v1alpha1.CurrentState{
+				AgentID:    "00:00:00:00:00:01",
+				TaskID:     "task1",
+				ActionID:   "action1",
+				State:      v1alpha1.WorkflowStateRunning,
+				ActionName: "stream",
+				TaskName:   "os-installation",
+			},
+			Tasks: []v1alpha1.Task{{
+				ID:          "task1",
+				Name:        "os-installation",
+				AgentID:     "00:00:00:00:00:01",
+				Volumes:     []string{"/dev:/dev"},
+				Environment: map[string]string{"FOO": "bar"},
+				Actions: []v1alpha1.Action{{
+					ID:             "action1",
+					Name:           "stream",
+					Image:          "quay.io/tinkerbell/actions/image2disk:v1.0.0",
+					Timeout:        600,
+					Command:        []string{"--verbose"},
+					Pid:            "host",
+					Namespaces:     &v1alpha1.ActionNamespaces{Network: "host"},
+					Retries:        3,
+					RetryDelay:     10,
+					State:          v1alpha1.WorkflowStateRunning,
+					ExecutionStart: &start,
+				}},
+			}},
+			Conditions: []v1alpha1.WorkflowCondition{{Type: v1alpha1.TemplateRenderedSuccess, Status: metav1.ConditionTrue}},
+		},
+	}
+}
+
+func TestWorkflowConvertFrom(t *testing.T) {
+	start := metav1.Unix(1700000000, 0)
+	want := &Workflow{
+		ObjectMeta: metav1.ObjectMeta{Name: "wf1", Namespace: "tinkerbell"},
+		Spec: WorkflowSpec{
+			Tasks: []WorkflowTask{{
+				TaskRef: SimpleReference{Name: "debian", Namespace: "tinkerbell"},
+				Hardware: &WorkflowHardware{
+					HardwareRef: &SimpleReference{Name: "machine1", Namespace: "tinkerbell"},
+					BootOptions: &BootOptions{ToggleNetboot: true, BootMode: BootModeIsoboot, ISOURL: "http://example.com/hook.iso"},
+				},
+			}},
+			Vars: map[string]string{"device_1": "00:00:00:00:00:01"},
+		},
+		Status: WorkflowStatus{
+			Metadata: WorkflowMetadata{
+				Workflow: Metadata{AgentID: "00:00:00:00:00:01", State: StateRunning},
+				Task:     Metadata{AgentID: "00:00:00:00:00:01", ID: "task1", Name: "os-installation"},
+				Action:   Metadata{ID: "action1", Name: "stream", State: StateRunning},
+			},
+			GlobalTimeout: 600,
+			RenderedTasks: []TaskWithMetadata{{
+				Name:     "os-installation",
+				Env:      map[string]string{"FOO": "bar"},
+				Volumes:  []Volume{"/dev:/dev"},
+				Metadata: Metadata{AgentID: "00:00:00:00:00:01", ID: "task1"},
+				Actions: []ActionWithMetadata{{
+					Action: Action{
+						Name:           "stream",
+						Image:          "quay.io/tinkerbell/actions/image2disk:v1.0.0",
+						Args:           []string{"--verbose"},
+						Namespaces:     Namespaces{Network: "host", PID: "host"},
+						Retries:        3,
+						TimeoutSeconds: ptr(int64(600)),
+					},
+					Metadata: Metadata{ID: "action1", State: StateRunning, StartTime: &start},
+				}},
+			}},
+			Conditions: []WorkflowCondition{{Type: TemplateRenderedSuccess, Status: metav1.ConditionTrue}},
+		},
+	}
+
+	got := &Workflow{}
+	if err := got.ConvertFrom(v1alpha1Workflow()); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, got, ignoreConversionData); diff != "" {
+		t.Errorf("unexpected Workflow (-want +got):\n%s", diff)
+	}
+}
+
+func TestWorkflowRoundTrip(t *testing.T) {
+	tests := map[string]struct {
+		wf *v1alpha1.Workflow
+	}{
+		"full":  {wf: v1alpha1Workflow()},
+		"empty": {wf: &v1alpha1.Workflow{ObjectMeta: metav1.ObjectMeta{Name: "empty"}}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			hub := &Workflow{}
+			if err := hub.ConvertFrom(tc.wf); err != nil {
+				t.Fatal(err)
+			}
+			got := &v1alpha1.Workflow{}
+			if err := hub.ConvertTo(got); err != nil {
+				t.Fatal(err)
+			}
+			// The status round trip is tested in TestWorkflowStatusRoundTrip.
+			ignoreStatus := cmpopts.IgnoreFields(v1alpha1.Workflow{}, "Status")
+			if diff := cmp.Diff(tc.wf, got, ignoreConversionData, ignoreStatus, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("unexpected Workflow after round trip (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWorkflowStatusRoundTrip(t *testing.T) {
+	// v1alpha1 only status fields are restored from the conversion data annotation.
+	want := v1alpha1Workflow()
+
+	hub := &Workflow{}
+	if err := hub.ConvertFrom(v1alpha1Workflow()); err != nil {
+		t.Fatal(err)
+	}
+	got := &v1alpha1.Workflow{}
+	if err := hub.ConvertTo(got); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want.Status, got.Status, cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("unexpected Workflow status after round trip (-want +got):\n%s", diff)
+	}
+}
+
+func TestWorkflowRoundTripFromV1Alpha2(t *testing.T) {
+	start := metav1.Unix(1700000000, 0)
+	want := &Workflow{
+		ObjectMeta: metav1.ObjectMeta{Name: "wf1", Namespace: "tinkerbell"},
+		Spec: WorkflowSpec{
+			TimeoutSeconds: ptr(int64(1800)),
+			Globals:        &Extra{Env: map[string]string{"FOO": "bar"}},
+			Tasks: []WorkflowTask{
+				{
+					AgentID: "00:00:00:00:00:01",
+					TaskRef: SimpleReference{Name: "debian", Namespace: "templates"},
+					Hardware: &WorkflowHardware{
+						HardwareRef: &SimpleReference{Name: "machine1", Namespace: "tinkerbell"},
+					},
+				},
+				{TaskRef: SimpleReference{Name: "cleanup", Namespace: "tinkerbell"}},
+			},
+		},
+		Status: WorkflowStatus{
+			Metadata: WorkflowMetadata{
+				Workflow: Metadata{ID: "wf-id", State: StateRunning, StartTime: &start, Message: "running"},
+				Task:     Metadata{ID: "task1", Name: "os-installation", State: StateTimeout, StartTime: &start},
+				Action:   Metadata{ID: "action1", Name: "stream", State: StateTimeout, Hardware: "machine1"},
+			},
+			RenderedTasks: []TaskWithMetadata{{
+				Name:     "os-installation",
+				Metadata: Metadata{ID: "task1", State: StateTimeout, StartTime: &start, Message: "timed out"},
+				Actions: []ActionWithMetadata{{
+					Action: Action{
+						Name: "stream", Image: "alpine", Command: "/bin/sh", If: "true", Retries: 2, Background: true,
+						Sandbox: &Sandbox{
+							Resources:              &SandboxResources{CPU: "500m", Memory: "512Mi", PIDs: 100},
+							Capabilities:           &SandboxCapabilities{Add: []string{"NET_ADMIN"}, Drop: []string{"ALL"}},
+							ReadOnlyRootFilesystem: true,
+							Privileged:             ptr(false),
+						},
+					},
+					Metadata: Metadata{ID: "action1", Message: "done"},
+				}},
+			}},
+		},
+	}
+
+	hub := &v1alpha1.Workflow{}
+	if err := want.DeepCopy().ConvertTo(hub); err != nil {
+		t.Fatal(err)
+	}
+	got := &Workflow{}
+	if err := got.ConvertFrom(hub); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, got, ignoreConversionData, cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("unexpected Workflow after round trip (-want +got):\n%s", diff)
+	}
+
+}
+
+func TestConvertWrongHub(t *testing.T) {
+	if err := (&Hardware{}).ConvertTo(&v1alpha1.Workflow{}); err == nil {
+		t.Error("expected an error converting Hardware to a Workflow")
+	}
+	if err := (&Workflow{}).ConvertFrom(&v1alpha1.Hardware{}); err == nil {
+		t.Error("expected an error converting a Workflow from Hardware")
+	}
+}
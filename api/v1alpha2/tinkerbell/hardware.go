@@ -405,6 +405,37 @@ type Netboot struct {
 	// RPI (Raspberry Pi) configuration.
 	// +optional
 	RPI *RPI `json:"rpi,omitempty"`
+
+	// HTTPBoot (UEFI HTTP Boot without iPXE) configuration.
+	// +optional
+	HTTPBoot *HTTPBoot `json:"httpBoot,omitempty"`
+}
+
+// HTTPBootMode is the type of boot file served to UEFI HTTP Boot clients.
+type HTTPBootMode string
+
+const (
+	HTTPBootModeGRUB HTTPBootMode = "grub"
+	HTTPBootModeUKI  HTTPBootMode = "uki"
+)
+
+// HTTPBoot represents UEFI HTTP Boot configuration.
+// UEFI clients that identify as "HTTPClient" in DHCP option 60 are given the URL of a boot file that Tinkerbell serves
+// from its HTTP boot asset directory, instead of the URL of an iPXE binary.
+// This allows Secure Boot machines, which refuse to run unsigned iPXE binaries, to network boot.
+type HTTPBoot struct {
+	// Mode is the type of boot file to serve.
+	// "grub" serves a signed shim that loads GRUB from the same directory. GRUB loads a grub.cfg that Tinkerbell generates
+	// from the same OSIE kernel, initrd and kernel parameters that the iPXE script uses.
+	// "uki" serves a Unified Kernel Image, which contains the kernel, the initrd and the kernel command line.
+	// +kubebuilder:validation:Enum=grub;uki
+	Mode HTTPBootMode `json:"mode"`
+
+	// BootFile, when defined, overrides the default boot file of the mode and architecture.
+	// The defaults are shimx64.efi and shimaa64.efi for "grub", and uki-x64.efi and uki-aa64.efi for "uki".
+	// It is a path relative to Tinkerbell's HTTP boot asset directory.
+	// +optional
+	BootFile string `json:"bootFile,omitempty"`
 }
 
 // IPXE configuration.
@@ -0,0 +1,581 @@
+package tinkerbell
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	v1alpha1 "github.com/tinkerbell/tinkerbell/api/v1alpha1/tinkerbell"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+// ConvertTo converts this Hardware to the Hub version (v1alpha1).
+func (h *Hardware) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*v1alpha1.Hardware)
+	if !ok {
+		return fmt.Errorf("unsupported conversion hub type %T, expected *v1alpha1.Hardware", dstRaw)
+	}
+	src := h.DeepCopy()
+
+	// Start from the v1alpha1 Hardware stored by ConvertFrom, if any,
+	// so that fields v1alpha2 cannot represent are not lost.
+	base := &v1alpha1.Hardware{}
+	if _, err := unmarshalConversionData(src.ObjectMeta, &base.Spec, &base.Status); err != nil {
+		return err
+	}
+
+	dst.ObjectMeta = convertObjectMeta(src.ObjectMeta)
+	dst.Spec = hardwareSpecToV1Alpha1(src.Spec, base.Spec)
+	dst.Status = base.Status
+
+	return marshalConversionData(&dst.ObjectMeta, h.Spec, h.Status)
+}
+
+// ConvertFrom converts from the Hub version (v1alpha1) to this Hardware.
+func (h *Hardware) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*v1alpha1.Hardware)
+	if !ok {
+		return fmt.Errorf("unsupported conversion hub type %T, expected *v1alpha1.Hardware", srcRaw)
+	}
+	src = src.DeepCopy()
+
+	// Start from the v1alpha2 Hardware stored by ConvertTo, if any,
+	// so that fields v1alpha1 cannot represent are not lost.
+	base := &Hardware{}
+	if _, err := unmarshalConversionData(src.ObjectMeta, &base.Spec, &base.Status); err != nil {
+		return err
+	}
+
+	h.ObjectMeta = convertObjectMeta(src.ObjectMeta)
+	h.Spec = hardwareSpecFromV1Alpha1(src.Spec, base.Spec)
+	h.Status = base.Status
+
+	return marshalConversionData(&h.ObjectMeta, src.Spec, src.Status)
+}
+
+// hardwareSpecToV1Alpha1 sets every field of dst that has a v1alpha2 equivalent from src.
+// Fields without an equivalent are left as they are in dst.
+func hardwareSpecToV1Alpha1(src HardwareSpec, dst v1alpha1.HardwareSpec) v1alpha1.HardwareSpec {
+	dst.AgentID = src.AgentID
+	dst.Auto.EnrollmentEnabled = src.Auto.EnrollmentEnabled
+
+	dst.References = nil
+	for name, ref := range src.References {
+		if dst.References == nil {
+			dst.References = make(map[string]v1alpha1.Reference, len(src.References))
+		}
+		dst.References[name] = v1alpha1.Reference{
+			Namespace: ref.Namespace,
+			Name:      ref.Name,
+			Group:     ref.Group,
+			Version:   ref.Version,
+			Resource:  ref.Resource,
+		}
+	}
+
+	dst.Disks = nil
+	for _, sd := range src.StorageDevices {
+		dst.Disks = append(dst.Disks, v1alpha1.Disk{Device: sd.Name})
+	}
+
+	instance := src.Instance
+	if instance == nil {
+		instance = &Instance{}
+	}
+	dst.UserData = instance.Userdata
+	dst.VendorData = instance.Vendordata
+	if len(instance.SSHKeys) > 0 || (dst.Metadata != nil && dst.Metadata.Instance != nil) {
+		if dst.Metadata == nil {
+			dst.Metadata = &v1alpha1.HardwareMetadata{}
+		}
+		if dst.Metadata.Instance == nil {
+			dst.Metadata.Instance = &v1alpha1.MetadataInstance{}
+		}
+		dst.Metadata.Instance.SSHKeys = instance.SSHKeys
+	}
+
+	wide := hardwareWide{arch: src.Arch, osie: instance.OSIE}
+	if instance.OSIE != nil {
+		wide.isoURL = instance.OSIE.ISOURL
+	}
+	dst.Interfaces = interfacesToV1Alpha1(src.NetworkInterfaces, wide, dst.Interfaces)
+
+	return dst
+}
+
+// hardwareWide holds the values that v1alpha2 defines once per Hardware and v1alpha1 defines per Interface.
+// +kubebuilder:object:generate=false
+type hardwareWide struct {
+	arch   string
+	osie   *OSIE
+	isoURL string
+}
+
+// forInterface returns the values to set on iface. A value is only set on the Interfaces that already
+// defined it, or on all Interfaces when none of the existing Interfaces defined it.
+func (h hardwareWide) forInterface(iface v1alpha1.Interface, existing []v1alpha1.Interface) hardwareWide {
+	hasArch := func(i v1alpha1.Interface) bool { return i.DHCP != nil && i.DHCP.Arch != "" }
+	hasOSIE := func(i v1alpha1.Interface) bool { return i.Netboot != nil && i.Netboot.OSIE != nil }
+	hasISO := func(i v1alpha1.Interface) bool { return i.Isoboot != nil }
+
+	out := h
+	if slices.ContainsFunc(existing, hasArch) && !hasArch(iface) {
+		out.arch = ""
+	}
+	if slices.ContainsFunc(existing, hasOSIE) && !hasOSIE(iface) {
+		out.osie = nil
+	}
+	if slices.ContainsFunc(existing, hasISO) && !hasISO(iface) {
+		out.isoURL = ""
+	}
+
+	return out
+}
+
+// interfacesToV1Alpha1 converts NetworkInterfaces to a list of v1alpha1 Interfaces.
+// The order of the existing Interfaces is kept and new Interfaces are appended sorted by MAC address.
+// Existing Interfaces without a MAC address cannot be represented in v1alpha2 and are kept as they are.
+func interfacesToV1Alpha1(src NetworkInterfaces, wide hardwareWide, existing []v1alpha1.Interface) []v1alpha1.Interface {
+	var out []v1alpha1.Interface
+	seen := map[MAC]bool{}
+	for _, iface := range existing {
+		if iface.DHCP == nil || iface.DHCP.MAC == "" {
+			out = append(out, iface)
+			continue
+		}
+		mac := MAC(strings.ToLower(iface.DHCP.MAC))
+		ni, ok := src[mac]
+		if !ok || seen[mac] {
+			continue
+		}
+		seen[mac] = true
+		out = append(out, interfaceToV1Alpha1(mac, ni, wide.forInterface(iface, existing), iface))
+	}
+
+	macs := make([]MAC, 0, len(src))
+	for mac := range src {
+		if !seen[mac] {
+			macs = append(macs, mac)
+		}
+	}
+	slices.Sort(macs)
+	for _, mac := range macs {
+		iface := v1alpha1.Interface{}
+		out = append(out, interfaceToV1Alpha1(mac, src[mac], wide.forInterface(iface, existing), iface))
+	}
+
+	return out
+}
+
+func interfaceToV1Alpha1(mac MAC, src NetworkInterface, wide hardwareWide, dst v1alpha1.Interface) v1alpha1.Interface {
+	if dst.DHCP == nil {
+		dst.DHCP = &v1alpha1.DHCP{}
+	}
+	// Keep the MAC as it was written in v1alpha1 when it only differs by case.
+	if !strings.EqualFold(dst.DHCP.MAC, string(mac)) {
+		dst.DHCP.MAC = string(mac)
+	}
+	dst.DHCP.Arch = wide.arch
+
+	v4 := &DHCPv4{}
+	if src.DHCP != nil && src.DHCP.IPv4 != nil {
+		v4 = src.DHCP.IPv4
+	}
+	dst.DisableDHCP = v4.Disabled
+	dst.DHCP.Hostname = deref(v4.Hostname)
+	dst.DHCP.DomainName = v4.DomainName
+	dst.DHCP.LeaseTime = deref(v4.LeaseTimeSeconds)
+	dst.DHCP.NameServers = toStrings(v4.Nameservers)
+	dst.DHCP.TimeServers = toStrings(v4.NTPServers)
+	dst.DHCP.VLANID = deref(v4.VLANID)
+	dst.DHCP.TFTPServerName = v4.TFTPServerName
+	dst.DHCP.BootFileName = v4.BootFileName
+	dst.DHCP.ClasslessStaticRoutes = nil
+	for _, r := range v4.ClasslessStaticRoutes {
+		dst.DHCP.ClasslessStaticRoutes = append(dst.DHCP.ClasslessStaticRoutes, v1alpha1.ClasslessStaticRoute{
+			DestinationDescriptor: r.DestinationDescriptor,
+			Router:                r.Router,
+		})
+	}
+
+	dst.DHCP.IP = ipToV1Alpha1(src.IPAM, dst.DHCP.IP)
+	dst.Netboot = netbootToV1Alpha1(src.Netboot, wide.osie, dst.Netboot)
+	dst.Isoboot = nil
+	if wide.isoURL != "" {
+		dst.Isoboot = &v1alpha1.Isoboot{SourceISO: wide.isoURL}
+	}
+
+	return dst
+}
+
+// ipToV1Alpha1 converts IPAM to a v1alpha1 IP. v1alpha1 only holds a single address,
+// so the IPv4 address is used when both an IPv4 and an IPv6 address are defined.
+func ipToV1Alpha1(src *IPAM, dst *v1alpha1.IP) *v1alpha1.IP {
+	if src == nil || (src.IPv4 == nil && src.IPv6 == nil) {
+		return nil
+	}
+	if dst == nil {
+		dst = &v1alpha1.IP{}
+	}
+	ip := src.IPv4
+	if ip == nil {
+		ip = src.IPv6
+		dst.Family = 6
+	} else if dst.Family == 6 {
+		dst.Family = 4
+	}
+	dst.Address = ip.Address
+	dst.Gateway = ip.Gateway
+	if dst.Family == 6 {
+		dst.Netmask = ip.Prefix
+	} else {
+		dst.Netmask = prefixToNetmask(ip.Prefix)
+	}
+
+	return dst
+}
+
+func netbootToV1Alpha1(src *Netboot, osie *OSIE, dst *v1alpha1.Netboot) *v1alpha1.Netboot {
+	if dst == nil {
+		dst = &v1alpha1.Netboot{}
+	}
+	if src == nil {
+		src = &Netboot{}
+	}
+
+	// A nil AllowPXE already means netbooting is not allowed, keep it that way when nothing changed.
+	if allow := !src.Disabled; allow || dst.AllowPXE != nil {
+		dst.AllowPXE = ptr(allow)
+	}
+
+	dst.IPXE = nil
+	if src.IPXE != nil {
+		dst.IPXE = &v1alpha1.IPXE{URL: src.IPXE.URL, Contents: src.IPXE.Script, Binary: src.IPXE.Binary}
+	}
+	dst.PXELINUX = nil
+	if src.PXELINUX != nil {
+		dst.PXELINUX = &v1alpha1.PXELINUX{Config: src.PXELINUX.Config}
+	}
+	dst.RPI = nil
+	if src.RPI != nil {
+		dst.RPI = &v1alpha1.RPI{ConfigTxt: src.RPI.ConfigTxt, FirmwarePath: src.RPI.FirmwarePath, SerialNum: src.RPI.SerialNum}
+	}
+	dst.HTTPBoot = nil
+	if src.HTTPBoot != nil {
+		dst.HTTPBoot = &v1alpha1.HTTPBoot{Mode: v1alpha1.HTTPBootMode(src.HTTPBoot.Mode), BootFile: src.HTTPBoot.BootFile}
+	}
+	dst.OSIE = osieToV1Alpha1(osie, dst.OSIE)
+
+	if reflect.DeepEqual(*dst, v1alpha1.Netboot{}) {
+		return nil
+	}
+
+	return dst
+}
+
+// osieToV1Alpha1 splits the kernel and initrd URLs into the v1alpha1 base URL and file names.
+func osieToV1Alpha1(src *OSIE, dst *v1alpha1.OSIE) *v1alpha1.OSIE {
+	if src == nil || (src.KernelURL == "" && src.InitrdURL == "" && len(src.KernelParams) == 0) {
+		return nil
+	}
+	if dst == nil {
+		dst = &v1alpha1.OSIE{}
+	}
+	dst.KernelParams = src.KernelParams
+
+	// Keep the existing values when they still point to the same files.
+	if joinURL(dst.BaseURL, dst.Kernel) == src.KernelURL && joinURL(dst.BaseURL, dst.Initrd) == src.InitrdURL {
+		return dst
+	}
+	base, kernel := splitURL(src.KernelURL)
+	initrdBase, initrd := splitURL(src.InitrdURL)
+	switch {
+	case src.KernelURL == "":
+		base = initrdBase
+	case src.InitrdURL != "" && initrdBase != base:
+		// v1alpha1 has a single base URL, the initrd can only be referenced relative to it.
+		initrd = src.InitrdURL
+	}
+	dst.BaseURL, dst.Kernel, dst.Initrd = base, kernel, initrd
+
+	return dst
+}
+
+// hardwareSpecFromV1Alpha1 sets every field of dst that has a v1alpha1 equivalent from src.
+// Fields without an equivalent are left as they are in dst.
+func hardwareSpecFromV1Alpha1(src v1alpha1.HardwareSpec, dst HardwareSpec) HardwareSpec {
+	dst.AgentID = src.AgentID
+	dst.Auto.EnrollmentEnabled = src.Auto.EnrollmentEnabled
+
+	dst.References = nil
+	for name, ref := range src.References {
+		if dst.References == nil {
+			dst.References = make(map[string]Reference, len(src.References))
+		}
+		dst.References[name] = Reference{
+			Group:     ref.Group,
+			Name:      ref.Name,
+			Namespace: ref.Namespace,
+			Resource:  ref.Resource,
+			Version:   ref.Version,
+		}
+	}
+
+	dst.StorageDevices = nil
+	for _, d := range src.Disks {
+		dst.StorageDevices = append(dst.StorageDevices, StorageDevice{Name: d.Device})
+	}
+
+	dst.Arch = ""
+	for _, iface := range src.Interfaces {
+		if iface.DHCP != nil && iface.DHCP.Arch != "" {
+			dst.Arch = iface.DHCP.Arch
+			break
+		}
+	}
+
+	instance := dst.Instance
+	if instance == nil {
+		instance = &Instance{}
+	}
+	instance.Userdata = src.UserData
+	instance.Vendordata = src.VendorData
+	instance.SSHKeys = nil
+	if src.Metadata != nil && src.Metadata.Instance != nil {
+		instance.SSHKeys = src.Metadata.Instance.SSHKeys
+	}
+	instance.OSIE = osieFromV1Alpha1(src.Interfaces, instance.OSIE)
+	dst.Instance = instance
+	if reflect.DeepEqual(*instance, Instance{}) {
+		dst.Instance = nil
+	}
+
+	existing := dst.NetworkInterfaces
+	dst.NetworkInterfaces = nil
+	for _, iface := range src.Interfaces {
+		if iface.DHCP == nil || iface.DHCP.MAC == "" {
+			continue
+		}
+		mac := MAC(strings.ToLower(iface.DHCP.MAC))
+		if _, ok := dst.NetworkInterfaces[mac]; ok {
+			continue
+		}
+		if dst.NetworkInterfaces == nil {
+			dst.NetworkInterfaces = NetworkInterfaces{}
+		}
+		dst.NetworkInterfaces[mac] = interfaceFromV1Alpha1(iface, existing[mac])
+	}
+
+	return dst
+}
+
+func interfaceFromV1Alpha1(src v1alpha1.Interface, dst NetworkInterface) NetworkInterface {
+	if dst.DHCP == nil {
+		dst.DHCP = &DHCP{}
+	}
+	v4 := dst.DHCP.IPv4
+	if v4 == nil {
+		v4 = &DHCPv4{}
+	}
+	v4.Disabled = src.DisableDHCP
+	v4.Hostname = nonZero(src.DHCP.Hostname)
+	v4.DomainName = src.DHCP.DomainName
+	v4.LeaseTimeSeconds = nonZero(src.DHCP.LeaseTime)
+	v4.Nameservers = fromStrings[Nameserver](src.DHCP.NameServers)
+	v4.NTPServers = fromStrings[Timeserver](src.DHCP.TimeServers)
+	v4.VLANID = nonZero(src.DHCP.VLANID)
+	v4.TFTPServerName = src.DHCP.TFTPServerName
+	v4.BootFileName = src.DHCP.BootFileName
+	v4.ClasslessStaticRoutes = nil
+	for _, r := range src.DHCP.ClasslessStaticRoutes {
+		v4.ClasslessStaticRoutes = append(v4.ClasslessStaticRoutes, ClasslessStaticRoute{
+			DestinationDescriptor: r.DestinationDescriptor,
+			Router:                r.Router,
+		})
+	}
+	dst.DHCP.IPv4 = v4
+	if reflect.DeepEqual(*v4, DHCPv4{}) {
+		dst.DHCP.IPv4 = nil
+	}
+	if reflect.DeepEqual(*dst.DHCP, DHCP{}) {
+		dst.DHCP = nil
+	}
+
+	dst.IPAM = ipFromV1Alpha1(src.DHCP.IP, dst.IPAM)
+	dst.Netboot = netbootFromV1Alpha1(src.Netboot, dst.Netboot)
+
+	return dst
+}
+
+func ipFromV1Alpha1(src *v1alpha1.IP, dst *IPAM) *IPAM {
+	if dst == nil {
+		dst = &IPAM{}
+	}
+	switch {
+	case src == nil:
+		dst.IPv4 = nil
+	case src.Family == 6:
+		dst.IPv6 = &IP{Address: src.Address, Gateway: src.Gateway, Prefix: src.Netmask}
+	default:
+		dst.IPv4 = &IP{Address: src.Address, Gateway: src.Gateway, Prefix: netmaskToPrefix(src.Netmask)}
+	}
+	if dst.IPv4 == nil && dst.IPv6 == nil {
+		return nil
+	}
+
+	return dst
+}
+
+func netbootFromV1Alpha1(src *v1alpha1.Netboot, dst *Netboot) *Netboot {
+	if dst == nil {
+		dst = &Netboot{}
+	}
+	if src == nil {
+		src = &v1alpha1.Netboot{}
+	}
+	// In v1alpha1 netbooting is only allowed when explicitly enabled.
+	dst.Disabled = src.AllowPXE == nil || !*src.AllowPXE
+
+	dst.IPXE = nil
+	if src.IPXE != nil {
+		dst.IPXE = &IPXE{Binary: src.IPXE.Binary, Script: src.IPXE.Contents, URL: src.IPXE.URL}
+	}
+	dst.PXELINUX = nil
+	if src.PXELINUX != nil {
+		dst.PXELINUX = &PXELINUX{Config: src.PXELINUX.Config}
+	}
+	dst.RPI = nil
+	if src.RPI != nil {
+		dst.RPI = &RPI{ConfigTxt: src.RPI.ConfigTxt, FirmwarePath: src.RPI.FirmwarePath, SerialNum: src.RPI.SerialNum}
+	}
+	dst.HTTPBoot = nil
+	if src.HTTPBoot != nil {
+		dst.HTTPBoot = &HTTPBoot{Mode: HTTPBootMode(src.HTTPBoot.Mode), BootFile: src.HTTPBoot.BootFile}
+	}
+	if reflect.DeepEqual(*dst, Netboot{}) {
+		return nil
+	}
+
+	return dst
+}
+
+// osieFromV1Alpha1 returns the OSIE configuration of the first Interface that defines one.
+// v1alpha2 defines the OSIE once per Hardware instead of per Interface.
+func osieFromV1Alpha1(src []v1alpha1.Interface, dst *OSIE) *OSIE {
+	var o *v1alpha1.OSIE
+	var iso string
+	for _, iface := range src {
+		if o == nil && iface.Netboot != nil && iface.Netboot.OSIE != nil {
+			o = iface.Netboot.OSIE
+		}
+		if iso == "" && iface.Isoboot != nil {
+			iso = iface.Isoboot.SourceISO
+		}
+	}
+	if dst == nil {
+		dst = &OSIE{}
+	}
+	dst.ISOURL = iso
+	dst.KernelURL, dst.InitrdURL, dst.KernelParams = "", "", nil
+	if o != nil {
+		dst.KernelURL = joinURL(o.BaseURL, o.Kernel)
+		dst.InitrdURL = joinURL(o.BaseURL, o.Initrd)
+		dst.KernelParams = o.KernelParams
+	}
+	if reflect.DeepEqual(*dst, OSIE{}) {
+		return nil
+	}
+
+	return dst
+}
+
+// joinURL joins a v1alpha1 OSIE base URL and file name.
+// File names that are already absolute URLs are returned as is.
+func joinURL(base, name string) string {
+	if name == "" || base == "" || strings.Contains(name, "://") {
+		return name
+	}
+
+	return strings.TrimSuffix(base, "/") + "/" + strings.TrimPrefix(name, "/")
+}
+
+// splitURL splits a URL into everything up to the last path element and the last path element.
+func splitURL(u string) (string, string) {
+	i := strings.LastIndex(u, "/")
+	if i < 0 || strings.HasSuffix(u[:i+1], "://") {
+		return "", u
+	}
+
+	return u[:i], u[i+1:]
+}
+
+// prefixToNetmask converts an IPv4 prefix length, for example "24", to a netmask, for example "255.255.255.0".
+// Values that are not a valid IPv4 prefix length are returned as is.
+func prefixToNetmask(prefix string) string {
+	n, err := strconv.Atoi(prefix)
+	if err != nil || n < 0 || n > 32 {
+		return prefix
+	}
+
+	return net.IP(net.CIDRMask(n, 32)).String()
+}
+
+// netmaskToPrefix converts an IPv4 netmask, for example "255.255.255.0", to a prefix length, for example "24".
+// Values that are not a valid IPv4 netmask are returned as is.
+func netmaskToPrefix(netmask string) string {
+	ip := net.ParseIP(netmask).To4()
+	if ip == nil {
+		return netmask
+	}
+	ones, bits := net.IPMask(ip).Size()
+	if bits == 0 {
+		return netmask
+	}
+
+	return strconv.Itoa(ones)
+}
+
+func deref[T any](v *T) T {
+	if v == nil {
+		var zero T
+		return zero
+	}
+
+	return *v
+}
+
+func nonZero[T comparable](v T) *T {
+	var zero T
+	if v == zero {
+		return nil
+	}
+
+	return &v
+}
+
+func toStrings[T ~string](in []T) []string {
+	if in == nil {
+		return nil
+	}
+	out := make([]string, 0, len(in))
+	for _, v := range in {
+		out = append(out, string(v))
+	}
+
+	return out
+}
+
+func fromStrings[T ~string](in []string) []T {
+	if in == nil {
+		return nil
+	}
+	out := make([]T, 0, len(in))
+	for _, v := range in {
+		out = append(out, T(v))
+	}
+
+	return out
+}
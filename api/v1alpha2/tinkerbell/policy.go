@@ -235,6 +235,16 @@ type ReferencePattern struct {
 // +kubebuilder:validation:XPreserveUnknownFields
 type PatternValue apiextensionsv1.JSON
 
+// MarshalJSON encodes the pattern value as its raw JSON, the same as apiextensionsv1.JSON.
+func (p PatternValue) MarshalJSON() ([]byte, error) {
+	return apiextensionsv1.JSON(p).MarshalJSON()
+}
+
+// UnmarshalJSON keeps the raw JSON of the pattern value, the same as apiextensionsv1.JSON.
+func (p *PatternValue) UnmarshalJSON(data []byte) error {
+	return (*apiextensionsv1.JSON)(p).UnmarshalJSON(data)
+}
+
 // FieldPattern represents all possible pattern values for a single field.
 // Multiple values in the array are combined with OR logic (any value can match).
 type FieldPattern []PatternValue
@@ -0,0 +1,47 @@
+/*
+Copyright 2025 Tinkerbell.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tinkerbell
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPatternValueRoundTrip(t *testing.T) {
+	in := `{"name":["config",{"prefix":"config-"},123,true],"namespace":["tinkerbell"]}`
+	rp := ReferencePattern{}
+	if err := json.Unmarshal([]byte(in), &rp); err != nil {
+		t.Fatal(err)
+	}
+	var raw []string
+	for _, v := range rp.Name {
+		raw = append(raw, string(v.Raw))
+	}
+	if diff := cmp.Diff([]string{`"config"`, `{"prefix":"config-"}`, `123`, `true`}, raw); diff != "" {
+		t.Errorf("unexpected decoded pattern values (-want +got):\n%s", diff)
+	}
+
+	out, err := json.Marshal(rp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(in, string(out)); diff != "" {
+		t.Errorf("unexpected encoded pattern (-want +got):\n%s", diff)
+	}
+}
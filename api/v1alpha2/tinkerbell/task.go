@@ -128,6 +128,59 @@ type Action struct {
 	// This is useful for Actions that do things like kexec, reboot, or power off a machine.
 	// +optional
 	Background bool `json:"background,omitempty,omitzero" yaml:"background,omitempty,omitzero"`
+
+	// Sandbox restricts what the Action container can do and use.
+	// When omitted, the Agent's runtime default is used.
+	// +optional
+	Sandbox *Sandbox `json:"sandbox,omitempty,omitzero" yaml:"sandbox,omitempty,omitzero"`
+}
+
+// Sandbox restricts what an Action container can do and use.
+type Sandbox struct {
+	// Resources limits the CPU, memory and processes the container can use.
+	// +optional
+	Resources *SandboxResources `json:"resources,omitempty,omitzero" yaml:"resources,omitempty,omitzero"`
+
+	// Capabilities are the Linux capabilities added to, and dropped from, the runtime's default set.
+	// They require Privileged to be false, a privileged container has all capabilities.
+	// +optional
+	Capabilities *SandboxCapabilities `json:"capabilities,omitempty,omitzero" yaml:"capabilities,omitempty,omitzero"`
+
+	// ReadOnlyRootFilesystem mounts the root filesystem of the container read-only. Volumes keep their own mode.
+	// +optional
+	ReadOnlyRootFilesystem bool `json:"readOnlyRootFilesystem,omitempty,omitzero" yaml:"readOnlyRootFilesystem,omitempty,omitzero"`
+
+	// Privileged runs the container with all capabilities and access to all host devices.
+	// When not set the runtime's default is used, privileged for all runtimes but kubernetes.
+	// +optional
+	Privileged *bool `json:"privileged,omitempty,omitzero" yaml:"privileged,omitempty,omitzero"`
+}
+
+// SandboxResources are the resource limits of an Action container.
+type SandboxResources struct {
+	// CPU is the maximum number of CPUs the container can use, as a quantity like 500m or 2.
+	// +optional
+	CPU string `json:"cpu,omitempty,omitzero" yaml:"cpu,omitempty,omitzero"`
+
+	// Memory is the maximum memory the container can use, as a quantity like 512Mi.
+	// The container is killed when it uses more.
+	// +optional
+	Memory string `json:"memory,omitempty,omitzero" yaml:"memory,omitempty,omitzero"`
+
+	// PIDs is the maximum number of processes in the container.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	PIDs int64 `json:"pids,omitempty,omitzero" yaml:"pids,omitempty,omitzero"`
+}
+
+// SandboxCapabilities are the Linux capabilities added to, and dropped from, an Action container.
+// Names are with or without the CAP_ prefix, like NET_ADMIN or CAP_NET_ADMIN. ALL means all capabilities.
+type SandboxCapabilities struct {
+	// +optional
+	Add []string `json:"add,omitempty,omitzero" yaml:"add,omitempty,omitzero"`
+
+	// +optional
+	Drop []string `json:"drop,omitempty,omitzero" yaml:"drop,omitempty,omitzero"`
 }
 
 // Volume is a specification for mounting a location on a Host into an Action container.
@@ -0,0 +1,373 @@
+package tinkerbell
+
+import (
+	"fmt"
+
+	v1alpha1 "github.com/tinkerbell/tinkerbell/api/v1alpha1/tinkerbell"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+// ConvertTo converts this Workflow to the Hub version (v1alpha1).
+// v1alpha1 Workflows have a single Task, only the first Task of this Workflow is converted.
+func (w *Workflow) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*v1alpha1.Workflow)
+	if !ok {
+		return fmt.Errorf("unsupported conversion hub type %T, expected *v1alpha1.Workflow", dstRaw)
+	}
+	src := w.DeepCopy()
+
+	// Start from the v1alpha1 Workflow stored by ConvertFrom, if any,
+	// so that fields v1alpha2 cannot represent are not lost.
+	base := &v1alpha1.Workflow{}
+	if _, err := unmarshalConversionData(src.ObjectMeta, &base.Spec, &base.Status); err != nil {
+		return err
+	}
+
+	dst.ObjectMeta = convertObjectMeta(src.ObjectMeta)
+	dst.Spec = workflowSpecToV1Alpha1(src.Spec, base.Spec)
+	dst.Status = workflowStatusToV1Alpha1(src.Status, base.Status)
+
+	return marshalConversionData(&dst.ObjectMeta, w.Spec, w.Status)
+}
+
+// ConvertFrom converts from the Hub version (v1alpha1) to this Workflow.
+func (w *Workflow) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*v1alpha1.Workflow)
+	if !ok {
+		return fmt.Errorf("unsupported conversion hub type %T, expected *v1alpha1.Workflow", srcRaw)
+	}
+	src = src.DeepCopy()
+
+	// Start from the v1alpha2 Workflow stored by ConvertTo, if any,
+	// so that fields v1alpha1 cannot represent are not lost.
+	base := &Workflow{}
+	if _, err := unmarshalConversionData(src.ObjectMeta, &base.Spec, &base.Status); err != nil {
+		return err
+	}
+
+	w.ObjectMeta = convertObjectMeta(src.ObjectMeta)
+	w.Spec = workflowSpecFromV1Alpha1(src.Spec, src.Namespace, base.Spec)
+	w.Status = workflowStatusFromV1Alpha1(src.Status, base.Status)
+
+	return marshalConversionData(&w.ObjectMeta, src.Spec, src.Status)
+}
+
+func workflowSpecToV1Alpha1(src WorkflowSpec, dst v1alpha1.WorkflowSpec) v1alpha1.WorkflowSpec {
+	dst.Disabled = src.Disabled
+	dst.HardwareMap = src.Vars
+	dst.TemplateRef, dst.HardwareRef = "", ""
+	bo := BootOptions{}
+	if len(src.Tasks) > 0 {
+		t := src.Tasks[0]
+		dst.TemplateRef = t.TaskRef.Name
+		if t.Hardware != nil {
+			if t.Hardware.HardwareRef != nil {
+				dst.HardwareRef = t.Hardware.HardwareRef.Name
+			}
+			if t.Hardware.BootOptions != nil {
+				bo = *t.Hardware.BootOptions
+			}
+		}
+	}
+
+	dst.BootOptions.ToggleAllowNetboot = bo.ToggleNetboot
+	dst.BootOptions.ISOURL = bo.ISOURL
+	// "iso" and "isoboot" are the same boot mode in v1alpha1, keep whichever one was used.
+	if !(bo.BootMode == BootModeIsoboot && dst.BootOptions.BootMode == v1alpha1.BootModeISO) {
+		dst.BootOptions.BootMode = v1alpha1.BootMode(bo.BootMode)
+	}
+
+	return dst
+}
+
+func workflowSpecFromV1Alpha1(src v1alpha1.WorkflowSpec, namespace string, dst WorkflowSpec) WorkflowSpec {
+	dst.Disabled = src.Disabled
+	dst.Vars = src.HardwareMap
+
+	var t WorkflowTask
+	if len(dst.Tasks) > 0 {
+		t = dst.Tasks[0]
+	}
+	t.TaskRef.Name = src.TemplateRef
+	if t.TaskRef.Namespace == "" {
+		t.TaskRef.Namespace = namespace
+	}
+
+	hw := t.Hardware
+	if hw == nil {
+		hw = &WorkflowHardware{}
+	}
+	hw.HardwareRef = nil
+	if src.HardwareRef != "" {
+		hw.HardwareRef = &SimpleReference{Name: src.HardwareRef, Namespace: namespace}
+	}
+	bo := hw.BootOptions
+	if bo == nil {
+		bo = &BootOptions{}
+	}
+	bo.ToggleNetboot = src.BootOptions.ToggleAllowNetboot
+	bo.ISOURL = src.BootOptions.ISOURL
+	bo.BootMode = BootMode(src.BootOptions.BootMode)
+	if src.BootOptions.BootMode == v1alpha1.BootModeISO {
+		bo.BootMode = BootModeIsoboot
+	}
+	hw.BootOptions = bo
+	if bo.IsZero() {
+		hw.BootOptions = nil
+	}
+	t.Hardware = hw
+	if hw.HardwareRef == nil && hw.BootOptions == nil {
+		t.Hardware = nil
+	}
+
+	if len(dst.Tasks) > 0 {
+		dst.Tasks[0] = t
+	} else {
+		dst.Tasks = []WorkflowTask{t}
+	}
+
+	return dst
+}
+
+func workflowStatusToV1Alpha1(src WorkflowStatus, dst v1alpha1.WorkflowStatus) v1alpha1.WorkflowStatus {
+	dst.AgentID = src.Metadata.Workflow.AgentID
+	dst.State = toV1Alpha1State[src.Metadata.Workflow.State]
+	dst.BootOptions = v1alpha1.BootOptionsStatus{
+		AllowNetboot: v1alpha1.AllowNetbootStatus{
+			ToggledTrue:  src.BootOptions.AllowNetboot.ToggledTrue,
+			ToggledFalse: src.BootOptions.AllowNetboot.ToggledFalse,
+		},
+	}
+	for name, j := range src.BootOptions.Jobs {
+		if dst.BootOptions.Jobs == nil {
+			dst.BootOptions.Jobs = make(map[string]v1alpha1.JobStatus, len(src.BootOptions.Jobs))
+		}
+		dst.BootOptions.Jobs[name] = v1alpha1.JobStatus{UID: j.UID, Complete: j.Complete, ExistingJobDeleted: j.ExistingJobDeleted}
+	}
+	dst.GlobalTimeout = src.GlobalTimeout
+	dst.GlobalExecutionStop = src.GlobalExecutionStop
+
+	dst.CurrentState = nil
+	if task, action := src.Metadata.Task, src.Metadata.Action; task.ID != "" || action.ID != "" {
+		dst.CurrentState = &v1alpha1.CurrentState{
+			AgentID:    task.AgentID,
+			TaskID:     string(task.ID),
+			ActionID:   string(action.ID),
+			State:      toV1Alpha1State[action.State],
+			ActionName: action.Name,
+			TaskName:   task.Name,
+		}
+	}
+
+	existing := map[string]v1alpha1.Task{}
+	for _, t := range dst.Tasks {
+		existing[t.ID] = t
+	}
+	dst.Tasks = nil
+	for _, t := range src.RenderedTasks {
+		dst.Tasks = append(dst.Tasks, taskToV1Alpha1(t, existing[string(t.Metadata.ID)]))
+	}
+
+	dst.Conditions = nil
+	for _, c := range src.Conditions {
+		dst.Conditions = append(dst.Conditions, v1alpha1.WorkflowCondition{
+			Type:    v1alpha1.WorkflowConditionType(c.Type),
+			Status:  c.Status,
+			Reason:  c.Reason,
+			Message: c.Message,
+			Time:    c.Time,
+		})
+	}
+
+	return dst
+}
+
+func taskToV1Alpha1(src TaskWithMetadata, dst v1alpha1.Task) v1alpha1.Task {
+	dst.ID = string(src.Metadata.ID)
+	dst.Name = src.Name
+	dst.AgentID = src.Metadata.AgentID
+	dst.Volumes = toStrings(src.Volumes)
+	dst.Environment = src.Env
+
+	existing := map[string]v1alpha1.Action{}
+	for _, a := range dst.Actions {
+		existing[a.ID] = a
+	}
+	dst.Actions = nil
+	for _, a := range src.Actions {
+		dst.Actions = append(dst.Actions, actionToV1Alpha1(a, existing[string(a.Metadata.ID)]))
+	}
+
+	return dst
+}
+
+func actionToV1Alpha1(src ActionWithMetadata, dst v1alpha1.Action) v1alpha1.Action {
+	dst.ID = string(src.Metadata.ID)
+	dst.Name = src.Name
+	dst.Image = src.Image
+	dst.Timeout = deref(src.TimeoutSeconds)
+	dst.Command = src.Args
+	dst.Volumes = toStrings(src.Volumes)
+	dst.Environment = src.Env
+	dst.Retries = int64(src.Retries)
+	dst.If = src.If
+	dst.State = toV1Alpha1State[src.Metadata.State]
+	dst.ExecutionStart = src.Metadata.StartTime
+	dst.ExecutionStop = src.Metadata.EndTime
+	dst.ExecutionDuration = src.Metadata.ExecutionDuration
+	dst.Message = src.Metadata.Message
+
+	// v1alpha1 has two places to define the PID namespace, keep using the deprecated Pid field if it was used.
+	if dst.Pid != "" && (dst.Namespaces == nil || dst.Namespaces.PID == "") {
+		dst.Pid = src.Namespaces.PID
+		dst.Namespaces = nil
+		if src.Namespaces.Network != "" {
+			dst.Namespaces = &v1alpha1.ActionNamespaces{Network: src.Namespaces.Network}
+		}
+		return dst
+	}
+	dst.Namespaces = nil
+	if src.Namespaces != (Namespaces{}) {
+		dst.Namespaces = &v1alpha1.ActionNamespaces{Network: src.Namespaces.Network, PID: src.Namespaces.PID}
+	}
+	dst.Sandbox = src.Sandbox.ToV1Alpha1()
+
+	return dst
+}
+
+// ToV1Alpha1 converts the Sandbox to the v1alpha1 ActionSandbox, which is also what tink-server serves to Agents.
+// It returns nil for a nil Sandbox.
+func (s *Sandbox) ToV1Alpha1() *v1alpha1.ActionSandbox {
+	if s == nil {
+		return nil
+	}
+	dst := &v1alpha1.ActionSandbox{
+		ReadOnlyRootFilesystem: s.ReadOnlyRootFilesystem,
+		Privileged:             s.Privileged,
+	}
+	if r := s.Resources; r != nil {
+		dst.Resources = &v1alpha1.ActionResources{CPU: r.CPU, Memory: r.Memory, PIDs: r.PIDs}
+	}
+	if c := s.Capabilities; c != nil {
+		dst.Capabilities = &v1alpha1.ActionCapabilities{Add: c.Add, Drop: c.Drop}
+	}
+
+	return dst
+}
+
+func workflowStatusFromV1Alpha1(src v1alpha1.WorkflowStatus, dst WorkflowStatus) WorkflowStatus {
+	dst.Metadata.Workflow.AgentID = src.AgentID
+	dst.Metadata.Workflow.State = fromV1Alpha1State[src.State]
+	dst.BootOptions = BootOptionsStatus{
+		AllowNetboot: AllowNetbootStatus{
+			ToggledTrue:  src.BootOptions.AllowNetboot.ToggledTrue,
+			ToggledFalse: src.BootOptions.AllowNetboot.ToggledFalse,
+		},
+	}
+	for name, j := range src.BootOptions.Jobs {
+		if dst.BootOptions.Jobs == nil {
+			dst.BootOptions.Jobs = make(map[string]JobStatus, len(src.BootOptions.Jobs))
+		}
+		dst.BootOptions.Jobs[name] = JobStatus{UID: j.UID, Complete: j.Complete, ExistingJobDeleted: j.ExistingJobDeleted}
+	}
+	dst.GlobalTimeout = src.GlobalTimeout
+	dst.GlobalExecutionStop = src.GlobalExecutionStop
+
+	cs := src.CurrentState
+	if cs == nil {
+		cs = &v1alpha1.CurrentState{}
+	}
+	dst.Metadata.Task.AgentID = cs.AgentID
+	dst.Metadata.Task.ID = types.UID(cs.TaskID)
+	dst.Metadata.Task.Name = cs.TaskName
+	dst.Metadata.Action.ID = types.UID(cs.ActionID)
+	dst.Metadata.Action.Name = cs.ActionName
+	dst.Metadata.Action.State = fromV1Alpha1State[cs.State]
+
+	existing := map[types.UID]TaskWithMetadata{}
+	for _, t := range dst.RenderedTasks {
+		existing[t.Metadata.ID] = t
+	}
+	dst.RenderedTasks = nil
+	for _, t := range src.Tasks {
+		dst.RenderedTasks = append(dst.RenderedTasks, taskFromV1Alpha1(t, existing[types.UID(t.ID)]))
+	}
+
+	dst.Conditions = nil
+	for _, c := range src.Conditions {
+		dst.Conditions = append(dst.Conditions, WorkflowCondition{
+			Type:    ConditionType(c.Type),
+			Status:  c.Status,
+			Reason:  c.Reason,
+			Message: c.Message,
+			Time:    c.Time,
+		})
+	}
+
+	return dst
+}
+
+func taskFromV1Alpha1(src v1alpha1.Task, dst TaskWithMetadata) TaskWithMetadata {
+	dst.Metadata.ID = types.UID(src.ID)
+	dst.Metadata.AgentID = src.AgentID
+	dst.Name = src.Name
+	dst.Volumes = fromStrings[Volume](src.Volumes)
+	dst.Env = src.Environment
+
+	existing := map[types.UID]ActionWithMetadata{}
+	for _, a := range dst.Actions {
+		existing[a.Metadata.ID] = a
+	}
+	dst.Actions = nil
+	for _, a := range src.Actions {
+		dst.Actions = append(dst.Actions, actionFromV1Alpha1(a, existing[types.UID(a.ID)]))
+	}
+
+	return dst
+}
+
+func actionFromV1Alpha1(src v1alpha1.Action, dst ActionWithMetadata) ActionWithMetadata {
+	dst.Metadata.ID = types.UID(src.ID)
+	dst.Name = src.Name
+	dst.Image = src.Image
+	dst.TimeoutSeconds = nonZero(src.Timeout)
+	dst.Args = src.Command
+	dst.Volumes = fromStrings[Volume](src.Volumes)
+	dst.Env = src.Environment
+	dst.Retries = int(src.Retries)
+	dst.If = src.If
+	dst.Namespaces = Namespaces{PID: src.Pid}
+	if src.Namespaces != nil {
+		dst.Namespaces.Network = src.Namespaces.Network
+		if src.Namespaces.PID != "" {
+			dst.Namespaces.PID = src.Namespaces.PID
+		}
+	}
+	dst.Sandbox = sandboxFromV1Alpha1(src.Sandbox)
+	dst.Metadata.State = fromV1Alpha1State[src.State]
+	dst.Metadata.StartTime = src.ExecutionStart
+	dst.Metadata.EndTime = src.ExecutionStop
+	dst.Metadata.ExecutionDuration = src.ExecutionDuration
+	dst.Metadata.Message = src.Message
+
+	return dst
+}
+
+func sandboxFromV1Alpha1(src *v1alpha1.ActionSandbox) *Sandbox {
+	if src == nil {
+		return nil
+	}
+	dst := &Sandbox{
+		ReadOnlyRootFilesystem: src.ReadOnlyRootFilesystem,
+		Privileged:             src.Privileged,
+	}
+	if r := src.Resources; r != nil {
+		dst.Resources = &SandboxResources{CPU: r.CPU, Memory: r.Memory, PIDs: r.PIDs}
+	}
+	if c := src.Capabilities; c != nil {
+		dst.Capabilities = &SandboxCapabilities{Add: c.Add, Drop: c.Drop}
+	}
+
+	return dst
+}
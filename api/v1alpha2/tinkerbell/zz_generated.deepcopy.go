@@ -76,6 +76,11 @@ func (in *Action) DeepCopyInto(out *Action) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.Sandbox != nil {
+		in, out := &in.Sandbox, &out.Sandbox
+		*out = new(Sandbox)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Action.
@@ -792,6 +797,21 @@ func (in *GPU) DeepCopy() *GPU {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPBoot) DeepCopyInto(out *HTTPBoot) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPBoot.
+func (in *HTTPBoot) DeepCopy() *HTTPBoot {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPBoot)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Hardware) DeepCopyInto(out *Hardware) {
 	*out = *in
@@ -1125,6 +1145,11 @@ func (in *Netboot) DeepCopyInto(out *Netboot) {
 		*out = new(RPI)
 		**out = **in
 	}
+	if in.HTTPBoot != nil {
+		in, out := &in.HTTPBoot, &out.HTTPBoot
+		*out = new(HTTPBoot)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Netboot.
@@ -1662,6 +1687,76 @@ func (in *Rules) DeepCopy() *Rules {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Sandbox) DeepCopyInto(out *Sandbox) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(SandboxResources)
+		**out = **in
+	}
+	if in.Capabilities != nil {
+		in, out := &in.Capabilities, &out.Capabilities
+		*out = new(SandboxCapabilities)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Privileged != nil {
+		in, out := &in.Privileged, &out.Privileged
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Sandbox.
+func (in *Sandbox) DeepCopy() *Sandbox {
+	if in == nil {
+		return nil
+	}
+	out := new(Sandbox)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SandboxCapabilities) DeepCopyInto(out *SandboxCapabilities) {
+	*out = *in
+	if in.Add != nil {
+		in, out := &in.Add, &out.Add
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Drop != nil {
+		in, out := &in.Drop, &out.Drop
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SandboxCapabilities.
+func (in *SandboxCapabilities) DeepCopy() *SandboxCapabilities {
+	if in == nil {
+		return nil
+	}
+	out := new(SandboxCapabilities)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SandboxResources) DeepCopyInto(out *SandboxResources) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SandboxResources.
+func (in *SandboxResources) DeepCopy() *SandboxResources {
+	if in == nil {
+		return nil
+	}
+	out := new(SandboxResources)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SimpleReference) DeepCopyInto(out *SimpleReference) {
 	*out = *in
@@ -81,9 +81,13 @@ func RegisterAllFlags(c *config) *ff.FlagSet {
 	RegisterKubernetesRuntimeFlags(c, fsk)
 	fsKubernetes := ff.NewFlagSetFrom("kubernetes runtime", fsk).SetParent(fsDocker)
 
+	fso := flag.NewFlagSet("oci runtime", flag.ContinueOnError)
+	RegisterOCIRuntimeFlags(c, fso)
+	fsOCI := ff.NewFlagSetFrom("oci runtime", fso).SetParent(fsKubernetes)
+
 	fsg := flag.NewFlagSet("grpc transport", flag.ContinueOnError)
 	RegisterGRPCTransportFlags(c, fsg)
-	fsGrpc := ff.NewFlagSetFrom("grpc transport", fsg).SetParent(fsKubernetes)
+	fsGrpc := ff.NewFlagSetFrom("grpc transport", fsg).SetParent(fsOCI)
 
 	fsf := flag.NewFlagSet("file transport", flag.ContinueOnError)
 	RegisterFileTransportFlags(c, fsf)
@@ -104,9 +108,12 @@ func RegisterRootFlags(c *config, fs *flag.FlagSet) {
 	fs.StringVar(&c.AgentID, "id", "", "ID of the agent")
 	fs.IntVar(&c.LogLevel, "log-level", 0, "Log level")
 	fs.BoolVar(&c.PrintVersion, "version", false, "Print the version and exit")
-	fs.Var(&c.Options.RuntimeSelected, "runtime", fmt.Sprintf("Container runtime used to run Actions, must be one of [%s, %s, %s]", agent.DockerRuntimeType, agent.ContainerdRuntimeType, agent.KubernetesRuntimeType))
+	fs.Var(&c.Options.RuntimeSelected, "runtime", fmt.Sprintf("Container runtime used to run Actions, must be one of [%s, %s, %s, %s]", agent.DockerRuntimeType, agent.ContainerdRuntimeType, agent.KubernetesRuntimeType, agent.OCIRuntimeType))
 	fs.Var(&c.Options.TransportSelected, "transport", fmt.Sprintf("Transport used to receive Workflows/Actions and to send results, must be one of [%s, %s, %s]", agent.GRPCTransportType, agent.NATSTransportType, agent.FileTransportType))
 	fs.BoolVar(&c.Options.AttributeDetectionEnabled, "attribute-detection", true, "Enable attribute detection")
+	fs.DurationVar(&c.Options.LLDPTimeout, "attribute-lldp-timeout", 0, "How long attribute detection listens for LLDP frames on each NIC with a link, before the transport starts. LLDP neighbour discovery is disabled when 0, the default")
+	fs.StringVar(&c.Options.OutputDir, "output-dir", "", "Directory, on the host running Actions, under which Action outputs are collected. Output collection is disabled when empty")
+	fs.Var(ffval.NewList(&c.Options.ImageArchives), "image-archive", "OCI image layout tarball, local path or http(s) URL, whose images are loaded at start up. Smee serves files from its TFTP/HTTP asset directory. Can be specified multiple times")
 	// This is an implementation detail of github.com/cenkalti/backoff/v5, MaxInterval caps the RetryInterval and not the randomized interval.
 	// 5 seconds will give random exponential backoff times of < 7.5 seconds. See the github.com/cenkalti/backoff/v5 doc for more detail.
 	fs.DurationVar(&c.Options.BackoffOptions.MaxInterval, "backoff-max-interval", time.Second*5, "Max interval for exponential backoff retries")
@@ -122,6 +129,10 @@ func RegisterGRPCTransportFlags(c *config, fs *flag.FlagSet) {
 	fs.StringVar(&c.Options.Transport.GRPC.ServerAddrPort, "grpc-server", "", "gRPC server address:port")
 	fs.BoolVar(&c.Options.Transport.GRPC.TLSEnabled, "grpc-tls", false, "gRPC TLS enabled")
 	fs.BoolVar(&c.Options.Transport.GRPC.TLSInsecure, "grpc-insecure-tls", false, "gRPC insecure TLS")
+	fs.StringVar(&c.Options.Transport.GRPC.TLSCertFile, "grpc-tls-cert", "", "gRPC TLS client certificate file, its common name or DNS SAN must be the Agent ID")
+	fs.StringVar(&c.Options.Transport.GRPC.TLSKeyFile, "grpc-tls-key", "", "gRPC TLS client key file")
+	fs.StringVar(&c.Options.Transport.GRPC.TLSCAFile, "grpc-tls-ca", "", "gRPC TLS CA file the Tink server certificate is verified with, the system roots are used when empty")
+	fs.StringVar(&c.Options.Transport.GRPC.Token, "grpc-token", "", "gRPC bootstrap token the Agent authenticates with when it has no client certificate")
 	fs.Var(ffval.NewValueDefault(&c.Options.Transport.GRPC.RetryInterval, 5*time.Second), "grpc-retry-interval", "gRPC retry interval in Seconds")
 }
 
@@ -147,6 +158,13 @@ func RegisterContainerdRuntimeFlags(c *config, fs *flag.FlagSet) {
 	fs.StringVar(&c.Options.Runtime.Containerd.RegistryConfigPath, "containerd-registry-config-path", "/etc/containerd/certs.d", "Root directory containing containerd registry hosts.toml and certificate configuration")
 }
 
+func RegisterOCIRuntimeFlags(c *config, fs *flag.FlagSet) {
+	fs.StringVar(&c.Options.Runtime.OCI.Binary, "oci-binary", "runc", "OCI runtime, runc or crun, used to run Actions without a container daemon")
+	fs.StringVar(&c.Options.Runtime.OCI.Root, "oci-root", "/var/lib/tink-agent/oci", "Root directory for the OCI image layout and the Action bundles")
+	fs.StringVar(&c.Options.Runtime.OCI.StateDir, "oci-state-dir", "/run/tink-agent/oci", "Directory the OCI runtime keeps container state in")
+	fs.StringVar(&c.Options.Runtime.OCI.RegistryConfigPath, "oci-registry-config-path", "/etc/containerd/certs.d", "Root directory containing containerd style registry hosts.toml and certificate configuration")
+}
+
 func RegisterKubernetesRuntimeFlags(c *config, fs *flag.FlagSet) {
 	fs.StringVar(&c.Options.Runtime.Kubernetes.Namespace, "kubernetes-namespace", "tinkerbell", "Namespace Action Jobs are created in")
 	fs.StringVar(&c.Options.Runtime.Kubernetes.Kubeconfig, "kubernetes-kubeconfig", "", "Path to a kubeconfig file; defaults to the in-cluster config")
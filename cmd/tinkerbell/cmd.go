@@ -17,6 +17,7 @@ import (
 	"github.com/peterbourgon/ff/v4/ffhelp"
 	"github.com/tinkerbell/tinkerbell/cmd/tinkerbell/flag"
 	"github.com/tinkerbell/tinkerbell/crd"
+	"github.com/tinkerbell/tinkerbell/pkg/backend/kube"
 	"github.com/tinkerbell/tinkerbell/pkg/build"
 	"github.com/tinkerbell/tinkerbell/pkg/otel"
 	"github.com/tinkerbell/tinkerbell/rufio"
@@ -27,7 +28,6 @@ import (
 	"github.com/tinkerbell/tinkerbell/tootles"
 	"github.com/tinkerbell/tinkerbell/ui"
 	"golang.org/x/sync/errgroup"
-	"google.golang.org/grpc/credentials"
 	"k8s.io/client-go/rest"
 	"k8s.io/klog/v2"
 	controllerruntime "sigs.k8s.io/controller-runtime"
@@ -235,7 +235,7 @@ func executeWithOutput(ctx context.Context, cancel context.CancelFunc, args []st
 	ts.Convert(globals.BindAddr)
 	// Configure TLS if cert and key files are provided
 	if globals.TLS.CertFile != "" && globals.TLS.KeyFile != "" {
-		creds, err := credentials.NewServerTLSFromFile(globals.TLS.CertFile, globals.TLS.KeyFile)
+		creds, err := tinkServerCredentials(globals.TLS.CertFile, globals.TLS.KeyFile, ts.ClientCAFile, ts.AgentTokensFile != "")
 		if err != nil {
 			return fmt.Errorf("failed to load TLS credentials for Tink Server gRPC: %w", err)
 		}
@@ -244,9 +244,37 @@ func executeWithOutput(ctx context.Context, cancel context.CancelFunc, args []st
 		// Set UseTLS so the iPXE script template emits tinkerbell_tls=true in kernel args.
 		s.Config.TinkServer.UseTLS = true
 	}
+	if ts.ClientCAFile != "" || ts.AgentTokensFile != "" {
+		if ts.Config.TLS.Cert == nil {
+			return fmt.Errorf("tink server agent authentication requires the TLS cert and key files")
+		}
+		ts.Config.AgentAuth.ClientCertificates = ts.ClientCAFile != ""
+		if ts.AgentTokensFile != "" {
+			f, err := os.Open(ts.AgentTokensFile)
+			if err != nil {
+				return fmt.Errorf("failed to open tink server agent tokens file: %w", err)
+			}
+			tokens, err := server.ReadAgentTokens(f)
+			f.Close()
+			if err != nil {
+				return fmt.Errorf("failed to read tink server agent tokens file: %w", err)
+			}
+			ts.Config.AgentAuth.Tokens = tokens
+		}
+	}
 
 	// Tink Controller
 	tc.Config.LeaderElectionNamespace = leaderElectionNamespace(inCluster(), tc.Config.EnableLeaderElection, tc.Config.LeaderElectionNamespace)
+	if tc.Config.ConversionWebhook.Enabled {
+		if globals.TLS.CertFile == "" || globals.TLS.KeyFile == "" {
+			return fmt.Errorf("the tink controller conversion webhook requires the TLS cert and key files")
+		}
+		tc.Config.ConversionWebhook.CertFile = globals.TLS.CertFile
+		tc.Config.ConversionWebhook.KeyFile = globals.TLS.KeyFile
+		if globals.BindAddr.IsValid() {
+			tc.Config.ConversionWebhook.BindAddr = globals.BindAddr.String()
+		}
+	}
 
 	// Rufio Controller
 	rc.Config.LeaderElectionNamespace = leaderElectionNamespace(inCluster(), rc.Config.EnableLeaderElection, rc.Config.LeaderElectionNamespace)
@@ -328,7 +356,15 @@ func executeWithOutput(ctx context.Context, cancel context.CancelFunc, args []st
 				return fmt.Errorf("failed to wait for API server health: %w", err)
 			}
 
-			tb, err := crd.NewTinkerbell(crd.WithLogger(cliLog), crd.WithRestConfig(backendNoIndexes.ClientConfig))
+			crdOpts := []crd.ConfigOption{crd.WithLogger(cliLog), crd.WithRestConfig(backendNoIndexes.ClientConfig)}
+			if globals.EnableTinkController && tc.Config.ConversionWebhook.Enabled {
+				cc, err := tc.ConversionClientConfig(globals.PublicIP, globals.TLS.CertFile)
+				if err != nil {
+					return fmt.Errorf("failed to configure CRD conversion webhook: %w", err)
+				}
+				crdOpts = append(crdOpts, crd.WithConversionWebhook(cc))
+			}
+			tb, err := crd.NewTinkerbell(crdOpts...)
 			if err != nil {
 				return fmt.Errorf("failed to create CRD migrator: %w", err)
 			}
@@ -340,13 +376,20 @@ func executeWithOutput(ctx context.Context, cancel context.CancelFunc, args []st
 			cliLog.Info("CRD migrations completed")
 		}
 
-		b, err := newKubeBackend(ctx, globals.BackendKubeConfig, "", globals.BackendKubeNamespace, enabledIndexes(globals.EnableSmee, globals.EnableTootles, globals.EnableTinkServer, globals.EnableSecondStar), WithQPS(globals.BackendKubeOptions.QPS), WithBurst(globals.BackendKubeOptions.Burst))
+		idxs := enabledIndexes(globals.EnableSmee, globals.EnableTootles, globals.EnableTinkServer, globals.EnableSecondStar)
+		if globals.EnableTinkServer && ts.EnableV1Alpha2 {
+			idxs[kube.IndexTypeWorkflowV1Alpha2AgentID] = kube.Indexes[kube.IndexTypeWorkflowV1Alpha2AgentID]
+		}
+		b, err := newKubeBackend(ctx, globals.BackendKubeConfig, "", globals.BackendKubeNamespace, idxs, WithQPS(globals.BackendKubeOptions.QPS), WithBurst(globals.BackendKubeOptions.Burst))
 		if err != nil {
 			return fmt.Errorf("failed to create kube backend: %w", err)
 		}
 		s.Config.Backend = b
 		h.Config.SetBackendFromFilterer(b)
 		ts.Config.SetBackends(b)
+		if ts.EnableV1Alpha2 {
+			ts.Config.BackendV1Alpha2 = b
+		}
 		tc.Config.Client = b.ClientConfig
 		tc.Config.DynamicClient = b
 		rc.Config.Client = b.ClientConfig
@@ -387,6 +430,8 @@ func executeWithOutput(ctx context.Context, cancel context.CancelFunc, args []st
 	// server can receive iPXE script requests that reference them.
 	if globals.EnableSmee {
 		s.Config.InitMetrics()
+		// The UI shows the DHCP transactions of a Hardware object on its detail page.
+		uic.Config.DHCPTransactions = s.Config.DHCPTransactions
 	}
 
 	// Smee (non-HTTP services: DHCP, TFTP, syslog)
@@ -406,7 +451,7 @@ func executeWithOutput(ctx context.Context, cancel context.CancelFunc, args []st
 
 	// HTTP server
 	g.Go(func() error {
-		return startHTTPServer(ctx, globals, s, h, uic, startTime)
+		return startHTTPServer(ctx, globals, s, h, tc, uic, startTime)
 	})
 
 	// Tink Server
@@ -29,8 +29,9 @@ type SmeeConfig struct {
 }
 
 var KubeIndexesSmee = map[kube.IndexType]kube.Index{
-	kube.IndexTypeMACAddr: kube.Indexes[kube.IndexTypeMACAddr],
-	kube.IndexTypeIPAddr:  kube.Indexes[kube.IndexTypeIPAddr],
+	kube.IndexTypeMACAddr:    kube.Indexes[kube.IndexTypeMACAddr],
+	kube.IndexTypeIPAddr:     kube.Indexes[kube.IndexTypeIPAddr],
+	kube.IndexTypeSwitchPort: kube.Indexes[kube.IndexTypeSwitchPort],
 }
 
 // URLBuilder breaks out the fields of a url.URL so they can be set individually from the CLI.
@@ -62,6 +63,15 @@ func RegisterSmeeFlags(fs *Set, sc *SmeeConfig) {
 	fs.Register(DHCPIPXEHTTPScriptHost, ffval.NewValueDefault(&sc.DHCPIPXEScript.Host, sc.DHCPIPXEScript.Host))
 	fs.Register(DHCPIPXEHTTPScriptPort, ffval.NewValueDefault(&sc.DHCPIPXEScript.Port, sc.DHCPIPXEScript.Port))
 	fs.Register(DHCPIPXEHTTPScriptPath, ffval.NewValueDefault(&sc.Config.DHCP.IPXEHTTPScript.URL.Path, sc.Config.DHCP.IPXEHTTPScript.URL.Path))
+	fs.Register(DHCPPools, &sc.Config.DHCP.Pools)
+	fs.Register(DHCPLeaseFile, ffval.NewValueDefault(&sc.Config.DHCP.LeaseFile, sc.Config.DHCP.LeaseFile))
+	fs.Register(DHCPTransactionHistory, ffval.NewValueDefault(&sc.Config.DHCP.TransactionHistorySize, sc.Config.DHCP.TransactionHistorySize))
+
+	// DHCPv6 flags
+	fs.Register(DHCPv6Enabled, ffval.NewValueDefault(&sc.Config.DHCPv6.Enabled, sc.Config.DHCPv6.Enabled))
+	fs.Register(DHCPv6BindAddr, &ntip.Addr{Addr: &sc.Config.DHCPv6.BindAddr})
+	fs.Register(DHCPv6BindPort, ffval.NewValueDefault(&sc.Config.DHCPv6.BindPort, sc.Config.DHCPv6.BindPort))
+	fs.Register(DHCPv6PublicIP, &ntip.Addr{Addr: &sc.Config.DHCPv6.PublicIP})
 
 	// IPXE flags
 	fs.Register(IPXEArchMapping, &ffval.Value[map[iana.Arch]constant.IPXEBinary]{
@@ -146,6 +156,9 @@ func RegisterSmeeFlags(fs *Set, sc *SmeeConfig) {
 	// PXE-over-HTTP flags
 	fs.Register(PXEHTTPEnabled, ffval.NewValueDefault(&sc.Config.PXEHTTP.Enabled, sc.Config.PXEHTTP.Enabled))
 	fs.Register(PXEHTTPPathPrefix, ffval.NewValueDefault(&sc.Config.PXEHTTP.PathPrefix, sc.Config.PXEHTTP.PathPrefix))
+
+	// UEFI HTTP Boot flags
+	fs.Register(HTTPBootAssetDir, ffval.NewValueDefault(&sc.Config.HTTPBoot.AssetDir, sc.Config.HTTPBoot.AssetDir))
 }
 
 // Convert CLI specific fields to smee.Config fields.
@@ -166,7 +179,8 @@ func (s *SmeeConfig) Convert(trustedProxies *[]netip.Prefix, publicIP netip.Addr
 		}
 
 		if port != "" {
-			return fmt.Sprintf("%s:%s", addr, port)
+			// net.JoinHostPort adds the brackets that an IPv6 address needs in a URL host.
+			return net.JoinHostPort(strings.Trim(addr, "[]"), port)
 		}
 		return addr
 	}()
@@ -186,7 +200,8 @@ func (s *SmeeConfig) Convert(trustedProxies *[]netip.Prefix, publicIP netip.Addr
 		}
 
 		if port != "" {
-			return fmt.Sprintf("%s:%s", addr, port)
+			// net.JoinHostPort adds the brackets that an IPv6 address needs in a URL host.
+			return net.JoinHostPort(strings.Trim(addr, "[]"), port)
 		}
 		return addr
 	}()
@@ -223,6 +238,11 @@ func (s *SmeeConfig) Convert(trustedProxies *[]netip.Prefix, publicIP netip.Addr
 		return net.JoinHostPort(host, port)
 	}()
 
+	// The DHCPv6 server hands out URLs with an IPv6 host, so only an IPv6 publicIP is a usable default.
+	if !s.Config.DHCPv6.PublicIP.IsValid() && publicIP.Is6() && !publicIP.Is4In6() && !publicIP.IsUnspecified() {
+		s.Config.DHCPv6.PublicIP = publicIP
+	}
+
 	// publicIP is used to set IPForPacket, SyslogIP, TFTPIP, IPXEHTTPBinaryURL.Host, and IPXEHTTPScript.URL.Host.
 	if publicIP.IsUnspecified() || !publicIP.IsValid() {
 		return
@@ -342,6 +362,42 @@ var DHCPIPXEHTTPScriptInjectMac = Config{
 	Usage: "[dhcp] prepend the hardware MAC address to iPXE script URL base, http://1.2.3.4/auto.ipxe -> http://1.2.3.4/40:15:ff:89:cc:0e/auto.ipxe",
 }
 
+var DHCPPools = Config{
+	Name:  "dhcp-pools",
+	Usage: "[dhcp] dynamic address pools for machines without a Hardware object, reservation mode only, pools are separated by ';', e.g. subnet=192.168.2.0/24,range=192.168.2.100-192.168.2.200,gateway=192.168.2.1,dns=1.1.1.1,domain=example.com,lease-time=1h",
+}
+
+var DHCPLeaseFile = Config{
+	Name:  "dhcp-lease-file",
+	Usage: "[dhcp] path of the file that stores the leases of the dynamic address pools, leases are kept in memory when empty",
+}
+
+var DHCPTransactionHistory = Config{
+	Name:  "dhcp-transaction-history",
+	Usage: "[dhcp] number of recent DHCP transactions kept in memory and served at /smee/dhcp/transactions, 0 disables",
+}
+
+// DHCPv6 flags.
+var DHCPv6Enabled = Config{
+	Name:  "dhcpv6-enabled",
+	Usage: "[dhcpv6] enable DHCPv6 server, uses the same mode as the DHCP server",
+}
+
+var DHCPv6BindAddr = Config{
+	Name:  "dhcpv6-bind-addr",
+	Usage: "[dhcpv6] DHCPv6 server bind address, the multicast groups are joined when unspecified",
+}
+
+var DHCPv6BindPort = Config{
+	Name:  "dhcpv6-bind-port",
+	Usage: "[dhcpv6] DHCPv6 server bind port",
+}
+
+var DHCPv6PublicIP = Config{
+	Name:  "dhcpv6-public-ip",
+	Usage: "[dhcpv6] IPv6 address to use in DHCPv6 Boot File URLs (opt 59), defaults to the public IP when it is IPv6",
+}
+
 // iPXE HTTP script flags.
 var IPXEHTTPScriptEnabled = Config{
 	Name:  "ipxe-http-script-enabled",
@@ -446,6 +502,12 @@ var PXEHTTPPathPrefix = Config{
 	Usage: "[pxe-http] URL path prefix to serve pxelinux.cfg and TFTP assets under over HTTP",
 }
 
+// UEFI HTTP Boot flags.
+var HTTPBootAssetDir = Config{
+	Name:  "httpboot-asset-dir",
+	Usage: "[httpboot] local directory with the signed shim, GRUB and UKI files for UEFI HTTP Boot clients that boot without iPXE (disabled if empty)",
+}
+
 // iPXE flags.
 var IPXEEmbeddedScriptPatch = Config{
 	Name:  "ipxe-embedded-script-patch",
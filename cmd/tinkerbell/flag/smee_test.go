@@ -152,3 +152,48 @@ func TestSmeeConfig_Convert_TinkServerAddrPort(t *testing.T) {
 		})
 	}
 }
+
+func TestSmeeConfig_Convert_IPv6PublicIP(t *testing.T) {
+	tests := []struct {
+		name           string
+		publicIP       netip.Addr
+		dhcpv6PublicIP netip.Addr
+		wantScriptHost string
+		wantDHCPv6IP   netip.Addr
+	}{
+		{
+			name:           "IPv4 public IP is not used for DHCPv6",
+			publicIP:       netip.MustParseAddr("192.168.1.100"),
+			wantScriptHost: "192.168.1.100:8080",
+		},
+		{
+			name:           "IPv6 public IP is bracketed and used for DHCPv6",
+			publicIP:       netip.MustParseAddr("2001:db8::100"),
+			wantScriptHost: "[2001:db8::100]:8080",
+			wantDHCPv6IP:   netip.MustParseAddr("2001:db8::100"),
+		},
+		{
+			name:           "DHCPv6 public IP flag takes precedence",
+			publicIP:       netip.MustParseAddr("2001:db8::100"),
+			dhcpv6PublicIP: netip.MustParseAddr("2001:db8::200"),
+			wantScriptHost: "[2001:db8::100]:8080",
+			wantDHCPv6IP:   netip.MustParseAddr("2001:db8::200"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sc := &SmeeConfig{Config: smee.NewConfig(smee.Config{})}
+			sc.Config.DHCPv6.PublicIP = tt.dhcpv6PublicIP
+
+			sc.Convert(nil, tt.publicIP, netip.Addr{}, 8080)
+
+			if got := sc.Config.DHCP.IPXEHTTPScript.URL.Host; got != tt.wantScriptHost {
+				t.Errorf("DHCP.IPXEHTTPScript.URL.Host = %q, want %q", got, tt.wantScriptHost)
+			}
+			if got := sc.Config.DHCPv6.PublicIP; got != tt.wantDHCPv6IP {
+				t.Errorf("DHCPv6.PublicIP = %v, want %v", got, tt.wantDHCPv6IP)
+			}
+		})
+	}
+}
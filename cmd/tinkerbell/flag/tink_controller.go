@@ -1,14 +1,25 @@
 package flag
 
 import (
+	"fmt"
+	"net/netip"
+	"net/url"
+	"os"
+
 	"github.com/peterbourgon/ff/v4/ffval"
+	"github.com/tinkerbell/tinkerbell/crd"
 	"github.com/tinkerbell/tinkerbell/pkg/flag/delimitedlist"
 	"github.com/tinkerbell/tinkerbell/tink/controller"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 )
 
 type TinkControllerConfig struct {
 	Config   *controller.Config
 	LogLevel int
+	// ConversionWebhookURL is the URL the Kubernetes API server uses to reach the conversion webhook.
+	ConversionWebhookURL string
+	// ConversionWebhookCAFile is the CA bundle the Kubernetes API server uses to verify the conversion webhook certificate.
+	ConversionWebhookCAFile string
 }
 
 func RegisterTinkControllerFlags(fs *Set, t *TinkControllerConfig) {
@@ -18,6 +29,41 @@ func RegisterTinkControllerFlags(fs *Set, t *TinkControllerConfig) {
 	fs.Register(TinkControllerLogLevel, ffval.NewValueDefault(&t.LogLevel, t.LogLevel))
 	fs.Register(TinkControllerReferenceAllowListRules, delimitedlist.New(&t.Config.ReferenceAllowListRules, '|'))
 	fs.Register(TinkControllerReferenceDenyListRules, delimitedlist.New(&t.Config.ReferenceDenyListRules, '|'))
+	fs.Register(TinkControllerEnableV1Alpha2, ffval.NewValueDefault(&t.Config.EnableV1Alpha2, t.Config.EnableV1Alpha2))
+	fs.Register(TinkControllerEnableConversionWebhook, ffval.NewValueDefault(&t.Config.ConversionWebhook.Enabled, t.Config.ConversionWebhook.Enabled))
+	fs.Register(TinkControllerConversionWebhookBindPort, ffval.NewValueDefault(&t.Config.ConversionWebhook.BindPort, t.Config.ConversionWebhook.BindPort))
+	fs.Register(TinkControllerConversionWebhookURL, ffval.NewValueDefault(&t.ConversionWebhookURL, t.ConversionWebhookURL))
+	fs.Register(TinkControllerConversionWebhookCAFile, ffval.NewValueDefault(&t.ConversionWebhookCAFile, t.ConversionWebhookCAFile))
+	fs.Register(TinkControllerEventsWebhookURL, ffval.NewValueDefault(&t.Config.EventsWebhookURL, t.Config.EventsWebhookURL))
+	fs.Register(TinkControllerAgentLostTimeout, ffval.NewValueDefault(&t.Config.AgentLostTimeout, t.Config.AgentLostTimeout))
+}
+
+// ConversionClientConfig returns the client config the Kubernetes API server uses to call the conversion webhook.
+// When no URL is configured, the webhook is reached on the public IP. When no CA file is configured,
+// the certificate file is used as the CA bundle, which works for self-signed certificates.
+func (t *TinkControllerConfig) ConversionClientConfig(publicIP netip.Addr, certFile string) (apiextensionsv1.WebhookClientConfig, error) {
+	u := t.ConversionWebhookURL
+	if u == "" {
+		if !publicIP.IsValid() {
+			return apiextensionsv1.WebhookClientConfig{}, fmt.Errorf("conversion webhook URL is required when no public IP is available")
+		}
+		u = (&url.URL{
+			Scheme: "https",
+			Host:   netip.AddrPortFrom(publicIP, uint16(t.Config.ConversionWebhook.BindPort)).String(), //nolint:gosec // Port is validated by the webhook server.
+			Path:   crd.ConversionWebhookPath,
+		}).String()
+	}
+
+	caFile := t.ConversionWebhookCAFile
+	if caFile == "" {
+		caFile = certFile
+	}
+	ca, err := os.ReadFile(caFile)
+	if err != nil {
+		return apiextensionsv1.WebhookClientConfig{}, fmt.Errorf("failed to read conversion webhook CA bundle: %w", err)
+	}
+
+	return apiextensionsv1.WebhookClientConfig{URL: &u, CABundle: ca}, nil
 }
 
 var TinkControllerEnableLeaderElection = Config{
@@ -49,3 +95,38 @@ var TinkControllerMaxConcurrentReconciles = Config{
 	Name:  "tink-controller-max-concurrent-reconciles",
 	Usage: "maximum number of concurrent reconciles for tink controller",
 }
+
+var TinkControllerEnableV1Alpha2 = Config{
+	Name:  "tink-controller-enable-v1alpha2",
+	Usage: "reconcile v1alpha2 Workflows instead of v1alpha1 Workflows, requires the v1alpha2 CRDs. Reference access is controlled by Policy objects instead of the reference rules flags",
+}
+
+var TinkControllerEnableConversionWebhook = Config{
+	Name:  "tink-controller-enable-conversion-webhook",
+	Usage: "serve the v1alpha1 <-> v1alpha2 conversion webhook for Hardware and Workflows and serve both versions from the CRDs, requires the tls cert and key files",
+}
+
+var TinkControllerConversionWebhookBindPort = Config{
+	Name:  "tink-controller-conversion-webhook-bind-port",
+	Usage: "port on which the conversion webhook will listen",
+}
+
+var TinkControllerConversionWebhookURL = Config{
+	Name:  "tink-controller-conversion-webhook-url",
+	Usage: "URL the Kubernetes API server uses to reach the conversion webhook, defaults to https://<public-ip>:<bind-port>" + crd.ConversionWebhookPath,
+}
+
+var TinkControllerConversionWebhookCAFile = Config{
+	Name:  "tink-controller-conversion-webhook-ca-file",
+	Usage: "path to the CA bundle the Kubernetes API server uses to verify the conversion webhook, defaults to the tls cert file",
+}
+
+var TinkControllerEventsWebhookURL = Config{
+	Name:  "tink-controller-events-webhook-url",
+	Usage: "URL to which Workflow start, Action completion, failure and timeout events are posted as CloudEvents, disabled when empty",
+}
+
+var TinkControllerAgentLostTimeout = Config{
+	Name:  "tink-controller-agent-lost-timeout",
+	Usage: "how long the Agent of a running Workflow can go without a heartbeat before the Workflow is marked failed, 0 (the default) disables the check",
+}
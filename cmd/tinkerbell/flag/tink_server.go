@@ -14,6 +14,12 @@ type TinkServerConfig struct {
 	BindAddr netip.Addr
 	BindPort uint16
 	LogLevel int
+	// EnableV1Alpha2 serves v1alpha2 Workflows instead of v1alpha1 Workflows.
+	EnableV1Alpha2 bool
+	// ClientCAFile is the CA bundle Agent client certificates are verified with.
+	ClientCAFile string
+	// AgentTokensFile holds the Agent bootstrap tokens, one "<agent-id> <token>" pair per line.
+	AgentTokensFile string
 }
 
 var KubeIndexesTinkServer = map[kube.IndexType]kube.Index{
@@ -29,6 +35,14 @@ func RegisterTinkServerFlags(fs *Set, t *TinkServerConfig) {
 	fs.Register(TinkerbellAutoDiscoveryEnabled, ffval.NewValueDefault(&t.Config.Auto.Discovery.Enabled, t.Config.Auto.Discovery.Enabled))
 	fs.Register(TinkerbellAutoDiscoveryAutoEnrollmentEnabled, ffval.NewValueDefault(&t.Config.Auto.Discovery.EnrollmentEnabled, t.Config.Auto.Discovery.EnrollmentEnabled))
 	fs.Register(TinkerbellAutoDiscoveryNamespace, ffval.NewValueDefault(&t.Config.Auto.Discovery.Namespace, t.Config.Auto.Discovery.Namespace))
+	fs.Register(TinkServerEnableV1Alpha2, ffval.NewValueDefault(&t.EnableV1Alpha2, t.EnableV1Alpha2))
+	fs.Register(TinkServerNATSURL, ffval.NewValueDefault(&t.Config.NATS.URL, t.Config.NATS.URL))
+	fs.Register(TinkServerNATSStream, ffval.NewValueDefault(&t.Config.NATS.StreamName, t.Config.NATS.StreamName))
+	fs.Register(TinkServerNATSActions, ffval.NewValueDefault(&t.Config.NATS.ActionsSubject, t.Config.NATS.ActionsSubject))
+	fs.Register(TinkServerNATSEvents, ffval.NewValueDefault(&t.Config.NATS.EventsSubject, t.Config.NATS.EventsSubject))
+	fs.Register(TinkServerNativeCommands, ffval.NewList(&t.Config.NativeCommands))
+	fs.Register(TinkServerTLSClientCAFile, ffval.NewValueDefault(&t.ClientCAFile, t.ClientCAFile))
+	fs.Register(TinkServerAgentTokensFile, ffval.NewValueDefault(&t.AgentTokensFile, t.AgentTokensFile))
 }
 
 // Convert TinkServerConfig data types to tink server server.Config data types.
@@ -74,3 +88,43 @@ var TinkerbellAutoDiscoveryAutoEnrollmentEnabled = Config{
 	Name:  "tink-server-auto-discovery-auto-enrollment-enabled",
 	Usage: "this tells auto discovery the value to set for the hardware.spec.auto.enrollmentEnabled field when creating Hardware objects",
 }
+
+var TinkServerEnableV1Alpha2 = Config{
+	Name:  "tink-server-enable-v1alpha2",
+	Usage: "serve Actions from v1alpha2 Workflows instead of v1alpha1 Workflows, requires the v1alpha2 CRDs. Auto discovery and enrollment are not supported for v1alpha2 Workflows",
+}
+
+var TinkServerNATSURL = Config{
+	Name:  "tink-server-nats-url",
+	Usage: "URL of the NATS server, for example nats://192.168.2.50:4222, to which Actions are published for Agents using the NATS transport, disabled when empty",
+}
+
+var TinkServerNATSStream = Config{
+	Name:  "tink-server-nats-stream",
+	Usage: "NATS stream name, must match the Agents' nats-stream",
+}
+
+var TinkServerNATSActions = Config{
+	Name:  "tink-server-nats-actions",
+	Usage: "NATS actions subject, must match the Agents' nats-actions",
+}
+
+var TinkServerNATSEvents = Config{
+	Name:  "tink-server-nats-events",
+	Usage: "NATS events subject, must match the Agents' nats-events",
+}
+
+var TinkServerNativeCommands = Config{
+	Name:  "tink-server-native-commands",
+	Usage: "host commands, as absolute paths or glob patterns like /usr/sbin/*, that native Actions may run, no native Action is served when empty",
+}
+
+var TinkServerTLSClientCAFile = Config{
+	Name:  "tink-server-tls-client-ca-file",
+	Usage: "[tls] path to the CA bundle Agent client certificates are verified with. Agents must then present a client certificate whose common name or DNS SAN is their ID, or a bootstrap token",
+}
+
+var TinkServerAgentTokensFile = Config{
+	Name:  "tink-server-agent-tokens-file",
+	Usage: "[tls] path to a file of Agent bootstrap tokens, one '<agent-id> <token>' pair per line, that Agents without a client certificate authenticate with",
+}
@@ -1,11 +1,17 @@
 package main
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
 	"path"
 	"strings"
 
 	"github.com/tinkerbell/tinkerbell/cmd/tinkerbell/flag"
 	"github.com/tinkerbell/tinkerbell/pkg/backend/kube"
+	"google.golang.org/grpc/credentials"
 	"k8s.io/client-go/rest"
 )
 
@@ -90,3 +96,27 @@ func inCluster() bool {
 	}
 	return false
 }
+
+// tinkServerCredentials returns the Tink server TLS credentials. When clientCAFile is set, Agent client certificates
+// are verified with it and required unless Agents may use a bootstrap token instead.
+func tinkServerCredentials(certFile, keyFile, clientCAFile string, tokens bool) (credentials.TransportCredentials, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+	if clientCAFile != "" {
+		pem, err := os.ReadFile(clientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.New("no certificates found in client CA file")
+		}
+		cfg.ClientCAs = pool
+		cfg.ClientAuth = ternary(tokens, tls.VerifyClientCertIfGiven, tls.RequireAndVerifyClientCert)
+	}
+
+	return credentials.NewTLS(cfg), nil
+}
@@ -27,6 +27,7 @@ const (
 	routeHealthz           = "/healthz"
 	routeReadyz            = "/readyz"
 	routeSmeeMetrics       = "/smee/metrics"
+	routeSmeeDHCPTxs       = "/smee/dhcp/transactions"
 	routeTinkServerMetrics = "/tink-server/metrics"
 	routeControllerMetrics = "/controllers/metrics"
 	routeHTTPMetrics       = "/http/metrics"
@@ -36,11 +37,13 @@ const (
 	routeISO               = smee.ISOURI
 	routeIPXEBinary        = smee.IPXEBinaryURI
 	routeIPXEScript        = smee.IPXEScriptURI
+	routeHTTPBoot          = smee.HTTPBootURI
+	routeTemplateDryRun    = "/tink-controller/templates/dry-run"
 )
 
 // startHTTPServer registers all HTTP/HTTPS routes, applies middleware, and
 // starts the consolidated HTTP server. It blocks until ctx is cancelled.
-func startHTTPServer(ctx context.Context, globals *flag.GlobalConfig, s *flag.SmeeConfig, h *flag.TootlesConfig, uic *flag.UIConfig, startTime time.Time) error {
+func startHTTPServer(ctx context.Context, globals *flag.GlobalConfig, s *flag.SmeeConfig, h *flag.TootlesConfig, tc *flag.TinkControllerConfig, uic *flag.UIConfig, startTime time.Time) error {
 	httpLog := getLogger(globals.LogLevel).WithName("http")
 	routeList := &httpserver.Routes{}
 	tlsEnabled := len(s.Config.TLS.Certs) > 0
@@ -77,6 +80,18 @@ func startHTTPServer(ctx context.Context, globals *flag.GlobalConfig, s *flag.Sm
 				"smee PXE-over-HTTP handler",
 			)
 		}
+		if hb := s.Config.HTTPBootHandler(smeeLog); hb != nil {
+			routeList.Register(routeHTTPBoot,
+				middleware.WithLogLevel(middleware.LogLevelAlways, hb),
+				"smee UEFI HTTP Boot handler",
+			)
+		}
+		if th := s.Config.DHCPTransactionsHandler(); th != nil {
+			routeList.Register(routeSmeeDHCPTxs,
+				middleware.WithLogLevel(middleware.LogLevelNever, th),
+				"smee DHCP transaction history handler",
+			)
+		}
 	}
 
 	// Tootles HTTP handlers
@@ -104,6 +119,18 @@ func startHTTPServer(ctx context.Context, globals *flag.GlobalConfig, s *flag.Sm
 		)
 	}
 
+	// Tink Controller HTTP handlers
+	// The dry-run handler needs a Kubernetes client config, which is only set with the kube backend.
+	if globals.EnableTinkController && tc.Config.Client != nil && !tc.Config.EnableV1Alpha2 {
+		ll := ternary((tc.LogLevel != 0), tc.LogLevel, globals.LogLevel)
+		routeList.Register(routeTemplateDryRun,
+			middleware.WithLogLevel(middleware.LogLevelAlways, tc.Config.DryRunHandler(getLogger(ll).WithName("tink-controller"))),
+			"Tink controller Template dry-run handler",
+			httpserver.WithHTTPSEnabled(tlsEnabled),
+			httpserver.WithRewriteHTTPToHTTPS(tlsEnabled),
+		)
+	}
+
 	// UI HTTP handler
 	if globals.EnableUI {
 		ll := ternary((uic.LogLevel != 0), uic.LogLevel, globals.LogLevel)
@@ -5,6 +5,7 @@ import (
 	"embed"
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/avast/retry-go/v4"
@@ -37,6 +38,10 @@ type Tinkerbell struct {
 	Client     clientset.Interface
 	Logger     logr.Logger
 	restConfig *rest.Config
+	// conversion, when set, is the client config for the conversion webhook.
+	// The v1alpha2 versions of the Convertible CRDs are served alongside
+	// v1alpha1 and converted by this webhook.
+	conversion *apiv1.WebhookClientConfig
 }
 
 const (
@@ -69,6 +74,12 @@ var TinkerbellV1Alpha2 = map[string][]byte{
 	"jobs.bmc.tinkerbell.org":  mustReadCRD("bases/v1alpha2/bmc.tinkerbell.org_jobs.yaml"),
 }
 
+// Convertible lists the CRDs that the conversion webhook converts between v1alpha1 and v1alpha2.
+var Convertible = []string{"hardware.tinkerbell.org", "workflows.tinkerbell.org"}
+
+// ConversionWebhookPath is the HTTP path the conversion webhook is served on.
+const ConversionWebhookPath = "/convert"
+
 // CRDsByVersion maps API version strings to their CRD source maps.
 var CRDsByVersion = map[string]map[string][]byte{
 	"v1alpha1": TinkerbellDefaults,
@@ -84,6 +95,15 @@ func WithRestConfig(config *rest.Config) ConfigOption {
 	}
 }
 
+// WithConversionWebhook serves the v1alpha2 version of the Convertible CRDs alongside v1alpha1
+// and configures the CRDs to use the conversion webhook at the given client config.
+// v1alpha1 stays the storage version.
+func WithConversionWebhook(config apiv1.WebhookClientConfig) ConfigOption {
+	return func(t *Tinkerbell) {
+		t.conversion = &config
+	}
+}
+
 // WithLogger sets a structured logger for Kubernetes API server warnings.
 func WithLogger(logger logr.Logger) ConfigOption {
 	return func(t *Tinkerbell) {
@@ -147,6 +167,11 @@ func (t Tinkerbell) Migrate(ctx context.Context) error {
 		if _, _, err := decoder.Decode(raw, nil, obj); err != nil {
 			return fmt.Errorf("failed to decode YAML: %w", err)
 		}
+		if t.conversion != nil && slices.Contains(Convertible, obj.GetName()) {
+			if err := t.addConversion(obj); err != nil {
+				return err
+			}
+		}
 
 		// Try apply, if that fails, try create. Apply only works if the CRD already exists.
 		if errApply := t.apply(ctx, obj); errApply != nil {
@@ -234,6 +259,51 @@ func (t Tinkerbell) update(ctx context.Context, obj *unstructured.Unstructured)
 	return nil
 }
 
+// addConversion adds the v1alpha2 versions of the CRD to obj and sets the conversion strategy to the conversion webhook.
+func (t Tinkerbell) addConversion(obj *unstructured.Unstructured) error {
+	var crdef apiv1.CustomResourceDefinition
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &crdef); err != nil {
+		return fmt.Errorf("failed to convert unstructured to CRD: %w", err)
+	}
+	raw, ok := TinkerbellV1Alpha2[crdef.Name]
+	if !ok {
+		return fmt.Errorf("no v1alpha2 CRD found for %s", crdef.Name)
+	}
+	v2obj := &unstructured.Unstructured{}
+	if _, _, err := yaml.NewDecodingSerializer(unstructured.UnstructuredJSONScheme).Decode(raw, nil, v2obj); err != nil {
+		return fmt.Errorf("failed to decode YAML: %w", err)
+	}
+	var v2 apiv1.CustomResourceDefinition
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(v2obj.Object, &v2); err != nil {
+		return fmt.Errorf("failed to convert unstructured to CRD: %w", err)
+	}
+
+	for _, v := range v2.Spec.Versions {
+		if slices.ContainsFunc(crdef.Spec.Versions, func(e apiv1.CustomResourceDefinitionVersion) bool { return e.Name == v.Name }) {
+			continue
+		}
+		v.Served = true
+		v.Storage = false
+		crdef.Spec.Versions = append(crdef.Spec.Versions, v)
+	}
+	crdef.Spec.Conversion = &apiv1.CustomResourceConversion{
+		Strategy: apiv1.WebhookConverter,
+		Webhook: &apiv1.WebhookConversion{
+			ClientConfig:             t.conversion.DeepCopy(),
+			ConversionReviewVersions: []string{"v1"},
+		},
+	}
+
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&crdef)
+	if err != nil {
+		return fmt.Errorf("failed to convert CRD to unstructured: %w", err)
+	}
+	unstructured.RemoveNestedField(u, "status")
+	obj.Object = u
+
+	return nil
+}
+
 // getCondition returns a condition from a list of conditions if it exists.
 func getCondition(crd *apiv1.CustomResourceDefinition, conditionType apiv1.CustomResourceDefinitionConditionType) *apiv1.CustomResourceDefinitionCondition {
 	for _, cond := range crd.Status.Conditions {
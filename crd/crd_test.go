@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/go-cmp/cmp"
 	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -70,3 +71,69 @@ func TestMigrateAndReady(t *testing.T) {
 		t.Errorf("failed to migrate CRDs: %v", err)
 	}
 }
+
+func TestMigrateConversionWebhook(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	cc := v1.WebhookClientConfig{
+		URL:      ptr("https://192.168.2.50:9443" + ConversionWebhookPath),
+		CABundle: []byte("ca"),
+	}
+	m, err := NewTinkerbell(func(t *Tinkerbell) { t.Client = client }, WithConversionWebhook(cc))
+	if err != nil {
+		t.Fatalf("failed to create Tinkerbell: %v", err)
+	}
+	if err := m.Migrate(context.Background()); err != nil {
+		t.Fatalf("failed to migrate CRDs: %v", err)
+	}
+
+	type version struct {
+		Name    string
+		Served  bool
+		Storage bool
+	}
+	tests := map[string]struct {
+		want           []version
+		wantConversion *v1.CustomResourceConversion
+	}{
+		"hardware.tinkerbell.org": {
+			want: []version{{Name: "v1alpha1", Served: true, Storage: true}, {Name: "v1alpha2", Served: true}},
+			wantConversion: &v1.CustomResourceConversion{
+				Strategy: v1.WebhookConverter,
+				Webhook:  &v1.WebhookConversion{ClientConfig: &cc, ConversionReviewVersions: []string{"v1"}},
+			},
+		},
+		"workflows.tinkerbell.org": {
+			want: []version{{Name: "v1alpha1", Served: true, Storage: true}, {Name: "v1alpha2", Served: true}},
+			wantConversion: &v1.CustomResourceConversion{
+				Strategy: v1.WebhookConverter,
+				Webhook:  &v1.WebhookConversion{ClientConfig: &cc, ConversionReviewVersions: []string{"v1"}},
+			},
+		},
+		"templates.tinkerbell.org": {
+			want: []version{{Name: "v1alpha1", Served: true, Storage: true}},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			crd, err := client.ApiextensionsV1().CustomResourceDefinitions().Get(context.Background(), name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []version
+			for _, v := range crd.Spec.Versions {
+				got = append(got, version{Name: v.Name, Served: v.Served, Storage: v.Storage})
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("unexpected versions (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantConversion, crd.Spec.Conversion); diff != "" {
+				t.Errorf("unexpected conversion (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
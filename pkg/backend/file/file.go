@@ -4,6 +4,7 @@ package file
 import (
 	"context"
 	"fmt"
+	"net/netip"
 	"os"
 	"path/filepath"
 	"strings"
@@ -185,8 +186,20 @@ func matchHardware(hw *tinkerbell.Hardware, opts data.HardwareFilter) bool {
 	if opts.ByInstanceID != "" && (hw.Spec.Metadata == nil || hw.Spec.Metadata.Instance == nil || hw.Spec.Metadata.Instance.ID != opts.ByInstanceID) {
 		return false
 	}
+	if opts.BySwitchPort != "" && !hardwareHasSwitchPort(hw, opts.BySwitchPort) {
+		return false
+	}
 	// At least one selector must be set for a match.
-	return opts.ByName != "" || opts.ByAgentID != "" || opts.ByMACAddress != "" || opts.ByIPAddress != "" || opts.ByInstanceID != ""
+	return opts.ByName != "" || opts.ByAgentID != "" || opts.ByMACAddress != "" || opts.ByIPAddress != "" || opts.ByInstanceID != "" || opts.BySwitchPort != ""
+}
+
+func hardwareHasSwitchPort(hw *tinkerbell.Hardware, key string) bool {
+	for _, iface := range hw.Spec.Interfaces {
+		if iface.SwitchPort != nil && iface.SwitchPort.CircuitID != "" && data.SwitchPortKey(iface.SwitchPort.CircuitID, iface.SwitchPort.RemoteID) == key {
+			return true
+		}
+	}
+	return false
 }
 
 func hardwareHasMAC(hw *tinkerbell.Hardware, mac string) bool {
@@ -199,8 +212,16 @@ func hardwareHasMAC(hw *tinkerbell.Hardware, mac string) bool {
 }
 
 func hardwareHasIP(hw *tinkerbell.Hardware, ip string) bool {
+	want, err := netip.ParseAddr(ip)
 	for _, iface := range hw.Spec.Interfaces {
-		if iface.DHCP != nil && iface.DHCP.IP != nil && iface.DHCP.IP.Address == ip {
+		if iface.DHCP == nil || iface.DHCP.IP == nil {
+			continue
+		}
+		if iface.DHCP.IP.Address == ip {
+			return true
+		}
+		// IPv6 addresses can be written in more than one way, so compare the parsed addresses too.
+		if got, perr := netip.ParseAddr(iface.DHCP.IP.Address); err == nil && perr == nil && got.Unmap() == want.Unmap() {
 			return true
 		}
 	}
@@ -336,6 +336,40 @@ func TestReadHardwareByIP(t *testing.T) {
 	}
 }
 
+func TestReadHardwareBySwitchPort(t *testing.T) {
+	tests := map[string]struct {
+		key      string
+		wantName string
+	}{
+		"no record found":        {key: "eth1/5"},
+		"record found":           {key: data.SwitchPortKey("eth1/5", "leaf01"), wantName: "sandbox"},
+		"other switch not found": {key: data.SwitchPortKey("eth1/5", "leaf02")},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			w, err := NewWatcher(logr.Discard(), "testdata/example.yaml")
+			if err != nil {
+				t.Fatal(err)
+			}
+			hw, err := w.FilterHardware(context.Background(), data.HardwareFilter{BySwitchPort: tt.key})
+			if tt.wantName == "" {
+				var nf hardwareNotFoundError
+				if !errors.As(err, &nf) {
+					t.Fatalf("FilterHardware() error = %v, want NotFound error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FilterHardware() unexpected error = %v", err)
+			}
+			if hw.Name != tt.wantName {
+				t.Fatalf("FilterHardware() = %v, want %v", hw.Name, tt.wantName)
+			}
+		})
+	}
+}
+
 func TestReadHardwareByName(t *testing.T) {
 	tests := map[string]struct {
 		name     string
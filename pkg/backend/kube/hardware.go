@@ -85,6 +85,9 @@ func filterQueryDesc(opts data.HardwareFilter) string {
 	if opts.ByInstanceID != "" {
 		desc = fmt.Sprintf("%s with instanceID %q", desc, opts.ByInstanceID)
 	}
+	if opts.BySwitchPort != "" {
+		desc = fmt.Sprintf("%s with switch port %q", desc, opts.BySwitchPort)
+	}
 	return desc
 }
 
@@ -110,7 +113,7 @@ func hardwareListOptions(opts data.HardwareFilter) []client.ListOption {
 		los = append(los, client.MatchingFields{NameIndex: opts.ByName})
 	}
 	if opts.ByIPAddress != "" {
-		los = append(los, client.MatchingFields{IPAddrIndex: opts.ByIPAddress})
+		los = append(los, client.MatchingFields{IPAddrIndex: canonicalIP(opts.ByIPAddress)})
 	}
 	if opts.ByMACAddress != "" {
 		los = append(los, client.MatchingFields{MACAddrIndex: opts.ByMACAddress})
@@ -118,6 +121,9 @@ func hardwareListOptions(opts data.HardwareFilter) []client.ListOption {
 	if opts.ByInstanceID != "" {
 		los = append(los, client.MatchingFields{InstanceIDIndex: opts.ByInstanceID})
 	}
+	if opts.BySwitchPort != "" {
+		los = append(los, client.MatchingFields{SwitchPortIndex: opts.BySwitchPort})
+	}
 
 	return los
 }
@@ -1,8 +1,13 @@
 package kube
 
 import (
+	"net/netip"
+	"slices"
+
 	"github.com/tinkerbell/tinkerbell/api/v1alpha1/bmc"
 	"github.com/tinkerbell/tinkerbell/api/v1alpha1/tinkerbell"
+	v1alpha2 "github.com/tinkerbell/tinkerbell/api/v1alpha2/tinkerbell"
+	"github.com/tinkerbell/tinkerbell/pkg/data"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -16,6 +21,9 @@ const (
 	IndexTypeWorkflowAgentID IndexType = WorkflowAgentIDIndex
 	IndexTypeHardwareAgentID IndexType = HardwareAgentIDIndex
 	IndexTypeInstanceID      IndexType = InstanceIDIndex
+	IndexTypeSwitchPort      IndexType = SwitchPortIndex
+
+	IndexTypeWorkflowV1Alpha2AgentID IndexType = WorkflowV1Alpha2AgentIDIndex
 
 	// MACAddrIndex is an index used with a controller-runtime client to lookup hardware by MAC.
 	MACAddrIndex = ".Spec.Interfaces.MAC"
@@ -29,12 +37,17 @@ const (
 	// WorkflowAgentIDIndex is an index used with a controller-runtime client to lookup workflows by their status agent id.
 	WorkflowAgentIDIndex = ".status.agentID"
 
+	// WorkflowV1Alpha2AgentIDIndex is an index used with a controller-runtime client to lookup v1alpha2 workflows by their rendered task agent ids.
+	WorkflowV1Alpha2AgentIDIndex = ".status.renderedTasks.taskMetadata.agentID"
+
 	// HardwareAgentIDIndex is an index used with a controller-runtime client to lookup hardware by their spec agent id.
 	HardwareAgentIDIndex = ".spec.agentID"
 
 	// InstanceIDIndex is an index used with a controller-runtime client to lookup hardware by its metadata instance id.
 	InstanceIDIndex = ".Spec.Metadata.Instance.ID" // #nosec G101 - This is a field path, not a credential
 
+	// SwitchPortIndex is an index used with a controller-runtime client to lookup hardware by the switch port of an interface.
+	SwitchPortIndex = ".Spec.Interfaces.SwitchPort"
 )
 
 // Indexes that are currently known.
@@ -74,6 +87,16 @@ var Indexes = map[IndexType]Index{
 		Field:        InstanceIDIndex,
 		ExtractValue: InstanceID,
 	},
+	IndexTypeSwitchPort: {
+		Obj:          &tinkerbell.Hardware{},
+		Field:        SwitchPortIndex,
+		ExtractValue: SwitchPorts,
+	},
+	IndexTypeWorkflowV1Alpha2AgentID: {
+		Obj:          &v1alpha2.Workflow{},
+		Field:        WorkflowV1Alpha2AgentIDIndex,
+		ExtractValue: WorkflowV1Alpha2AgentIDs,
+	},
 }
 
 // MACAddrs returns a list of MAC addresses for a Hardware object.
@@ -107,16 +130,42 @@ func IPAddrs(obj client.Object) []string {
 }
 
 // GetIPs retrieves all IP addresses.
+// Addresses are returned in their canonical form so that IPv6 addresses match regardless of how they were written.
 func GetIPs(h *tinkerbell.Hardware) []string {
 	var ips []string
 	for _, i := range h.Spec.Interfaces {
 		if i.DHCP != nil && i.DHCP.IP != nil && i.DHCP.IP.Address != "" {
-			ips = append(ips, i.DHCP.IP.Address)
+			ips = append(ips, canonicalIP(i.DHCP.IP.Address))
 		}
 	}
 	return ips
 }
 
+// canonicalIP returns the canonical string form of ip, or ip unchanged if it is not an IP address.
+func canonicalIP(ip string) string {
+	a, err := netip.ParseAddr(ip)
+	if err != nil {
+		return ip
+	}
+	return a.Unmap().String()
+}
+
+// SwitchPorts returns the switch port keys of the interfaces of a Hardware object.
+// The keys are built with data.SwitchPortKey.
+func SwitchPorts(obj client.Object) []string {
+	hw, ok := obj.(*tinkerbell.Hardware)
+	if !ok {
+		return nil
+	}
+	var ports []string
+	for _, i := range hw.Spec.Interfaces {
+		if i.SwitchPort != nil && i.SwitchPort.CircuitID != "" {
+			ports = append(ports, data.SwitchPortKey(i.SwitchPort.CircuitID, i.SwitchPort.RemoteID))
+		}
+	}
+	return ports
+}
+
 // HardwareName extracts the name of a Hardware object for field indexing.
 func HardwareName(obj client.Object) []string {
 	hw, ok := obj.(*tinkerbell.Hardware)
@@ -136,17 +185,43 @@ func MachineName(obj client.Object) []string {
 }
 
 // WorkflowAgentID extracts the agent ID from a Workflow's status for field indexing.
+// Workflows whose Tasks declare dependencies can run Tasks on multiple Agents at the same time,
+// so the agent IDs of all Tasks are extracted.
 func WorkflowAgentID(obj client.Object) []string {
 	wf, ok := obj.(*tinkerbell.Workflow)
 	if !ok {
 		return nil
 	}
+	if wf.Status.HasTaskDependencies() {
+		ids := []string{}
+		for _, t := range wf.Status.Tasks {
+			if t.AgentID != "" && !slices.Contains(ids, t.AgentID) {
+				ids = append(ids, t.AgentID)
+			}
+		}
+		return ids
+	}
 	if wf.Status.AgentID == "" {
 		return []string{}
 	}
 	return []string{wf.Status.AgentID}
 }
 
+// WorkflowV1Alpha2AgentIDs extracts the unique agent IDs from a v1alpha2 Workflow's rendered tasks for field indexing.
+func WorkflowV1Alpha2AgentIDs(obj client.Object) []string {
+	wf, ok := obj.(*v1alpha2.Workflow)
+	if !ok {
+		return nil
+	}
+	ids := []string{}
+	for _, t := range wf.Status.RenderedTasks {
+		if t.Metadata.AgentID != "" && !slices.Contains(ids, t.Metadata.AgentID) {
+			ids = append(ids, t.Metadata.AgentID)
+		}
+	}
+	return ids
+}
+
 // HardwareAgentID extracts the agent ID from a Hardware's spec for field indexing.
 func HardwareAgentID(obj client.Object) []string {
 	hw, ok := obj.(*tinkerbell.Hardware)
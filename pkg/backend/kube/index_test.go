@@ -6,6 +6,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	v1alpha1 "github.com/tinkerbell/tinkerbell/api/v1alpha1/tinkerbell"
+	v1alpha2 "github.com/tinkerbell/tinkerbell/api/v1alpha2/tinkerbell"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -80,6 +81,19 @@ func TestIPAddrs(t *testing.T) {
 				},
 			},
 		}, want: []string{"192.168.2.1", "192.168.2.2"}},
+		"ipv6 is canonical": {hw: &v1alpha1.Hardware{
+			Spec: v1alpha1.HardwareSpec{
+				Interfaces: []v1alpha1.Interface{
+					{
+						DHCP: &v1alpha1.DHCP{
+							IP: &v1alpha1.IP{
+								Address: "2001:DB8:0:0::0a",
+							},
+						},
+					},
+				},
+			},
+		}, want: []string{"2001:db8::a"}},
 		"no interfaces": {hw: &v1alpha1.Hardware{}, want: nil},
 	}
 	for name, tc := range tests {
@@ -92,6 +106,42 @@ func TestIPAddrs(t *testing.T) {
 	}
 }
 
+func TestSwitchPorts(t *testing.T) {
+	tests := map[string]struct {
+		hw   client.Object
+		want []string
+	}{
+		"not a v1alpha1.Hardware object": {hw: &v1alpha1.Workflow{}, want: nil},
+		"2 switch ports": {hw: &v1alpha1.Hardware{
+			Spec: v1alpha1.HardwareSpec{
+				Interfaces: []v1alpha1.Interface{
+					{
+						SwitchPort: &v1alpha1.SwitchPort{CircuitID: "eth1/1"},
+					},
+					{
+						SwitchPort: &v1alpha1.SwitchPort{CircuitID: "eth1/2", RemoteID: "leaf01"},
+					},
+					{
+						SwitchPort: &v1alpha1.SwitchPort{RemoteID: "leaf01"},
+					},
+					{
+						DHCP: &v1alpha1.DHCP{MAC: "00:00:00:00:00:01"},
+					},
+				},
+			},
+		}, want: []string{"eth1/1", "leaf01|eth1/2"}},
+		"no interfaces": {hw: &v1alpha1.Hardware{}, want: nil},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := SwitchPorts(tc.hw)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("unexpected switch ports (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestWorkflowByAgentIDFunc(t *testing.T) {
 	cases := []struct {
 		name           string
@@ -143,6 +193,32 @@ func TestWorkflowByAgentIDFunc(t *testing.T) {
 			},
 			[]string{"agent1"},
 		},
+		{
+			"workflow with task dependencies",
+			&v1alpha1.Workflow{
+				Status: v1alpha1.WorkflowStatus{
+					State:   v1alpha1.WorkflowStateRunning,
+					AgentID: "agent1",
+					Tasks: []v1alpha1.Task{
+						{
+							Name:    "one",
+							AgentID: "agent1",
+						},
+						{
+							Name:      "two",
+							AgentID:   "agent2",
+							DependsOn: []string{"one"},
+						},
+						{
+							Name:      "three",
+							AgentID:   "agent2",
+							DependsOn: []string{"one"},
+						},
+					},
+				},
+			},
+			[]string{"agent1", "agent2"},
+		},
 		{
 			"completeworkflow",
 			&v1alpha1.Workflow{
@@ -168,3 +244,45 @@ func TestWorkflowByAgentIDFunc(t *testing.T) {
 		})
 	}
 }
+
+func TestWorkflowV1Alpha2AgentIDs(t *testing.T) {
+	cases := []struct {
+		name  string
+		input client.Object
+		want  []string
+	}{
+		{
+			"not a v1alpha2 workflow",
+			&v1alpha1.Workflow{},
+			nil,
+		},
+		{
+			"no rendered tasks",
+			&v1alpha2.Workflow{},
+			[]string{},
+		},
+		{
+			"multiple tasks",
+			&v1alpha2.Workflow{
+				Status: v1alpha2.WorkflowStatus{
+					RenderedTasks: []v1alpha2.TaskWithMetadata{
+						{Metadata: v1alpha2.Metadata{AgentID: "agent1"}},
+						{Metadata: v1alpha2.Metadata{AgentID: ""}},
+						{Metadata: v1alpha2.Metadata{AgentID: "agent2"}},
+						{Metadata: v1alpha2.Metadata{AgentID: "agent1"}},
+					},
+				},
+			},
+			[]string{"agent1", "agent2"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := WorkflowV1Alpha2AgentIDs(tc.input)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("unexpected agent IDs (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/tinkerbell/tinkerbell/api/v1alpha1/bmc"
 	"github.com/tinkerbell/tinkerbell/api/v1alpha1/tinkerbell"
+	v1alpha2 "github.com/tinkerbell/tinkerbell/api/v1alpha2/tinkerbell"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -77,6 +78,10 @@ func NewBackend(cfg Backend, opts ...cluster.Option) (*Backend, error) {
 		return nil, err
 	}
 
+	if err := v1alpha2.AddToScheme(rs); err != nil {
+		return nil, err
+	}
+
 	if err := bmc.AddToScheme(rs); err != nil {
 		return nil, err
 	}
@@ -0,0 +1,97 @@
+package kube
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	v1alpha1 "github.com/tinkerbell/tinkerbell/api/v1alpha1/tinkerbell"
+	"github.com/tinkerbell/tinkerbell/pkg/constant"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// maxActionLogs is the number of Action logs kept in the action logs ConfigMap of a Workflow.
+// Together with constant.MaxActionLogSize it keeps the ConfigMap well below the 1MiB Kubernetes object size limit.
+const maxActionLogs = 24
+
+// WriteActionLogs stores the logs of an Action in the Workflow's action logs ConfigMap, under the Action ID.
+// The ConfigMap is created, owned by the Workflow, when it does not exist so that it is deleted with the Workflow.
+// The ConfigMap is only ever patched or created, never read, so no ConfigMap informer is started.
+// Only the logs of the last maxActionLogs Actions, in Workflow order, are kept. The logs of earlier Actions are removed.
+func (b *Backend) WriteActionLogs(ctx context.Context, wf *v1alpha1.Workflow, actionID string, logs []byte) error {
+	cc := b.cluster.GetClient()
+	name := wf.Name + constant.ActionLogsConfigMapSuffix
+	if len(logs) > constant.MaxActionLogSize {
+		logs = logs[len(logs)-constant.MaxActionLogSize:]
+	}
+
+	// A null value in a merge patch removes the key, removing keys that do not exist is a no-op.
+	l := string(logs)
+	data := map[string]*string{actionID: &l}
+	for _, id := range evictedActionLogs(wf, actionID) {
+		data[id] = nil
+	}
+	patch, err := json.Marshal(map[string]any{"data": data})
+	if err != nil {
+		return fmt.Errorf("failed to marshal action logs patch: %w", err)
+	}
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: wf.Namespace}}
+	err = cc.Patch(ctx, cm, client.RawPatch(types.MergePatchType, patch))
+	if !apierrors.IsNotFound(err) {
+		if err != nil {
+			return fmt.Errorf("failed to patch action logs configmap %s/%s: %w", wf.Namespace, name, err)
+		}
+		return nil
+	}
+
+	gvk, err := apiutil.GVKForObject(wf, cc.Scheme())
+	if err != nil {
+		return fmt.Errorf("failed to get workflow kind: %w", err)
+	}
+	cm = &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: wf.Namespace,
+			Labels:    map[string]string{constant.WorkflowLabel: wf.Name},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(wf, gvk),
+			},
+		},
+		Data: map[string]string{actionID: string(logs)},
+	}
+	if err := cc.Create(ctx, cm); err != nil {
+		if apierrors.IsAlreadyExists(err) {
+			// Created by a concurrent stream from another Agent of the same Workflow.
+			if err := cc.Patch(ctx, cm, client.RawPatch(types.MergePatchType, patch)); err != nil {
+				return fmt.Errorf("failed to patch action logs configmap %s/%s: %w", wf.Namespace, name, err)
+			}
+			return nil
+		}
+		return fmt.Errorf("failed to create action logs configmap %s/%s: %w", wf.Namespace, name, err)
+	}
+
+	return nil
+}
+
+// evictedActionLogs returns the IDs of the Actions, before actionID in Workflow order,
+// whose logs no longer fit in the action logs ConfigMap.
+func evictedActionLogs(wf *v1alpha1.Workflow, actionID string) []string {
+	var ids []string
+	for _, t := range wf.Status.Tasks {
+		for _, a := range t.Actions {
+			ids = append(ids, a.ID)
+		}
+	}
+	idx := slices.Index(ids, actionID)
+	if idx < maxActionLogs {
+		return nil
+	}
+
+	return ids[:idx-maxActionLogs+1]
+}
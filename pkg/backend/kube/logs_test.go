@@ -0,0 +1,125 @@
+package kube
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tinkerbell/tinkerbell/api/v1alpha1/tinkerbell"
+	"github.com/tinkerbell/tinkerbell/pkg/constant"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
+)
+
+func TestWriteActionLogs(t *testing.T) {
+	wf := &tinkerbell.Workflow{ObjectMeta: v1.ObjectMeta{Name: "wf1", Namespace: "tink", UID: "1234"}}
+	// manyActions has the logs of its first two Actions evicted when the logs of action25 are written.
+	manyActions := wf.DeepCopy()
+	manyActions.Status.Tasks = []tinkerbell.Task{{Name: "task1"}}
+	for i := range maxActionLogs + 2 {
+		manyActions.Status.Tasks[0].Actions = append(manyActions.Status.Tasks[0].Actions, tinkerbell.Action{ID: fmt.Sprintf("action%d", i)})
+	}
+	tests := map[string]struct {
+		wf       *tinkerbell.Workflow
+		actionID string
+		logs     string
+		existing *corev1.ConfigMap
+		want     map[string]string
+	}{
+		"creates configmap": {
+			want: map[string]string{"action1": "wiping disk\n"},
+		},
+		"adds to existing configmap": {
+			existing: &corev1.ConfigMap{
+				ObjectMeta: v1.ObjectMeta{Name: "wf1-action-logs", Namespace: "tink"},
+				Data:       map[string]string{"action0": "done\n", "action1": "old"},
+			},
+			want: map[string]string{"action0": "done\n", "action1": "wiping disk\n"},
+		},
+		"keeps the tail of large logs": {
+			logs: strings.Repeat("a", constant.MaxActionLogSize) + "wiping disk\n",
+			want: map[string]string{"action1": strings.Repeat("a", constant.MaxActionLogSize-len("wiping disk\n")) + "wiping disk\n"},
+		},
+		"evicts the logs of the earliest actions": {
+			wf:       manyActions,
+			actionID: "action25",
+			existing: &corev1.ConfigMap{
+				ObjectMeta: v1.ObjectMeta{Name: "wf1-action-logs", Namespace: "tink"},
+				Data:       map[string]string{"action0": "done\n", "action1": "done\n", "action2": "done\n"},
+			},
+			want: map[string]string{"action2": "done\n", "action25": "wiping disk\n"},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			rs := runtime.NewScheme()
+			if err := scheme.AddToScheme(rs); err != nil {
+				t.Fatal(err)
+			}
+			if err := tinkerbell.AddToScheme(rs); err != nil {
+				t.Fatal(err)
+			}
+			ct := fake.NewClientBuilder().WithScheme(rs)
+			if tt.existing != nil {
+				ct = ct.WithObjects(tt.existing)
+			}
+			cl := ct.Build()
+
+			fn := func(o *cluster.Options) {
+				o.NewClient = func(*rest.Config, client.Options) (client.Client, error) {
+					return cl, nil
+				}
+				o.MapperProvider = func(*rest.Config, *http.Client) (meta.RESTMapper, error) {
+					return cl.RESTMapper(), nil
+				}
+				o.NewCache = func(*rest.Config, cache.Options) (cache.Cache, error) {
+					return &informertest.FakeInformers{Scheme: cl.Scheme()}, nil
+				}
+			}
+			b, err := NewBackend(Backend{ClientConfig: new(rest.Config)}, fn)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			w, actionID, logs := wf, "action1", "wiping disk\n"
+			if tt.wf != nil {
+				w = tt.wf
+			}
+			if tt.actionID != "" {
+				actionID = tt.actionID
+			}
+			if tt.logs != "" {
+				logs = tt.logs
+			}
+			if err := b.WriteActionLogs(context.Background(), w, actionID, []byte(logs)); err != nil {
+				t.Fatal(err)
+			}
+
+			got := &corev1.ConfigMap{}
+			if err := cl.Get(context.Background(), types.NamespacedName{Name: "wf1-action-logs", Namespace: "tink"}, got); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, got.Data); diff != "" {
+				t.Errorf("unexpected configmap data (-want +got):\n%s", diff)
+			}
+			if tt.existing == nil {
+				if len(got.OwnerReferences) != 1 || got.OwnerReferences[0].UID != wf.UID || got.OwnerReferences[0].Kind != "Workflow" {
+					t.Errorf("expected the configmap to be owned by the workflow, got %v", got.OwnerReferences)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package kube
+
+import (
+	"context"
+	"fmt"
+
+	v1alpha1 "github.com/tinkerbell/tinkerbell/api/v1alpha1/tinkerbell"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// WatchWorkflows calls notify with the Agent IDs of a Workflow every time the Workflow is added or updated in the cache.
+// It uses the Workflow informer that backs ReadWorkflow and ListWorkflows, so it does not add any load on the API server.
+func (b *Backend) WatchWorkflows(ctx context.Context, notify func(agentIDs []string)) error {
+	inf, err := b.cluster.GetCache().GetInformer(ctx, &v1alpha1.Workflow{})
+	if err != nil {
+		return fmt.Errorf("failed to get workflow informer: %w", err)
+	}
+	handle := func(obj any) {
+		if o, ok := obj.(client.Object); ok {
+			if ids := WorkflowAgentID(o); len(ids) > 0 {
+				notify(ids)
+			}
+		}
+	}
+	_, err = inf.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+		AddFunc:    handle,
+		UpdateFunc: func(_, obj any) { handle(obj) },
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add workflow event handler: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package kube
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tinkerbell/tinkerbell/api/v1alpha1/tinkerbell"
+	"k8s.io/apimachinery/pkg/api/meta"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
+)
+
+func TestWatchWorkflows(t *testing.T) {
+	rs := runtime.NewScheme()
+	if err := tinkerbell.AddToScheme(rs); err != nil {
+		t.Fatal(err)
+	}
+	cl := fake.NewClientBuilder().WithScheme(rs).Build()
+	informers := &informertest.FakeInformers{Scheme: rs}
+	fn := func(o *cluster.Options) {
+		o.NewClient = func(*rest.Config, client.Options) (client.Client, error) {
+			return cl, nil
+		}
+		o.MapperProvider = func(*rest.Config, *http.Client) (meta.RESTMapper, error) {
+			return cl.RESTMapper(), nil
+		}
+		o.NewCache = func(*rest.Config, cache.Options) (cache.Cache, error) {
+			return informers, nil
+		}
+	}
+	b, err := NewBackend(Backend{ClientConfig: new(rest.Config)}, fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got [][]string
+	if err := b.WatchWorkflows(context.Background(), func(agentIDs []string) { got = append(got, agentIDs) }); err != nil {
+		t.Fatal(err)
+	}
+	inf, err := informers.FakeInformerFor(context.Background(), &tinkerbell.Workflow{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pending := &tinkerbell.Workflow{ObjectMeta: v1.ObjectMeta{Name: "wf1", Namespace: "tink"}}
+	running := pending.DeepCopy()
+	running.Status.AgentID = "agent1"
+	inf.Add(pending)
+	inf.Update(pending, running)
+
+	if diff := cmp.Diff([][]string{{"agent1"}}, got); diff != "" {
+		t.Errorf("unexpected notifications (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,67 @@
+package kube
+
+import (
+	"context"
+	"fmt"
+
+	v1alpha2 "github.com/tinkerbell/tinkerbell/api/v1alpha2/tinkerbell"
+	"github.com/tinkerbell/tinkerbell/pkg/data"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func (b *Backend) ReadWorkflowV1Alpha2(ctx context.Context, name, namespace string) (*v1alpha2.Workflow, error) {
+	wflw := &v1alpha2.Workflow{}
+	if err := b.cluster.GetClient().Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, wflw); err != nil {
+		return nil, fmt.Errorf("failed to get workflow %s/%s: %w", namespace, name, err)
+	}
+	return wflw, nil
+}
+
+// ListWorkflowsV1Alpha2 lists v1alpha2 Workflows. Filtering by agent ID requires the IndexTypeWorkflowV1Alpha2AgentID index.
+func (b *Backend) ListWorkflowsV1Alpha2(ctx context.Context, opts data.WorkflowFilter) ([]v1alpha2.Workflow, error) {
+	stored := &v1alpha2.WorkflowList{}
+	los := []client.ListOption{}
+	if opts.InNamespace != "" {
+		los = append(los, client.InNamespace(opts.InNamespace))
+	}
+	if opts.ByAgentID != "" {
+		los = append(los, client.MatchingFields{WorkflowV1Alpha2AgentIDIndex: opts.ByAgentID})
+	}
+	if err := b.cluster.GetClient().List(ctx, stored, los...); err != nil {
+		return nil, fmt.Errorf("failed to list workflows in namespace %s: %w", opts.InNamespace, err)
+	}
+
+	return stored.Items, nil
+}
+
+func (b *Backend) UpdateWorkflowV1Alpha2(ctx context.Context, wf *v1alpha2.Workflow, opts data.UpdateOptions) error {
+	cc := b.cluster.GetClient()
+
+	if p, err := patchFromOpts(opts); err != nil {
+		return fmt.Errorf("invalid patch options for workflow %s: %w", wf.Name, err)
+	} else if p != nil {
+		if opts.StatusOnly {
+			if err := cc.Status().Patch(ctx, wf, p); err != nil {
+				return fmt.Errorf("failed to patch workflow status %s: %w", wf.Name, err)
+			}
+			return nil
+		}
+		if err := cc.Patch(ctx, wf, p); err != nil {
+			return fmt.Errorf("failed to patch workflow %s: %w", wf.Name, err)
+		}
+		return nil
+	}
+
+	if opts.StatusOnly {
+		if err := cc.Status().Update(ctx, wf); err != nil {
+			return fmt.Errorf("failed to update workflow status %s: %w", wf.Name, err)
+		}
+		return nil
+	}
+	if err := cc.Update(ctx, wf); err != nil {
+		return fmt.Errorf("failed to update workflow %s: %w", wf.Name, err)
+	}
+
+	return nil
+}
@@ -36,6 +36,15 @@ const (
 	// For Workflows, disabled means that the Workflow will not be executed.
 	// For BMCs, disabled means that no operations against the BMC will be attempted.
 	DisabledAnnotation = "tinkerbell.org/disabled"
+
+	// ActionLogsConfigMapSuffix is appended to the name of a Workflow to get the name of the ConfigMap
+	// that holds the tail of the output of each of the Workflow's Actions. The ConfigMap data keys are Action IDs.
+	ActionLogsConfigMapSuffix = "-action-logs"
+	// MaxActionLogSize is the maximum size of the tail of the output kept for each Action in the action logs ConfigMap.
+	// Kubernetes objects, and so the ConfigMap that holds the logs of all Actions in a Workflow, are limited to 1MiB.
+	MaxActionLogSize = 32 * 1024
+	// WorkflowLabel is the label key used to indicate the Workflow an object belongs to.
+	WorkflowLabel = "tinkerbell.org/workflow"
 )
 
 // MACFormat is a format for a MAC address.
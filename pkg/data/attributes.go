@@ -12,6 +12,7 @@ type AgentAttributes struct {
 	BIOS              *BIOS      `json:"bios,omitempty" yaml:"bios,omitempty"`
 	Baseboard         *Baseboard `json:"baseboard,omitempty" yaml:"baseboard,omitempty"`
 	Product           *Product   `json:"product,omitempty" yaml:"product,omitempty"`
+	TPM               *TPM       `json:"tpm,omitempty" yaml:"tpm,omitempty"`
 }
 
 type CPU struct {
@@ -44,13 +45,24 @@ type Block struct {
 	Model             *string `json:"model,omitempty" yaml:"model,omitempty"`
 	WWN               *string `json:"wwn,omitempty" yaml:"wwn,omitempty"`
 	SerialNumber      *string `json:"serialNumber,omitempty" yaml:"serialNumber,omitempty"`
+	SmartStatus       *string `json:"smartStatus,omitempty" yaml:"smartStatus,omitempty"`
 }
 
 type Network struct {
-	Name                *string  `json:"name,omitempty" yaml:"name,omitempty"`
-	Mac                 *string  `json:"mac,omitempty" yaml:"mac,omitempty"`
-	Speed               *string  `json:"speed,omitempty" yaml:"speed,omitempty"`
-	EnabledCapabilities []string `json:"enabledCapabilities,omitempty" yaml:"enabledCapabilities,omitempty"`
+	Name                *string         `json:"name,omitempty" yaml:"name,omitempty"`
+	Mac                 *string         `json:"mac,omitempty" yaml:"mac,omitempty"`
+	Speed               *string         `json:"speed,omitempty" yaml:"speed,omitempty"`
+	EnabledCapabilities []string        `json:"enabledCapabilities,omitempty" yaml:"enabledCapabilities,omitempty"`
+	LinkState           *string         `json:"linkState,omitempty" yaml:"linkState,omitempty"`
+	LLDPNeighbors       []*LLDPNeighbor `json:"lldpNeighbors,omitempty" yaml:"lldpNeighbors,omitempty"`
+}
+
+type LLDPNeighbor struct {
+	ChassisID         *string `json:"chassisID,omitempty" yaml:"chassisID,omitempty"`
+	PortID            *string `json:"portID,omitempty" yaml:"portID,omitempty"`
+	PortDescription   *string `json:"portDescription,omitempty" yaml:"portDescription,omitempty"`
+	SystemName        *string `json:"systemName,omitempty" yaml:"systemName,omitempty"`
+	SystemDescription *string `json:"systemDescription,omitempty" yaml:"systemDescription,omitempty"`
 }
 
 type PCI struct {
@@ -91,6 +103,10 @@ type Product struct {
 	SerialNumber *string `json:"serialNumber,omitempty" yaml:"serialNumber,omitempty"`
 }
 
+type TPM struct {
+	Version *string `json:"version,omitempty" yaml:"version,omitempty"`
+}
+
 // NewAgentAttributes initializes a new AgentAttributes struct.
 func NewAgentAttributes() *AgentAttributes {
 	return &AgentAttributes{
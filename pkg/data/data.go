@@ -10,6 +10,19 @@ type HardwareFilter struct {
 	ByMACAddress string
 	ByIPAddress  string
 	ByInstanceID string
+	// BySwitchPort finds Hardware by the switch port an interface is connected to.
+	// Use SwitchPortKey to build the value.
+	BySwitchPort string
+}
+
+// SwitchPortKey returns the HardwareFilter BySwitchPort value of a switch port.
+// Without a remote ID, the circuit ID alone identifies the port.
+func SwitchPortKey(circuitID, remoteID string) string {
+	if remoteID == "" {
+		return circuitID
+	}
+
+	return remoteID + "|" + circuitID
 }
 
 // WorkflowFilter holds selectors for listing Workflows.
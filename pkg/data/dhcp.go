@@ -0,0 +1,42 @@
+package data
+
+import "time"
+
+// DHCPTransaction is a DHCP message received by Smee and the decision made for it.
+// It is used to troubleshoot machines that do not network boot.
+type DHCPTransaction struct {
+	// Time is when the message was received.
+	Time time.Time `json:"time"`
+	// MAC is the client hardware address (chaddr).
+	MAC string `json:"mac"`
+	// XID is the transaction ID of the message.
+	XID string `json:"xid"`
+	// MessageType is the DHCP message type, option 53.
+	MessageType string `json:"messageType"`
+	// Mode is the DHCP mode that handled the message: reservation, proxy or auto-proxy.
+	Mode string `json:"mode"`
+	// Interface is the name of the interface the message was received on.
+	Interface string `json:"interface,omitempty"`
+	// RelayAgent is the relay agent address (giaddr) of a relayed message.
+	RelayAgent string `json:"relayAgent,omitempty"`
+	// Hardware is the namespace/name of the Hardware object that matched the MAC address.
+	Hardware string `json:"hardware,omitempty"`
+	// VendorClass is the vendor class identifier, option 60.
+	VendorClass string `json:"vendorClass,omitempty"`
+	// UserClass is the user class, option 77.
+	UserClass string `json:"userClass,omitempty"`
+	// ClientArch is the client system architecture, option 93.
+	ClientArch string `json:"clientArch,omitempty"`
+	// NetbootError is why no netboot options were sent to the client.
+	NetbootError string `json:"netbootError,omitempty"`
+	// ReplyType is the DHCP message type of the reply. It is empty when no reply was sent.
+	ReplyType string `json:"replyType,omitempty"`
+	// IPAddress is the IP address (yiaddr) of the reply.
+	IPAddress string `json:"ipAddress,omitempty"`
+	// Bootfile is the boot file name of the reply.
+	Bootfile string `json:"bootfile,omitempty"`
+	// NextServer is the next server address (siaddr) of the reply.
+	NextServer string `json:"nextServer,omitempty"`
+	// IgnoredReason is why no reply was sent.
+	IgnoredReason string `json:"ignoredReason,omitempty"`
+}
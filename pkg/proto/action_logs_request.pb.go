@@ -0,0 +1,192 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.7
+// 	protoc        (unknown)
+// source: action_logs_request.proto
+
+package proto
+
+import (
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// ActionLogsRequest is a chunk of the output of a running Action container.
+// The first request on a stream must identify the Action, later requests may
+// only set the data.
+type ActionLogsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The workflow id
+	WorkflowId *string `protobuf:"bytes,1,opt,name=workflow_id,json=workflowId" json:"workflow_id,omitempty"`
+	// The agent id
+	AgentId *string `protobuf:"bytes,2,opt,name=agent_id,json=agentId" json:"agent_id,omitempty"`
+	// The id of the task the action is part of
+	TaskId *string `protobuf:"bytes,3,opt,name=task_id,json=taskId" json:"task_id,omitempty"`
+	// The action id
+	ActionId *string `protobuf:"bytes,4,opt,name=action_id,json=actionId" json:"action_id,omitempty"`
+	// The stream the data was written to, "stdout" or "stderr".
+	Stream *string `protobuf:"bytes,5,opt,name=stream" json:"stream,omitempty"`
+	// The output of the action container.
+	Data []byte `protobuf:"bytes,6,opt,name=data" json:"data,omitempty"`
+	// The time the data was read from the action container.
+	Time          *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=time" json:"time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ActionLogsRequest) Reset() {
+	*x = ActionLogsRequest{}
+	mi := &file_action_logs_request_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ActionLogsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ActionLogsRequest) ProtoMessage() {}
+
+func (x *ActionLogsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_action_logs_request_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ActionLogsRequest.ProtoReflect.Descriptor instead.
+func (*ActionLogsRequest) Descriptor() ([]byte, []int) {
+	return file_action_logs_request_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ActionLogsRequest) GetWorkflowId() string {
+	if x != nil && x.WorkflowId != nil {
+		return *x.WorkflowId
+	}
+	return ""
+}
+
+func (x *ActionLogsRequest) GetAgentId() string {
+	if x != nil && x.AgentId != nil {
+		return *x.AgentId
+	}
+	return ""
+}
+
+func (x *ActionLogsRequest) GetTaskId() string {
+	if x != nil && x.TaskId != nil {
+		return *x.TaskId
+	}
+	return ""
+}
+
+func (x *ActionLogsRequest) GetActionId() string {
+	if x != nil && x.ActionId != nil {
+		return *x.ActionId
+	}
+	return ""
+}
+
+func (x *ActionLogsRequest) GetStream() string {
+	if x != nil && x.Stream != nil {
+		return *x.Stream
+	}
+	return ""
+}
+
+func (x *ActionLogsRequest) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *ActionLogsRequest) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+var File_action_logs_request_proto protoreflect.FileDescriptor
+
+const file_action_logs_request_proto_rawDesc = "" +
+	"\n" +
+	"\x19action_logs_request.proto\x12\x05proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xe1\x01\n" +
+	"\x11ActionLogsRequest\x12\x1f\n" +
+	"\vworkflow_id\x18\x01 \x01(\tR\n" +
+	"workflowId\x12\x19\n" +
+	"\bagent_id\x18\x02 \x01(\tR\aagentId\x12\x17\n" +
+	"\atask_id\x18\x03 \x01(\tR\x06taskId\x12\x1b\n" +
+	"\taction_id\x18\x04 \x01(\tR\bactionId\x12\x16\n" +
+	"\x06stream\x18\x05 \x01(\tR\x06stream\x12\x12\n" +
+	"\x04data\x18\x06 \x01(\fR\x04data\x12.\n" +
+	"\x04time\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\x04timeB\x83\x01\n" +
+	"\tcom.protoB\x16ActionLogsRequestProtoP\x01Z*github.com/tinkerbell/tinkerbell/pkg/proto\xa2\x02\x03PXX\xaa\x02\x05Proto\xca\x02\x05Proto\xe2\x02\x11Proto\\GPBMetadata\xea\x02\x05Protob\beditionsp\xe8\a"
+
+var (
+	file_action_logs_request_proto_rawDescOnce sync.Once
+	file_action_logs_request_proto_rawDescData []byte
+)
+
+func file_action_logs_request_proto_rawDescGZIP() []byte {
+	file_action_logs_request_proto_rawDescOnce.Do(func() {
+		file_action_logs_request_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_action_logs_request_proto_rawDesc), len(file_action_logs_request_proto_rawDesc)))
+	})
+	return file_action_logs_request_proto_rawDescData
+}
+
+var file_action_logs_request_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_action_logs_request_proto_goTypes = []any{
+	(*ActionLogsRequest)(nil),     // 0: proto.ActionLogsRequest
+	(*timestamppb.Timestamp)(nil), // 1: google.protobuf.Timestamp
+}
+var file_action_logs_request_proto_depIdxs = []int32{
+	1, // 0: proto.ActionLogsRequest.time:type_name -> google.protobuf.Timestamp
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_action_logs_request_proto_init() }
+func file_action_logs_request_proto_init() {
+	if File_action_logs_request_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_action_logs_request_proto_rawDesc), len(file_action_logs_request_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_action_logs_request_proto_goTypes,
+		DependencyIndexes: file_action_logs_request_proto_depIdxs,
+		MessageInfos:      file_action_logs_request_proto_msgTypes,
+	}.Build()
+	File_action_logs_request_proto = out.File
+	file_action_logs_request_proto_goTypes = nil
+	file_action_logs_request_proto_depIdxs = nil
+}
@@ -0,0 +1,116 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.7
+// 	protoc        (unknown)
+// source: action_logs_response.proto
+
+package proto
+
+import (
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// ActionLogsResponse is sent once an Agent has closed its log stream.
+type ActionLogsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ActionLogsResponse) Reset() {
+	*x = ActionLogsResponse{}
+	mi := &file_action_logs_response_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ActionLogsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ActionLogsResponse) ProtoMessage() {}
+
+func (x *ActionLogsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_action_logs_response_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ActionLogsResponse.ProtoReflect.Descriptor instead.
+func (*ActionLogsResponse) Descriptor() ([]byte, []int) {
+	return file_action_logs_response_proto_rawDescGZIP(), []int{0}
+}
+
+var File_action_logs_response_proto protoreflect.FileDescriptor
+
+const file_action_logs_response_proto_rawDesc = "" +
+	"\n" +
+	"\x1aaction_logs_response.proto\x12\x05proto\"\x14\n" +
+	"\x12ActionLogsResponseB\x84\x01\n" +
+	"\tcom.protoB\x17ActionLogsResponseProtoP\x01Z*github.com/tinkerbell/tinkerbell/pkg/proto\xa2\x02\x03PXX\xaa\x02\x05Proto\xca\x02\x05Proto\xe2\x02\x11Proto\\GPBMetadata\xea\x02\x05Protob\beditionsp\xe8\a"
+
+var (
+	file_action_logs_response_proto_rawDescOnce sync.Once
+	file_action_logs_response_proto_rawDescData []byte
+)
+
+func file_action_logs_response_proto_rawDescGZIP() []byte {
+	file_action_logs_response_proto_rawDescOnce.Do(func() {
+		file_action_logs_response_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_action_logs_response_proto_rawDesc), len(file_action_logs_response_proto_rawDesc)))
+	})
+	return file_action_logs_response_proto_rawDescData
+}
+
+var file_action_logs_response_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_action_logs_response_proto_goTypes = []any{
+	(*ActionLogsResponse)(nil), // 0: proto.ActionLogsResponse
+}
+var file_action_logs_response_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_action_logs_response_proto_init() }
+func file_action_logs_response_proto_init() {
+	if File_action_logs_response_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_action_logs_response_proto_rawDesc), len(file_action_logs_response_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_action_logs_response_proto_goTypes,
+		DependencyIndexes: file_action_logs_response_proto_depIdxs,
+		MessageInfos:      file_action_logs_response_proto_msgTypes,
+	}.Build()
+	File_action_logs_response_proto = out.File
+	file_action_logs_response_proto_goTypes = nil
+	file_action_logs_response_proto_depIdxs = nil
+}
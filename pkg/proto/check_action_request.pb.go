@@ -0,0 +1,157 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.7
+// 	protoc        (unknown)
+// source: check_action_request.proto
+
+package proto
+
+import (
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// CheckActionRequest is sent periodically by an Agent while it runs an Action.
+type CheckActionRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The workflow id
+	WorkflowId *string `protobuf:"bytes,1,opt,name=workflow_id,json=workflowId" json:"workflow_id,omitempty"`
+	// The ID of the Agent running the action
+	AgentId *string `protobuf:"bytes,2,opt,name=agent_id,json=agentId" json:"agent_id,omitempty"`
+	// The id of the task the action is part of
+	TaskId *string `protobuf:"bytes,3,opt,name=task_id,json=taskId" json:"task_id,omitempty"`
+	// The action id
+	ActionId      *string `protobuf:"bytes,4,opt,name=action_id,json=actionId" json:"action_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckActionRequest) Reset() {
+	*x = CheckActionRequest{}
+	mi := &file_check_action_request_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckActionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckActionRequest) ProtoMessage() {}
+
+func (x *CheckActionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_check_action_request_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckActionRequest.ProtoReflect.Descriptor instead.
+func (*CheckActionRequest) Descriptor() ([]byte, []int) {
+	return file_check_action_request_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CheckActionRequest) GetWorkflowId() string {
+	if x != nil && x.WorkflowId != nil {
+		return *x.WorkflowId
+	}
+	return ""
+}
+
+func (x *CheckActionRequest) GetAgentId() string {
+	if x != nil && x.AgentId != nil {
+		return *x.AgentId
+	}
+	return ""
+}
+
+func (x *CheckActionRequest) GetTaskId() string {
+	if x != nil && x.TaskId != nil {
+		return *x.TaskId
+	}
+	return ""
+}
+
+func (x *CheckActionRequest) GetActionId() string {
+	if x != nil && x.ActionId != nil {
+		return *x.ActionId
+	}
+	return ""
+}
+
+var File_check_action_request_proto protoreflect.FileDescriptor
+
+const file_check_action_request_proto_rawDesc = "" +
+	"\n" +
+	"\x1acheck_action_request.proto\x12\x05proto\"\x86\x01\n" +
+	"\x12CheckActionRequest\x12\x1f\n" +
+	"\vworkflow_id\x18\x01 \x01(\tR\n" +
+	"workflowId\x12\x19\n" +
+	"\bagent_id\x18\x02 \x01(\tR\aagentId\x12\x17\n" +
+	"\atask_id\x18\x03 \x01(\tR\x06taskId\x12\x1b\n" +
+	"\taction_id\x18\x04 \x01(\tR\bactionIdB\x84\x01\n" +
+	"\tcom.protoB\x17CheckActionRequestProtoP\x01Z*github.com/tinkerbell/tinkerbell/pkg/proto\xa2\x02\x03PXX\xaa\x02\x05Proto\xca\x02\x05Proto\xe2\x02\x11Proto\\GPBMetadata\xea\x02\x05Protob\beditionsp\xe8\a"
+
+var (
+	file_check_action_request_proto_rawDescOnce sync.Once
+	file_check_action_request_proto_rawDescData []byte
+)
+
+func file_check_action_request_proto_rawDescGZIP() []byte {
+	file_check_action_request_proto_rawDescOnce.Do(func() {
+		file_check_action_request_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_check_action_request_proto_rawDesc), len(file_check_action_request_proto_rawDesc)))
+	})
+	return file_check_action_request_proto_rawDescData
+}
+
+var file_check_action_request_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_check_action_request_proto_goTypes = []any{
+	(*CheckActionRequest)(nil), // 0: proto.CheckActionRequest
+}
+var file_check_action_request_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_check_action_request_proto_init() }
+func file_check_action_request_proto_init() {
+	if File_check_action_request_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_check_action_request_proto_rawDesc), len(file_check_action_request_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_check_action_request_proto_goTypes,
+		DependencyIndexes: file_check_action_request_proto_depIdxs,
+		MessageInfos:      file_check_action_request_proto_msgTypes,
+	}.Build()
+	File_check_action_request_proto = out.File
+	file_check_action_request_proto_goTypes = nil
+	file_check_action_request_proto_depIdxs = nil
+}
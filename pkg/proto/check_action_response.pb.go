@@ -0,0 +1,127 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.7
+// 	protoc        (unknown)
+// source: check_action_response.proto
+
+package proto
+
+import (
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// CheckActionResponse tells an Agent whether to keep running an Action.
+type CheckActionResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Cancel is true when the Workflow has been canceled. The Agent should
+	// stop the running action and report it as canceled.
+	Cancel        *bool `protobuf:"varint,1,opt,name=cancel" json:"cancel,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckActionResponse) Reset() {
+	*x = CheckActionResponse{}
+	mi := &file_check_action_response_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckActionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckActionResponse) ProtoMessage() {}
+
+func (x *CheckActionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_check_action_response_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckActionResponse.ProtoReflect.Descriptor instead.
+func (*CheckActionResponse) Descriptor() ([]byte, []int) {
+	return file_check_action_response_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CheckActionResponse) GetCancel() bool {
+	if x != nil && x.Cancel != nil {
+		return *x.Cancel
+	}
+	return false
+}
+
+var File_check_action_response_proto protoreflect.FileDescriptor
+
+const file_check_action_response_proto_rawDesc = "" +
+	"\n" +
+	"\x1bcheck_action_response.proto\x12\x05proto\"-\n" +
+	"\x13CheckActionResponse\x12\x16\n" +
+	"\x06cancel\x18\x01 \x01(\bR\x06cancelB\x85\x01\n" +
+	"\tcom.protoB\x18CheckActionResponseProtoP\x01Z*github.com/tinkerbell/tinkerbell/pkg/proto\xa2\x02\x03PXX\xaa\x02\x05Proto\xca\x02\x05Proto\xe2\x02\x11Proto\\GPBMetadata\xea\x02\x05Protob\beditionsp\xe8\a"
+
+var (
+	file_check_action_response_proto_rawDescOnce sync.Once
+	file_check_action_response_proto_rawDescData []byte
+)
+
+func file_check_action_response_proto_rawDescGZIP() []byte {
+	file_check_action_response_proto_rawDescOnce.Do(func() {
+		file_check_action_response_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_check_action_response_proto_rawDesc), len(file_check_action_response_proto_rawDesc)))
+	})
+	return file_check_action_response_proto_rawDescData
+}
+
+var file_check_action_response_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_check_action_response_proto_goTypes = []any{
+	(*CheckActionResponse)(nil), // 0: proto.CheckActionResponse
+}
+var file_check_action_response_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_check_action_response_proto_init() }
+func file_check_action_response_proto_init() {
+	if File_check_action_response_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_check_action_response_proto_rawDesc), len(file_check_action_response_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_check_action_response_proto_goTypes,
+		DependencyIndexes: file_check_action_response_proto_depIdxs,
+		MessageInfos:      file_check_action_response_proto_msgTypes,
+	}.Build()
+	File_check_action_response_proto = out.File
+	file_check_action_response_proto_goTypes = nil
+	file_check_action_response_proto_depIdxs = nil
+}
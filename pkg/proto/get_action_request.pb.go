@@ -78,17 +78,19 @@ func (x *ActionRequest) GetAgentAttributes() *AgentAttributes {
 }
 
 type AgentAttributes struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Cpu           *CPU                   `protobuf:"bytes,1,opt,name=cpu" json:"cpu,omitempty"`
-	Memory        *Memory                `protobuf:"bytes,2,opt,name=memory" json:"memory,omitempty"`
-	Block         []*Block               `protobuf:"bytes,3,rep,name=block" json:"block,omitempty"`
-	Network       []*Network             `protobuf:"bytes,4,rep,name=network" json:"network,omitempty"`
-	Pci           []*PCI                 `protobuf:"bytes,5,rep,name=pci" json:"pci,omitempty"`
-	Gpu           []*GPU                 `protobuf:"bytes,6,rep,name=gpu" json:"gpu,omitempty"`
-	Chassis       *Chassis               `protobuf:"bytes,7,opt,name=chassis" json:"chassis,omitempty"`
-	Bios          *BIOS                  `protobuf:"bytes,8,opt,name=bios" json:"bios,omitempty"`
-	Baseboard     *Baseboard             `protobuf:"bytes,9,opt,name=baseboard" json:"baseboard,omitempty"`
-	Product       *Product               `protobuf:"bytes,10,opt,name=product" json:"product,omitempty"`
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Cpu       *CPU                   `protobuf:"bytes,1,opt,name=cpu" json:"cpu,omitempty"`
+	Memory    *Memory                `protobuf:"bytes,2,opt,name=memory" json:"memory,omitempty"`
+	Block     []*Block               `protobuf:"bytes,3,rep,name=block" json:"block,omitempty"`
+	Network   []*Network             `protobuf:"bytes,4,rep,name=network" json:"network,omitempty"`
+	Pci       []*PCI                 `protobuf:"bytes,5,rep,name=pci" json:"pci,omitempty"`
+	Gpu       []*GPU                 `protobuf:"bytes,6,rep,name=gpu" json:"gpu,omitempty"`
+	Chassis   *Chassis               `protobuf:"bytes,7,opt,name=chassis" json:"chassis,omitempty"`
+	Bios      *BIOS                  `protobuf:"bytes,8,opt,name=bios" json:"bios,omitempty"`
+	Baseboard *Baseboard             `protobuf:"bytes,9,opt,name=baseboard" json:"baseboard,omitempty"`
+	Product   *Product               `protobuf:"bytes,10,opt,name=product" json:"product,omitempty"`
+	// The TPM of the Agent's machine, unset when it has none
+	Tpm           *TPM `protobuf:"bytes,11,opt,name=tpm" json:"tpm,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -193,6 +195,13 @@ func (x *AgentAttributes) GetProduct() *Product {
 	return nil
 }
 
+func (x *AgentAttributes) GetTpm() *TPM {
+	if x != nil {
+		return x.Tpm
+	}
+	return nil
+}
+
 type CPU struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	TotalCores    *uint32                `protobuf:"varint,1,opt,name=total_cores,json=totalCores" json:"total_cores,omitempty"`
@@ -400,8 +409,10 @@ type Block struct {
 	Model             *string                `protobuf:"bytes,7,opt,name=model" json:"model,omitempty"`
 	Wwn               *string                `protobuf:"bytes,8,opt,name=wwn" json:"wwn,omitempty"`
 	SerialNumber      *string                `protobuf:"bytes,9,opt,name=serial_number,json=serialNumber" json:"serial_number,omitempty"`
-	unknownFields     protoimpl.UnknownFields
-	sizeCache         protoimpl.SizeCache
+	// The SMART overall-health self-assessment of the drive, "passed" or "failed". Unset when it couldn't be read
+	SmartStatus   *string `protobuf:"bytes,10,opt,name=smart_status,json=smartStatus" json:"smart_status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Block) Reset() {
@@ -497,14 +508,25 @@ func (x *Block) GetSerialNumber() string {
 	return ""
 }
 
+func (x *Block) GetSmartStatus() string {
+	if x != nil && x.SmartStatus != nil {
+		return *x.SmartStatus
+	}
+	return ""
+}
+
 type Network struct {
 	state               protoimpl.MessageState `protogen:"open.v1"`
 	Name                *string                `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
 	Mac                 *string                `protobuf:"bytes,2,opt,name=mac" json:"mac,omitempty"`
 	Speed               *string                `protobuf:"bytes,3,opt,name=speed" json:"speed,omitempty"`
 	EnabledCapabilities []string               `protobuf:"bytes,4,rep,name=enabled_capabilities,json=enabledCapabilities" json:"enabled_capabilities,omitempty"`
-	unknownFields       protoimpl.UnknownFields
-	sizeCache           protoimpl.SizeCache
+	// Whether the interface has a carrier, "up" or "down"
+	LinkState *string `protobuf:"bytes,5,opt,name=link_state,json=linkState" json:"link_state,omitempty"`
+	// The neighbours, usually the switch port the interface is cabled to, that sent an LLDP frame on the interface
+	LldpNeighbors []*LLDPNeighbor `protobuf:"bytes,6,rep,name=lldp_neighbors,json=lldpNeighbors" json:"lldp_neighbors,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Network) Reset() {
@@ -565,6 +587,96 @@ func (x *Network) GetEnabledCapabilities() []string {
 	return nil
 }
 
+func (x *Network) GetLinkState() string {
+	if x != nil && x.LinkState != nil {
+		return *x.LinkState
+	}
+	return ""
+}
+
+func (x *Network) GetLldpNeighbors() []*LLDPNeighbor {
+	if x != nil {
+		return x.LldpNeighbors
+	}
+	return nil
+}
+
+type LLDPNeighbor struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	ChassisId         *string                `protobuf:"bytes,1,opt,name=chassis_id,json=chassisId" json:"chassis_id,omitempty"`
+	PortId            *string                `protobuf:"bytes,2,opt,name=port_id,json=portId" json:"port_id,omitempty"`
+	PortDescription   *string                `protobuf:"bytes,3,opt,name=port_description,json=portDescription" json:"port_description,omitempty"`
+	SystemName        *string                `protobuf:"bytes,4,opt,name=system_name,json=systemName" json:"system_name,omitempty"`
+	SystemDescription *string                `protobuf:"bytes,5,opt,name=system_description,json=systemDescription" json:"system_description,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *LLDPNeighbor) Reset() {
+	*x = LLDPNeighbor{}
+	mi := &file_get_action_request_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LLDPNeighbor) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LLDPNeighbor) ProtoMessage() {}
+
+func (x *LLDPNeighbor) ProtoReflect() protoreflect.Message {
+	mi := &file_get_action_request_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LLDPNeighbor.ProtoReflect.Descriptor instead.
+func (*LLDPNeighbor) Descriptor() ([]byte, []int) {
+	return file_get_action_request_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *LLDPNeighbor) GetChassisId() string {
+	if x != nil && x.ChassisId != nil {
+		return *x.ChassisId
+	}
+	return ""
+}
+
+func (x *LLDPNeighbor) GetPortId() string {
+	if x != nil && x.PortId != nil {
+		return *x.PortId
+	}
+	return ""
+}
+
+func (x *LLDPNeighbor) GetPortDescription() string {
+	if x != nil && x.PortDescription != nil {
+		return *x.PortDescription
+	}
+	return ""
+}
+
+func (x *LLDPNeighbor) GetSystemName() string {
+	if x != nil && x.SystemName != nil {
+		return *x.SystemName
+	}
+	return ""
+}
+
+func (x *LLDPNeighbor) GetSystemDescription() string {
+	if x != nil && x.SystemDescription != nil {
+		return *x.SystemDescription
+	}
+	return ""
+}
+
 type PCI struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Vendor        *string                `protobuf:"bytes,1,opt,name=vendor" json:"vendor,omitempty"`
@@ -577,7 +689,7 @@ type PCI struct {
 
 func (x *PCI) Reset() {
 	*x = PCI{}
-	mi := &file_get_action_request_proto_msgTypes[7]
+	mi := &file_get_action_request_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -589,7 +701,7 @@ func (x *PCI) String() string {
 func (*PCI) ProtoMessage() {}
 
 func (x *PCI) ProtoReflect() protoreflect.Message {
-	mi := &file_get_action_request_proto_msgTypes[7]
+	mi := &file_get_action_request_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -602,7 +714,7 @@ func (x *PCI) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PCI.ProtoReflect.Descriptor instead.
 func (*PCI) Descriptor() ([]byte, []int) {
-	return file_get_action_request_proto_rawDescGZIP(), []int{7}
+	return file_get_action_request_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *PCI) GetVendor() string {
@@ -645,7 +757,7 @@ type GPU struct {
 
 func (x *GPU) Reset() {
 	*x = GPU{}
-	mi := &file_get_action_request_proto_msgTypes[8]
+	mi := &file_get_action_request_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -657,7 +769,7 @@ func (x *GPU) String() string {
 func (*GPU) ProtoMessage() {}
 
 func (x *GPU) ProtoReflect() protoreflect.Message {
-	mi := &file_get_action_request_proto_msgTypes[8]
+	mi := &file_get_action_request_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -670,7 +782,7 @@ func (x *GPU) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GPU.ProtoReflect.Descriptor instead.
 func (*GPU) Descriptor() ([]byte, []int) {
-	return file_get_action_request_proto_rawDescGZIP(), []int{8}
+	return file_get_action_request_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *GPU) GetVendor() string {
@@ -711,7 +823,7 @@ type Chassis struct {
 
 func (x *Chassis) Reset() {
 	*x = Chassis{}
-	mi := &file_get_action_request_proto_msgTypes[9]
+	mi := &file_get_action_request_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -723,7 +835,7 @@ func (x *Chassis) String() string {
 func (*Chassis) ProtoMessage() {}
 
 func (x *Chassis) ProtoReflect() protoreflect.Message {
-	mi := &file_get_action_request_proto_msgTypes[9]
+	mi := &file_get_action_request_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -736,7 +848,7 @@ func (x *Chassis) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Chassis.ProtoReflect.Descriptor instead.
 func (*Chassis) Descriptor() ([]byte, []int) {
-	return file_get_action_request_proto_rawDescGZIP(), []int{9}
+	return file_get_action_request_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *Chassis) GetSerial() string {
@@ -764,7 +876,7 @@ type BIOS struct {
 
 func (x *BIOS) Reset() {
 	*x = BIOS{}
-	mi := &file_get_action_request_proto_msgTypes[10]
+	mi := &file_get_action_request_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -776,7 +888,7 @@ func (x *BIOS) String() string {
 func (*BIOS) ProtoMessage() {}
 
 func (x *BIOS) ProtoReflect() protoreflect.Message {
-	mi := &file_get_action_request_proto_msgTypes[10]
+	mi := &file_get_action_request_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -789,7 +901,7 @@ func (x *BIOS) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BIOS.ProtoReflect.Descriptor instead.
 func (*BIOS) Descriptor() ([]byte, []int) {
-	return file_get_action_request_proto_rawDescGZIP(), []int{10}
+	return file_get_action_request_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *BIOS) GetVendor() string {
@@ -825,7 +937,7 @@ type Baseboard struct {
 
 func (x *Baseboard) Reset() {
 	*x = Baseboard{}
-	mi := &file_get_action_request_proto_msgTypes[11]
+	mi := &file_get_action_request_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -837,7 +949,7 @@ func (x *Baseboard) String() string {
 func (*Baseboard) ProtoMessage() {}
 
 func (x *Baseboard) ProtoReflect() protoreflect.Message {
-	mi := &file_get_action_request_proto_msgTypes[11]
+	mi := &file_get_action_request_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -850,7 +962,7 @@ func (x *Baseboard) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Baseboard.ProtoReflect.Descriptor instead.
 func (*Baseboard) Descriptor() ([]byte, []int) {
-	return file_get_action_request_proto_rawDescGZIP(), []int{11}
+	return file_get_action_request_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *Baseboard) GetVendor() string {
@@ -892,7 +1004,7 @@ type Product struct {
 
 func (x *Product) Reset() {
 	*x = Product{}
-	mi := &file_get_action_request_proto_msgTypes[12]
+	mi := &file_get_action_request_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -904,7 +1016,7 @@ func (x *Product) String() string {
 func (*Product) ProtoMessage() {}
 
 func (x *Product) ProtoReflect() protoreflect.Message {
-	mi := &file_get_action_request_proto_msgTypes[12]
+	mi := &file_get_action_request_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -917,7 +1029,7 @@ func (x *Product) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Product.ProtoReflect.Descriptor instead.
 func (*Product) Descriptor() ([]byte, []int) {
-	return file_get_action_request_proto_rawDescGZIP(), []int{12}
+	return file_get_action_request_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *Product) GetName() string {
@@ -941,6 +1053,51 @@ func (x *Product) GetSerialNumber() string {
 	return ""
 }
 
+type TPM struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The TPM specification version, "1.2" or "2.0"
+	Version       *string `protobuf:"bytes,1,opt,name=version" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TPM) Reset() {
+	*x = TPM{}
+	mi := &file_get_action_request_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TPM) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TPM) ProtoMessage() {}
+
+func (x *TPM) ProtoReflect() protoreflect.Message {
+	mi := &file_get_action_request_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TPM.ProtoReflect.Descriptor instead.
+func (*TPM) Descriptor() ([]byte, []int) {
+	return file_get_action_request_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *TPM) GetVersion() string {
+	if x != nil && x.Version != nil {
+		return *x.Version
+	}
+	return ""
+}
+
 var File_get_action_request_proto protoreflect.FileDescriptor
 
 const file_get_action_request_proto_rawDesc = "" +
@@ -948,7 +1105,7 @@ const file_get_action_request_proto_rawDesc = "" +
 	"\x18get_action_request.proto\x12\x05proto\"m\n" +
 	"\rActionRequest\x12\x19\n" +
 	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12A\n" +
-	"\x10agent_attributes\x18\x02 \x01(\v2\x16.proto.AgentAttributesR\x0fagentAttributes\"\x85\x03\n" +
+	"\x10agent_attributes\x18\x02 \x01(\v2\x16.proto.AgentAttributesR\x0fagentAttributes\"\xa3\x03\n" +
 	"\x0fAgentAttributes\x12\x1c\n" +
 	"\x03cpu\x18\x01 \x01(\v2\n" +
 	".proto.CPUR\x03cpu\x12%\n" +
@@ -963,7 +1120,9 @@ const file_get_action_request_proto_rawDesc = "" +
 	"\x04bios\x18\b \x01(\v2\v.proto.BIOSR\x04bios\x12.\n" +
 	"\tbaseboard\x18\t \x01(\v2\x10.proto.BaseboardR\tbaseboard\x12(\n" +
 	"\aproduct\x18\n" +
-	" \x01(\v2\x0e.proto.ProductR\aproduct\"}\n" +
+	" \x01(\v2\x0e.proto.ProductR\aproduct\x12\x1c\n" +
+	"\x03tpm\x18\v \x01(\v2\n" +
+	".proto.TPMR\x03tpm\"}\n" +
 	"\x03CPU\x12\x1f\n" +
 	"\vtotal_cores\x18\x01 \x01(\rR\n" +
 	"totalCores\x12#\n" +
@@ -980,7 +1139,7 @@ const file_get_action_request_proto_rawDesc = "" +
 	"\fcapabilities\x18\x06 \x03(\tR\fcapabilities\"6\n" +
 	"\x06Memory\x12\x14\n" +
 	"\x05total\x18\x01 \x01(\tR\x05total\x12\x16\n" +
-	"\x06usable\x18\x02 \x01(\tR\x06usable\"\x8c\x02\n" +
+	"\x06usable\x18\x02 \x01(\tR\x06usable\"\xaf\x02\n" +
 	"\x05Block\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12'\n" +
 	"\x0fcontroller_type\x18\x02 \x01(\tR\x0econtrollerType\x12\x1d\n" +
@@ -991,12 +1150,25 @@ const file_get_action_request_proto_rawDesc = "" +
 	"\x06vendor\x18\x06 \x01(\tR\x06vendor\x12\x14\n" +
 	"\x05model\x18\a \x01(\tR\x05model\x12\x10\n" +
 	"\x03wwn\x18\b \x01(\tR\x03wwn\x12#\n" +
-	"\rserial_number\x18\t \x01(\tR\fserialNumber\"x\n" +
+	"\rserial_number\x18\t \x01(\tR\fserialNumber\x12!\n" +
+	"\fsmart_status\x18\n" +
+	" \x01(\tR\vsmartStatus\"\xd3\x01\n" +
 	"\aNetwork\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x10\n" +
 	"\x03mac\x18\x02 \x01(\tR\x03mac\x12\x14\n" +
 	"\x05speed\x18\x03 \x01(\tR\x05speed\x121\n" +
-	"\x14enabled_capabilities\x18\x04 \x03(\tR\x13enabledCapabilities\"e\n" +
+	"\x14enabled_capabilities\x18\x04 \x03(\tR\x13enabledCapabilities\x12\x1d\n" +
+	"\n" +
+	"link_state\x18\x05 \x01(\tR\tlinkState\x12:\n" +
+	"\x0elldp_neighbors\x18\x06 \x03(\v2\x13.proto.LLDPNeighborR\rlldpNeighbors\"\xc1\x01\n" +
+	"\fLLDPNeighbor\x12\x1d\n" +
+	"\n" +
+	"chassis_id\x18\x01 \x01(\tR\tchassisId\x12\x17\n" +
+	"\aport_id\x18\x02 \x01(\tR\x06portId\x12)\n" +
+	"\x10port_description\x18\x03 \x01(\tR\x0fportDescription\x12\x1f\n" +
+	"\vsystem_name\x18\x04 \x01(\tR\n" +
+	"systemName\x12-\n" +
+	"\x12system_description\x18\x05 \x01(\tR\x11systemDescription\"e\n" +
 	"\x03PCI\x12\x16\n" +
 	"\x06vendor\x18\x01 \x01(\tR\x06vendor\x12\x18\n" +
 	"\aproduct\x18\x02 \x01(\tR\aproduct\x12\x14\n" +
@@ -1022,7 +1194,9 @@ const file_get_action_request_proto_rawDesc = "" +
 	"\aProduct\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
 	"\x06vendor\x18\x02 \x01(\tR\x06vendor\x12#\n" +
-	"\rserial_number\x18\x03 \x01(\tR\fserialNumberB\x82\x01\n" +
+	"\rserial_number\x18\x03 \x01(\tR\fserialNumber\"\x1f\n" +
+	"\x03TPM\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\tR\aversionB\x82\x01\n" +
 	"\tcom.protoB\x15GetActionRequestProtoP\x01Z*github.com/tinkerbell/tinkerbell/pkg/proto\xa2\x02\x03PXX\xaa\x02\x05Proto\xca\x02\x05Proto\xe2\x02\x11Proto\\GPBMetadata\xea\x02\x05Protob\beditionsp\xe8\a"
 
 var (
@@ -1037,7 +1211,7 @@ func file_get_action_request_proto_rawDescGZIP() []byte {
 	return file_get_action_request_proto_rawDescData
 }
 
-var file_get_action_request_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_get_action_request_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
 var file_get_action_request_proto_goTypes = []any{
 	(*ActionRequest)(nil),   // 0: proto.ActionRequest
 	(*AgentAttributes)(nil), // 1: proto.AgentAttributes
@@ -1046,12 +1220,14 @@ var file_get_action_request_proto_goTypes = []any{
 	(*Memory)(nil),          // 4: proto.Memory
 	(*Block)(nil),           // 5: proto.Block
 	(*Network)(nil),         // 6: proto.Network
-	(*PCI)(nil),             // 7: proto.PCI
-	(*GPU)(nil),             // 8: proto.GPU
-	(*Chassis)(nil),         // 9: proto.Chassis
-	(*BIOS)(nil),            // 10: proto.BIOS
-	(*Baseboard)(nil),       // 11: proto.Baseboard
-	(*Product)(nil),         // 12: proto.Product
+	(*LLDPNeighbor)(nil),    // 7: proto.LLDPNeighbor
+	(*PCI)(nil),             // 8: proto.PCI
+	(*GPU)(nil),             // 9: proto.GPU
+	(*Chassis)(nil),         // 10: proto.Chassis
+	(*BIOS)(nil),            // 11: proto.BIOS
+	(*Baseboard)(nil),       // 12: proto.Baseboard
+	(*Product)(nil),         // 13: proto.Product
+	(*TPM)(nil),             // 14: proto.TPM
 }
 var file_get_action_request_proto_depIdxs = []int32{
 	1,  // 0: proto.ActionRequest.agent_attributes:type_name -> proto.AgentAttributes
@@ -1059,18 +1235,20 @@ var file_get_action_request_proto_depIdxs = []int32{
 	4,  // 2: proto.AgentAttributes.memory:type_name -> proto.Memory
 	5,  // 3: proto.AgentAttributes.block:type_name -> proto.Block
 	6,  // 4: proto.AgentAttributes.network:type_name -> proto.Network
-	7,  // 5: proto.AgentAttributes.pci:type_name -> proto.PCI
-	8,  // 6: proto.AgentAttributes.gpu:type_name -> proto.GPU
-	9,  // 7: proto.AgentAttributes.chassis:type_name -> proto.Chassis
-	10, // 8: proto.AgentAttributes.bios:type_name -> proto.BIOS
-	11, // 9: proto.AgentAttributes.baseboard:type_name -> proto.Baseboard
-	12, // 10: proto.AgentAttributes.product:type_name -> proto.Product
-	3,  // 11: proto.CPU.processors:type_name -> proto.Processor
-	12, // [12:12] is the sub-list for method output_type
-	12, // [12:12] is the sub-list for method input_type
-	12, // [12:12] is the sub-list for extension type_name
-	12, // [12:12] is the sub-list for extension extendee
-	0,  // [0:12] is the sub-list for field type_name
+	8,  // 5: proto.AgentAttributes.pci:type_name -> proto.PCI
+	9,  // 6: proto.AgentAttributes.gpu:type_name -> proto.GPU
+	10, // 7: proto.AgentAttributes.chassis:type_name -> proto.Chassis
+	11, // 8: proto.AgentAttributes.bios:type_name -> proto.BIOS
+	12, // 9: proto.AgentAttributes.baseboard:type_name -> proto.Baseboard
+	13, // 10: proto.AgentAttributes.product:type_name -> proto.Product
+	14, // 11: proto.AgentAttributes.tpm:type_name -> proto.TPM
+	3,  // 12: proto.CPU.processors:type_name -> proto.Processor
+	7,  // 13: proto.Network.lldp_neighbors:type_name -> proto.LLDPNeighbor
+	14, // [14:14] is the sub-list for method output_type
+	14, // [14:14] is the sub-list for method input_type
+	14, // [14:14] is the sub-list for extension type_name
+	14, // [14:14] is the sub-list for extension extendee
+	0,  // [0:14] is the sub-list for field type_name
 }
 
 func init() { file_get_action_request_proto_init() }
@@ -1084,7 +1262,7 @@ func file_get_action_request_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_get_action_request_proto_rawDesc), len(file_get_action_request_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   13,
+			NumMessages:   15,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -22,6 +22,58 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// ActionType is how an action runs.
+type ActionType int32
+
+const (
+	ActionType_ACTION_TYPE_UNSPECIFIED ActionType = 0
+	// The action runs in a container started from its image.
+	ActionType_ACTION_TYPE_CONTAINER ActionType = 1
+	// The action runs its command directly on the host.
+	ActionType_ACTION_TYPE_NATIVE ActionType = 2
+)
+
+// Enum value maps for ActionType.
+var (
+	ActionType_name = map[int32]string{
+		0: "ACTION_TYPE_UNSPECIFIED",
+		1: "ACTION_TYPE_CONTAINER",
+		2: "ACTION_TYPE_NATIVE",
+	}
+	ActionType_value = map[string]int32{
+		"ACTION_TYPE_UNSPECIFIED": 0,
+		"ACTION_TYPE_CONTAINER":   1,
+		"ACTION_TYPE_NATIVE":      2,
+	}
+)
+
+func (x ActionType) Enum() *ActionType {
+	p := new(ActionType)
+	*p = x
+	return p
+}
+
+func (x ActionType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ActionType) Descriptor() protoreflect.EnumDescriptor {
+	return file_get_action_response_proto_enumTypes[0].Descriptor()
+}
+
+func (ActionType) Type() protoreflect.EnumType {
+	return &file_get_action_response_proto_enumTypes[0]
+}
+
+func (x ActionType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ActionType.Descriptor instead.
+func (ActionType) EnumDescriptor() ([]byte, []int) {
+	return file_get_action_response_proto_rawDescGZIP(), []int{0}
+}
+
 type PreconditionFailureViolation int32
 
 const (
@@ -52,11 +104,11 @@ func (x PreconditionFailureViolation) String() string {
 }
 
 func (PreconditionFailureViolation) Descriptor() protoreflect.EnumDescriptor {
-	return file_get_action_response_proto_enumTypes[0].Descriptor()
+	return file_get_action_response_proto_enumTypes[1].Descriptor()
 }
 
 func (PreconditionFailureViolation) Type() protoreflect.EnumType {
-	return &file_get_action_response_proto_enumTypes[0]
+	return &file_get_action_response_proto_enumTypes[1]
 }
 
 func (x PreconditionFailureViolation) Number() protoreflect.EnumNumber {
@@ -65,7 +117,7 @@ func (x PreconditionFailureViolation) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use PreconditionFailureViolation.Descriptor instead.
 func (PreconditionFailureViolation) EnumDescriptor() ([]byte, []int) {
-	return file_get_action_response_proto_rawDescGZIP(), []int{0}
+	return file_get_action_response_proto_rawDescGZIP(), []int{1}
 }
 
 // ActionResponse
@@ -98,7 +150,28 @@ type ActionResponse struct {
 	// compatibility; namespaces.pid takes precedence when both are set.
 	Pid *string `protobuf:"bytes,11,opt,name=pid" json:"pid,omitempty"`
 	// The Linux namespaces the action container should run in.
-	Namespaces    *Namespaces `protobuf:"bytes,12,opt,name=namespaces" json:"namespaces,omitempty"`
+	Namespaces *Namespaces `protobuf:"bytes,12,opt,name=namespaces" json:"namespaces,omitempty"`
+	// Override the entrypoint of the action container. When empty the image's
+	// entrypoint is used and command holds its arguments.
+	Entrypoint *string `protobuf:"bytes,13,opt,name=entrypoint" json:"entrypoint,omitempty"`
+	// The command run, using the action image, when the action times out.
+	OnTimeout []string `protobuf:"bytes,14,rep,name=on_timeout,json=onTimeout" json:"on_timeout,omitempty"`
+	// The command run, using the action image, when the action fails.
+	OnFailure []string `protobuf:"bytes,15,rep,name=on_failure,json=onFailure" json:"on_failure,omitempty"`
+	// The maximum number of times the action is run until it succeeds.
+	// Zero and one both mean the action is run once.
+	Retries *int64 `protobuf:"varint,16,opt,name=retries" json:"retries,omitempty"`
+	// The number of seconds to wait between runs of the action.
+	RetryDelay *int64 `protobuf:"varint,17,opt,name=retry_delay,json=retryDelay" json:"retry_delay,omitempty"`
+	// The actions of the workflow that run on this agent after this one, in
+	// order. Agents use them to pull images before they are needed.
+	RemainingActions []*RemainingAction `protobuf:"bytes,18,rep,name=remaining_actions,json=remainingActions" json:"remaining_actions,omitempty"`
+	// How the action runs. Native actions run command directly on the host,
+	// without a container, and have no image.
+	Type *ActionType `protobuf:"varint,19,opt,name=type,enum=proto.ActionType" json:"type,omitempty"`
+	// Restrictions on the action container. When unset the container runs with
+	// the runtime's defaults.
+	Sandbox       *Sandbox `protobuf:"bytes,20,opt,name=sandbox" json:"sandbox,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -217,6 +290,232 @@ func (x *ActionResponse) GetNamespaces() *Namespaces {
 	return nil
 }
 
+func (x *ActionResponse) GetEntrypoint() string {
+	if x != nil && x.Entrypoint != nil {
+		return *x.Entrypoint
+	}
+	return ""
+}
+
+func (x *ActionResponse) GetOnTimeout() []string {
+	if x != nil {
+		return x.OnTimeout
+	}
+	return nil
+}
+
+func (x *ActionResponse) GetOnFailure() []string {
+	if x != nil {
+		return x.OnFailure
+	}
+	return nil
+}
+
+func (x *ActionResponse) GetRetries() int64 {
+	if x != nil && x.Retries != nil {
+		return *x.Retries
+	}
+	return 0
+}
+
+func (x *ActionResponse) GetRetryDelay() int64 {
+	if x != nil && x.RetryDelay != nil {
+		return *x.RetryDelay
+	}
+	return 0
+}
+
+func (x *ActionResponse) GetRemainingActions() []*RemainingAction {
+	if x != nil {
+		return x.RemainingActions
+	}
+	return nil
+}
+
+func (x *ActionResponse) GetType() ActionType {
+	if x != nil && x.Type != nil {
+		return *x.Type
+	}
+	return ActionType_ACTION_TYPE_UNSPECIFIED
+}
+
+func (x *ActionResponse) GetSandbox() *Sandbox {
+	if x != nil {
+		return x.Sandbox
+	}
+	return nil
+}
+
+// Sandbox restricts what an action container can do and use.
+type Sandbox struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The maximum number of CPUs, as a Kubernetes quantity like 500m or 2.
+	Cpu *string `protobuf:"bytes,1,opt,name=cpu" json:"cpu,omitempty"`
+	// The maximum memory, as a Kubernetes quantity like 512Mi.
+	Memory *string `protobuf:"bytes,2,opt,name=memory" json:"memory,omitempty"`
+	// The maximum number of processes.
+	Pids *int64 `protobuf:"varint,3,opt,name=pids" json:"pids,omitempty"`
+	// Linux capabilities added to the runtime's default set, like NET_ADMIN.
+	CapabilitiesAdd []string `protobuf:"bytes,4,rep,name=capabilities_add,json=capabilitiesAdd" json:"capabilities_add,omitempty"`
+	// Linux capabilities dropped from the runtime's default set.
+	CapabilitiesDrop []string `protobuf:"bytes,5,rep,name=capabilities_drop,json=capabilitiesDrop" json:"capabilities_drop,omitempty"`
+	// Mount the root filesystem of the container read-only.
+	ReadOnlyRootFilesystem *bool `protobuf:"varint,6,opt,name=read_only_root_filesystem,json=readOnlyRootFilesystem" json:"read_only_root_filesystem,omitempty"`
+	// Run the container privileged. When unset the runtime's default is used.
+	Privileged    *bool `protobuf:"varint,7,opt,name=privileged" json:"privileged,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Sandbox) Reset() {
+	*x = Sandbox{}
+	mi := &file_get_action_response_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Sandbox) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Sandbox) ProtoMessage() {}
+
+func (x *Sandbox) ProtoReflect() protoreflect.Message {
+	mi := &file_get_action_response_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Sandbox.ProtoReflect.Descriptor instead.
+func (*Sandbox) Descriptor() ([]byte, []int) {
+	return file_get_action_response_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Sandbox) GetCpu() string {
+	if x != nil && x.Cpu != nil {
+		return *x.Cpu
+	}
+	return ""
+}
+
+func (x *Sandbox) GetMemory() string {
+	if x != nil && x.Memory != nil {
+		return *x.Memory
+	}
+	return ""
+}
+
+func (x *Sandbox) GetPids() int64 {
+	if x != nil && x.Pids != nil {
+		return *x.Pids
+	}
+	return 0
+}
+
+func (x *Sandbox) GetCapabilitiesAdd() []string {
+	if x != nil {
+		return x.CapabilitiesAdd
+	}
+	return nil
+}
+
+func (x *Sandbox) GetCapabilitiesDrop() []string {
+	if x != nil {
+		return x.CapabilitiesDrop
+	}
+	return nil
+}
+
+func (x *Sandbox) GetReadOnlyRootFilesystem() bool {
+	if x != nil && x.ReadOnlyRootFilesystem != nil {
+		return *x.ReadOnlyRootFilesystem
+	}
+	return false
+}
+
+func (x *Sandbox) GetPrivileged() bool {
+	if x != nil && x.Privileged != nil {
+		return *x.Privileged
+	}
+	return false
+}
+
+// RemainingAction is an action of a workflow that has not run yet.
+type RemainingAction struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	TaskId   *string                `protobuf:"bytes,1,opt,name=task_id,json=taskId" json:"task_id,omitempty"`
+	ActionId *string                `protobuf:"bytes,2,opt,name=action_id,json=actionId" json:"action_id,omitempty"`
+	Name     *string                `protobuf:"bytes,3,opt,name=name" json:"name,omitempty"`
+	// The docker/oci image the action starts from
+	Image         *string `protobuf:"bytes,4,opt,name=image" json:"image,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemainingAction) Reset() {
+	*x = RemainingAction{}
+	mi := &file_get_action_response_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemainingAction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemainingAction) ProtoMessage() {}
+
+func (x *RemainingAction) ProtoReflect() protoreflect.Message {
+	mi := &file_get_action_response_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemainingAction.ProtoReflect.Descriptor instead.
+func (*RemainingAction) Descriptor() ([]byte, []int) {
+	return file_get_action_response_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *RemainingAction) GetTaskId() string {
+	if x != nil && x.TaskId != nil {
+		return *x.TaskId
+	}
+	return ""
+}
+
+func (x *RemainingAction) GetActionId() string {
+	if x != nil && x.ActionId != nil {
+		return *x.ActionId
+	}
+	return ""
+}
+
+func (x *RemainingAction) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *RemainingAction) GetImage() string {
+	if x != nil && x.Image != nil {
+		return *x.Image
+	}
+	return ""
+}
+
 // Namespaces defines the Linux namespaces an action container runs in.
 // This mirrors the v1alpha2 API spec.
 type Namespaces struct {
@@ -233,7 +532,7 @@ type Namespaces struct {
 
 func (x *Namespaces) Reset() {
 	*x = Namespaces{}
-	mi := &file_get_action_response_proto_msgTypes[1]
+	mi := &file_get_action_response_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -245,7 +544,7 @@ func (x *Namespaces) String() string {
 func (*Namespaces) ProtoMessage() {}
 
 func (x *Namespaces) ProtoReflect() protoreflect.Message {
-	mi := &file_get_action_response_proto_msgTypes[1]
+	mi := &file_get_action_response_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -258,7 +557,7 @@ func (x *Namespaces) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Namespaces.ProtoReflect.Descriptor instead.
 func (*Namespaces) Descriptor() ([]byte, []int) {
-	return file_get_action_response_proto_rawDescGZIP(), []int{1}
+	return file_get_action_response_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *Namespaces) GetNetwork() string {
@@ -279,7 +578,7 @@ var File_get_action_response_proto protoreflect.FileDescriptor
 
 const file_get_action_response_proto_rawDesc = "" +
 	"\n" +
-	"\x19get_action_response.proto\x12\x05proto\"\xe1\x02\n" +
+	"\x19get_action_response.proto\x12\x05proto\"\x90\x05\n" +
 	"\x0eActionResponse\x12\x1f\n" +
 	"\vworkflow_id\x18\x01 \x01(\tR\n" +
 	"workflowId\x12\x17\n" +
@@ -296,11 +595,44 @@ const file_get_action_response_proto_rawDesc = "" +
 	"\x03pid\x18\v \x01(\tR\x03pid\x121\n" +
 	"\n" +
 	"namespaces\x18\f \x01(\v2\x11.proto.NamespacesR\n" +
-	"namespaces\"8\n" +
+	"namespaces\x12\x1e\n" +
+	"\n" +
+	"entrypoint\x18\r \x01(\tR\n" +
+	"entrypoint\x12\x1d\n" +
+	"\n" +
+	"on_timeout\x18\x0e \x03(\tR\tonTimeout\x12\x1d\n" +
+	"\n" +
+	"on_failure\x18\x0f \x03(\tR\tonFailure\x12\x18\n" +
+	"\aretries\x18\x10 \x01(\x03R\aretries\x12\x1f\n" +
+	"\vretry_delay\x18\x11 \x01(\x03R\n" +
+	"retryDelay\x12C\n" +
+	"\x11remaining_actions\x18\x12 \x03(\v2\x16.proto.RemainingActionR\x10remainingActions\x12%\n" +
+	"\x04type\x18\x13 \x01(\x0e2\x11.proto.ActionTypeR\x04type\x12(\n" +
+	"\asandbox\x18\x14 \x01(\v2\x0e.proto.SandboxR\asandbox\"\xfa\x01\n" +
+	"\aSandbox\x12\x10\n" +
+	"\x03cpu\x18\x01 \x01(\tR\x03cpu\x12\x16\n" +
+	"\x06memory\x18\x02 \x01(\tR\x06memory\x12\x12\n" +
+	"\x04pids\x18\x03 \x01(\x03R\x04pids\x12)\n" +
+	"\x10capabilities_add\x18\x04 \x03(\tR\x0fcapabilitiesAdd\x12+\n" +
+	"\x11capabilities_drop\x18\x05 \x03(\tR\x10capabilitiesDrop\x129\n" +
+	"\x19read_only_root_filesystem\x18\x06 \x01(\bR\x16readOnlyRootFilesystem\x12\x1e\n" +
+	"\n" +
+	"privileged\x18\a \x01(\bR\n" +
+	"privileged\"q\n" +
+	"\x0fRemainingAction\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x1b\n" +
+	"\taction_id\x18\x02 \x01(\tR\bactionId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x14\n" +
+	"\x05image\x18\x04 \x01(\tR\x05image\"8\n" +
 	"\n" +
 	"Namespaces\x12\x18\n" +
 	"\anetwork\x18\x01 \x01(\tR\anetwork\x12\x10\n" +
-	"\x03pid\x18\x02 \x01(\tR\x03pid*\x86\x01\n" +
+	"\x03pid\x18\x02 \x01(\tR\x03pid*\\\n" +
+	"\n" +
+	"ActionType\x12\x1b\n" +
+	"\x17ACTION_TYPE_UNSPECIFIED\x10\x00\x12\x19\n" +
+	"\x15ACTION_TYPE_CONTAINER\x10\x01\x12\x16\n" +
+	"\x12ACTION_TYPE_NATIVE\x10\x02*\x86\x01\n" +
 	"\x1cPreconditionFailureViolation\x12.\n" +
 	"*PRECONDITION_FAILURE_VIOLATION_UNSPECIFIED\x10\x00\x126\n" +
 	"2PRECONDITION_FAILURE_VIOLATION_NO_ACTION_AVAILABLE\x10\x01B\x83\x01\n" +
@@ -318,20 +650,26 @@ func file_get_action_response_proto_rawDescGZIP() []byte {
 	return file_get_action_response_proto_rawDescData
 }
 
-var file_get_action_response_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_get_action_response_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_get_action_response_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_get_action_response_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
 var file_get_action_response_proto_goTypes = []any{
-	(PreconditionFailureViolation)(0), // 0: proto.PreconditionFailureViolation
-	(*ActionResponse)(nil),            // 1: proto.ActionResponse
-	(*Namespaces)(nil),                // 2: proto.Namespaces
+	(ActionType)(0),                   // 0: proto.ActionType
+	(PreconditionFailureViolation)(0), // 1: proto.PreconditionFailureViolation
+	(*ActionResponse)(nil),            // 2: proto.ActionResponse
+	(*Sandbox)(nil),                   // 3: proto.Sandbox
+	(*RemainingAction)(nil),           // 4: proto.RemainingAction
+	(*Namespaces)(nil),                // 5: proto.Namespaces
 }
 var file_get_action_response_proto_depIdxs = []int32{
-	2, // 0: proto.ActionResponse.namespaces:type_name -> proto.Namespaces
-	1, // [1:1] is the sub-list for method output_type
-	1, // [1:1] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	5, // 0: proto.ActionResponse.namespaces:type_name -> proto.Namespaces
+	4, // 1: proto.ActionResponse.remaining_actions:type_name -> proto.RemainingAction
+	0, // 2: proto.ActionResponse.type:type_name -> proto.ActionType
+	3, // 3: proto.ActionResponse.sandbox:type_name -> proto.Sandbox
+	4, // [4:4] is the sub-list for method output_type
+	4, // [4:4] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
 }
 
 func init() { file_get_action_response_proto_init() }
@@ -344,8 +682,8 @@ func file_get_action_response_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_get_action_response_proto_rawDesc), len(file_get_action_response_proto_rawDesc)),
-			NumEnums:      1,
-			NumMessages:   2,
+			NumEnums:      2,
+			NumMessages:   4,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
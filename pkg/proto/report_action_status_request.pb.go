@@ -47,6 +47,9 @@ const (
 	// This is the state we all deserve. The execution of the workflow is over
 	// and everything is just fine. Sit down, and enjoy your great work.
 	ActionStatusRequest_SUCCESS ActionStatusRequest_StateType = 5
+	// Canceled is a final state. The workflow was canceled while the action
+	// was running and the action was stopped.
+	ActionStatusRequest_CANCELED ActionStatusRequest_StateType = 6
 )
 
 // Enum value maps for ActionStatusRequest_StateType.
@@ -58,6 +61,7 @@ var (
 		3: "FAILED",
 		4: "TIMEOUT",
 		5: "SUCCESS",
+		6: "CANCELED",
 	}
 	ActionStatusRequest_StateType_value = map[string]int32{
 		"UNSPECIFIED": 0,
@@ -66,6 +70,7 @@ var (
 		"FAILED":      3,
 		"TIMEOUT":     4,
 		"SUCCESS":     5,
+		"CANCELED":    6,
 	}
 )
 
@@ -119,7 +124,13 @@ type ActionStatusRequest struct {
 	// The execution duration time for the action
 	ExecutionDuration *string `protobuf:"bytes,9,opt,name=execution_duration,json=executionDuration" json:"execution_duration,omitempty"`
 	// The message returned from the action.
-	Message       *ActionMessage `protobuf:"bytes,10,opt,name=message" json:"message,omitempty"`
+	Message *ActionMessage `protobuf:"bytes,10,opt,name=message" json:"message,omitempty"`
+	// The result of the on-timeout or on-failure hook, when one ran.
+	Hook *ActionHook `protobuf:"bytes,11,opt,name=hook" json:"hook,omitempty"`
+	// The number of the run of the action this status is for. It starts at 1.
+	Attempt *int64 `protobuf:"varint,12,opt,name=attempt" json:"attempt,omitempty"`
+	// The key/value pairs the action wrote to its output file.
+	Outputs       map[string]string `protobuf:"bytes,13,rep,name=outputs" json:"outputs,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -224,6 +235,27 @@ func (x *ActionStatusRequest) GetMessage() *ActionMessage {
 	return nil
 }
 
+func (x *ActionStatusRequest) GetHook() *ActionHook {
+	if x != nil {
+		return x.Hook
+	}
+	return nil
+}
+
+func (x *ActionStatusRequest) GetAttempt() int64 {
+	if x != nil && x.Attempt != nil {
+		return *x.Attempt
+	}
+	return 0
+}
+
+func (x *ActionStatusRequest) GetOutputs() map[string]string {
+	if x != nil {
+		return x.Outputs
+	}
+	return nil
+}
+
 // ActionMessage to report the status of a single action, it's an object so it can be extended
 type ActionMessage struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
@@ -270,11 +302,75 @@ func (x *ActionMessage) GetMessage() string {
 	return ""
 }
 
+// ActionHook reports the result of running an action's on-timeout or on-failure hook.
+type ActionHook struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Name is the hook that ran, either "on-timeout" or "on-failure".
+	Name *string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	// State is the final state of the hook.
+	State *ActionStatusRequest_StateType `protobuf:"varint,2,opt,name=state,enum=proto.ActionStatusRequest_StateType" json:"state,omitempty"`
+	// Message is the human readable message describing the result of the hook.
+	Message       *string `protobuf:"bytes,3,opt,name=message" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ActionHook) Reset() {
+	*x = ActionHook{}
+	mi := &file_report_action_status_request_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ActionHook) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ActionHook) ProtoMessage() {}
+
+func (x *ActionHook) ProtoReflect() protoreflect.Message {
+	mi := &file_report_action_status_request_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ActionHook.ProtoReflect.Descriptor instead.
+func (*ActionHook) Descriptor() ([]byte, []int) {
+	return file_report_action_status_request_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ActionHook) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *ActionHook) GetState() ActionStatusRequest_StateType {
+	if x != nil && x.State != nil {
+		return *x.State
+	}
+	return ActionStatusRequest_UNSPECIFIED
+}
+
+func (x *ActionHook) GetMessage() string {
+	if x != nil && x.Message != nil {
+		return *x.Message
+	}
+	return ""
+}
+
 var File_report_action_status_request_proto protoreflect.FileDescriptor
 
 const file_report_action_status_request_proto_rawDesc = "" +
 	"\n" +
-	"\"report_action_status_request.proto\x12\x05proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xb6\x04\n" +
+	"\"report_action_status_request.proto\x12\x05proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x84\x06\n" +
 	"\x13ActionStatusRequest\x12\x1f\n" +
 	"\vworkflow_id\x18\x01 \x01(\tR\n" +
 	"workflowId\x12\x19\n" +
@@ -288,7 +384,13 @@ const file_report_action_status_request_proto_rawDesc = "" +
 	"\x0eexecution_stop\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\rexecutionStop\x12-\n" +
 	"\x12execution_duration\x18\t \x01(\tR\x11executionDuration\x12.\n" +
 	"\amessage\x18\n" +
-	" \x01(\v2\x14.proto.ActionMessageR\amessage\"\\\n" +
+	" \x01(\v2\x14.proto.ActionMessageR\amessage\x12%\n" +
+	"\x04hook\x18\v \x01(\v2\x11.proto.ActionHookR\x04hook\x12\x18\n" +
+	"\aattempt\x18\f \x01(\x03R\aattempt\x12A\n" +
+	"\aoutputs\x18\r \x03(\v2'.proto.ActionStatusRequest.OutputsEntryR\aoutputs\x1a:\n" +
+	"\fOutputsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"j\n" +
 	"\tStateType\x12\x0f\n" +
 	"\vUNSPECIFIED\x10\x00\x12\v\n" +
 	"\aPENDING\x10\x01\x12\v\n" +
@@ -296,9 +398,15 @@ const file_report_action_status_request_proto_rawDesc = "" +
 	"\n" +
 	"\x06FAILED\x10\x03\x12\v\n" +
 	"\aTIMEOUT\x10\x04\x12\v\n" +
-	"\aSUCCESS\x10\x05\")\n" +
+	"\aSUCCESS\x10\x05\x12\f\n" +
+	"\bCANCELED\x10\x06\")\n" +
 	"\rActionMessage\x12\x18\n" +
-	"\amessage\x18\x01 \x01(\tR\amessageB\x8b\x01\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\"v\n" +
+	"\n" +
+	"ActionHook\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12:\n" +
+	"\x05state\x18\x02 \x01(\x0e2$.proto.ActionStatusRequest.StateTypeR\x05state\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessageB\x8b\x01\n" +
 	"\tcom.protoB\x1eReportActionStatusRequestProtoP\x01Z*github.com/tinkerbell/tinkerbell/pkg/proto\xa2\x02\x03PXX\xaa\x02\x05Proto\xca\x02\x05Proto\xe2\x02\x11Proto\\GPBMetadata\xea\x02\x05Protob\beditionsp\xe8\a"
 
 var (
@@ -314,23 +422,28 @@ func file_report_action_status_request_proto_rawDescGZIP() []byte {
 }
 
 var file_report_action_status_request_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_report_action_status_request_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_report_action_status_request_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
 var file_report_action_status_request_proto_goTypes = []any{
 	(ActionStatusRequest_StateType)(0), // 0: proto.ActionStatusRequest.StateType
 	(*ActionStatusRequest)(nil),        // 1: proto.ActionStatusRequest
 	(*ActionMessage)(nil),              // 2: proto.ActionMessage
-	(*timestamppb.Timestamp)(nil),      // 3: google.protobuf.Timestamp
+	(*ActionHook)(nil),                 // 3: proto.ActionHook
+	nil,                                // 4: proto.ActionStatusRequest.OutputsEntry
+	(*timestamppb.Timestamp)(nil),      // 5: google.protobuf.Timestamp
 }
 var file_report_action_status_request_proto_depIdxs = []int32{
 	0, // 0: proto.ActionStatusRequest.action_state:type_name -> proto.ActionStatusRequest.StateType
-	3, // 1: proto.ActionStatusRequest.execution_start:type_name -> google.protobuf.Timestamp
-	3, // 2: proto.ActionStatusRequest.execution_stop:type_name -> google.protobuf.Timestamp
+	5, // 1: proto.ActionStatusRequest.execution_start:type_name -> google.protobuf.Timestamp
+	5, // 2: proto.ActionStatusRequest.execution_stop:type_name -> google.protobuf.Timestamp
 	2, // 3: proto.ActionStatusRequest.message:type_name -> proto.ActionMessage
-	4, // [4:4] is the sub-list for method output_type
-	4, // [4:4] is the sub-list for method input_type
-	4, // [4:4] is the sub-list for extension type_name
-	4, // [4:4] is the sub-list for extension extendee
-	0, // [0:4] is the sub-list for field type_name
+	3, // 4: proto.ActionStatusRequest.hook:type_name -> proto.ActionHook
+	4, // 5: proto.ActionStatusRequest.outputs:type_name -> proto.ActionStatusRequest.OutputsEntry
+	0, // 6: proto.ActionHook.state:type_name -> proto.ActionStatusRequest.StateType
+	7, // [7:7] is the sub-list for method output_type
+	7, // [7:7] is the sub-list for method input_type
+	7, // [7:7] is the sub-list for extension type_name
+	7, // [7:7] is the sub-list for extension extendee
+	0, // [0:7] is the sub-list for field type_name
 }
 
 func init() { file_report_action_status_request_proto_init() }
@@ -344,7 +457,7 @@ func file_report_action_status_request_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_report_action_status_request_proto_rawDesc), len(file_report_action_status_request_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   2,
+			NumMessages:   4,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
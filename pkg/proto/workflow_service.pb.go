@@ -25,25 +25,40 @@ var File_workflow_service_proto protoreflect.FileDescriptor
 
 const file_workflow_service_proto_rawDesc = "" +
 	"\n" +
-	"\x16workflow_service.proto\x12\x05proto\x1a\x18get_action_request.proto\x1a\x19get_action_response.proto\x1a\"report_action_status_request.proto\x1a#report_action_status_response.proto2\x9e\x01\n" +
+	"\x16workflow_service.proto\x12\x05proto\x1a\x18get_action_request.proto\x1a\x19get_action_response.proto\x1a\"report_action_status_request.proto\x1a#report_action_status_response.proto\x1a\x1acheck_action_request.proto\x1a\x1bcheck_action_response.proto\x1a\x19action_logs_request.proto\x1a\x1aaction_logs_response.proto\x1a\x15connect_request.proto\x1a\x16connect_response.proto2\xf3\x02\n" +
 	"\x0fWorkflowService\x12:\n" +
 	"\tGetAction\x12\x14.proto.ActionRequest\x1a\x15.proto.ActionResponse\"\x00\x12O\n" +
-	"\x12ReportActionStatus\x12\x1a.proto.ActionStatusRequest\x1a\x1b.proto.ActionStatusResponse\"\x00B\x81\x01\n" +
+	"\x12ReportActionStatus\x12\x1a.proto.ActionStatusRequest\x1a\x1b.proto.ActionStatusResponse\"\x00\x12F\n" +
+	"\vCheckAction\x12\x19.proto.CheckActionRequest\x1a\x1a.proto.CheckActionResponse\"\x00\x12K\n" +
+	"\x10StreamActionLogs\x12\x18.proto.ActionLogsRequest\x1a\x19.proto.ActionLogsResponse\"\x00(\x01\x12>\n" +
+	"\aConnect\x12\x15.proto.ConnectRequest\x1a\x16.proto.ConnectResponse\"\x00(\x010\x01B\x81\x01\n" +
 	"\tcom.protoB\x14WorkflowServiceProtoP\x01Z*github.com/tinkerbell/tinkerbell/pkg/proto\xa2\x02\x03PXX\xaa\x02\x05Proto\xca\x02\x05Proto\xe2\x02\x11Proto\\GPBMetadata\xea\x02\x05Protob\beditionsp\xe8\a"
 
 var file_workflow_service_proto_goTypes = []any{
 	(*ActionRequest)(nil),        // 0: proto.ActionRequest
 	(*ActionStatusRequest)(nil),  // 1: proto.ActionStatusRequest
-	(*ActionResponse)(nil),       // 2: proto.ActionResponse
-	(*ActionStatusResponse)(nil), // 3: proto.ActionStatusResponse
+	(*CheckActionRequest)(nil),   // 2: proto.CheckActionRequest
+	(*ActionLogsRequest)(nil),    // 3: proto.ActionLogsRequest
+	(*ConnectRequest)(nil),       // 4: proto.ConnectRequest
+	(*ActionResponse)(nil),       // 5: proto.ActionResponse
+	(*ActionStatusResponse)(nil), // 6: proto.ActionStatusResponse
+	(*CheckActionResponse)(nil),  // 7: proto.CheckActionResponse
+	(*ActionLogsResponse)(nil),   // 8: proto.ActionLogsResponse
+	(*ConnectResponse)(nil),      // 9: proto.ConnectResponse
 }
 var file_workflow_service_proto_depIdxs = []int32{
 	0, // 0: proto.WorkflowService.GetAction:input_type -> proto.ActionRequest
 	1, // 1: proto.WorkflowService.ReportActionStatus:input_type -> proto.ActionStatusRequest
-	2, // 2: proto.WorkflowService.GetAction:output_type -> proto.ActionResponse
-	3, // 3: proto.WorkflowService.ReportActionStatus:output_type -> proto.ActionStatusResponse
-	2, // [2:4] is the sub-list for method output_type
-	0, // [0:2] is the sub-list for method input_type
+	2, // 2: proto.WorkflowService.CheckAction:input_type -> proto.CheckActionRequest
+	3, // 3: proto.WorkflowService.StreamActionLogs:input_type -> proto.ActionLogsRequest
+	4, // 4: proto.WorkflowService.Connect:input_type -> proto.ConnectRequest
+	5, // 5: proto.WorkflowService.GetAction:output_type -> proto.ActionResponse
+	6, // 6: proto.WorkflowService.ReportActionStatus:output_type -> proto.ActionStatusResponse
+	7, // 7: proto.WorkflowService.CheckAction:output_type -> proto.CheckActionResponse
+	8, // 8: proto.WorkflowService.StreamActionLogs:output_type -> proto.ActionLogsResponse
+	9, // 9: proto.WorkflowService.Connect:output_type -> proto.ConnectResponse
+	5, // [5:10] is the sub-list for method output_type
+	0, // [0:5] is the sub-list for method input_type
 	0, // [0:0] is the sub-list for extension type_name
 	0, // [0:0] is the sub-list for extension extendee
 	0, // [0:0] is the sub-list for field type_name
@@ -58,6 +73,12 @@ func file_workflow_service_proto_init() {
 	file_get_action_response_proto_init()
 	file_report_action_status_request_proto_init()
 	file_report_action_status_response_proto_init()
+	file_check_action_request_proto_init()
+	file_check_action_response_proto_init()
+	file_action_logs_request_proto_init()
+	file_action_logs_response_proto_init()
+	file_connect_request_proto_init()
+	file_connect_response_proto_init()
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
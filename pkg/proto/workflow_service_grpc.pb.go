@@ -22,6 +22,9 @@ const _ = grpc.SupportPackageIsVersion9
 const (
 	WorkflowService_GetAction_FullMethodName          = "/proto.WorkflowService/GetAction"
 	WorkflowService_ReportActionStatus_FullMethodName = "/proto.WorkflowService/ReportActionStatus"
+	WorkflowService_CheckAction_FullMethodName        = "/proto.WorkflowService/CheckAction"
+	WorkflowService_StreamActionLogs_FullMethodName   = "/proto.WorkflowService/StreamActionLogs"
+	WorkflowService_Connect_FullMethodName            = "/proto.WorkflowService/Connect"
 )
 
 // WorkflowServiceClient is the client API for WorkflowService service.
@@ -32,6 +35,11 @@ const (
 type WorkflowServiceClient interface {
 	GetAction(ctx context.Context, in *ActionRequest, opts ...grpc.CallOption) (*ActionResponse, error)
 	ReportActionStatus(ctx context.Context, in *ActionStatusRequest, opts ...grpc.CallOption) (*ActionStatusResponse, error)
+	CheckAction(ctx context.Context, in *CheckActionRequest, opts ...grpc.CallOption) (*CheckActionResponse, error)
+	StreamActionLogs(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ActionLogsRequest, ActionLogsResponse], error)
+	// Connect is a long-lived stream over which the server pushes Actions to an
+	// Agent as soon as they are runnable, instead of the Agent polling GetAction.
+	Connect(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ConnectRequest, ConnectResponse], error)
 }
 
 type workflowServiceClient struct {
@@ -62,6 +70,42 @@ func (c *workflowServiceClient) ReportActionStatus(ctx context.Context, in *Acti
 	return out, nil
 }
 
+func (c *workflowServiceClient) CheckAction(ctx context.Context, in *CheckActionRequest, opts ...grpc.CallOption) (*CheckActionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CheckActionResponse)
+	err := c.cc.Invoke(ctx, WorkflowService_CheckAction_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowServiceClient) StreamActionLogs(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ActionLogsRequest, ActionLogsResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &WorkflowService_ServiceDesc.Streams[0], WorkflowService_StreamActionLogs_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ActionLogsRequest, ActionLogsResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type WorkflowService_StreamActionLogsClient = grpc.ClientStreamingClient[ActionLogsRequest, ActionLogsResponse]
+
+func (c *workflowServiceClient) Connect(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ConnectRequest, ConnectResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &WorkflowService_ServiceDesc.Streams[1], WorkflowService_Connect_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ConnectRequest, ConnectResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type WorkflowService_ConnectClient = grpc.BidiStreamingClient[ConnectRequest, ConnectResponse]
+
 // WorkflowServiceServer is the server API for WorkflowService service.
 // All implementations must embed UnimplementedWorkflowServiceServer
 // for forward compatibility.
@@ -70,6 +114,11 @@ func (c *workflowServiceClient) ReportActionStatus(ctx context.Context, in *Acti
 type WorkflowServiceServer interface {
 	GetAction(context.Context, *ActionRequest) (*ActionResponse, error)
 	ReportActionStatus(context.Context, *ActionStatusRequest) (*ActionStatusResponse, error)
+	CheckAction(context.Context, *CheckActionRequest) (*CheckActionResponse, error)
+	StreamActionLogs(grpc.ClientStreamingServer[ActionLogsRequest, ActionLogsResponse]) error
+	// Connect is a long-lived stream over which the server pushes Actions to an
+	// Agent as soon as they are runnable, instead of the Agent polling GetAction.
+	Connect(grpc.BidiStreamingServer[ConnectRequest, ConnectResponse]) error
 	mustEmbedUnimplementedWorkflowServiceServer()
 }
 
@@ -86,6 +135,15 @@ func (UnimplementedWorkflowServiceServer) GetAction(context.Context, *ActionRequ
 func (UnimplementedWorkflowServiceServer) ReportActionStatus(context.Context, *ActionStatusRequest) (*ActionStatusResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ReportActionStatus not implemented")
 }
+func (UnimplementedWorkflowServiceServer) CheckAction(context.Context, *CheckActionRequest) (*CheckActionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckAction not implemented")
+}
+func (UnimplementedWorkflowServiceServer) StreamActionLogs(grpc.ClientStreamingServer[ActionLogsRequest, ActionLogsResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamActionLogs not implemented")
+}
+func (UnimplementedWorkflowServiceServer) Connect(grpc.BidiStreamingServer[ConnectRequest, ConnectResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method Connect not implemented")
+}
 func (UnimplementedWorkflowServiceServer) mustEmbedUnimplementedWorkflowServiceServer() {}
 func (UnimplementedWorkflowServiceServer) testEmbeddedByValue()                         {}
 
@@ -143,6 +201,38 @@ func _WorkflowService_ReportActionStatus_Handler(srv interface{}, ctx context.Co
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WorkflowService_CheckAction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckActionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowServiceServer).CheckAction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WorkflowService_CheckAction_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowServiceServer).CheckAction(ctx, req.(*CheckActionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowService_StreamActionLogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(WorkflowServiceServer).StreamActionLogs(&grpc.GenericServerStream[ActionLogsRequest, ActionLogsResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type WorkflowService_StreamActionLogsServer = grpc.ClientStreamingServer[ActionLogsRequest, ActionLogsResponse]
+
+func _WorkflowService_Connect_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(WorkflowServiceServer).Connect(&grpc.GenericServerStream[ConnectRequest, ConnectResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type WorkflowService_ConnectServer = grpc.BidiStreamingServer[ConnectRequest, ConnectResponse]
+
 // WorkflowService_ServiceDesc is the grpc.ServiceDesc for WorkflowService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -158,7 +248,23 @@ var WorkflowService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ReportActionStatus",
 			Handler:    _WorkflowService_ReportActionStatus_Handler,
 		},
+		{
+			MethodName: "CheckAction",
+			Handler:    _WorkflowService_CheckAction_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamActionLogs",
+			Handler:       _WorkflowService_StreamActionLogs_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "Connect",
+			Handler:       _WorkflowService_Connect_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "workflow_service.proto",
 }
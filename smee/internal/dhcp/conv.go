@@ -39,7 +39,7 @@ func ConvertByMac(ctx context.Context, mac net.HardwareAddr, hw *v1alpha1.Hardwa
 		return Hardware{}, err
 	}
 
-	result := Hardware{DHCP: d, Netboot: n, AgentID: hw.Spec.AgentID}
+	result := Hardware{DHCP: d, Netboot: n, AgentID: hw.Spec.AgentID, SwitchPort: i.SwitchPort != nil}
 
 	if i.Isoboot != nil && i.Isoboot.SourceISO != "" {
 		si, err := url.Parse(i.Isoboot.SourceISO)
@@ -65,9 +65,14 @@ func ConvertByIP(ctx context.Context, ip net.IP, hw *v1alpha1.Hardware) (Hardwar
 	if hw == nil {
 		return Hardware{}, errors.New("hardware is nil")
 	}
+	// Compare parsed addresses, IPv6 addresses have more than one valid text representation.
+	want, _ := netip.AddrFromSlice(ip)
 	i := v1alpha1.Interface{}
 	for _, iface := range hw.Spec.Interfaces {
-		if iface.DHCP != nil && iface.DHCP.IP != nil && iface.DHCP.IP.Address == ip.String() {
+		if iface.DHCP == nil || iface.DHCP.IP == nil {
+			continue
+		}
+		if addr, err := netip.ParseAddr(iface.DHCP.IP.Address); err == nil && addr.Unmap() == want.Unmap() {
 			i = iface
 			break
 		}
@@ -80,7 +85,53 @@ func ConvertByIP(ctx context.Context, ip net.IP, hw *v1alpha1.Hardware) (Hardwar
 		return Hardware{}, err
 	}
 
-	result := Hardware{DHCP: d, Netboot: n, AgentID: hw.Spec.AgentID}
+	result := Hardware{DHCP: d, Netboot: n, AgentID: hw.Spec.AgentID, SwitchPort: i.SwitchPort != nil}
+
+	if i.Isoboot != nil && i.Isoboot.SourceISO != "" {
+		si, err := url.Parse(i.Isoboot.SourceISO)
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+
+			return Hardware{}, fmt.Errorf("failed to parse source ISO as a URL %q: %w", i.Isoboot.SourceISO, err)
+		}
+		result.Isoboot = &Isoboot{SourceISO: si}
+	}
+
+	span.SetAttributes(d.EncodeToAttributes()...)
+	span.SetAttributes(n.EncodeToAttributes()...)
+	span.SetStatus(codes.Ok, "")
+
+	return result, nil
+}
+
+// ConvertBySwitchPort converts the interface of the Hardware that is connected to the switch port.
+// The MAC address of the interface is replaced with mac, the address of the client that sent the DHCP message.
+func ConvertBySwitchPort(ctx context.Context, mac net.HardwareAddr, sp SwitchPort, hw *v1alpha1.Hardware) (Hardware, error) {
+	tracer := otel.Tracer(tracerName)
+	_, span := tracer.Start(ctx, "smee.internal.data.ConvertBySwitchPort")
+	defer span.End()
+	if hw == nil {
+		return Hardware{}, errors.New("hardware is nil")
+	}
+	i := v1alpha1.Interface{}
+	for _, iface := range hw.Spec.Interfaces {
+		if sp.Matches(iface) {
+			i = *iface.DeepCopy()
+			break
+		}
+	}
+	if i.DHCP != nil {
+		i.DHCP.MAC = mac.String()
+	}
+
+	d, n, err := transform(i, hw.Spec.Metadata)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+
+		return Hardware{}, err
+	}
+
+	result := Hardware{DHCP: d, Netboot: n, AgentID: hw.Spec.AgentID, SwitchPort: i.SwitchPort != nil}
 
 	if i.Isoboot != nil && i.Isoboot.SourceISO != "" {
 		si, err := url.Parse(i.Isoboot.SourceISO)
@@ -132,7 +183,16 @@ func toDHCPData(h *v1alpha1.DHCP) (*DHCP, error) {
 			}
 			d.SubnetMask = net.IPMask(sm4)
 		} else if h.IP.Netmask != "" {
-			return nil, errors.New("netmask is not applicable for IPv6 addresses")
+			// DHCPv6 does not send a netmask, the on-link prefix comes from router advertisements.
+			// A netmask is still accepted so that the Hardware object can describe the prefix.
+			sm := net.ParseIP(h.IP.Netmask)
+			if sm == nil || sm.To4() != nil {
+				return nil, errors.New("netmask must be an IPv6 address for IPv6 addresses")
+			}
+			if _, bits := net.IPMask(sm).Size(); bits == 0 {
+				return nil, errors.New("netmask is not a valid IPv6 netmask")
+			}
+			d.SubnetMask = net.IPMask(sm)
 		}
 	}
 
@@ -287,6 +347,13 @@ func toNetbootData(i *v1alpha1.Netboot, facility string) (*Netboot, error) {
 		n.RPI.ConfigTxt = i.RPI.ConfigTxt
 	}
 
+	// HTTPBoot (UEFI HTTP Boot without iPXE) data
+	n.HTTPBoot = HTTPBoot{}
+	if i.HTTPBoot != nil {
+		n.HTTPBoot.Mode = string(i.HTTPBoot.Mode)
+		n.HTTPBoot.BootFile = i.HTTPBoot.BootFile
+	}
+
 	return n, nil
 }
 
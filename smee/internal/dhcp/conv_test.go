@@ -294,6 +294,55 @@ func TestConvertByMac(t *testing.T) {
 			},
 			shouldErr: true,
 		},
+		"ipv6 address with ipv6 netmask": {
+			mac: net.HardwareAddr{0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff},
+			hw: &tinkerbell.Hardware{
+				Spec: tinkerbell.HardwareSpec{
+					Interfaces: []tinkerbell.Interface{
+						{
+							DHCP: &tinkerbell.DHCP{
+								MAC: "aa:bb:cc:dd:ee:ff",
+								IP: &tinkerbell.IP{
+									Address: "2001:db8::1",
+									Netmask: "ffff:ffff:ffff:ffff::",
+								},
+							},
+							Netboot: &tinkerbell.Netboot{AllowPXE: boolPtr(true)},
+						},
+					},
+				},
+			},
+			want: Hardware{
+				DHCP: &DHCP{
+					MACAddress: net.HardwareAddr{0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff},
+					IPAddress:  netip.MustParseAddr("2001:db8::1"),
+					SubnetMask: net.CIDRMask(64, 128),
+				},
+				Netboot: &Netboot{
+					AllowNetboot: true,
+				},
+			},
+		},
+		"ipv6 address with non contiguous netmask errors": {
+			mac: net.HardwareAddr{0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff},
+			hw: &tinkerbell.Hardware{
+				Spec: tinkerbell.HardwareSpec{
+					Interfaces: []tinkerbell.Interface{
+						{
+							DHCP: &tinkerbell.DHCP{
+								MAC: "aa:bb:cc:dd:ee:ff",
+								IP: &tinkerbell.IP{
+									Address: "2001:db8::1",
+									Netmask: "ffff::ffff",
+								},
+							},
+							Netboot: &tinkerbell.Netboot{AllowPXE: boolPtr(true)},
+						},
+					},
+				},
+			},
+			shouldErr: true,
+		},
 		"ipv6 gateway with ipv6 ip": {
 			mac: net.HardwareAddr{0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff},
 			hw: &tinkerbell.Hardware{
@@ -472,6 +521,35 @@ func TestConvertByIP(t *testing.T) {
 				},
 			},
 		},
+		"matching ipv6 in another notation": {
+			ip: net.ParseIP("2001:db8::a"),
+			hw: &tinkerbell.Hardware{
+				Spec: tinkerbell.HardwareSpec{
+					Interfaces: []tinkerbell.Interface{
+						{
+							DHCP: &tinkerbell.DHCP{
+								MAC:      "aa:bb:cc:dd:ee:ff",
+								Hostname: "by-ipv6",
+								IP: &tinkerbell.IP{
+									Address: "2001:0db8:0000:0000:0000:0000:0000:000A",
+								},
+							},
+							Netboot: &tinkerbell.Netboot{AllowPXE: boolPtr(true)},
+						},
+					},
+				},
+			},
+			want: Hardware{
+				DHCP: &DHCP{
+					MACAddress: net.HardwareAddr{0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff},
+					IPAddress:  netip.MustParseAddr("2001:db8::a"),
+					Hostname:   "by-ipv6",
+				},
+				Netboot: &Netboot{
+					AllowNetboot: true,
+				},
+			},
+		},
 		"with agent id": {
 			ip: net.ParseIP("192.168.1.10"),
 			hw: &tinkerbell.Hardware{
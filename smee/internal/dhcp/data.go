@@ -21,6 +21,8 @@ type Hardware struct {
 	Netboot *Netboot
 	// Isoboot holds info used in booting a client using an ISO image.
 	Isoboot *Isoboot
+	// SwitchPort is true when the matched interface declares the switch port it is connected to.
+	SwitchPort bool
 }
 
 // DHCP holds the DHCP headers and options to be set in a DHCP handler response.
@@ -56,6 +58,7 @@ type Netboot struct {
 	OSIE          OSIE
 	PXELINUX      PXELINUX
 	RPI           RPI
+	HTTPBoot      HTTPBoot
 }
 
 // Isoboot holds info used in booting a client using an ISO image.
@@ -78,6 +81,14 @@ type RPI struct {
 	ConfigTxt    string `json:"configTxt,omitempty"`
 }
 
+// HTTPBoot holds the data used to boot UEFI HTTP Boot clients without iPXE.
+type HTTPBoot struct {
+	// Mode is the type of boot file, HTTPBootModeGRUB or HTTPBootModeUKI. UEFI HTTP Boot clients boot iPXE when it is empty.
+	Mode string
+	// BootFile overrides the default boot file of the mode and architecture.
+	BootFile string
+}
+
 // OSIE or OS Installation Environment is the data about where the OSIE parts are located.
 type OSIE struct {
 	// BaseURL is the URL where the OSIE parts are located.
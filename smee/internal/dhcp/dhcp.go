@@ -337,15 +337,7 @@ func (i Info) Bootfile(customUC UserClass, ipxeScript, ipxeHTTPBinServer *url.UR
 			bootfile = ipxeHTTPBinServer.JoinPath(paths...).String()
 		}
 	case i.UserClass == IPXE: // if the "iPXE" user class is found it means we aren't in our custom version of ipxe, but because of the option 43 we're setting we need to give a full tftp url from which to boot.
-		t := url.URL{
-			Scheme: "tftp",
-			Host:   ipxeTFTPBinServer.String(),
-		}
-		paths := []string{i.IPXEBinary}
-		if i.Mac != nil {
-			paths = append([]string{macAddrFormat(i.Mac, i.MacAddrFormat)}, paths...)
-		}
-		bootfile = t.JoinPath(paths...).String()
+		bootfile = i.tftpBinaryURL(ipxeTFTPBinServer)
 	default:
 		if i.IPXEBinary != "" {
 			bootfile = i.IPXEBinary
@@ -355,6 +347,20 @@ func (i Info) Bootfile(customUC UserClass, ipxeScript, ipxeHTTPBinServer *url.UR
 	return bootfile
 }
 
+// tftpBinaryURL returns the full TFTP URL of the iPXE binary on the TFTP server.
+func (i Info) tftpBinaryURL(ipxeTFTPBinServer netip.AddrPort) string {
+	t := url.URL{
+		Scheme: "tftp",
+		Host:   ipxeTFTPBinServer.String(),
+	}
+	paths := []string{i.IPXEBinary}
+	if i.Mac != nil {
+		paths = append([]string{macAddrFormat(i.Mac, i.MacAddrFormat)}, paths...)
+	}
+
+	return t.JoinPath(paths...).String()
+}
+
 func macAddrFormat(mac net.HardwareAddr, f constant.MACFormat) string {
 	switch f {
 	case constant.MacAddrFormatColon:
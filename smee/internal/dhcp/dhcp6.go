@@ -0,0 +1,175 @@
+package dhcp
+
+import (
+	"errors"
+	"net"
+	"net/netip"
+	"net/url"
+	"strings"
+
+	"github.com/insomniacslk/dhcp/dhcpv6"
+	"github.com/insomniacslk/dhcp/iana"
+)
+
+// PXEEnterpriseNumber is the IANA enterprise number that PXE and UEFI HTTP boot clients send
+// their DHCPv6 vendor class (option 16) with. See https://www.rfc-editor.org/rfc/rfc5970#section-3.3 .
+const PXEEnterpriseNumber = 343
+
+// Packet6 holds the data that is passed to a DHCPv6 handler.
+type Packet6 struct {
+	// Peer is the address of the client or relay agent that sent the DHCPv6 message.
+	Peer net.Addr
+	// Pkt is the DHCPv6 message. It is either a client message or a Relay-Forward message wrapping one.
+	Pkt dhcpv6.DHCPv6
+	// Md is the metadata that was passed to the DHCPv6 server.
+	Md *Metadata
+}
+
+// Message returns the client message of the packet, unwrapping any Relay-Forward messages.
+func (p Packet6) Message() (*dhcpv6.Message, error) {
+	if p.Pkt == nil {
+		return nil, errors.New("packet is nil")
+	}
+
+	return p.Pkt.GetInnerMessage()
+}
+
+// ClientMAC returns the MAC address of the client that sent the packet.
+// DHCPv6 has no client hardware address field, so the MAC address is taken from, in order:
+// the EUI-64 link-local address of a directly connected client, the Client Link-Layer Address option (79)
+// or the EUI-64 peer address of the innermost relay, and the DUID-LL or DUID-LLT of the client.
+func (p Packet6) ClientMAC() (net.HardwareAddr, error) {
+	if p.Pkt == nil {
+		return nil, errors.New("packet is nil")
+	}
+	if u, ok := p.Peer.(*net.UDPAddr); ok && !p.Pkt.IsRelay() && u.IP.IsLinkLocalUnicast() {
+		if mac, err := dhcpv6.GetMacAddressFromEUI64(u.IP); err == nil {
+			return mac, nil
+		}
+	}
+
+	return dhcpv6.ExtractMAC(p.Pkt)
+}
+
+// NewInfo6 returns the details of a DHCPv6 client message from the client with the MAC address.
+// The Pkt field is not set, it only holds DHCPv4 packets.
+func NewInfo6(msg *dhcpv6.Message, mac net.HardwareAddr, opts ...InfoOption) Info {
+	i := Info{Mac: mac}
+	for _, opt := range opts {
+		opt(&i)
+	}
+	if msg != nil {
+		i.Arch = Arch6(msg, mac)
+		i.UserClass = userClass6(msg)
+		i.ClientType = clientType6(msg)
+		i.IsNetbootClient = IsNetbootClient6(msg)
+		if i.IPXEBinary == "" {
+			i.IPXEBinary = i.IPXEBinaryFrom()
+		}
+	}
+
+	return i
+}
+
+// Arch6 returns the Arch of the client pulled from DHCPv6 option 61.
+func Arch6(msg *dhcpv6.Message, mac net.HardwareAddr) iana.Arch {
+	// See Arch for why Raspberry PI's are matched by their mac address.
+	if isRaspberryPI(mac) {
+		return iana.Arch(41)
+	}
+	for _, a := range msg.Options.ArchTypes() {
+		if !strings.Contains(a.String(), "unknown") {
+			return a
+		}
+	}
+
+	return iana.Arch(255) // unknown arch
+}
+
+// userClass6 returns the first user class of DHCPv6 option 15.
+func userClass6(msg *dhcpv6.Message) UserClass {
+	if uc := msg.Options.UserClasses(); len(uc) > 0 {
+		return UserClass(uc[0])
+	}
+
+	return ""
+}
+
+// clientType6 returns the client type from the PXE vendor class of DHCPv6 option 16.
+func clientType6(msg *dhcpv6.Message) ClientType {
+	vc := msg.Options.VendorClass(PXEEnterpriseNumber)
+	if len(vc) == 0 {
+		return ""
+	}
+	for _, v := range vc {
+		if strings.HasPrefix(string(v), HTTPClient.String()) {
+			return HTTPClient
+		}
+	}
+
+	return PXEClient
+}
+
+// IsNetbootClient6 returns nil if the client is a valid DHCPv6 netboot client. Otherwise it returns an error.
+//
+// A valid netboot client will have the following in its DHCPv6 message:
+// 1. is a Solicit, Request or Information-Request message type.
+// 2. option 61 is set.
+// 3. option 16 is set with the PXE enterprise number (343) and this format: "PXEClient:Arch:xxxxx:UNDI:yyyzzz" or "HTTPClient:Arch:xxxxx:UNDI:yyyzzz".
+//
+// See: https://www.rfc-editor.org/rfc/rfc5970.html
+func IsNetbootClient6(msg *dhcpv6.Message) error {
+	var err error
+	switch msg.Type() {
+	case dhcpv6.MessageTypeSolicit, dhcpv6.MessageTypeRequest, dhcpv6.MessageTypeInformationRequest:
+	default:
+		err = wrapNonNil(err, "message type must be either Solicit, Request or Information-Request")
+	}
+	if msg.GetOneOption(dhcpv6.OptionClientArchType) == nil {
+		err = wrapNonNil(err, "option 61 not set")
+	}
+	vc := msg.Options.VendorClass(PXEEnterpriseNumber)
+	if len(vc) == 0 {
+		return wrapNonNil(err, "option 16 not set")
+	}
+	var found bool
+	for _, v := range vc {
+		if strings.HasPrefix(string(v), PXEClient.String()) || strings.HasPrefix(string(v), HTTPClient.String()) {
+			found = true
+			break
+		}
+	}
+	if !found {
+		err = wrapNonNil(err, "option 16 not PXEClient or HTTPClient")
+	}
+
+	return err
+}
+
+// BootfileURL returns the calculated DHCPv6 Boot File URL (option 59). see https://www.rfc-editor.org/rfc/rfc5970#section-3.1 .
+// DHCPv6 has no next server, so where Bootfile would return only the iPXE binary name, the full TFTP URL is returned.
+func (i Info) BootfileURL(customUC UserClass, ipxeScript, ipxeHTTPBinServer *url.URL, ipxeTFTPBinServer netip.AddrPort) string {
+	bootfile := i.Bootfile(customUC, ipxeScript, ipxeHTTPBinServer, ipxeTFTPBinServer)
+	if i.IPXEBinary == "" || bootfile != i.IPXEBinary {
+		return bootfile
+	}
+
+	return i.tftpBinaryURL(ipxeTFTPBinServer)
+}
+
+// Reply returns the DHCPv6 message to send back to the peer for the reply to the client message of the packet.
+// When the packet was relayed, the reply is wrapped in the matching Relay-Reply messages.
+func (p Packet6) Reply(reply *dhcpv6.Message) (dhcpv6.DHCPv6, error) {
+	if p.Pkt == nil {
+		return nil, errors.New("packet is nil")
+	}
+	if !p.Pkt.IsRelay() {
+		return reply, nil
+	}
+	relay, ok := p.Pkt.(*dhcpv6.RelayMessage)
+	if !ok {
+		return nil, errors.New("relayed packet is not a relay message")
+	}
+
+	return dhcpv6.NewRelayReplFromRelayForw(relay, reply)
+}
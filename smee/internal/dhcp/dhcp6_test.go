@@ -0,0 +1,244 @@
+package dhcp
+
+import (
+	"net"
+	"net/netip"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/insomniacslk/dhcp/dhcpv6"
+	"github.com/insomniacslk/dhcp/iana"
+	"github.com/tinkerbell/tinkerbell/pkg/constant"
+)
+
+// message6 returns a DHCPv6 client message of the type with the options.
+func message6(mt dhcpv6.MessageType, opts ...dhcpv6.Option) *dhcpv6.Message {
+	m := &dhcpv6.Message{MessageType: mt, TransactionID: dhcpv6.TransactionID{0x01, 0x02, 0x03}}
+	for _, o := range opts {
+		m.AddOption(o)
+	}
+
+	return m
+}
+
+func vendorClass6(data string) dhcpv6.Option {
+	return &dhcpv6.OptVendorClass{EnterpriseNumber: PXEEnterpriseNumber, Data: [][]byte{[]byte(data)}}
+}
+
+func TestNewInfo6(t *testing.T) {
+	mac := net.HardwareAddr{0xde, 0xed, 0xbe, 0xef, 0xfe, 0xed}
+	tests := map[string]struct {
+		msg  *dhcpv6.Message
+		opts []InfoOption
+		want Info
+	}{
+		"http client": {
+			msg: message6(dhcpv6.MessageTypeSolicit,
+				dhcpv6.OptClientArchType(iana.EFI_X86_64_HTTP),
+				vendorClass6(exampleHTTPClient),
+			),
+			want: Info{Mac: mac, Arch: iana.EFI_X86_64_HTTP, ClientType: HTTPClient, IPXEBinary: "ipxe.efi"},
+		},
+		"pxe client with ipxe user class": {
+			msg: message6(dhcpv6.MessageTypeSolicit,
+				dhcpv6.OptClientArchType(iana.EFI_X86_64),
+				vendorClass6(examplePXEClient),
+				&dhcpv6.OptUserClass{UserClasses: [][]byte{[]byte(IPXE)}},
+			),
+			want: Info{Mac: mac, Arch: iana.EFI_X86_64, ClientType: PXEClient, UserClass: IPXE, IPXEBinary: "ipxe.efi"},
+		},
+		"arch mapping override": {
+			msg: message6(dhcpv6.MessageTypeRequest,
+				dhcpv6.OptClientArchType(iana.EFI_X86_64),
+				vendorClass6(examplePXEClient),
+			),
+			opts: []InfoOption{WithArchMappingOverride(map[iana.Arch]constant.IPXEBinary{iana.EFI_X86_64: "custom.efi"})},
+			want: Info{Mac: mac, Arch: iana.EFI_X86_64, ClientType: PXEClient, IPXEBinary: "custom.efi", ArchMappingOverride: map[iana.Arch]constant.IPXEBinary{iana.EFI_X86_64: "custom.efi"}},
+		},
+		"not a netboot client": {
+			msg:  message6(dhcpv6.MessageTypeSolicit),
+			want: Info{Mac: mac, Arch: iana.Arch(255)},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := NewInfo6(tt.msg, mac, tt.opts...)
+			if (got.IsNetbootClient == nil) != (tt.want.ClientType != "") {
+				t.Errorf("NewInfo6() IsNetbootClient = %v", got.IsNetbootClient)
+			}
+			got.IsNetbootClient = nil
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func TestIsNetbootClient6(t *testing.T) {
+	tests := map[string]struct {
+		input   *dhcpv6.Message
+		wantErr bool
+	}{
+		"fail invalid message type": {input: message6(dhcpv6.MessageTypeRenew,
+			dhcpv6.OptClientArchType(iana.EFI_X86_64),
+			vendorClass6(examplePXEClient),
+		), wantErr: true},
+		"fail no opt61": {input: message6(dhcpv6.MessageTypeSolicit,
+			vendorClass6(examplePXEClient),
+		), wantErr: true},
+		"fail no opt16": {input: message6(dhcpv6.MessageTypeSolicit,
+			dhcpv6.OptClientArchType(iana.EFI_X86_64),
+		), wantErr: true},
+		"fail bad opt16": {input: message6(dhcpv6.MessageTypeSolicit,
+			dhcpv6.OptClientArchType(iana.EFI_X86_64),
+			vendorClass6("BadClient"),
+		), wantErr: true},
+		"fail opt16 with another enterprise number": {input: message6(dhcpv6.MessageTypeSolicit,
+			dhcpv6.OptClientArchType(iana.EFI_X86_64),
+			&dhcpv6.OptVendorClass{EnterpriseNumber: 1, Data: [][]byte{[]byte(examplePXEClient)}},
+		), wantErr: true},
+		"success pxe client": {input: message6(dhcpv6.MessageTypeSolicit,
+			dhcpv6.OptClientArchType(iana.EFI_X86_64),
+			vendorClass6(examplePXEClient),
+		)},
+		"success http client information request": {input: message6(dhcpv6.MessageTypeInformationRequest,
+			dhcpv6.OptClientArchType(iana.EFI_X86_64_HTTP),
+			vendorClass6(exampleHTTPClient),
+		)},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := IsNetbootClient6(tt.input); (err != nil) != tt.wantErr {
+				t.Errorf("IsNetbootClient6() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBootfileURL(t *testing.T) {
+	mac := net.HardwareAddr{0xde, 0xed, 0xbe, 0xef, 0xfe, 0xed}
+	tftp := netip.MustParseAddrPort("[2001:db8::1]:69")
+	httpBin := &url.URL{Scheme: "http", Host: "[2001:db8::1]:8080", Path: "/ipxe"}
+	script := &url.URL{Scheme: "http", Host: "[2001:db8::1]:8080", Path: "/auto.ipxe"}
+	tests := map[string]struct {
+		info Info
+		want string
+	}{
+		"pxe client gets a tftp url": {
+			info: Info{Mac: mac, ClientType: PXEClient, IPXEBinary: "ipxe.efi"},
+			want: "tftp://[2001:db8::1]:69/de:ed:be:ef:fe:ed/ipxe.efi",
+		},
+		"http client gets an http url": {
+			info: Info{Mac: mac, ClientType: HTTPClient, IPXEBinary: "ipxe.efi"},
+			want: "http://[2001:db8::1]:8080/ipxe/de:ed:be:ef:fe:ed/ipxe.efi",
+		},
+		"tinkerbell user class gets the script": {
+			info: Info{Mac: mac, ClientType: PXEClient, UserClass: Tinkerbell, IPXEBinary: "ipxe.efi"},
+			want: "http://[2001:db8::1]:8080/auto.ipxe",
+		},
+		"no binary": {
+			info: Info{Mac: mac, ClientType: PXEClient},
+			want: "/no-ipxe-script-defined",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := tt.info.BootfileURL("", script, httpBin, tftp); got != tt.want {
+				t.Errorf("BootfileURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPacket6ClientMAC(t *testing.T) {
+	mac := net.HardwareAddr{0xde, 0xed, 0xbe, 0xef, 0xfe, 0xed}
+	other := net.HardwareAddr{0x00, 0x00, 0x5e, 0x00, 0x53, 0x01}
+	relayed, err := dhcpv6.EncapsulateRelay(
+		message6(dhcpv6.MessageTypeSolicit, dhcpv6.OptClientID(&dhcpv6.DUIDLL{HWType: iana.HWTypeEthernet, LinkLayerAddr: other})),
+		dhcpv6.MessageTypeRelayForward, net.ParseIP("2001:db8::1"), net.ParseIP("2001:db8::100"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	relayed.AddOption(dhcpv6.OptClientLinkLayerAddress(iana.HWTypeEthernet, mac))
+
+	tests := map[string]struct {
+		pkt     Packet6
+		want    net.HardwareAddr
+		wantErr bool
+	}{
+		"eui-64 link-local peer": {
+			pkt: Packet6{
+				Peer: &net.UDPAddr{IP: net.ParseIP("fe80::dced:beff:feef:feed"), Port: 546},
+				Pkt:  message6(dhcpv6.MessageTypeSolicit, dhcpv6.OptClientID(&dhcpv6.DUIDLL{HWType: iana.HWTypeEthernet, LinkLayerAddr: other})),
+			},
+			want: mac,
+		},
+		"duid-ll client id": {
+			pkt: Packet6{
+				Peer: &net.UDPAddr{IP: net.ParseIP("2001:db8::100"), Port: 546},
+				Pkt:  message6(dhcpv6.MessageTypeSolicit, dhcpv6.OptClientID(&dhcpv6.DUIDLL{HWType: iana.HWTypeEthernet, LinkLayerAddr: mac})),
+			},
+			want: mac,
+		},
+		"relay client link-layer address": {
+			pkt:  Packet6{Peer: &net.UDPAddr{IP: net.ParseIP("2001:db8::1"), Port: 547}, Pkt: relayed},
+			want: mac,
+		},
+		"no mac": {
+			pkt:     Packet6{Peer: &net.UDPAddr{IP: net.ParseIP("2001:db8::100"), Port: 546}, Pkt: message6(dhcpv6.MessageTypeSolicit)},
+			wantErr: true,
+		},
+		"nil packet": {wantErr: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := tt.pkt.ClientMAC()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ClientMAC() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func TestPacket6Reply(t *testing.T) {
+	msg := message6(dhcpv6.MessageTypeRequest, dhcpv6.OptClientID(&dhcpv6.DUIDLL{HWType: iana.HWTypeEthernet, LinkLayerAddr: net.HardwareAddr{0xde, 0xed, 0xbe, 0xef, 0xfe, 0xed}}))
+	reply, err := dhcpv6.NewReplyFromMessage(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	relayed, err := dhcpv6.EncapsulateRelay(msg, dhcpv6.MessageTypeRelayForward, net.ParseIP("2001:db8::1"), net.ParseIP("fe80::1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		pkt      Packet6
+		wantType dhcpv6.MessageType
+	}{
+		"direct":  {pkt: Packet6{Pkt: msg}, wantType: dhcpv6.MessageTypeReply},
+		"relayed": {pkt: Packet6{Pkt: relayed}, wantType: dhcpv6.MessageTypeRelayReply},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := tt.pkt.Reply(reply)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Type() != tt.wantType {
+				t.Fatalf("Reply() type = %v, want %v", got.Type(), tt.wantType)
+			}
+			inner, err := got.GetInnerMessage()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if inner.TransactionID != msg.TransactionID {
+				t.Errorf("Reply() transaction ID = %v, want %v", inner.TransactionID, msg.TransactionID)
+			}
+		})
+	}
+}
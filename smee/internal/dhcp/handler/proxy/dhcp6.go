@@ -0,0 +1,225 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+
+	"github.com/go-logr/logr"
+	"github.com/insomniacslk/dhcp/dhcpv6"
+	"github.com/insomniacslk/dhcp/iana"
+	"github.com/tinkerbell/tinkerbell/pkg/constant"
+	"github.com/tinkerbell/tinkerbell/pkg/data"
+	"github.com/tinkerbell/tinkerbell/smee/internal/dhcp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/net/ipv6"
+)
+
+// Handler6 holds the configuration details for running the DHCPv6 proxy server.
+// The DHCPv6 proxy server does not assign addresses, it only gives network boot clients the Boot File URL (option 59).
+type Handler6 struct {
+	// Backend is the backend to use for getting DHCP data.
+	Backend BackendReader
+
+	// ServerID is the DUID of the server, DHCPv6 option 2.
+	// Clients use it to select the server that they send Request messages to.
+	ServerID dhcpv6.DUID
+
+	// Log is used to log messages.
+	// `logr.Discard()` can be used if no logging is desired.
+	Log logr.Logger
+
+	// Netboot configuration
+	Netboot Netboot6
+
+	// AutoProxyEnabled is used to determine if the proxy handler should do any Backend calls or not.
+	// When enabled no Backend calls are made and responses are sent to all valid network boot clients.
+	AutoProxyEnabled bool
+}
+
+// Netboot6 holds the netboot configuration details used in running a DHCPv6 server.
+type Netboot6 struct {
+	// iPXE binary server [IPv6]:Port serving via TFTP.
+	IPXEBinServerTFTP netip.AddrPort
+
+	// IPXEBinServerHTTP is the URL to the IPXE binary server serving via HTTP(s).
+	IPXEBinServerHTTP *url.URL
+
+	// IPXEScriptURL is the URL to the IPXE script to use for the client with the MAC address.
+	IPXEScriptURL func(net.HardwareAddr) *url.URL
+
+	// Enabled is whether to enable sending netboot DHCPv6 options.
+	Enabled bool
+
+	// UserClass (for network booting) allows a custom DHCPv6 option 15 to be used to break out of an iPXE loop.
+	UserClass dhcp.UserClass
+
+	// InjectMacAddrFormat is the format to use when injecting the mac address into the iPXE binary URL.
+	InjectMacAddrFormat constant.MACFormat
+
+	// IPXEArchMapping will override the default architecture to binary mapping.
+	IPXEArchMapping map[iana.Arch]constant.IPXEBinary
+}
+
+// Handle responds to DHCPv6 messages from network boot clients with the Boot File URL.
+// A Solicit is answered with an Advertise, a Request or Information-Request with a Reply. Neither contains an address.
+func (h *Handler6) Handle(ctx context.Context, conn *ipv6.PacketConn, p dhcp.Packet6) {
+	if h.Log.GetSink() == nil {
+		h.Log = logr.Discard()
+	}
+	// validations
+	if p.Pkt == nil {
+		h.Log.Error(errors.New("incoming packet is nil"), "not able to respond when the incoming packet is nil")
+		return
+	}
+	if _, ok := p.Peer.(*net.UDPAddr); !ok {
+		h.Log.Error(errors.New("peer is not a UDP connection"), "not able to respond when the peer is not a UDP connection")
+		return
+	}
+	if conn == nil {
+		h.Log.Error(errors.New("connection is nil"), "not able to respond when the connection is nil")
+		return
+	}
+	msg, err := p.Message()
+	if err != nil {
+		h.Log.Info("not able to respond to a packet without a client message", "error", err)
+		return
+	}
+	mac, err := p.ClientMAC()
+	if err != nil {
+		h.Log.V(1).Info("not able to respond when the client MAC address is unknown", "error", err, "xid", msg.TransactionID.String())
+		return
+	}
+
+	var ifName string
+	if p.Md != nil {
+		ifName = p.Md.IfName
+	}
+	log := h.Log.WithValues("mac", mac.String(), "xid", msg.TransactionID.String(), "interface", ifName, "type", msg.Type().String())
+	tracer := otel.Tracer(tracerName)
+	var span trace.Span
+	ctx, span = tracer.Start(
+		ctx,
+		fmt.Sprintf("DHCPv6 Packet Received: %v", msg.Type().String()),
+		trace.WithAttributes(attribute.String("DHCP.request.mac", mac.String())),
+		trace.WithAttributes(attribute.String("DHCP.peer", p.Peer.String())),
+		trace.WithAttributes(attribute.String("DHCP.server.ifname", ifName)),
+	)
+
+	defer span.End()
+
+	if !h.Netboot.Enabled {
+		log.V(1).Info("Ignoring packet: netboot is not enabled")
+		span.SetStatus(codes.Ok, "Ignoring packet: netboot is not enabled")
+
+		return
+	}
+	if sid := msg.Options.ServerID(); sid != nil && h.ServerID != nil && !sid.Equal(h.ServerID) {
+		log.V(1).Info("Ignoring packet: message is for another server", "serverID", sid.String())
+		span.SetStatus(codes.Ok, "Ignoring packet: message is for another server")
+
+		return
+	}
+
+	i := dhcp.NewInfo6(msg, mac, dhcp.WithMacAddrFormat(h.Netboot.InjectMacAddrFormat), dhcp.WithArchMappingOverride(h.Netboot.IPXEArchMapping))
+	if err := i.IsNetbootClient; err != nil {
+		log.V(1).Info("Ignoring packet: not from a PXE enabled client", "error", err.Error())
+		span.SetStatus(codes.Ok, fmt.Sprintf("Ignoring packet: not from a PXE enabled client: %s", err.Error()))
+
+		return
+	}
+
+	// check the backend, if PXE is NOT allowed, set the boot file URL to "/<mac address>/netboot-not-allowed"
+	var hw dhcp.Hardware
+	spec, err := h.Backend.FilterHardware(ctx, data.HardwareFilter{ByMACAddress: mac.String()})
+	switch {
+	case err != nil && !h.AutoProxyEnabled:
+		log.Info("Ignoring packet", "error", err.Error())
+		span.SetStatus(codes.Error, err.Error())
+		return
+	case err != nil:
+		log.Info("No hardware found, proceeding with defaults", "error", err.Error())
+	default:
+		hw, err = dhcp.ConvertByMac(ctx, mac, spec)
+		if err != nil && !h.AutoProxyEnabled {
+			log.Info("Ignoring packet", "error", err.Error())
+			span.SetStatus(codes.Error, err.Error())
+			return
+		}
+		if err != nil {
+			log.Info("Failed to convert hardware data, proceeding with defaults", "error", err.Error())
+			hw = dhcp.Hardware{}
+		}
+	}
+	if hw.Netboot != nil && hw.Netboot.IPXEBinary != "" {
+		i.IPXEBinary = hw.Netboot.IPXEBinary
+	}
+	if i.IPXEBinary == "" {
+		log.V(1).Info("Ignoring packet: no iPXE binary was able to be determined")
+		span.SetStatus(codes.Ok, "Ignoring packet: no iPXE binary was able to be determined")
+
+		return
+	}
+
+	var ipxeScript *url.URL
+	if h.Netboot.IPXEScriptURL != nil {
+		ipxeScript = h.Netboot.IPXEScriptURL(mac)
+	}
+	bootFileURL := i.BootfileURL(h.Netboot.UserClass, ipxeScript, h.Netboot.IPXEBinServerHTTP, h.Netboot.IPXEBinServerTFTP)
+	if hw.Netboot != nil && !hw.Netboot.AllowNetboot {
+		// this follows the same pattern and keeps the same user experience as the DHCPv4 proxy handler.
+		bootFileURL = fmt.Sprintf("/%s/netboot-not-allowed", mac.String())
+		log.V(1).Info("netboot not allowed")
+	}
+
+	mods := []dhcpv6.Modifier{
+		dhcpv6.WithServerID(h.ServerID),
+		dhcpv6.WithOption(dhcpv6.OptBootFileURL(bootFileURL)),
+	}
+	// UEFI HTTP boot clients only accept replies that identify the server as an HTTPClient.
+	if i.ClientType == dhcp.HTTPClient {
+		mods = append(mods, dhcpv6.WithOption(&dhcpv6.OptVendorClass{EnterpriseNumber: dhcp.PXEEnterpriseNumber, Data: [][]byte{[]byte(dhcp.HTTPClient)}}))
+	}
+	var reply *dhcpv6.Message
+	if msg.Type() == dhcpv6.MessageTypeSolicit {
+		// Without addresses to assign, a rapid commit Reply would end the client's address configuration.
+		reply, err = dhcpv6.NewAdvertiseFromSolicit(msg, mods...)
+	} else {
+		reply, err = dhcpv6.NewReplyFromMessage(msg, mods...)
+	}
+	if err != nil {
+		log.Info("error creating reply", "error", err)
+		span.SetStatus(codes.Error, err.Error())
+
+		return
+	}
+
+	log.Info("received DHCPv6 packet", "clientType", i.ClientType.String(), "userClass", i.UserClass.String())
+	out, err := p.Reply(reply)
+	if err != nil {
+		log.Info("error creating relay reply", "error", err)
+		span.SetStatus(codes.Error, err.Error())
+
+		return
+	}
+	cm := &ipv6.ControlMessage{}
+	if p.Md != nil {
+		cm.IfIndex = p.Md.IfIndex
+	}
+	log = log.WithValues("destination", p.Peer.String(), "bootFileURL", bootFileURL, "replyType", reply.Type().String())
+	if _, err := conn.WriteTo(out.ToBytes(), cm, p.Peer); err != nil {
+		log.Error(err, "failed to send DHCPv6 proxy response")
+		span.SetStatus(codes.Error, err.Error())
+
+		return
+	}
+	log.Info("Sent DHCPv6 proxy response")
+	span.SetAttributes(attribute.String("DHCP.reply.type", reply.Type().String()))
+	span.SetStatus(codes.Ok, "sent DHCPv6 response")
+}
@@ -0,0 +1,180 @@
+//go:build linux
+
+package proxy
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/insomniacslk/dhcp/dhcpv6"
+	"github.com/insomniacslk/dhcp/iana"
+	"github.com/tinkerbell/tinkerbell/smee/internal/dhcp"
+	"golang.org/x/net/ipv6"
+)
+
+func TestHandle6(t *testing.T) {
+	mac := net.HardwareAddr{0xde, 0xed, 0xbe, 0xef, 0xfe, 0xed}
+	serverID := &dhcpv6.DUIDLL{HWType: iana.HWTypeEthernet, LinkLayerAddr: net.HardwareAddr{0x00, 0x00, 0x5e, 0x00, 0x53, 0x01}}
+	netboot := Netboot6{
+		IPXEBinServerTFTP: netip.MustParseAddrPort("[2001:db8::1]:69"),
+		IPXEBinServerHTTP: &url.URL{Scheme: "http", Host: "[2001:db8::1]:8080", Path: "/ipxe"},
+		IPXEScriptURL: func(net.HardwareAddr) *url.URL {
+			return &url.URL{Scheme: "http", Host: "[2001:db8::1]:8080", Path: "/auto.ipxe"}
+		},
+		Enabled: true,
+	}
+	solicit := func(vendorClass string, opts ...dhcpv6.Option) *dhcpv6.Message {
+		m := &dhcpv6.Message{MessageType: dhcpv6.MessageTypeSolicit, TransactionID: dhcpv6.TransactionID{0x01, 0x02, 0x03}}
+		m.AddOption(dhcpv6.OptClientID(&dhcpv6.DUIDLL{HWType: iana.HWTypeEthernet, LinkLayerAddr: mac}))
+		m.AddOption(dhcpv6.OptClientArchType(iana.EFI_X86_64))
+		m.AddOption(&dhcpv6.OptVendorClass{EnterpriseNumber: dhcp.PXEEnterpriseNumber, Data: [][]byte{[]byte(vendorClass)}})
+		for _, o := range opts {
+			m.AddOption(o)
+		}
+		return m
+	}
+	request := solicit("HTTPClient:Arch:00016:UNDI:003001", dhcpv6.OptServerID(serverID))
+	request.MessageType = dhcpv6.MessageTypeRequest
+
+	tests := map[string]struct {
+		handler         *Handler6
+		pkt             *dhcpv6.Message
+		wantType        dhcpv6.MessageType
+		wantBootFileURL string
+		wantHTTPClient  bool
+		wantNoResponse  bool
+	}{
+		"pxe client solicit": {
+			handler:         &Handler6{Backend: &mockBackend{allowNetboot: true}, ServerID: serverID, Netboot: netboot},
+			pkt:             solicit("PXEClient:Arch:00007:UNDI:003001"),
+			wantType:        dhcpv6.MessageTypeAdvertise,
+			wantBootFileURL: "tftp://[2001:db8::1]:69/de:ed:be:ef:fe:ed/ipxe.efi",
+		},
+		"pxe client solicit with rapid commit is still advertised": {
+			handler:         &Handler6{Backend: &mockBackend{allowNetboot: true}, ServerID: serverID, Netboot: netboot},
+			pkt:             solicit("PXEClient:Arch:00007:UNDI:003001", &dhcpv6.OptionGeneric{OptionCode: dhcpv6.OptionRapidCommit}),
+			wantType:        dhcpv6.MessageTypeAdvertise,
+			wantBootFileURL: "tftp://[2001:db8::1]:69/de:ed:be:ef:fe:ed/ipxe.efi",
+		},
+		"http client request": {
+			handler:         &Handler6{Backend: &mockBackend{allowNetboot: true}, ServerID: serverID, Netboot: netboot},
+			pkt:             request,
+			wantType:        dhcpv6.MessageTypeReply,
+			wantBootFileURL: "http://[2001:db8::1]:8080/ipxe/de:ed:be:ef:fe:ed/ipxe.efi",
+			wantHTTPClient:  true,
+		},
+		"ipxe user class gets the script": {
+			handler:         &Handler6{Backend: &mockBackend{allowNetboot: true}, ServerID: serverID, Netboot: netboot},
+			pkt:             solicit("PXEClient:Arch:00007:UNDI:003001", &dhcpv6.OptUserClass{UserClasses: [][]byte{[]byte(dhcp.Tinkerbell)}}),
+			wantType:        dhcpv6.MessageTypeAdvertise,
+			wantBootFileURL: "http://[2001:db8::1]:8080/auto.ipxe",
+		},
+		"netboot not allowed": {
+			handler:         &Handler6{Backend: &mockBackend{allowNetboot: false}, ServerID: serverID, Netboot: netboot},
+			pkt:             solicit("PXEClient:Arch:00007:UNDI:003001"),
+			wantType:        dhcpv6.MessageTypeAdvertise,
+			wantBootFileURL: "/de:ed:be:ef:fe:ed/netboot-not-allowed",
+		},
+		"hardware overrides the ipxe binary": {
+			handler:         &Handler6{Backend: &mockBackend{allowNetboot: true, iPXEBinary: "snp.efi"}, ServerID: serverID, Netboot: netboot},
+			pkt:             solicit("PXEClient:Arch:00007:UNDI:003001"),
+			wantType:        dhcpv6.MessageTypeAdvertise,
+			wantBootFileURL: "tftp://[2001:db8::1]:69/de:ed:be:ef:fe:ed/snp.efi",
+		},
+		"backend error with auto proxy uses defaults": {
+			handler:         &Handler6{Backend: &mockBackend{err: errBackend}, ServerID: serverID, Netboot: netboot, AutoProxyEnabled: true},
+			pkt:             solicit("PXEClient:Arch:00007:UNDI:003001"),
+			wantType:        dhcpv6.MessageTypeAdvertise,
+			wantBootFileURL: "tftp://[2001:db8::1]:69/de:ed:be:ef:fe:ed/ipxe.efi",
+		},
+		"backend error": {
+			handler:        &Handler6{Backend: &mockBackend{err: errBackend}, ServerID: serverID, Netboot: netboot},
+			pkt:            solicit("PXEClient:Arch:00007:UNDI:003001"),
+			wantNoResponse: true,
+		},
+		"not a netboot client": {
+			handler:        &Handler6{Backend: &mockBackend{allowNetboot: true}, ServerID: serverID, Netboot: netboot},
+			pkt:            solicit("BadClient"),
+			wantNoResponse: true,
+		},
+		"message for another server": {
+			handler: &Handler6{Backend: &mockBackend{allowNetboot: true}, ServerID: serverID, Netboot: netboot},
+			pkt: solicit("PXEClient:Arch:00007:UNDI:003001", dhcpv6.OptServerID(&dhcpv6.DUIDLL{
+				HWType: iana.HWTypeEthernet, LinkLayerAddr: net.HardwareAddr{0x00, 0x00, 0x5e, 0x00, 0x53, 0x02},
+			})),
+			wantNoResponse: true,
+		},
+		"netboot disabled": {
+			handler:        &Handler6{Backend: &mockBackend{allowNetboot: true}, ServerID: serverID},
+			pkt:            solicit("PXEClient:Arch:00007:UNDI:003001"),
+			wantNoResponse: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Create the test server
+			conn, err := net.ListenPacket("udp6", "[::1]:0")
+			if err != nil {
+				t.Skip("IPv6 loopback is not available", err)
+			}
+			defer conn.Close()
+
+			// Create a client to listen for responses
+			pc, err := net.ListenPacket("udp6", "[::1]:0")
+			if err != nil {
+				t.Fatal("failed to create test client", err)
+			}
+			defer pc.Close()
+
+			tt.handler.Log = logr.Discard()
+			pkt := dhcp.Packet6{Peer: pc.LocalAddr(), Pkt: tt.pkt}
+			tt.handler.Handle(context.Background(), ipv6.NewPacketConn(conn), pkt)
+
+			msg, err := clientResponse6(pc)
+			if tt.wantNoResponse {
+				if err == nil {
+					t.Fatalf("expected no response, got %v", msg.Summary())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected a response, got error: %v", err)
+			}
+			if msg.Type() != tt.wantType {
+				t.Errorf("response type = %v, want %v", msg.Type(), tt.wantType)
+			}
+			if got := msg.Options.BootFileURL(); got != tt.wantBootFileURL {
+				t.Errorf("boot file URL = %q, want %q", got, tt.wantBootFileURL)
+			}
+			if got := len(msg.Options.VendorClass(dhcp.PXEEnterpriseNumber)) > 0; got != tt.wantHTTPClient {
+				t.Errorf("HTTPClient vendor class = %v, want %v", got, tt.wantHTTPClient)
+			}
+			if sid := msg.Options.ServerID(); sid == nil || !sid.Equal(serverID) {
+				t.Errorf("server ID = %v, want %v", sid, serverID)
+			}
+			if len(msg.Options.IANA()) != 0 {
+				t.Error("proxy response should not assign addresses")
+			}
+		})
+	}
+}
+
+// clientResponse6 attempts to read a DHCPv6 response from the given connection.
+func clientResponse6(pc net.PacketConn) (*dhcpv6.Message, error) {
+	buf := make([]byte, 1500)
+	if err := pc.SetReadDeadline(time.Now().Add(time.Millisecond * 100)); err != nil {
+		return nil, err
+	}
+	n, _, err := pc.ReadFrom(buf)
+	if err != nil {
+		return nil, err
+	}
+
+	return dhcpv6.MessageFromBytes(buf[:n])
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/tinkerbell/tinkerbell/pkg/constant"
 	"github.com/tinkerbell/tinkerbell/pkg/data"
 	"github.com/tinkerbell/tinkerbell/smee/internal/dhcp"
+	"github.com/tinkerbell/tinkerbell/smee/internal/dhcp/history"
 	oteldhcp "github.com/tinkerbell/tinkerbell/smee/internal/dhcp/otel"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -69,6 +70,9 @@ type Handler struct {
 	// AutoProxyEnabled is used to determine if the proxyDHCP handler should do any Backend calls or not.
 	// When enabled no Backend calls are made and responses are sent to all valid network boot clients.
 	AutoProxyEnabled bool
+
+	// History records the DHCP transactions. When nil, no transactions are recorded.
+	History *history.Ring
 }
 
 // Netboot holds the netboot configuration details used in running a DHCP server.
@@ -93,6 +97,10 @@ type Netboot struct {
 
 	// IPXEArchMapping will override the default architecture to binary mapping.
 	IPXEArchMapping map[iana.Arch]constant.IPXEBinary
+
+	// HTTPBootServer is the URL of the server of UEFI HTTP Boot files. When set, UEFI HTTP Boot clients
+	// whose Hardware configures HTTP Boot are given the URL of a shim or a Unified Kernel Image instead of iPXE.
+	HTTPBootServer *url.URL
 }
 
 // Handle implements a ProxyDHCP Redirection server.
@@ -134,6 +142,13 @@ func (h *Handler) Handle(ctx context.Context, conn *ipv4.PacketConn, dp dhcp.Pac
 
 	defer span.End()
 
+	mode := "proxy"
+	if h.AutoProxyEnabled {
+		mode = "auto-proxy"
+	}
+	tx := history.New(dp.Pkt, mode, ifName)
+	defer h.History.Add(tx)
+
 	// We ignore the error here because:
 	// 1. it's only non-nil if the generation of a transaction id (XID) fails.
 	// 2. We always use the clients transaction id (XID) in responses. See dhcpv4.WithReply().
@@ -141,6 +156,7 @@ func (h *Handler) Handle(ctx context.Context, conn *ipv4.PacketConn, dp dhcp.Pac
 
 	if dp.Pkt.OpCode != dhcpv4.OpcodeBootRequest { // TODO(jacobweinstock): dont understand this, found it in an example here: https://github.com/insomniacslk/dhcp/blob/c51060810aaab9c8a0bd1b0fcbf72bc0b91e6427/dhcpv4/server4/server_test.go#L31
 		log.V(1).Info("Ignoring packet", "OpCode", dp.Pkt.OpCode)
+		tx.IgnoredReason = "OpCode is not BootRequest"
 		span.SetStatus(codes.Ok, "Ignoring packet: OpCode not BootRequest")
 
 		return
@@ -148,6 +164,7 @@ func (h *Handler) Handle(ctx context.Context, conn *ipv4.PacketConn, dp dhcp.Pac
 
 	if err := setMessageType(reply, dp.Pkt.MessageType()); err != nil {
 		log.V(1).Info("Ignoring packet", "error", err.Error())
+		tx.IgnoredReason = err.Error()
 		span.SetStatus(codes.Ok, err.Error())
 
 		return
@@ -160,18 +177,23 @@ func (h *Handler) Handle(ctx context.Context, conn *ipv4.PacketConn, dp dhcp.Pac
 
 	if !h.Netboot.Enabled {
 		log.V(1).Info("Ignoring packet: netboot is not enabled")
+		tx.IgnoredReason = "netboot is not enabled"
+		tx.NetbootError = "netboot options are disabled"
 		span.SetStatus(codes.Ok, "Ignoring packet: netboot is not enabled")
 
 		return
 	}
 	if err := i.IsNetbootClient; err != nil {
 		log.V(1).Info("Ignoring packet: not from a PXE enabled client", "error", err.Error())
+		tx.IgnoredReason = "not from a PXE enabled client"
+		tx.NetbootError = err.Error()
 		span.SetStatus(codes.Ok, fmt.Sprintf("Ignoring packet: not from a PXE enabled client: %s", err.Error()))
 
 		return
 	}
 	if i.IPXEBinary == "" {
 		log.V(1).Info("Ignoring packet: no iPXE binary was able to be determined")
+		tx.IgnoredReason = "no iPXE binary was able to be determined"
 		span.SetStatus(codes.Ok, "Ignoring packet: no iPXE binary was able to be determined")
 
 		return
@@ -202,17 +224,33 @@ func (h *Handler) Handle(ctx context.Context, conn *ipv4.PacketConn, dp dhcp.Pac
 	// check the backend, if PXE is NOT allowed, set the boot file name to "/<mac address>/not-allowed"
 	var hw dhcp.Hardware
 	spec, err := h.Backend.FilterHardware(ctx, data.HardwareFilter{ByMACAddress: dp.Pkt.ClientHWAddr.String()})
+	// A client without a Hardware object of its own uses the Hardware object of the switch port it is connected to.
+	sp, relayed := dhcp.SwitchPortFrom(dp.Pkt)
+	bySwitchPort := relayed && dhcp.NotFound(err)
+	if bySwitchPort {
+		spec, err = dhcp.FilterBySwitchPort(ctx, h.Backend.FilterHardware, sp)
+	}
 	switch {
 	case err != nil && !h.AutoProxyEnabled:
 		log.Info("Ignoring packet", "error", err.Error())
+		tx.IgnoredReason = err.Error()
 		span.SetStatus(codes.Error, err.Error())
 		return
 	case err != nil:
 		log.Info("No hardware found, proceeding with defaults", "error", err.Error())
 	default:
-		hw, err = dhcp.ConvertByMac(ctx, dp.Pkt.ClientHWAddr, spec)
+		if spec != nil && spec.Name != "" {
+			tx.Hardware = spec.Namespace + "/" + spec.Name
+		}
+		if bySwitchPort {
+			log.Info("no hardware found by MAC address, using the hardware of the switch port", "circuitID", sp.CircuitID, "remoteID", sp.RemoteID)
+			hw, err = dhcp.ConvertBySwitchPort(ctx, dp.Pkt.ClientHWAddr, sp, spec)
+		} else {
+			hw, err = dhcp.ConvertByMac(ctx, dp.Pkt.ClientHWAddr, spec)
+		}
 		if err != nil && !h.AutoProxyEnabled {
 			log.Info("Ignoring packet", "error", err.Error())
+			tx.IgnoredReason = err.Error()
 			span.SetStatus(codes.Error, err.Error())
 			return
 		}
@@ -229,11 +267,18 @@ func (h *Handler) Handle(ctx context.Context, conn *ipv4.PacketConn, dp dhcp.Pac
 	// set bootfile header
 	// TODO(jacobweinstock): plum through the custom user class.
 	reply.BootFileName = i.Bootfile("", h.Netboot.IPXEScriptURL(dp.Pkt), h.Netboot.IPXEBinServerHTTP, h.Netboot.IPXEBinServerTFTP)
+	// UEFI HTTP Boot clients boot a shim or a Unified Kernel Image directly, without iPXE, when their Hardware says so.
+	if hw.Netboot != nil {
+		if bf := i.HTTPBootURL(h.Netboot.HTTPBootServer, hw.Netboot.HTTPBoot); bf != "" {
+			reply.BootFileName = bf
+		}
+	}
 	if hw.Netboot != nil && !hw.Netboot.AllowNetboot {
 		// if the netboot is not allowed, set the boot file name to "/<mac address>/netboot-not-allowed"
 		// this follows the same pattern and keeps the same user experience as the reservation handler.
 		reply.BootFileName = fmt.Sprintf("/%s/netboot-not-allowed", dp.Pkt.ClientHWAddr.String())
 		log.V(1).Info("netboot not allowed")
+		tx.NetbootError = "netboot is not allowed by the Hardware object"
 		span.SetStatus(codes.Ok, "netboot not allowed")
 	}
 
@@ -259,11 +304,13 @@ func (h *Handler) Handle(ctx context.Context, conn *ipv4.PacketConn, dp dhcp.Pac
 	// send the DHCP packet
 	if _, err := conn.WriteTo(reply.ToBytes(), cm, dst); err != nil {
 		log.Error(err, "failed to send ProxyDHCP response")
+		tx.IgnoredReason = "failed to send reply: " + err.Error()
 		span.SetStatus(codes.Error, err.Error())
 
 		return
 	}
 	log.Info("Sent ProxyDHCP response")
+	history.SetReply(tx, reply)
 	span.SetAttributes(h.encodeToAttributes(reply, "reply")...)
 	span.SetStatus(codes.Ok, "sent DHCP response")
 }
@@ -0,0 +1,318 @@
+package reservation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/insomniacslk/dhcp/dhcpv6"
+	"github.com/insomniacslk/dhcp/iana"
+	"github.com/tinkerbell/tinkerbell/pkg/constant"
+	dhcpotel "github.com/tinkerbell/tinkerbell/pkg/otel"
+	"github.com/tinkerbell/tinkerbell/smee/internal/dhcp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/net/ipv6"
+)
+
+// Handler6 holds the configuration details for running the DHCPv6 server with host reservations.
+type Handler6 struct {
+	// Backend is the backend to use for getting DHCP data.
+	Backend BackendReader
+
+	// ServerID is the DUID of the server, DHCPv6 option 2.
+	// Clients use it to select the server that they send Request, Renew, Release and Decline messages to.
+	ServerID dhcpv6.DUID
+
+	// Log is used to log messages.
+	// `logr.Discard()` can be used if no logging is desired.
+	Log logr.Logger
+
+	// Netboot configuration
+	Netboot Netboot6
+
+	// OTELEnabled is used to determine if netboot options include otel naming.
+	// When true, the netboot filename will be appended with otel information.
+	OTELEnabled bool
+}
+
+// Netboot6 holds the netboot configuration details used in running a DHCPv6 server.
+type Netboot6 struct {
+	// iPXE binary server [IPv6]:Port serving via TFTP.
+	IPXEBinServerTFTP netip.AddrPort
+
+	// IPXEBinServerHTTP is the URL to the IPXE binary server serving via HTTP(s).
+	IPXEBinServerHTTP *url.URL
+
+	// IPXEScriptURL is the URL to the IPXE script to use for the client with the MAC address.
+	IPXEScriptURL func(net.HardwareAddr) *url.URL
+
+	// Enabled is whether to enable sending netboot DHCPv6 options.
+	Enabled bool
+
+	// UserClass (for network booting) allows a custom DHCPv6 option 15 to be used to break out of an iPXE loop.
+	UserClass dhcp.UserClass
+
+	// InjectMacAddrFormat is the format to use when injecting the mac address into the iPXE binary URL.
+	InjectMacAddrFormat constant.MACFormat
+
+	// IPXEArchMapping will override the default architecture to binary mapping.
+	IPXEArchMapping map[iana.Arch]constant.IPXEBinary
+}
+
+// noReservationError is returned when the reservation of a Hardware object is not for the IP family being served.
+// It is not a NotFound error, the Hardware exists, so it must not be served from a dynamic address pool.
+type noReservationError struct {
+	family string
+}
+
+func (e noReservationError) Error() string {
+	return fmt.Sprintf("no %s reservation", e.family)
+}
+
+// noReservation reports whether err means there is no reservation to serve, either because no Hardware
+// object was found or because its reservation is for the other IP family.
+func noReservation(err error) bool {
+	return dhcp.NotFound(err) || errors.As(err, &noReservationError{})
+}
+
+func (h *Handler6) setDefaults() {
+	if h.Log.GetSink() == nil {
+		h.Log = logr.Discard()
+	}
+}
+
+// Handle responds to DHCPv6 messages with the host reservation of the client.
+func (h *Handler6) Handle(ctx context.Context, conn *ipv6.PacketConn, p dhcp.Packet6) {
+	h.setDefaults()
+	if p.Pkt == nil {
+		h.Log.Error(errors.New("incoming packet is nil"), "not able to respond when the incoming packet is nil")
+		return
+	}
+	if _, ok := p.Peer.(*net.UDPAddr); !ok {
+		h.Log.Error(errors.New("peer is not a UDP connection"), "not able to respond when the peer is not a UDP connection")
+		return
+	}
+	if conn == nil {
+		h.Log.Error(errors.New("connection is nil"), "not able to respond when the connection is nil")
+		return
+	}
+	msg, err := p.Message()
+	if err != nil {
+		h.Log.Info("not able to respond to a packet without a client message", "error", err)
+		return
+	}
+	mac, err := p.ClientMAC()
+	if err != nil {
+		h.Log.V(1).Info("not able to respond when the client MAC address is unknown", "error", err, "xid", msg.TransactionID.String())
+		return
+	}
+
+	var ifName string
+	if p.Md != nil {
+		ifName = p.Md.IfName
+	}
+	log := h.Log.WithValues("mac", mac.String(), "xid", msg.TransactionID.String(), "interface", ifName, "type", msg.Type().String())
+	tracer := otel.Tracer(tracerName)
+	var span trace.Span
+	ctx, span = tracer.Start(
+		ctx,
+		fmt.Sprintf("DHCPv6 Packet Received: %v", msg.Type().String()),
+		trace.WithAttributes(attribute.String("DHCP.request.mac", mac.String())),
+		trace.WithAttributes(attribute.String("DHCP.peer", p.Peer.String())),
+		trace.WithAttributes(attribute.String("DHCP.server.ifname", ifName)),
+	)
+
+	defer span.End()
+
+	// Clients send messages to all servers, only reply to those that select this server.
+	if sid := msg.Options.ServerID(); sid != nil && h.ServerID != nil && !sid.Equal(h.ServerID) {
+		log.V(1).Info("Ignoring packet: message is for another server", "serverID", sid.String())
+		span.SetStatus(codes.Ok, "Ignoring packet: message is for another server")
+
+		return
+	}
+
+	var reply *dhcpv6.Message
+	switch msg.Type() {
+	case dhcpv6.MessageTypeSolicit, dhcpv6.MessageTypeRequest, dhcpv6.MessageTypeRenew, dhcpv6.MessageTypeRebind, dhcpv6.MessageTypeInformationRequest:
+		d, n, err := h.readBackend(ctx, mac)
+		if err != nil {
+			if noReservation(err) {
+				log.V(1).Info("no reservation found", "error", err)
+				span.SetStatus(codes.Ok, "no reservation found")
+				return
+			}
+			log.Info("error reading from backend", "error", err)
+			span.SetStatus(codes.Error, err.Error())
+
+			return
+		}
+		if d.Disabled {
+			log.Info("DHCP is disabled for this MAC address, no response sent")
+			span.SetStatus(codes.Ok, "disabled DHCP response")
+
+			return
+		}
+		log.Info("received DHCPv6 packet")
+		if reply, err = h.updateMsg(ctx, msg, mac, d, n); err != nil {
+			log.Info("error creating reply", "error", err)
+			span.SetStatus(codes.Error, err.Error())
+
+			return
+		}
+	case dhcpv6.MessageTypeRelease, dhcpv6.MessageTypeDecline:
+		// Since all IP addresses are host reservations, the server has nothing to release or mark as unavailable.
+		// The client still waits for a Reply before it stops using the address.
+		log.Info("received DHCPv6 packet, all IPs are host reservations")
+		if reply, err = dhcpv6.NewReplyFromMessage(msg, dhcpv6.WithServerID(h.ServerID)); err != nil {
+			log.Info("error creating reply", "error", err)
+			span.SetStatus(codes.Error, err.Error())
+
+			return
+		}
+		reply.AddOption(&dhcpv6.OptStatusCode{StatusCode: iana.StatusSuccess, StatusMessage: "all addresses are host reservations"})
+	default:
+		log.Info("received unsupported message type")
+		span.SetStatus(codes.Error, "received unsupported message type")
+
+		return
+	}
+
+	if err := h.send(conn, p, reply); err != nil {
+		log.Error(err, "failed to send DHCPv6")
+		span.SetStatus(codes.Error, err.Error())
+
+		return
+	}
+
+	log.Info("sent DHCPv6 response", "replyType", reply.Type().String(), "destination", p.Peer.String(), "bootFileURL", reply.Options.BootFileURL())
+	span.SetAttributes(attribute.String("DHCP.reply.type", reply.Type().String()))
+	span.SetStatus(codes.Ok, "sent DHCPv6 response")
+}
+
+// send writes the reply to the peer that sent the packet, through the interface it was received on.
+func (h *Handler6) send(conn *ipv6.PacketConn, p dhcp.Packet6, reply *dhcpv6.Message) error {
+	out, err := p.Reply(reply)
+	if err != nil {
+		return err
+	}
+	cm := &ipv6.ControlMessage{}
+	if p.Md != nil {
+		cm.IfIndex = p.Md.IfIndex
+	}
+	_, err = conn.WriteTo(out.ToBytes(), cm, p.Peer)
+
+	return err
+}
+
+// readBackend reads the reservation of the client and checks that it is for an IPv6 address.
+func (h *Handler6) readBackend(ctx context.Context, mac net.HardwareAddr) (*dhcp.DHCP, *dhcp.Netboot, error) {
+	d, n, err := readHardware(ctx, h.Backend, mac)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !d.IPAddress.Is6() || d.IPAddress.Is4In6() {
+		return nil, nil, noReservationError{family: "IPv6"}
+	}
+
+	return d, n, nil
+}
+
+// updateMsg creates the reply to the client message with the data from the backend.
+// A Solicit is answered with an Advertise, unless the client asked for rapid commit, all other messages with a Reply.
+func (h *Handler6) updateMsg(ctx context.Context, msg *dhcpv6.Message, mac net.HardwareAddr, d *dhcp.DHCP, n *dhcp.Netboot) (*dhcpv6.Message, error) {
+	mods := []dhcpv6.Modifier{dhcpv6.WithServerID(h.ServerID)}
+	if msg.Type() != dhcpv6.MessageTypeInformationRequest {
+		mods = append(mods, withIANA(msg, d))
+	}
+	mods = append(mods, setDHCPOpts6(d)...)
+
+	// Only apply default netboot logic if the BootFileName is empty, see Handler.updateMsg.
+	if h.Netboot.Enabled && dhcp.IsNetbootClient6(msg) == nil && d.BootFileName == "" {
+		mods = append(mods, h.setNetworkBootOpts(ctx, msg, mac, n))
+	}
+
+	if msg.Type() == dhcpv6.MessageTypeSolicit && msg.GetOneOption(dhcpv6.OptionRapidCommit) == nil {
+		return dhcpv6.NewAdvertiseFromSolicit(msg, mods...)
+	}
+
+	return dhcpv6.NewReplyFromMessage(msg, mods...)
+}
+
+// withIANA returns a modifier that assigns the reserved address to each of the client's IA_NA options.
+// The client renews the address at half of the lease time (T1) or rebinds it with any server at 0.8 of the lease time (T2).
+func withIANA(msg *dhcpv6.Message, d *dhcp.DHCP) dhcpv6.Modifier {
+	return func(reply dhcpv6.DHCPv6) {
+		lease := time.Duration(d.LeaseTime) * time.Second
+		for _, ia := range msg.Options.IANA() {
+			reply.AddOption(&dhcpv6.OptIANA{
+				IaId: ia.IaId,
+				T1:   lease / 2,
+				T2:   lease * 4 / 5,
+				Options: dhcpv6.IdentityOptions{Options: dhcpv6.Options{
+					&dhcpv6.OptIAAddress{IPv6Addr: d.IPAddress.AsSlice(), PreferredLifetime: lease, ValidLifetime: lease},
+				}},
+			})
+		}
+	}
+}
+
+// setDHCPOpts6 creates the DHCPv6 options from the data of the reservation.
+// DHCPv6 has no options for the default gateway and routes, they are advertised by routers.
+func setDHCPOpts6(d *dhcp.DHCP) []dhcpv6.Modifier {
+	var mods []dhcpv6.Modifier
+	var dns []net.IP
+	for _, ns := range d.NameServers {
+		if ns.To4() == nil {
+			dns = append(dns, ns)
+		}
+	}
+	if len(dns) > 0 {
+		mods = append(mods, dhcpv6.WithDNS(dns...))
+	}
+	if len(d.DomainSearch) > 0 {
+		mods = append(mods, dhcpv6.WithDomainSearchList(d.DomainSearch...))
+	}
+	if d.BootFileName != "" {
+		mods = append(mods, dhcpv6.WithOption(dhcpv6.OptBootFileURL(d.BootFileName)))
+	}
+
+	return mods
+}
+
+// setNetworkBootOpts returns a modifier that sets the Boot File URL option (59).
+// UEFI HTTP boot clients also need the vendor class option (16) with HTTPClient in the reply.
+func (h *Handler6) setNetworkBootOpts(ctx context.Context, msg *dhcpv6.Message, mac net.HardwareAddr, n *dhcp.Netboot) dhcpv6.Modifier {
+	return func(reply dhcpv6.DHCPv6) {
+		i := dhcp.NewInfo6(msg, mac, dhcp.WithMacAddrFormat(h.Netboot.InjectMacAddrFormat), dhcp.WithIPXEBinary(n.IPXEBinary), dhcp.WithArchMappingOverride(h.Netboot.IPXEArchMapping))
+		if i.ClientType == dhcp.HTTPClient {
+			reply.AddOption(&dhcpv6.OptVendorClass{EnterpriseNumber: dhcp.PXEEnterpriseNumber, Data: [][]byte{[]byte(dhcp.HTTPClient)}})
+		}
+		if !n.AllowNetboot {
+			reply.AddOption(dhcpv6.OptBootFileURL("/netboot-not-allowed"))
+			return
+		}
+		if i.IPXEBinary == "" {
+			return
+		}
+		var ipxeScript *url.URL
+		if h.Netboot.IPXEScriptURL != nil {
+			ipxeScript = h.Netboot.IPXEScriptURL(mac)
+		}
+		if n.IPXEScriptURL != nil {
+			ipxeScript = n.IPXEScriptURL
+		}
+		if tp := dhcpotel.TraceparentStringFromContext(ctx); h.OTELEnabled && tp != "" {
+			i.IPXEBinary = fmt.Sprintf("%s-%v", i.IPXEBinary, tp)
+		}
+		reply.AddOption(dhcpv6.OptBootFileURL(i.BootfileURL(h.Netboot.UserClass, ipxeScript, h.Netboot.IPXEBinServerHTTP, h.Netboot.IPXEBinServerTFTP)))
+	}
+}
@@ -0,0 +1,258 @@
+//go:build linux
+
+package reservation
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
+	"github.com/insomniacslk/dhcp/dhcpv6"
+	"github.com/insomniacslk/dhcp/iana"
+	"github.com/tinkerbell/tinkerbell/api/v1alpha1/tinkerbell"
+	"github.com/tinkerbell/tinkerbell/pkg/data"
+	"github.com/tinkerbell/tinkerbell/smee/internal/dhcp"
+	"golang.org/x/net/ipv6"
+)
+
+// mock6Backend returns hardware with an IPv6 reservation.
+type mock6Backend struct {
+	allowNetboot bool
+	disabled     bool
+	bootFileName string
+}
+
+func (m *mock6Backend) FilterHardware(_ context.Context, opts data.HardwareFilter) (*tinkerbell.Hardware, error) {
+	return &tinkerbell.Hardware{
+		Spec: tinkerbell.HardwareSpec{
+			Interfaces: []tinkerbell.Interface{
+				{
+					DisableDHCP: m.disabled,
+					DHCP: &tinkerbell.DHCP{
+						MAC: opts.ByMACAddress,
+						IP: &tinkerbell.IP{
+							Address: "2001:db8::100",
+							Netmask: "ffff:ffff:ffff:ffff::",
+							Family:  6,
+						},
+						NameServers:  []string{"1.1.1.1", "2001:db8::53"},
+						Hostname:     "test-host",
+						DomainName:   "mydomain.com",
+						LeaseTime:    3600,
+						BootFileName: m.bootFileName,
+					},
+					Netboot: &tinkerbell.Netboot{
+						AllowPXE: &m.allowNetboot,
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+func TestReadBackendAddressFamily(t *testing.T) {
+	mac := net.HardwareAddr{0x01, 0x02, 0x03, 0x04, 0x05, 0x06}
+	tests := map[string]struct {
+		backend      BackendReader
+		v6           bool
+		wantNoReservation bool
+	}{
+		"ipv4 handler with ipv4 reservation": {backend: &mockBackend{}},
+		"ipv4 handler with ipv6 reservation": {backend: &mock6Backend{}, wantNoReservation: true},
+		"ipv6 handler with ipv6 reservation": {backend: &mock6Backend{}, v6: true},
+		"ipv6 handler with ipv4 reservation": {backend: &mockBackend{}, v6: true, wantNoReservation: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var err error
+			if tt.v6 {
+				h := &Handler6{Backend: tt.backend}
+				_, _, err = h.readBackend(context.Background(), mac)
+			} else {
+				h := &Handler{Backend: tt.backend}
+				_, _, err = h.readBackend(context.Background(), mac)
+			}
+			if got := err != nil && noReservation(err); got != tt.wantNoReservation {
+				t.Fatalf("readBackend() error = %v, wantNoReservation %v", err, tt.wantNoReservation)
+			}
+			// The Hardware exists, it must not be handled as a missing Hardware object.
+			if dhcp.NotFound(err) {
+				t.Fatalf("readBackend() error = %v, want an error that is not NotFound", err)
+			}
+			if !tt.wantNoReservation && err != nil {
+				t.Fatalf("readBackend() unexpected error = %v", err)
+			}
+		})
+	}
+}
+
+func TestHandle6(t *testing.T) {
+	mac := net.HardwareAddr{0xde, 0xed, 0xbe, 0xef, 0xfe, 0xed}
+	serverID := &dhcpv6.DUIDLL{HWType: iana.HWTypeEthernet, LinkLayerAddr: net.HardwareAddr{0x00, 0x00, 0x5e, 0x00, 0x53, 0x01}}
+	netboot := Netboot6{
+		IPXEBinServerTFTP: netip.MustParseAddrPort("[2001:db8::1]:69"),
+		IPXEBinServerHTTP: &url.URL{Scheme: "http", Host: "[2001:db8::1]:8080", Path: "/ipxe"},
+		IPXEScriptURL: func(net.HardwareAddr) *url.URL {
+			return &url.URL{Scheme: "http", Host: "[2001:db8::1]:8080", Path: "/auto.ipxe"}
+		},
+		Enabled: true,
+	}
+	message := func(mt dhcpv6.MessageType, opts ...dhcpv6.Option) *dhcpv6.Message {
+		m := &dhcpv6.Message{MessageType: mt, TransactionID: dhcpv6.TransactionID{0x01, 0x02, 0x03}}
+		m.AddOption(dhcpv6.OptClientID(&dhcpv6.DUIDLL{HWType: iana.HWTypeEthernet, LinkLayerAddr: mac}))
+		for _, o := range opts {
+			m.AddOption(o)
+		}
+		return m
+	}
+	iana1 := &dhcpv6.OptIANA{IaId: [4]byte{0, 0, 0, 1}}
+	pxe := []dhcpv6.Option{
+		dhcpv6.OptClientArchType(iana.EFI_X86_64),
+		&dhcpv6.OptVendorClass{EnterpriseNumber: dhcp.PXEEnterpriseNumber, Data: [][]byte{[]byte("PXEClient:Arch:00007:UNDI:003001")}},
+	}
+	wantIANA := []*dhcpv6.OptIANA{{
+		IaId: [4]byte{0, 0, 0, 1},
+		T1:   30 * time.Minute,
+		T2:   48 * time.Minute,
+		Options: dhcpv6.IdentityOptions{Options: dhcpv6.Options{
+			&dhcpv6.OptIAAddress{IPv6Addr: net.ParseIP("2001:db8::100"), PreferredLifetime: time.Hour, ValidLifetime: time.Hour, Options: dhcpv6.AddressOptions{Options: dhcpv6.Options{}}},
+		}},
+	}}
+
+	tests := map[string]struct {
+		handler         *Handler6
+		pkt             *dhcpv6.Message
+		wantType        dhcpv6.MessageType
+		wantIANA        []*dhcpv6.OptIANA
+		wantBootFileURL string
+		wantStatus      bool
+		wantNoResponse  bool
+	}{
+		"solicit": {
+			handler:  &Handler6{Backend: &mock6Backend{}, ServerID: serverID},
+			pkt:      message(dhcpv6.MessageTypeSolicit, iana1),
+			wantType: dhcpv6.MessageTypeAdvertise,
+			wantIANA: wantIANA,
+		},
+		"solicit with rapid commit": {
+			handler:  &Handler6{Backend: &mock6Backend{}, ServerID: serverID},
+			pkt:      message(dhcpv6.MessageTypeSolicit, iana1, &dhcpv6.OptionGeneric{OptionCode: dhcpv6.OptionRapidCommit}),
+			wantType: dhcpv6.MessageTypeReply,
+			wantIANA: wantIANA,
+		},
+		"request from a netboot client": {
+			handler:         &Handler6{Backend: &mock6Backend{allowNetboot: true}, ServerID: serverID, Netboot: netboot},
+			pkt:             message(dhcpv6.MessageTypeRequest, append([]dhcpv6.Option{iana1, dhcpv6.OptServerID(serverID)}, pxe...)...),
+			wantType:        dhcpv6.MessageTypeReply,
+			wantIANA:        wantIANA,
+			wantBootFileURL: "tftp://[2001:db8::1]:69/de:ed:be:ef:fe:ed/ipxe.efi",
+		},
+		"netboot not allowed": {
+			handler:         &Handler6{Backend: &mock6Backend{}, ServerID: serverID, Netboot: netboot},
+			pkt:             message(dhcpv6.MessageTypeSolicit, append([]dhcpv6.Option{iana1}, pxe...)...),
+			wantType:        dhcpv6.MessageTypeAdvertise,
+			wantIANA:        wantIANA,
+			wantBootFileURL: "/netboot-not-allowed",
+		},
+		"boot file name from the backend": {
+			handler:         &Handler6{Backend: &mock6Backend{allowNetboot: true, bootFileName: "http://[2001:db8::2]/custom.efi"}, ServerID: serverID, Netboot: netboot},
+			pkt:             message(dhcpv6.MessageTypeSolicit, append([]dhcpv6.Option{iana1}, pxe...)...),
+			wantType:        dhcpv6.MessageTypeAdvertise,
+			wantIANA:        wantIANA,
+			wantBootFileURL: "http://[2001:db8::2]/custom.efi",
+		},
+		"information request": {
+			handler:  &Handler6{Backend: &mock6Backend{}, ServerID: serverID},
+			pkt:      message(dhcpv6.MessageTypeInformationRequest),
+			wantType: dhcpv6.MessageTypeReply,
+		},
+		"release": {
+			handler:    &Handler6{Backend: &mock6Backend{}, ServerID: serverID},
+			pkt:        message(dhcpv6.MessageTypeRelease, iana1, dhcpv6.OptServerID(serverID)),
+			wantType:   dhcpv6.MessageTypeReply,
+			wantStatus: true,
+		},
+		"message for another server": {
+			handler: &Handler6{Backend: &mock6Backend{}, ServerID: serverID},
+			pkt: message(dhcpv6.MessageTypeRequest, iana1, dhcpv6.OptServerID(&dhcpv6.DUIDLL{
+				HWType: iana.HWTypeEthernet, LinkLayerAddr: net.HardwareAddr{0x00, 0x00, 0x5e, 0x00, 0x53, 0x02},
+			})),
+			wantNoResponse: true,
+		},
+		"ipv4 reservation": {
+			handler:        &Handler6{Backend: &mockBackend{}, ServerID: serverID},
+			pkt:            message(dhcpv6.MessageTypeSolicit, iana1),
+			wantNoResponse: true,
+		},
+		"dhcp disabled": {
+			handler:        &Handler6{Backend: &mock6Backend{disabled: true}, ServerID: serverID},
+			pkt:            message(dhcpv6.MessageTypeSolicit, iana1),
+			wantNoResponse: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			conn, err := net.ListenPacket("udp6", "[::1]:0")
+			if err != nil {
+				t.Skip("IPv6 loopback is not available", err)
+			}
+			defer conn.Close()
+
+			pc, err := net.ListenPacket("udp6", "[::1]:0")
+			if err != nil {
+				t.Fatal("failed to create test client", err)
+			}
+			defer pc.Close()
+
+			tt.handler.Log = logr.Discard()
+			tt.handler.Handle(context.Background(), ipv6.NewPacketConn(conn), dhcp.Packet6{Peer: pc.LocalAddr(), Pkt: tt.pkt})
+
+			buf := make([]byte, 1500)
+			if err := pc.SetReadDeadline(time.Now().Add(time.Millisecond * 100)); err != nil {
+				t.Fatal(err)
+			}
+			n, _, err := pc.ReadFrom(buf)
+			if tt.wantNoResponse {
+				if err == nil {
+					t.Fatal("expected no response, but got one")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected a response, got error: %v", err)
+			}
+			msg, err := dhcpv6.MessageFromBytes(buf[:n])
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if msg.Type() != tt.wantType {
+				t.Errorf("response type = %v, want %v", msg.Type(), tt.wantType)
+			}
+			if sid := msg.Options.ServerID(); sid == nil || !sid.Equal(serverID) {
+				t.Errorf("server ID = %v, want %v", sid, serverID)
+			}
+			if diff := cmp.Diff(tt.wantIANA, msg.Options.IANA()); diff != "" {
+				t.Errorf("unexpected IA_NA (-want +got):\n%s", diff)
+			}
+			if got := msg.Options.BootFileURL(); got != tt.wantBootFileURL {
+				t.Errorf("boot file URL = %q, want %q", got, tt.wantBootFileURL)
+			}
+			if got := msg.Options.Status() != nil; got != tt.wantStatus {
+				t.Errorf("status code set = %v, want %v", got, tt.wantStatus)
+			}
+			if tt.wantStatus {
+				return
+			}
+			if diff := cmp.Diff([]net.IP{net.ParseIP("2001:db8::53")}, msg.Options.DNS()); diff != "" {
+				t.Errorf("unexpected DNS servers (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
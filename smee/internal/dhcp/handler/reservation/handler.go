@@ -10,7 +10,9 @@ import (
 	"github.com/insomniacslk/dhcp/dhcpv4"
 	"github.com/tinkerbell/tinkerbell/pkg/data"
 	"github.com/tinkerbell/tinkerbell/smee/internal/dhcp"
+	"github.com/tinkerbell/tinkerbell/smee/internal/dhcp/history"
 	oteldhcp "github.com/tinkerbell/tinkerbell/smee/internal/dhcp/otel"
+	"github.com/tinkerbell/tinkerbell/smee/internal/dhcp/pool"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
@@ -66,22 +68,35 @@ func (h *Handler) Handle(ctx context.Context, conn *ipv4.PacketConn, p dhcp.Pack
 
 	defer span.End()
 
+	tx := history.New(p.Pkt, "reservation", ifName)
+	defer h.History.Add(tx)
+	ctx = history.WithTransaction(ctx, tx)
+
 	var reply *dhcpv4.DHCPv4
 	switch mt := p.Pkt.MessageType(); mt {
 	case dhcpv4.MessageTypeDiscover:
 		d, n, err := h.readBackend(ctx, p.Pkt.ClientHWAddr)
+		if sp, ok := dhcp.SwitchPortFrom(p.Pkt); ok && dhcp.NotFound(err) {
+			d, n, err = h.readBackendBySwitchPort(ctx, p.Pkt.ClientHWAddr, sp)
+		}
+		if dhcp.NotFound(err) && h.Pools != nil {
+			d, n, err = h.offerFromPool(ctx, p)
+		}
 		if err != nil {
-			if hardwareNotFound(err) {
+			if noReservation(err) {
+				tx.IgnoredReason = err.Error()
 				span.SetStatus(codes.Ok, "no reservation found")
 				return
 			}
 			log.Info("error reading from backend", "error", err)
+			tx.IgnoredReason = "error reading from backend: " + err.Error()
 			span.SetStatus(codes.Error, err.Error())
 
 			return
 		}
 		if d.Disabled {
 			log.Info("DHCP is disabled for this MAC address, no response sent", "type", p.Pkt.MessageType().String())
+			tx.IgnoredReason = "DHCP is disabled for this MAC address"
 			span.SetStatus(codes.Ok, "disabled DHCP response")
 
 			return
@@ -91,18 +106,33 @@ func (h *Handler) Handle(ctx context.Context, conn *ipv4.PacketConn, p dhcp.Pack
 		log = log.WithValues("type", dhcpv4.MessageTypeOffer.String())
 	case dhcpv4.MessageTypeRequest:
 		d, n, err := h.readBackend(ctx, p.Pkt.ClientHWAddr)
+		if sp, ok := dhcp.SwitchPortFrom(p.Pkt); ok && dhcp.NotFound(err) {
+			d, n, err = h.readBackendBySwitchPort(ctx, p.Pkt.ClientHWAddr, sp)
+		}
+		if dhcp.NotFound(err) && h.Pools != nil {
+			d, n, err = h.ackFromPool(ctx, p)
+		}
 		if err != nil {
-			if hardwareNotFound(err) {
+			if noReservation(err) {
+				tx.IgnoredReason = err.Error()
 				span.SetStatus(codes.Ok, "no reservation found")
 				return
 			}
+			if errors.Is(err, pool.ErrUnavailable) {
+				log.Info("requested address is not available from the dynamic address pool", "type", p.Pkt.MessageType().String())
+				reply = h.nak(p.Pkt)
+				log = log.WithValues("type", dhcpv4.MessageTypeNak.String())
+				break
+			}
 			log.Info("error reading from backend", "error", err)
+			tx.IgnoredReason = "error reading from backend: " + err.Error()
 			span.SetStatus(codes.Error, err.Error())
 
 			return
 		}
 		if d.Disabled {
 			log.Info("DHCP is disabled for this MAC address, no response sent", "type", p.Pkt.MessageType().String())
+			tx.IgnoredReason = "DHCP is disabled for this MAC address"
 			span.SetStatus(codes.Ok, "disabled DHCP response")
 
 			return
@@ -111,16 +141,48 @@ func (h *Handler) Handle(ctx context.Context, conn *ipv4.PacketConn, p dhcp.Pack
 		reply = h.updateMsg(ctx, p.Pkt, d, n, dhcpv4.MessageTypeAck)
 		log = log.WithValues("type", dhcpv4.MessageTypeAck.String())
 	case dhcpv4.MessageTypeRelease:
-		// Since the design of this DHCP server is that all IP addresses are
+		// Host reservations are not released. Only the leases of the dynamic address pools are.
+		if h.Pools != nil {
+			if err := h.releaseToPool(ctx, p.Pkt); err != nil {
+				log.Info("error releasing dynamic pool lease", "error", err)
+				tx.IgnoredReason = "error releasing dynamic pool lease: " + err.Error()
+				span.SetStatus(codes.Error, err.Error())
+
+				return
+			}
+		}
+		// Since the design of this DHCP server is that all other IP addresses are
 		// Host reservations, when a client releases an address, the server
 		// doesn't have anything to do. This case is included for clarity of this
 		// design decision.
 		log.Info("received DHCP release packet, no response required, all IPs are host reservations", "type", p.Pkt.MessageType().String())
+		tx.IgnoredReason = "release messages have no response"
 		span.SetStatus(codes.Ok, "received release, no response required")
 
+		return
+	case dhcpv4.MessageTypeDecline:
+		if h.Pools == nil {
+			log.Info("received DHCP decline packet, no response required", "type", p.Pkt.MessageType().String())
+			tx.IgnoredReason = "decline messages have no response"
+			span.SetStatus(codes.Ok, "received decline, no response required")
+
+			return
+		}
+		if err := h.declineToPool(ctx, p); err != nil {
+			log.Info("error declining dynamic pool address", "error", err)
+			tx.IgnoredReason = "error declining dynamic pool address: " + err.Error()
+			span.SetStatus(codes.Error, err.Error())
+
+			return
+		}
+		log.Info("received DHCP decline packet, address marked as unavailable", "type", p.Pkt.MessageType().String(), "declinedIP", p.Pkt.RequestedIPAddress().String())
+		tx.IgnoredReason = "decline messages have no response"
+		span.SetStatus(codes.Ok, "received decline, no response required")
+
 		return
 	default:
 		log.Info("received unknown message type", "type", p.Pkt.MessageType().String(), "message", p.Pkt.Message())
+		tx.IgnoredReason = "unknown message type"
 		span.SetStatus(codes.Error, "received unknown message type")
 
 		return
@@ -142,12 +204,14 @@ func (h *Handler) Handle(ctx context.Context, conn *ipv4.PacketConn, p dhcp.Pack
 
 	if _, err := conn.WriteTo(reply.ToBytes(), cm, dst); err != nil {
 		log.Error(err, "failed to send DHCP")
+		tx.IgnoredReason = "failed to send reply: " + err.Error()
 		span.SetStatus(codes.Error, err.Error())
 
 		return
 	}
 
 	log.Info("sent DHCP response")
+	history.SetReply(tx, reply)
 	span.SetAttributes(h.encodeToAttributes(reply, "reply")...)
 	span.SetStatus(codes.Ok, "sent DHCP response")
 }
@@ -169,19 +233,53 @@ func replyDestination(directPeer net.Addr, giaddr net.IP) net.Addr {
 }
 
 // readBackend encapsulates the backend read and opentelemetry handling.
+// A reservation for an IPv6 address is served by the DHCPv6 Handler6, it returns a noReservationError.
 func (h *Handler) readBackend(ctx context.Context, mac net.HardwareAddr) (*dhcp.DHCP, *dhcp.Netboot, error) {
 	h.setDefaults()
 
+	d, n, err := readHardware(ctx, h.Backend, mac)
+	if err != nil {
+		return nil, nil, err
+	}
+	if d.IPAddress.Is6() && !d.IPAddress.Is4In6() {
+		return nil, nil, noReservationError{family: "IPv4"}
+	}
+
+	return d, n, nil
+}
+
+// readBackendBySwitchPort reads the Hardware of the switch port of a relayed message.
+// It is used when no Hardware has the client's MAC address, for example after the machine's NIC was replaced.
+func (h *Handler) readBackendBySwitchPort(ctx context.Context, mac net.HardwareAddr, sp dhcp.SwitchPort) (*dhcp.DHCP, *dhcp.Netboot, error) {
+	h.setDefaults()
+
+	d, n, err := readHardwareBySwitchPort(ctx, h.Backend, mac, sp)
+	if err != nil {
+		return nil, nil, err
+	}
+	if d.IPAddress.Is6() && !d.IPAddress.Is4In6() {
+		return nil, nil, noReservationError{family: "IPv4"}
+	}
+	h.Log.Info("no hardware found by MAC address, using the hardware of the switch port", "mac", mac.String(), "circuitID", sp.CircuitID, "remoteID", sp.RemoteID)
+
+	return d, n, nil
+}
+
+// readHardware reads the DHCP and netboot data of the MAC address from the backend.
+func readHardware(ctx context.Context, backend BackendReader, mac net.HardwareAddr) (*dhcp.DHCP, *dhcp.Netboot, error) {
 	tracer := otel.Tracer(tracerName)
 	ctx, span := tracer.Start(ctx, "Hardware data get")
 	defer span.End()
 
-	spec, err := h.Backend.FilterHardware(ctx, data.HardwareFilter{ByMACAddress: mac.String()})
+	spec, err := backend.FilterHardware(ctx, data.HardwareFilter{ByMACAddress: mac.String()})
 	if err != nil {
 		span.SetStatus(codes.Error, err.Error())
 
 		return nil, nil, err
 	}
+	if t := history.FromContext(ctx); t != nil && spec != nil && spec.Name != "" {
+		t.Hardware = spec.Namespace + "/" + spec.Name
+	}
 	hw, err := dhcp.ConvertByMac(ctx, mac, spec)
 	if err != nil {
 		span.SetStatus(codes.Error, err.Error())
@@ -196,6 +294,35 @@ func (h *Handler) readBackend(ctx context.Context, mac net.HardwareAddr) (*dhcp.
 	return hw.DHCP, hw.Netboot, nil
 }
 
+// readHardwareBySwitchPort reads the DHCP and netboot data of the interface that is connected to the switch port.
+func readHardwareBySwitchPort(ctx context.Context, backend BackendReader, mac net.HardwareAddr, sp dhcp.SwitchPort) (*dhcp.DHCP, *dhcp.Netboot, error) {
+	tracer := otel.Tracer(tracerName)
+	ctx, span := tracer.Start(ctx, "Hardware data get by switch port")
+	defer span.End()
+
+	spec, err := dhcp.FilterBySwitchPort(ctx, backend.FilterHardware, sp)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+
+		return nil, nil, err
+	}
+	if t := history.FromContext(ctx); t != nil && spec != nil && spec.Name != "" {
+		t.Hardware = spec.Namespace + "/" + spec.Name
+	}
+	hw, err := dhcp.ConvertBySwitchPort(ctx, mac, sp, spec)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+
+		return nil, nil, fmt.Errorf("failed to convert hardware data: %w", err)
+	}
+
+	span.SetAttributes(hw.DHCP.EncodeToAttributes()...)
+	span.SetAttributes(hw.Netboot.EncodeToAttributes()...)
+	span.SetStatus(codes.Ok, "done reading from backend")
+
+	return hw.DHCP, hw.Netboot, nil
+}
+
 // updateMsg handles updating DHCP packets with the data from the backend.
 func (h *Handler) updateMsg(ctx context.Context, pkt *dhcpv4.DHCPv4, d *dhcp.DHCP, n *dhcp.Netboot, msgType dhcpv4.MessageType) *dhcpv4.DHCPv4 {
 	h.setDefaults()
@@ -209,10 +336,23 @@ func (h *Handler) updateMsg(ctx context.Context, pkt *dhcpv4.DHCPv4, d *dhcp.DHC
 
 	// Only apply default netboot logic if BOTH TFTPServerName and BootFileName are empty.
 	// If either is explicitly configured by the user, we respect those values and skip defaults.
-	if h.Netboot.Enabled && dhcp.IsNetbootClient(pkt) == nil &&
+	netbootErr := dhcp.IsNetbootClient(pkt)
+	if h.Netboot.Enabled && netbootErr == nil &&
 		d.TFTPServerName == "" && d.BootFileName == "" {
 		mods = append(mods, h.setNetworkBootOpts(ctx, pkt, n))
 	}
+	if t := history.FromContext(ctx); t != nil {
+		switch {
+		case !h.Netboot.Enabled:
+			t.NetbootError = "netboot options are disabled"
+		case netbootErr != nil:
+			t.NetbootError = netbootErr.Error()
+		case d.TFTPServerName != "" || d.BootFileName != "":
+			t.NetbootError = "the Hardware object sets the TFTP server name or boot file name"
+		case n != nil && !n.AllowNetboot:
+			t.NetbootError = "netboot is not allowed by the Hardware object"
+		}
+	}
 	// We ignore the error here because:
 	// 1. it's only non-nil if the generation of a transaction id (XID) fails.
 	// 2. We always use the clients transaction id (XID) in responses. See dhcpv4.WithReply().
@@ -221,6 +361,17 @@ func (h *Handler) updateMsg(ctx context.Context, pkt *dhcpv4.DHCPv4, d *dhcp.DHC
 	return reply
 }
 
+// nak returns a DHCPNAK reply to the request.
+func (h *Handler) nak(pkt *dhcpv4.DHCPv4) *dhcpv4.DHCPv4 {
+	// The error is ignored for the same reason as in updateMsg.
+	reply, _ := dhcpv4.NewReplyFromRequest(pkt,
+		dhcpv4.WithMessageType(dhcpv4.MessageTypeNak),
+		dhcpv4.WithGeneric(dhcpv4.OptionServerIdentifier, h.IPAddr.AsSlice()),
+	)
+
+	return reply
+}
+
 // encodeToAttributes takes a DHCP packet and returns opentelemetry key/value attributes.
 func (h *Handler) encodeToAttributes(d *dhcpv4.DHCPv4, namespace string) []attribute.KeyValue {
 	h.setDefaults()
@@ -228,12 +379,3 @@ func (h *Handler) encodeToAttributes(d *dhcpv4.DHCPv4, namespace string) []attri
 
 	return a.Encode(d, namespace, oteldhcp.AllEncoders()...)
 }
-
-// hardwareNotFound returns true if the error is from a hardware record not being found.
-func hardwareNotFound(err error) bool {
-	type hardwareNotFound interface {
-		NotFound() bool
-	}
-	te, ok := err.(hardwareNotFound)
-	return ok && te.NotFound()
-}
@@ -24,6 +24,7 @@ import (
 	"github.com/tinkerbell/tinkerbell/pkg/constant"
 	"github.com/tinkerbell/tinkerbell/pkg/data"
 	"github.com/tinkerbell/tinkerbell/smee/internal/dhcp"
+	"github.com/tinkerbell/tinkerbell/smee/internal/dhcp/history"
 	"github.com/tinkerbell/tinkerbell/smee/internal/dhcp/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"golang.org/x/net/ipv4"
@@ -50,6 +51,9 @@ type mockBackend struct {
 	classlessStaticRoutes dhcpv4.Routes
 	tftpServerName        string
 	bootFileName          string
+	// switchPort is the switch port of the Hardware object.
+	// When set, the Hardware object is only found by its switch port.
+	switchPort *tinkerbell.SwitchPort
 }
 
 type hwNotFoundError struct{}
@@ -57,11 +61,11 @@ type hwNotFoundError struct{}
 func (hwNotFoundError) NotFound() bool { return true }
 func (hwNotFoundError) Error() string  { return "not found" }
 
-func (m *mockBackend) FilterHardware(_ context.Context, _ data.HardwareFilter) (*tinkerbell.Hardware, error) {
+func (m *mockBackend) FilterHardware(_ context.Context, f data.HardwareFilter) (*tinkerbell.Hardware, error) {
 	if m.err != nil {
 		return nil, m.err
 	}
-	if m.hardwareNotFound {
+	if m.hardwareNotFound || (m.switchPort != nil && f.BySwitchPort != data.SwitchPortKey(m.switchPort.CircuitID, m.switchPort.RemoteID)) {
 		return nil, hwNotFoundError{}
 	}
 	hw := &tinkerbell.Hardware{
@@ -112,6 +116,7 @@ func (m *mockBackend) FilterHardware(_ context.Context, _ data.HardwareFilter) (
 							return nil
 						}(),
 					},
+					SwitchPort: m.switchPort,
 				},
 			},
 		},
@@ -1116,3 +1121,74 @@ func TestDHCPOnlyModeReflection(t *testing.T) {
 		})
 	}
 }
+
+func TestHandleHistory(t *testing.T) {
+	mac := net.HardwareAddr{0x01, 0x02, 0x03, 0x04, 0x05, 0x06}
+	req, err := dhcpv4.NewDiscovery(mac)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := &Handler{
+		Backend: &mockBackend{hardwareNotFound: true},
+		IPAddr:  netip.MustParseAddr("192.168.1.254"),
+		Netboot: Netboot{Enabled: true},
+		History: history.NewRing(10),
+	}
+	if reply := exchange(t, h, req); reply != nil {
+		t.Fatalf("reply for an unknown client = %v, want none", reply)
+	}
+
+	h.Backend = &mockBackend{}
+	if reply := exchange(t, h, req); reply == nil {
+		t.Fatal("no reply for a known client")
+	}
+
+	txs := h.History.List(mac.String())
+	if len(txs) != 2 {
+		t.Fatalf("recorded transactions = %v, want 2", txs)
+	}
+	// Newest first.
+	if got := txs[0]; got.ReplyType != "OFFER" || got.IPAddress != "192.168.1.100" || got.NetbootError == "" {
+		t.Errorf("transaction of a known client = %+v, want an OFFER of 192.168.1.100 without netboot options", got)
+	}
+	if got := txs[1]; got.ReplyType != "" || got.IgnoredReason == "" {
+		t.Errorf("transaction of an unknown client = %+v, want no reply and an ignored reason", got)
+	}
+}
+
+func TestHandleSwitchPort(t *testing.T) {
+	mac := net.HardwareAddr{0x00, 0x00, 0x5e, 0x00, 0x53, 0x20}
+	req, err := dhcpv4.NewDiscovery(mac, dhcpv4.WithGatewayIP(net.IP{127, 0, 0, 1}), dhcpv4.WithOption(dhcpv4.OptRelayAgentInfo(
+		dhcpv4.OptGeneric(dhcpv4.AgentCircuitIDSubOption, []byte("eth1/5")),
+		dhcpv4.OptGeneric(dhcpv4.AgentRemoteIDSubOption, []byte("leaf01")),
+	)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := &Handler{
+		Backend: &mockBackend{switchPort: &tinkerbell.SwitchPort{CircuitID: "eth1/5"}},
+		IPAddr:  netip.MustParseAddr("192.168.1.254"),
+		History: history.NewRing(10),
+	}
+	// The offer is sent to the relay agent, it is read from the history.
+	_ = exchange(t, h, req)
+	txs := h.History.List(mac.String())
+	if len(txs) != 1 {
+		t.Fatalf("recorded transactions = %v, want 1", txs)
+	}
+	if got := txs[0]; got.ReplyType != "OFFER" || got.IPAddress != "192.168.1.100" {
+		t.Errorf("transaction of a client on a switch port with a Hardware object = %+v, want an OFFER of 192.168.1.100", got)
+	}
+
+	// The relay agent information of a message that was not relayed is not trusted.
+	req.GatewayIPAddr = net.IPv4zero
+	if reply := exchange(t, h, req); reply != nil {
+		t.Fatalf("reply for a client that was not relayed = %v, want none", reply)
+	}
+
+	// Without the relay agent information the client is unknown.
+	req.Options.Del(dhcpv4.OptionRelayAgentInformation)
+	if reply := exchange(t, h, req); reply != nil {
+		t.Fatalf("reply for a client without relay agent information = %v, want none", reply)
+	}
+}
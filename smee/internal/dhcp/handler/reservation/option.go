@@ -93,6 +93,12 @@ func (h *Handler) setNetworkBootOpts(ctx context.Context, m *dhcpv4.DHCPv4, n *d
 		d.ServerIPAddr = net.IPv4(0, 0, 0, 0)
 		if n.AllowNetboot {
 			i := dhcp.NewInfo(m, dhcp.WithMacAddrFormat(h.Netboot.InjectMacAddrFormat), dhcp.WithIPXEBinary(n.IPXEBinary), dhcp.WithArchMappingOverride(h.Netboot.IPXEArchMapping))
+			// UEFI HTTP Boot clients boot a shim or a Unified Kernel Image directly, without iPXE, when their Hardware says so.
+			if bf := i.HTTPBootURL(h.Netboot.HTTPBootServer, n.HTTPBoot); bf != "" {
+				d.BootFileName = bf
+				d.ServerIPAddr = i.NextServer(h.Netboot.HTTPBootServer, h.Netboot.IPXEBinServerTFTP, h.IPAddr)
+				return
+			}
 			if i.IPXEBinary == "" {
 				return
 			}
@@ -187,6 +187,7 @@ func TestSetDHCPOpts(t *testing.T) {
 					IPXEScriptURL:     tt.server.Netboot.IPXEScriptURL,
 					Enabled:           tt.server.Netboot.Enabled,
 					UserClass:         tt.server.Netboot.UserClass,
+					HTTPBootServer:    tt.server.Netboot.HTTPBootServer,
 				},
 				IPAddr:     tt.server.IPAddr,
 				Backend:    tt.server.Backend,
@@ -402,6 +403,30 @@ func TestSetNetworkBootOpts(t *testing.T) {
 				dhcpv4.OptClassIdentifier("HTTPClient"),
 			)},
 		},
+		"netboot allowed, HTTP Boot": {
+			server: &Handler{
+				Log: logr.Discard(),
+				Netboot: Netboot{
+					Enabled:           true,
+					IPXEBinServerHTTP: &url.URL{Scheme: "http", Host: "localhost:8181", Path: "/ipxe"},
+					HTTPBootServer:    &url.URL{Scheme: "http", Host: "localhost:8181", Path: "/httpboot/"},
+				},
+			},
+			args: args{
+				in0: context.Background(),
+				m: &dhcpv4.DHCPv4{
+					ClientHWAddr: net.HardwareAddr{0x01, 0x02, 0x03, 0x04, 0x05, 0x06},
+					Options: dhcpv4.OptionsFromList(
+						dhcpv4.OptClassIdentifier("HTTPClient:xxxxx"),
+						dhcpv4.OptClientArch(iana.EFI_X86_64_HTTP),
+					),
+				},
+				n: &dhcp.Netboot{AllowNetboot: true, HTTPBoot: dhcp.HTTPBoot{Mode: dhcp.HTTPBootModeGRUB}},
+			},
+			want: &dhcpv4.DHCPv4{BootFileName: "http://localhost:8181/httpboot/01:02:03:04:05:06/shimx64.efi", Options: dhcpv4.OptionsFromList(
+				dhcpv4.OptClassIdentifier("HTTPClient"),
+			)},
+		},
 		"netboot not allowed, arch unknown": {
 			server: &Handler{Log: logr.Discard(), Netboot: Netboot{IPXEScriptURL: func(*dhcpv4.DHCPv4) *url.URL {
 				return &url.URL{Scheme: "http", Host: "localhost:8181", Path: "/01:02:03:04:05:06/auto.ipxe"}
@@ -430,6 +455,7 @@ func TestSetNetworkBootOpts(t *testing.T) {
 					IPXEScriptURL:     tt.server.Netboot.IPXEScriptURL,
 					Enabled:           tt.server.Netboot.Enabled,
 					UserClass:         tt.server.Netboot.UserClass,
+					HTTPBootServer:    tt.server.Netboot.HTTPBootServer,
 				},
 				IPAddr:  tt.server.IPAddr,
 				Backend: tt.server.Backend,
@@ -0,0 +1,149 @@
+package reservation
+
+import (
+	"context"
+	"net"
+	"net/netip"
+
+	"github.com/insomniacslk/dhcp/dhcpv4"
+	"github.com/tinkerbell/tinkerbell/smee/internal/dhcp"
+	"github.com/tinkerbell/tinkerbell/smee/internal/dhcp/pool"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// noPoolError is returned when a client without a Hardware object can not be served from a dynamic address pool.
+// It is handled like a missing Hardware object, no response is sent.
+type noPoolError struct {
+	reason string
+}
+
+func (e noPoolError) Error() string {
+	return "no dynamic address pool: " + e.reason
+}
+
+func (noPoolError) NotFound() bool {
+	return true
+}
+
+// offerFromPool holds an address of a dynamic address pool for a client without a Hardware object.
+func (h *Handler) offerFromPool(ctx context.Context, p dhcp.Packet) (*dhcp.DHCP, *dhcp.Netboot, error) {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "Dynamic pool offer")
+	defer span.End()
+
+	pl, ok := h.Pools.Select(h.poolAddrs(p)...)
+	if !ok {
+		span.SetStatus(codes.Ok, "no pool for the client's network")
+		return nil, nil, noPoolError{reason: "no pool for the client's network"}
+	}
+	requested, _ := netip.AddrFromSlice(p.Pkt.RequestedIPAddress().To4())
+	l, err := h.Pools.Offer(ctx, pl, p.Pkt.ClientHWAddr, requested)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, nil, err
+	}
+	span.SetStatus(codes.Ok, "offered address from pool")
+
+	return poolData(pl, l), &dhcp.Netboot{AllowNetboot: true}, nil
+}
+
+// ackFromPool leases the requested address of a dynamic address pool to a client without a Hardware object.
+// It returns pool.ErrUnavailable when the address can not be leased to the client.
+func (h *Handler) ackFromPool(ctx context.Context, p dhcp.Packet) (*dhcp.DHCP, *dhcp.Netboot, error) {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "Dynamic pool ack")
+	defer span.End()
+
+	requested := p.Pkt.RequestedIPAddress()
+	if requested == nil || requested.IsUnspecified() {
+		// A client that renews or rebinds its lease sends its address in ciaddr.
+		requested = p.Pkt.ClientIPAddr
+	}
+	ip, _ := netip.AddrFromSlice(requested.To4())
+
+	// The client selected the offer of another DHCP server.
+	if sid := p.Pkt.ServerIdentifier(); sid != nil && !sid.Equal(h.IPAddr.AsSlice()) {
+		if err := h.Pools.Release(ctx, p.Pkt.ClientHWAddr, ip); err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			return nil, nil, err
+		}
+		span.SetStatus(codes.Ok, "client selected another server")
+		return nil, nil, noPoolError{reason: "client selected another server"}
+	}
+
+	pl, ok := h.Pools.Select(h.poolAddrs(p)...)
+	if !ok {
+		span.SetStatus(codes.Ok, "no pool for the client's network")
+		return nil, nil, noPoolError{reason: "no pool for the client's network"}
+	}
+	l, err := h.Pools.Ack(ctx, pl, p.Pkt.ClientHWAddr, ip)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, nil, err
+	}
+	span.SetStatus(codes.Ok, "leased address from pool")
+
+	return poolData(pl, l), &dhcp.Netboot{AllowNetboot: true}, nil
+}
+
+// poolAddrs returns the addresses used to select the dynamic address pool of a client.
+// For a relayed message this is the relay agent address (giaddr).
+// Otherwise these are the IPv4 addresses of the interface the message was received on and the Handler IPAddr.
+func (h *Handler) poolAddrs(p dhcp.Packet) []netip.Addr {
+	if gi, ok := netip.AddrFromSlice(p.Pkt.GatewayIPAddr.To4()); ok && !gi.IsUnspecified() {
+		return []netip.Addr{gi}
+	}
+
+	var addrs []netip.Addr
+	if p.Md != nil && p.Md.IfName != "" {
+		if iface, err := net.InterfaceByName(p.Md.IfName); err == nil {
+			ifAddrs, _ := iface.Addrs()
+			for _, a := range ifAddrs {
+				if ipnet, ok := a.(*net.IPNet); ok {
+					if ip, ok := netip.AddrFromSlice(ipnet.IP.To4()); ok {
+						addrs = append(addrs, ip)
+					}
+				}
+			}
+		}
+	}
+
+	return append(addrs, h.IPAddr)
+}
+
+// releaseToPool removes the lease of a client that released its address.
+func (h *Handler) releaseToPool(ctx context.Context, pkt *dhcpv4.DHCPv4) error {
+	ip, _ := netip.AddrFromSlice(pkt.ClientIPAddr.To4())
+
+	return h.Pools.Release(ctx, pkt.ClientHWAddr, ip)
+}
+
+// declineToPool marks the address that a client found to be in use as unavailable.
+func (h *Handler) declineToPool(ctx context.Context, p dhcp.Packet) error {
+	ip, _ := netip.AddrFromSlice(p.Pkt.RequestedIPAddress().To4())
+	pl, ok := h.Pools.Select(h.poolAddrs(p)...)
+	if !ok {
+		return nil
+	}
+
+	return h.Pools.Decline(ctx, pl, ip)
+}
+
+// poolData converts a pool lease to the DHCP data of the response.
+func poolData(pl pool.Pool, l pool.Lease) *dhcp.DHCP {
+	d := &dhcp.DHCP{
+		MACAddress:     l.MAC,
+		IPAddress:      l.IP,
+		SubnetMask:     net.CIDRMask(pl.Subnet.Bits(), 32),
+		DefaultGateway: pl.Gateway,
+		DomainName:     pl.DomainName,
+		LeaseTime:      uint32(pl.LeaseTime.Seconds()),
+	}
+	if pl.LeaseTime <= 0 {
+		d.LeaseTime = uint32(pool.DefaultLeaseTime.Seconds())
+	}
+	for _, ns := range pl.NameServers {
+		d.NameServers = append(d.NameServers, ns.AsSlice())
+	}
+
+	return d
+}
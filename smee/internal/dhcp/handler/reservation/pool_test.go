@@ -0,0 +1,219 @@
+//go:build linux
+
+package reservation
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/insomniacslk/dhcp/dhcpv4"
+	"github.com/tinkerbell/tinkerbell/smee/internal/dhcp"
+	"github.com/tinkerbell/tinkerbell/smee/internal/dhcp/pool"
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/nettest"
+)
+
+// exchange sends the request to the handler and returns the reply, or nil when no reply was sent.
+func exchange(t *testing.T, h *Handler, req *dhcpv4.DHCPv4) *dhcpv4.DHCPv4 {
+	t.Helper()
+	conn, err := nettest.NewLocalPacketListener("udp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	pc, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pc.Close()
+
+	n, err := net.InterfaceByName("lo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.Handle(context.Background(), ipv4.NewPacketConn(conn), dhcp.Packet{Peer: pc.LocalAddr(), Pkt: req, Md: &dhcp.Metadata{IfName: n.Name, IfIndex: n.Index}})
+
+	reply, err := client(pc)
+	if errors.Is(err, errBadBackend) {
+		return nil
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return reply
+}
+
+func TestHandlePool(t *testing.T) {
+	mac := net.HardwareAddr{0x00, 0x00, 0x5e, 0x00, 0x53, 0x10}
+	other := net.HardwareAddr{0x00, 0x00, 0x5e, 0x00, 0x53, 0x11}
+	p := pool.Pool{
+		Subnet:      netip.MustParsePrefix("192.168.2.0/24"),
+		Start:       netip.MustParseAddr("192.168.2.10"),
+		End:         netip.MustParseAddr("192.168.2.11"),
+		Gateway:     netip.MustParseAddr("192.168.2.1"),
+		NameServers: []netip.Addr{netip.MustParseAddr("192.168.2.53")},
+		DomainName:  "example.com",
+		LeaseTime:   10 * time.Minute,
+	}
+	store := pool.NewMemoryStore()
+	a, err := pool.NewAllocator(store, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := &Handler{
+		Backend: &mockBackend{hardwareNotFound: true},
+		IPAddr:  netip.MustParseAddr("192.168.2.254"),
+		Pools:   a,
+	}
+	msg := func(mt dhcpv4.MessageType, hw net.HardwareAddr, mods ...dhcpv4.Modifier) *dhcpv4.DHCPv4 {
+		m, err := dhcpv4.New(append([]dhcpv4.Modifier{dhcpv4.WithMessageType(mt), dhcpv4.WithHwAddr(hw)}, mods...)...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return m
+	}
+
+	offer := exchange(t, h, msg(dhcpv4.MessageTypeDiscover, mac))
+	if offer == nil {
+		t.Fatal("no offer for a client without a Hardware object")
+	}
+	if got := offer.MessageType(); got != dhcpv4.MessageTypeOffer {
+		t.Fatalf("message type = %v, want %v", got, dhcpv4.MessageTypeOffer)
+	}
+	if !offer.YourIPAddr.Equal(net.IP{192, 168, 2, 10}) {
+		t.Fatalf("offered address = %v, want 192.168.2.10", offer.YourIPAddr)
+	}
+	if got := offer.SubnetMask(); net.IP(got).String() != "255.255.255.0" {
+		t.Errorf("subnet mask = %v, want 255.255.255.0", net.IP(got))
+	}
+	if got := offer.Router(); len(got) != 1 || !got[0].Equal(net.IP{192, 168, 2, 1}) {
+		t.Errorf("router = %v, want 192.168.2.1", got)
+	}
+	if got := offer.DNS(); len(got) != 1 || !got[0].Equal(net.IP{192, 168, 2, 53}) {
+		t.Errorf("DNS = %v, want 192.168.2.53", got)
+	}
+	if got := offer.DomainName(); got != "example.com" {
+		t.Errorf("domain name = %q, want example.com", got)
+	}
+	if got := offer.IPAddressLeaseTime(0); got != 10*time.Minute {
+		t.Errorf("lease time = %v, want 10m", got)
+	}
+
+	// Another client can not request the address offered to the first one.
+	nak := exchange(t, h, msg(dhcpv4.MessageTypeRequest, other, dhcpv4.WithOption(dhcpv4.OptRequestedIPAddress(offer.YourIPAddr))))
+	if nak == nil || nak.MessageType() != dhcpv4.MessageTypeNak {
+		t.Fatalf("reply to a request for a taken address = %v, want a NAK", nak)
+	}
+
+	ack := exchange(t, h, msg(dhcpv4.MessageTypeRequest, mac,
+		dhcpv4.WithOption(dhcpv4.OptRequestedIPAddress(offer.YourIPAddr)),
+		dhcpv4.WithOption(dhcpv4.OptServerIdentifier(net.IP{192, 168, 2, 254})),
+	))
+	if ack == nil || ack.MessageType() != dhcpv4.MessageTypeAck {
+		t.Fatalf("reply to a request = %v, want an ACK", ack)
+	}
+	if !ack.YourIPAddr.Equal(offer.YourIPAddr) {
+		t.Fatalf("acknowledged address = %v, want %v", ack.YourIPAddr, offer.YourIPAddr)
+	}
+
+	// A client that selected another DHCP server releases the offer.
+	otherOffer := exchange(t, h, msg(dhcpv4.MessageTypeDiscover, other))
+	if otherOffer == nil || !otherOffer.YourIPAddr.Equal(net.IP{192, 168, 2, 11}) {
+		t.Fatalf("offer to the second client = %v, want 192.168.2.11", otherOffer)
+	}
+	if reply := exchange(t, h, msg(dhcpv4.MessageTypeRequest, other,
+		dhcpv4.WithOption(dhcpv4.OptRequestedIPAddress(otherOffer.YourIPAddr)),
+		dhcpv4.WithOption(dhcpv4.OptServerIdentifier(net.IP{192, 168, 2, 200})),
+	)); reply != nil {
+		t.Fatalf("reply to a request for another server = %v, want none", reply)
+	}
+	if leases, _ := store.Leases(context.Background()); len(leases) != 1 {
+		t.Fatalf("leases after selecting another server = %v, want 1", leases)
+	}
+
+	// A release removes the lease.
+	if reply := exchange(t, h, msg(dhcpv4.MessageTypeRelease, mac, dhcpv4.WithClientIP(ack.YourIPAddr))); reply != nil {
+		t.Fatalf("reply to a release = %v, want none", reply)
+	}
+	if leases, _ := store.Leases(context.Background()); len(leases) != 0 {
+		t.Fatalf("leases after release = %v, want none", leases)
+	}
+
+	// A declined address is not offered again.
+	if reply := exchange(t, h, msg(dhcpv4.MessageTypeDecline, mac, dhcpv4.WithOption(dhcpv4.OptRequestedIPAddress(net.IP{192, 168, 2, 10})))); reply != nil {
+		t.Fatalf("reply to a decline = %v, want none", reply)
+	}
+	offer = exchange(t, h, msg(dhcpv4.MessageTypeDiscover, mac))
+	if offer == nil || !offer.YourIPAddr.Equal(net.IP{192, 168, 2, 11}) {
+		t.Fatalf("offer after decline = %v, want 192.168.2.11", offer)
+	}
+}
+
+func TestHandlePoolNoMatch(t *testing.T) {
+	a, err := pool.NewAllocator(pool.NewMemoryStore(), pool.Pool{
+		Subnet: netip.MustParsePrefix("10.10.0.0/24"),
+		Start:  netip.MustParseAddr("10.10.0.10"),
+		End:    netip.MustParseAddr("10.10.0.20"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := &Handler{
+		Backend: &mockBackend{hardwareNotFound: true},
+		IPAddr:  netip.MustParseAddr("192.168.2.254"),
+		Pools:   a,
+	}
+	req, err := dhcpv4.NewDiscovery(net.HardwareAddr{0x00, 0x00, 0x5e, 0x00, 0x53, 0x10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reply := exchange(t, h, req); reply != nil {
+		t.Fatalf("reply from a pool of another network = %v, want none", reply)
+	}
+
+	// A relayed message selects the pool of the relay agent's network.
+	req.GatewayIPAddr = net.IP{10, 10, 0, 1}
+	d, n, err := h.offerFromPool(context.Background(), dhcp.Packet{Pkt: req})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.IPAddress != netip.MustParseAddr("10.10.0.10") {
+		t.Errorf("offered address = %v, want 10.10.0.10", d.IPAddress)
+	}
+	if !n.AllowNetboot {
+		t.Error("pool clients must be allowed to netboot")
+	}
+}
+
+func TestHandlePoolKnownHardware(t *testing.T) {
+	// The Hardware exists and is not allowed to netboot, but only has an IPv6 reservation.
+	// It must not be served from the pools, which allow every client to netboot.
+	a, err := pool.NewAllocator(pool.NewMemoryStore(), pool.Pool{
+		Subnet: netip.MustParsePrefix("192.168.2.0/24"),
+		Start:  netip.MustParseAddr("192.168.2.10"),
+		End:    netip.MustParseAddr("192.168.2.20"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := &Handler{
+		Backend: &mock6Backend{},
+		IPAddr:  netip.MustParseAddr("192.168.2.254"),
+		Pools:   a,
+	}
+	mac := net.HardwareAddr{0x00, 0x00, 0x5e, 0x00, 0x53, 0x10}
+	for _, mt := range []dhcpv4.MessageType{dhcpv4.MessageTypeDiscover, dhcpv4.MessageTypeRequest} {
+		req, err := dhcpv4.New(dhcpv4.WithMessageType(mt), dhcpv4.WithHwAddr(mac), dhcpv4.WithOption(dhcpv4.OptRequestedIPAddress(net.IP{192, 168, 2, 10})))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if reply := exchange(t, h, req); reply != nil {
+			t.Fatalf("reply to a %v for Hardware with only an IPv6 reservation = %v, want none", mt, reply)
+		}
+	}
+}
@@ -1,4 +1,4 @@
-// Package reservation is the handler for responding to DHCPv4 messages with only host reservations.
+// Package reservation is the handler for responding to DHCPv4 messages with host reservations and, optionally, dynamic address pools.
 package reservation
 
 import (
@@ -13,6 +13,8 @@ import (
 	"github.com/tinkerbell/tinkerbell/pkg/constant"
 	"github.com/tinkerbell/tinkerbell/pkg/data"
 	"github.com/tinkerbell/tinkerbell/smee/internal/dhcp"
+	"github.com/tinkerbell/tinkerbell/smee/internal/dhcp/history"
+	"github.com/tinkerbell/tinkerbell/smee/internal/dhcp/pool"
 )
 
 // BackendReader is the interface for getting data from a backend.
@@ -45,6 +47,13 @@ type Handler struct {
 
 	// SyslogAddr is the address to send syslog messages to. DHCP Option 7.
 	SyslogAddr netip.Addr
+
+	// Pools leases addresses of dynamic address pools to clients that do not have a Hardware object.
+	// These clients are allowed to network boot. When nil, clients without a Hardware object get no response.
+	Pools *pool.Allocator
+
+	// History records the DHCP transactions. When nil, no transactions are recorded.
+	History *history.Ring
 }
 
 // Netboot holds the netboot configuration details used in running a DHCP server.
@@ -69,4 +78,8 @@ type Netboot struct {
 
 	// IPXEArchMapping will override the default architecture to binary mapping.
 	IPXEArchMapping map[iana.Arch]constant.IPXEBinary
+
+	// HTTPBootServer is the URL of the server of UEFI HTTP Boot files. When set, UEFI HTTP Boot clients
+	// whose Hardware configures HTTP Boot are given the URL of a shim or a Unified Kernel Image instead of iPXE.
+	HTTPBootServer *url.URL
 }
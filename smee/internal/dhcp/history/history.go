@@ -0,0 +1,155 @@
+// Package history keeps a bounded, in-memory history of DHCP transactions.
+// It shows why a machine was, or was not, sent netboot options without having to read the debug logs.
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/insomniacslk/dhcp/dhcpv4"
+	"github.com/tinkerbell/tinkerbell/pkg/data"
+)
+
+// DefaultSize is the default number of transactions kept in a Ring.
+const DefaultSize = 500
+
+// Ring holds the most recent DHCP transactions. When it is full the oldest transaction is overwritten.
+// A nil Ring records nothing.
+type Ring struct {
+	mu   sync.Mutex
+	txs  []data.DHCPTransaction
+	next int
+	full bool
+}
+
+// NewRing returns a Ring that holds size transactions. It returns nil when size is not positive.
+func NewRing(size int) *Ring {
+	if size <= 0 {
+		return nil
+	}
+
+	return &Ring{txs: make([]data.DHCPTransaction, size)}
+}
+
+// Add records a copy of the transaction.
+func (r *Ring) Add(t *data.DHCPTransaction) {
+	if r == nil || t == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.txs[r.next] = *t
+	r.next = (r.next + 1) % len(r.txs)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// List returns the transactions, newest first.
+// When MAC addresses are given, only the transactions of these MAC addresses are returned.
+func (r *Ring) List(macs ...string) []data.DHCPTransaction {
+	if r == nil {
+		return nil
+	}
+	want := make(map[string]bool, len(macs))
+	for _, m := range macs {
+		want[normalizeMAC(m)] = true
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n := r.next
+	if r.full {
+		n = len(r.txs)
+	}
+	txs := make([]data.DHCPTransaction, 0, n)
+	for i := 1; i <= n; i++ {
+		t := r.txs[(r.next-i+len(r.txs))%len(r.txs)]
+		if len(want) > 0 && !want[t.MAC] {
+			continue
+		}
+		txs = append(txs, t)
+	}
+
+	return txs
+}
+
+// ServeHTTP serves the transactions as a JSON array, newest first.
+// The mac query parameter filters the transactions by MAC address and can be repeated.
+func (r *Ring) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(r.List(req.URL.Query()["mac"]...))
+}
+
+// New returns the transaction of a received DHCP message. mode is the DHCP mode of the handler.
+func New(pkt *dhcpv4.DHCPv4, mode, ifName string) *data.DHCPTransaction {
+	t := &data.DHCPTransaction{
+		Time:        time.Now(),
+		MAC:         pkt.ClientHWAddr.String(),
+		XID:         pkt.TransactionID.String(),
+		MessageType: pkt.MessageType().String(),
+		Mode:        mode,
+		Interface:   ifName,
+		VendorClass: pkt.ClassIdentifier(),
+		UserClass:   strings.Join(pkt.UserClass(), ","),
+	}
+	if gi := pkt.GatewayIPAddr; gi != nil && !gi.IsUnspecified() {
+		t.RelayAgent = gi.String()
+	}
+	archs := make([]string, 0, len(pkt.ClientArch()))
+	for _, a := range pkt.ClientArch() {
+		archs = append(archs, a.String())
+	}
+	t.ClientArch = strings.Join(archs, ",")
+
+	return t
+}
+
+// SetReply records the reply sent for the transaction.
+func SetReply(t *data.DHCPTransaction, reply *dhcpv4.DHCPv4) {
+	if t == nil || reply == nil {
+		return
+	}
+	t.ReplyType = reply.MessageType().String()
+	if ip := reply.YourIPAddr; ip != nil && !ip.IsUnspecified() {
+		t.IPAddress = ip.String()
+	}
+	t.Bootfile = reply.BootFileName
+	if ns := reply.ServerIPAddr; ns != nil && !ns.IsUnspecified() {
+		t.NextServer = ns.String()
+	}
+}
+
+type contextKey struct{}
+
+// WithTransaction returns a context that carries the transaction, so that the functions handling the
+// message can record their decisions.
+func WithTransaction(ctx context.Context, t *data.DHCPTransaction) context.Context {
+	return context.WithValue(ctx, contextKey{}, t)
+}
+
+// FromContext returns the transaction of the context or nil.
+func FromContext(ctx context.Context) *data.DHCPTransaction {
+	t, _ := ctx.Value(contextKey{}).(*data.DHCPTransaction)
+	return t
+}
+
+// normalizeMAC returns the MAC address in the format of net.HardwareAddr.String.
+func normalizeMAC(mac string) string {
+	if hw, err := net.ParseMAC(mac); err == nil {
+		return hw.String()
+	}
+
+	return strings.ToLower(mac)
+}
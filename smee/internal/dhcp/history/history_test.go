@@ -0,0 +1,136 @@
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/insomniacslk/dhcp/dhcpv4"
+	"github.com/insomniacslk/dhcp/iana"
+	"github.com/tinkerbell/tinkerbell/pkg/data"
+)
+
+func macs(txs []data.DHCPTransaction) []string {
+	var got []string
+	for _, t := range txs {
+		got = append(got, t.MAC)
+	}
+	return got
+}
+
+func TestRing(t *testing.T) {
+	tests := map[string]struct {
+		size   int
+		add    []string
+		filter []string
+		want   []string
+	}{
+		"empty":          {size: 3},
+		"not full":       {size: 3, add: []string{"00:00:00:00:00:01", "00:00:00:00:00:02"}, want: []string{"00:00:00:00:00:02", "00:00:00:00:00:01"}},
+		"full":           {size: 2, add: []string{"00:00:00:00:00:01", "00:00:00:00:00:02"}, want: []string{"00:00:00:00:00:02", "00:00:00:00:00:01"}},
+		"oldest dropped": {size: 2, add: []string{"00:00:00:00:00:01", "00:00:00:00:00:02", "00:00:00:00:00:03"}, want: []string{"00:00:00:00:00:03", "00:00:00:00:00:02"}},
+		"filtered": {
+			size:   3,
+			add:    []string{"00:00:00:00:00:01", "00:00:00:00:00:02", "00:00:00:00:00:01"},
+			filter: []string{"00-00-00-00-00-01"},
+			want:   []string{"00:00:00:00:00:01", "00:00:00:00:00:01"},
+		},
+		"disabled": {size: 0, add: []string{"00:00:00:00:00:01"}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := NewRing(tt.size)
+			for _, m := range tt.add {
+				r.Add(&data.DHCPTransaction{MAC: m})
+			}
+			if diff := cmp.Diff(tt.want, macs(r.List(tt.filter...))); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func TestServeHTTP(t *testing.T) {
+	r := NewRing(3)
+	r.Add(&data.DHCPTransaction{MAC: "00:00:00:00:00:01", IgnoredReason: "no hardware found"})
+	r.Add(&data.DHCPTransaction{MAC: "00:00:00:00:00:02", Bootfile: "ipxe.efi"})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/?mac=00:00:00:00:00:01", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	var got []data.DHCPTransaction
+	if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	want := []data.DHCPTransaction{{MAC: "00:00:00:00:00:01", IgnoredReason: "no hardware found"}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatal(diff)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusMethodNotAllowed)
+	}
+}
+
+func TestNewAndSetReply(t *testing.T) {
+	mac := net.HardwareAddr{0x00, 0x00, 0x5e, 0x00, 0x53, 0x01}
+	pkt, err := dhcpv4.New(
+		dhcpv4.WithHwAddr(mac),
+		dhcpv4.WithMessageType(dhcpv4.MessageTypeDiscover),
+		dhcpv4.WithGatewayIP(net.IP{192, 168, 2, 1}),
+		dhcpv4.WithOption(dhcpv4.OptClassIdentifier("PXEClient:Arch:00007:UNDI:003001")),
+		dhcpv4.WithOption(dhcpv4.OptUserClass("Tinkerbell")),
+		dhcpv4.WithOption(dhcpv4.OptClientArch(iana.EFI_X86_64)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reply, err := dhcpv4.NewReplyFromRequest(pkt,
+		dhcpv4.WithMessageType(dhcpv4.MessageTypeOffer),
+		dhcpv4.WithYourIP(net.IP{192, 168, 2, 10}),
+		dhcpv4.WithServerIP(net.IP{192, 168, 2, 254}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reply.BootFileName = "ipxe.efi"
+
+	got := New(pkt, "reservation", "eth0")
+	SetReply(got, reply)
+	want := &data.DHCPTransaction{
+		MAC:         mac.String(),
+		XID:         pkt.TransactionID.String(),
+		MessageType: "DISCOVER",
+		Mode:        "reservation",
+		Interface:   "eth0",
+		RelayAgent:  "192.168.2.1",
+		VendorClass: "PXEClient:Arch:00007:UNDI:003001",
+		UserClass:   "Tinkerbell",
+		ClientArch:  iana.EFI_X86_64.String(),
+		ReplyType:   "OFFER",
+		IPAddress:   "192.168.2.10",
+		Bootfile:    "ipxe.efi",
+		NextServer:  "192.168.2.254",
+	}
+	if diff := cmp.Diff(want, got, cmp.FilterPath(func(p cmp.Path) bool { return p.String() == "Time" }, cmp.Ignore())); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	if got := FromContext(context.Background()); got != nil {
+		t.Fatalf("FromContext() = %v, want nil", got)
+	}
+	tx := &data.DHCPTransaction{MAC: "00:00:00:00:00:01"}
+	if got := FromContext(WithTransaction(context.Background(), tx)); got != tx {
+		t.Fatalf("FromContext() = %v, want %v", got, tx)
+	}
+}
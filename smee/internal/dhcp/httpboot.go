@@ -0,0 +1,69 @@
+package dhcp
+
+import (
+	"net/url"
+
+	"github.com/insomniacslk/dhcp/iana"
+)
+
+// UEFI HTTP Boot modes.
+const (
+	// HTTPBootModeGRUB boots a signed shim that loads GRUB, which loads a grub.cfg generated by Smee.
+	HTTPBootModeGRUB = "grub"
+	// HTTPBootModeUKI boots a Unified Kernel Image.
+	HTTPBootModeUKI = "uki"
+)
+
+// efiArchSuffix maps UEFI architectures to the suffix of their default boot file names, following the
+// naming of the removable media boot path (for example BOOTX64.EFI) that shim and GRUB also use.
+var efiArchSuffix = map[iana.Arch]string{
+	iana.EFI_IA32:        "ia32",
+	iana.EFI_X86_HTTP:    "ia32",
+	iana.EFI_X86_64:      "x64",
+	iana.EFI_BC:          "x64",
+	iana.EFI_X86_64_HTTP: "x64",
+	iana.EFI_BC_HTTP:     "x64",
+	iana.EFI_ARM64:       "aa64",
+	iana.EFI_ARM64_HTTP:  "aa64",
+}
+
+// HTTPBootFile returns the file a UEFI HTTP Boot client boots without iPXE.
+// It returns h.BootFile when set, otherwise the default file of the mode and the client architecture:
+// shim<arch>.efi for HTTPBootModeGRUB and uki-<arch>.efi for HTTPBootModeUKI.
+// An empty string is returned when the mode is not set or unknown, or the architecture has no default.
+func (i Info) HTTPBootFile(h HTTPBoot) string {
+	if h.Mode != HTTPBootModeGRUB && h.Mode != HTTPBootModeUKI {
+		return ""
+	}
+	if h.BootFile != "" {
+		return h.BootFile
+	}
+	suffix, found := efiArchSuffix[i.Arch]
+	if !found {
+		return ""
+	}
+	if h.Mode == HTTPBootModeGRUB {
+		return "shim" + suffix + ".efi"
+	}
+
+	return "uki-" + suffix + ".efi"
+}
+
+// HTTPBootURL returns the URL of the file a UEFI HTTP Boot client boots without iPXE: <server>/<mac>/<file>.
+// The MAC address in the path lets Smee find the Hardware of the client when it, shim or GRUB request files.
+// An empty string is returned when the client is not a UEFI HTTP Boot client or has no boot file.
+func (i Info) HTTPBootURL(server *url.URL, h HTTPBoot) string {
+	if server == nil || i.ClientType != HTTPClient {
+		return ""
+	}
+	f := i.HTTPBootFile(h)
+	if f == "" {
+		return ""
+	}
+	paths := []string{f}
+	if i.Mac != nil {
+		paths = append([]string{macAddrFormat(i.Mac, i.MacAddrFormat)}, paths...)
+	}
+
+	return server.JoinPath(paths...).String()
+}
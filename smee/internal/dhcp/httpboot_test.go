@@ -0,0 +1,76 @@
+package dhcp
+
+import (
+	"net"
+	"net/url"
+	"testing"
+
+	"github.com/insomniacslk/dhcp/iana"
+	"github.com/tinkerbell/tinkerbell/pkg/constant"
+)
+
+func TestHTTPBootURL(t *testing.T) {
+	server := &url.URL{Scheme: "http", Host: "192.168.2.4:7171", Path: "/httpboot/"}
+	mac := net.HardwareAddr{0x00, 0x00, 0x5e, 0x00, 0x53, 0x01}
+	tests := map[string]struct {
+		info   Info
+		server *url.URL
+		boot   HTTPBoot
+		want   string
+	}{
+		"grub x64": {
+			info:   Info{Arch: iana.EFI_X86_64_HTTP, Mac: mac, ClientType: HTTPClient},
+			server: server,
+			boot:   HTTPBoot{Mode: HTTPBootModeGRUB},
+			want:   "http://192.168.2.4:7171/httpboot/00:00:5e:00:53:01/shimx64.efi",
+		},
+		"uki arm64": {
+			info:   Info{Arch: iana.EFI_ARM64_HTTP, Mac: mac, ClientType: HTTPClient},
+			server: server,
+			boot:   HTTPBoot{Mode: HTTPBootModeUKI},
+			want:   "http://192.168.2.4:7171/httpboot/00:00:5e:00:53:01/uki-aa64.efi",
+		},
+		"custom boot file": {
+			info:   Info{Arch: iana.EFI_X86_64_HTTP, Mac: mac, ClientType: HTTPClient},
+			server: server,
+			boot:   HTTPBoot{Mode: HTTPBootModeGRUB, BootFile: "signed/shimx64.efi"},
+			want:   "http://192.168.2.4:7171/httpboot/00:00:5e:00:53:01/signed/shimx64.efi",
+		},
+		"mac address format": {
+			info:   Info{Arch: iana.EFI_X86_64_HTTP, Mac: mac, ClientType: HTTPClient, MacAddrFormat: constant.MacAddrFormatNoDelimiter},
+			server: server,
+			boot:   HTTPBoot{Mode: HTTPBootModeUKI},
+			want:   "http://192.168.2.4:7171/httpboot/00005e005301/uki-x64.efi",
+		},
+		"pxe client": {
+			info:   Info{Arch: iana.EFI_X86_64, Mac: mac, ClientType: PXEClient},
+			server: server,
+			boot:   HTTPBoot{Mode: HTTPBootModeGRUB},
+		},
+		"no mode": {
+			info:   Info{Arch: iana.EFI_X86_64_HTTP, Mac: mac, ClientType: HTTPClient},
+			server: server,
+		},
+		"unknown mode": {
+			info:   Info{Arch: iana.EFI_X86_64_HTTP, Mac: mac, ClientType: HTTPClient},
+			server: server,
+			boot:   HTTPBoot{Mode: "ipxe"},
+		},
+		"legacy bios architecture": {
+			info:   Info{Arch: iana.INTEL_X86PC, Mac: mac, ClientType: HTTPClient},
+			server: server,
+			boot:   HTTPBoot{Mode: HTTPBootModeGRUB},
+		},
+		"http boot disabled": {
+			info: Info{Arch: iana.EFI_X86_64_HTTP, Mac: mac, ClientType: HTTPClient},
+			boot: HTTPBoot{Mode: HTTPBootModeGRUB},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := tt.info.HTTPBootURL(tt.server, tt.boot); got != tt.want {
+				t.Fatalf("HTTPBootURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,315 @@
+// Package pool allocates IP addresses from dynamic address pools to machines that do not have a Hardware object.
+package pool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultLeaseTime is the lease time of a pool that does not set one.
+	DefaultLeaseTime = time.Hour
+	// DefaultOfferTime is how long an offered address is held for a client to request it.
+	DefaultOfferTime = time.Minute
+)
+
+var (
+	// ErrNoFreeAddress is returned when all addresses of a pool are leased.
+	ErrNoFreeAddress = errors.New("no free address in pool")
+	// ErrUnavailable is returned when a client requests an address that it can not be given.
+	ErrUnavailable = errors.New("address is not available")
+)
+
+// Pool is a range of IPv4 addresses that are leased to machines without a Hardware object.
+type Pool struct {
+	// Subnet is the network of the pool. Its mask is sent in DHCP option 1.
+	Subnet netip.Prefix
+	// Start is the first address of the pool.
+	Start netip.Addr
+	// End is the last address of the pool.
+	End netip.Addr
+	// Gateway is the default gateway, DHCP option 3.
+	Gateway netip.Addr
+	// NameServers are the DNS servers, DHCP option 6.
+	NameServers []netip.Addr
+	// DomainName is the domain name, DHCP option 15.
+	DomainName string
+	// LeaseTime is the lease time, DHCP option 51. Defaults to DefaultLeaseTime.
+	LeaseTime time.Duration
+}
+
+// Validate checks that the range of the pool is made of IPv4 addresses inside of the subnet.
+func (p Pool) Validate() error {
+	if !p.Subnet.IsValid() || !p.Subnet.Addr().Is4() {
+		return fmt.Errorf("pool subnet %q must be an IPv4 prefix", p.Subnet)
+	}
+	if !p.Start.Is4() || !p.End.Is4() {
+		return fmt.Errorf("pool range %v-%v must be IPv4 addresses", p.Start, p.End)
+	}
+	if !p.Subnet.Contains(p.Start) || !p.Subnet.Contains(p.End) {
+		return fmt.Errorf("pool range %v-%v is not in the subnet %v", p.Start, p.End, p.Subnet)
+	}
+	if p.End.Less(p.Start) {
+		return fmt.Errorf("pool range start %v is after the end %v", p.Start, p.End)
+	}
+	if p.Gateway.IsValid() && !p.Subnet.Contains(p.Gateway) {
+		return fmt.Errorf("pool gateway %v is not in the subnet %v", p.Gateway, p.Subnet)
+	}
+
+	return nil
+}
+
+// Contains reports whether the IP address is in the range of the pool.
+func (p Pool) Contains(ip netip.Addr) bool {
+	return ip.Is4() && !ip.Less(p.Start) && !p.End.Less(ip)
+}
+
+// leaseTime returns the lease time of the pool or DefaultLeaseTime.
+func (p Pool) leaseTime() time.Duration {
+	if p.LeaseTime <= 0 {
+		return DefaultLeaseTime
+	}
+
+	return p.LeaseTime
+}
+
+// usable reports whether the IP address of the range can be leased.
+// The network, broadcast and gateway addresses are never leased.
+func (p Pool) usable(ip netip.Addr) bool {
+	if ip == p.Gateway || ip == p.Subnet.Masked().Addr() {
+		return false
+	}
+	if p.Subnet.Bits() < 31 && ip == broadcast(p.Subnet) {
+		return false
+	}
+
+	return true
+}
+
+// broadcast returns the broadcast address of the IPv4 prefix.
+func broadcast(p netip.Prefix) netip.Addr {
+	a := p.Masked().Addr().As4()
+	mask := net.CIDRMask(p.Bits(), 32)
+	for i := range a {
+		a[i] |= ^mask[i]
+	}
+
+	return netip.AddrFrom4(a)
+}
+
+// Lease is an IP address leased to a MAC address.
+type Lease struct {
+	// IP is the leased IP address.
+	IP netip.Addr
+	// MAC is the MAC address of the client. It is empty for a declined address.
+	MAC net.HardwareAddr
+	// Expires is when the lease ends and the address can be leased to another client.
+	Expires time.Time
+}
+
+// Expired reports whether the lease ended at the time.
+func (l Lease) Expired(now time.Time) bool {
+	return !now.Before(l.Expires)
+}
+
+func (l Lease) ownedBy(mac net.HardwareAddr) bool {
+	return len(l.MAC) > 0 && l.MAC.String() == mac.String()
+}
+
+// Allocator leases addresses of the pools to clients and keeps the leases in the Store.
+type Allocator struct {
+	// Pools are the address pools to lease from.
+	Pools []Pool
+	// Store persists the leases.
+	Store Store
+	// OfferTime is how long an offered address is held for the client. Defaults to DefaultOfferTime.
+	OfferTime time.Duration
+
+	// now returns the current time, it is replaced in tests.
+	now func() time.Time
+	mu  sync.Mutex
+}
+
+// NewAllocator returns an Allocator for the pools. The pools must be valid and must not overlap.
+func NewAllocator(store Store, pools ...Pool) (*Allocator, error) {
+	if store == nil {
+		return nil, errors.New("a lease store is required")
+	}
+	for i, p := range pools {
+		if err := p.Validate(); err != nil {
+			return nil, err
+		}
+		for _, o := range pools[:i] {
+			if p.Contains(o.Start) || p.Contains(o.End) || o.Contains(p.Start) {
+				return nil, fmt.Errorf("pool range %v-%v overlaps with %v-%v", p.Start, p.End, o.Start, o.End)
+			}
+		}
+	}
+
+	return &Allocator{Pools: pools, Store: store, OfferTime: DefaultOfferTime, now: time.Now}, nil
+}
+
+// Select returns the first pool whose subnet contains one of the addresses.
+// The addresses are the relay agent address (giaddr) of a relayed message or the addresses of the local interface.
+func (a *Allocator) Select(addrs ...netip.Addr) (Pool, bool) {
+	for _, addr := range addrs {
+		addr = addr.Unmap()
+		for _, p := range a.Pools {
+			if p.Subnet.Contains(addr) {
+				return p, true
+			}
+		}
+	}
+
+	return Pool{}, false
+}
+
+// Offer holds an address of the pool for the MAC address for the OfferTime.
+// The client's current lease is preferred, then the requested address, then the first free address of the pool.
+func (a *Allocator) Offer(ctx context.Context, p Pool, mac net.HardwareAddr, requested netip.Addr) (Lease, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := a.timeNow()
+	leases, err := a.leases(ctx, p)
+	if err != nil {
+		return Lease{}, err
+	}
+	for _, l := range leases {
+		if l.ownedBy(mac) && !l.Expired(now) {
+			return l, nil
+		}
+	}
+
+	ip, ok := netip.Addr{}, false
+	if requested.IsValid() && p.Contains(requested) && p.usable(requested) && free(leases, requested, mac, now) {
+		ip, ok = requested, true
+	}
+	for next := p.Start; !ok && next.IsValid() && !p.End.Less(next); next = next.Next() {
+		if p.usable(next) && free(leases, next, mac, now) {
+			ip, ok = next, true
+		}
+	}
+	if !ok {
+		return Lease{}, ErrNoFreeAddress
+	}
+
+	l := Lease{IP: ip, MAC: mac, Expires: now.Add(a.offerTime())}
+	if err := a.Store.Put(ctx, l); err != nil {
+		return Lease{}, err
+	}
+
+	return l, nil
+}
+
+// Ack leases the address to the MAC address for the lease time of the pool.
+// It returns ErrUnavailable when the address is not in the pool or is leased to another client.
+// Other leases of the MAC address in the pool are removed.
+func (a *Allocator) Ack(ctx context.Context, p Pool, mac net.HardwareAddr, ip netip.Addr) (Lease, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if !p.Contains(ip) || !p.usable(ip) {
+		return Lease{}, ErrUnavailable
+	}
+	now := a.timeNow()
+	leases, err := a.leases(ctx, p)
+	if err != nil {
+		return Lease{}, err
+	}
+	if !free(leases, ip, mac, now) {
+		return Lease{}, ErrUnavailable
+	}
+	for _, l := range leases {
+		if l.ownedBy(mac) && l.IP != ip {
+			if err := a.Store.Delete(ctx, l.IP); err != nil {
+				return Lease{}, err
+			}
+		}
+	}
+
+	l := Lease{IP: ip, MAC: mac, Expires: now.Add(p.leaseTime())}
+	if err := a.Store.Put(ctx, l); err != nil {
+		return Lease{}, err
+	}
+
+	return l, nil
+}
+
+// Release removes the lease of the address when it belongs to the MAC address.
+func (a *Allocator) Release(ctx context.Context, mac net.HardwareAddr, ip netip.Addr) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	leases, err := a.Store.Leases(ctx)
+	if err != nil {
+		return err
+	}
+	for _, l := range leases {
+		if l.IP == ip && l.ownedBy(mac) {
+			return a.Store.Delete(ctx, ip)
+		}
+	}
+
+	return nil
+}
+
+// Decline marks an address of the pool that a client found to be in use as unavailable for the lease time of the pool.
+func (a *Allocator) Decline(ctx context.Context, p Pool, ip netip.Addr) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if !p.Contains(ip) {
+		return nil
+	}
+
+	return a.Store.Put(ctx, Lease{IP: ip, Expires: a.timeNow().Add(p.leaseTime())})
+}
+
+// leases returns the leases of the store that are in the range of the pool.
+func (a *Allocator) leases(ctx context.Context, p Pool) ([]Lease, error) {
+	all, err := a.Store.Leases(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read leases: %w", err)
+	}
+	var leases []Lease
+	for _, l := range all {
+		if p.Contains(l.IP) {
+			leases = append(leases, l)
+		}
+	}
+
+	return leases, nil
+}
+
+func (a *Allocator) timeNow() time.Time {
+	if a.now == nil {
+		return time.Now()
+	}
+
+	return a.now()
+}
+
+func (a *Allocator) offerTime() time.Duration {
+	if a.OfferTime <= 0 {
+		return DefaultOfferTime
+	}
+
+	return a.OfferTime
+}
+
+// free reports whether the address has no lease, an expired lease, or a lease of the MAC address.
+func free(leases []Lease, ip netip.Addr, mac net.HardwareAddr, now time.Time) bool {
+	for _, l := range leases {
+		if l.IP == ip {
+			return l.Expired(now) || l.ownedBy(mac)
+		}
+	}
+
+	return true
+}
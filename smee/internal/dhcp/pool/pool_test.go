@@ -0,0 +1,270 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var (
+	mac1 = net.HardwareAddr{0x00, 0x00, 0x5e, 0x00, 0x53, 0x01}
+	mac2 = net.HardwareAddr{0x00, 0x00, 0x5e, 0x00, 0x53, 0x02}
+)
+
+func testPool() Pool {
+	return Pool{
+		Subnet:    netip.MustParsePrefix("192.168.2.0/24"),
+		Start:     netip.MustParseAddr("192.168.2.1"),
+		End:       netip.MustParseAddr("192.168.2.3"),
+		Gateway:   netip.MustParseAddr("192.168.2.1"),
+		LeaseTime: time.Hour,
+	}
+}
+
+func testAllocator(t *testing.T, now time.Time, pools ...Pool) *Allocator {
+	t.Helper()
+	a, err := NewAllocator(NewMemoryStore(), pools...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.now = func() time.Time { return now }
+
+	return a
+}
+
+func TestValidate(t *testing.T) {
+	tests := map[string]struct {
+		mutate  func(*Pool)
+		wantErr bool
+	}{
+		"valid":                  {mutate: func(*Pool) {}},
+		"ipv6 subnet":            {mutate: func(p *Pool) { p.Subnet = netip.MustParsePrefix("2001:db8::/64") }, wantErr: true},
+		"start outside subnet":   {mutate: func(p *Pool) { p.Start = netip.MustParseAddr("192.168.3.1") }, wantErr: true},
+		"end before start":       {mutate: func(p *Pool) { p.End = netip.MustParseAddr("192.168.2.0") }, wantErr: true},
+		"gateway outside subnet": {mutate: func(p *Pool) { p.Gateway = netip.MustParseAddr("10.0.0.1") }, wantErr: true},
+		"missing range":          {mutate: func(p *Pool) { p.Start = netip.Addr{} }, wantErr: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			p := testPool()
+			tt.mutate(&p)
+			if err := p.Validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewAllocatorOverlap(t *testing.T) {
+	p2 := testPool()
+	p2.Start = netip.MustParseAddr("192.168.2.3")
+	p2.End = netip.MustParseAddr("192.168.2.10")
+	if _, err := NewAllocator(NewMemoryStore(), testPool(), p2); err == nil {
+		t.Fatal("expected an error for overlapping pools")
+	}
+}
+
+func TestSelect(t *testing.T) {
+	p2 := Pool{
+		Subnet: netip.MustParsePrefix("10.0.0.0/24"),
+		Start:  netip.MustParseAddr("10.0.0.100"),
+		End:    netip.MustParseAddr("10.0.0.200"),
+	}
+	a := testAllocator(t, time.Now(), testPool(), p2)
+	tests := map[string]struct {
+		addrs  []netip.Addr
+		want   Pool
+		wantOK bool
+	}{
+		"relay agent address":      {addrs: []netip.Addr{netip.MustParseAddr("10.0.0.1")}, want: p2, wantOK: true},
+		"first matching interface": {addrs: []netip.Addr{netip.MustParseAddr("172.16.0.1"), netip.MustParseAddr("192.168.2.254")}, want: testPool(), wantOK: true},
+		"ipv4 mapped address":      {addrs: []netip.Addr{netip.MustParseAddr("::ffff:10.0.0.1")}, want: p2, wantOK: true},
+		"no match":                 {addrs: []netip.Addr{netip.MustParseAddr("172.16.0.1")}},
+		"no addresses":             {},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, ok := a.Select(tt.addrs...)
+			if ok != tt.wantOK {
+				t.Fatalf("Select() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if diff := cmp.Diff(tt.want, got, cmp.Comparer(func(a, b netip.Addr) bool { return a == b }), cmp.Comparer(func(a, b netip.Prefix) bool { return a == b })); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func TestOffer(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := map[string]struct {
+		leases    []Lease
+		mac       net.HardwareAddr
+		requested netip.Addr
+		wantIP    netip.Addr
+		wantErr   error
+	}{
+		"first free address skips the gateway": {
+			mac:    mac1,
+			wantIP: netip.MustParseAddr("192.168.2.2"),
+		},
+		"requested address": {
+			mac:       mac1,
+			requested: netip.MustParseAddr("192.168.2.3"),
+			wantIP:    netip.MustParseAddr("192.168.2.3"),
+		},
+		"requested address outside the pool": {
+			mac:       mac1,
+			requested: netip.MustParseAddr("192.168.2.100"),
+			wantIP:    netip.MustParseAddr("192.168.2.2"),
+		},
+		"current lease is preferred": {
+			leases:    []Lease{{IP: netip.MustParseAddr("192.168.2.3"), MAC: mac1, Expires: now.Add(time.Minute)}},
+			mac:       mac1,
+			requested: netip.MustParseAddr("192.168.2.2"),
+			wantIP:    netip.MustParseAddr("192.168.2.3"),
+		},
+		"address leased to another client is skipped": {
+			leases:    []Lease{{IP: netip.MustParseAddr("192.168.2.2"), MAC: mac2, Expires: now.Add(time.Minute)}},
+			mac:       mac1,
+			requested: netip.MustParseAddr("192.168.2.2"),
+			wantIP:    netip.MustParseAddr("192.168.2.3"),
+		},
+		"expired lease of another client is reused": {
+			leases: []Lease{
+				{IP: netip.MustParseAddr("192.168.2.2"), MAC: mac2, Expires: now.Add(-time.Minute)},
+				{IP: netip.MustParseAddr("192.168.2.3"), MAC: mac2, Expires: now.Add(time.Minute)},
+			},
+			mac:    mac1,
+			wantIP: netip.MustParseAddr("192.168.2.2"),
+		},
+		"pool exhausted": {
+			leases: []Lease{
+				{IP: netip.MustParseAddr("192.168.2.2"), MAC: mac2, Expires: now.Add(time.Minute)},
+				{IP: netip.MustParseAddr("192.168.2.3"), Expires: now.Add(time.Minute)},
+			},
+			mac:     mac1,
+			wantErr: ErrNoFreeAddress,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			a := testAllocator(t, now, testPool())
+			for _, l := range tt.leases {
+				if err := a.Store.Put(context.Background(), l); err != nil {
+					t.Fatal(err)
+				}
+			}
+			got, err := a.Offer(context.Background(), testPool(), tt.mac, tt.requested)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Offer() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.IP != tt.wantIP {
+				t.Errorf("Offer() IP = %v, want %v", got.IP, tt.wantIP)
+			}
+			if got.MAC.String() != tt.mac.String() {
+				t.Errorf("Offer() MAC = %v, want %v", got.MAC, tt.mac)
+			}
+		})
+	}
+}
+
+func TestLeaseLifecycle(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	a := testAllocator(t, now, testPool())
+	p := testPool()
+
+	offer, err := a.Offer(ctx, p, mac1, netip.Addr{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := now.Add(DefaultOfferTime); !offer.Expires.Equal(want) {
+		t.Errorf("offer expires = %v, want %v", offer.Expires, want)
+	}
+
+	// Another client can not take the offered address.
+	if _, err := a.Ack(ctx, p, mac2, offer.IP); !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("Ack() of an address offered to another client error = %v, want %v", err, ErrUnavailable)
+	}
+
+	ack, err := a.Ack(ctx, p, mac1, offer.IP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := now.Add(time.Hour); !ack.Expires.Equal(want) {
+		t.Errorf("lease expires = %v, want %v", ack.Expires, want)
+	}
+
+	// Acknowledging another address moves the lease.
+	moved, err := a.Ack(ctx, p, mac1, netip.MustParseAddr("192.168.2.3"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	leases, _ := a.Store.Leases(ctx)
+	if len(leases) != 1 || leases[0].IP != moved.IP {
+		t.Fatalf("leases after moving = %v, want only %v", leases, moved.IP)
+	}
+
+	// Only the owner can release the lease.
+	if err := a.Release(ctx, mac2, moved.IP); err != nil {
+		t.Fatal(err)
+	}
+	if leases, _ := a.Store.Leases(ctx); len(leases) != 1 {
+		t.Fatalf("lease was released by another client: %v", leases)
+	}
+	if err := a.Release(ctx, mac1, moved.IP); err != nil {
+		t.Fatal(err)
+	}
+	if leases, _ := a.Store.Leases(ctx); len(leases) != 0 {
+		t.Fatalf("lease was not released: %v", leases)
+	}
+
+	// A declined address is not offered again.
+	if err := a.Decline(ctx, p, netip.MustParseAddr("192.168.2.2")); err != nil {
+		t.Fatal(err)
+	}
+	offer, err = a.Offer(ctx, p, mac2, netip.MustParseAddr("192.168.2.2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if offer.IP != netip.MustParseAddr("192.168.2.3") {
+		t.Errorf("offer after decline = %v, want 192.168.2.3", offer.IP)
+	}
+}
+
+func TestAckUnavailable(t *testing.T) {
+	a := testAllocator(t, time.Now(), testPool())
+	tests := map[string]netip.Addr{
+		"outside the pool": netip.MustParseAddr("192.168.2.100"),
+		"gateway":          netip.MustParseAddr("192.168.2.1"),
+	}
+	for name, ip := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := a.Ack(context.Background(), testPool(), mac1, ip); !errors.Is(err, ErrUnavailable) {
+				t.Fatalf("Ack() error = %v, want %v", err, ErrUnavailable)
+			}
+		})
+	}
+}
+
+func TestBroadcast(t *testing.T) {
+	tests := map[string]string{
+		"192.168.2.0/24": "192.168.2.255",
+		"10.0.0.0/8":     "10.255.255.255",
+		"10.1.2.64/26":   "10.1.2.127",
+	}
+	for in, want := range tests {
+		if got := broadcast(netip.MustParsePrefix(in)); got != netip.MustParseAddr(want) {
+			t.Errorf("broadcast(%v) = %v, want %v", in, got, want)
+		}
+	}
+}
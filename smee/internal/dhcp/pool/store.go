@@ -0,0 +1,153 @@
+package pool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Store persists the leases of the dynamic address pools.
+// Leases are keyed by their IP address, there is at most one lease per address.
+type Store interface {
+	// Leases returns all leases, including expired ones.
+	Leases(ctx context.Context) ([]Lease, error)
+	// Put creates or replaces the lease of the IP address.
+	Put(ctx context.Context, l Lease) error
+	// Delete removes the lease of the IP address.
+	Delete(ctx context.Context, ip netip.Addr) error
+}
+
+// MemoryStore keeps leases in memory. Leases are lost when Smee restarts.
+type MemoryStore struct {
+	mu     sync.Mutex
+	leases map[netip.Addr]Lease
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{leases: make(map[netip.Addr]Lease)}
+}
+
+func (m *MemoryStore) Leases(_ context.Context) ([]Lease, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	leases := make([]Lease, 0, len(m.leases))
+	for _, l := range m.leases {
+		leases = append(leases, l)
+	}
+
+	return leases, nil
+}
+
+func (m *MemoryStore) Put(_ context.Context, l Lease) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.leases[l.IP] = l
+
+	return nil
+}
+
+func (m *MemoryStore) Delete(_ context.Context, ip netip.Addr) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.leases, ip)
+
+	return nil
+}
+
+// leaseBucket is the bbolt bucket that holds the leases.
+var leaseBucket = []byte("leases")
+
+// BoltStore keeps leases in a local bbolt database file so that they survive a restart of Smee.
+type BoltStore struct {
+	db *bolt.DB
+}
+
+// boltLease is the stored value of a lease, the IP address is the key.
+type boltLease struct {
+	MAC     string    `json:"mac,omitempty"`
+	Expires time.Time `json:"expires"`
+}
+
+// NewBoltStore opens, or creates, the bbolt database file at path.
+func NewBoltStore(path string) (*BoltStore, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lease file %q: %w", path, err)
+	}
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(leaseBucket)
+		return err
+	}); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to create lease bucket: %w", err)
+	}
+
+	return &BoltStore{db: db}, nil
+}
+
+// Close closes the database file.
+func (b *BoltStore) Close() error {
+	return b.db.Close()
+}
+
+func (b *BoltStore) Leases(_ context.Context) ([]Lease, error) {
+	var leases []Lease
+	err := b.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(leaseBucket).ForEach(func(k, v []byte) error {
+			ip, err := netip.ParseAddr(string(k))
+			if err != nil {
+				return fmt.Errorf("invalid lease key %q: %w", k, err)
+			}
+			var bl boltLease
+			if err := json.Unmarshal(v, &bl); err != nil {
+				return fmt.Errorf("invalid lease for %v: %w", ip, err)
+			}
+			l := Lease{IP: ip, Expires: bl.Expires}
+			if bl.MAC != "" {
+				if l.MAC, err = net.ParseMAC(bl.MAC); err != nil {
+					return fmt.Errorf("invalid lease MAC address for %v: %w", ip, err)
+				}
+			}
+			leases = append(leases, l)
+
+			return nil
+		})
+	})
+
+	return leases, err
+}
+
+func (b *BoltStore) Put(_ context.Context, l Lease) error {
+	if !l.IP.IsValid() {
+		return errors.New("lease IP address is required")
+	}
+	bl := boltLease{Expires: l.Expires}
+	if len(l.MAC) > 0 {
+		bl.MAC = l.MAC.String()
+	}
+	v, err := json.Marshal(bl)
+	if err != nil {
+		return err
+	}
+
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(leaseBucket).Put([]byte(l.IP.String()), v)
+	})
+}
+
+func (b *BoltStore) Delete(_ context.Context, ip netip.Addr) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(leaseBucket).Delete([]byte(ip.String()))
+	})
+}
@@ -0,0 +1,96 @@
+package pool
+
+import (
+	"context"
+	"net/netip"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestStore(t *testing.T) {
+	stores := map[string]func(t *testing.T) Store{
+		"memory": func(*testing.T) Store { return NewMemoryStore() },
+		"bolt": func(t *testing.T) Store {
+			s, err := NewBoltStore(filepath.Join(t.TempDir(), "leases.db"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { _ = s.Close() })
+			return s
+		},
+	}
+	expires := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			s := newStore(t)
+			leases := []Lease{
+				{IP: netip.MustParseAddr("192.168.2.2"), MAC: mac1, Expires: expires},
+				{IP: netip.MustParseAddr("192.168.2.3"), Expires: expires},
+			}
+			for _, l := range leases {
+				if err := s.Put(ctx, l); err != nil {
+					t.Fatal(err)
+				}
+			}
+			// Replacing a lease keeps one lease per address.
+			leases[0].MAC = mac2
+			if err := s.Put(ctx, leases[0]); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := s.Leases(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sort.Slice(got, func(i, j int) bool { return got[i].IP.Less(got[j].IP) })
+			if diff := cmp.Diff(leases, got, cmp.Comparer(func(a, b netip.Addr) bool { return a == b })); diff != "" {
+				t.Fatalf("unexpected leases (-want +got):\n%s", diff)
+			}
+
+			if err := s.Delete(ctx, leases[0].IP); err != nil {
+				t.Fatal(err)
+			}
+			got, err = s.Leases(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(leases[1:], got, cmp.Comparer(func(a, b netip.Addr) bool { return a == b })); diff != "" {
+				t.Fatalf("unexpected leases after delete (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestBoltStorePersists(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "leases.db")
+	s, err := NewBoltStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Lease{IP: netip.MustParseAddr("192.168.2.2"), MAC: mac1, Expires: time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)}
+	if err := s.Put(ctx, want); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err = NewBoltStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	got, err := s.Leases(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]Lease{want}, got, cmp.Comparer(func(a, b netip.Addr) bool { return a == b })); diff != "" {
+		t.Fatalf("unexpected leases after reopening (-want +got):\n%s", diff)
+	}
+}
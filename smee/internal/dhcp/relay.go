@@ -0,0 +1,100 @@
+package dhcp
+
+import (
+	"context"
+	"encoding/hex"
+	"unicode"
+
+	"github.com/insomniacslk/dhcp/dhcpv4"
+	v1alpha1 "github.com/tinkerbell/tinkerbell/api/v1alpha1/tinkerbell"
+	"github.com/tinkerbell/tinkerbell/pkg/data"
+)
+
+// SwitchPort is the switch port a relayed DHCP message was received on.
+// It comes from the relay agent information option (82), see https://www.rfc-editor.org/rfc/rfc3046.html.
+type SwitchPort struct {
+	// CircuitID is the agent circuit ID, sub-option 1.
+	CircuitID string
+	// RemoteID is the agent remote ID, sub-option 2.
+	RemoteID string
+}
+
+// SwitchPortFrom returns the switch port of the relay agent information option (82) of a DHCP message.
+// Sub-option values that are not printable text are returned as lowercase hex.
+// The bool is false when the message has no agent circuit ID, or was not relayed. A client can set option 82 itself,
+// it is only trusted when a relay agent, which replaces or drops the client's option, set the gateway address.
+func SwitchPortFrom(pkt *dhcpv4.DHCPv4) (SwitchPort, bool) {
+	if pkt.GatewayIPAddr == nil || pkt.GatewayIPAddr.IsUnspecified() {
+		return SwitchPort{}, false
+	}
+	rai := pkt.RelayAgentInfo()
+	if rai == nil {
+		return SwitchPort{}, false
+	}
+	sp := SwitchPort{
+		CircuitID: subOptionString(rai.Get(dhcpv4.AgentCircuitIDSubOption)),
+		RemoteID:  subOptionString(rai.Get(dhcpv4.AgentRemoteIDSubOption)),
+	}
+
+	return sp, sp.CircuitID != ""
+}
+
+// Keys returns the data.HardwareFilter BySwitchPort values to find the Hardware of the switch port, most specific first.
+// A Hardware interface that only declares the circuit ID matches any remote ID.
+func (s SwitchPort) Keys() []string {
+	if s.RemoteID == "" {
+		return []string{data.SwitchPortKey(s.CircuitID, "")}
+	}
+
+	return []string{data.SwitchPortKey(s.CircuitID, s.RemoteID), data.SwitchPortKey(s.CircuitID, "")}
+}
+
+// Matches reports whether a Hardware interface is connected to the switch port.
+func (s SwitchPort) Matches(i v1alpha1.Interface) bool {
+	if i.SwitchPort == nil || i.SwitchPort.CircuitID != s.CircuitID {
+		return false
+	}
+
+	return i.SwitchPort.RemoteID == "" || i.SwitchPort.RemoteID == s.RemoteID
+}
+
+// FilterBySwitchPort finds the Hardware of the switch port with filter.
+// The error of the last lookup is returned when no Hardware is found.
+func FilterBySwitchPort(ctx context.Context, filter func(context.Context, data.HardwareFilter) (*v1alpha1.Hardware, error), s SwitchPort) (*v1alpha1.Hardware, error) {
+	var err error
+	for _, k := range s.Keys() {
+		var hw *v1alpha1.Hardware
+		hw, err = filter(ctx, data.HardwareFilter{BySwitchPort: k})
+		if err == nil {
+			return hw, nil
+		}
+		if !NotFound(err) {
+			return nil, err
+		}
+	}
+
+	return nil, err
+}
+
+// NotFound reports whether the error of a backend lookup means that no Hardware was found.
+func NotFound(err error) bool {
+	type hardwareNotFound interface {
+		NotFound() bool
+	}
+	te, ok := err.(hardwareNotFound)
+	return ok && te.NotFound()
+}
+
+// subOptionString returns a relay agent sub-option value as text, or as lowercase hex when it is not printable.
+func subOptionString(b []byte) string {
+	if len(b) == 0 {
+		return ""
+	}
+	for _, r := range string(b) {
+		if r == unicode.ReplacementChar || !unicode.IsPrint(r) {
+			return hex.EncodeToString(b)
+		}
+	}
+
+	return string(b)
+}
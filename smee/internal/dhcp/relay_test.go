@@ -0,0 +1,200 @@
+package dhcp
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/insomniacslk/dhcp/dhcpv4"
+	"github.com/tinkerbell/tinkerbell/api/v1alpha1/tinkerbell"
+	"github.com/tinkerbell/tinkerbell/pkg/data"
+)
+
+type notFoundError struct{}
+
+func (notFoundError) NotFound() bool { return true }
+func (notFoundError) Error() string  { return "not found" }
+
+func TestSwitchPortFrom(t *testing.T) {
+	tests := map[string]struct {
+		opts      []dhcpv4.Option
+		unrelayed bool
+		want      SwitchPort
+		wantOK    bool
+	}{
+		"no relay agent information": {},
+		"circuit and remote ID": {
+			opts:   []dhcpv4.Option{dhcpv4.OptGeneric(dhcpv4.AgentCircuitIDSubOption, []byte("eth1/5")), dhcpv4.OptGeneric(dhcpv4.AgentRemoteIDSubOption, []byte("leaf01"))},
+			want:   SwitchPort{CircuitID: "eth1/5", RemoteID: "leaf01"},
+			wantOK: true,
+		},
+		"not relayed": {
+			opts:      []dhcpv4.Option{dhcpv4.OptGeneric(dhcpv4.AgentCircuitIDSubOption, []byte("eth1/5")), dhcpv4.OptGeneric(dhcpv4.AgentRemoteIDSubOption, []byte("leaf01"))},
+			unrelayed: true,
+		},
+		"binary values are hex": {
+			opts:   []dhcpv4.Option{dhcpv4.OptGeneric(dhcpv4.AgentCircuitIDSubOption, []byte{0x00, 0x04, 0x00, 0x0a}), dhcpv4.OptGeneric(dhcpv4.AgentRemoteIDSubOption, []byte{0x00, 0x06, 0xb4, 0x96, 0x91, 0x6f, 0x33, 0xd0})},
+			want:   SwitchPort{CircuitID: "0004000a", RemoteID: "0006b496916f33d0"},
+			wantOK: true,
+		},
+		"remote ID only": {
+			opts: []dhcpv4.Option{dhcpv4.OptGeneric(dhcpv4.AgentRemoteIDSubOption, []byte("leaf01"))},
+			want: SwitchPort{RemoteID: "leaf01"},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var mods []dhcpv4.Modifier
+			if !tt.unrelayed {
+				mods = append(mods, dhcpv4.WithGatewayIP(net.IP{192, 168, 2, 1}))
+			}
+			if len(tt.opts) > 0 {
+				mods = append(mods, dhcpv4.WithOption(dhcpv4.OptRelayAgentInfo(tt.opts...)))
+			}
+			pkt, err := dhcpv4.NewDiscovery(net.HardwareAddr{0x00, 0x00, 0x5e, 0x00, 0x53, 0x01}, mods...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, ok := SwitchPortFrom(pkt)
+			if ok != tt.wantOK {
+				t.Fatalf("SwitchPortFrom() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func TestSwitchPortMatches(t *testing.T) {
+	tests := map[string]struct {
+		port *tinkerbell.SwitchPort
+		want bool
+	}{
+		"no switch port":          {},
+		"circuit ID only":         {port: &tinkerbell.SwitchPort{CircuitID: "eth1/5"}, want: true},
+		"circuit and remote ID":   {port: &tinkerbell.SwitchPort{CircuitID: "eth1/5", RemoteID: "leaf01"}, want: true},
+		"other circuit ID":        {port: &tinkerbell.SwitchPort{CircuitID: "eth1/6"}},
+		"other remote ID":         {port: &tinkerbell.SwitchPort{CircuitID: "eth1/5", RemoteID: "leaf02"}},
+		"circuit ID of other key": {port: &tinkerbell.SwitchPort{CircuitID: "leaf01"}},
+	}
+	sp := SwitchPort{CircuitID: "eth1/5", RemoteID: "leaf01"}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := sp.Matches(tinkerbell.Interface{SwitchPort: tt.port}); got != tt.want {
+				t.Fatalf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterBySwitchPort(t *testing.T) {
+	errBackend := errors.New("backend error")
+	tests := map[string]struct {
+		sp       SwitchPort
+		found    map[string]string
+		err      error
+		want     string
+		wantErr  error
+		wantKeys []string
+	}{
+		"most specific first": {
+			sp:       SwitchPort{CircuitID: "eth1/5", RemoteID: "leaf01"},
+			found:    map[string]string{"leaf01|eth1/5": "specific", "eth1/5": "circuit"},
+			want:     "specific",
+			wantKeys: []string{"leaf01|eth1/5"},
+		},
+		"circuit ID fallback": {
+			sp:       SwitchPort{CircuitID: "eth1/5", RemoteID: "leaf01"},
+			found:    map[string]string{"eth1/5": "circuit"},
+			want:     "circuit",
+			wantKeys: []string{"leaf01|eth1/5", "eth1/5"},
+		},
+		"not found": {
+			sp:       SwitchPort{CircuitID: "eth1/5"},
+			wantErr:  notFoundError{},
+			wantKeys: []string{"eth1/5"},
+		},
+		"backend error": {
+			sp:       SwitchPort{CircuitID: "eth1/5", RemoteID: "leaf01"},
+			err:      errBackend,
+			wantErr:  errBackend,
+			wantKeys: []string{"leaf01|eth1/5"},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var keys []string
+			filter := func(_ context.Context, f data.HardwareFilter) (*tinkerbell.Hardware, error) {
+				keys = append(keys, f.BySwitchPort)
+				if tt.err != nil {
+					return nil, tt.err
+				}
+				if n, ok := tt.found[f.BySwitchPort]; ok {
+					hw := &tinkerbell.Hardware{}
+					hw.Name = n
+					return hw, nil
+				}
+				return nil, notFoundError{}
+			}
+			hw, err := FilterBySwitchPort(context.Background(), filter, tt.sp)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("FilterBySwitchPort() error = %v, want %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.wantKeys, keys); diff != "" {
+				t.Fatal(diff)
+			}
+			if tt.want != "" && (hw == nil || hw.Name != tt.want) {
+				t.Fatalf("FilterBySwitchPort() = %v, want %v", hw, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertBySwitchPort(t *testing.T) {
+	mac := net.HardwareAddr{0x00, 0x00, 0x5e, 0x00, 0x53, 0x01}
+	hw := &tinkerbell.Hardware{
+		Spec: tinkerbell.HardwareSpec{
+			Interfaces: []tinkerbell.Interface{
+				{
+					DHCP: &tinkerbell.DHCP{
+						MAC: "00:11:22:33:44:55",
+						IP:  &tinkerbell.IP{Address: "10.0.0.1", Netmask: "255.255.255.0"},
+					},
+					SwitchPort: &tinkerbell.SwitchPort{CircuitID: "eth1/1"},
+				},
+				{
+					DHCP: &tinkerbell.DHCP{
+						MAC: "00:11:22:33:44:66",
+						IP:  &tinkerbell.IP{Address: "10.0.0.2", Netmask: "255.255.255.0"},
+					},
+					Netboot:    &tinkerbell.Netboot{AllowPXE: boolPtr(true)},
+					SwitchPort: &tinkerbell.SwitchPort{CircuitID: "eth1/5"},
+				},
+			},
+		},
+	}
+
+	got, err := ConvertBySwitchPort(context.Background(), mac, SwitchPort{CircuitID: "eth1/5", RemoteID: "leaf01"}, hw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.DHCP.MACAddress.String() != mac.String() {
+		t.Errorf("MAC address = %v, want %v", got.DHCP.MACAddress, mac)
+	}
+	if got.DHCP.IPAddress.String() != "10.0.0.2" {
+		t.Errorf("IP address = %v, want 10.0.0.2", got.DHCP.IPAddress)
+	}
+	if !got.Netboot.AllowNetboot {
+		t.Error("netboot must be allowed")
+	}
+	if hw.Spec.Interfaces[1].DHCP.MAC != "00:11:22:33:44:66" {
+		t.Errorf("the Hardware object must not be modified, MAC = %v", hw.Spec.Interfaces[1].DHCP.MAC)
+	}
+
+	if _, err := ConvertBySwitchPort(context.Background(), mac, SwitchPort{CircuitID: "eth1/9"}, hw); err == nil {
+		t.Error("expected an error for a switch port without an interface")
+	}
+}
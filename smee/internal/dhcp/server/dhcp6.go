@@ -0,0 +1,182 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/go-logr/logr"
+	"github.com/insomniacslk/dhcp/dhcpv6"
+	"github.com/insomniacslk/dhcp/dhcpv6/server6"
+	"github.com/insomniacslk/dhcp/iana"
+	dp "github.com/tinkerbell/tinkerbell/smee/internal/dhcp"
+	"golang.org/x/net/ipv6"
+)
+
+// Handler6 is a type that defines the handler function to be called every time a valid DHCPv6 message is received.
+type Handler6 interface {
+	Handle(ctx context.Context, conn *ipv6.PacketConn, d dp.Packet6)
+}
+
+// DHCP6 represents a DHCPv6 server object.
+type DHCP6 struct {
+	Conn     net.PacketConn
+	Handlers []Handler6
+	Logger   logr.Logger
+}
+
+// Serve serves requests.
+func (s *DHCP6) Serve(ctx context.Context) error {
+	go func() {
+		<-ctx.Done()
+		_ = s.Close()
+	}()
+	s.Logger.V(1).Info("Server listening on", "addr", s.Conn.LocalAddr())
+
+	nConn := ipv6.NewPacketConn(s.Conn)
+	if err := nConn.SetControlMessage(ipv6.FlagInterface, true); err != nil {
+		s.Logger.Info("error setting control message", "err", err)
+		return err
+	}
+
+	defer func() {
+		_ = nConn.Close()
+	}()
+	for {
+		// DHCPv6 messages are not limited to a single ethernet frame, relayed messages can be larger.
+		// We use 8192 as a reasonable buffer size. dhcpv6.FromBytes will handle the rest.
+		rbuf := make([]byte, 8192)
+		n, cm, peer, err := nConn.ReadFrom(rbuf)
+		if err != nil {
+			select {
+			case <-ctx.Done():
+				return nil
+			default:
+			}
+			s.Logger.Info("error reading from packet conn", "err", err)
+			return err
+		}
+
+		m, err := dhcpv6.FromBytes(rbuf[:n])
+		if err != nil {
+			s.Logger.Info("error parsing DHCPv6 request", "err", err)
+			continue
+		}
+
+		upeer, ok := peer.(*net.UDPAddr)
+		if !ok {
+			s.Logger.Info("not a UDP connection? Peer is", "peer", peer)
+			continue
+		}
+
+		var ifName string
+		var ifIndex int
+		if cm != nil {
+			ifIndex = cm.IfIndex
+		}
+		if n, err := net.InterfaceByIndex(ifIndex); err == nil {
+			ifName = n.Name
+		}
+
+		for _, handler := range s.Handlers {
+			go handler.Handle(ctx, nConn, dp.Packet6{Peer: upeer, Pkt: m, Md: &dp.Metadata{IfName: ifName, IfIndex: ifIndex}})
+		}
+	}
+}
+
+// Close sends a termination request to the server, and closes the UDP listener.
+func (s *DHCP6) Close() error {
+	return s.Conn.Close()
+}
+
+// NewServer6 initializes and returns a new DHCPv6 Server object.
+// When addr is the unspecified address, the server joins the All_DHCP_Relay_Agents_and_Servers (ff02::1:2)
+// and All_DHCP_Servers (ff05::1:3) multicast groups on the ifname interface,
+// or on all multicast capable interfaces when ifname is empty.
+func NewServer6(ifname string, addr *net.UDPAddr, handler ...Handler6) (*DHCP6, error) {
+	s := &DHCP6{
+		Handlers: handler,
+		Logger:   logr.Discard(),
+	}
+
+	conn, err := server6.NewIPv6UDPConn(ifname, addr)
+	if err != nil {
+		return nil, err
+	}
+	if addr.IP == nil || addr.IP.IsUnspecified() {
+		if err := joinGroups(ipv6.NewPacketConn(conn), ifname, addr.Port); err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+	}
+	s.Conn = conn
+
+	return s, nil
+}
+
+// joinGroups joins the DHCPv6 server multicast groups on the ifname interface or on all multicast capable interfaces.
+func joinGroups(conn *ipv6.PacketConn, ifname string, port int) error {
+	var ifaces []net.Interface
+	if ifname != "" {
+		iface, err := net.InterfaceByName(ifname)
+		if err != nil {
+			return err
+		}
+		ifaces = append(ifaces, *iface)
+	} else {
+		all, err := net.Interfaces()
+		if err != nil {
+			return err
+		}
+		for _, iface := range all {
+			if iface.Flags&net.FlagUp != 0 && iface.Flags&net.FlagMulticast != 0 && iface.Flags&net.FlagLoopback == 0 {
+				ifaces = append(ifaces, iface)
+			}
+		}
+	}
+
+	var joined int
+	var errs error
+	for _, iface := range ifaces {
+		for _, g := range []net.IP{dhcpv6.AllDHCPRelayAgentsAndServers, dhcpv6.AllDHCPServers} {
+			if err := conn.JoinGroup(&iface, &net.UDPAddr{IP: g, Port: port}); err != nil {
+				errs = errors.Join(errs, fmt.Errorf("interface %s: %w", iface.Name, err))
+				continue
+			}
+			joined++
+		}
+	}
+	if joined == 0 {
+		return errors.Join(errors.New("unable to join any DHCPv6 multicast group"), errs)
+	}
+
+	return nil
+}
+
+// ServerID returns the DUID-LL that identifies the DHCPv6 server, DHCPv6 option 2.
+// It is built from the hardware address of the ifname interface,
+// or of the first non-loopback interface that has one when ifname is empty.
+func ServerID(ifname string) (dhcpv6.DUID, error) {
+	if ifname != "" {
+		iface, err := net.InterfaceByName(ifname)
+		if err != nil {
+			return nil, err
+		}
+		if len(iface.HardwareAddr) == 0 {
+			return nil, fmt.Errorf("interface %s has no hardware address", ifname)
+		}
+		return &dhcpv6.DUIDLL{HWType: iana.HWTypeEthernet, LinkLayerAddr: iface.HardwareAddr}, nil
+	}
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagLoopback == 0 && len(iface.HardwareAddr) > 0 {
+			return &dhcpv6.DUIDLL{HWType: iana.HWTypeEthernet, LinkLayerAddr: iface.HardwareAddr}, nil
+		}
+	}
+
+	return nil, errors.New("no interface with a hardware address found")
+}
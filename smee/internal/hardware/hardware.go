@@ -34,6 +34,9 @@ type Info struct {
 	OSIE          OSIE
 	PXELINUX      PXELINUX
 	RPI           RPI
+	HTTPBoot      HTTPBoot
+	// SwitchPort is true when the matched Hardware interface declares the switch port it is connected to.
+	SwitchPort bool
 }
 
 // OSIE or OS Installation Environment is the data about where the OSIE parts are located.
@@ -53,6 +56,14 @@ type PXELINUX struct {
 	Config string `json:"config,omitempty"`
 }
 
+// HTTPBoot represents the data used to boot UEFI HTTP Boot clients without iPXE.
+type HTTPBoot struct {
+	// Mode is the type of boot file, "grub" or "uki". UEFI HTTP Boot clients boot iPXE when it is empty.
+	Mode string
+	// BootFile overrides the default boot file of the mode and architecture.
+	BootFile string
+}
+
 // RPI represents the data needed to support RaspberryPi EEPROM firmware netbooting.
 type RPI struct {
 	SerialNum    string `json:"serialNum"`
@@ -97,6 +108,8 @@ func GetByMac(ctx context.Context, mac net.HardwareAddr, br BackendReader) (Info
 		OSIE:          OSIE(n.OSIE),
 		PXELINUX:      PXELINUX(n.PXELINUX),
 		RPI:           RPI(n.RPI),
+		HTTPBoot:      HTTPBoot(n.HTTPBoot),
+		SwitchPort:    hw.SwitchPort,
 	}, nil
 }
 
@@ -136,5 +149,7 @@ func GetByIP(ctx context.Context, ip net.IP, br BackendReader) (Info, error) {
 		OSIE:          OSIE(n.OSIE),
 		PXELINUX:      PXELINUX(n.PXELINUX),
 		RPI:           RPI(n.RPI),
+		HTTPBoot:      HTTPBoot(n.HTTPBoot),
+		SwitchPort:    hw.SwitchPort,
 	}, nil
 }
@@ -116,6 +116,7 @@ func TestGetByIP(t *testing.T) {
 		wantConfig       string
 		wantRPiSerial    string
 		wantKernelParams []string
+		wantSwitchPort   bool
 	}{
 		"nil backend": {
 			backend: nil,
@@ -133,6 +134,16 @@ func TestGetByIP(t *testing.T) {
 			wantRPiSerial:    "serial42",
 			wantKernelParams: []string{"quiet"},
 		},
+		"interface with a switch port": {
+			backend: &mockBackend{
+				hw: func() *tinkerbell.Hardware {
+					hw := validHardware("01:02:03:04:05:06", ip.String(), true, "", "", nil)
+					hw.Spec.Interfaces[0].SwitchPort = &tinkerbell.SwitchPort{CircuitID: "eth1/5"}
+					return hw
+				}(),
+			},
+			wantSwitchPort: true,
+		},
 	}
 
 	for name, tt := range tests {
@@ -156,6 +167,9 @@ func TestGetByIP(t *testing.T) {
 			if diff := cmp.Diff(info.OSIE.KernelParams, tt.wantKernelParams); diff != "" {
 				t.Fatalf("OSIE.KernelParams mismatch: %s", diff)
 			}
+			if info.SwitchPort != tt.wantSwitchPort {
+				t.Fatalf("SwitchPort = %v, want %v", info.SwitchPort, tt.wantSwitchPort)
+			}
 		})
 	}
 }
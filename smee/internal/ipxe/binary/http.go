@@ -85,8 +85,8 @@ func (h HTTPHandler) Handle(w http.ResponseWriter, req *http.Request) {
 	rel = strings.TrimPrefix(rel, "/")
 
 	host, port, _ := net.SplitHostPort(req.RemoteAddr)
-	// Client is kept for parity with the TFTP request; the routes enabled for
-	// HTTP (pxelinux, disk asset) key on the path, not the client address.
+	// Client is the address of the HTTP client. The HTTP Boot route looks up
+	// the Hardware of a machine matched by switch port by the client IP.
 	client := net.UDPAddr{IP: net.ParseIP(host)}
 	log := h.Log.WithValues("host", host, "port", port, "path", req.URL.Path, "rel", rel)
 
@@ -0,0 +1,126 @@
+package binary
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"path"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/tinkerbell/tinkerbell/smee/internal/dhcp"
+	"github.com/tinkerbell/tinkerbell/smee/internal/hardware"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// HTTPBootRoute handles UEFI HTTP Boot clients that boot a signed shim and
+// GRUB, or a Unified Kernel Image, instead of iPXE. Requests arrive as
+// "<MAC>/<file>": the DHCP server puts the client's MAC address in the boot
+// file URL, and shim and GRUB request their files relative to it.
+//
+// The route:
+//   - Looks up Hardware by the MAC address of the first path element, or by
+//     req.Client.IP when no Hardware has that MAC address and the Hardware of
+//     the client IP declares a switch port (eg. a Hardware matched by switch
+//     port rather than MAC).
+//   - If the Hardware is allowed to netboot and has an HTTP Boot mode, either
+//     serves the generated GRUB config for "grub.cfg" in the grub mode, or
+//     streams the rest of the path from Dir.
+//
+// Returns handled=false when there's no Hardware match, the Hardware is not
+// allowed to netboot or has no HTTP Boot mode, or the file does not exist.
+type HTTPBootRoute struct {
+	Log      logr.Logger
+	Resolver hardware.Resolver
+	// Dir is the directory that holds the shim, GRUB and UKI files.
+	Dir string
+	// GRUBConfig generates the grub.cfg that boots Hook on the Hardware.
+	GRUBConfig func(context.Context, hardware.Info) (string, error)
+}
+
+func (r HTTPBootRoute) Name() string { return "httpboot" }
+
+func (r HTTPBootRoute) TryServe(ctx context.Context, req Request, w io.ReaderFrom) (bool, error) {
+	if r.Dir == "" {
+		return false, nil
+	}
+	log := r.Log.WithValues("route", r.Name(), "filename", req.Filename, "client", req.Client)
+	span := trace.SpanFromContext(ctx)
+
+	prefix, rest, found := strings.Cut(req.Filename, "/")
+	if !found || rest == "" {
+		log.V(1).Info("request path does not have a MAC address prefix; skipping")
+		return false, nil
+	}
+
+	hw, err := r.lookup(ctx, prefix, req.Client.IP)
+	if err != nil {
+		// Expected fall-through (404), like the other Hardware-keyed routes.
+		log.V(1).Info("failed to get hardware; skipping", "err", err)
+		return false, nil
+	}
+	if !hw.AllowNetboot || hw.HTTPBoot.Mode == "" {
+		log.V(1).Info("hardware is not allowed to HTTP Boot; skipping", "allowNetboot", hw.AllowNetboot, "mode", hw.HTTPBoot.Mode)
+		return false, nil
+	}
+
+	// shim and GRUB look for grub.cfg in a few places relative to the boot
+	// file, so match on the file name rather than the full path.
+	if path.Base(rest) == "grub.cfg" && hw.HTTPBoot.Mode == "grub" && r.GRUBConfig != nil {
+		cfg, err := r.GRUBConfig(ctx, hw)
+		if err != nil {
+			log.Error(err, "failed to generate GRUB config")
+			span.SetStatus(codes.Error, err.Error())
+			return true, err
+		}
+		log.Info("serving generated GRUB config")
+		return serveTemplate(w, log, span, req.Filename, cfg)
+	}
+
+	// rest is client-supplied; openAsset confines it to Dir and rejects
+	// absolute paths and ".." traversal.
+	file, err := openAsset(r.Dir, rest)
+	if err != nil {
+		log.V(1).Info("asset not found on disk; skipping", "assetDir", r.Dir, "err", err)
+		return false, nil
+	}
+	defer func() {
+		if cerr := file.Close(); cerr != nil {
+			log.Error(cerr, "failed to close file", "assetPath", file.Name())
+		}
+	}()
+
+	bytesSent, err := w.ReadFrom(file)
+	if err != nil {
+		log.Error(err, "serving asset failed", "assetPath", file.Name(), "bytesSent", bytesSent)
+		span.SetStatus(codes.Error, err.Error())
+		return true, err
+	}
+	log.Info("asset served from disk", "assetPath", file.Name(), "bytesSent", bytesSent)
+	span.SetStatus(codes.Ok, req.Filename)
+	return true, nil
+}
+
+// lookup finds the Hardware by the MAC address of the path prefix.
+// A machine that Smee matched by the switch port of its DHCP relay agent has no Hardware object with its MAC address.
+// It is found by the client IP instead, when that Hardware interface declares a switch port.
+// The MAC address of the path prefix is kept when the Hardware is found by IP.
+func (r HTTPBootRoute) lookup(ctx context.Context, prefix string, ip net.IP) (hardware.Info, error) {
+	mac, err := net.ParseMAC(prefix)
+	if err != nil {
+		return hardware.Info{}, fmt.Errorf("path prefix is not a MAC address: %w", err)
+	}
+	hw, err := r.Resolver.ByMAC(ctx, mac)
+	if !dhcp.NotFound(err) {
+		return hw, err
+	}
+	byIP, ipErr := r.Resolver.ByIP(ctx, ip)
+	if ipErr != nil || !byIP.SwitchPort {
+		return hardware.Info{}, err
+	}
+	byIP.MACAddress = mac
+
+	return byIP, nil
+}
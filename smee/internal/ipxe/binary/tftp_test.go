@@ -30,8 +30,8 @@ func (c *captureWriter) ReadFrom(r io.Reader) (int64, error) {
 }
 
 // fakeResolver is a minimal hardware.Resolver for tests. ByMAC keys on
-// mac.String(); ByIP keys on ip.String(). If err is set, both methods
-// return it.
+// mac.String(); ByIP keys on ip.String(). Misses return a notFoundError.
+// If err is set, both methods return it.
 type fakeResolver struct {
 	byMAC map[string]hardware.Info
 	byIP  map[string]hardware.Info
@@ -45,7 +45,7 @@ func (f *fakeResolver) ByMAC(_ context.Context, m net.HardwareAddr) (hardware.In
 	if info, ok := f.byMAC[m.String()]; ok {
 		return info, nil
 	}
-	return hardware.Info{}, fmt.Errorf("mac %s not found", m.String())
+	return hardware.Info{}, notFoundError(fmt.Sprintf("mac %s not found", m.String()))
 }
 
 func (f *fakeResolver) ByIP(_ context.Context, ip net.IP) (hardware.Info, error) {
@@ -55,9 +55,16 @@ func (f *fakeResolver) ByIP(_ context.Context, ip net.IP) (hardware.Info, error)
 	if info, ok := f.byIP[ip.String()]; ok {
 		return info, nil
 	}
-	return hardware.Info{}, fmt.Errorf("ip %s not found", ip.String())
+	return hardware.Info{}, notFoundError(fmt.Sprintf("ip %s not found", ip.String()))
 }
 
+// notFoundError is a Hardware miss, like the errors of the backends.
+type notFoundError string
+
+func (e notFoundError) Error() string { return string(e) }
+
+func (notFoundError) NotFound() bool { return true }
+
 // stubRoute returns canned (handled, err) and remembers whether it was
 // called. Used to drive Router tests independent of the real routes.
 type stubRoute struct {
@@ -405,6 +412,148 @@ func TestRPiNetbootRoute(t *testing.T) {
 	}
 }
 
+// ---------- HTTPBootRoute ----------
+
+func TestHTTPBootRoute(t *testing.T) {
+	clientIP := net.ParseIP("192.168.1.50")
+	clientAddr := net.UDPAddr{IP: clientIP, Port: 12345}
+	mac := net.HardwareAddr{0x00, 0x00, 0x5e, 0x00, 0x53, 0x01}
+
+	grubHW := hardware.Info{AllowNetboot: true, HTTPBoot: hardware.HTTPBoot{Mode: "grub"}}
+	switchPortHW := hardware.Info{AllowNetboot: true, HTTPBoot: hardware.HTTPBoot{Mode: "grub"}, SwitchPort: true}
+	ukiHW := hardware.Info{AllowNetboot: true, HTTPBoot: hardware.HTTPBoot{Mode: "uki"}}
+
+	dir := t.TempDir()
+	const shimBody = "shim contents"
+	if err := os.WriteFile(filepath.Join(dir, "shimx64.efi"), []byte(shimBody), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	grubConfig := func(_ context.Context, hw hardware.Info) (string, error) {
+		return "grub config for " + hw.HTTPBoot.Mode, nil
+	}
+
+	tests := map[string]struct {
+		filename    string
+		dir         string
+		resolver    hardware.Resolver
+		wantHandled bool
+		wantBody    string
+	}{
+		"empty Dir passes through": {
+			filename: mac.String() + "/shimx64.efi",
+			resolver: &fakeResolver{byMAC: map[string]hardware.Info{mac.String(): grubHW}},
+		},
+		"path without a MAC address prefix passes through": {
+			filename: "shimx64.efi",
+			dir:      dir,
+			resolver: &fakeResolver{byMAC: map[string]hardware.Info{mac.String(): grubHW}},
+		},
+		"ByMAC and ByIP miss passes through": {
+			filename: mac.String() + "/shimx64.efi",
+			dir:      dir,
+			resolver: &fakeResolver{},
+		},
+		"hardware not allowed to netboot passes through": {
+			filename: mac.String() + "/shimx64.efi",
+			dir:      dir,
+			resolver: &fakeResolver{byMAC: map[string]hardware.Info{mac.String(): {HTTPBoot: hardware.HTTPBoot{Mode: "grub"}}}},
+		},
+		"hardware without a mode passes through": {
+			filename: mac.String() + "/shimx64.efi",
+			dir:      dir,
+			resolver: &fakeResolver{byMAC: map[string]hardware.Info{mac.String(): {AllowNetboot: true}}},
+		},
+		"boot file served from disk": {
+			filename:    mac.String() + "/shimx64.efi",
+			dir:         dir,
+			resolver:    &fakeResolver{byMAC: map[string]hardware.Info{mac.String(): grubHW}},
+			wantHandled: true,
+			wantBody:    shimBody,
+		},
+		"MAC miss looks up by client IP": {
+			filename:    mac.String() + "/shimx64.efi",
+			dir:         dir,
+			resolver:    &fakeResolver{byIP: map[string]hardware.Info{clientIP.String(): switchPortHW}},
+			wantHandled: true,
+			wantBody:    shimBody,
+		},
+		"MAC miss with client IP hardware without a switch port passes through": {
+			filename: mac.String() + "/shimx64.efi",
+			dir:      dir,
+			resolver: &fakeResolver{byIP: map[string]hardware.Info{clientIP.String(): grubHW}},
+		},
+		"backend error passes through": {
+			filename: mac.String() + "/shimx64.efi",
+			dir:      dir,
+			resolver: &fakeResolver{err: errors.New("backend unavailable"), byIP: map[string]hardware.Info{clientIP.String(): switchPortHW}},
+		},
+		"prefix that is not a MAC address passes through": {
+			filename: "EFI/BOOT/shimx64.efi",
+			dir:      dir,
+			resolver: &fakeResolver{byIP: map[string]hardware.Info{clientIP.String(): switchPortHW}},
+		},
+		"grub.cfg is generated": {
+			filename:    mac.String() + "/EFI/BOOT/grub.cfg",
+			dir:         dir,
+			resolver:    &fakeResolver{byMAC: map[string]hardware.Info{mac.String(): grubHW}},
+			wantHandled: true,
+			wantBody:    "grub config for grub",
+		},
+		"grub.cfg is not generated in the uki mode": {
+			filename: mac.String() + "/grub.cfg",
+			dir:      dir,
+			resolver: &fakeResolver{byMAC: map[string]hardware.Info{mac.String(): ukiHW}},
+		},
+		"file miss passes through": {
+			filename: mac.String() + "/uki-x64.efi",
+			dir:      dir,
+			resolver: &fakeResolver{byMAC: map[string]hardware.Info{mac.String(): ukiHW}},
+		},
+		"traversal is rejected": {
+			filename: mac.String() + "/../../../etc/passwd",
+			dir:      dir,
+			resolver: &fakeResolver{byMAC: map[string]hardware.Info{mac.String(): grubHW}},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := HTTPBootRoute{Log: logr.Discard(), Resolver: tt.resolver, Dir: tt.dir, GRUBConfig: grubConfig}
+			w := &captureWriter{}
+			handled, err := r.TryServe(context.Background(), Request{Filename: tt.filename, Client: clientAddr}, w)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if handled != tt.wantHandled {
+				t.Fatalf("handled=%v want=%v", handled, tt.wantHandled)
+			}
+			if tt.wantBody != "" && w.buf.String() != tt.wantBody {
+				t.Fatalf("body=%q want=%q", w.buf.String(), tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestHTTPBootRouteLookup(t *testing.T) {
+	clientIP := net.ParseIP("192.168.1.50")
+	mac := net.HardwareAddr{0x00, 0x00, 0x5e, 0x00, 0x53, 0x01}
+	r := HTTPBootRoute{
+		Log:      logr.Discard(),
+		Resolver: &fakeResolver{byIP: map[string]hardware.Info{clientIP.String(): {AllowNetboot: true, SwitchPort: true}}},
+	}
+
+	hw, err := r.lookup(context.Background(), mac.String(), clientIP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hw.MACAddress.String() != mac.String() {
+		t.Fatalf("MACAddress=%v want=%v", hw.MACAddress, mac)
+	}
+	if _, err := r.lookup(context.Background(), "EFI", clientIP); err == nil {
+		t.Fatal("expected an error for a prefix that is not a MAC address")
+	}
+}
+
 // ---------- DiskAssetRoute ----------
 
 func TestDiskAssetRoute(t *testing.T) {
@@ -200,6 +200,68 @@ echo Failed to load initrd
 imgfree
 exit
 
+:boot-error
+echo Failed to boot
+imgfree
+exit
+`,
+		},
+		"ipv6 syslog host": {
+			h: Hook{
+				Arch:              x8664Arch,
+				TinkGRPCAuthority: "[2001:db8::10]:42113",
+				TinkerbellTLS:     false,
+				WorkerID:          "3c:ec:ef:4c:4f:54",
+				SyslogHost:        "2001:db8::10",
+				DownloadURL:       "http://location:8080/to/kernel/and/initrd",
+				Facility:          "onprem",
+				ExtraKernelParams: []string{"tink_worker_image=quay.io/tinkerbell/tink-worker:v0.8.0", "tinkerbell=packet"},
+				HWAddr:            "3c:ec:ef:4c:4f:54",
+				Retries:           10,
+				RetryDelay:        3,
+				KernelName:        "vmlinuz-x86_64",
+				InitrdName:        "initramfs-x86_64",
+			},
+			script: HookScript,
+			want: `#!ipxe
+# An IPv6 syslog server is set with the syslog6 setting (https://ipxe.org/cfg/syslog6).
+set syslog6 2001:db8::10
+
+echo Loading the Tinkerbell Hook iPXE script...
+
+set arch x86_64
+set download-url http://location:8080/to/kernel/and/initrd
+set kernel vmlinuz-x86_64
+set initrd initramfs-x86_64
+set retries:int32 10
+set retry_delay:int32 3
+
+set idx:int32 0
+:retry_kernel
+kernel ${download-url}/${kernel} \
+facility=onprem syslog_host=2001:db8::10 grpc_authority=[2001:db8::10]:42113 tinkerbell_tls=false tinkerbell_insecure_tls=false worker_id=3c:ec:ef:4c:4f:54 hw_addr=3c:ec:ef:4c:4f:54 \
+modules=loop,squashfs,sd-mod,usb-storage intel_iommu=on iommu=pt initrd=${initrd} console=tty0 console=ttyS1,115200 tink_worker_image=quay.io/tinkerbell/tink-worker:v0.8.0 tinkerbell=packet && goto download_initrd || iseq ${idx} ${retries} && goto kernel-error || inc idx && echo retry in ${retry_delay} seconds ; sleep ${retry_delay} ; goto retry_kernel
+
+:download_initrd
+set idx:int32 0
+:retry_initrd
+initrd ${download-url}/${initrd} && goto boot || iseq ${idx} ${retries} && goto initrd-error || inc idx && echo retry in ${retry_delay} seconds ; sleep ${retry_delay} ; goto retry_initrd
+
+:boot
+set idx:int32 0
+:retry_boot
+boot || iseq ${idx} ${retries} && goto boot-error || inc idx && echo retry in ${retry_delay} seconds ; sleep ${retry_delay} ; goto retry_boot
+
+:kernel-error
+echo Failed to load kernel
+imgfree
+exit
+
+:initrd-error
+echo Failed to load initrd
+imgfree
+exit
+
 :boot-error
 echo Failed to boot
 imgfree
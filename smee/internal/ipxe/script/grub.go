@@ -0,0 +1,34 @@
+package script
+
+import (
+	"net/url"
+	"strings"
+)
+
+// HookGRUBConfig is the grub.cfg for loading Hook with GRUB.
+// It is used by UEFI HTTP Boot clients that boot a signed shim and GRUB instead of iPXE,
+// and passes Hook the same kernel parameters as HookScript.
+var HookGRUBConfig = `set timeout=0
+set default=0
+{{- if .TraceID }}
+echo "Debug TraceID: {{ .TraceID }}"
+{{- end }}
+
+menuentry "Tinkerbell Hook" {
+	echo "Loading the Tinkerbell Hook kernel..."
+	linux {{ .GRUBDownloadURL }}/{{ if .KernelName }}{{ .KernelName }}{{ else }}vmlinuz-{{ .Arch }}{{ end }} {{- if ne .VLANID "" }} vlan_id={{ .VLANID }} {{- end }} facility={{ .Facility }} syslog_host={{ .SyslogHost }} grpc_authority={{ .TinkGRPCAuthority }} tinkerbell_tls={{ .TinkerbellTLS }} tinkerbell_insecure_tls={{ .TinkerbellInsecureTLS }} worker_id={{ .WorkerID }} hw_addr={{ .HWAddr }} modules=loop,squashfs,sd-mod,usb-storage intel_iommu=on iommu=pt console=tty0 console=ttyS1,115200 {{- range .ExtraKernelParams}} {{.}} {{- end}}
+	echo "Loading the Tinkerbell Hook initrd..."
+	initrd {{ .GRUBDownloadURL }}/{{ if .InitrdName }}{{ .InitrdName }}{{ else }}initramfs-{{ .Arch }}{{ end }}
+}
+`
+
+// GRUBDownloadURL returns the DownloadURL as a GRUB network path, for example "(http,192.168.2.111:8080)/hook".
+// GRUB does not open URLs, files on a network server are addressed with the protocol and the server as the device.
+func (h Hook) GRUBDownloadURL() string {
+	u, err := url.Parse(h.DownloadURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return strings.TrimSuffix(h.DownloadURL, "/")
+	}
+
+	return "(" + u.Scheme + "," + u.Host + ")" + strings.TrimSuffix(u.EscapedPath(), "/")
+}
@@ -0,0 +1,80 @@
+package script
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tinkerbell/tinkerbell/smee/internal/hardware"
+)
+
+func TestGRUBConfig(t *testing.T) {
+	want := `set timeout=0
+set default=0
+
+menuentry "Tinkerbell Hook" {
+	echo "Loading the Tinkerbell Hook kernel..."
+	linux (http,127.1.1.1)/vmlinuz-x86_64 vlan_id=1234 facility=onprem syslog_host= grpc_authority=127.0.0.1:42113 tinkerbell_tls=false tinkerbell_insecure_tls=false worker_id=00:01:02:03:04:05 hw_addr=00:01:02:03:04:05 modules=loop,squashfs,sd-mod,usb-storage intel_iommu=on iommu=pt console=tty0 console=ttyS1,115200 global=1 perhw=1
+	echo "Loading the Tinkerbell Hook initrd..."
+	initrd (http,127.1.1.1)/initramfs-x86_64
+}
+`
+	h := &Handler{
+		OSIEURL:            "http://127.1.1.1",
+		TinkServerGRPCAddr: "127.0.0.1:42113",
+		KernelName:         "vmlinuz",
+		InitrdName:         "initramfs",
+		ExtraKernelParams:  []string{"global=1"},
+	}
+	hw := hardware.Info{
+		MACAddress: net.HardwareAddr{0x00, 0x01, 0x02, 0x03, 0x04, 0x05},
+		VLANID:     "1234",
+		Facility:   "onprem",
+		Arch:       x8664Arch,
+		OSIE:       hardware.OSIE{KernelParams: []string{"perhw=1"}},
+	}
+	got, err := h.GRUBConfig(context.Background(), hw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Log(got)
+		t.Fatal(diff)
+	}
+
+	// Per-Hardware OSIE settings are used the same way as in the iPXE script.
+	hw.OSIE.BaseURL = &url.URL{Scheme: "http", Host: "10.1.1.1:8080", Path: "/hook/"}
+	hw.OSIE.Kernel = "custom-kernel"
+	hw.OSIE.Initrd = "custom-initrd"
+	got, err = h.GRUBConfig(context.Background(), hw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{"linux (http,10.1.1.1:8080)/hook/custom-kernel ", "initrd (http,10.1.1.1:8080)/hook/custom-initrd\n"} {
+		if !strings.Contains(got, line) {
+			t.Errorf("expected %q in config, got:\n%s", line, got)
+		}
+	}
+}
+
+func TestGRUBDownloadURL(t *testing.T) {
+	tests := map[string]struct {
+		downloadURL string
+		want        string
+	}{
+		"host":           {downloadURL: "http://192.168.2.111", want: "(http,192.168.2.111)"},
+		"host and port":  {downloadURL: "http://192.168.2.111:8080/", want: "(http,192.168.2.111:8080)"},
+		"path":           {downloadURL: "http://192.168.2.111:8080/hook/", want: "(http,192.168.2.111:8080)/hook"},
+		"not a full URL": {downloadURL: "(tftp)/hook/", want: "(tftp)/hook"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := (Hook{DownloadURL: tt.downloadURL}).GRUBDownloadURL(); got != tt.want {
+				t.Fatalf("GRUBDownloadURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
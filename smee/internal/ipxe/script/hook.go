@@ -1,13 +1,20 @@
 package script
 
+import "net/netip"
+
 // HookScript is the default iPXE script for loading Hook.
 var HookScript = `#!ipxe
 
 {{- if .SyslogHost }}
+{{- if .SyslogIPv6 }}
+# An IPv6 syslog server is set with the syslog6 setting (https://ipxe.org/cfg/syslog6).
+set syslog6 {{ .SyslogHost }}
+{{- else }}
 # iPXE can only set the syslog server to an IP address, not a hostname (https://ipxe.org/cfg/syslog).
 # If target is an IP, save it directly; if not, resolve it via nslookup directly into the syslog variable.
 set check:ipv4 {{ .SyslogHost }} && set syslog {{ .SyslogHost }} || nslookup syslog {{ .SyslogHost }} || echo [WARN] Failed to resolve syslog host {{ .SyslogHost }}
 clear check
+{{- end }}
 {{- end}}
 
 echo Loading the Tinkerbell Hook iPXE script...
@@ -74,3 +81,9 @@ type Hook struct {
 	KernelName            string // name of the kernel file
 	InitrdName            string // name of the initrd file
 }
+
+// SyslogIPv6 reports whether the SyslogHost is an IPv6 address.
+func (h Hook) SyslogIPv6() bool {
+	addr, err := netip.ParseAddr(h.SyslogHost)
+	return err == nil && addr.Is6() && !addr.Is4In6()
+}
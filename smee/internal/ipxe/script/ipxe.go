@@ -10,6 +10,7 @@ import (
 
 	"github.com/go-logr/logr"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/tinkerbell/tinkerbell/smee/internal/dhcp"
 	"github.com/tinkerbell/tinkerbell/smee/internal/hardware"
 	"github.com/tinkerbell/tinkerbell/smee/internal/metric"
 	"go.opentelemetry.io/otel/attribute"
@@ -63,6 +64,14 @@ func (h *Handler) HandlerFunc() http.HandlerFunc {
 		// Try to get the MAC address from the URL path, if not available get the source IP address.
 		if ha, err := getMAC(r.URL.Path); err == nil {
 			hw, err := hardware.GetByMac(ctx, ha, h.Backend)
+			// A machine that Smee matched by the switch port of its DHCP relay agent has no Hardware object with its MAC address.
+			// It is found by the IP address it was given instead, when that Hardware interface declares a switch port.
+			if ip, ipErr := getIP(r.RemoteAddr); dhcp.NotFound(err) && ipErr == nil {
+				if byIP, ipErr := hardware.GetByIP(ctx, ip, h.Backend); ipErr == nil && byIP.SwitchPort {
+					byIP.MACAddress = ha
+					hw, err = byIP, nil
+				}
+			}
 			if err != nil && h.StaticIPXEEnabled {
 				h.Logger.Info("serving static ipxe script", "mac", ha.String(), "reasonForStaticScript", err)
 				h.serveStaticIPXEScript(w)
@@ -194,6 +203,17 @@ func (h *Handler) serveBootScript(ctx context.Context, w http.ResponseWriter, na
 }
 
 func (h *Handler) defaultScript(span trace.Span, hw hardware.Info) (string, error) {
+	return GenerateTemplate(h.hook(span, hw), HookScript)
+}
+
+// GRUBConfig returns the grub.cfg that loads Hook, for UEFI HTTP Boot clients that boot GRUB instead of iPXE.
+// It is generated from the same OSIE kernel, initrd and kernel parameters as the default iPXE script.
+func (h *Handler) GRUBConfig(ctx context.Context, hw hardware.Info) (string, error) {
+	return GenerateTemplate(h.hook(trace.SpanFromContext(ctx), hw), HookGRUBConfig)
+}
+
+// hook returns the values used to generate the boot configuration of Hook for the hardware.
+func (h *Handler) hook(span trace.Span, hw hardware.Info) Hook {
 	mac := hw.MACAddress
 	arch := hw.Arch
 	if arch == "" {
@@ -244,7 +264,7 @@ func (h *Handler) defaultScript(span trace.Span, hw hardware.Info) (string, erro
 		auto.TraceID = span.SpanContext().TraceID().String()
 	}
 
-	return GenerateTemplate(auto, HookScript)
+	return auto
 }
 
 // customScript returns the custom script or chain URL if defined in the hardware data otherwise an error.
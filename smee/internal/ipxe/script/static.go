@@ -4,10 +4,15 @@ package script
 // It is built to be generic enough for all hardware to use.
 var StaticScript = `#!ipxe
 {{- if .SyslogHost }}
+{{- if .SyslogIPv6 }}
+# An IPv6 syslog server is set with the syslog6 setting (https://ipxe.org/cfg/syslog6).
+set syslog6 {{ .SyslogHost }}
+{{- else }}
 # iPXE can only set the syslog server to an IP address, not a hostname (https://ipxe.org/cfg/syslog).
 # If target is an IP, save it directly; if not, resolve it via nslookup directly into the syslog variable.
 set check:ipv4 {{ .SyslogHost }} && set syslog {{ .SyslogHost }} || nslookup syslog {{ .SyslogHost }} || echo [WARN] Failed to resolve syslog host {{ .SyslogHost }}
 clear check
+{{- end }}
 {{- end}}
 echo Loading the static Tinkerbell iPXE script...
 
@@ -30,6 +30,11 @@ func TestStartReceiver(t *testing.T) {
 			parsers: 3,
 			wantErr: false,
 		},
+		"valid ipv6 address": {
+			laddr:   "[::1]:0",
+			parsers: 1,
+			wantErr: false,
+		},
 		"invalid address": {
 			laddr:     "invalid-address",
 			parsers:   1,
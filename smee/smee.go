@@ -5,6 +5,7 @@ import (
 	"crypto/tls"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"net/netip"
@@ -12,6 +13,7 @@ import (
 	"path"
 	"reflect"
 	"strings"
+	"sync"
 	"time"
 
 	"dario.cat/mergo"
@@ -25,6 +27,8 @@ import (
 	"github.com/tinkerbell/tinkerbell/pkg/data"
 	"github.com/tinkerbell/tinkerbell/smee/internal/dhcp/handler/proxy"
 	"github.com/tinkerbell/tinkerbell/smee/internal/dhcp/handler/reservation"
+	"github.com/tinkerbell/tinkerbell/smee/internal/dhcp/history"
+	"github.com/tinkerbell/tinkerbell/smee/internal/dhcp/pool"
 	"github.com/tinkerbell/tinkerbell/smee/internal/dhcp/server"
 	"github.com/tinkerbell/tinkerbell/smee/internal/hardware"
 	"github.com/tinkerbell/tinkerbell/smee/internal/ipxe/binary"
@@ -62,12 +66,17 @@ const (
 	DefaultTFFTPSinglePort   = true
 	DefaultTFFTPTimeout      = 10 * time.Second
 	DefaultDHCPPort          = 67
+	DefaultDHCPv6Port        = 547
 	DefaultSyslogPort        = 514
 	DefaultTinkServerPort    = 42113
 
+	// DefaultDHCPTransactionHistorySize is the default number of DHCP transactions kept in memory.
+	DefaultDHCPTransactionHistorySize = history.DefaultSize
+
 	IPXEBinaryURI = "/ipxe/binary/"
 	IPXEScriptURI = "/ipxe/script/"
 	ISOURI        = "/iso/"
+	HTTPBootURI   = "/httpboot/"
 )
 
 type DHCPMode string
@@ -96,6 +105,10 @@ type Config struct {
 	Backend BackendReader
 	// DHCP is the configuration for the DHCP service.
 	DHCP DHCP
+	// DHCPv6 is the configuration for the DHCPv6 service.
+	DHCPv6 DHCPv6
+	// HTTPBoot is the configuration for booting UEFI HTTP Boot clients with a signed shim and GRUB or a UKI.
+	HTTPBoot HTTPBoot
 	// IPXE is the configuration for the iPXE service.
 	IPXE IPXE
 	// ISO is the configuration for the ISO service.
@@ -113,6 +126,15 @@ type Config struct {
 	TinkServer TinkServer
 	// TLS is the configuration for TLS.
 	TLS TLS
+
+	// transactions holds the DHCP transaction history shared by the DHCP handlers and the HTTP handler.
+	transactions *dhcpTransactions
+}
+
+// dhcpTransactions creates the DHCP transaction history on first use, after the configured size is known.
+type dhcpTransactions struct {
+	once sync.Once
+	ring *history.Ring
 }
 
 type Syslog struct {
@@ -152,6 +174,15 @@ type PXEHTTP struct {
 	PathPrefix string
 }
 
+// HTTPBoot configures booting UEFI HTTP Boot clients without iPXE.
+// Hardware with spec.interfaces[].netboot.httpBoot set are offered a signed shim
+// (which loads GRUB) or a Unified Kernel Image, served from AssetDir at HTTPBootURI.
+type HTTPBoot struct {
+	// AssetDir is the directory with the shim, GRUB and UKI files.
+	// HTTP Boot is disabled when empty.
+	AssetDir string
+}
+
 type IPXE struct {
 	EmbeddedScriptPatch string
 	HTTPBinaryServer    IPXEHTTPBinaryServer
@@ -204,6 +235,144 @@ type DHCP struct {
 	IPXEHTTPBinaryURL *url.URL
 	// IPXEHTTPScript is the URL to the iPXE script to use.
 	IPXEHTTPScript IPXEHTTPScript
+	// Pools are dynamic address pools for machines that do not have a Hardware object.
+	// They are only used in reservation mode. Machines that get an address from a pool are served the static iPXE script.
+	Pools DHCPPools
+	// LeaseFile is the path of the bbolt database file that stores the leases of the dynamic address pools.
+	// When empty, leases are kept in memory and are lost when Smee restarts.
+	LeaseFile string
+	// TransactionHistorySize is the number of recent DHCP transactions kept in memory for troubleshooting.
+	// 0 disables the transaction history.
+	TransactionHistorySize int
+}
+
+// DHCPPool is a dynamic address pool of a subnet.
+type DHCPPool struct {
+	// Subnet is the network of the pool. Its mask is sent in DHCP option 1.
+	Subnet netip.Prefix
+	// Start is the first address of the pool.
+	Start netip.Addr
+	// End is the last address of the pool.
+	End netip.Addr
+	// Gateway is the default gateway, DHCP option 3.
+	Gateway netip.Addr
+	// NameServers are the DNS servers, DHCP option 6.
+	NameServers []netip.Addr
+	// DomainName is the domain name, DHCP option 15.
+	DomainName string
+	// LeaseTime is the lease time, DHCP option 51.
+	LeaseTime time.Duration
+}
+
+// String returns the pool in the format accepted by DHCPPools.Set.
+func (p DHCPPool) String() string {
+	fields := []string{"subnet=" + p.Subnet.String(), fmt.Sprintf("range=%v-%v", p.Start, p.End)}
+	if p.Gateway.IsValid() {
+		fields = append(fields, "gateway="+p.Gateway.String())
+	}
+	for _, ns := range p.NameServers {
+		fields = append(fields, "dns="+ns.String())
+	}
+	if p.DomainName != "" {
+		fields = append(fields, "domain="+p.DomainName)
+	}
+	if p.LeaseTime > 0 {
+		fields = append(fields, "lease-time="+p.LeaseTime.String())
+	}
+
+	return strings.Join(fields, ",")
+}
+
+// DHCPPools is a list of dynamic address pools.
+type DHCPPools []DHCPPool
+
+func (d DHCPPools) String() string {
+	pools := make([]string, 0, len(d))
+	for _, p := range d {
+		pools = append(pools, p.String())
+	}
+
+	return strings.Join(pools, ";")
+}
+
+// Set appends the pools of s. Pools are separated by a semicolon and have comma separated key=value fields.
+// For example: "subnet=192.168.2.0/24,range=192.168.2.100-192.168.2.200,gateway=192.168.2.1,dns=1.1.1.1,lease-time=1h".
+// The subnet and range fields are required, the dns field can be repeated.
+func (d *DHCPPools) Set(s string) error {
+	for _, ps := range strings.Split(s, ";") {
+		if strings.TrimSpace(ps) == "" {
+			continue
+		}
+		p, err := parseDHCPPool(ps)
+		if err != nil {
+			return err
+		}
+		*d = append(*d, p)
+	}
+
+	return nil
+}
+
+func (d *DHCPPools) Type() string {
+	return "dhcp-pools"
+}
+
+func parseDHCPPool(s string) (DHCPPool, error) {
+	var p DHCPPool
+	for _, field := range strings.Split(s, ",") {
+		k, v, ok := strings.Cut(strings.TrimSpace(field), "=")
+		if !ok {
+			return DHCPPool{}, fmt.Errorf("invalid DHCP pool field: %q, must be key=value", field)
+		}
+		var err error
+		switch k {
+		case "subnet":
+			p.Subnet, err = netip.ParsePrefix(v)
+		case "range":
+			start, end, found := strings.Cut(v, "-")
+			if !found {
+				return DHCPPool{}, fmt.Errorf("invalid DHCP pool range: %q, must be start-end", v)
+			}
+			if p.Start, err = netip.ParseAddr(start); err == nil {
+				p.End, err = netip.ParseAddr(end)
+			}
+		case "gateway":
+			p.Gateway, err = netip.ParseAddr(v)
+		case "dns":
+			var ns netip.Addr
+			if ns, err = netip.ParseAddr(v); err == nil {
+				p.NameServers = append(p.NameServers, ns)
+			}
+		case "domain":
+			p.DomainName = v
+		case "lease-time":
+			p.LeaseTime, err = time.ParseDuration(v)
+		default:
+			return DHCPPool{}, fmt.Errorf("unknown DHCP pool field: %q, must be one of [subnet, range, gateway, dns, domain, lease-time]", k)
+		}
+		if err != nil {
+			return DHCPPool{}, fmt.Errorf("invalid DHCP pool %s: %w", k, err)
+		}
+	}
+	if !p.Subnet.IsValid() || !p.Start.IsValid() {
+		return DHCPPool{}, fmt.Errorf("invalid DHCP pool: %q, subnet and range are required", s)
+	}
+
+	return p, nil
+}
+
+// DHCPv6 is the configuration for the DHCPv6 server.
+// It shares the Mode, BindInterface, EnableNetbootOptions, TFTPPort and iPXE URLs of the DHCP configuration.
+type DHCPv6 struct {
+	// Enabled configures whether the DHCPv6 server is enabled. It is independent of DHCP.Enabled.
+	Enabled bool
+	// BindAddr is the local address to which to bind the DHCPv6 server and listen for DHCPv6 packets.
+	// When it is the unspecified address (::), the server joins the DHCPv6 server multicast groups.
+	BindAddr netip.Addr
+	BindPort uint16
+	// PublicIP is the IPv6 address of Smee used in the TFTP and HTTP boot file URLs (DHCPv6 option 59).
+	// It replaces the IP address host of the DHCP iPXE binary and script URLs, DNS name hosts are kept.
+	PublicIP netip.Addr
 }
 
 type IPXEHTTPBinary struct {
@@ -268,7 +437,13 @@ func NewConfig(c Config) *Config {
 				},
 				InjectMacAddress: true,
 			},
-			TFTPPort: DefaultTFFTPPort,
+			TFTPPort:               DefaultTFFTPPort,
+			TransactionHistorySize: DefaultDHCPTransactionHistorySize,
+		},
+		DHCPv6: DHCPv6{
+			Enabled:  false,
+			BindAddr: netip.IPv6Unspecified(),
+			BindPort: DefaultDHCPv6Port,
 		},
 		IPXE: IPXE{
 			EmbeddedScriptPatch: "",
@@ -320,6 +495,7 @@ func NewConfig(c Config) *Config {
 	if err := mergo.Merge(defaults, &c, mergo.WithTransformers(&c)); err != nil {
 		panic(fmt.Sprintf("failed to merge config: %v", err))
 	}
+	defaults.transactions = &dhcpTransactions{}
 
 	return defaults
 }
@@ -333,6 +509,34 @@ func (c *Config) InitMetrics() {
 	metric.Init()
 }
 
+// DHCPTransactions returns the recent DHCP transactions, newest first.
+// When MAC addresses are given, only the transactions of these MAC addresses are returned.
+func (c *Config) DHCPTransactions(macs ...string) []data.DHCPTransaction {
+	return c.dhcpHistory().List(macs...)
+}
+
+// DHCPTransactionsHandler returns an http.Handler that serves the recent DHCP transactions as JSON.
+// The mac query parameter filters the transactions by MAC address. It returns nil when the transaction history is disabled.
+func (c *Config) DHCPTransactionsHandler() http.Handler {
+	if r := c.dhcpHistory(); r != nil {
+		return r
+	}
+
+	return nil
+}
+
+// dhcpHistory returns the DHCP transaction history or nil when it is disabled.
+func (c *Config) dhcpHistory() *history.Ring {
+	if c.transactions == nil {
+		return nil
+	}
+	c.transactions.once.Do(func() {
+		c.transactions.ring = history.NewRing(c.DHCP.TransactionHistorySize)
+	})
+
+	return c.transactions.ring
+}
+
 // BinaryHandler returns an http.Handler that serves iPXE binaries.
 // Returns nil if the iPXE HTTP binary server is disabled.
 func (c *Config) BinaryHandler(log logr.Logger) http.Handler {
@@ -371,7 +575,36 @@ func (c *Config) ScriptHandler(log logr.Logger) http.Handler {
 	if !c.IPXE.HTTPScriptServer.Enabled {
 		return nil
 	}
-	jh := script.Handler{
+	jh := c.scriptHandler(log)
+	return jh.HandlerFunc()
+}
+
+// HTTPBootHandler returns an http.Handler that serves the shim, GRUB and UKI
+// files of c.HTTPBoot.AssetDir to UEFI HTTP Boot clients, and the grub.cfg that
+// boots Hook, generated from the same settings as the iPXE script.
+// Returns nil if HTTP Boot is disabled.
+func (c *Config) HTTPBootHandler(log logr.Logger) http.Handler {
+	if c.HTTPBoot.AssetDir == "" {
+		return nil
+	}
+	jh := c.scriptHandler(log)
+	router := binary.Router{
+		Log: log,
+		Routes: []binary.Route{
+			binary.HTTPBootRoute{
+				Log:        log,
+				Resolver:   hardware.BackendResolver{Backend: c.Backend},
+				Dir:        c.HTTPBoot.AssetDir,
+				GRUBConfig: jh.GRUBConfig,
+			},
+		},
+	}
+	return http.HandlerFunc(binary.NewHTTPHandler(log, router, HTTPBootURI).Handle)
+}
+
+// scriptHandler returns the script.Handler that generates the iPXE script and GRUB config for Hook.
+func (c *Config) scriptHandler(log logr.Logger) *script.Handler {
+	return &script.Handler{
 		Logger:                log,
 		Backend:               c.Backend,
 		OSIEURL:               c.IPXE.HTTPScriptServer.OSIEURL.String(),
@@ -382,11 +615,10 @@ func (c *Config) ScriptHandler(log logr.Logger) http.Handler {
 		TinkServerGRPCAddr:    c.TinkServer.AddrPort,
 		IPXEScriptRetries:     c.IPXE.HTTPScriptServer.Retries,
 		IPXEScriptRetryDelay:  c.IPXE.HTTPScriptServer.RetryDelay,
-		StaticIPXEEnabled:     (c.DHCP.Mode == DHCPModeAutoProxy),
+		StaticIPXEEnabled:     c.DHCP.Mode == DHCPModeAutoProxy || (c.DHCP.Mode == DHCPModeReservation && len(c.DHCP.Pools) > 0),
 		KernelName:            c.IPXE.HTTPScriptServer.KernelName,
 		InitrdName:            c.IPXE.HTTPScriptServer.InitrdName,
 	}
-	return jh.HandlerFunc()
 }
 
 // syslogHost returns the host used for the syslog_host kernel parameter in iPXE scripts.
@@ -456,7 +688,7 @@ func (c *Config) Start(ctx context.Context, log logr.Logger) error {
 		return errors.New("no backend provided")
 	}
 	if c.noServicesEnabled() {
-		return errors.New("all Smee services are disabled (DHCP, TFTP, syslog, iPXE binary, iPXE script, ISO)")
+		return errors.New("all Smee services are disabled (DHCP, DHCPv6, TFTP, syslog, iPXE binary, iPXE script, ISO)")
 	}
 
 	g, ctx := errgroup.WithContext(ctx)
@@ -519,12 +751,35 @@ func (c *Config) Start(ctx context.Context, log logr.Logger) error {
 				return err
 			}
 			defer conn.Close()
+			defer closeLeaseStore(dh)
 			ds := &server.DHCP{Logger: log, Conn: conn, Handlers: []server.Handler{dh}}
 
 			return ds.Serve(ctx)
 		})
 	}
 
+	// dhcpv6 serving
+	if c.DHCPv6.Enabled {
+		dh, err := c.dhcp6Handler(log)
+		if err != nil {
+			return fmt.Errorf("failed to create dhcpv6 listener: %w", err)
+		}
+		dhcpAddrPort := netip.AddrPortFrom(c.DHCPv6.BindAddr, c.DHCPv6.BindPort)
+		if !dhcpAddrPort.IsValid() || !dhcpAddrPort.Addr().Is6() {
+			return fmt.Errorf("invalid DHCPv6 bind address: IP: %v, Port: %v", dhcpAddrPort.Addr(), dhcpAddrPort.Port())
+		}
+		log.Info("starting dhcpv6 server", "bindAddr", dhcpAddrPort)
+		g.Go(func() error {
+			ds, err := server.NewServer6(c.DHCP.BindInterface, net.UDPAddrFromAddrPort(dhcpAddrPort), dh)
+			if err != nil {
+				return err
+			}
+			ds.Logger = log
+
+			return ds.Serve(ctx)
+		})
+	}
+
 	if err := g.Wait(); err != nil && !errors.Is(err, context.Canceled) {
 		return fmt.Errorf("failed running all Smee services: %w", err)
 	}
@@ -532,6 +787,46 @@ func (c *Config) Start(ctx context.Context, log logr.Logger) error {
 	return nil
 }
 
+// dhcpPools returns the allocator of the dynamic address pools, or nil when no pools are configured.
+func (c *Config) dhcpPools() (*pool.Allocator, error) {
+	if len(c.DHCP.Pools) == 0 {
+		return nil, nil
+	}
+	pools := make([]pool.Pool, 0, len(c.DHCP.Pools))
+	for _, p := range c.DHCP.Pools {
+		pools = append(pools, pool.Pool(p))
+	}
+
+	var store pool.Store = pool.NewMemoryStore()
+	if c.DHCP.LeaseFile != "" {
+		bs, err := pool.NewBoltStore(c.DHCP.LeaseFile)
+		if err != nil {
+			return nil, err
+		}
+		store = bs
+	}
+	a, err := pool.NewAllocator(store, pools...)
+	if err != nil {
+		closeStore(store)
+		return nil, fmt.Errorf("invalid DHCP pools: %w", err)
+	}
+
+	return a, nil
+}
+
+// closeLeaseStore closes the lease file of the dynamic address pools of a reservation handler.
+func closeLeaseStore(h server.Handler) {
+	if rh, ok := h.(*reservation.Handler); ok && rh.Pools != nil {
+		closeStore(rh.Pools.Store)
+	}
+}
+
+func closeStore(s pool.Store) {
+	if c, ok := s.(io.Closer); ok {
+		_ = c.Close()
+	}
+}
+
 func (c *Config) dhcpHandler(log logr.Logger) (server.Handler, error) {
 	// 1. create the handler
 	// 2. create the backend
@@ -542,6 +837,13 @@ func (c *Config) dhcpHandler(log logr.Logger) (server.Handler, error) {
 	}
 
 	httpBinaryURL := *c.DHCP.IPXEHTTPBinaryURL
+	// HTTP Boot files are served by the same HTTP server as the iPXE binaries.
+	var httpBootServer *url.URL
+	if c.HTTPBoot.AssetDir != "" {
+		u := httpBinaryURL
+		u.Path, u.RawPath = HTTPBootURI, ""
+		httpBootServer = &u
+	}
 
 	httpScriptURL := c.DHCP.IPXEHTTPScript.URL
 
@@ -556,15 +858,20 @@ func (c *Config) dhcpHandler(log logr.Logger) (server.Handler, error) {
 	}
 	if c.DHCP.IPXEHTTPScript.InjectMacAddress {
 		ipxeScript = func(d *dhcpv4.DHCPv4) *url.URL {
-			u := *httpScriptURL
-			p := path.Base(u.Path)
-			u.Path = path.Join(path.Dir(u.Path), d.ClientHWAddr.String(), p)
-			return &u
+			return injectMAC(httpScriptURL, d.ClientHWAddr)
 		}
 	}
 
+	if len(c.DHCP.Pools) > 0 && c.DHCP.Mode != DHCPModeReservation {
+		log.Info("DHCP pools are only used in reservation mode, ignoring them", "mode", c.DHCP.Mode)
+	}
+
 	switch c.DHCP.Mode {
 	case DHCPModeReservation:
+		pools, err := c.dhcpPools()
+		if err != nil {
+			return nil, err
+		}
 		dh := &reservation.Handler{
 			Backend: c.Backend,
 			IPAddr:  c.DHCP.IPForPacket,
@@ -576,9 +883,12 @@ func (c *Config) dhcpHandler(log logr.Logger) (server.Handler, error) {
 				Enabled:             c.DHCP.EnableNetbootOptions,
 				InjectMacAddrFormat: c.IPXE.IPXEBinary.InjectMacAddrFormat,
 				IPXEArchMapping:     c.IPXE.IPXEBinary.IPXEArchMapping,
+				HTTPBootServer:      httpBootServer,
 			},
 			OTELEnabled: true,
 			SyslogAddr:  c.DHCP.SyslogIP,
+			Pools:       pools,
+			History:     c.dhcpHistory(),
 		}
 		return dh, nil
 	case DHCPModeProxy:
@@ -593,9 +903,11 @@ func (c *Config) dhcpHandler(log logr.Logger) (server.Handler, error) {
 				Enabled:             c.DHCP.EnableNetbootOptions,
 				InjectMacAddrFormat: c.IPXE.IPXEBinary.InjectMacAddrFormat,
 				IPXEArchMapping:     c.IPXE.IPXEBinary.IPXEArchMapping,
+				HTTPBootServer:      httpBootServer,
 			},
 			OTELEnabled:      true,
 			AutoProxyEnabled: false,
+			History:          c.dhcpHistory(),
 		}
 		return dh, nil
 	case DHCPModeAutoProxy:
@@ -610,9 +922,11 @@ func (c *Config) dhcpHandler(log logr.Logger) (server.Handler, error) {
 				Enabled:             c.DHCP.EnableNetbootOptions,
 				InjectMacAddrFormat: c.IPXE.IPXEBinary.InjectMacAddrFormat,
 				IPXEArchMapping:     c.IPXE.IPXEBinary.IPXEArchMapping,
+				HTTPBootServer:      httpBootServer,
 			},
 			OTELEnabled:      true,
 			AutoProxyEnabled: true,
+			History:          c.dhcpHistory(),
 		}
 		return dh, nil
 	}
@@ -620,6 +934,87 @@ func (c *Config) dhcpHandler(log logr.Logger) (server.Handler, error) {
 	return nil, errors.New("invalid dhcp mode")
 }
 
+func (c *Config) dhcp6Handler(log logr.Logger) (server.Handler6, error) {
+	if !c.DHCPv6.PublicIP.Is6() || c.DHCPv6.PublicIP.Is4In6() {
+		return nil, fmt.Errorf("invalid DHCPv6 public IP: %v, must be an IPv6 address", c.DHCPv6.PublicIP)
+	}
+	serverID, err := server.ServerID(c.DHCP.BindInterface)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create DHCPv6 server ID: %w", err)
+	}
+	if c.DHCP.IPXEHTTPBinaryURL == nil || c.DHCP.IPXEHTTPScript.URL == nil {
+		return nil, errors.New("http ipxe binary and script urls are required")
+	}
+	tftp := netip.AddrPortFrom(c.DHCPv6.PublicIP, c.DHCP.TFTPPort)
+	httpBinaryURL := withIPv6Host(c.DHCP.IPXEHTTPBinaryURL, c.DHCPv6.PublicIP)
+	httpScriptURL := withIPv6Host(c.DHCP.IPXEHTTPScript.URL, c.DHCPv6.PublicIP)
+	ipxeScript := func(net.HardwareAddr) *url.URL {
+		return httpScriptURL
+	}
+	if c.DHCP.IPXEHTTPScript.InjectMacAddress {
+		ipxeScript = func(mac net.HardwareAddr) *url.URL {
+			return injectMAC(httpScriptURL, mac)
+		}
+	}
+
+	switch c.DHCP.Mode {
+	case DHCPModeReservation:
+		return &reservation.Handler6{
+			Backend:  c.Backend,
+			ServerID: serverID,
+			Log:      log,
+			Netboot: reservation.Netboot6{
+				IPXEBinServerTFTP:   tftp,
+				IPXEBinServerHTTP:   httpBinaryURL,
+				IPXEScriptURL:       ipxeScript,
+				Enabled:             c.DHCP.EnableNetbootOptions,
+				InjectMacAddrFormat: c.IPXE.IPXEBinary.InjectMacAddrFormat,
+				IPXEArchMapping:     c.IPXE.IPXEBinary.IPXEArchMapping,
+			},
+			OTELEnabled: true,
+		}, nil
+	case DHCPModeProxy, DHCPModeAutoProxy:
+		return &proxy.Handler6{
+			Backend:  c.Backend,
+			ServerID: serverID,
+			Log:      log,
+			Netboot: proxy.Netboot6{
+				IPXEBinServerTFTP:   tftp,
+				IPXEBinServerHTTP:   httpBinaryURL,
+				IPXEScriptURL:       ipxeScript,
+				Enabled:             c.DHCP.EnableNetbootOptions,
+				InjectMacAddrFormat: c.IPXE.IPXEBinary.InjectMacAddrFormat,
+				IPXEArchMapping:     c.IPXE.IPXEBinary.IPXEArchMapping,
+			},
+			AutoProxyEnabled: c.DHCP.Mode == DHCPModeAutoProxy,
+		}, nil
+	}
+
+	return nil, errors.New("invalid dhcp mode")
+}
+
+// injectMAC returns a copy of the iPXE script URL with the mac address prepended to the file name.
+func injectMAC(u *url.URL, mac net.HardwareAddr) *url.URL {
+	v := *u
+	p := path.Base(v.Path)
+	v.Path = path.Join(path.Dir(v.Path), mac.String(), p)
+	return &v
+}
+
+// withIPv6Host returns a copy of u with an empty or IP address host replaced by the IPv6 address, keeping the port.
+// A DNS name host is kept, the name can resolve to an IPv6 address.
+func withIPv6Host(u *url.URL, ip netip.Addr) *url.URL {
+	v := *u
+	if h := v.Hostname(); h != "" && net.ParseIP(h) == nil {
+		return &v
+	}
+	v.Host = "[" + ip.String() + "]"
+	if port := u.Port(); port != "" {
+		v.Host = net.JoinHostPort(ip.String(), port)
+	}
+	return &v
+}
+
 // Transformer for merging the netip.IPPort and logr.Logger structs.
 func (c *Config) Transformer(typ reflect.Type) func(dst, src reflect.Value) error {
 	var zeroUint16 uint16
@@ -692,5 +1087,5 @@ func (c *Config) Transformer(typ reflect.Type) func(dst, src reflect.Value) erro
 }
 
 func (c *Config) noServicesEnabled() bool {
-	return !c.DHCP.Enabled && !c.TFTP.Enabled && !c.Syslog.Enabled && !c.ISO.Enabled && !c.IPXE.HTTPBinaryServer.Enabled && !c.IPXE.HTTPScriptServer.Enabled && !c.PXEHTTP.Enabled
+	return !c.DHCP.Enabled && !c.DHCPv6.Enabled && !c.TFTP.Enabled && !c.Syslog.Enabled && !c.ISO.Enabled && !c.IPXE.HTTPBinaryServer.Enabled && !c.IPXE.HTTPScriptServer.Enabled && !c.PXEHTTP.Enabled && c.HTTPBoot.AssetDir == ""
 }
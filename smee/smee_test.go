@@ -4,10 +4,12 @@ import (
 	"context"
 	"net"
 	"net/netip"
+	"net/url"
 	"testing"
 	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
 )
 
 // TestConfig_syslogHost verifies that a configured SyslogFQDN takes precedence over the DHCP
@@ -89,3 +91,94 @@ func TestRunSyslogServer_startError(t *testing.T) {
 		t.Error("runSyslogServer() expected error for invalid bind address, got nil")
 	}
 }
+
+func TestWithIPv6Host(t *testing.T) {
+	ip := netip.MustParseAddr("2001:db8::10")
+	tests := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{name: "no host", url: "http:///ipxe/binary/", want: "http://[2001:db8::10]/ipxe/binary/"},
+		{name: "ipv4 host and port", url: "http://192.168.2.10:7171/ipxe/script/auto.ipxe", want: "http://[2001:db8::10]:7171/ipxe/script/auto.ipxe"},
+		{name: "dns host kept", url: "http://tinkerbell.example.com:7171/ipxe/binary/", want: "http://tinkerbell.example.com:7171/ipxe/binary/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := url.Parse(tt.url)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := withIPv6Host(u, ip).String(); got != tt.want {
+				t.Errorf("withIPv6Host() = %q, want %q", got, tt.want)
+			}
+			if u.String() != tt.url {
+				t.Errorf("withIPv6Host() modified the url: %q", u.String())
+			}
+		})
+	}
+}
+
+func TestDHCPPools_Set(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   []string
+		want    DHCPPools
+		wantErr bool
+	}{
+		{
+			name:  "all fields",
+			input: []string{"subnet=192.168.2.0/24,range=192.168.2.100-192.168.2.200,gateway=192.168.2.1,dns=1.1.1.1,dns=8.8.8.8,domain=example.com,lease-time=1h"},
+			want: DHCPPools{{
+				Subnet:      netip.MustParsePrefix("192.168.2.0/24"),
+				Start:       netip.MustParseAddr("192.168.2.100"),
+				End:         netip.MustParseAddr("192.168.2.200"),
+				Gateway:     netip.MustParseAddr("192.168.2.1"),
+				NameServers: []netip.Addr{netip.MustParseAddr("1.1.1.1"), netip.MustParseAddr("8.8.8.8")},
+				DomainName:  "example.com",
+				LeaseTime:   time.Hour,
+			}},
+		},
+		{
+			name:  "pools separated by a semicolon and repeated flags",
+			input: []string{"subnet=10.0.0.0/24,range=10.0.0.10-10.0.0.20;subnet=10.0.1.0/24,range=10.0.1.10-10.0.1.20", "subnet=10.0.2.0/24,range=10.0.2.10-10.0.2.20"},
+			want: DHCPPools{
+				{Subnet: netip.MustParsePrefix("10.0.0.0/24"), Start: netip.MustParseAddr("10.0.0.10"), End: netip.MustParseAddr("10.0.0.20")},
+				{Subnet: netip.MustParsePrefix("10.0.1.0/24"), Start: netip.MustParseAddr("10.0.1.10"), End: netip.MustParseAddr("10.0.1.20")},
+				{Subnet: netip.MustParsePrefix("10.0.2.0/24"), Start: netip.MustParseAddr("10.0.2.10"), End: netip.MustParseAddr("10.0.2.20")},
+			},
+		},
+		{name: "missing range", input: []string{"subnet=10.0.0.0/24"}, wantErr: true},
+		{name: "invalid range", input: []string{"subnet=10.0.0.0/24,range=10.0.0.10"}, wantErr: true},
+		{name: "unknown field", input: []string{"subnet=10.0.0.0/24,range=10.0.0.10-10.0.0.20,ntp=10.0.0.1"}, wantErr: true},
+		{name: "invalid lease time", input: []string{"subnet=10.0.0.0/24,range=10.0.0.10-10.0.0.20,lease-time=1"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got DHCPPools
+			var err error
+			for _, in := range tt.input {
+				if err = got.Set(in); err != nil {
+					break
+				}
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Set() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if diff := cmp.Diff(tt.want, got, cmp.Comparer(func(a, b netip.Addr) bool { return a == b }), cmp.Comparer(func(a, b netip.Prefix) bool { return a == b })); diff != "" {
+				t.Fatal(diff)
+			}
+			// String returns a value that Set parses to the same pools.
+			var again DHCPPools
+			if err := again.Set(got.String()); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(got, again, cmp.Comparer(func(a, b netip.Addr) bool { return a == b }), cmp.Comparer(func(a, b netip.Prefix) bool { return a == b })); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
@@ -4,8 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net/netip"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/cenkalti/backoff/v5"
@@ -17,6 +20,8 @@ import (
 	"github.com/tinkerbell/tinkerbell/tink/agent/internal/runtime/containerd"
 	"github.com/tinkerbell/tinkerbell/tink/agent/internal/runtime/docker"
 	"github.com/tinkerbell/tinkerbell/tink/agent/internal/runtime/kubernetes"
+	"github.com/tinkerbell/tinkerbell/tink/agent/internal/runtime/native"
+	"github.com/tinkerbell/tinkerbell/tink/agent/internal/runtime/oci"
 	"github.com/tinkerbell/tinkerbell/tink/agent/internal/spec"
 	"github.com/tinkerbell/tinkerbell/tink/agent/internal/transport/file"
 	"github.com/tinkerbell/tinkerbell/tink/agent/internal/transport/grpc"
@@ -42,11 +47,44 @@ type TransportWriter interface {
 	Write(ctx context.Context, event spec.Event) error
 }
 
+// TransportCancelWatcher provides a method to watch for the cancellation of an action.
+type TransportCancelWatcher interface {
+	// WatchCancel blocks until the action is canceled, returning nil, or until ctx is done or an error occurs.
+	WatchCancel(ctx context.Context, action spec.Action) error
+}
+
+// TransportLogStreamer provides a method to stream the output of an action.
+type TransportLogStreamer interface {
+	// StreamLogs returns the writers for the stdout and stderr of an action. The returned io.Closer ends the stream
+	// and must be called once the action, including any retries and hook, is done.
+	StreamLogs(ctx context.Context, action spec.Action) (spec.Logs, io.Closer, error)
+}
+
 type Config struct {
 	TransportReader TransportReader
 	RuntimeExecutor RuntimeExecutor
+	// NativeExecutor runs native actions, which run directly on the host. Native actions fail when it is not set.
+	NativeExecutor  RuntimeExecutor
 	TransportWriter TransportWriter
-	Backoff         *backoff.ExponentialBackOff
+	// TransportCancelWatcher is optional. When set, a running action is stopped once it reports the action as canceled.
+	TransportCancelWatcher TransportCancelWatcher
+	// TransportLogStreamer is optional. When set, the output of action containers is streamed with it.
+	TransportLogStreamer TransportLogStreamer
+	Backoff              *backoff.ExponentialBackOff
+	// OutputDir is the directory, on the host running the Actions, under which Action outputs are collected.
+	// Output capture is disabled when empty.
+	OutputDir string
+	// ImageArchives are OCI image layout, or docker save, tarballs whose images are loaded before any action runs.
+	// Each is a local path or an http(s) URL. They require a RuntimeExecutor that implements RuntimeImagePuller.
+	ImageArchives []string
+
+	// pulled are the images pre-pulled, or being pre-pulled, for the Workflow pulledFor.
+	// Each channel is closed once its image is pulled or failed to pull.
+	pulled    map[string]chan struct{}
+	pulledFor string
+	pulledMu  sync.Mutex
+	// prePulls are the running pre-pulls.
+	prePulls sync.WaitGroup
 }
 
 func (c *Config) Run(ctx context.Context, log logr.Logger) {
@@ -64,6 +102,7 @@ func (c *Config) Run(ctx context.Context, log logr.Logger) {
 			MaxInterval:         5 * time.Second,
 		}
 	}
+	c.loadImageArchives(ctx, log)
 	// sending true to the channel logs the backoff
 	doBackoff := make(chan bool, 1)
 	defer close(doBackoff)
@@ -111,17 +150,32 @@ func (c *Config) Run(ctx context.Context, log logr.Logger) {
 		}
 		log.Info("reported action status", "action", action, "state", spec.StateRunning)
 
+		// Pre-pulls run in the background, only the image of action is waited for, before the timeout of action starts.
+		// The runtime pulls the image of action if it is still missing.
+		c.prePull(ctx, log, action)
+
 		state := spec.StateSuccess
-		// TODO(jacobweinstock): Add a retry count that comes from a CLI flag. It should only take precedence if the action has a retry count of 0.
-		retries := ternary(action.Retries == 0, 1, action.Retries)
+		// Tink Server serves the Action's retries, zero and one both mean the Action is run once.
+		retries := ternary(action.Retries <= 0, 1, action.Retries)
 
 		responseEvent := spec.Event{}
+		outputDir, err := c.prepareOutput(&action)
+		if err != nil {
+			log.Info("error preparing action output directory, outputs will not be collected", "error", err)
+		}
 		action.ExecutionStart = time.Now().UTC()
-		timeoutCtx, timeoutDone := context.WithTimeout(ctx, time.Duration(action.TimeoutSeconds)*time.Second)
+		logCtx, closeLogs := c.streamLogs(ctx, log, action)
+		timeoutCtx, timeoutDone := context.WithTimeout(logCtx, time.Duration(action.TimeoutSeconds)*time.Second)
+		canceled := c.watchCancel(timeoutCtx, log, action, timeoutDone)
 		for i := 1; i <= retries; i++ {
-			if err := c.RuntimeExecutor.Execute(timeoutCtx, action); err != nil {
+			action.Attempt = i
+			if err := c.execute(timeoutCtx, action); err != nil {
 				log.Info("error executing action", "error", err, "maxRetries", retries, "currentTry", i)
 				state = spec.StateFailure
+				if canceled.Load() {
+					state = spec.StateCanceled
+					break
+				}
 				if errors.Is(err, context.DeadlineExceeded) {
 					state = spec.StateTimeout
 					timeoutDone()
@@ -131,6 +185,24 @@ func (c *Config) Run(ctx context.Context, log logr.Logger) {
 					timeoutDone()
 					break
 				}
+				// Report every failed attempt so the retry is visible in the Workflow status.
+				msg := fmt.Sprintf("attempt %d of %d failed, retrying: %v", i, retries, err)
+				if err := c.TransportWriter.Write(ctx, spec.Event{Action: action, Message: msg, State: spec.StateRunning}); err != nil {
+					log.Info("error reporting failed attempt", "error", err)
+				}
+				select {
+				case <-timeoutCtx.Done():
+				case <-time.After(time.Duration(action.RetryDelaySeconds) * time.Second):
+				}
+				if canceled.Load() {
+					state = spec.StateCanceled
+					break
+				}
+				if errors.Is(timeoutCtx.Err(), context.DeadlineExceeded) {
+					state = spec.StateTimeout
+					timeoutDone()
+					break
+				}
 				continue
 			}
 			state = spec.StateSuccess
@@ -143,8 +215,17 @@ func (c *Config) Run(ctx context.Context, log logr.Logger) {
 		action.ExecutionStop = time.Now().UTC()
 		action.ExecutionDuration = humanDuration(action.ExecutionStop.Sub(action.ExecutionStart), 2)
 		responseEvent.Action = action
-		responseEvent.Message = "action completed"
+		responseEvent.Message = ternary(state == spec.StateCanceled, "action canceled", "action completed")
 		responseEvent.State = state
+		responseEvent.Hook = c.runHook(logCtx, log, action, state)
+		closeLogs()
+		if outputDir != "" {
+			outputs, err := readOutputs(outputDir)
+			if err != nil {
+				log.Info("error reading action outputs", "error", err)
+			}
+			responseEvent.Outputs = outputs
+		}
 
 		// Retry reporting the action completion with backoff. The agent must persist in
 		// reporting the result because the server will not re-serve the action once the agent
@@ -177,6 +258,100 @@ func (c *Config) Run(ctx context.Context, log logr.Logger) {
 	}
 }
 
+// watchCancel stops the running action, by calling stop, when the TransportCancelWatcher reports the action as canceled.
+// The returned value is true once the action has been canceled. Watching stops when ctx is done.
+func (c *Config) watchCancel(ctx context.Context, log logr.Logger, action spec.Action, stop context.CancelFunc) *atomic.Bool {
+	canceled := &atomic.Bool{}
+	if c.TransportCancelWatcher == nil {
+		return canceled
+	}
+	go func() {
+		if err := c.TransportCancelWatcher.WatchCancel(ctx, action); err != nil {
+			if ctx.Err() == nil {
+				log.Info("error watching for action cancellation", "error", err)
+			}
+			return
+		}
+		log.Info("action canceled", "action", action.ID)
+		canceled.Store(true)
+		stop()
+	}()
+
+	return canceled
+}
+
+// streamLogs opens a log stream for action, when a TransportLogStreamer is set, and returns ctx carrying its writers.
+// The returned func ends the stream. Log streaming is best-effort, errors are logged and never fail the action.
+func (c *Config) streamLogs(ctx context.Context, log logr.Logger, action spec.Action) (context.Context, func()) {
+	if c.TransportLogStreamer == nil {
+		return ctx, func() {}
+	}
+	logs, closer, err := c.TransportLogStreamer.StreamLogs(ctx, action)
+	if err != nil {
+		log.Info("error opening action log stream, logs will not be streamed", "error", err)
+		return ctx, func() {}
+	}
+
+	return spec.WithLogs(ctx, logs), func() {
+		if err := closer.Close(); err != nil {
+			log.Info("error closing action log stream", "error", err)
+		}
+	}
+}
+
+// runHook runs the on-failure or on-timeout hook of an action, if the action has one for the given state.
+// The hook command is run using the action's image, environment, volumes and namespaces.
+// It returns nil when no hook was run.
+func (c *Config) runHook(ctx context.Context, log logr.Logger, action spec.Action, state spec.State) *spec.Hook {
+	var name spec.HookName
+	var cmd []string
+	switch state {
+	case spec.StateFailure:
+		name, cmd = spec.HookOnFailure, action.OnFailure
+	case spec.StateTimeout:
+		name, cmd = spec.HookOnTimeout, action.OnTimeout
+	default:
+		return nil
+	}
+	if len(cmd) == 0 {
+		return nil
+	}
+
+	hook := action
+	hook.ID = fmt.Sprintf("%s-%s", action.ID, name)
+	hook.Name = fmt.Sprintf("%s-%s", action.Name, name)
+	hook.Cmd = ""
+	hook.Args = cmd
+	hook.OnTimeout = nil
+	hook.OnFailure = nil
+
+	log.Info("running action hook", "hook", name, "action", action.ID)
+	hookCtx, done := context.WithTimeout(ctx, time.Duration(action.TimeoutSeconds)*time.Second)
+	defer done()
+	if err := c.execute(hookCtx, hook); err != nil {
+		log.Info("error executing action hook", "hook", name, "error", err)
+		st := spec.StateFailure
+		if errors.Is(err, context.DeadlineExceeded) {
+			st = spec.StateTimeout
+		}
+		return &spec.Hook{Name: name, State: st, Message: err.Error()}
+	}
+
+	return &spec.Hook{Name: name, State: spec.StateSuccess, Message: "hook completed"}
+}
+
+// execute runs action with the NativeExecutor when it is a native action and with the RuntimeExecutor otherwise.
+func (c *Config) execute(ctx context.Context, action spec.Action) error {
+	if action.Type != spec.ActionTypeNative {
+		return c.RuntimeExecutor.Execute(ctx, action)
+	}
+	if c.NativeExecutor == nil {
+		return errors.New("native actions are not supported by this Agent")
+	}
+
+	return c.NativeExecutor.Execute(ctx, action)
+}
+
 func ternary[T any](condition bool, valueIfTrue, valueIfFalse T) T {
 	if condition {
 		return valueIfTrue
@@ -192,6 +367,7 @@ const (
 	DockerRuntimeType     RuntimeType = "docker"
 	ContainerdRuntimeType RuntimeType = "containerd"
 	KubernetesRuntimeType RuntimeType = "kubernetes"
+	OCIRuntimeType        RuntimeType = "oci"
 )
 
 type TransportType string
@@ -207,6 +383,13 @@ type Options struct {
 	RuntimeSelected           RuntimeType
 	AttributeDetectionEnabled bool
 	BackoffOptions            BackoffOptions
+	// LLDPTimeout is how long attribute detection listens for LLDP frames on each NIC, it delays the start of the transport by as long.
+	// LLDP discovery is disabled when 0.
+	LLDPTimeout time.Duration
+	// OutputDir is the directory under which Action outputs are collected. Output capture is disabled when empty.
+	OutputDir string
+	// ImageArchives are OCI image layout tarballs, local paths or http(s) URLs, whose images are loaded at start up.
+	ImageArchives []string
 }
 
 type Transport struct {
@@ -219,6 +402,7 @@ type Runtime struct {
 	Docker     DockerRuntime
 	Containerd ContainerdRuntime
 	Kubernetes KubernetesRuntime
+	OCI        OCIRuntime
 }
 
 type Registry struct {
@@ -237,7 +421,14 @@ type GRPCTransport struct {
 	ServerAddrPort string
 	TLSEnabled     bool
 	TLSInsecure    bool
-	RetryInterval  time.Duration
+	// TLSCertFile and TLSKeyFile are the client certificate and key the Agent authenticates to the Tink server with.
+	TLSCertFile string
+	TLSKeyFile  string
+	// TLSCAFile is the CA bundle the Tink server certificate is verified with. The system roots are used when empty.
+	TLSCAFile string
+	// Token is the bootstrap token the Agent authenticates to the Tink server with when it has no client certificate.
+	Token         string
+	RetryInterval time.Duration
 }
 type FileTransport struct {
 	WorkflowPath string
@@ -264,6 +455,16 @@ type KubernetesRuntime struct {
 	Kubeconfig         string
 	ServiceAccountName string
 }
+type OCIRuntime struct {
+	// Binary is the OCI runtime, runc or crun, used to run Actions.
+	Binary string
+	// Root is the directory holding the image layout and the Action bundles.
+	Root string
+	// StateDir is the directory the OCI runtime keeps container state in.
+	StateDir string
+	// RegistryConfigPath is the root directory containing per-registry hosts.toml and certificate configuration.
+	RegistryConfigPath string
+}
 
 // BackoffOptions holds the configuration for the backoff strategy.
 type BackoffOptions struct {
@@ -280,6 +481,8 @@ func (o *Options) ConfigureAndRun(inctx context.Context, log logr.Logger, id str
 	eg, ctx := errgroup.WithContext(inctx)
 	var tr TransportReader
 	var tw TransportWriter
+	var cw TransportCancelWatcher
+	var ls TransportLogStreamer
 	switch o.TransportSelected {
 	case FileTransportType:
 		readWriter := &file.Config{
@@ -309,7 +512,13 @@ func (o *Options) ConfigureAndRun(inctx context.Context, log logr.Logger, id str
 		tr = readWriter
 		tw = readWriter
 	default:
-		conn, err := grpc.NewClientConn(o.Transport.GRPC.ServerAddrPort, o.Transport.GRPC.TLSEnabled, o.Transport.GRPC.TLSInsecure)
+		auth := grpc.ClientAuth{
+			CertFile: o.Transport.GRPC.TLSCertFile,
+			KeyFile:  o.Transport.GRPC.TLSKeyFile,
+			CAFile:   o.Transport.GRPC.TLSCAFile,
+			Token:    o.Transport.GRPC.Token,
+		}
+		conn, err := grpc.NewClientConn(o.Transport.GRPC.ServerAddrPort, o.Transport.GRPC.TLSEnabled, o.Transport.GRPC.TLSInsecure, auth)
 		if err != nil {
 			return fmt.Errorf("unable to create gRPC client: %w", err)
 		}
@@ -317,12 +526,18 @@ func (o *Options) ConfigureAndRun(inctx context.Context, log logr.Logger, id str
 			Log:              log,
 			TinkServerClient: proto.NewWorkflowServiceClient(conn),
 			AgentID:          id,
-			Actions:          make(chan spec.Action),
+			// Buffered so that the server can push an action while the previous one is still running.
+			Actions: make(chan spec.Action, 1),
 		}
+		cw = readWriter
+		ls = readWriter
 		if o.AttributeDetectionEnabled {
-			readWriter.Attributes = attribute.DiscoverAll(log)
+			readWriter.Attributes = attribute.DiscoverAll(log, o.LLDPTimeout)
 		}
 		log.Info("starting gRPC transport", "server", o.Transport.GRPC.ServerAddrPort, "attributes", readWriter.Attributes)
+		eg.Go(func() error {
+			return readWriter.Connect(ctx)
+		})
 		tr = readWriter
 		tw = readWriter
 	}
@@ -336,7 +551,30 @@ func (o *Options) ConfigureAndRun(inctx context.Context, log logr.Logger, id str
 			return fmt.Errorf("unable to create Kubernetes config: %w", err)
 		}
 		re = kn
+		if o.OutputDir != "" {
+			return fmt.Errorf("action outputs are not supported by the Kubernetes runtime")
+		}
 		log.Info("using Kubernetes runtime")
+	case OCIRuntimeType:
+		opts := []oci.Opt{}
+		if o.Runtime.OCI.Binary != "" {
+			opts = append(opts, oci.WithBinary(o.Runtime.OCI.Binary))
+		}
+		if o.Runtime.OCI.Root != "" {
+			opts = append(opts, oci.WithRoot(o.Runtime.OCI.Root))
+		}
+		if o.Runtime.OCI.StateDir != "" {
+			opts = append(opts, oci.WithStateDir(o.Runtime.OCI.StateDir))
+		}
+		if o.Runtime.OCI.RegistryConfigPath != "" {
+			opts = append(opts, oci.WithRegistryConfigPath(o.Runtime.OCI.RegistryConfigPath))
+		}
+		oc, err := oci.NewConfig(log, opts...)
+		if err != nil {
+			return fmt.Errorf("unable to create OCI runtime config: %w", err)
+		}
+		re = oc
+		log.Info("using OCI runtime", "binary", oc.Binary)
 	case ContainerdRuntimeType:
 		opts := []containerd.Opt{}
 		if o.Runtime.Containerd.Namespace != "" {
@@ -385,10 +623,15 @@ func (o *Options) ConfigureAndRun(inctx context.Context, log logr.Logger, id str
 	bo.MaxInterval = o.BackoffOptions.MaxInterval
 
 	a := &Config{
-		TransportReader: tr,
-		RuntimeExecutor: re,
-		TransportWriter: tw,
-		Backoff:         bo,
+		TransportReader:        tr,
+		RuntimeExecutor:        re,
+		NativeExecutor:         &native.Config{Log: log},
+		TransportWriter:        tw,
+		TransportCancelWatcher: cw,
+		TransportLogStreamer:   ls,
+		Backoff:                bo,
+		OutputDir:              o.OutputDir,
+		ImageArchives:          o.ImageArchives,
 	}
 
 	eg.Go(func() error {
@@ -427,11 +670,11 @@ func (t *TransportType) Type() string {
 
 func (r *RuntimeType) Set(s string) error {
 	switch strings.ToLower(s) {
-	case DockerRuntimeType.String(), ContainerdRuntimeType.String(), KubernetesRuntimeType.String():
+	case DockerRuntimeType.String(), ContainerdRuntimeType.String(), KubernetesRuntimeType.String(), OCIRuntimeType.String():
 		*r = RuntimeType(strings.ToLower(s))
 		return nil
 	default:
-		return fmt.Errorf("invalid Runtime type: %q, must be one of [%s, %s, %s]", s, DockerRuntimeType, ContainerdRuntimeType, KubernetesRuntimeType)
+		return fmt.Errorf("invalid Runtime type: %q, must be one of [%s, %s, %s, %s]", s, DockerRuntimeType, ContainerdRuntimeType, KubernetesRuntimeType, OCIRuntimeType)
 	}
 }
 
@@ -2,13 +2,18 @@ package agent
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/cenkalti/backoff/v5"
 	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
 	"github.com/tinkerbell/tinkerbell/tink/agent/internal/spec"
 )
 
@@ -167,6 +172,7 @@ func TestRuntimeTypeSet(t *testing.T) {
 		"lowercase docker":         {in: "docker", want: DockerRuntimeType},
 		"lowercase containerd":     {in: "containerd", want: ContainerdRuntimeType},
 		"lowercase kubernetes":     {in: "kubernetes", want: KubernetesRuntimeType},
+		"lowercase oci":            {in: "oci", want: OCIRuntimeType},
 		"mixed case is normalized": {in: "Kubernetes", want: KubernetesRuntimeType},
 		"unknown value rejected":   {in: "bogus", wantErr: true},
 	}
@@ -190,3 +196,415 @@ func TestRuntimeTypeSet(t *testing.T) {
 		})
 	}
 }
+
+// failingExecutor fails every action whose ID is not in succeed.
+type failingExecutor struct {
+	err     error
+	succeed map[string]bool
+	mu      sync.Mutex
+	ran     []spec.Action
+}
+
+func (e *failingExecutor) Execute(_ context.Context, a spec.Action) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.ran = append(e.ran, a)
+	if e.succeed[a.ID] {
+		return nil
+	}
+	return e.err
+}
+
+// captureWriter records the completion event and cancels the context.
+type captureWriter struct {
+	cancel context.CancelFunc
+	mu     sync.Mutex
+	events []spec.Event
+}
+
+func (w *captureWriter) Write(_ context.Context, e spec.Event) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.events = append(w.events, e)
+	if e.State != spec.StateRunning {
+		w.cancel()
+	}
+	return nil
+}
+
+type hookActionReader struct {
+	action spec.Action
+	sent   atomic.Bool
+}
+
+func (r *hookActionReader) Read(ctx context.Context) (spec.Action, error) {
+	if r.sent.CompareAndSwap(false, true) {
+		return r.action, nil
+	}
+	<-ctx.Done()
+	return spec.Action{}, ctx.Err()
+}
+
+func TestRunHooks(t *testing.T) {
+	tests := map[string]struct {
+		action       spec.Action
+		executor     *failingExecutor
+		wantState    spec.State
+		wantHook     *spec.Hook
+		wantHookArgs []string
+	}{
+		"success runs no hook": {
+			action:    spec.Action{ID: "a1", TimeoutSeconds: 5, OnFailure: []string{"echo", "failed"}},
+			executor:  &failingExecutor{succeed: map[string]bool{"a1": true}},
+			wantState: spec.StateSuccess,
+		},
+		"failure without hook": {
+			action:    spec.Action{ID: "a1", TimeoutSeconds: 5},
+			executor:  &failingExecutor{err: errors.New("boom")},
+			wantState: spec.StateFailure,
+		},
+		"failure runs on-failure hook": {
+			action:       spec.Action{ID: "a1", TimeoutSeconds: 5, OnFailure: []string{"echo", "failed"}, OnTimeout: []string{"echo", "timeout"}},
+			executor:     &failingExecutor{err: errors.New("boom"), succeed: map[string]bool{"a1-on-failure": true}},
+			wantState:    spec.StateFailure,
+			wantHook:     &spec.Hook{Name: spec.HookOnFailure, State: spec.StateSuccess, Message: "hook completed"},
+			wantHookArgs: []string{"echo", "failed"},
+		},
+		"timeout runs on-timeout hook": {
+			action:       spec.Action{ID: "a1", TimeoutSeconds: 5, OnFailure: []string{"echo", "failed"}, OnTimeout: []string{"echo", "timeout"}},
+			executor:     &failingExecutor{err: context.DeadlineExceeded, succeed: map[string]bool{"a1-on-timeout": true}},
+			wantState:    spec.StateTimeout,
+			wantHook:     &spec.Hook{Name: spec.HookOnTimeout, State: spec.StateSuccess, Message: "hook completed"},
+			wantHookArgs: []string{"echo", "timeout"},
+		},
+		"failing hook is reported": {
+			action:       spec.Action{ID: "a1", TimeoutSeconds: 5, OnFailure: []string{"echo", "failed"}},
+			executor:     &failingExecutor{err: errors.New("boom")},
+			wantState:    spec.StateFailure,
+			wantHook:     &spec.Hook{Name: spec.HookOnFailure, State: spec.StateFailure, Message: "boom"},
+			wantHookArgs: []string{"echo", "failed"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			writer := &captureWriter{cancel: cancel}
+			c := &Config{
+				TransportReader: &hookActionReader{action: tt.action},
+				RuntimeExecutor: tt.executor,
+				TransportWriter: writer,
+			}
+			c.Run(ctx, logr.Discard())
+
+			if len(writer.events) != 2 {
+				t.Fatalf("expected 2 events, got %d", len(writer.events))
+			}
+			got := writer.events[1]
+			if got.State != tt.wantState {
+				t.Errorf("expected state %q, got %q", tt.wantState, got.State)
+			}
+			if diff := cmp.Diff(tt.wantHook, got.Hook); diff != "" {
+				t.Errorf("unexpected hook (-want +got):\n%s", diff)
+			}
+			if tt.wantHookArgs == nil {
+				return
+			}
+			last := tt.executor.ran[len(tt.executor.ran)-1]
+			if diff := cmp.Diff(tt.wantHookArgs, last.Args); diff != "" {
+				t.Errorf("unexpected hook args (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRunNative(t *testing.T) {
+	tests := map[string]struct {
+		native        *failingExecutor
+		wantState     spec.State
+		wantNativeRan []string
+	}{
+		"native executor runs the action and its hook": {
+			native:        &failingExecutor{err: errors.New("boom"), succeed: map[string]bool{"a1-on-failure": true}},
+			wantState:     spec.StateFailure,
+			wantNativeRan: []string{"a1", "a1-on-failure"},
+		},
+		"no native executor": {
+			wantState: spec.StateFailure,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			writer := &captureWriter{cancel: cancel}
+			runtime := &failingExecutor{}
+			c := &Config{
+				TransportReader: &hookActionReader{action: spec.Action{ID: "a1", Type: spec.ActionTypeNative, TimeoutSeconds: 5, Args: []string{"/usr/sbin/wipefs", "-a"}, OnFailure: []string{"/bin/true"}}},
+				RuntimeExecutor: runtime,
+				TransportWriter: writer,
+			}
+			if tt.native != nil {
+				c.NativeExecutor = tt.native
+			}
+			c.Run(ctx, logr.Discard())
+
+			if len(runtime.ran) != 0 {
+				t.Errorf("expected the RuntimeExecutor not to run native actions, ran %d", len(runtime.ran))
+			}
+			if got := writer.events[len(writer.events)-1].State; got != tt.wantState {
+				t.Errorf("expected state %q, got %q", tt.wantState, got)
+			}
+			if tt.native == nil {
+				return
+			}
+			var got []string
+			for _, a := range tt.native.ran {
+				got = append(got, a.ID)
+			}
+			if diff := cmp.Diff(tt.wantNativeRan, got); diff != "" {
+				t.Errorf("unexpected native runs (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// failNExecutor fails the first failCount runs of an action then succeeds.
+type failNExecutor struct {
+	failCount int
+	calls     atomic.Int32
+}
+
+func (e *failNExecutor) Execute(_ context.Context, _ spec.Action) error {
+	if int(e.calls.Add(1)) <= e.failCount {
+		return errors.New("image pull failed")
+	}
+	return nil
+}
+
+func TestRunRetries(t *testing.T) {
+	tests := map[string]struct {
+		retries      int
+		failCount    int
+		wantState    spec.State
+		wantAttempts []int
+	}{
+		"succeeds after retries": {
+			retries:      3,
+			failCount:    2,
+			wantState:    spec.StateSuccess,
+			wantAttempts: []int{0, 1, 2, 3},
+		},
+		"fails after all retries": {
+			retries:      2,
+			failCount:    5,
+			wantState:    spec.StateFailure,
+			wantAttempts: []int{0, 1, 2},
+		},
+		"no retries": {
+			retries:      0,
+			failCount:    1,
+			wantState:    spec.StateFailure,
+			wantAttempts: []int{0, 1},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			writer := &captureWriter{cancel: cancel}
+			executor := &failNExecutor{failCount: tt.failCount}
+			c := &Config{
+				TransportReader: &hookActionReader{action: spec.Action{ID: "a1", TimeoutSeconds: 5, Retries: tt.retries}},
+				RuntimeExecutor: executor,
+				TransportWriter: writer,
+			}
+			c.Run(ctx, logr.Discard())
+
+			got := []int{}
+			for _, e := range writer.events {
+				got = append(got, e.Action.Attempt)
+			}
+			if diff := cmp.Diff(tt.wantAttempts, got); diff != "" {
+				t.Errorf("unexpected reported attempts (-want +got):\n%s", diff)
+			}
+			if last := writer.events[len(writer.events)-1]; last.State != tt.wantState {
+				t.Errorf("expected state %q, got %q", tt.wantState, last.State)
+			}
+		})
+	}
+}
+
+// blockingExecutor blocks until the context is done.
+type blockingExecutor struct{}
+
+func (blockingExecutor) Execute(ctx context.Context, _ spec.Action) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+// cancelWatcher reports the action as canceled after delay, or returns err.
+type cancelWatcher struct {
+	delay time.Duration
+	err   error
+}
+
+func (w cancelWatcher) WatchCancel(ctx context.Context, _ spec.Action) error {
+	if w.err != nil {
+		return w.err
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(w.delay):
+		return nil
+	}
+}
+
+func TestRunCancel(t *testing.T) {
+	tests := map[string]struct {
+		watcher   cancelWatcher
+		executor  RuntimeExecutor
+		wantState spec.State
+		wantMsg   string
+	}{
+		"running action is canceled": {
+			watcher:   cancelWatcher{delay: 10 * time.Millisecond},
+			executor:  blockingExecutor{},
+			wantState: spec.StateCanceled,
+			wantMsg:   "action canceled",
+		},
+		"action completes before cancel": {
+			watcher:   cancelWatcher{delay: time.Hour},
+			executor:  &failingExecutor{succeed: map[string]bool{"a1": true}},
+			wantState: spec.StateSuccess,
+			wantMsg:   "action completed",
+		},
+		"watch error does not stop action": {
+			watcher:   cancelWatcher{err: errors.New("unimplemented")},
+			executor:  &failingExecutor{succeed: map[string]bool{"a1": true}},
+			wantState: spec.StateSuccess,
+			wantMsg:   "action completed",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			writer := &captureWriter{cancel: cancel}
+			c := &Config{
+				TransportReader:        &hookActionReader{action: spec.Action{ID: "a1", TimeoutSeconds: 5, Retries: 3, OnFailure: []string{"echo", "failed"}}},
+				RuntimeExecutor:        tt.executor,
+				TransportWriter:        writer,
+				TransportCancelWatcher: tt.watcher,
+			}
+			c.Run(ctx, logr.Discard())
+
+			if len(writer.events) != 2 {
+				t.Fatalf("expected 2 events, got %d", len(writer.events))
+			}
+			got := writer.events[1]
+			if got.State != tt.wantState {
+				t.Errorf("expected state %q, got %q", tt.wantState, got.State)
+			}
+			if got.Message != tt.wantMsg {
+				t.Errorf("expected message %q, got %q", tt.wantMsg, got.Message)
+			}
+			if got.Hook != nil {
+				t.Errorf("expected no hook, got %v", got.Hook)
+			}
+		})
+	}
+}
+
+// logExecutor writes the action ID to stdout and the command to stderr, then fails when the action has a hook.
+type logExecutor struct{}
+
+func (logExecutor) Execute(ctx context.Context, a spec.Action) error {
+	logs := spec.LogsFrom(ctx)
+	fmt.Fprintln(logs.Stdout, a.ID)
+	fmt.Fprintln(logs.Stderr, a.Args)
+	if len(a.OnFailure) > 0 {
+		return errors.New("failed")
+	}
+	return nil
+}
+
+// logStreamer collects everything written to the logs of an action.
+type logStreamer struct {
+	err    error
+	mu     sync.Mutex
+	out    strings.Builder
+	closed bool
+}
+
+func (s *logStreamer) StreamLogs(_ context.Context, _ spec.Action) (spec.Logs, io.Closer, error) {
+	if s.err != nil {
+		return spec.Logs{}, nil, s.err
+	}
+	return spec.Logs{Stdout: s, Stderr: s}, s, nil
+}
+
+func (s *logStreamer) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.out.Write(p)
+}
+
+func (s *logStreamer) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return nil
+}
+
+func TestRunLogs(t *testing.T) {
+	tests := map[string]struct {
+		streamer   *logStreamer
+		wantLogs   string
+		wantClosed bool
+	}{
+		"action and hook output": {
+			streamer:   &logStreamer{},
+			wantLogs:   "a1\n[]\na1-on-failure\n[echo failed]\n",
+			wantClosed: true,
+		},
+		"stream error does not fail the action": {
+			streamer: &logStreamer{err: errors.New("unimplemented")},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			writer := &captureWriter{cancel: cancel}
+			c := &Config{
+				TransportReader:      &hookActionReader{action: spec.Action{ID: "a1", TimeoutSeconds: 5, OnFailure: []string{"echo", "failed"}}},
+				RuntimeExecutor:      logExecutor{},
+				TransportWriter:      writer,
+				TransportLogStreamer: tt.streamer,
+			}
+			c.Run(ctx, logr.Discard())
+
+			if len(writer.events) != 2 || writer.events[1].State != spec.StateFailure {
+				t.Fatalf("expected the action to fail, got %v", writer.events)
+			}
+			if diff := cmp.Diff(tt.wantLogs, tt.streamer.out.String()); diff != "" {
+				t.Errorf("unexpected logs (-want +got):\n%s", diff)
+			}
+			if tt.streamer.closed != tt.wantClosed {
+				t.Errorf("expected closed %v, got %v", tt.wantClosed, tt.streamer.closed)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package agent
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/tinkerbell/tinkerbell/tink/agent/internal/spec"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// maxParallelPulls is the maximum number of images pulled at the same time.
+	maxParallelPulls = 4
+	// prePullTimeout bounds the pre-pulls started for an action.
+	prePullTimeout = 15 * time.Minute
+	// imageArchiveTimeout bounds fetching and loading each image archive.
+	imageArchiveTimeout = 15 * time.Minute
+)
+
+// RuntimeImagePuller provides methods to make the images of actions available before the actions are executed.
+type RuntimeImagePuller interface {
+	// PullImage blocks until image is pulled or an error occurs.
+	PullImage(ctx context.Context, image string) error
+	// LoadImages loads the images of an OCI image layout, or docker save, tarball.
+	LoadImages(ctx context.Context, r io.Reader) error
+}
+
+// prePull pulls the image of action and the images of the actions after it, in parallel and in the background.
+// The pulls are bounded by prePullTimeout. It waits for the image of action only, so that image is not pulled within the timeout of action.
+// Images already pulled for the Workflow are skipped. Images referenced by digest are content-addressed, they are kept across Workflows.
+// Errors are logged, the runtime pulls an image that is still missing when it executes the action.
+func (c *Config) prePull(ctx context.Context, log logr.Logger, action spec.Action) {
+	p, ok := c.RuntimeExecutor.(RuntimeImagePuller)
+	if !ok {
+		return
+	}
+	c.pulledMu.Lock()
+	if c.pulled == nil || c.pulledFor != action.WorkflowID {
+		pulled := map[string]chan struct{}{}
+		for img, done := range c.pulled {
+			if strings.Contains(img, "@sha256:") {
+				pulled[img] = done
+			}
+		}
+		c.pulled, c.pulledFor = pulled, action.WorkflowID
+	}
+
+	var images []string
+	for _, img := range append([]string{action.Image}, action.RemainingImages...) {
+		if _, ok := c.pulled[img]; img == "" || ok {
+			continue
+		}
+		c.pulled[img] = make(chan struct{})
+		images = append(images, img)
+	}
+	// done is closed once the image of action is pulled, or failed to pull, by this or an earlier pre-pull.
+	done := c.pulled[action.Image]
+	pulls := make(map[string]chan struct{}, len(images))
+	for _, img := range images {
+		pulls[img] = c.pulled[img]
+	}
+	c.pulledMu.Unlock()
+
+	if len(images) > 0 {
+		c.prePulls.Add(1)
+		go func() {
+			defer c.prePulls.Done()
+			ctx, cancel := context.WithTimeout(ctx, prePullTimeout)
+			defer cancel()
+			eg := errgroup.Group{}
+			eg.SetLimit(maxParallelPulls)
+			for _, img := range images {
+				eg.Go(func() error {
+					defer close(pulls[img])
+					if err := p.PullImage(ctx, img); err != nil {
+						log.Info("error pre-pulling image", "image", img, "error", err)
+						c.pulledMu.Lock()
+						if c.pulled[img] == pulls[img] {
+							delete(c.pulled, img)
+						}
+						c.pulledMu.Unlock()
+						return nil
+					}
+					log.V(1).Info("pre-pulled image", "image", img)
+					return nil
+				})
+			}
+			_ = eg.Wait()
+		}()
+	}
+
+	if done == nil {
+		return
+	}
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+}
+
+// loadImageArchives loads the images of ImageArchives. Errors are logged, images that are not loaded are pulled from their registry.
+func (c *Config) loadImageArchives(ctx context.Context, log logr.Logger) {
+	if len(c.ImageArchives) == 0 {
+		return
+	}
+	p, ok := c.RuntimeExecutor.(RuntimeImagePuller)
+	if !ok {
+		log.Info("the runtime does not support loading images, image archives are ignored", "archives", c.ImageArchives)
+		return
+	}
+	for _, loc := range c.ImageArchives {
+		if err := loadImageArchive(ctx, p, loc); err != nil {
+			log.Info("error loading image archive", "archive", loc, "error", err)
+			continue
+		}
+		log.Info("loaded image archive", "archive", loc)
+	}
+}
+
+// loadImageArchive loads the images of the tarball at loc, a local path or an http(s) URL. Gzip compressed tarballs are decompressed.
+// Fetching and loading the tarball is bounded by imageArchiveTimeout.
+func loadImageArchive(ctx context.Context, p RuntimeImagePuller, loc string) error {
+	ctx, cancel := context.WithTimeout(ctx, imageArchiveTimeout)
+	defer cancel()
+	rc, err := openImageArchive(ctx, loc)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	br := bufio.NewReader(rc)
+	var r io.Reader = br
+	if magic, err := br.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return fmt.Errorf("error decompressing image archive: %w", err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	return p.LoadImages(ctx, r)
+}
+
+func openImageArchive(ctx context.Context, loc string) (io.ReadCloser, error) {
+	if !strings.HasPrefix(loc, "http://") && !strings.HasPrefix(loc, "https://") {
+		return os.Open(loc)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, loc, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching image archive: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("error fetching image archive: unexpected status %v", resp.Status)
+	}
+
+	return resp.Body, nil
+}
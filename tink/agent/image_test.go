@@ -0,0 +1,166 @@
+package agent
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
+	"github.com/tinkerbell/tinkerbell/tink/agent/internal/spec"
+)
+
+// pullRuntime records the images it pulls and the archives it loads.
+type pullRuntime struct {
+	mu     sync.Mutex
+	pulls  []string
+	loaded []string
+	fail   map[string]bool
+	// block are the images whose pull blocks until the channel is closed.
+	block map[string]chan struct{}
+}
+
+func (p *pullRuntime) Execute(_ context.Context, _ spec.Action) error {
+	return nil
+}
+
+func (p *pullRuntime) PullImage(_ context.Context, image string) error {
+	if b, ok := p.block[image]; ok {
+		<-b
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.pulls = append(p.pulls, image)
+	if p.fail[image] {
+		return errors.New("pull failed")
+	}
+	return nil
+}
+
+func (p *pullRuntime) LoadImages(_ context.Context, r io.Reader) error {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	p.loaded = append(p.loaded, string(b))
+	return nil
+}
+
+func TestPrePull(t *testing.T) {
+	tests := map[string]struct {
+		actions []spec.Action
+		fail    map[string]bool
+		want    []string
+	}{
+		"remaining images are pulled once": {
+			actions: []spec.Action{
+				{WorkflowID: "wf1", Image: "stream", RemainingImages: []string{"writefile", "stream", "kexec"}},
+				{WorkflowID: "wf1", Image: "writefile", RemainingImages: []string{"stream", "kexec"}},
+			},
+			want: []string{"kexec", "stream", "writefile"},
+		},
+		"failed pulls are retried": {
+			actions: []spec.Action{
+				{WorkflowID: "wf1", Image: "stream", RemainingImages: []string{"kexec"}},
+				{WorkflowID: "wf1", Image: "kexec"},
+			},
+			fail: map[string]bool{"kexec": true},
+			want: []string{"kexec", "kexec", "stream"},
+		},
+		"tags are pulled again for a new workflow": {
+			actions: []spec.Action{
+				{WorkflowID: "wf1", Image: "stream", RemainingImages: []string{"kexec@sha256:1234"}},
+				{WorkflowID: "wf2", Image: "stream", RemainingImages: []string{"kexec@sha256:1234"}},
+			},
+			want: []string{"kexec@sha256:1234", "stream", "stream"},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			rt := &pullRuntime{fail: tt.fail}
+			c := &Config{RuntimeExecutor: rt}
+			for _, a := range tt.actions {
+				c.prePull(context.Background(), logr.Discard(), a)
+				c.prePulls.Wait()
+			}
+			sort.Strings(rt.pulls)
+			if diff := cmp.Diff(tt.want, rt.pulls); diff != "" {
+				t.Errorf("unexpected pulls (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPrePullWaitsForActionImage(t *testing.T) {
+	release := make(chan struct{})
+	rt := &pullRuntime{block: map[string]chan struct{}{"kexec": release}}
+	c := &Config{RuntimeExecutor: rt}
+
+	// Only the image of the action is waited for, not the remaining images.
+	c.prePull(context.Background(), logr.Discard(), spec.Action{WorkflowID: "wf1", Image: "stream", RemainingImages: []string{"kexec"}})
+	rt.mu.Lock()
+	pulls := slices.Clone(rt.pulls)
+	rt.mu.Unlock()
+	if diff := cmp.Diff([]string{"stream"}, pulls); diff != "" {
+		t.Errorf("unexpected pulls (-want +got):\n%s", diff)
+	}
+
+	// An image still being pulled by an earlier pre-pull is waited for too.
+	done := make(chan struct{})
+	go func() {
+		c.prePull(context.Background(), logr.Discard(), spec.Action{WorkflowID: "wf1", Image: "kexec"})
+		close(done)
+	}()
+	select {
+	case <-done:
+		t.Fatal("expected prePull to wait for the image of the action")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected prePull to return once the image of the action is pulled")
+	}
+	c.prePulls.Wait()
+}
+
+func TestLoadImageArchives(t *testing.T) {
+	var gz bytes.Buffer
+	w := gzip.NewWriter(&gz)
+	_, _ = w.Write([]byte("compressed"))
+	_ = w.Close()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/images.tar.gz" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(gz.Bytes())
+	}))
+	defer srv.Close()
+	file := filepath.Join(t.TempDir(), "images.tar")
+	if err := os.WriteFile(file, []byte("uncompressed"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	rt := &pullRuntime{}
+	c := &Config{
+		RuntimeExecutor: rt,
+		ImageArchives:   []string{file, srv.URL + "/images.tar.gz", srv.URL + "/missing.tar", "/does/not/exist.tar"},
+	}
+	c.loadImageArchives(context.Background(), logr.Discard())
+	if diff := cmp.Diff([]string{"uncompressed", "compressed"}, rt.loaded); diff != "" {
+		t.Errorf("unexpected loaded archives (-want +got):\n%s", diff)
+	}
+}
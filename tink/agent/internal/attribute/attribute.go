@@ -3,6 +3,8 @@ package attribute
 import (
 	"fmt"
 	"math"
+	"os/exec"
+	"time"
 
 	"github.com/ccoveille/go-safecast/v2"
 	"github.com/go-logr/logr"
@@ -11,19 +13,30 @@ import (
 	"github.com/tinkerbell/tinkerbell/pkg/data"
 )
 
-func DiscoverAll(l logr.Logger) *data.AgentAttributes {
-	return &data.AgentAttributes{
+// DiscoverAll discovers all the attributes. LLDP neighbours are listened for, up to lldpTimeout, while the other attributes are discovered.
+func DiscoverAll(l logr.Logger, lldpTimeout time.Duration) *data.AgentAttributes {
+	nics := DiscoverNetworks(l)
+	lldp := make(chan struct{})
+	go func() {
+		defer close(lldp)
+		DiscoverLLDP(l, nics, lldpTimeout)
+	}()
+	a := &data.AgentAttributes{
 		CPU:               DiscoverCPU(l),
 		Memory:            DiscoverMemory(l),
 		BlockDevices:      DiscoverBlockDevices(l),
-		NetworkInterfaces: DiscoverNetworks(l),
+		NetworkInterfaces: nics,
 		PCIDevices:        DiscoverPCI(l),
 		GPUDevices:        DiscoverGPU(l),
 		Chassis:           DiscoverChassis(l),
 		BIOS:              DiscoverBIOS(l),
 		Baseboard:         DiscoverBaseboard(l),
 		Product:           DiscoverProduct(l),
+		TPM:               DiscoverTPM(l),
 	}
+	<-lldp
+
+	return a
 }
 
 func DiscoverCPU(l logr.Logger) *data.CPU {
@@ -83,13 +96,15 @@ func DiscoverBlockDevices(l logr.Logger) []*data.Block {
 		l.V(1).Info("error getting block info", "error", err)
 		return nil
 	}
+	// SMART health is only reported when smartctl is installed.
+	smartctl, smartctlErr := exec.LookPath("smartctl")
 	var blockDevices []*data.Block
 	for _, d := range b.Disks {
 		if d == nil {
 			continue
 		}
 		if d.StorageController != block.StorageControllerLoop && d.StorageController != block.StorageControllerUnknown {
-			blockDevices = append(blockDevices, &data.Block{
+			bd := &data.Block{
 				Name:              toPtr(d.Name),
 				ControllerType:    toPtr(d.StorageController.String()),
 				DriveType:         toPtr(d.DriveType.String()),
@@ -99,7 +114,15 @@ func DiscoverBlockDevices(l logr.Logger) []*data.Block {
 				Model:             toPtr(d.Model),
 				WWN:               toPtr(d.WWN),
 				SerialNumber:      toPtr(d.SerialNumber),
-			})
+			}
+			if smartctlErr == nil {
+				if status, err := smartStatus(smartctl, d.Name); err == nil {
+					bd.SmartStatus = toPtr(status)
+				} else {
+					l.V(1).Info("error getting smart status", "disk", d.Name, "error", err)
+				}
+			}
+			blockDevices = append(blockDevices, bd)
 		}
 	}
 	return blockDevices
@@ -129,6 +152,7 @@ func DiscoverNetworks(l logr.Logger) []*data.Network {
 				}
 				return capabilities
 			}(),
+			LinkState: toPtr(linkState(sysfsRoot, n.Name)),
 		})
 	}
 	return nics
@@ -0,0 +1,122 @@
+package attribute
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"net"
+	"net/netip"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/tinkerbell/tinkerbell/pkg/data"
+)
+
+const (
+	ethTypeLLDP  = 0x88cc
+	ethHeaderLen = 14
+
+	tlvEnd               = 0
+	tlvChassisID         = 1
+	tlvPortID            = 2
+	tlvPortDescription   = 4
+	tlvSystemName        = 5
+	tlvSystemDescription = 6
+
+	chassisIDSubtypeMAC     = 4
+	chassisIDSubtypeAddress = 5
+	portIDSubtypeMAC        = 3
+	portIDSubtypeAddress    = 4
+)
+
+// lldpMulticast is the nearest bridge group address, LLDP frames are sent to it.
+var lldpMulticast = net.HardwareAddr{0x01, 0x80, 0xc2, 0x00, 0x00, 0x0e}
+
+// DiscoverLLDP listens, for up to timeout, for an LLDP frame on each NIC whose link is up and adds
+// the neighbour that sent it to the NIC. Switches usually send LLDP frames every 30 seconds.
+// NICs are listened on concurrently, DiscoverLLDP returns as soon as every NIC got a frame.
+func DiscoverLLDP(l logr.Logger, nics []*data.Network, timeout time.Duration) {
+	if timeout <= 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	var wg sync.WaitGroup
+	for _, nic := range nics {
+		if nic == nil || nic.Name == nil || nic.LinkState == nil || *nic.LinkState != linkUp {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			nb, err := listenLLDP(ctx, *nic.Name)
+			if err != nil {
+				if !errors.Is(err, context.DeadlineExceeded) {
+					l.V(1).Info("error listening for lldp frames", "interface", *nic.Name, "error", err)
+				}
+				return
+			}
+			nic.LLDPNeighbors = append(nic.LLDPNeighbors, nb)
+		}()
+	}
+	wg.Wait()
+}
+
+// parseLLDP returns the neighbour that sent the LLDP Ethernet frame.
+func parseLLDP(frame []byte) (*data.LLDPNeighbor, error) {
+	if len(frame) < ethHeaderLen || binary.BigEndian.Uint16(frame[12:14]) != ethTypeLLDP {
+		return nil, errors.New("not an lldp frame")
+	}
+	nb := &data.LLDPNeighbor{}
+	b := frame[ethHeaderLen:]
+	for len(b) >= 2 {
+		header := binary.BigEndian.Uint16(b[:2])
+		typ, length := header>>9, int(header&0x1ff)
+		if len(b) < 2+length {
+			return nil, errors.New("truncated lldp tlv")
+		}
+		value := b[2 : 2+length]
+		b = b[2+length:]
+		switch typ {
+		case tlvEnd:
+			b = nil
+		case tlvChassisID:
+			if len(value) > 1 {
+				nb.ChassisID = toPtr(lldpID(value[0], value[1:], chassisIDSubtypeMAC, chassisIDSubtypeAddress))
+			}
+		case tlvPortID:
+			if len(value) > 1 {
+				nb.PortID = toPtr(lldpID(value[0], value[1:], portIDSubtypeMAC, portIDSubtypeAddress))
+			}
+		case tlvPortDescription:
+			nb.PortDescription = toPtr(string(value))
+		case tlvSystemName:
+			nb.SystemName = toPtr(string(value))
+		case tlvSystemDescription:
+			nb.SystemDescription = toPtr(string(value))
+		}
+	}
+	if nb.ChassisID == nil || nb.PortID == nil {
+		return nil, errors.New("lldp frame without a chassis id or port id")
+	}
+
+	return nb, nil
+}
+
+// lldpID formats a chassis or port ID. MAC and network address IDs are formatted as such, the other subtypes are strings.
+func lldpID(subtype byte, id []byte, macSubtype, addressSubtype byte) string {
+	switch subtype {
+	case macSubtype:
+		if len(id) == 6 {
+			return net.HardwareAddr(id).String()
+		}
+	case addressSubtype:
+		// The first byte is the IANA address family.
+		if addr, ok := netip.AddrFromSlice(id[1:]); ok {
+			return addr.String()
+		}
+	}
+
+	return string(id)
+}
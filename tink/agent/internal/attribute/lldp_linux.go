@@ -0,0 +1,58 @@
+package attribute
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/tinkerbell/tinkerbell/pkg/data"
+	"golang.org/x/sys/unix"
+)
+
+// listenLLDP returns the neighbour of the first LLDP frame received on the interface name, or an error once ctx is done.
+func listenLLDP(ctx context.Context, name string) (*data.LLDPNeighbor, error) {
+	ifi, err := net.InterfaceByName(name)
+	if err != nil {
+		return nil, err
+	}
+	proto := htons(ethTypeLLDP)
+	fd, err := unix.Socket(unix.AF_PACKET, unix.SOCK_RAW|unix.SOCK_CLOEXEC, int(proto))
+	if err != nil {
+		return nil, fmt.Errorf("error opening packet socket: %w", err)
+	}
+	defer unix.Close(fd)
+	if err := unix.Bind(fd, &unix.SockaddrLinklayer{Protocol: proto, Ifindex: ifi.Index}); err != nil {
+		return nil, fmt.Errorf("error binding packet socket: %w", err)
+	}
+	// Without it, NICs may filter out the frames sent to the LLDP multicast address.
+	mreq := &unix.PacketMreq{Ifindex: int32(ifi.Index), Type: unix.PACKET_MR_MULTICAST, Alen: uint16(len(lldpMulticast))} //nolint:gosec // interface indexes fit in an int32.
+	copy(mreq.Address[:], lldpMulticast)
+	if err := unix.SetsockoptPacketMreq(fd, unix.SOL_PACKET, unix.PACKET_ADD_MEMBERSHIP, mreq); err != nil {
+		return nil, fmt.Errorf("error joining the lldp multicast group: %w", err)
+	}
+	// Receives time out every second so that ctx is checked.
+	if err := unix.SetsockoptTimeval(fd, unix.SOL_SOCKET, unix.SO_RCVTIMEO, &unix.Timeval{Sec: 1}); err != nil {
+		return nil, fmt.Errorf("error setting the receive timeout: %w", err)
+	}
+
+	buf := make([]byte, 1518)
+	for ctx.Err() == nil {
+		n, _, err := unix.Recvfrom(fd, buf, 0)
+		if errors.Is(err, unix.EAGAIN) || errors.Is(err, unix.EINTR) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error receiving lldp frame: %w", err)
+		}
+		if nb, err := parseLLDP(buf[:n]); err == nil {
+			return nb, nil
+		}
+	}
+
+	return nil, ctx.Err()
+}
+
+func htons(v uint16) uint16 {
+	return v<<8 | v>>8
+}
@@ -0,0 +1,14 @@
+//go:build !linux
+
+package attribute
+
+import (
+	"context"
+	"errors"
+
+	"github.com/tinkerbell/tinkerbell/pkg/data"
+)
+
+func listenLLDP(context.Context, string) (*data.LLDPNeighbor, error) {
+	return nil, errors.ErrUnsupported
+}
@@ -0,0 +1,79 @@
+package attribute
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tinkerbell/tinkerbell/pkg/data"
+)
+
+// lldpFrame returns an LLDP Ethernet frame with the TLVs, each a type followed by its value.
+func lldpFrame(tlvs ...[]byte) []byte {
+	frame := append([]byte{}, lldpMulticast...)
+	frame = append(frame, 0x3c, 0xec, 0xef, 0x00, 0x00, 0x01, 0x88, 0xcc)
+	for _, tlv := range tlvs {
+		length := len(tlv) - 1
+		frame = append(frame, tlv[0]<<1|byte(length>>8), byte(length))
+		frame = append(frame, tlv[1:]...)
+	}
+	return append(frame, 0x00, 0x00)
+}
+
+func TestParseLLDP(t *testing.T) {
+	tests := map[string]struct {
+		frame   []byte
+		want    *data.LLDPNeighbor
+		wantErr bool
+	}{
+		"mac chassis and interface name port": {
+			frame: lldpFrame(
+				[]byte{tlvChassisID, chassisIDSubtypeMAC, 0x3c, 0xec, 0xef, 0x00, 0x00, 0x01},
+				append([]byte{tlvPortID, 5}, "Ethernet1/12"...),
+				[]byte{3, 0x00, 0x78},
+				append([]byte{tlvPortDescription}, "server rack 4"...),
+				append([]byte{tlvSystemName}, "tor1"...),
+				append([]byte{tlvSystemDescription}, "Arista Networks EOS"...),
+			),
+			want: &data.LLDPNeighbor{
+				ChassisID:         toPtr("3c:ec:ef:00:00:01"),
+				PortID:            toPtr("Ethernet1/12"),
+				PortDescription:   toPtr("server rack 4"),
+				SystemName:        toPtr("tor1"),
+				SystemDescription: toPtr("Arista Networks EOS"),
+			},
+		},
+		"address chassis and mac port": {
+			frame: lldpFrame(
+				[]byte{tlvChassisID, chassisIDSubtypeAddress, 1, 192, 168, 2, 1},
+				[]byte{tlvPortID, portIDSubtypeMAC, 0x3c, 0xec, 0xef, 0x00, 0x00, 0x0c},
+			),
+			want: &data.LLDPNeighbor{
+				ChassisID: toPtr("192.168.2.1"),
+				PortID:    toPtr("3c:ec:ef:00:00:0c"),
+			},
+		},
+		"no port id": {
+			frame:   lldpFrame([]byte{tlvChassisID, chassisIDSubtypeMAC, 0x3c, 0xec, 0xef, 0x00, 0x00, 0x01}),
+			wantErr: true,
+		},
+		"truncated": {
+			frame:   lldpFrame([]byte{tlvChassisID, chassisIDSubtypeMAC, 0x3c, 0xec, 0xef, 0x00, 0x00, 0x01})[:18],
+			wantErr: true,
+		},
+		"not lldp": {
+			frame:   append(append([]byte{}, lldpMulticast...), 0x3c, 0xec, 0xef, 0x00, 0x00, 0x01, 0x08, 0x00),
+			wantErr: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := parseLLDP(tt.frame)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseLLDP() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("unexpected neighbor (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package attribute
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"time"
+)
+
+const smartctlTimeout = 10 * time.Second
+
+// smartStatus returns the SMART overall-health self-assessment of the disk name, "passed" or "failed", read with smartctl.
+func smartStatus(smartctl, name string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), smartctlTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, smartctl, "--json", "--health", "/dev/"+name).Output() //nolint:gosec // the disk name comes from sysfs.
+	// smartctl exits with a non-zero code for failing disks too, its output tells whether the status could be read.
+	status, perr := parseSmartctl(out)
+	if perr != nil {
+		if err != nil {
+			return "", fmt.Errorf("error running smartctl: %w", err)
+		}
+		return "", perr
+	}
+
+	return status, nil
+}
+
+// parseSmartctl returns the SMART overall-health self-assessment from the output of smartctl --json --health.
+func parseSmartctl(out []byte) (string, error) {
+	var r struct {
+		SmartStatus *struct {
+			Passed bool `json:"passed"`
+		} `json:"smart_status"`
+	}
+	if err := json.Unmarshal(out, &r); err != nil {
+		return "", fmt.Errorf("error decoding smartctl output: %w", err)
+	}
+	if r.SmartStatus == nil {
+		return "", errors.New("smartctl did not report a smart status")
+	}
+	if !r.SmartStatus.Passed {
+		return "failed", nil
+	}
+
+	return "passed", nil
+}
@@ -0,0 +1,27 @@
+package attribute
+
+import "testing"
+
+func TestParseSmartctl(t *testing.T) {
+	tests := map[string]struct {
+		out     string
+		want    string
+		wantErr bool
+	}{
+		"passed":    {out: `{"device":{"name":"/dev/sda"},"smart_status":{"passed":true}}`, want: "passed"},
+		"failed":    {out: `{"device":{"name":"/dev/sda"},"smart_status":{"passed":false}}`, want: "failed"},
+		"no status": {out: `{"smartctl":{"exit_status":4},"device":{"name":"/dev/sda"}}`, wantErr: true},
+		"no output": {wantErr: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := parseSmartctl([]byte(tt.out))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseSmartctl() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseSmartctl() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
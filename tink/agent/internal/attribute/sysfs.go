@@ -0,0 +1,60 @@
+package attribute
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/tinkerbell/tinkerbell/pkg/data"
+)
+
+const (
+	sysfsRoot = "/sys"
+
+	linkUp   = "up"
+	linkDown = "down"
+)
+
+// linkState returns whether the interface name has a carrier, "up" or "down".
+// The carrier of an interface that is administratively down can't be read, it is reported as "down".
+func linkState(root, name string) string {
+	b, err := os.ReadFile(filepath.Join(root, "class", "net", name, "carrier"))
+	if err != nil || strings.TrimSpace(string(b)) != "1" {
+		return linkDown
+	}
+
+	return linkUp
+}
+
+// DiscoverTPM returns the TPM of the machine, or nil when it has none.
+func DiscoverTPM(l logr.Logger) *data.TPM {
+	t, err := discoverTPM(sysfsRoot)
+	if err != nil {
+		l.V(1).Info("error getting tpm info", "error", err)
+		return nil
+	}
+
+	return t
+}
+
+func discoverTPM(root string) (*data.TPM, error) {
+	devices, err := filepath.Glob(filepath.Join(root, "class", "tpm", "tpm[0-9]*"))
+	if err != nil || len(devices) == 0 {
+		return nil, err
+	}
+	t := &data.TPM{}
+	// tpm_version_major is only there since Linux 5.6. Only TPM 1.2 devices have caps.
+	if b, err := os.ReadFile(filepath.Join(devices[0], "tpm_version_major")); err == nil {
+		switch strings.TrimSpace(string(b)) {
+		case "1":
+			t.Version = toPtr("1.2")
+		case "2":
+			t.Version = toPtr("2.0")
+		}
+	} else if _, err := os.Stat(filepath.Join(devices[0], "device", "caps")); err == nil {
+		t.Version = toPtr("1.2")
+	}
+
+	return t, nil
+}
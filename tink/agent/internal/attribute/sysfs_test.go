@@ -0,0 +1,64 @@
+package attribute
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tinkerbell/tinkerbell/pkg/data"
+)
+
+// writeFiles writes the files, paths relative to root mapped to their content.
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLinkState(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"class/net/eno1/carrier": "1\n",
+		"class/net/eno2/carrier": "0\n",
+	})
+	tests := map[string]string{"eno1": linkUp, "eno2": linkDown, "eno3": linkDown}
+	for name, want := range tests {
+		if got := linkState(root, name); got != want {
+			t.Errorf("linkState(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestDiscoverTPM(t *testing.T) {
+	tests := map[string]struct {
+		files map[string]string
+		want  *data.TPM
+	}{
+		"no tpm":        {},
+		"tpm 2.0":       {files: map[string]string{"class/tpm/tpm0/tpm_version_major": "2\n"}, want: &data.TPM{Version: toPtr("2.0")}},
+		"tpm 1.2":       {files: map[string]string{"class/tpm/tpm0/tpm_version_major": "1\n"}, want: &data.TPM{Version: toPtr("1.2")}},
+		"old kernel 12": {files: map[string]string{"class/tpm/tpm0/device/caps": "Manufacturer: 0x49465800\n"}, want: &data.TPM{Version: toPtr("1.2")}},
+		"old kernel 20": {files: map[string]string{"class/tpm/tpm0/dev": "10:224\n"}, want: &data.TPM{}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			root := t.TempDir()
+			writeFiles(t, root, tt.files)
+			got, err := discoverTPM(root)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("unexpected tpm (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
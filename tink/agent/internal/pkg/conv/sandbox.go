@@ -0,0 +1,44 @@
+package conv
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tinkerbell/tinkerbell/tink/agent/internal/spec"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// ParseLimits converts the CPU and memory limits of a sandbox to nano CPUs and bytes. Zero means no limit.
+func ParseLimits(s spec.Sandbox) (nanoCPUs int64, memory int64, err error) {
+	if s.CPU != "" {
+		q, err := resource.ParseQuantity(s.CPU)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid cpu limit %q: %w", s.CPU, err)
+		}
+		nanoCPUs = q.ScaledValue(resource.Nano)
+	}
+	if s.Memory != "" {
+		q, err := resource.ParseQuantity(s.Memory)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid memory limit %q: %w", s.Memory, err)
+		}
+		memory = q.Value()
+	}
+
+	return nanoCPUs, memory, nil
+}
+
+// ParseCapabilities converts capability names, like net_admin or CAP_NET_ADMIN, to their CAP_NET_ADMIN form.
+// ALL is returned as is.
+func ParseCapabilities(names []string) []string {
+	var caps []string
+	for _, n := range names {
+		n = strings.ToUpper(n)
+		if n != "ALL" && !strings.HasPrefix(n, "CAP_") {
+			n = "CAP_" + n
+		}
+		caps = append(caps, n)
+	}
+
+	return caps
+}
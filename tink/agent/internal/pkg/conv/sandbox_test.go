@@ -0,0 +1,44 @@
+package conv
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tinkerbell/tinkerbell/tink/agent/internal/spec"
+)
+
+func TestParseLimits(t *testing.T) {
+	tests := map[string]struct {
+		sandbox      spec.Sandbox
+		wantNanoCPUs int64
+		wantMemory   int64
+		wantErr      bool
+	}{
+		"no limits":      {},
+		"millicores":     {sandbox: spec.Sandbox{CPU: "500m"}, wantNanoCPUs: 500_000_000},
+		"whole cpus":     {sandbox: spec.Sandbox{CPU: "2"}, wantNanoCPUs: 2_000_000_000},
+		"binary memory":  {sandbox: spec.Sandbox{Memory: "512Mi"}, wantMemory: 512 << 20},
+		"decimal memory": {sandbox: spec.Sandbox{Memory: "1G"}, wantMemory: 1_000_000_000},
+		"invalid cpu":    {sandbox: spec.Sandbox{CPU: "half"}, wantErr: true},
+		"invalid memory": {sandbox: spec.Sandbox{Memory: "lots"}, wantErr: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			cpus, memory, err := ParseLimits(tt.sandbox)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseLimits() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if cpus != tt.wantNanoCPUs || memory != tt.wantMemory {
+				t.Errorf("ParseLimits() = %d, %d, want %d, %d", cpus, memory, tt.wantNanoCPUs, tt.wantMemory)
+			}
+		})
+	}
+}
+
+func TestParseCapabilities(t *testing.T) {
+	got := ParseCapabilities([]string{"net_admin", "CAP_SYS_ADMIN", "all"})
+	want := []string{"CAP_NET_ADMIN", "CAP_SYS_ADMIN", "ALL"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected capabilities (-want +got):\n%s", diff)
+	}
+}
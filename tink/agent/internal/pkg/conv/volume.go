@@ -18,7 +18,7 @@ Portions of this file are adapted from github.com/containerd/nerdctl,
 which is licensed under the Apache License, Version 2.0.
 */
 
-package containerd
+package conv
 
 import (
 	"errors"
@@ -37,7 +37,7 @@ const (
 	rwOption = "rw"
 )
 
-// parseVolumes converts action volumes to OCI runtime spec mounts.
+// ParseVolumes converts action volumes to OCI runtime spec mounts.
 // Volume format: {SRC-HOST-DIR}:{TGT-CONTAINER-DIR}[:OPTIONS]
 // Options can include: ro (read-only), rw (read-write, default)
 // Only bind mounts with absolute or relative path sources are supported;
@@ -45,7 +45,7 @@ const (
 // Examples:
 //   - /etc/data:/data:ro     - Read-only bind mount
 //   - /tmp/work:/work        - Read-write bind mount (default)
-func parseVolumes(log logr.Logger, volumes []spec.Volume) []specs.Mount {
+func ParseVolumes(log logr.Logger, volumes []spec.Volume) []specs.Mount {
 	var mounts []specs.Mount
 	for _, v := range volumes {
 		mount := parseVolume(log, string(v))
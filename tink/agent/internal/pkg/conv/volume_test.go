@@ -1,4 +1,4 @@
-package containerd
+package conv
 
 import (
 	"os"
@@ -174,25 +174,25 @@ func TestParseVolumes(t *testing.T) {
 	log := logr.Discard()
 
 	t.Run("nil volumes", func(t *testing.T) {
-		got := parseVolumes(log, nil)
+		got := ParseVolumes(log, nil)
 		if len(got) != 0 {
-			t.Errorf("parseVolumes(nil) returned %d mounts, want 0", len(got))
+			t.Errorf("ParseVolumes(nil) returned %d mounts, want 0", len(got))
 		}
 	})
 
 	t.Run("empty volumes", func(t *testing.T) {
-		got := parseVolumes(log, []spec.Volume{})
+		got := ParseVolumes(log, []spec.Volume{})
 		if len(got) != 0 {
-			t.Errorf("parseVolumes([]) returned %d mounts, want 0", len(got))
+			t.Errorf("ParseVolumes([]) returned %d mounts, want 0", len(got))
 		}
 	})
 
 	t.Run("single valid volume with existing source", func(t *testing.T) {
 		src := t.TempDir()
 		vols := []spec.Volume{spec.Volume(src + ":/container")}
-		got := parseVolumes(log, vols)
+		got := ParseVolumes(log, vols)
 		if len(got) != 1 {
-			t.Fatalf("parseVolumes() returned %d mounts, want 1", len(got))
+			t.Fatalf("ParseVolumes() returned %d mounts, want 1", len(got))
 		}
 		if got[0].Source != src {
 			t.Errorf("Source = %q, want %q", got[0].Source, src)
@@ -203,9 +203,9 @@ func TestParseVolumes(t *testing.T) {
 		base := t.TempDir()
 		src := filepath.Join(base, "nonexistent", "deep")
 		vols := []spec.Volume{spec.Volume(src + ":/container")}
-		got := parseVolumes(log, vols)
+		got := ParseVolumes(log, vols)
 		if len(got) != 1 {
-			t.Fatalf("parseVolumes() returned %d mounts, want 1", len(got))
+			t.Fatalf("ParseVolumes() returned %d mounts, want 1", len(got))
 		}
 		if got[0].Source != src {
 			t.Errorf("Source = %q, want %q", got[0].Source, src)
@@ -226,9 +226,9 @@ func TestParseVolumes(t *testing.T) {
 			spec.Volume(src1 + ":/container1"),
 			spec.Volume(src2 + ":/container2:ro"),
 		}
-		got := parseVolumes(log, vols)
+		got := ParseVolumes(log, vols)
 		if len(got) != 2 {
-			t.Errorf("parseVolumes() returned %d mounts, want 2", len(got))
+			t.Errorf("ParseVolumes() returned %d mounts, want 2", len(got))
 		}
 	})
 
@@ -241,9 +241,9 @@ func TestParseVolumes(t *testing.T) {
 			"namedvol:/container2",
 			spec.Volume(src3 + ":/container3:rw"),
 		}
-		got := parseVolumes(log, vols)
+		got := ParseVolumes(log, vols)
 		if len(got) != 2 {
-			t.Errorf("parseVolumes() returned %d mounts, want 2", len(got))
+			t.Errorf("ParseVolumes() returned %d mounts, want 2", len(got))
 		}
 	})
 
@@ -252,9 +252,9 @@ func TestParseVolumes(t *testing.T) {
 			"namedvol:/data",
 			"invalidformat",
 		}
-		got := parseVolumes(log, vols)
+		got := ParseVolumes(log, vols)
 		if len(got) != 0 {
-			t.Errorf("parseVolumes() returned %d mounts, want 0", len(got))
+			t.Errorf("ParseVolumes() returned %d mounts, want 0", len(got))
 		}
 	})
 
@@ -270,9 +270,9 @@ func TestParseVolumes(t *testing.T) {
 			t.Fatal(err)
 		}
 		vols := []spec.Volume{"./reldir:/container"}
-		got := parseVolumes(log, vols)
+		got := ParseVolumes(log, vols)
 		if len(got) != 1 {
-			t.Fatalf("parseVolumes() returned %d mounts, want 1", len(got))
+			t.Fatalf("ParseVolumes() returned %d mounts, want 1", len(got))
 		}
 		want := filepath.Join(base, "reldir")
 		if got[0].Source != want {
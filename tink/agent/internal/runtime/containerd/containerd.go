@@ -236,10 +236,31 @@ func NewConfig(log logr.Logger, opts ...Opt) (*Config, error) {
 	return c, nil
 }
 
-func (c *Config) Execute(ctx context.Context, a spec.Action) error {
+// PullImage pulls image, unless it is already in the namespace.
+func (c *Config) PullImage(ctx context.Context, image string) error {
+	_, err := c.pullImage(namespaces.WithNamespace(ctx, c.Namespace), image)
+	return err
+}
+
+// LoadImages imports the images of an OCI image layout, or docker save, tarball into the namespace and unpacks them.
+func (c *Config) LoadImages(ctx context.Context, r io.Reader) error {
 	ctx = namespaces.WithNamespace(ctx, c.Namespace)
-	// Pull the image
-	imageName := a.Image
+	imgs, err := c.Client.Import(ctx, r)
+	if err != nil {
+		return fmt.Errorf("error importing images: %w", err)
+	}
+	for _, img := range imgs {
+		if err := containerd.NewImage(c.Client, img).Unpack(ctx, ""); err != nil {
+			return fmt.Errorf("error unpacking image %v: %w", img.Name, err)
+		}
+		c.Log.V(1).Info("image loaded", "image", img.Name)
+	}
+
+	return nil
+}
+
+// pullImage returns the image named imageName, pulling it if it isn't already in the namespace of ctx.
+func (c *Config) pullImage(ctx context.Context, imageName string) (containerd.Image, error) {
 	r, err := shortnames.Resolve(&types.SystemContext{PodmanOnlyShortNamesIgnoreRegistriesConfAndForceDockerHub: true}, imageName)
 	if err != nil {
 		c.Log.Info("unable to resolve image fully qualified name", "error", err)
@@ -248,22 +269,33 @@ func (c *Config) Execute(ctx context.Context, a spec.Action) error {
 		imageName = r.PullCandidates[0].Value.String()
 	}
 	image, err := c.Client.GetImage(ctx, imageName)
-	if err != nil {
-		// if the image isn't already in our namespaced context, then pull it
-		resolver := newResolver(ctx, c.RegistryConfigPath)
-		pullImage := func() error {
-			image, err = c.Client.Pull(ctx, imageName, containerd.WithPullUnpack, containerd.WithResolver(resolver))
-			if err != nil {
-				return fmt.Errorf("error pulling image: %w", err)
-			}
-			c.Log.V(1).Info("image pulled", "image", image.Name())
-
-			return nil
-		}
-		err := retry.Do(pullImage, retry.Attempts(5), retry.Delay(2*time.Second), retry.MaxDelay(10*time.Second), retry.DelayType(retry.BackOffDelay))
+	if err == nil {
+		return image, nil
+	}
+	// if the image isn't already in our namespaced context, then pull it
+	resolver := newResolver(ctx, c.RegistryConfigPath)
+	pullImage := func() error {
+		image, err = c.Client.Pull(ctx, imageName, containerd.WithPullUnpack, containerd.WithResolver(resolver))
 		if err != nil {
-			return err
+			return fmt.Errorf("error pulling image: %w", err)
 		}
+		c.Log.V(1).Info("image pulled", "image", image.Name())
+
+		return nil
+	}
+	if err := retry.Do(pullImage, retry.Attempts(5), retry.Delay(2*time.Second), retry.MaxDelay(10*time.Second), retry.DelayType(retry.BackOffDelay)); err != nil {
+		return nil, err
+	}
+
+	return image, nil
+}
+
+func (c *Config) Execute(ctx context.Context, a spec.Action) error {
+	ctx = namespaces.WithNamespace(ctx, c.Namespace)
+	// Pull the image
+	image, err := c.pullImage(ctx, a.Image)
+	if err != nil {
+		return err
 	}
 
 	// Determine network mode.
@@ -339,8 +371,9 @@ func (c *Config) Execute(ctx context.Context, a spec.Action) error {
 	}
 
 	// Open the json-file log writer pair and tee container stdout/stderr
-	// into both the json-file (for `nerdctl logs`) and tink-agent's own
-	// stdout/stderr (which are forwarded via syslog to tink-server).
+	// into the json-file (for `nerdctl logs`), tink-agent's own
+	// stdout/stderr (which are forwarded via syslog to tink-server) and
+	// the action log stream, if there is one.
 	logPair, err := newJSONLogPair(containerLogFile(dataStore, c.Namespace, containerID))
 	if err != nil {
 		return fmt.Errorf("failed to open container log file: %w", err)
@@ -362,10 +395,11 @@ func (c *Config) Execute(ctx context.Context, a spec.Action) error {
 	}()
 
 	// create the task
+	logs := spec.LogsFrom(ctx)
 	task, err := tainer.NewTask(ctx, cio.NewCreator(cio.WithStreams(
 		nil,
-		io.MultiWriter(os.Stdout, logPair.Stdout),
-		io.MultiWriter(os.Stderr, logPair.Stderr),
+		io.MultiWriter(os.Stdout, logPair.Stdout, logs.Stdout),
+		io.MultiWriter(os.Stderr, logPair.Stderr, logs.Stderr),
 	)))
 	if err != nil {
 		_ = logPair.Close()
@@ -448,11 +482,13 @@ func (c *Config) createContainer(ctx context.Context, image containerd.Image, ac
 	newOpts := []containerd.NewContainerOpts{}
 	specOpts := []oci.SpecOpts{
 		oci.WithImageConfig(image), // Loads ENTRYPOINT and CMD from image
-		oci.WithPrivileged,
-		oci.WithAllDevicesAllowed, // Allow access to all devices via cgroup rules
-		oci.WithHostDevices,       // Mount all host devices into the container
 		oci.WithEnv(conv.ParseEnv(action.Env)),
 	}
+	sandboxOpts, err := sandboxSpecOpts(action.Sandbox)
+	if err != nil {
+		return nil, "", err
+	}
+	specOpts = append(specOpts, sandboxOpts...)
 
 	// Replicate Docker's Entrypoint/Cmd semantics:
 	// - action.Cmd maps to Docker's Entrypoint (the binary to run)
@@ -473,7 +509,7 @@ func (c *Config) createContainer(ctx context.Context, image containerd.Image, ac
 
 	// Add volume mounts
 	if len(action.Volumes) > 0 {
-		mounts := parseVolumes(c.Log, action.Volumes)
+		mounts := conv.ParseVolumes(c.Log, action.Volumes)
 		if len(mounts) > 0 {
 			specOpts = append(specOpts, oci.WithMounts(mounts))
 			c.Log.V(1).Info("volume mounts configured", "count", len(mounts))
@@ -0,0 +1,81 @@
+/*
+Copyright The containerd Authors.
+Copyright The Tinkerbell Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerd
+
+import (
+	"slices"
+
+	"github.com/containerd/containerd/v2/contrib/seccomp"
+	"github.com/containerd/containerd/v2/pkg/oci"
+	"github.com/tinkerbell/tinkerbell/tink/agent/internal/pkg/conv"
+	"github.com/tinkerbell/tinkerbell/tink/agent/internal/spec"
+)
+
+// cpuPeriod is the CFS period, in microseconds, CPU limits are enforced over. It is the kernel and Docker default.
+const cpuPeriod = 100000
+
+// sandboxSpecOpts returns the spec options that apply the sandbox of an action to its container.
+// Containers are privileged, with access to all host devices, unless the sandbox sets Privileged to false.
+// Unprivileged containers get containerd's default capabilities, devices, seccomp profile and masked paths.
+func sandboxSpecOpts(s spec.Sandbox) ([]oci.SpecOpts, error) {
+	var opts []oci.SpecOpts
+	if s.IsPrivileged(true) {
+		opts = append(opts,
+			oci.WithPrivileged,
+			oci.WithAllDevicesAllowed, // Allow access to all devices via cgroup rules
+			oci.WithHostDevices,       // Mount all host devices into the container
+		)
+	} else {
+		// The same as Docker, dropping ALL drops every capability before the others in add are added.
+		add, drop := conv.ParseCapabilities(s.CapAdd), conv.ParseCapabilities(s.CapDrop)
+		if slices.Contains(add, "ALL") {
+			opts = append(opts, oci.WithAllKnownCapabilities)
+			add = slices.DeleteFunc(add, func(c string) bool { return c == "ALL" })
+		}
+		switch {
+		case slices.Contains(drop, "ALL"):
+			opts = append(opts, oci.WithCapabilities(nil))
+		case len(drop) > 0:
+			opts = append(opts, oci.WithDroppedCapabilities(drop))
+		}
+		if len(add) > 0 {
+			opts = append(opts, oci.WithAddedCapabilities(add))
+		}
+		// The default profile allows the syscalls of the capabilities set above, it must follow them.
+		opts = append(opts, seccomp.WithDefaultProfile())
+	}
+
+	nanoCPUs, memory, err := conv.ParseLimits(s)
+	if err != nil {
+		return nil, err
+	}
+	if nanoCPUs > 0 {
+		opts = append(opts, oci.WithCPUCFS(nanoCPUs*cpuPeriod/1e9, cpuPeriod))
+	}
+	if memory > 0 {
+		opts = append(opts, oci.WithMemoryLimit(uint64(memory)))
+	}
+	if s.PIDs > 0 {
+		opts = append(opts, oci.WithPidsLimit(s.PIDs))
+	}
+	if s.ReadOnlyRootFilesystem {
+		opts = append(opts, oci.WithRootFSReadonly())
+	}
+
+	return opts, nil
+}
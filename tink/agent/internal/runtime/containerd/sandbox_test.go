@@ -0,0 +1,82 @@
+package containerd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/containerd/containerd/v2/core/containers"
+	"github.com/containerd/containerd/v2/pkg/oci"
+	"github.com/google/go-cmp/cmp"
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/tinkerbell/tinkerbell/tink/agent/internal/spec"
+)
+
+func TestSandboxSpecOpts(t *testing.T) {
+	caps := func() *specs.LinuxCapabilities {
+		c := []string{"CAP_CHOWN", "CAP_NET_RAW"}
+		return &specs.LinuxCapabilities{Bounding: c, Effective: c, Permitted: c}
+	}
+	tests := map[string]struct {
+		sandbox       spec.Sandbox
+		wantCaps      []string
+		wantResources *specs.LinuxResources
+		wantReadonly  bool
+		wantErr       bool
+	}{
+		"drop and add": {
+			sandbox:  spec.Sandbox{Privileged: new(bool), CapDrop: []string{"net_raw"}, CapAdd: []string{"SYS_ADMIN"}},
+			wantCaps: []string{"CAP_CHOWN", "CAP_SYS_ADMIN"},
+		},
+		"drop all": {
+			sandbox:  spec.Sandbox{Privileged: new(bool), CapDrop: []string{"ALL"}, CapAdd: []string{"CAP_NET_ADMIN"}},
+			wantCaps: []string{"CAP_NET_ADMIN"},
+		},
+		"limits": {
+			sandbox:  spec.Sandbox{Privileged: new(bool), CPU: "1500m", Memory: "1Gi", PIDs: 100, ReadOnlyRootFilesystem: true},
+			wantCaps: []string{"CAP_CHOWN", "CAP_NET_RAW"},
+			wantResources: &specs.LinuxResources{
+				CPU:    &specs.LinuxCPU{Quota: ptr(int64(150000)), Period: ptr(uint64(cpuPeriod))},
+				Memory: &specs.LinuxMemory{Limit: ptr(int64(1 << 30))},
+				Pids:   &specs.LinuxPids{Limit: ptr(int64(100))},
+			},
+			wantReadonly: true,
+		},
+		"invalid limit": {
+			sandbox: spec.Sandbox{Memory: "lots"},
+			wantErr: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			opts, err := sandboxSpecOpts(tt.sandbox)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("sandboxSpecOpts() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			s := &oci.Spec{Root: &specs.Root{}, Process: &specs.Process{Capabilities: caps()}, Linux: &specs.Linux{}}
+			for _, o := range opts {
+				if err := o(context.Background(), nil, &containers.Container{}, s); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if diff := cmp.Diff(tt.wantCaps, s.Process.Capabilities.Bounding); diff != "" {
+				t.Errorf("unexpected capabilities (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantResources, s.Linux.Resources); diff != "" {
+				t.Errorf("unexpected resources (-want +got):\n%s", diff)
+			}
+			if s.Root.Readonly != tt.wantReadonly {
+				t.Errorf("Root.Readonly = %v, want %v", s.Root.Readonly, tt.wantReadonly)
+			}
+			if s.Linux.Seccomp == nil {
+				t.Error("expected the default seccomp profile to be applied to an unprivileged container")
+			}
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -6,10 +6,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"sync"
 	"time"
 
 	retry "github.com/avast/retry-go/v4"
 	"github.com/go-logr/logr"
+	"github.com/moby/moby/api/pkg/stdcopy"
 	"github.com/moby/moby/api/types/container"
 	"github.com/moby/moby/api/types/registry"
 	"github.com/moby/moby/client"
@@ -21,47 +23,78 @@ type Config struct {
 	Log          logr.Logger
 	Client       *client.Client
 	RegistryAuth *registry.AuthConfig
+
+	// pulled are the images pulled by PullImage that no Execute used yet. Execute does not pull them again,
+	// and removes them so that later Executes pull again and tags like latest do not go stale.
+	pulled sync.Map
 }
 
-func (c *Config) Execute(ctx context.Context, a spec.Action) error {
-	pullImage := func() error {
-		pullOpts := client.ImagePullOptions{}
-
-		// Check if authentication should be used for this image
-		// Only apply auth to images from the exact registry that is configured for authentication
-		if c.RegistryAuth != nil && useAuth(a.Image, c.RegistryAuth.ServerAddress) {
-			encodedJSON, err := json.Marshal(c.RegistryAuth) //nolint:gosec // G117: intentionally marshaling auth config to pass to Docker API
-			if err != nil {
-				return fmt.Errorf("unable to encode auth config: %w", err)
-			}
-			pullOpts.RegistryAuth = base64.URLEncoding.EncodeToString(encodedJSON)
-		}
+// PullImage pulls image and records it as pulled.
+func (c *Config) PullImage(ctx context.Context, image string) error {
+	if err := retry.Do(func() error { return c.pullImage(ctx, image) }, retry.Attempts(5), retry.DelayType(retry.BackOffDelay)); err != nil {
+		return err
+	}
+	c.pulled.Store(image, struct{}{})
 
-		img, err := c.Client.ImagePull(ctx, a.Image, pullOpts)
+	return nil
+}
+
+// LoadImages loads the images of an OCI image layout, or docker save, tarball.
+func (c *Config) LoadImages(ctx context.Context, r io.Reader) error {
+	resp, err := c.Client.ImageLoad(ctx, r)
+	if err != nil {
+		return fmt.Errorf("docker: %w", err)
+	}
+	defer resp.Close()
+
+	// The images are only loaded once the response is read.
+	if _, err := io.Copy(io.Discard, resp); err != nil {
+		return fmt.Errorf("docker: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Config) pullImage(ctx context.Context, image string) error {
+	pullOpts := client.ImagePullOptions{}
+
+	// Check if authentication should be used for this image
+	// Only apply auth to images from the exact registry that is configured for authentication
+	if c.RegistryAuth != nil && useAuth(image, c.RegistryAuth.ServerAddress) {
+		encodedJSON, err := json.Marshal(c.RegistryAuth) //nolint:gosec // G117: intentionally marshaling auth config to pass to Docker API
 		if err != nil {
-			// If the image is already present, we can ignore the error.
-			// This might be the case where the image is already present in the local cache
-			// and the environment doesn't have access to the registry.
-			// Embedded images in HookOS are a partial example of this.
-			if _, err := c.Client.ImageInspect(ctx, a.Image); err == nil {
-				return nil
-			}
-			return fmt.Errorf("docker: %w", err)
+			return fmt.Errorf("unable to encode auth config: %w", err)
 		}
-		defer img.Close()
+		pullOpts.RegistryAuth = base64.URLEncoding.EncodeToString(encodedJSON)
+	}
 
-		// Docker requires everything to be read from the images ReadCloser for the image to actually
-		// be pulled. We may want to log image pulls in a circular buffer somewhere for debug-ability.
-		if _, err = io.Copy(io.Discard, img); err != nil {
-			return fmt.Errorf("docker: %w", err)
+	img, err := c.Client.ImagePull(ctx, image, pullOpts)
+	if err != nil {
+		// If the image is already present, we can ignore the error.
+		// This might be the case where the image is already present in the local cache
+		// and the environment doesn't have access to the registry.
+		// Embedded images in HookOS are a partial example of this.
+		if _, err := c.Client.ImageInspect(ctx, image); err == nil {
+			return nil
 		}
+		return fmt.Errorf("docker: %w", err)
+	}
+	defer img.Close()
 
-		return nil
+	// Docker requires everything to be read from the images ReadCloser for the image to actually
+	// be pulled. We may want to log image pulls in a circular buffer somewhere for debug-ability.
+	if _, err = io.Copy(io.Discard, img); err != nil {
+		return fmt.Errorf("docker: %w", err)
 	}
 
-	err := retry.Do(pullImage, retry.Attempts(5), retry.DelayType(retry.BackOffDelay))
-	if err != nil {
-		return err
+	return nil
+}
+
+func (c *Config) Execute(ctx context.Context, a spec.Action) error {
+	if _, ok := c.pulled.LoadAndDelete(a.Image); !ok {
+		if err := retry.Do(func() error { return c.pullImage(ctx, a.Image) }, retry.Attempts(5), retry.DelayType(retry.BackOffDelay)); err != nil {
+			return err
+		}
 	}
 
 	// TODO: Support all the other things on the action such as volumes.
@@ -71,8 +104,23 @@ func (c *Config) Execute(ctx context.Context, a spec.Action) error {
 	}
 
 	hostCfg := container.HostConfig{
-		Binds:      []string{},
-		Privileged: true,
+		Binds:          []string{},
+		Privileged:     a.Sandbox.IsPrivileged(true),
+		ReadonlyRootfs: a.Sandbox.ReadOnlyRootFilesystem,
+	}
+	if !hostCfg.Privileged {
+		// Docker ignores capabilities for privileged containers, they have all of them.
+		hostCfg.CapAdd = conv.ParseCapabilities(a.Sandbox.CapAdd)
+		hostCfg.CapDrop = conv.ParseCapabilities(a.Sandbox.CapDrop)
+	}
+	nanoCPUs, memory, err := conv.ParseLimits(a.Sandbox)
+	if err != nil {
+		return err
+	}
+	hostCfg.NanoCPUs = nanoCPUs
+	hostCfg.Memory = memory
+	if a.Sandbox.PIDs > 0 {
+		hostCfg.PidsLimit = &a.Sandbox.PIDs
 	}
 	if a.Namespaces.PID != "" {
 		hostCfg.PidMode = container.PidMode(a.Namespaces.PID)
@@ -144,20 +192,14 @@ func (c *Config) Execute(ctx context.Context, a spec.Action) error {
 	}
 	defer attachResp.Close()
 
-	// Stream logs in a goroutine so we don't block waiting for the container to exit
+	// Stream logs in a goroutine so we don't block waiting for the container to exit.
+	// The attach stream multiplexes stdout and stderr, stdcopy splits them back out so that
+	// each can also be copied to the action log stream.
+	logs := spec.LogsFrom(ctx)
+	out := &logWriter{log: c.Log, containerName: containerName}
 	go func() {
-		buf := make([]byte, 4096)
-		for {
-			n, err := attachResp.Reader.Read(buf)
-			if n > 0 {
-				c.Log.Info(string(buf[:n]), "container_name", containerName)
-			}
-			if err != nil {
-				if err != io.EOF {
-					c.Log.Error(err, "error reading container output", "container_name", containerName)
-				}
-				break
-			}
+		if _, err := stdcopy.StdCopy(io.MultiWriter(out, logs.Stdout), io.MultiWriter(out, logs.Stderr), attachResp.Reader); err != nil {
+			c.Log.Error(err, "error reading container output", "container_name", containerName)
 		}
 	}()
 
@@ -183,6 +225,17 @@ func (c *Config) Execute(ctx context.Context, a spec.Action) error {
 	}
 }
 
+// logWriter logs everything written to it with the name of the container it came from.
+type logWriter struct {
+	log           logr.Logger
+	containerName string
+}
+
+func (w *logWriter) Write(p []byte) (int, error) {
+	w.log.Info(string(p), "container_name", w.containerName)
+	return len(p), nil
+}
+
 func toPtr[T any](v T) *T {
 	return &v
 }
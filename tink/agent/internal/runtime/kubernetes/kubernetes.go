@@ -17,6 +17,7 @@ import (
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/apimachinery/pkg/watch"
@@ -139,7 +140,7 @@ func (c *Config) Execute(ctx context.Context, a spec.Action) error {
 		return err
 	}
 
-	c.streamLogs(pod.Name)
+	c.streamLogs(pod.Name, spec.LogsFrom(ctx).Stdout)
 
 	return exitError(pod)
 }
@@ -193,13 +194,22 @@ func (c *Config) jobFor(a spec.Action) (*batchv1.Job, error) {
 	if len(a.Volumes) > 0 {
 		return nil, fmt.Errorf("kubernetes runtime: volumes are not supported")
 	}
+	if a.Sandbox.PIDs > 0 {
+		return nil, fmt.Errorf("kubernetes runtime: pids limits are not supported")
+	}
+	resources, err := resourceLimits(a.Sandbox)
+	if err != nil {
+		return nil, fmt.Errorf("kubernetes runtime: %w", err)
+	}
 
 	backoffLimit := int32(0) // retries are handled by (*agent.Config).Run, not the Job
 
 	container := corev1.Container{
-		Name:  actionContainerName,
-		Image: a.Image,
-		Env:   convEnv(a.Env),
+		Name:            actionContainerName,
+		Image:           a.Image,
+		Env:             convEnv(a.Env),
+		Resources:       resources,
+		SecurityContext: securityContext(a.Sandbox),
 	}
 	if a.Cmd != "" {
 		container.Command = []string{a.Cmd}
@@ -233,15 +243,59 @@ func (c *Config) jobFor(a spec.Action) (*batchv1.Job, error) {
 					// Agent does), so don't hand them an API token regardless of which
 					// ServiceAccount they run as.
 					AutomountServiceAccountToken: boolPtr(false),
-					// No SecurityContext/Privileged: deliberately the Kubernetes default
-					// (unprivileged), unlike docker.go/containerd.go which both set Privileged
-					// unconditionally for the bare-metal case this runtime isn't used for.
+					// The container only gets a SecurityContext when the Action's sandbox asks for
+					// one, so it is unprivileged by default, the Kubernetes default, unlike
+					// docker.go/containerd.go which are privileged by default for the bare-metal
+					// case this runtime isn't used for.
 				},
 			},
 		},
 	}, nil
 }
 
+// resourceLimits returns the CPU and memory limits of the Action's sandbox. Requests default to the limits.
+func resourceLimits(s spec.Sandbox) (corev1.ResourceRequirements, error) {
+	limits := corev1.ResourceList{}
+	for name, v := range map[corev1.ResourceName]string{corev1.ResourceCPU: s.CPU, corev1.ResourceMemory: s.Memory} {
+		if v == "" {
+			continue
+		}
+		q, err := resource.ParseQuantity(v)
+		if err != nil {
+			return corev1.ResourceRequirements{}, fmt.Errorf("invalid %s limit %q: %w", name, v, err)
+		}
+		limits[name] = q
+	}
+	if len(limits) == 0 {
+		return corev1.ResourceRequirements{}, nil
+	}
+	return corev1.ResourceRequirements{Limits: limits}, nil
+}
+
+// securityContext returns the SecurityContext of the Action's sandbox, or nil when the sandbox sets nothing it covers.
+func securityContext(s spec.Sandbox) *corev1.SecurityContext {
+	if s.Privileged == nil && !s.ReadOnlyRootFilesystem && len(s.CapAdd) == 0 && len(s.CapDrop) == 0 {
+		return nil
+	}
+	sc := &corev1.SecurityContext{Privileged: s.Privileged}
+	if s.ReadOnlyRootFilesystem {
+		sc.ReadOnlyRootFilesystem = boolPtr(true)
+	}
+	if len(s.CapAdd) > 0 || len(s.CapDrop) > 0 {
+		sc.Capabilities = &corev1.Capabilities{Add: capabilities(s.CapAdd), Drop: capabilities(s.CapDrop)}
+	}
+	return sc
+}
+
+// capabilities converts capability names to the Kubernetes form, upper case without the CAP_ prefix.
+func capabilities(names []string) []corev1.Capability {
+	var caps []corev1.Capability
+	for _, n := range names {
+		caps = append(caps, corev1.Capability(strings.TrimPrefix(strings.ToUpper(n), "CAP_")))
+	}
+	return caps
+}
+
 // activeDeadlineSeconds returns nil if the Action has no timeout, so the Job never gets an
 // ActiveDeadlineSeconds it wasn't asked for.
 func activeDeadlineSeconds(a spec.Action) *int64 {
@@ -366,8 +420,9 @@ func actionContainerStatus(pod *corev1.Pod) corev1.ContainerState {
 // about to be deleted, so failures here shouldn't affect the Action's outcome. It deliberately
 // uses its own bounded timeout rather than Execute's ctx (which may already be done) or an
 // unbounded context.Background() (which could hang Execute, and therefore the single-threaded
-// agent.Config.Run loop, forever if the log endpoint stalls).
-func (c *Config) streamLogs(podName string) {
+// agent.Config.Run loop, forever if the log endpoint stalls). The Pod log combines stdout and
+// stderr, so all of it is also copied to w.
+func (c *Config) streamLogs(podName string, w io.Writer) {
 	ctx, cancel := context.WithTimeout(context.Background(), logStreamTimeout)
 	defer cancel()
 
@@ -383,6 +438,7 @@ func (c *Config) streamLogs(podName string) {
 		n, err := rc.Read(buf)
 		if n > 0 {
 			c.Log.Info(string(buf[:n]), "pod", podName)
+			_, _ = w.Write(buf[:n])
 		}
 		if err != nil {
 			if err != io.EOF {
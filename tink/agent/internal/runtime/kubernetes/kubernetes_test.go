@@ -13,6 +13,7 @@ import (
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/watch"
@@ -131,6 +132,54 @@ func TestJobFor(t *testing.T) {
 				},
 			},
 		},
+		"sandbox sets limits and security context": {
+			action: spec.Action{ID: "id8", Name: "n8", Image: "img", Sandbox: spec.Sandbox{
+				CPU:                    "500m",
+				Memory:                 "256Mi",
+				CapAdd:                 []string{"CAP_NET_ADMIN"},
+				CapDrop:                []string{"all"},
+				ReadOnlyRootFilesystem: true,
+				Privileged:             boolPtr(false),
+			}},
+			want: &batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "tinkerbell-n8-id8",
+					Namespace: "tinkerbell",
+					Labels:    map[string]string{actionIDLabel: "id8"},
+				},
+				Spec: batchv1.JobSpec{
+					BackoffLimit: int32Ptr(0),
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{actionIDLabel: "id8"}},
+						Spec: corev1.PodSpec{
+							RestartPolicy:                corev1.RestartPolicyNever,
+							AutomountServiceAccountToken: boolPtr(false),
+							Containers: []corev1.Container{{
+								Name:  "action",
+								Image: "img",
+								Resources: corev1.ResourceRequirements{Limits: corev1.ResourceList{
+									corev1.ResourceCPU:    resource.MustParse("500m"),
+									corev1.ResourceMemory: resource.MustParse("256Mi"),
+								}},
+								SecurityContext: &corev1.SecurityContext{
+									Privileged:             boolPtr(false),
+									ReadOnlyRootFilesystem: boolPtr(true),
+									Capabilities:           &corev1.Capabilities{Add: []corev1.Capability{"NET_ADMIN"}, Drop: []corev1.Capability{"ALL"}},
+								},
+							}},
+						},
+					},
+				},
+			},
+		},
+		"invalid memory limit rejected": {
+			action:  spec.Action{ID: "id9", Sandbox: spec.Sandbox{Memory: "lots"}},
+			wantErr: true,
+		},
+		"pids limit rejected": {
+			action:  spec.Action{ID: "id10", Sandbox: spec.Sandbox{PIDs: 100}},
+			wantErr: true,
+		},
 		"host pid namespace rejected": {
 			action:  spec.Action{ID: "id3", Namespaces: spec.Namespaces{PID: "host"}},
 			wantErr: true,
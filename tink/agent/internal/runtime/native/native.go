@@ -0,0 +1,70 @@
+// Package native runs Actions directly on the host, as a binary or script, without a container.
+package native
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"syscall"
+
+	"github.com/go-logr/logr"
+	"github.com/tinkerbell/tinkerbell/tink/agent/internal/spec"
+)
+
+type Config struct {
+	Log logr.Logger
+}
+
+// Execute runs the Action's command on the host and blocks until it exits or ctx is done, in which case it is killed.
+// The command is Cmd followed by Args, or Args when Cmd is empty. It runs with the Agent's environment and the Action's.
+// The Action's image, volumes and namespaces are ignored.
+func (c *Config) Execute(ctx context.Context, a spec.Action) error {
+	args := command(a)
+	if len(args) == 0 {
+		return errors.New("native action has no command")
+	}
+	logs := spec.LogsFrom(ctx)
+	cmd := exec.Command(args[0], args[1:]...) //nolint:gosec // native commands are allowlisted by the server.
+	cmd.Env = os.Environ()
+	for _, e := range a.Env {
+		cmd.Env = append(cmd.Env, e.Key+"="+e.Value)
+	}
+	cmd.Stdout = io.MultiWriter(os.Stdout, logs.Stdout)
+	cmd.Stderr = io.MultiWriter(os.Stderr, logs.Stderr)
+	// The command runs in its own process group so that anything it starts is killed with it.
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("error starting command: %w", err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+
+	select {
+	case err := <-done:
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return fmt.Errorf("command exited with non-zero code: %d", exitErr.ExitCode())
+		}
+		if err != nil {
+			return fmt.Errorf("error running command: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+		if err := syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL); err != nil {
+			c.Log.Error(err, "failed to kill command after context cancellation", "pid", cmd.Process.Pid)
+		}
+		<-done
+		return fmt.Errorf("context cancelled while waiting for command: %w", ctx.Err())
+	}
+}
+
+func command(a spec.Action) []string {
+	if a.Cmd == "" {
+		return a.Args
+	}
+
+	return append([]string{a.Cmd}, a.Args...)
+}
@@ -0,0 +1,72 @@
+package native
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/tinkerbell/tinkerbell/tink/agent/internal/spec"
+)
+
+func TestExecute(t *testing.T) {
+	tests := map[string]struct {
+		action     spec.Action
+		timeout    time.Duration
+		wantStdout string
+		wantErr    string
+		wantCtxErr error
+	}{
+		"success": {
+			action:     spec.Action{Args: []string{"/bin/sh", "-c", "echo $GREETING"}, Env: []spec.Env{{Key: "GREETING", Value: "hello"}}},
+			wantStdout: "hello\n",
+		},
+		"cmd and args": {
+			action:     spec.Action{Cmd: "/bin/sh", Args: []string{"-c", "echo args"}},
+			wantStdout: "args\n",
+		},
+		"non-zero exit": {
+			action:  spec.Action{Args: []string{"/bin/sh", "-c", "exit 3"}},
+			wantErr: "command exited with non-zero code: 3",
+		},
+		"timeout": {
+			action:     spec.Action{Args: []string{"/bin/sh", "-c", "sleep 30"}},
+			timeout:    100 * time.Millisecond,
+			wantCtxErr: context.DeadlineExceeded,
+		},
+		"no command": {
+			wantErr: "native action has no command",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+			stdout := &bytes.Buffer{}
+			ctx = spec.WithLogs(ctx, spec.Logs{Stdout: stdout})
+
+			err := (&Config{Log: logr.Discard()}).Execute(ctx, tt.action)
+			switch {
+			case tt.wantCtxErr != nil:
+				if !errors.Is(err, tt.wantCtxErr) {
+					t.Fatalf("Execute() error = %v, want %v", err, tt.wantCtxErr)
+				}
+			case tt.wantErr != "":
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("Execute() error = %v, want %q", err, tt.wantErr)
+				}
+			case err != nil:
+				t.Fatalf("Execute() unexpected error: %v", err)
+			}
+			if got := stdout.String(); got != tt.wantStdout {
+				t.Errorf("unexpected stdout: got %q, want %q", got, tt.wantStdout)
+			}
+		})
+	}
+}
@@ -0,0 +1,256 @@
+package oci
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/tinkerbell/tinkerbell/tink/agent/internal/pkg/conv"
+	"github.com/tinkerbell/tinkerbell/tink/agent/internal/spec"
+)
+
+const (
+	defaultPath = "PATH=/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"
+	// cpuPeriod is the CFS period, in microseconds, CPU limits are enforced over. It is the kernel and Docker default.
+	cpuPeriod = 100000
+)
+
+// defaultCapabilities are the capabilities of unprivileged containers, the same as Docker's.
+var defaultCapabilities = []string{
+	"CAP_CHOWN", "CAP_DAC_OVERRIDE", "CAP_FSETID", "CAP_FOWNER", "CAP_MKNOD", "CAP_NET_RAW", "CAP_SETGID", "CAP_SETUID",
+	"CAP_SETFCAP", "CAP_SETPCAP", "CAP_NET_BIND_SERVICE", "CAP_SYS_CHROOT", "CAP_KILL", "CAP_AUDIT_WRITE",
+}
+
+// capabilities are all the Linux capabilities. Actions run privileged by default, the same as with the other runtimes.
+var capabilities = []string{
+	"CAP_CHOWN", "CAP_DAC_OVERRIDE", "CAP_DAC_READ_SEARCH", "CAP_FOWNER", "CAP_FSETID", "CAP_KILL", "CAP_SETGID",
+	"CAP_SETUID", "CAP_SETPCAP", "CAP_LINUX_IMMUTABLE", "CAP_NET_BIND_SERVICE", "CAP_NET_BROADCAST", "CAP_NET_ADMIN",
+	"CAP_NET_RAW", "CAP_IPC_LOCK", "CAP_IPC_OWNER", "CAP_SYS_MODULE", "CAP_SYS_RAWIO", "CAP_SYS_CHROOT", "CAP_SYS_PTRACE",
+	"CAP_SYS_PACCT", "CAP_SYS_ADMIN", "CAP_SYS_BOOT", "CAP_SYS_NICE", "CAP_SYS_RESOURCE", "CAP_SYS_TIME",
+	"CAP_SYS_TTY_CONFIG", "CAP_MKNOD", "CAP_LEASE", "CAP_AUDIT_WRITE", "CAP_AUDIT_CONTROL", "CAP_SETFCAP",
+	"CAP_MAC_OVERRIDE", "CAP_MAC_ADMIN", "CAP_SYSLOG", "CAP_WAKE_ALARM", "CAP_BLOCK_SUSPEND", "CAP_AUDIT_READ",
+	"CAP_PERFMON", "CAP_BPF", "CAP_CHECKPOINT_RESTORE",
+}
+
+// generateSpec returns the runtime spec of the container running action a from an image with the config ic.
+// The container gets the host's resolv.conf and hosts files. Unless the action's sandbox sets Privileged to false,
+// the container is privileged and has access to all host devices.
+// There is no CNI, so the container uses the host network namespace unless the action asks for another one.
+// In that case the container gets a new network namespace, with only a loopback interface.
+func generateSpec(ic v1.ImageConfig, a spec.Action, user specs.User, hostname string, mounts []specs.Mount) (*specs.Spec, error) {
+	cwd := ic.WorkingDir
+	if cwd == "" {
+		cwd = "/"
+	}
+	s := &specs.Spec{
+		Version:  specs.Version,
+		Root:     &specs.Root{Path: "rootfs", Readonly: a.Sandbox.ReadOnlyRootFilesystem},
+		Hostname: hostname,
+		Process: &specs.Process{
+			User: user,
+			Args: processArgs(ic, a),
+			Env:  mergeEnv(ic.Env, a.Env),
+			Cwd:  cwd,
+		},
+		Linux: &specs.Linux{
+			Namespaces: []specs.LinuxNamespace{
+				{Type: specs.MountNamespace},
+				{Type: specs.UTSNamespace},
+				{Type: specs.IPCNamespace},
+			},
+			Resources: &specs.LinuxResources{},
+		},
+	}
+	if a.Sandbox.IsPrivileged(true) {
+		s.Process.Capabilities = &specs.LinuxCapabilities{Bounding: capabilities, Effective: capabilities, Permitted: capabilities}
+		s.Mounts = []specs.Mount{
+			{Destination: "/proc", Type: "proc", Source: "proc", Options: []string{"nosuid", "noexec", "nodev"}},
+			{Destination: "/dev", Type: "bind", Source: "/dev", Options: []string{"rbind", "rw"}},
+			{Destination: "/sys", Type: "sysfs", Source: "sysfs", Options: []string{"nosuid", "noexec", "nodev", "rw"}},
+			{Destination: "/sys/fs/cgroup", Type: "cgroup", Source: "cgroup", Options: []string{"nosuid", "noexec", "nodev", "relatime", "rw"}},
+		}
+		s.Linux.Resources.Devices = []specs.LinuxDeviceCgroup{{Allow: true, Access: "rwm"}}
+	} else {
+		caps := sandboxCapabilities(conv.ParseCapabilities(a.Sandbox.CapAdd), conv.ParseCapabilities(a.Sandbox.CapDrop))
+		s.Process.Capabilities = &specs.LinuxCapabilities{Bounding: caps, Effective: caps, Permitted: caps}
+		// The OCI runtime creates the default devices, like /dev/null, in the /dev tmpfs.
+		s.Mounts = []specs.Mount{
+			{Destination: "/proc", Type: "proc", Source: "proc", Options: []string{"nosuid", "noexec", "nodev"}},
+			{Destination: "/dev", Type: "tmpfs", Source: "tmpfs", Options: []string{"nosuid", "strictatime", "mode=755", "size=65536k"}},
+			{Destination: "/dev/pts", Type: "devpts", Source: "devpts", Options: []string{"nosuid", "noexec", "newinstance", "ptmxmode=0666", "mode=0620"}},
+			{Destination: "/dev/shm", Type: "tmpfs", Source: "shm", Options: []string{"nosuid", "noexec", "nodev", "mode=1777", "size=65536k"}},
+			{Destination: "/sys", Type: "sysfs", Source: "sysfs", Options: []string{"nosuid", "noexec", "nodev", "ro"}},
+			{Destination: "/sys/fs/cgroup", Type: "cgroup", Source: "cgroup", Options: []string{"nosuid", "noexec", "nodev", "relatime", "ro"}},
+		}
+		s.Linux.Resources.Devices = []specs.LinuxDeviceCgroup{{Allow: false, Access: "rwm"}}
+		s.Linux.MaskedPaths = []string{"/proc/acpi", "/proc/kcore", "/proc/keys", "/proc/latency_stats", "/proc/timer_list", "/proc/timer_stats", "/proc/sched_debug", "/proc/scsi", "/sys/firmware"}
+		s.Linux.ReadonlyPaths = []string{"/proc/asound", "/proc/bus", "/proc/fs", "/proc/irq", "/proc/sys", "/proc/sysrq-trigger"}
+	}
+	nanoCPUs, memory, err := conv.ParseLimits(a.Sandbox)
+	if err != nil {
+		return nil, err
+	}
+	if nanoCPUs > 0 {
+		quota, period := nanoCPUs*cpuPeriod/1e9, uint64(cpuPeriod)
+		s.Linux.Resources.CPU = &specs.LinuxCPU{Quota: &quota, Period: &period}
+	}
+	if memory > 0 {
+		s.Linux.Resources.Memory = &specs.LinuxMemory{Limit: &memory}
+	}
+	if a.Sandbox.PIDs > 0 {
+		s.Linux.Resources.Pids = &specs.LinuxPids{Limit: &a.Sandbox.PIDs}
+	}
+	if a.Namespaces.PID != "host" {
+		s.Linux.Namespaces = append(s.Linux.Namespaces, specs.LinuxNamespace{Type: specs.PIDNamespace})
+	}
+	if a.Namespaces.Network != "" && a.Namespaces.Network != "host" {
+		s.Linux.Namespaces = append(s.Linux.Namespaces, specs.LinuxNamespace{Type: specs.NetworkNamespace})
+	}
+	for _, f := range []string{"/etc/resolv.conf", "/etc/hosts"} {
+		if _, err := os.Stat(f); err == nil {
+			s.Mounts = append(s.Mounts, specs.Mount{Destination: f, Type: "bind", Source: f, Options: []string{"rbind", "ro"}})
+		}
+	}
+	s.Mounts = append(s.Mounts, mounts...)
+
+	return s, nil
+}
+
+// sandboxCapabilities returns the default capabilities, or all of them when add has ALL, without drop and with add.
+// The same as Docker, dropping ALL drops every capability before the others in add are added.
+func sandboxCapabilities(add, drop []string) []string {
+	base := defaultCapabilities
+	if slices.Contains(add, "ALL") {
+		base = capabilities
+	}
+	caps := []string{}
+	if !slices.Contains(drop, "ALL") {
+		for _, c := range base {
+			if !slices.Contains(drop, c) {
+				caps = append(caps, c)
+			}
+		}
+	}
+	for _, c := range add {
+		if c != "ALL" && !slices.Contains(caps, c) {
+			caps = append(caps, c)
+		}
+	}
+
+	return caps
+}
+
+// processArgs replicates Docker's Entrypoint/Cmd semantics:
+// - a.Cmd maps to Docker's Entrypoint (the binary to run)
+// - a.Args maps to Docker's Cmd (arguments to the entrypoint)
+// Setting only the entrypoint keeps the image's Cmd.
+func processArgs(ic v1.ImageConfig, a spec.Action) []string {
+	entrypoint := ic.Entrypoint
+	if a.Cmd != "" {
+		entrypoint = []string{a.Cmd}
+	}
+	cmd := ic.Cmd
+	if len(a.Args) > 0 {
+		cmd = a.Args
+	}
+
+	return append(append([]string{}, entrypoint...), cmd...)
+}
+
+// mergeEnv returns the image environment overridden by the action environment. PATH is set when the image doesn't set it.
+func mergeEnv(image []string, action []spec.Env) []string {
+	env := []string{}
+	index := map[string]int{}
+	set := func(kv string) {
+		k, _, _ := strings.Cut(kv, "=")
+		if i, ok := index[k]; ok {
+			env[i] = kv
+			return
+		}
+		index[k] = len(env)
+		env = append(env, kv)
+	}
+	set(defaultPath)
+	for _, kv := range image {
+		set(kv)
+	}
+	for _, e := range action {
+		set(e.Key + "=" + e.Value)
+	}
+
+	return env
+}
+
+// lookupUser resolves the user of an image config, [user|uid][:group|gid], using the passwd and group files of rootfs.
+// An empty user is root.
+func lookupUser(rootfs, user string) (specs.User, error) {
+	if user == "" {
+		return specs.User{}, nil
+	}
+	name, group, hasGroup := strings.Cut(user, ":")
+	u := specs.User{}
+	uid, gid, err := lookupID(filepath.Join(rootfs, "etc", "passwd"), name)
+	if err != nil {
+		return u, fmt.Errorf("error looking up user %q: %w", name, err)
+	}
+	u.UID, u.GID = uid, gid
+	if hasGroup {
+		g, _, err := lookupID(filepath.Join(rootfs, "etc", "group"), group)
+		if err != nil {
+			return u, fmt.Errorf("error looking up group %q: %w", group, err)
+		}
+		u.GID = g
+	}
+
+	return u, nil
+}
+
+// lookupID returns the third and fourth fields of the line of an /etc/passwd or /etc/group formatted file
+// whose first or third field is name. A numeric name not in the file is returned as is, with a zero second id.
+func lookupID(file, name string) (uint32, uint32, error) {
+	numeric, err := strconv.ParseUint(name, 10, 32)
+	isNumeric := err == nil
+	f, err := os.Open(file)
+	if err != nil {
+		if isNumeric {
+			return uint32(numeric), 0, nil
+		}
+		if errors.Is(err, fs.ErrNotExist) {
+			return 0, 0, fmt.Errorf("%v not found", file)
+		}
+		return 0, 0, err
+	}
+	defer f.Close()
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		fields := strings.Split(s.Text(), ":")
+		if len(fields) < 3 || (fields[0] != name && fields[2] != name) {
+			continue
+		}
+		id, err := strconv.ParseUint(fields[2], 10, 32)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid id %q", fields[2])
+		}
+		var second uint64
+		if len(fields) > 3 {
+			second, _ = strconv.ParseUint(fields[3], 10, 32)
+		}
+		return uint32(id), uint32(second), nil
+	}
+	if err := s.Err(); err != nil {
+		return 0, 0, err
+	}
+	if isNumeric {
+		return uint32(numeric), 0, nil
+	}
+
+	return 0, 0, fmt.Errorf("%q not found in %v", name, file)
+}
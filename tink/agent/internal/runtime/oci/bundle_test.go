@@ -0,0 +1,194 @@
+package oci
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/tinkerbell/tinkerbell/tink/agent/internal/spec"
+)
+
+func TestProcessArgs(t *testing.T) {
+	ic := v1.ImageConfig{Entrypoint: []string{"/image2disk"}, Cmd: []string{"--help"}}
+	tests := map[string]struct {
+		action spec.Action
+		want   []string
+	}{
+		"image defaults":      {want: []string{"/image2disk", "--help"}},
+		"args override cmd":   {action: spec.Action{Args: []string{"-v"}}, want: []string{"/image2disk", "-v"}},
+		"entrypoint override": {action: spec.Action{Cmd: "/bin/sh"}, want: []string{"/bin/sh", "--help"}},
+		"both overridden":     {action: spec.Action{Cmd: "/bin/sh", Args: []string{"-c", "true"}}, want: []string{"/bin/sh", "-c", "true"}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.want, processArgs(ic, tt.action)); diff != "" {
+				t.Errorf("unexpected args (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMergeEnv(t *testing.T) {
+	got := mergeEnv([]string{"PATH=/bin", "IMG=1", "DEBUG=false"}, []spec.Env{{Key: "DEBUG", Value: "true"}, {Key: "DEST_DISK", Value: "/dev/sda"}})
+	want := []string{"PATH=/bin", "IMG=1", "DEBUG=true", "DEST_DISK=/dev/sda"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected env (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{defaultPath}, mergeEnv(nil, nil)); diff != "" {
+		t.Errorf("unexpected default env (-want +got):\n%s", diff)
+	}
+}
+
+func TestLookupUser(t *testing.T) {
+	rootfs := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(rootfs, "etc"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(rootfs, "etc", "passwd"), []byte("root:x:0:0::/root:/bin/sh\nnobody:x:65534:65533::/:/sbin/nologin\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(rootfs, "etc", "group"), []byte("root:x:0:\ndisk:x:6:\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]struct {
+		user    string
+		want    specs.User
+		wantErr bool
+	}{
+		"empty":             {want: specs.User{}},
+		"name":              {user: "nobody", want: specs.User{UID: 65534, GID: 65533}},
+		"uid in passwd":     {user: "65534", want: specs.User{UID: 65534, GID: 65533}},
+		"uid not in passwd": {user: "1000", want: specs.User{UID: 1000}},
+		"name and group":    {user: "nobody:disk", want: specs.User{UID: 65534, GID: 6}},
+		"uid and gid":       {user: "1000:1000", want: specs.User{UID: 1000, GID: 1000}},
+		"unknown name":      {user: "tink", wantErr: true},
+		"unknown group":     {user: "nobody:tink", wantErr: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := lookupUser(rootfs, tt.user)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("lookupUser() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); !tt.wantErr && diff != "" {
+				t.Errorf("unexpected user (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGenerateSpecNamespaces(t *testing.T) {
+	tests := map[string]struct {
+		namespaces spec.Namespaces
+		want       []specs.LinuxNamespaceType
+	}{
+		"defaults": {
+			want: []specs.LinuxNamespaceType{specs.MountNamespace, specs.UTSNamespace, specs.IPCNamespace, specs.PIDNamespace},
+		},
+		"host pid": {
+			namespaces: spec.Namespaces{PID: "host"},
+			want:       []specs.LinuxNamespaceType{specs.MountNamespace, specs.UTSNamespace, specs.IPCNamespace},
+		},
+		"isolated network": {
+			namespaces: spec.Namespaces{Network: "none"},
+			want:       []specs.LinuxNamespaceType{specs.MountNamespace, specs.UTSNamespace, specs.IPCNamespace, specs.PIDNamespace, specs.NetworkNamespace},
+		},
+		"host network": {
+			namespaces: spec.Namespaces{Network: "host"},
+			want:       []specs.LinuxNamespaceType{specs.MountNamespace, specs.UTSNamespace, specs.IPCNamespace, specs.PIDNamespace},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, err := generateSpec(v1.ImageConfig{}, spec.Action{Namespaces: tt.namespaces}, specs.User{}, "host", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []specs.LinuxNamespaceType
+			for _, ns := range s.Linux.Namespaces {
+				got = append(got, ns.Type)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("unexpected namespaces (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGenerateSpecMounts(t *testing.T) {
+	volume := specs.Mount{Destination: "/data", Type: "bind", Source: "/tmp/data", Options: []string{"rbind", "ro"}}
+	s, err := generateSpec(v1.ImageConfig{WorkingDir: "/work"}, spec.Action{}, specs.User{}, "host", []specs.Mount{volume})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(volume, s.Mounts[len(s.Mounts)-1]); diff != "" {
+		t.Errorf("expected the volume to be the last mount (-want +got):\n%s", diff)
+	}
+	if s.Process.Cwd != "/work" {
+		t.Errorf("Cwd = %q, want /work", s.Process.Cwd)
+	}
+	if s.Root.Path != "rootfs" {
+		t.Errorf("Root.Path = %q, want rootfs", s.Root.Path)
+	}
+}
+
+func TestGenerateSpecSandbox(t *testing.T) {
+	unprivileged := false
+	a := spec.Action{Sandbox: spec.Sandbox{
+		CPU:                    "250m",
+		Memory:                 "64Mi",
+		PIDs:                   32,
+		CapDrop:                []string{"ALL"},
+		CapAdd:                 []string{"net_admin"},
+		ReadOnlyRootFilesystem: true,
+		Privileged:             &unprivileged,
+	}}
+	s, err := generateSpec(v1.ImageConfig{}, a, specs.User{}, "host", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	quota, period, memory, pids := int64(25000), uint64(cpuPeriod), int64(64<<20), int64(32)
+	want := &specs.LinuxResources{
+		Devices: []specs.LinuxDeviceCgroup{{Allow: false, Access: "rwm"}},
+		CPU:     &specs.LinuxCPU{Quota: &quota, Period: &period},
+		Memory:  &specs.LinuxMemory{Limit: &memory},
+		Pids:    &specs.LinuxPids{Limit: &pids},
+	}
+	if diff := cmp.Diff(want, s.Linux.Resources); diff != "" {
+		t.Errorf("unexpected resources (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"CAP_NET_ADMIN"}, s.Process.Capabilities.Bounding); diff != "" {
+		t.Errorf("unexpected capabilities (-want +got):\n%s", diff)
+	}
+	if !s.Root.Readonly {
+		t.Error("expected a read-only root filesystem")
+	}
+	if _, err := generateSpec(v1.ImageConfig{}, spec.Action{Sandbox: spec.Sandbox{CPU: "half"}}, specs.User{}, "host", nil); err == nil {
+		t.Error("expected an error for an invalid cpu limit")
+	}
+}
+
+func TestSandboxCapabilities(t *testing.T) {
+	tests := map[string]struct {
+		add, drop []string
+		want      []string
+	}{
+		"defaults": {want: defaultCapabilities},
+		"drop one": {
+			drop: []string{"CAP_NET_RAW", "CAP_MKNOD"},
+			want: []string{"CAP_CHOWN", "CAP_DAC_OVERRIDE", "CAP_FSETID", "CAP_FOWNER", "CAP_SETGID", "CAP_SETUID", "CAP_SETFCAP", "CAP_SETPCAP", "CAP_NET_BIND_SERVICE", "CAP_SYS_CHROOT", "CAP_KILL", "CAP_AUDIT_WRITE"},
+		},
+		"drop all add one": {add: []string{"CAP_SYS_ADMIN"}, drop: []string{"ALL"}, want: []string{"CAP_SYS_ADMIN"}},
+		"add all":          {add: []string{"ALL"}, want: capabilities},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.want, sandboxCapabilities(tt.add, tt.drop)); diff != "" {
+				t.Errorf("unexpected capabilities (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
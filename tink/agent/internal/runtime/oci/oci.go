@@ -0,0 +1,320 @@
+// Package oci runs Actions directly with an OCI runtime, like runc or crun, without a container daemon.
+// Images are pulled, or loaded from tarballs, into an OCI image layout directory and each Action runs
+// from a bundle unpacked from its image.
+package oci
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/avast/retry-go/v4"
+	"github.com/containerd/containerd/v2/core/content"
+	"github.com/containerd/containerd/v2/core/images"
+	"github.com/containerd/containerd/v2/core/images/archive"
+	"github.com/containerd/containerd/v2/core/remotes"
+	"github.com/containerd/containerd/v2/core/remotes/docker"
+	dockerconfig "github.com/containerd/containerd/v2/core/remotes/docker/config"
+	fsarchive "github.com/containerd/containerd/v2/pkg/archive"
+	"github.com/containerd/containerd/v2/pkg/archive/compression"
+	"github.com/containerd/platforms"
+	"github.com/containers/image/v5/pkg/shortnames"
+	"github.com/containers/image/v5/types"
+	"github.com/go-logr/logr"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/tinkerbell/tinkerbell/tink/agent/internal/pkg/conv"
+	"github.com/tinkerbell/tinkerbell/tink/agent/internal/pkg/rand"
+	"github.com/tinkerbell/tinkerbell/tink/agent/internal/spec"
+)
+
+const (
+	defaultBinary             = "runc"
+	defaultRoot               = "/var/lib/tink-agent/oci"
+	defaultStateDir           = "/run/tink-agent/oci"
+	defaultRegistryConfigPath = "/etc/containerd/certs.d"
+)
+
+type Config struct {
+	Log logr.Logger
+	// Binary is the OCI runtime, runc or crun, used to run Actions. It is looked up in PATH when not absolute.
+	Binary string
+	// Root is the directory holding the image layout, in Root/images, and the Action bundles, in Root/bundles.
+	Root string
+	// StateDir is the directory the OCI runtime keeps container state in, its --root flag.
+	StateDir string
+	// RegistryConfigPath is the root directory containing per-registry hosts.toml and certificate configuration.
+	RegistryConfigPath string
+
+	store *store
+}
+
+type Opt func(*Config)
+
+func WithBinary(binary string) Opt {
+	return func(c *Config) {
+		c.Binary = binary
+	}
+}
+
+func WithRoot(root string) Opt {
+	return func(c *Config) {
+		c.Root = root
+	}
+}
+
+func WithStateDir(stateDir string) Opt {
+	return func(c *Config) {
+		c.StateDir = stateDir
+	}
+}
+
+func WithRegistryConfigPath(registryConfigPath string) Opt {
+	return func(c *Config) {
+		c.RegistryConfigPath = registryConfigPath
+	}
+}
+
+func NewConfig(log logr.Logger, opts ...Opt) (*Config, error) {
+	c := &Config{
+		Log:                log,
+		Binary:             defaultBinary,
+		Root:               defaultRoot,
+		StateDir:           defaultStateDir,
+		RegistryConfigPath: defaultRegistryConfigPath,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if _, err := exec.LookPath(c.Binary); err != nil {
+		return nil, fmt.Errorf("OCI runtime %q not found: %w", c.Binary, err)
+	}
+	s, err := newStore(filepath.Join(c.Root, "images"))
+	if err != nil {
+		return nil, err
+	}
+	c.store = s
+
+	return c, nil
+}
+
+// PullImage pulls image into the image layout, unless it is already there.
+func (c *Config) PullImage(ctx context.Context, image string) error {
+	_, err := c.image(ctx, normalize(image))
+	return err
+}
+
+// LoadImages imports the images of an OCI image layout, or docker save, tarball into the image layout.
+// The images are named by their io.containerd.image.name or org.opencontainers.image.ref.name annotation.
+func (c *Config) LoadImages(ctx context.Context, r io.Reader) error {
+	desc, err := archive.ImportIndex(ctx, c.store.content, r)
+	if err != nil {
+		return fmt.Errorf("error importing images: %w", err)
+	}
+	b, err := content.ReadBlob(ctx, c.store.content, desc)
+	if err != nil {
+		return fmt.Errorf("error reading imported index: %w", err)
+	}
+	var idx v1.Index
+	if err := json.Unmarshal(b, &idx); err != nil {
+		return fmt.Errorf("error decoding imported index: %w", err)
+	}
+	for _, m := range idx.Manifests {
+		name := m.Annotations[images.AnnotationImageName]
+		if name == "" {
+			name = m.Annotations[v1.AnnotationRefName]
+		}
+		if name == "" {
+			continue
+		}
+		if err := c.store.put(normalize(name), m); err != nil {
+			return err
+		}
+		c.Log.V(1).Info("image loaded", "image", name)
+	}
+
+	return nil
+}
+
+func (c *Config) Execute(ctx context.Context, a spec.Action) error {
+	desc, err := c.image(ctx, normalize(a.Image))
+	if err != nil {
+		return err
+	}
+
+	// Retries reuse the Action ID, the suffix keeps container IDs unique.
+	id := conv.ParseName(a.ID, a.Name) + "_" + rand.String(8)
+	bundle := filepath.Join(c.Root, "bundles", id)
+	defer func() {
+		if err := os.RemoveAll(bundle); err != nil {
+			c.Log.Info("failed to remove bundle", "bundle", bundle, "error", err)
+		}
+	}()
+	rootfs := filepath.Join(bundle, "rootfs")
+	ic, err := c.unpack(ctx, desc, rootfs)
+	if err != nil {
+		return fmt.Errorf("error unpacking image: %w", err)
+	}
+	user, err := lookupUser(rootfs, ic.User)
+	if err != nil {
+		return err
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		c.Log.Info("failed to get host hostname, using container ID", "error", err)
+		hostname = id
+	}
+	s, err := generateSpec(ic, a, user, hostname, conv.ParseVolumes(c.Log, a.Volumes))
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(bundle, "config.json"), b, 0o600); err != nil {
+		return fmt.Errorf("error writing runtime spec: %w", err)
+	}
+
+	return c.run(ctx, id, bundle)
+}
+
+// run runs the container id from bundle until it exits or ctx is done, in which case the container is killed.
+func (c *Config) run(ctx context.Context, id, bundle string) error {
+	logs := spec.LogsFrom(ctx)
+	cmd := exec.Command(c.Binary, "--root", c.StateDir, "run", "--bundle", bundle, id) //nolint:gosec // the binary is configured by the operator.
+	cmd.Stdout = io.MultiWriter(os.Stdout, logs.Stdout)
+	cmd.Stderr = io.MultiWriter(os.Stderr, logs.Stderr)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("error starting container: %w", err)
+	}
+	defer func() {
+		if out, err := exec.Command(c.Binary, "--root", c.StateDir, "delete", "--force", id).CombinedOutput(); err != nil { //nolint:gosec // the binary is configured by the operator.
+			c.Log.V(1).Info("failed to delete container", "container", id, "error", err, "output", string(out))
+		}
+	}()
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+
+	select {
+	case err := <-done:
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return fmt.Errorf("container exited with non-zero code: %d", exitErr.ExitCode())
+		}
+		if err != nil {
+			return fmt.Errorf("error running container: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+		// Killing the OCI runtime would leave the container running, the container is killed instead.
+		if out, err := exec.Command(c.Binary, "--root", c.StateDir, "kill", id, "KILL").CombinedOutput(); err != nil { //nolint:gosec // the binary is configured by the operator.
+			c.Log.Error(err, "failed to kill container after context cancellation", "output", string(out))
+		}
+		<-done
+		return fmt.Errorf("context cancelled while waiting for container: %w", ctx.Err())
+	}
+}
+
+// image returns the descriptor of the image named name, pulling it if it isn't in the image layout.
+func (c *Config) image(ctx context.Context, name string) (v1.Descriptor, error) {
+	desc, ok, err := c.store.get(name)
+	if err != nil || ok {
+		return desc, err
+	}
+	resolver := docker.NewResolver(docker.ResolverOptions{Hosts: newRegistryHosts(ctx, c.RegistryConfigPath)})
+	pullImage := func() error {
+		resolved, d, err := resolver.Resolve(ctx, name)
+		if err != nil {
+			return fmt.Errorf("error resolving image: %w", err)
+		}
+		fetcher, err := resolver.Fetcher(ctx, resolved)
+		if err != nil {
+			return fmt.Errorf("error pulling image: %w", err)
+		}
+		// Only the manifest of the host platform, and its blobs, are fetched. Blobs already in the store are skipped.
+		children := images.LimitManifests(images.FilterPlatforms(images.ChildrenHandler(c.store.content), platforms.Default()), platforms.Default(), 1)
+		if err := images.Dispatch(ctx, images.Handlers(remotes.FetchHandler(c.store.content, fetcher), children), nil, d); err != nil {
+			return fmt.Errorf("error pulling image: %w", err)
+		}
+		desc = d
+
+		return nil
+	}
+	if err := retry.Do(pullImage, retry.Context(ctx), retry.Attempts(5), retry.Delay(2*time.Second), retry.MaxDelay(10*time.Second), retry.DelayType(retry.BackOffDelay)); err != nil {
+		return v1.Descriptor{}, err
+	}
+	if err := c.store.put(name, desc); err != nil {
+		return v1.Descriptor{}, err
+	}
+	c.Log.V(1).Info("image pulled", "image", name)
+
+	return desc, nil
+}
+
+// unpack applies the layers of the image desc, for the host platform, to rootfs and returns the image config.
+func (c *Config) unpack(ctx context.Context, desc v1.Descriptor, rootfs string) (v1.ImageConfig, error) {
+	manifest, err := images.Manifest(ctx, c.store.content, desc, platforms.Default())
+	if err != nil {
+		return v1.ImageConfig{}, err
+	}
+	b, err := content.ReadBlob(ctx, c.store.content, manifest.Config)
+	if err != nil {
+		return v1.ImageConfig{}, fmt.Errorf("failed to read image config: %w", err)
+	}
+	var img v1.Image
+	if err := json.Unmarshal(b, &img); err != nil {
+		return v1.ImageConfig{}, fmt.Errorf("failed to unmarshal image config: %w", err)
+	}
+	if err := os.MkdirAll(rootfs, 0o755); err != nil {
+		return v1.ImageConfig{}, err
+	}
+	for _, layer := range manifest.Layers {
+		if err := c.applyLayer(ctx, layer, rootfs); err != nil {
+			return v1.ImageConfig{}, fmt.Errorf("error applying layer %v: %w", layer.Digest, err)
+		}
+	}
+
+	return img.Config, nil
+}
+
+func (c *Config) applyLayer(ctx context.Context, layer v1.Descriptor, rootfs string) error {
+	ra, err := c.store.content.ReaderAt(ctx, layer)
+	if err != nil {
+		return err
+	}
+	defer ra.Close()
+	r, err := compression.DecompressStream(content.NewReader(ra))
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	_, err = fsarchive.Apply(ctx, rootfs, r)
+
+	return err
+}
+
+func newRegistryHosts(ctx context.Context, configPath string) docker.RegistryHosts {
+	opts := dockerconfig.HostOptions{}
+	if configPath != "" {
+		opts.HostDir = dockerconfig.HostDirFromRoot(configPath)
+	}
+
+	return dockerconfig.ConfigureHosts(ctx, opts)
+}
+
+// normalize returns the fully qualified name of image, docker.io/library/alpine:latest for alpine.
+func normalize(image string) string {
+	r, err := shortnames.Resolve(&types.SystemContext{PodmanOnlyShortNamesIgnoreRegistriesConfAndForceDockerHub: true}, image)
+	if err != nil || r == nil || len(r.PullCandidates) == 0 {
+		return image
+	}
+
+	return r.PullCandidates[0].Value.String()
+}
@@ -0,0 +1,106 @@
+package oci
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/containerd/containerd/v2/core/content"
+	"github.com/containerd/containerd/v2/plugins/content/local"
+	"github.com/opencontainers/image-spec/specs-go"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// store is an OCI image layout directory. Blobs are content-addressed, a blob shared by images is stored once.
+// The images are the manifests of index.json, each annotated with its reference.
+type store struct {
+	root    string
+	content content.Store
+
+	mu sync.Mutex
+}
+
+func newStore(root string) (*store, error) {
+	cs, err := local.NewStore(root)
+	if err != nil {
+		return nil, fmt.Errorf("error creating content store: %w", err)
+	}
+	layout, err := json.Marshal(v1.ImageLayout{Version: v1.ImageLayoutVersion})
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(root, v1.ImageLayoutFile), layout, 0o644); err != nil {
+		return nil, fmt.Errorf("error writing image layout: %w", err)
+	}
+
+	return &store{root: root, content: cs}, nil
+}
+
+// get returns the descriptor of the image named name.
+func (s *store) get(name string) (v1.Descriptor, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	idx, err := s.index()
+	if err != nil {
+		return v1.Descriptor{}, false, err
+	}
+	for _, m := range idx.Manifests {
+		if m.Annotations[v1.AnnotationRefName] == name {
+			return m, true, nil
+		}
+	}
+
+	return v1.Descriptor{}, false, nil
+}
+
+// put names the image desc name, replacing the image previously named name.
+func (s *store) put(name string, desc v1.Descriptor) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	idx, err := s.index()
+	if err != nil {
+		return err
+	}
+	desc.Annotations = map[string]string{v1.AnnotationRefName: name}
+	manifests := []v1.Descriptor{desc}
+	for _, m := range idx.Manifests {
+		if m.Annotations[v1.AnnotationRefName] != name {
+			manifests = append(manifests, m)
+		}
+	}
+	idx.Manifests = manifests
+	b, err := json.Marshal(idx)
+	if err != nil {
+		return err
+	}
+	// Written to a temporary file and renamed so that a crash never leaves a partial index.
+	tmp := filepath.Join(s.root, v1.ImageIndexFile+".tmp")
+	if err := os.WriteFile(tmp, b, 0o644); err != nil {
+		return fmt.Errorf("error writing image index: %w", err)
+	}
+	if err := os.Rename(tmp, filepath.Join(s.root, v1.ImageIndexFile)); err != nil {
+		return fmt.Errorf("error writing image index: %w", err)
+	}
+
+	return nil
+}
+
+func (s *store) index() (v1.Index, error) {
+	idx := v1.Index{Versioned: specs.Versioned{SchemaVersion: 2}, MediaType: v1.MediaTypeImageIndex}
+	b, err := os.ReadFile(filepath.Join(s.root, v1.ImageIndexFile))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return idx, nil
+		}
+		return idx, fmt.Errorf("error reading image index: %w", err)
+	}
+	if err := json.Unmarshal(b, &idx); err != nil {
+		return idx, fmt.Errorf("error decoding image index: %w", err)
+	}
+
+	return idx, nil
+}
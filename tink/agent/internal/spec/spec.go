@@ -1,7 +1,9 @@
 package spec
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"time"
 )
 
@@ -18,6 +20,10 @@ type Action struct {
 	// Image is an OCI image.
 	Image string `json:"image" yaml:"image"`
 
+	// Type is how the action runs, in a container from Image, the default, or directly on the host.
+	// +optional
+	Type ActionType `json:"type,omitempty,omitzero" yaml:"type,omitempty,omitzero"`
+
 	// Cmd defines the command to use when launching the image. It overrides the default command
 	// of the action. It must be a unix path to an executable program.
 	// +kubebuilder:validation:Pattern=`^(/[^/ ]*)+/?$`
@@ -37,19 +43,46 @@ type Action struct {
 	// +optional
 	Volumes []Volume `json:"volumes,omitempty,omitzero" yaml:"volumes,omitempty,omitzero"`
 
+	// Sandbox restricts what the action container can do and use.
+	// +optional
+	Sandbox Sandbox `json:"sandbox,omitempty,omitzero" yaml:"sandbox,omitempty,omitzero"`
+
 	// Namespaces defines the Linux namespaces this container should execute in.
 	// +optional
 	Namespaces     Namespaces `json:"namespaces,omitempty,omitzero" yaml:"namespaces,omitempty,omitzero"`
 	Retries        int        `json:"retries,omitempty,omitzero" yaml:"retries,omitempty,omitzero"`
 	TimeoutSeconds int        `json:"timeoutSeconds,omitempty,omitzero" yaml:"timeoutSeconds,omitempty,omitzero"`
+	// RetryDelaySeconds is the number of seconds to wait between runs of the action.
+	RetryDelaySeconds int `json:"retryDelaySeconds,omitempty,omitzero" yaml:"retryDelaySeconds,omitempty,omitzero"`
+	// Attempt is the number of the current run of the action. It starts at 1.
+	Attempt int `json:"attempt,omitempty,omitzero" yaml:"attempt,omitempty,omitzero"`
 	// ExecutionStart is the time the action started executing.
 	ExecutionStart time.Time `json:"executionStart,omitzero" yaml:"executionStart,omitzero"`
 	// ExecutionStop is the time the action stopped executing.
 	ExecutionStop time.Time `json:"executionStop,omitzero" yaml:"executionStop,omitzero"`
 	// ExecutionDuration is the time the action took to complete.
 	ExecutionDuration string `json:"executionDuration,omitempty,omitzero" yaml:"duration,omitempty,omitzero"`
+	// OnTimeout is the command to run, using the action image, when the action times out.
+	// +optional
+	OnTimeout []string `json:"onTimeout,omitempty,omitzero" yaml:"onTimeout,omitempty,omitzero"`
+	// OnFailure is the command to run, using the action image, when the action fails.
+	// +optional
+	OnFailure []string `json:"onFailure,omitempty,omitzero" yaml:"onFailure,omitempty,omitzero"`
+	// RemainingImages are the images of the actions that run after this one. They are pulled before this action starts.
+	// +optional
+	RemainingImages []string `json:"remainingImages,omitempty,omitzero" yaml:"remainingImages,omitempty,omitzero"`
 }
 
+// ActionType is how an action runs.
+type ActionType string
+
+const (
+	// ActionTypeContainer actions run in a container from their image. It is the default.
+	ActionTypeContainer ActionType = "container"
+	// ActionTypeNative actions run a host binary or script, Args[0] when Cmd is empty, without a container.
+	ActionTypeNative ActionType = "native"
+)
+
 type Env struct {
 	Key   string `json:"key" yaml:"key"`
 	Value string `json:"value" yaml:"value"`
@@ -83,12 +116,56 @@ type Namespaces struct {
 	PID string `json:"pid,omitempty,omitzero" yaml:"pid,omitempty,omitzero"`
 }
 
+// Sandbox restricts what an action container can do and use.
+type Sandbox struct {
+	// CPU is the maximum number of CPUs, as a quantity like 500m or 2.
+	CPU string `json:"cpu,omitempty,omitzero" yaml:"cpu,omitempty,omitzero"`
+	// Memory is the maximum memory, as a quantity like 512Mi.
+	Memory string `json:"memory,omitempty,omitzero" yaml:"memory,omitempty,omitzero"`
+	// PIDs is the maximum number of processes.
+	PIDs int64 `json:"pids,omitempty,omitzero" yaml:"pids,omitempty,omitzero"`
+	// CapAdd are Linux capabilities added to the runtime's default set, with or without the CAP_ prefix.
+	CapAdd []string `json:"capAdd,omitempty,omitzero" yaml:"capAdd,omitempty,omitzero"`
+	// CapDrop are Linux capabilities dropped from the runtime's default set, with or without the CAP_ prefix.
+	CapDrop []string `json:"capDrop,omitempty,omitzero" yaml:"capDrop,omitempty,omitzero"`
+	// ReadOnlyRootFilesystem mounts the root filesystem of the container read-only.
+	ReadOnlyRootFilesystem bool `json:"readOnlyRootFilesystem,omitempty,omitzero" yaml:"readOnlyRootFilesystem,omitempty,omitzero"`
+	// Privileged runs the container privileged. When nil the runtime's default is used.
+	Privileged *bool `json:"privileged,omitempty,omitzero" yaml:"privileged,omitempty,omitzero"`
+}
+
+// IsPrivileged reports whether the container runs privileged. def is the runtime's default.
+func (s Sandbox) IsPrivileged(def bool) bool {
+	if s.Privileged == nil {
+		return def
+	}
+	return *s.Privileged
+}
+
 type Event struct {
-	Action  Action
-	Message string
-	State   State
+	Action  Action `json:"action"`
+	Message string `json:"message,omitempty"`
+	State   State  `json:"state"`
+	// Hook is the result of the on-failure or on-timeout hook, if one was run.
+	Hook *Hook `json:"hook,omitempty"`
+	// Outputs are the key/value pairs the action wrote to its output file.
+	Outputs map[string]string `json:"outputs,omitempty"`
+}
+
+// Hook is the result of running an on-failure or on-timeout hook.
+type Hook struct {
+	Name    HookName `json:"name"`
+	State   State    `json:"state"`
+	Message string   `json:"message,omitempty"`
 }
 
+type HookName string
+
+const (
+	HookOnTimeout HookName = "on-timeout"
+	HookOnFailure HookName = "on-failure"
+)
+
 type State string
 
 const (
@@ -96,9 +173,37 @@ const (
 	StateFailure State = "failure"
 	StateRunning State = "running"
 	StateTimeout State = "timeout"
-	StateUnknown State = "unknown"
+	// StateCanceled is the state of an action that was stopped because its Workflow was canceled.
+	StateCanceled State = "canceled"
+	StateUnknown  State = "unknown"
 )
 
 func (e Event) String() string {
 	return fmt.Sprintf("action: %v, message: %v, state: %v", e.Action, e.Message, e.State)
 }
+
+// Logs are the writers the output of an action container is copied to, in addition to any logging done by the runtime.
+type Logs struct {
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+type logsKey struct{}
+
+// WithLogs returns a copy of ctx that carries l. Runtimes copy the output of an action container to the Logs
+// in the context passed to Execute.
+func WithLogs(ctx context.Context, l Logs) context.Context {
+	return context.WithValue(ctx, logsKey{}, l)
+}
+
+// LogsFrom returns the Logs carried by ctx. Writers that are not set are io.Discard.
+func LogsFrom(ctx context.Context) Logs {
+	l, _ := ctx.Value(logsKey{}).(Logs)
+	if l.Stdout == nil {
+		l.Stdout = io.Discard
+	}
+	if l.Stderr == nil {
+		l.Stderr = io.Discard
+	}
+	return l
+}
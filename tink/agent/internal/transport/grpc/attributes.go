@@ -46,16 +46,28 @@ func ToProto(a *data.AgentAttributes) *proto.AgentAttributes {
 			Model:             block.Model,
 			Wwn:               block.WWN,
 			SerialNumber:      block.SerialNumber,
+			SmartStatus:       block.SmartStatus,
 		})
 	}
 
 	for _, nic := range a.NetworkInterfaces {
-		result.Network = append(result.Network, &proto.Network{
+		n := &proto.Network{
 			Name:                nic.Name,
 			Mac:                 nic.Mac,
 			Speed:               nic.Speed,
 			EnabledCapabilities: nic.EnabledCapabilities,
-		})
+			LinkState:           nic.LinkState,
+		}
+		for _, nb := range nic.LLDPNeighbors {
+			n.LldpNeighbors = append(n.LldpNeighbors, &proto.LLDPNeighbor{
+				ChassisId:         nb.ChassisID,
+				PortId:            nb.PortID,
+				PortDescription:   nb.PortDescription,
+				SystemName:        nb.SystemName,
+				SystemDescription: nb.SystemDescription,
+			})
+		}
+		result.Network = append(result.Network, n)
 	}
 
 	for _, p := range a.PCIDevices {
@@ -108,5 +120,11 @@ func ToProto(a *data.AgentAttributes) *proto.AgentAttributes {
 		}
 	}
 
+	if a.TPM != nil {
+		result.Tpm = &proto.TPM{
+			Version: a.TPM.Version,
+		}
+	}
+
 	return result
 }
@@ -47,6 +47,7 @@ func TestToProto(t *testing.T) {
 						PhysicalBlockSize: toPtr("4KB"),
 						Vendor:            toPtr("Samsung"),
 						Model:             toPtr("EVO860"),
+						SmartStatus:       toPtr("passed"),
 					},
 				},
 				NetworkInterfaces: []*data.Network{
@@ -55,8 +56,13 @@ func TestToProto(t *testing.T) {
 						Mac:                 toPtr("00:1A:2B:3C:4D:5E"),
 						Speed:               toPtr("1Gbps"),
 						EnabledCapabilities: []string{"rx-checksum", "tx-checksum"},
+						LinkState:           toPtr("up"),
+						LLDPNeighbors: []*data.LLDPNeighbor{
+							{ChassisID: toPtr("3c:ec:ef:00:00:01"), PortID: toPtr("Ethernet1/12"), SystemName: toPtr("tor1")},
+						},
 					},
 				},
+				TPM: &data.TPM{Version: toPtr("2.0")},
 			},
 			expected: &proto.AgentAttributes{
 				Cpu: &proto.CPU{
@@ -86,6 +92,7 @@ func TestToProto(t *testing.T) {
 						PhysicalBlockSize: toPtr("4KB"),
 						Vendor:            toPtr("Samsung"),
 						Model:             toPtr("EVO860"),
+						SmartStatus:       toPtr("passed"),
 					},
 				},
 				Network: []*proto.Network{
@@ -94,8 +101,13 @@ func TestToProto(t *testing.T) {
 						Mac:                 toPtr("00:1A:2B:3C:4D:5E"),
 						Speed:               toPtr("1Gbps"),
 						EnabledCapabilities: []string{"rx-checksum", "tx-checksum"},
+						LinkState:           toPtr("up"),
+						LldpNeighbors: []*proto.LLDPNeighbor{
+							{ChassisId: toPtr("3c:ec:ef:00:00:01"), PortId: toPtr("Ethernet1/12"), SystemName: toPtr("tor1")},
+						},
 					},
 				},
+				Tpm: &proto.TPM{Version: toPtr("2.0")},
 			},
 		},
 		"Partially populated input (only CPU)": {
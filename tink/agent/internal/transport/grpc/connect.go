@@ -0,0 +1,178 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v5"
+	"github.com/tinkerbell/tinkerbell/pkg/proto"
+	"github.com/tinkerbell/tinkerbell/tink/agent/internal/spec"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxReconnectInterval is the maximum interval between attempts to open a Connect stream.
+const maxReconnectInterval = 30 * time.Second
+
+// errStreamClosed is returned when an action status could not be reported because the Connect stream closed.
+var errStreamClosed = errors.New("connect stream closed")
+
+// connectStream is an open Connect stream. Heartbeats and action statuses are sent over it from different goroutines.
+type connectStream struct {
+	stream grpc.BidiStreamingClient[proto.ConnectRequest, proto.ConnectResponse]
+	sendMu sync.Mutex
+	// reportMu allows a single action status in flight, the next result received is its result.
+	reportMu sync.Mutex
+	results  chan *proto.ActionStatusResult
+	// done is closed once the stream is closed.
+	done chan struct{}
+}
+
+func (s *connectStream) send(r *proto.ConnectRequest) error {
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+	return s.stream.Send(r)
+}
+
+// report sends an action status over the stream and returns its result, the error ReportActionStatus would return.
+// It returns errStreamClosed when the stream closes before the result is received.
+func (s *connectStream) report(ctx context.Context, ar *proto.ActionStatusRequest) error {
+	s.reportMu.Lock()
+	defer s.reportMu.Unlock()
+	// A result received after an earlier report gave up is not the result of ar.
+	select {
+	case <-s.results:
+	default:
+	}
+	if err := s.send(&proto.ConnectRequest{ActionStatus: ar}); err != nil {
+		return errors.Join(errStreamClosed, err)
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-s.done:
+		return errStreamClosed
+	case res := <-s.results:
+		return status.Error(codes.Code(res.GetCode()), res.GetMessage())
+	}
+}
+
+// Connect keeps a Connect stream to the server open and hands the actions pushed over it to Read, through Actions.
+// It reconnects, with backoff, until ctx is done. Read polls GetAction whenever the stream is down.
+// When the server does not support Connect, it returns and Read only polls.
+func (c *Config) Connect(ctx context.Context) error {
+	bo := backoff.NewExponentialBackOff()
+	bo.MaxInterval = maxReconnectInterval
+	for {
+		err := c.connect(ctx, bo)
+		c.connected.Store(false)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if status.Code(err) == codes.Unimplemented {
+			c.Log.Info("server does not support connect, polling for actions", "error", err)
+			return nil
+		}
+		next := bo.NextBackOff()
+		c.Log.Info("connect stream closed, polling for actions until reconnected", "error", err, "retryIn", next.String())
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(next):
+		}
+	}
+}
+
+// connect opens a Connect stream and receives actions from it until the stream breaks.
+// While the stream is open, heartbeats and action statuses are sent over it.
+func (c *Config) connect(ctx context.Context, bo *backoff.ExponentialBackOff) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := c.TinkServerClient.Connect(ctx)
+	if err != nil {
+		return err
+	}
+	if err := stream.Send(&proto.ConnectRequest{AgentId: toPtr(c.AgentID), AgentAttributes: ToProto(c.Attributes)}); err != nil {
+		// The reason the stream broke is returned by Recv.
+		if _, rerr := stream.Recv(); rerr != nil {
+			return rerr
+		}
+		return err
+	}
+	cs := &connectStream{stream: stream, results: make(chan *proto.ActionStatusResult, 1), done: make(chan struct{})}
+	c.stream.Store(cs)
+	defer func() {
+		c.stream.CompareAndSwap(cs, nil)
+		close(cs.done)
+	}()
+	go c.heartbeat(ctx, cs)
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		if !c.connected.Swap(true) {
+			c.Log.Info("connected, the server pushes actions")
+			bo.Reset()
+		}
+		if res := resp.GetActionStatus(); res != nil {
+			select {
+			case cs.results <- res:
+			default:
+				c.Log.V(1).Info("dropped action status result, no report is waiting for it", "action", res.GetActionId())
+			}
+		}
+		if resp.GetAction() == nil {
+			continue
+		}
+		c.push(actionFromProto(resp.GetAction()))
+	}
+}
+
+// heartbeat sends a heartbeat with the running action over stream right away and then every HeartbeatInterval, until ctx is done.
+func (c *Config) heartbeat(ctx context.Context, stream *connectStream) {
+	interval := c.HeartbeatInterval
+	if interval <= 0 {
+		interval = defaultHeartbeatInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		a, _ := c.running.Load().(spec.Action)
+		hb := &proto.Heartbeat{}
+		if a.ID != "" {
+			hb = &proto.Heartbeat{WorkflowId: toPtr(a.WorkflowID), TaskId: toPtr(a.TaskID), ActionId: toPtr(a.ID)}
+		}
+		if err := stream.send(&proto.ConnectRequest{Heartbeat: hb}); err != nil {
+			// The stream is broken, Recv returns why and the stream is reopened.
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// push hands a to Read, replacing an action that has not been read yet. Only the latest pushed action is runnable.
+func (c *Config) push(a spec.Action) {
+	select {
+	case <-c.Actions:
+	default:
+	}
+	select {
+	case c.Actions <- a:
+	default:
+		// Nothing is reading and Actions is not buffered, the action is read with GetAction instead.
+		c.Log.V(1).Info("dropped pushed action", "action", a.ID)
+	}
+}
+
+// actionKey identifies an action across reads.
+func actionKey(a spec.Action) string {
+	return a.WorkflowID + "/" + a.TaskID + "/" + a.ID
+}
@@ -0,0 +1,262 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
+	"github.com/tinkerbell/tinkerbell/pkg/proto"
+	"github.com/tinkerbell/tinkerbell/tink/agent/internal/spec"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+// mockConnectStream is a Connect client stream that receives resps until ctx is done.
+// Requests are sent on reqs, when set. Action statuses are answered with result instead, when set.
+type mockConnectStream struct {
+	grpc.ClientStream
+	ctx    context.Context
+	resps  chan *proto.ConnectResponse
+	reqs   chan *proto.ConnectRequest
+	result *proto.ActionStatusResult
+	err    error
+}
+
+func (m *mockConnectStream) Send(r *proto.ConnectRequest) error {
+	if r.GetActionStatus() != nil && m.result != nil {
+		go func() { m.resps <- &proto.ConnectResponse{ActionStatus: m.result} }()
+		return nil
+	}
+	if m.reqs == nil {
+		return nil
+	}
+	select {
+	case <-m.ctx.Done():
+		return m.ctx.Err()
+	case m.reqs <- r:
+		return nil
+	}
+}
+
+func (m *mockConnectStream) Recv() (*proto.ConnectResponse, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	select {
+	case <-m.ctx.Done():
+		return nil, status.Error(codes.Canceled, m.ctx.Err().Error())
+	case r := <-m.resps:
+		return r, nil
+	}
+}
+
+func TestConnect(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	resps := make(chan *proto.ConnectResponse)
+	c := &Config{
+		Log:          logr.Discard(),
+		AgentID:      "agent1",
+		Actions:      make(chan spec.Action, 1),
+		PollInterval: time.Hour,
+		TinkServerClient: &mockWorkflowServiceClient{
+			ConnectFunc: func(ctx context.Context) (grpc.BidiStreamingClient[proto.ConnectRequest, proto.ConnectResponse], error) {
+				return &mockConnectStream{ctx: ctx, resps: resps, result: &proto.ActionStatusResult{ActionId: toPtr("action1")}}, nil
+			},
+			ReportActionStatusFunc: func(context.Context, *proto.ActionStatusRequest) (*proto.ActionStatusResponse, error) {
+				t.Error("action status reported with ReportActionStatus while connected")
+				return &proto.ActionStatusResponse{}, nil
+			},
+		},
+	}
+	done := make(chan error)
+	go func() { done <- c.Connect(ctx) }()
+
+	action := func(id string) *proto.ConnectResponse {
+		return &proto.ConnectResponse{Action: &proto.ActionResponse{WorkflowId: toPtr("default/wf"), TaskId: toPtr("task"), ActionId: toPtr(id)}}
+	}
+	read := func() string {
+		t.Helper()
+		rctx, rcancel := context.WithTimeout(ctx, 5*time.Second)
+		defer rcancel()
+		a, err := c.Read(rctx)
+		if err != nil {
+			t.Fatalf("Read() error = %v", err)
+		}
+		return a.ID
+	}
+
+	resps <- &proto.ConnectResponse{}
+	resps <- action("action1")
+	got := []string{read()}
+	if err := c.Write(ctx, spec.Event{Action: spec.Action{WorkflowID: "default/wf", TaskID: "task", ID: "action1"}, State: spec.StateSuccess}); err != nil {
+		t.Fatal(err)
+	}
+	// A copy of a completed action, pushed again after a reconnect for example, is not read again.
+	resps <- action("action1")
+	resps <- action("action2")
+	got = append(got, read())
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	if diff := cmp.Diff([]string{"action1", "action2"}, got); diff != "" {
+		t.Errorf("unexpected actions (-want +got):\n%s", diff)
+	}
+}
+
+func TestConnectReportActionStatus(t *testing.T) {
+	tests := map[string]struct {
+		result    *proto.ActionStatusResult
+		streamErr error
+		wantUnary bool
+		wantCode  codes.Code
+	}{
+		"recorded": {
+			result: &proto.ActionStatusResult{ActionId: toPtr("action1"), Code: toPtr(int32(codes.OK))},
+		},
+		"not found": {
+			result:   &proto.ActionStatusResult{ActionId: toPtr("action1"), Code: toPtr(int32(codes.NotFound)), Message: toPtr("action not found")},
+			wantCode: codes.NotFound,
+		},
+		"stream closed": {
+			streamErr: status.Error(codes.Unavailable, "connection reset"),
+			wantUnary: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			resps := make(chan *proto.ConnectResponse)
+			unary := false
+			c := &Config{
+				Log:     logr.Discard(),
+				AgentID: "agent1",
+				TinkServerClient: &mockWorkflowServiceClient{
+					ReportActionStatusFunc: func(context.Context, *proto.ActionStatusRequest) (*proto.ActionStatusResponse, error) {
+						unary = true
+						return &proto.ActionStatusResponse{}, nil
+					},
+				},
+			}
+			cs := &connectStream{
+				stream:  &mockConnectStream{ctx: ctx, resps: resps, result: tt.result, err: tt.streamErr},
+				results: make(chan *proto.ActionStatusResult, 1),
+				done:    make(chan struct{}),
+			}
+			c.stream.Store(cs)
+			if tt.streamErr != nil {
+				close(cs.done)
+			} else {
+				go func() { cs.results <- (<-resps).GetActionStatus() }()
+			}
+
+			err := c.Write(ctx, spec.Event{Action: spec.Action{WorkflowID: "default/wf", AgentID: "agent1", TaskID: "task", ID: "action1"}, State: spec.StateSuccess})
+			if got := status.Code(errors.Unwrap(err)); err != nil && got != tt.wantCode {
+				t.Errorf("Write() error = %v, want code %v", err, tt.wantCode)
+			}
+			if err == nil && tt.wantCode != codes.OK {
+				t.Errorf("Write() error = nil, want code %v", tt.wantCode)
+			}
+			if unary != tt.wantUnary {
+				t.Errorf("reported with ReportActionStatus = %v, want %v", unary, tt.wantUnary)
+			}
+		})
+	}
+}
+
+func TestConnectHeartbeat(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	reqs := make(chan *proto.ConnectRequest)
+	c := &Config{
+		Log:               logr.Discard(),
+		AgentID:           "agent1",
+		Actions:           make(chan spec.Action, 1),
+		HeartbeatInterval: time.Millisecond,
+		TinkServerClient: &mockWorkflowServiceClient{
+			ConnectFunc: func(ctx context.Context) (grpc.BidiStreamingClient[proto.ConnectRequest, proto.ConnectResponse], error) {
+				return &mockConnectStream{ctx: ctx, resps: make(chan *proto.ConnectResponse), reqs: reqs, result: &proto.ActionStatusResult{}}, nil
+			},
+			ReportActionStatusFunc: func(context.Context, *proto.ActionStatusRequest) (*proto.ActionStatusResponse, error) {
+				return &proto.ActionStatusResponse{}, nil
+			},
+		},
+	}
+	done := make(chan error)
+	go func() { done <- c.Connect(ctx) }()
+	next := func() *proto.ConnectRequest {
+		t.Helper()
+		select {
+		case r := <-reqs:
+			return r
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for a request")
+			return nil
+		}
+	}
+
+	if got := next().GetAgentId(); got != "agent1" {
+		t.Fatalf("expected the first request to identify the agent, got %q", got)
+	}
+	if hb := next().GetHeartbeat(); hb == nil || hb.GetActionId() != "" {
+		t.Fatalf("expected a heartbeat without an action, got %v", hb)
+	}
+	running := spec.Action{WorkflowID: "default/wf", TaskID: "task", ID: "action1"}
+	if err := c.Write(ctx, spec.Event{Action: running, State: spec.StateRunning}); err != nil {
+		t.Fatal(err)
+	}
+	// A heartbeat may have been sent before the write, the one after it has the running action.
+	next()
+	want := &proto.Heartbeat{WorkflowId: toPtr("default/wf"), TaskId: toPtr("task"), ActionId: toPtr("action1")}
+	if diff := cmp.Diff(want, next().GetHeartbeat(), protocmp.Transform()); diff != "" {
+		t.Errorf("unexpected heartbeat (-want +got):\n%s", diff)
+	}
+	if err := c.Write(ctx, spec.Event{Action: running, State: spec.StateSuccess}); err != nil {
+		t.Fatal(err)
+	}
+	next()
+	if hb := next().GetHeartbeat(); hb == nil || hb.GetActionId() != "" {
+		t.Errorf("expected a heartbeat without an action once the action completed, got %v", hb)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+}
+
+func TestConnectUnimplemented(t *testing.T) {
+	c := &Config{
+		Log:     logr.Discard(),
+		AgentID: "agent1",
+		Actions: make(chan spec.Action, 1),
+		TinkServerClient: &mockWorkflowServiceClient{
+			ConnectFunc: func(ctx context.Context) (grpc.BidiStreamingClient[proto.ConnectRequest, proto.ConnectResponse], error) {
+				return &mockConnectStream{ctx: ctx, err: status.Error(codes.Unimplemented, "method Connect not implemented")}, nil
+			},
+			GetActionFunc: func(context.Context, *proto.ActionRequest) (*proto.ActionResponse, error) {
+				return &proto.ActionResponse{ActionId: toPtr("polled")}, nil
+			},
+		},
+	}
+	if err := c.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+
+	// Without a Connect stream actions are polled.
+	a, err := c.Read(context.Background())
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if a.ID != "polled" {
+		t.Errorf("expected the polled action, got %q", a.ID)
+	}
+}
@@ -3,9 +3,13 @@ package grpc
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
+	"os"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/cenkalti/backoff/v5"
 	"github.com/go-logr/logr"
@@ -29,10 +33,57 @@ type Config struct {
 	AgentID          string
 	Actions          chan spec.Action
 	Attributes       *data.AgentAttributes
+	// CancelCheckInterval is how often the server is asked whether the running action has been canceled.
+	// Defaults to 10 seconds.
+	CancelCheckInterval time.Duration
+	// PollInterval is how often GetAction is still called while connected with Connect, in case a pushed action was missed.
+	// Defaults to 1 minute.
+	PollInterval time.Duration
+	// HeartbeatInterval is how often a heartbeat, with the running action, is sent over the Connect stream.
+	// Defaults to 30 seconds.
+	HeartbeatInterval time.Duration
+
+	// connected is true while a Connect stream is up, Read then waits for actions to be pushed on Actions.
+	connected atomic.Bool
+	// done is the key of the last action reported as complete. Pushed copies of it are stale and dropped by Read.
+	done atomic.Value
+	// running is the action last reported as running, or the zero action once it completed. It is sent with heartbeats.
+	running atomic.Value
+	// stream is the open Connect stream, action statuses are reported over it. It is nil while not connected.
+	stream atomic.Pointer[connectStream]
 }
 
+const (
+	defaultCancelCheckInterval = 10 * time.Second
+	defaultPollInterval        = time.Minute
+	defaultHeartbeatInterval   = 30 * time.Second
+)
+
+// Read returns the next action. While connected with Connect it waits for the server to push one,
+// otherwise, or when none is pushed within PollInterval, it asks the server with GetAction.
 func (c *Config) Read(ctx context.Context) (spec.Action, error) {
-	return c.doRead(ctx)
+	if c.Actions == nil || !c.connected.Load() {
+		return c.doRead(ctx)
+	}
+	interval := c.PollInterval
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return spec.Action{}, ctx.Err()
+		case a := <-c.Actions:
+			if done, _ := c.done.Load().(string); done == actionKey(a) {
+				continue
+			}
+			return a, nil
+		case <-timer.C:
+			return c.doRead(ctx)
+		}
+	}
 }
 
 func (c *Config) doRead(ctx context.Context) (spec.Action, error) {
@@ -50,18 +101,43 @@ func (c *Config) doRead(ctx context.Context) (spec.Action, error) {
 		return spec.Action{}, e
 	}
 
+	return actionFromProto(response), nil
+}
+
+// actionFromProto converts an Action served by the server to a spec.Action.
+func actionFromProto(response *proto.ActionResponse) spec.Action {
 	as := spec.Action{
-		TaskID:         response.GetTaskId(),
-		ID:             response.GetActionId(),
-		AgentID:        response.GetAgentId(),
-		WorkflowID:     response.GetWorkflowId(),
-		Name:           response.GetName(),
-		Image:          response.GetImage(),
-		Env:            []spec.Env{},
-		Volumes:        []spec.Volume{},
-		Namespaces:     spec.Namespaces{},
-		Retries:        0,
-		TimeoutSeconds: int(response.GetTimeout()),
+		TaskID:            response.GetTaskId(),
+		ID:                response.GetActionId(),
+		AgentID:           response.GetAgentId(),
+		WorkflowID:        response.GetWorkflowId(),
+		Name:              response.GetName(),
+		Image:             response.GetImage(),
+		Env:               []spec.Env{},
+		Volumes:           []spec.Volume{},
+		Namespaces:        spec.Namespaces{},
+		Retries:           int(response.GetRetries()),
+		TimeoutSeconds:    int(response.GetTimeout()),
+		RetryDelaySeconds: int(response.GetRetryDelay()),
+		OnTimeout:         response.GetOnTimeout(),
+		OnFailure:         response.GetOnFailure(),
+	}
+	if response.GetType() == proto.ActionType_ACTION_TYPE_NATIVE {
+		as.Type = spec.ActionTypeNative
+	}
+	if sb := response.GetSandbox(); sb != nil {
+		as.Sandbox = spec.Sandbox{
+			CPU:                    sb.GetCpu(),
+			Memory:                 sb.GetMemory(),
+			PIDs:                   sb.GetPids(),
+			CapAdd:                 sb.GetCapabilitiesAdd(),
+			CapDrop:                sb.GetCapabilitiesDrop(),
+			ReadOnlyRootFilesystem: sb.GetReadOnlyRootFilesystem(),
+			Privileged:             sb.Privileged,
+		}
+	}
+	for _, ra := range response.GetRemainingActions() {
+		as.RemainingImages = append(as.RemainingImages, ra.GetImage())
 	}
 	if len(response.GetCommand()) > 0 {
 		// action.Cmd is the entrypoint in a container.
@@ -79,6 +155,8 @@ func (c *Config) doRead(ctx context.Context) (spec.Action, error) {
 		*/
 		as.Args = response.GetCommand()
 	}
+	// The entrypoint is only set by v1alpha2 Workflows, which model the entrypoint and its arguments separately.
+	as.Cmd = response.GetEntrypoint()
 	for _, v := range response.GetVolumes() {
 		as.Volumes = append(as.Volumes, spec.Volume(v))
 	}
@@ -109,7 +187,7 @@ func (c *Config) doRead(ctx context.Context) (spec.Action, error) {
 		}
 	}
 
-	return as, nil
+	return as
 }
 
 func (c *Config) Write(ctx context.Context, event spec.Event) error {
@@ -128,10 +206,27 @@ func (c *Config) doWrite(ctx context.Context, event spec.Event) error {
 		ExecutionStop:     timestamppb.New(event.Action.ExecutionStop),
 		ExecutionDuration: toPtr(event.Action.ExecutionDuration),
 		Message:           &proto.ActionMessage{Message: toPtr(event.Message)},
+		Outputs:           event.Outputs,
 	}
-	_, err := c.TinkServerClient.ReportActionStatus(ctx, ar)
+	if event.Action.Attempt > 0 {
+		ar.Attempt = toPtr(int64(event.Action.Attempt))
+	}
+	if event.Hook != nil {
+		ar.Hook = &proto.ActionHook{
+			Name:    toPtr(string(event.Hook.Name)),
+			State:   specToProto(event.Hook.State),
+			Message: toPtr(event.Hook.Message),
+		}
+	}
+	err := c.reportActionStatus(ctx, ar)
 	switch status.Code(err) { //nolint:exhaustive // we want to retry on any error that is not explicitly marked as permanent
 	case codes.OK:
+		if event.State != spec.StateRunning {
+			c.done.Store(actionKey(event.Action))
+			c.running.Store(spec.Action{})
+		} else {
+			c.running.Store(event.Action)
+		}
 		return nil
 	case codes.NotFound, codes.InvalidArgument, codes.FailedPrecondition:
 		return backoff.Permanent(err)
@@ -143,18 +238,104 @@ func (c *Config) doWrite(ctx context.Context, event spec.Event) error {
 	return nil
 }
 
-func NewClientConn(authority string, tlsEnabled bool, tlsInsecure bool) (*grpc.ClientConn, error) {
+// reportActionStatus reports ar over the Connect stream while one is open, otherwise,
+// or when the stream closes before the server answers, with ReportActionStatus.
+func (c *Config) reportActionStatus(ctx context.Context, ar *proto.ActionStatusRequest) error {
+	if s := c.stream.Load(); s != nil {
+		err := s.report(ctx, ar)
+		if !errors.Is(err, errStreamClosed) {
+			return err
+		}
+		c.Log.V(1).Info("connect stream closed, reporting action status with ReportActionStatus", "error", err)
+	}
+	_, err := c.TinkServerClient.ReportActionStatus(ctx, ar)
+
+	return err
+}
+
+// WatchCancel polls the server until it reports the action as canceled, returning nil, or until ctx is done.
+// Errors from the server are logged and polling continues. The server also records each check as a heartbeat of the Agent.
+func (c *Config) WatchCancel(ctx context.Context, action spec.Action) error {
+	interval := c.CancelCheckInterval
+	if interval <= 0 {
+		interval = defaultCancelCheckInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	req := &proto.CheckActionRequest{
+		WorkflowId: toPtr(action.WorkflowID),
+		AgentId:    toPtr(action.AgentID),
+		TaskId:     toPtr(action.TaskID),
+		ActionId:   toPtr(action.ID),
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		resp, err := c.TinkServerClient.CheckAction(ctx, req)
+		if err != nil {
+			if status.Code(err) == codes.Unimplemented {
+				return fmt.Errorf("server does not support action cancellation: %w", err)
+			}
+			c.Log.V(1).Info("error checking for action cancellation", "error", err)
+			continue
+		}
+		if resp.GetCancel() {
+			return nil
+		}
+	}
+}
+
+// ClientAuth is how the Agent authenticates to the Tink server. It requires TLS.
+type ClientAuth struct {
+	// CertFile and KeyFile are the client certificate, whose common name or DNS SAN is the Agent ID, and its key.
+	CertFile string
+	KeyFile  string
+	// CAFile is the CA bundle the Tink server certificate is verified with. The system roots are used when empty.
+	CAFile string
+	// Token is the bootstrap token sent, as a bearer token, by Agents without a client certificate.
+	Token string
+}
+
+func NewClientConn(authority string, tlsEnabled bool, tlsInsecure bool, auth ClientAuth) (*grpc.ClientConn, error) {
 	if authority == "" {
 		return nil, errors.New("the Tinkerbell server address is required, none provided")
 	}
-	var creds grpc.DialOption
+	opts := []grpc.DialOption{grpc.WithStatsHandler(otelgrpc.NewClientHandler()), grpc.WithConnectParams(grpc.ConnectParams{Backoff: gbackoff.DefaultConfig})}
 	if tlsEnabled { // #nosec G402
-		creds = grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{InsecureSkipVerify: tlsInsecure}))
+		cfg := &tls.Config{InsecureSkipVerify: tlsInsecure}
+		if auth.CertFile != "" || auth.KeyFile != "" {
+			cert, err := tls.LoadX509KeyPair(auth.CertFile, auth.KeyFile)
+			if err != nil {
+				return nil, fmt.Errorf("load client certificate: %w", err)
+			}
+			cfg.Certificates = []tls.Certificate{cert}
+		}
+		if auth.CAFile != "" {
+			pem, err := os.ReadFile(auth.CAFile)
+			if err != nil {
+				return nil, fmt.Errorf("read CA file: %w", err)
+			}
+			cfg.RootCAs = x509.NewCertPool()
+			if !cfg.RootCAs.AppendCertsFromPEM(pem) {
+				return nil, errors.New("no certificates found in CA file")
+			}
+		}
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(cfg)))
 	} else {
-		creds = grpc.WithTransportCredentials(insecure.NewCredentials())
+		if auth != (ClientAuth{}) {
+			return nil, errors.New("authenticating to the Tinkerbell server requires TLS")
+		}
+		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	}
+	if auth.Token != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(tokenCredentials(auth.Token)))
 	}
 
-	conn, err := grpc.NewClient(authority, creds, grpc.WithStatsHandler(otelgrpc.NewClientHandler()), grpc.WithConnectParams(grpc.ConnectParams{Backoff: gbackoff.DefaultConfig}))
+	conn, err := grpc.NewClient(authority, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("dial tinkerbell server: %w", err)
 	}
@@ -162,6 +343,17 @@ func NewClientConn(authority string, tlsEnabled bool, tlsInsecure bool) (*grpc.C
 	return conn, nil
 }
 
+// tokenCredentials sends a bootstrap token with every request.
+type tokenCredentials string
+
+func (t tokenCredentials) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + string(t)}, nil
+}
+
+func (tokenCredentials) RequireTransportSecurity() bool {
+	return true
+}
+
 func specToProto(inState spec.State) *proto.ActionStatusRequest_StateType {
 	switch inState {
 	case spec.StateRunning:
@@ -172,6 +364,8 @@ func specToProto(inState spec.State) *proto.ActionStatusRequest_StateType {
 		return toPtr(proto.ActionStatusRequest_FAILED)
 	case spec.StateTimeout:
 		return toPtr(proto.ActionStatusRequest_TIMEOUT)
+	case spec.StateCanceled:
+		return toPtr(proto.ActionStatusRequest_CANCELED)
 	default:
 		return toPtr(proto.ActionStatusRequest_UNSPECIFIED)
 	}
@@ -4,16 +4,23 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/tinkerbell/tinkerbell/pkg/proto"
 	"github.com/tinkerbell/tinkerbell/tink/agent/internal/spec"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/testing/protocmp"
 )
 
 type mockWorkflowServiceClient struct {
 	GetActionFunc          func(ctx context.Context, req *proto.ActionRequest) (*proto.ActionResponse, error)
 	ReportActionStatusFunc func(ctx context.Context, req *proto.ActionStatusRequest) (*proto.ActionStatusResponse, error)
+	CheckActionFunc        func(ctx context.Context, req *proto.CheckActionRequest) (*proto.CheckActionResponse, error)
+	StreamActionLogsFunc   func(ctx context.Context) (grpc.ClientStreamingClient[proto.ActionLogsRequest, proto.ActionLogsResponse], error)
+	ConnectFunc            func(ctx context.Context) (grpc.BidiStreamingClient[proto.ConnectRequest, proto.ConnectResponse], error)
 }
 
 func (m *mockWorkflowServiceClient) GetAction(ctx context.Context, req *proto.ActionRequest, _ ...grpc.CallOption) (*proto.ActionResponse, error) {
@@ -24,6 +31,18 @@ func (m *mockWorkflowServiceClient) ReportActionStatus(ctx context.Context, req
 	return m.ReportActionStatusFunc(ctx, req)
 }
 
+func (m *mockWorkflowServiceClient) CheckAction(ctx context.Context, req *proto.CheckActionRequest, _ ...grpc.CallOption) (*proto.CheckActionResponse, error) {
+	return m.CheckActionFunc(ctx, req)
+}
+
+func (m *mockWorkflowServiceClient) StreamActionLogs(ctx context.Context, _ ...grpc.CallOption) (grpc.ClientStreamingClient[proto.ActionLogsRequest, proto.ActionLogsResponse], error) {
+	return m.StreamActionLogsFunc(ctx)
+}
+
+func (m *mockWorkflowServiceClient) Connect(ctx context.Context, _ ...grpc.CallOption) (grpc.BidiStreamingClient[proto.ConnectRequest, proto.ConnectResponse], error) {
+	return m.ConnectFunc(ctx)
+}
+
 var errTest = errors.New("failed to get action")
 
 func TestRead(t *testing.T) {
@@ -52,10 +71,20 @@ func TestRead(t *testing.T) {
 						Value: "",
 					},
 				},
-				Volumes:        []spec.Volume{"/var/lib:/var/lib"},
-				Namespaces:     spec.Namespaces{},
-				Retries:        0,
-				TimeoutSeconds: 60,
+				Volumes:           []spec.Volume{"/var/lib:/var/lib"},
+				Namespaces:        spec.Namespaces{},
+				Retries:           3,
+				TimeoutSeconds:    60,
+				RetryDelaySeconds: 10,
+				OnTimeout:         []string{"echo", "timeout"},
+				OnFailure:         []string{"echo", "failure"},
+				RemainingImages:   []string{"quay.io/tinkerbell/actions/kexec"},
+				Sandbox: spec.Sandbox{
+					Memory:                 "512Mi",
+					CapDrop:                []string{"NET_RAW"},
+					ReadOnlyRootFilesystem: true,
+					Privileged:             toPtr(false),
+				},
 			},
 			protoResponse: &proto.ActionResponse{
 				WorkflowId: toPtr("789"),
@@ -71,6 +100,43 @@ func TestRead(t *testing.T) {
 					"ENV_VAR=value",
 					"UNSET_VAR",
 				},
+				OnTimeout:  []string{"echo", "timeout"},
+				OnFailure:  []string{"echo", "failure"},
+				Retries:    toPtr(int64(3)),
+				RetryDelay: toPtr(int64(10)),
+				RemainingActions: []*proto.RemainingAction{
+					{TaskId: toPtr("456"), ActionId: toPtr("0124"), Name: toPtr("kexec"), Image: toPtr("quay.io/tinkerbell/actions/kexec")},
+				},
+				Sandbox: &proto.Sandbox{
+					Memory:                 toPtr("512Mi"),
+					CapabilitiesDrop:       []string{"NET_RAW"},
+					ReadOnlyRootFilesystem: toPtr(true),
+					Privileged:             toPtr(false),
+				},
+			},
+		},
+		"Success native": {
+			expectedSpec: spec.Action{
+				AgentID:        "123",
+				TaskID:         "456",
+				WorkflowID:     "789",
+				ID:             "0123",
+				Name:           "wipe disk",
+				Type:           spec.ActionTypeNative,
+				Args:           []string{"/usr/sbin/wipefs", "-a", "/dev/sda"},
+				Env:            []spec.Env{},
+				Volumes:        []spec.Volume{},
+				TimeoutSeconds: 60,
+			},
+			protoResponse: &proto.ActionResponse{
+				WorkflowId: toPtr("789"),
+				TaskId:     toPtr("456"),
+				AgentId:    toPtr("123"),
+				ActionId:   toPtr("0123"),
+				Name:       toPtr("wipe disk"),
+				Type:       toPtr(proto.ActionType_ACTION_TYPE_NATIVE),
+				Timeout:    toPtr(int64(60)),
+				Command:    []string{"/usr/sbin/wipefs", "-a", "/dev/sda"},
 			},
 		},
 		"Success with host network": {
@@ -129,6 +195,34 @@ func TestRead(t *testing.T) {
 				Namespaces: &proto.Namespaces{Network: toPtr("host"), Pid: toPtr("host")},
 			},
 		},
+		"Success with entrypoint": {
+			expectedSpec: spec.Action{
+				AgentID:        "123",
+				TaskID:         "456",
+				WorkflowID:     "789",
+				ID:             "0123",
+				Name:           "first action",
+				Image:          "alpine",
+				Cmd:            "/bin/sh",
+				Args:           []string{"-c", "sleep 5"},
+				Env:            []spec.Env{},
+				Volumes:        []spec.Volume{},
+				Namespaces:     spec.Namespaces{},
+				Retries:        0,
+				TimeoutSeconds: 60,
+			},
+			protoResponse: &proto.ActionResponse{
+				WorkflowId: toPtr("789"),
+				TaskId:     toPtr("456"),
+				AgentId:    toPtr("123"),
+				ActionId:   toPtr("0123"),
+				Name:       toPtr("first action"),
+				Image:      toPtr("alpine"),
+				Timeout:    toPtr(int64(60)),
+				Entrypoint: toPtr("/bin/sh"),
+				Command:    []string{"-c", "sleep 5"},
+			},
+		},
 		"Error": {
 			expectedSpec:  spec.Action{},
 			protoResponse: nil,
@@ -170,20 +264,45 @@ func TestRead(t *testing.T) {
 
 func TestWrite(t *testing.T) {
 	tests := map[string]struct {
-		expectedError error
+		event           spec.Event
+		expectedHook    *proto.ActionHook
+		expectedOutputs map[string]string
+		expectedError   error
 	}{
 		"Success": {
+			event:         spec.Event{State: spec.StateRunning},
 			expectedError: nil,
 		},
 		"Error": {
+			event:         spec.Event{State: spec.StateRunning},
 			expectedError: errors.New("failed to report action"),
 		},
+		"Hook": {
+			event: spec.Event{
+				State: spec.StateFailure,
+				Hook:  &spec.Hook{Name: spec.HookOnFailure, State: spec.StateSuccess, Message: "hook completed"},
+			},
+			expectedHook: &proto.ActionHook{
+				Name:    toPtr("on-failure"),
+				State:   toPtr(proto.ActionStatusRequest_SUCCESS),
+				Message: toPtr("hook completed"),
+			},
+		},
+		"Outputs": {
+			event: spec.Event{
+				State:   spec.StateSuccess,
+				Outputs: map[string]string{"device": "/dev/sda"},
+			},
+			expectedOutputs: map[string]string{"device": "/dev/sda"},
+		},
 	}
 
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
+			var got *proto.ActionStatusRequest
 			mockClient := &mockWorkflowServiceClient{
-				ReportActionStatusFunc: func(_ context.Context, _ *proto.ActionStatusRequest) (*proto.ActionStatusResponse, error) {
+				ReportActionStatusFunc: func(_ context.Context, req *proto.ActionStatusRequest) (*proto.ActionStatusResponse, error) {
+					got = req
 					return nil, test.expectedError
 				},
 			}
@@ -194,7 +313,7 @@ func TestWrite(t *testing.T) {
 			}
 
 			ctx := context.Background()
-			err := config.Write(ctx, spec.Event{State: spec.StateRunning})
+			err := config.Write(ctx, test.event)
 			if test.expectedError != nil {
 				if !errors.Is(err, test.expectedError) {
 					t.Fatalf("expected error: %v, got: %v", test.expectedError, err)
@@ -204,6 +323,80 @@ func TestWrite(t *testing.T) {
 					t.Fatalf("expected no error, got: %v", err)
 				}
 			}
+			if diff := cmp.Diff(test.expectedHook, got.GetHook(), protocmp.Transform()); diff != "" {
+				t.Fatalf("unexpected hook (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(test.expectedOutputs, got.GetOutputs()); diff != "" {
+				t.Fatalf("unexpected outputs (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWatchCancel(t *testing.T) {
+	tests := map[string]struct {
+		responses   []*proto.CheckActionResponse
+		checkErr    error
+		wantErr     bool
+		wantTimeout bool
+	}{
+		"canceled": {
+			responses: []*proto.CheckActionResponse{{Cancel: toPtr(false)}, {Cancel: toPtr(true)}},
+		},
+		"never canceled": {
+			responses:   []*proto.CheckActionResponse{{Cancel: toPtr(false)}},
+			wantErr:     true,
+			wantTimeout: true,
+		},
+		"transient error": {
+			checkErr:    status.Error(codes.Unavailable, "unavailable"),
+			wantErr:     true,
+			wantTimeout: true,
+		},
+		"unimplemented": {
+			checkErr: status.Error(codes.Unimplemented, "unimplemented"),
+			wantErr:  true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			calls := 0
+			var gotReq *proto.CheckActionRequest
+			c := &Config{
+				TinkServerClient: &mockWorkflowServiceClient{
+					CheckActionFunc: func(_ context.Context, req *proto.CheckActionRequest) (*proto.CheckActionResponse, error) {
+						gotReq = req
+						if tc.checkErr != nil {
+							return nil, tc.checkErr
+						}
+						resp := tc.responses[min(calls, len(tc.responses)-1)]
+						calls++
+						return resp, nil
+					},
+				},
+				CancelCheckInterval: time.Millisecond,
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+
+			action := spec.Action{WorkflowID: "default/wf", AgentID: "agent", TaskID: "task", ID: "action"}
+			err := c.WatchCancel(ctx, action)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("WatchCancel() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if got := errors.Is(err, context.DeadlineExceeded); got != tc.wantTimeout {
+				t.Errorf("WatchCancel() timed out = %v, want %v", got, tc.wantTimeout)
+			}
+			want := &proto.CheckActionRequest{
+				WorkflowId: toPtr("default/wf"),
+				AgentId:    toPtr("agent"),
+				TaskId:     toPtr("task"),
+				ActionId:   toPtr("action"),
+			}
+			if diff := cmp.Diff(want, gotReq, protocmp.Transform()); diff != "" {
+				t.Errorf("unexpected request (-want +got):\n%s", diff)
+			}
 		})
 	}
 }
@@ -211,6 +404,8 @@ func TestWrite(t *testing.T) {
 func TestNewClientConn(t *testing.T) {
 	tests := map[string]struct {
 		address string
+		tls     bool
+		auth    ClientAuth
 		wantErr bool
 	}{
 		"no error": {
@@ -221,11 +416,27 @@ func TestNewClientConn(t *testing.T) {
 			address: "",
 			wantErr: true,
 		},
+		"token": {
+			address: "localhost:8080",
+			tls:     true,
+			auth:    ClientAuth{Token: "s3cr3t"},
+		},
+		"token without tls": {
+			address: "localhost:8080",
+			auth:    ClientAuth{Token: "s3cr3t"},
+			wantErr: true,
+		},
+		"missing client certificate": {
+			address: "localhost:8080",
+			tls:     true,
+			auth:    ClientAuth{CertFile: "/does/not/exist.pem", KeyFile: "/does/not/exist-key.pem"},
+			wantErr: true,
+		},
 	}
 
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
-			conn, err := NewClientConn(test.address, false, false)
+			conn, err := NewClientConn(test.address, test.tls, false, test.auth)
 			if test.wantErr {
 				if err == nil {
 					t.Fatalf("expected error, got nil")
@@ -0,0 +1,155 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/tinkerbell/tinkerbell/pkg/proto"
+	"github.com/tinkerbell/tinkerbell/tink/agent/internal/spec"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// logQueueSize is the number of chunks of action output buffered for sending. Output is dropped when the
+	// queue is full so that a slow or unavailable server never blocks an action.
+	logQueueSize = 256
+	// maxLogChunkSize is the maximum size of the data in a single ActionLogsRequest.
+	maxLogChunkSize = 32 * 1024
+
+	streamStdout = "stdout"
+	streamStderr = "stderr"
+)
+
+// StreamLogs opens a StreamActionLogs stream for action. Writes to the returned Logs are sent in the background.
+// Close ends the stream and returns any error from the server.
+func (c *Config) StreamLogs(ctx context.Context, action spec.Action) (spec.Logs, io.Closer, error) {
+	stream, err := c.TinkServerClient.StreamActionLogs(ctx)
+	if err != nil {
+		return spec.Logs{}, nil, fmt.Errorf("error opening action log stream: %w", err)
+	}
+	// The first request identifies the action, all following requests only carry output.
+	first := &proto.ActionLogsRequest{
+		WorkflowId: toPtr(action.WorkflowID),
+		AgentId:    toPtr(action.AgentID),
+		TaskId:     toPtr(action.TaskID),
+		ActionId:   toPtr(action.ID),
+	}
+	if err := stream.Send(first); err != nil {
+		_, rerr := stream.CloseAndRecv()
+		return spec.Logs{}, nil, fmt.Errorf("error sending to action log stream: %w", errors.Join(err, rerr))
+	}
+
+	ls := &logStream{
+		log:    c.Log,
+		stream: stream,
+		queue:  make(chan *proto.ActionLogsRequest, logQueueSize),
+		done:   make(chan struct{}),
+	}
+	go ls.run()
+
+	return spec.Logs{
+		Stdout: &logWriter{ls: ls, stream: streamStdout},
+		Stderr: &logWriter{ls: ls, stream: streamStderr},
+	}, ls, nil
+}
+
+// logStream sends queued action output to the server.
+type logStream struct {
+	log    logr.Logger
+	stream grpc.ClientStreamingClient[proto.ActionLogsRequest, proto.ActionLogsResponse]
+	queue  chan *proto.ActionLogsRequest
+	done   chan struct{}
+	// sendErr is the first error from sending to the stream. It is only read after done is closed.
+	sendErr error
+	dropped atomic.Int64
+
+	// mu guards closed so that no request is queued after the queue is closed.
+	mu     sync.RWMutex
+	closed bool
+}
+
+// run sends requests from the queue until it is closed. After a send error, the remaining requests are discarded.
+func (l *logStream) run() {
+	defer close(l.done)
+	for req := range l.queue {
+		if l.sendErr != nil {
+			continue
+		}
+		if err := l.stream.Send(req); err != nil {
+			l.sendErr = err
+		}
+	}
+}
+
+// enqueue queues data for sending, dropping it if the queue is full or the stream is closed.
+func (l *logStream) enqueue(stream string, data []byte) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if l.closed {
+		return
+	}
+	req := &proto.ActionLogsRequest{
+		Stream: toPtr(stream),
+		Data:   data,
+		Time:   timestamppb.New(time.Now()),
+	}
+	select {
+	case l.queue <- req:
+	default:
+		l.dropped.Add(1)
+	}
+}
+
+// Close sends any queued output, ends the stream and waits for the server to acknowledge it. It is safe to call multiple times.
+func (l *logStream) Close() error {
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return nil
+	}
+	l.closed = true
+	close(l.queue)
+	l.mu.Unlock()
+
+	<-l.done
+	if n := l.dropped.Load(); n > 0 {
+		l.log.Info("action log stream queue was full, dropped output", "chunks", n)
+	}
+	// CloseAndRecv returns the status of the stream, which is also the real error when a send failed with io.EOF.
+	if _, err := l.stream.CloseAndRecv(); err != nil {
+		return fmt.Errorf("error closing action log stream: %w", err)
+	}
+	if l.sendErr != nil && !errors.Is(l.sendErr, io.EOF) {
+		return fmt.Errorf("error sending to action log stream: %w", l.sendErr)
+	}
+
+	return nil
+}
+
+// logWriter is an io.Writer for one output stream of an action.
+type logWriter struct {
+	ls     *logStream
+	stream string
+}
+
+// Write queues p to be sent. It never blocks on the server and always reports all of p as written,
+// so it can be used with io.MultiWriter alongside the runtime's own logging.
+func (w *logWriter) Write(p []byte) (int, error) {
+	for b := p; len(b) > 0; {
+		n := min(len(b), maxLogChunkSize)
+		// The caller may reuse p, so the data is copied before it is queued.
+		data := make([]byte, n)
+		copy(data, b[:n])
+		w.ls.enqueue(w.stream, data)
+		b = b[n:]
+	}
+
+	return len(p), nil
+}
@@ -0,0 +1,133 @@
+package grpc
+
+import (
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
+	"github.com/tinkerbell/tinkerbell/pkg/proto"
+	"github.com/tinkerbell/tinkerbell/tink/agent/internal/spec"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// mockLogStream records the requests sent on a StreamActionLogs stream.
+type mockLogStream struct {
+	grpc.ClientStream
+	mu      sync.Mutex
+	reqs    []*proto.ActionLogsRequest
+	sendErr error
+	recvErr error
+	closed  bool
+}
+
+func (m *mockLogStream) Send(req *proto.ActionLogsRequest) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.sendErr != nil {
+		return m.sendErr
+	}
+	m.reqs = append(m.reqs, req)
+	return nil
+}
+
+func (m *mockLogStream) CloseAndRecv() (*proto.ActionLogsResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.closed = true
+	return &proto.ActionLogsResponse{}, m.recvErr
+}
+
+func TestStreamLogs(t *testing.T) {
+	type sent struct {
+		Stream string
+		Data   string
+	}
+	tests := map[string]struct {
+		stream       *mockLogStream
+		write        func(spec.Logs)
+		wantSent     []sent
+		wantOpenErr  bool
+		wantCloseErr bool
+	}{
+		"stdout and stderr": {
+			stream: &mockLogStream{},
+			write: func(l spec.Logs) {
+				_, _ = l.Stdout.Write([]byte("wiping /dev/sda\n"))
+				_, _ = l.Stderr.Write([]byte("error: device busy\n"))
+			},
+			wantSent: []sent{
+				{},
+				{Stream: "stdout", Data: "wiping /dev/sda\n"},
+				{Stream: "stderr", Data: "error: device busy\n"},
+			},
+		},
+		"large writes are split": {
+			stream: &mockLogStream{},
+			write: func(l spec.Logs) {
+				_, _ = io.WriteString(l.Stdout, strings.Repeat("a", maxLogChunkSize+1))
+			},
+			wantSent: []sent{
+				{},
+				{Stream: "stdout", Data: strings.Repeat("a", maxLogChunkSize)},
+				{Stream: "stdout", Data: "a"},
+			},
+		},
+		"server error": {
+			stream:       &mockLogStream{recvErr: status.Error(codes.NotFound, "action not found")},
+			write:        func(l spec.Logs) { _, _ = l.Stdout.Write([]byte("hello\n")) },
+			wantSent:     []sent{{}, {Stream: "stdout", Data: "hello\n"}},
+			wantCloseErr: true,
+		},
+		"send error": {
+			stream:      &mockLogStream{sendErr: io.EOF, recvErr: status.Error(codes.Unimplemented, "not implemented")},
+			write:       func(spec.Logs) {},
+			wantOpenErr: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := &Config{
+				Log: logr.Discard(),
+				TinkServerClient: &mockWorkflowServiceClient{
+					StreamActionLogsFunc: func(context.Context) (grpc.ClientStreamingClient[proto.ActionLogsRequest, proto.ActionLogsResponse], error) {
+						return tt.stream, nil
+					},
+				},
+			}
+			action := spec.Action{WorkflowID: "default/wf", AgentID: "agent", TaskID: "task", ID: "action"}
+			logs, closer, err := c.StreamLogs(context.Background(), action)
+			if (err != nil) != tt.wantOpenErr {
+				t.Fatalf("StreamLogs() error = %v, wantErr %v", err, tt.wantOpenErr)
+			}
+			if err != nil {
+				if !tt.stream.closed {
+					t.Error("expected stream to be closed")
+				}
+				return
+			}
+			tt.write(logs)
+			if err := closer.Close(); (err != nil) != tt.wantCloseErr {
+				t.Fatalf("Close() error = %v, wantErr %v", err, tt.wantCloseErr)
+			}
+			// Writes after Close are dropped.
+			_, _ = logs.Stdout.Write([]byte("late\n"))
+
+			if tt.stream.reqs[0].GetWorkflowId() != "default/wf" || tt.stream.reqs[0].GetActionId() != "action" {
+				t.Errorf("first request does not identify the action: %v", tt.stream.reqs[0])
+			}
+			got := make([]sent, 0, len(tt.stream.reqs))
+			for _, r := range tt.stream.reqs {
+				got = append(got, sent{Stream: r.GetStream(), Data: string(r.GetData())})
+			}
+			if diff := cmp.Diff(tt.wantSent, got); diff != "" {
+				t.Errorf("unexpected requests (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
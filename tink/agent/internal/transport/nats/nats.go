@@ -2,6 +2,7 @@ package nats
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/netip"
@@ -14,6 +15,9 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
+// announceInterval is how often the Agent announces itself to tink-server.
+const announceInterval = 30 * time.Second
+
 type Config struct {
 	StreamName     string
 	EventsSubject  string
@@ -74,6 +78,7 @@ func (c *Config) Start(ctx context.Context) error {
 	defer func() {
 		_ = sub.Unsubscribe()
 	}()
+	go c.announce(ctx)
 
 	for {
 		select {
@@ -116,8 +121,34 @@ func (c *Config) Write(_ context.Context, event spec.Event) error {
 		c.Actions = make(chan spec.Action)
 		c.cancel <- true
 	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("error encoding event: %w", err)
+	}
 	return c.conn.PublishMsg(&nats.Msg{
 		Subject: fmt.Sprintf("%v.%v.%v", c.StreamName, c.AgentID, c.EventsSubject),
-		Data:    []byte(event.String()),
+		Data:    data,
 	})
 }
+
+// announce publishes an event without an Action until ctx is done. tink-server only publishes Actions
+// to Agents that published on NATS, announcing again picks up new Workflows and tink-server restarts.
+func (c *Config) announce(ctx context.Context) {
+	data, err := json.Marshal(spec.Event{Action: spec.Action{AgentID: c.AgentID}})
+	if err != nil {
+		return
+	}
+	subj := fmt.Sprintf("%v.%v.%v", c.StreamName, c.AgentID, c.EventsSubject)
+	ticker := time.NewTicker(announceInterval)
+	defer ticker.Stop()
+	for {
+		if err := c.conn.Publish(subj, data); err != nil {
+			c.Log.V(1).Info("error announcing agent", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package agent
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tinkerbell/tinkerbell/tink/agent/internal/spec"
+)
+
+const (
+	// OutputEnvVar is the environment variable, set in every Action container, that holds the path of the file
+	// the Action can write outputs to. Each line of the file is a key=value pair. Blank lines and lines starting with # are ignored.
+	OutputEnvVar = "TINKERBELL_OUTPUT"
+	// outputMountPath is where the output directory of an Action is mounted in the Action container.
+	outputMountPath = "/tinkerbell/output"
+	outputFileName  = "outputs"
+	// maxOutputSize is the maximum number of bytes read from the output file of an Action.
+	maxOutputSize = 64 * 1024
+)
+
+// prepareOutput creates the directory the Action writes its outputs to and mounts it into the Action.
+// It returns the directory on the host. An empty directory is returned when output capture is disabled.
+func (c *Config) prepareOutput(action *spec.Action) (string, error) {
+	if c.OutputDir == "" {
+		return "", nil
+	}
+	dir := filepath.Join(c.OutputDir, action.ID)
+	if err := os.RemoveAll(dir); err != nil {
+		return "", fmt.Errorf("error removing previous output directory: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("error creating output directory: %w", err)
+	}
+	// Native actions run on the host, they write to the output directory directly.
+	if action.Type == spec.ActionTypeNative {
+		action.Env = append(action.Env, spec.Env{Key: OutputEnvVar, Value: filepath.Join(dir, outputFileName)})
+		return dir, nil
+	}
+	action.Volumes = append(action.Volumes, spec.Volume(fmt.Sprintf("%s:%s", dir, outputMountPath)))
+	action.Env = append(action.Env, spec.Env{Key: OutputEnvVar, Value: outputMountPath + "/" + outputFileName})
+
+	return dir, nil
+}
+
+// readOutputs returns the outputs the Action wrote to its output file and then removes the output directory.
+// No outputs are returned when the Action did not write the file.
+func readOutputs(dir string) (map[string]string, error) {
+	defer os.RemoveAll(dir)
+
+	f, err := os.Open(filepath.Join(dir, outputFileName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error opening output file: %w", err)
+	}
+	defer f.Close()
+
+	return parseOutputs(io.LimitReader(f, maxOutputSize))
+}
+
+// parseOutputs parses key=value lines. Blank lines and lines starting with # are ignored.
+// When a key is set more than once, the last value is used.
+func parseOutputs(r io.Reader) (map[string]string, error) {
+	outputs := map[string]string{}
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 0, 4096), maxOutputSize)
+	for n := 1; s.Scan(); n++ {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		k, v, ok := strings.Cut(line, "=")
+		k = strings.TrimSpace(k)
+		if !ok || k == "" {
+			return nil, fmt.Errorf("invalid output on line %d, must be key=value: %q", n, line)
+		}
+		outputs[k] = strings.TrimSpace(v)
+	}
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("error reading outputs: %w", err)
+	}
+	if len(outputs) == 0 {
+		return nil, nil
+	}
+
+	return outputs, nil
+}
@@ -0,0 +1,134 @@
+package agent
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
+	"github.com/tinkerbell/tinkerbell/tink/agent/internal/spec"
+)
+
+func TestParseOutputs(t *testing.T) {
+	tests := map[string]struct {
+		input   string
+		want    map[string]string
+		wantErr bool
+	}{
+		"empty": {},
+		"key values": {
+			input: "device=/dev/nvme0n1\nsize = 512110190592\n",
+			want:  map[string]string{"device": "/dev/nvme0n1", "size": "512110190592"},
+		},
+		"comments and blank lines": {
+			input: "# discovered disks\n\ndevice=/dev/sda\n",
+			want:  map[string]string{"device": "/dev/sda"},
+		},
+		"last value wins": {
+			input: "device=/dev/sda\ndevice=/dev/sdb\n",
+			want:  map[string]string{"device": "/dev/sdb"},
+		},
+		"value with equals": {
+			input: "cmdline=console=ttyS0\n",
+			want:  map[string]string{"cmdline": "console=ttyS0"},
+		},
+		"missing equals": {
+			input:   "device\n",
+			wantErr: true,
+		},
+		"missing key": {
+			input:   "=/dev/sda\n",
+			wantErr: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := parseOutputs(strings.NewReader(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseOutputs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("parseOutputs() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// outputExecutor writes outputs to the host side of the output directory mounted into the action.
+type outputExecutor struct {
+	outputs string
+	env     []spec.Env
+}
+
+func (e *outputExecutor) Execute(_ context.Context, a spec.Action) error {
+	e.env = a.Env
+	for _, v := range a.Volumes {
+		src, dst, _ := strings.Cut(string(v), ":")
+		if dst == outputMountPath && e.outputs != "" {
+			return os.WriteFile(filepath.Join(src, outputFileName), []byte(e.outputs), 0o600)
+		}
+	}
+	return nil
+}
+
+func TestRunOutputs(t *testing.T) {
+	tests := map[string]struct {
+		outputDir   bool
+		outputs     string
+		wantOutputs map[string]string
+		wantEnv     []spec.Env
+	}{
+		"outputs collected": {
+			outputDir:   true,
+			outputs:     "device=/dev/nvme0n1\n",
+			wantOutputs: map[string]string{"device": "/dev/nvme0n1"},
+			wantEnv:     []spec.Env{{Key: OutputEnvVar, Value: "/tinkerbell/output/outputs"}},
+		},
+		"no outputs written": {
+			outputDir: true,
+			wantEnv:   []spec.Env{{Key: OutputEnvVar, Value: "/tinkerbell/output/outputs"}},
+		},
+		"output capture disabled": {
+			outputs: "device=/dev/nvme0n1\n",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			dir := ""
+			if tt.outputDir {
+				dir = t.TempDir()
+			}
+			executor := &outputExecutor{outputs: tt.outputs}
+			writer := &captureWriter{cancel: cancel}
+			c := &Config{
+				TransportReader: &hookActionReader{action: spec.Action{ID: "a1", TimeoutSeconds: 5}},
+				RuntimeExecutor: executor,
+				TransportWriter: writer,
+				OutputDir:       dir,
+			}
+			c.Run(ctx, logr.Discard())
+
+			if len(writer.events) != 2 {
+				t.Fatalf("expected 2 events, got %d", len(writer.events))
+			}
+			if diff := cmp.Diff(tt.wantOutputs, writer.events[1].Outputs); diff != "" {
+				t.Errorf("unexpected outputs (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantEnv, executor.env); diff != "" {
+				t.Errorf("unexpected env (-want +got):\n%s", diff)
+			}
+			if tt.outputDir {
+				if _, err := os.Stat(filepath.Join(dir, "a1")); !os.IsNotExist(err) {
+					t.Errorf("expected output directory to be removed, got: %v", err)
+				}
+			}
+		})
+	}
+}
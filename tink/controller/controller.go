@@ -3,11 +3,16 @@ package controller
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"path/filepath"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/tinkerbell/tinkerbell/api/v1alpha1/bmc"
 	"github.com/tinkerbell/tinkerbell/api/v1alpha1/tinkerbell"
+	tinkerbellv1alpha2 "github.com/tinkerbell/tinkerbell/api/v1alpha2/tinkerbell"
 	"github.com/tinkerbell/tinkerbell/tink/controller/internal/workflow"
+	workflowv1alpha2 "github.com/tinkerbell/tinkerbell/tink/controller/internal/workflow/v1alpha2"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
@@ -16,14 +21,19 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	ctrlcontroller "sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 )
 
 var schemeBuilder = runtime.NewSchemeBuilder(
 	clientgoscheme.AddToScheme,
 	tinkerbell.AddToScheme,
+	tinkerbellv1alpha2.AddToScheme,
 	bmc.AddToScheme,
 )
 
+// DefaultConversionWebhookPort is the default port the conversion webhook server listens on.
+const DefaultConversionWebhookPort = 9443
+
 type Config struct {
 	Namespace               string
 	Client                  *rest.Config
@@ -33,6 +43,32 @@ type Config struct {
 	ReferenceAllowListRules []string
 	ReferenceDenyListRules  []string
 	MaxConcurrentReconciles int
+	// EnableV1Alpha2 reconciles v1alpha2 Workflows instead of v1alpha1 Workflows.
+	// The v1alpha2 CRDs must be installed in the cluster.
+	EnableV1Alpha2 bool
+	// ConversionWebhook configures the webhook that converts Hardware and Workflows between v1alpha1 and v1alpha2.
+	ConversionWebhook ConversionWebhook
+	// EventsWebhookURL is the URL to which Workflow lifecycle events are posted as CloudEvents.
+	// Events are not posted when empty. Kubernetes Events are always emitted.
+	EventsWebhookURL string
+	// AgentLostTimeout is how long the Agent of a running Workflow can go without a heartbeat before the Workflow
+	// is marked failed. Zero, the default, disables the check. Agents only send heartbeats over the Connect stream
+	// and when reporting Action status, so the timeout must be longer than the longest Action run without Connect.
+	AgentLostTimeout time.Duration
+}
+
+// ConversionWebhook configures the conversion webhook server.
+type ConversionWebhook struct {
+	// Enabled serves the conversion webhook.
+	Enabled bool
+	// BindAddr is the address the webhook server listens on. Empty means all addresses.
+	BindAddr string
+	// BindPort is the port the webhook server listens on.
+	BindPort int
+	// CertFile is the path to the TLS certificate file. The Kubernetes API server must trust this certificate.
+	CertFile string
+	// KeyFile is the path to the TLS key file.
+	KeyFile string
 }
 
 type dynamicClient interface {
@@ -83,10 +119,37 @@ func WithReferenceDenyListRules(rules []string) Option {
 	}
 }
 
+func WithEnableV1Alpha2(enable bool) Option {
+	return func(c *Config) {
+		c.EnableV1Alpha2 = enable
+	}
+}
+
+func WithConversionWebhook(cw ConversionWebhook) Option {
+	return func(c *Config) {
+		c.ConversionWebhook = cw
+	}
+}
+
+func WithEventsWebhookURL(u string) Option {
+	return func(c *Config) {
+		c.EventsWebhookURL = u
+	}
+}
+
+func WithAgentLostTimeout(d time.Duration) Option {
+	return func(c *Config) {
+		c.AgentLostTimeout = d
+	}
+}
+
 func NewConfig(opts ...Option) *Config {
 	defatuls := &Config{
 		EnableLeaderElection:    true,
 		MaxConcurrentReconciles: 1,
+		ConversionWebhook: ConversionWebhook{
+			BindPort: DefaultConversionWebhookPort,
+		},
 	}
 
 	for _, opt := range opts {
@@ -110,7 +173,46 @@ func (c *Config) Start(ctx context.Context, log logr.Logger) error {
 	if c.Namespace != "" {
 		options.Cache = cache.Options{DefaultNamespaces: map[string]cache.Config{c.Namespace: {}}}
 	}
+	if c.ConversionWebhook.Enabled {
+		if c.ConversionWebhook.CertFile == "" || c.ConversionWebhook.KeyFile == "" {
+			return fmt.Errorf("conversion webhook requires a TLS cert and key file")
+		}
+		options.WebhookServer = webhook.NewServer(webhook.Options{
+			Host:     c.ConversionWebhook.BindAddr,
+			Port:     c.ConversionWebhook.BindPort,
+			CertDir:  filepath.Dir(c.ConversionWebhook.CertFile),
+			CertName: filepath.Base(c.ConversionWebhook.CertFile),
+			KeyName:  filepath.Base(c.ConversionWebhook.KeyFile),
+		})
+	}
 
+	mgr, err := newManager(c.Client, c.DynamicClient, options, c.MaxConcurrentReconciles, c.EnableV1Alpha2, c.workflowOptions()...)
+	if err != nil {
+		return err
+	}
+	if c.ConversionWebhook.Enabled {
+		if err := setupConversionWebhook(mgr); err != nil {
+			return err
+		}
+	}
+	if !c.EnableV1Alpha2 {
+		var wh *workflow.Webhook
+		if c.EventsWebhookURL != "" {
+			if _, err := url.ParseRequestURI(c.EventsWebhookURL); err != nil {
+				return fmt.Errorf("invalid events webhook URL: %w", err)
+			}
+			wh = workflow.NewWebhook(c.EventsWebhookURL)
+		}
+		if err := mgr.Add(workflow.NewEventNotifier(mgr.GetCache(), mgr.GetEventRecorder("tink-controller"), wh, log)); err != nil {
+			return fmt.Errorf("setup workflow event notifier: %w", err)
+		}
+	}
+
+	return mgr.Start(ctx)
+}
+
+// workflowOptions returns the options for the v1alpha1 Workflow Reconciler.
+func (c *Config) workflowOptions() []workflow.Option {
 	wfOpts := []workflow.Option{}
 	if len(c.ReferenceAllowListRules) > 0 {
 		wfOpts = append(wfOpts, workflow.WithAllowReferenceRules(c.ReferenceAllowListRules))
@@ -118,18 +220,14 @@ func (c *Config) Start(ctx context.Context, log logr.Logger) error {
 	if len(c.ReferenceDenyListRules) > 0 {
 		wfOpts = append(wfOpts, workflow.WithDenyReferenceRules(c.ReferenceDenyListRules))
 	}
+	wfOpts = append(wfOpts, workflow.WithAgentLostTimeout(c.AgentLostTimeout))
 
-	mgr, err := newManager(c.Client, c.DynamicClient, options, c.MaxConcurrentReconciles, wfOpts...)
-	if err != nil {
-		return err
-	}
-
-	return mgr.Start(ctx)
+	return wfOpts
 }
 
 // NewManager creates a new controller manager with tink controller controllers pre-registered.
 // If opts.Scheme is nil, DefaultScheme() is used.
-func newManager(cfg *rest.Config, dc dynamicClient, opts controllerruntime.Options, maxConcurrentReconciles int, wfOpts ...workflow.Option) (controllerruntime.Manager, error) {
+func newManager(cfg *rest.Config, dc dynamicClient, opts controllerruntime.Options, maxConcurrentReconciles int, enableV1Alpha2 bool, wfOpts ...workflow.Option) (controllerruntime.Manager, error) {
 	if opts.Scheme == nil {
 		s := runtime.NewScheme()
 		_ = schemeBuilder.AddToScheme(s)
@@ -141,9 +239,30 @@ func newManager(cfg *rest.Config, dc dynamicClient, opts controllerruntime.Optio
 		return nil, fmt.Errorf("controller manager: %w", err)
 	}
 
+	if enableV1Alpha2 {
+		if err = workflowv1alpha2.NewReconciler(mgr.GetClient(), dc).SetupWithManager(mgr, ctrlcontroller.Options{MaxConcurrentReconciles: maxConcurrentReconciles}); err != nil {
+			return nil, fmt.Errorf("setup v1alpha2 workflow reconciler: %w", err)
+		}
+
+		return mgr, nil
+	}
+
 	if err = workflow.NewReconciler(mgr.GetClient(), dc, wfOpts...).SetupWithManager(mgr, ctrlcontroller.Options{MaxConcurrentReconciles: maxConcurrentReconciles}); err != nil {
 		return nil, fmt.Errorf("setup workflow reconciler: %w", err)
 	}
 
 	return mgr, nil
 }
+
+// setupConversionWebhook registers the v1alpha1 <-> v1alpha2 conversion webhook for Hardware and Workflows.
+// v1alpha1 is the hub, the v1alpha2 types implement conversion to and from it.
+func setupConversionWebhook(mgr controllerruntime.Manager) error {
+	if err := controllerruntime.NewWebhookManagedBy(mgr, &tinkerbellv1alpha2.Hardware{}).Complete(); err != nil {
+		return fmt.Errorf("setup hardware conversion webhook: %w", err)
+	}
+	if err := controllerruntime.NewWebhookManagedBy(mgr, &tinkerbellv1alpha2.Workflow{}).Complete(); err != nil {
+		return fmt.Errorf("setup workflow conversion webhook: %w", err)
+	}
+
+	return nil
+}
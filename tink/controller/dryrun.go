@@ -0,0 +1,121 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/tinkerbell/tinkerbell/api/v1alpha1/tinkerbell"
+	"github.com/tinkerbell/tinkerbell/pkg/backend/kube"
+	"github.com/tinkerbell/tinkerbell/tink/controller/internal/workflow"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// maxDryRunRequestSize is the maximum size of a dry-run request body.
+const maxDryRunRequestSize = 1 << 20
+
+// DryRunRequest is the body of a Template dry-run request.
+type DryRunRequest = workflow.DryRunRequest
+
+// DryRunResponse is the body of a Template dry-run response.
+type DryRunResponse struct {
+	// Tasks are the rendered Tasks, as they would be in the Workflow status.
+	Tasks []tinkerbell.Task `json:"tasks,omitempty"`
+	// Error is the render, validation or request error.
+	Error string `json:"error,omitempty"`
+}
+
+// DryRunHandler returns an HTTP handler that renders a Template with a Hardware, without creating a Workflow.
+// The request body is a JSON encoded DryRunRequest and the response body is a JSON encoded DryRunResponse.
+// Requests must have a Kubernetes bearer token. The Template, Hardware and references are read with it,
+// so the caller needs permission to read them.
+func (c *Config) DryRunHandler(log logr.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeDryRunResponse(w, http.StatusMethodNotAllowed, DryRunResponse{Error: "method not allowed"})
+			return
+		}
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || token == "" {
+			writeDryRunResponse(w, http.StatusUnauthorized, DryRunResponse{Error: "a bearer token is required"})
+			return
+		}
+		var req DryRunRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxDryRunRequestSize)).Decode(&req); err != nil {
+			writeDryRunResponse(w, http.StatusBadRequest, DryRunResponse{Error: fmt.Sprintf("error decoding request: %v", err)})
+			return
+		}
+		if err := req.Validate(); err != nil {
+			writeDryRunResponse(w, http.StatusBadRequest, DryRunResponse{Error: err.Error()})
+			return
+		}
+
+		rec, err := c.dryRunReconciler(token)
+		if err != nil {
+			log.Error(err, "error creating dry-run client")
+			writeDryRunResponse(w, http.StatusInternalServerError, DryRunResponse{Error: "error creating client"})
+			return
+		}
+		tasks, err := rec.DryRun(r.Context(), log, req)
+		if err != nil {
+			writeDryRunResponse(w, dryRunStatusCode(err), DryRunResponse{Error: err.Error()})
+			return
+		}
+
+		writeDryRunResponse(w, http.StatusOK, DryRunResponse{Tasks: tasks})
+	})
+}
+
+// dryRunReconciler returns a Workflow Reconciler whose clients authenticate with token.
+func (c *Config) dryRunReconciler(token string) (*workflow.Reconciler, error) {
+	if c.Client == nil {
+		return nil, errors.New("no Kubernetes client config")
+	}
+	cfg := rest.AnonymousClientConfig(c.Client)
+	cfg.BearerToken = token
+
+	s := runtime.NewScheme()
+	if err := tinkerbell.AddToScheme(s); err != nil {
+		return nil, err
+	}
+	cl, err := ctrlclient.New(cfg, ctrlclient.Options{Scheme: s})
+	if err != nil {
+		return nil, err
+	}
+	dc, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return workflow.NewReconciler(cl, &kube.Backend{DynamicClient: dc}, c.workflowOptions()...), nil
+}
+
+// dryRunStatusCode returns the HTTP status code for an error from a dry-run.
+func dryRunStatusCode(err error) int {
+	switch {
+	case errors.Is(err, workflow.ErrRender):
+		return http.StatusUnprocessableEntity
+	case kerrors.IsNotFound(err):
+		return http.StatusNotFound
+	case kerrors.IsUnauthorized(err):
+		return http.StatusUnauthorized
+	case kerrors.IsForbidden(err):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func writeDryRunResponse(w http.ResponseWriter, code int, resp DryRunResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(resp)
+}
@@ -1,6 +1,8 @@
 package workflow
 
 import (
+	"strings"
+
 	"github.com/oklog/ulid/v2"
 	v1alpha1 "github.com/tinkerbell/tinkerbell/api/v1alpha1/tinkerbell"
 	"github.com/tinkerbell/tinkerbell/pkg/proto"
@@ -25,6 +27,15 @@ func YAMLToStatus(wf *Workflow) *v1alpha1.WorkflowStatus {
 				State:       v1alpha1.WorkflowState(proto.ActionStatusRequest_PENDING.String()),
 				Environment: action.Environment,
 				Pid:         action.Pid,
+				OnTimeout:   action.OnTimeout,
+				OnFailure:   action.OnFailure,
+				Retries:     action.Retries,
+				RetryDelay:  action.RetryDelay,
+				If:          strings.TrimSpace(action.If),
+				Type:        v1alpha1.ActionType(action.Type),
+			}
+			if action.Sandbox != nil {
+				a.Sandbox = convertSandbox(*action.Sandbox)
 			}
 			if action.Namespaces.Network != "" || action.Namespaces.PID != "" {
 				a.Namespaces = &v1alpha1.ActionNamespaces{
@@ -41,6 +52,7 @@ func YAMLToStatus(wf *Workflow) *v1alpha1.WorkflowStatus {
 			Volumes:     task.Volumes,
 			Environment: task.Environment,
 			Actions:     actions,
+			DependsOn:   task.DependsOn,
 		})
 		// only use the first Task's agentID. At the moment only support single Task Workflows.
 		if agentID == "" {
@@ -53,3 +65,19 @@ func YAMLToStatus(wf *Workflow) *v1alpha1.WorkflowStatus {
 		AgentID:       agentID,
 	}
 }
+
+// convertSandbox converts a template action sandbox to its v1alpha1 form. Empty resources and capabilities are left unset.
+func convertSandbox(s ActionSandbox) *v1alpha1.ActionSandbox {
+	sb := &v1alpha1.ActionSandbox{
+		ReadOnlyRootFilesystem: s.ReadOnlyRootFilesystem,
+		Privileged:             s.Privileged,
+	}
+	if s.Resources != (ActionResources{}) {
+		sb.Resources = &v1alpha1.ActionResources{CPU: s.Resources.CPU, Memory: s.Resources.Memory, PIDs: s.Resources.PIDs}
+	}
+	if len(s.Capabilities.Add) > 0 || len(s.Capabilities.Drop) > 0 {
+		sb.Capabilities = &v1alpha1.ActionCapabilities{Add: s.Capabilities.Add, Drop: s.Capabilities.Drop}
+	}
+
+	return sb
+}
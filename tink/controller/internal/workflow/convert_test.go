@@ -46,7 +46,11 @@ func TestYAMLToStatus(t *testing.T) {
 									"DEST_DISK":  "/dev/nvme0n1",
 									"IMG_URL":    "http://10.1.1.11:8080/debian-10-openstack-amd64.raw.gz",
 								},
-								Pid: "host",
+								Pid:        "host",
+								Retries:    3,
+								RetryDelay: 10,
+								If:         ` [[ eq .actions.wipe.state "SUCCESS" ]] `,
+								Sandbox:    &ActionSandbox{Resources: ActionResources{Memory: "1Gi"}, ReadOnlyRootFilesystem: true},
 							},
 						},
 					},
@@ -76,7 +80,11 @@ func TestYAMLToStatus(t *testing.T) {
 									"DEST_DISK":  "/dev/nvme0n1",
 									"IMG_URL":    "http://10.1.1.11:8080/debian-10-openstack-amd64.raw.gz",
 								},
-								State: v1alpha1.WorkflowStatePending,
+								State:      v1alpha1.WorkflowStatePending,
+								Retries:    3,
+								RetryDelay: 10,
+								If:         `[[ eq .actions.wipe.state "SUCCESS" ]]`,
+								Sandbox:    &v1alpha1.ActionSandbox{Resources: &v1alpha1.ActionResources{Memory: "1Gi"}, ReadOnlyRootFilesystem: true},
 							},
 						},
 					},
@@ -179,6 +187,49 @@ func TestYAMLToStatus(t *testing.T) {
 				},
 			},
 		},
+		{
+			"Tasks with dependencies",
+			&Workflow{
+				Version:       "1",
+				Name:          "cluster-workflow",
+				ID:            "ghi-789",
+				GlobalTimeout: 300,
+				Tasks: []Task{
+					{
+						Name:       "control-plane-1",
+						WorkerAddr: "00:00:53:00:53:F1",
+						Actions:    []Action{{Name: "kubeadm-init", Image: "example/kubeadm:latest", Timeout: 120}},
+					},
+					{
+						Name:       "control-plane-2",
+						WorkerAddr: "00:00:53:00:53:F2",
+						DependsOn:  []string{"control-plane-1"},
+						Actions:    []Action{{Name: "kubeadm-join", Image: "example/kubeadm:latest", Timeout: 120}},
+					},
+				},
+			},
+			&v1alpha1.WorkflowStatus{
+				GlobalTimeout: 300,
+				AgentID:       "00:00:53:00:53:F1",
+				Tasks: []v1alpha1.Task{
+					{
+						Name:    "control-plane-1",
+						AgentID: "00:00:53:00:53:F1",
+						Actions: []v1alpha1.Action{
+							{Name: "kubeadm-init", Image: "example/kubeadm:latest", Timeout: 120, State: v1alpha1.WorkflowStatePending},
+						},
+					},
+					{
+						Name:      "control-plane-2",
+						AgentID:   "00:00:53:00:53:F2",
+						DependsOn: []string{"control-plane-1"},
+						Actions: []v1alpha1.Action{
+							{Name: "kubeadm-join", Image: "example/kubeadm:latest", Timeout: 120, State: v1alpha1.WorkflowStatePending},
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, tc := range cases {
@@ -0,0 +1,76 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	v1alpha1 "github.com/tinkerbell/tinkerbell/api/v1alpha1/tinkerbell"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// dryRunName is the Template name used when rendering inline Template data.
+const dryRunName = "dry-run"
+
+// ErrRender is returned by DryRun when a Template fails to render or the rendered Template is not valid.
+var ErrRender = errors.New("error rendering template")
+
+// DryRunRequest is a request to render a Template without creating a Workflow.
+type DryRunRequest struct {
+	// Namespace of the Template and Hardware.
+	Namespace string `json:"namespace"`
+	// TemplateRef is the name of a Template object. Mutually exclusive with Template.
+	TemplateRef string `json:"templateRef,omitempty"`
+	// Template is inline Template data. Mutually exclusive with TemplateRef.
+	Template string `json:"template,omitempty"`
+	// HardwareRef is the name of the Hardware object to render the Template with. Optional.
+	HardwareRef string `json:"hardwareRef,omitempty"`
+	// HardwareMap is the same as the Workflow spec.hardwareMap.
+	HardwareMap map[string]string `json:"hardwareMap,omitempty"`
+}
+
+// Validate checks that the request has everything needed to render a Template.
+func (d DryRunRequest) Validate() error {
+	if d.Namespace == "" {
+		return errors.New("namespace is required")
+	}
+	if d.TemplateRef == "" && d.Template == "" {
+		return errors.New("one of templateRef or template is required")
+	}
+	if d.TemplateRef != "" && d.Template != "" {
+		return errors.New("only one of templateRef or template can be set")
+	}
+	return nil
+}
+
+// DryRun renders and validates a Template the same way as when a Workflow is created and returns the resulting Tasks.
+// Render and validation errors are wrapped with ErrRender. Errors getting the Template or Hardware are returned as is.
+func (r *Reconciler) DryRun(ctx context.Context, logger logr.Logger, req DryRunRequest) ([]v1alpha1.Task, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	name, data := dryRunName, req.Template
+	if req.TemplateRef != "" {
+		tpl := &v1alpha1.Template{}
+		if err := r.client.Get(ctx, ctrlclient.ObjectKey{Name: req.TemplateRef, Namespace: req.Namespace}, tpl); err != nil {
+			return nil, fmt.Errorf("error getting template: %w", err)
+		}
+		name, data = tpl.Name, pointerToValue(tpl.Spec.Data)
+	}
+
+	var hardware v1alpha1.Hardware
+	if req.HardwareRef != "" {
+		if err := r.client.Get(ctx, ctrlclient.ObjectKey{Name: req.HardwareRef, Namespace: req.Namespace}, &hardware); err != nil {
+			return nil, fmt.Errorf("error getting hardware: %w", err)
+		}
+	}
+
+	td, refErr := r.templateData(ctx, logger, hardware, req.HardwareMap)
+	wf, err := renderTemplateHardware(name, data, td)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrRender, errors.Join(refErr, err))
+	}
+
+	return YAMLToStatus(wf).Tasks, nil
+}
@@ -0,0 +1,138 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	v1alpha1 "github.com/tinkerbell/tinkerbell/api/v1alpha1/tinkerbell"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDryRun(t *testing.T) {
+	hw := &v1alpha1.Hardware{
+		ObjectMeta: metav1.ObjectMeta{Name: "machine1", Namespace: "default"},
+		Spec: v1alpha1.HardwareSpec{
+			Disks: []v1alpha1.Disk{{Device: "/dev/nvme0n1"}},
+		},
+	}
+	tpl := &v1alpha1.Template{
+		ObjectMeta: metav1.ObjectMeta{Name: "debian", Namespace: "default"},
+		Spec:       v1alpha1.TemplateSpec{Data: &templateWithDiskTemplate},
+	}
+	invalid := `version: "0.1"
+name: debian
+global_timeout: 1800
+tasks: []`
+
+	tests := map[string]struct {
+		req          DryRunRequest
+		wantTasks    []v1alpha1.Task
+		wantRender   bool
+		wantNotFound bool
+		wantErr      bool
+	}{
+		"template ref with hardware": {
+			req: DryRunRequest{Namespace: "default", TemplateRef: "debian", HardwareRef: "machine1", HardwareMap: map[string]string{"device_1": "3c:ec:ef:4c:4f:54"}},
+			wantTasks: []v1alpha1.Task{
+				{
+					Name:    "os-installation",
+					AgentID: "3c:ec:ef:4c:4f:54",
+					Volumes: []string{"/dev:/dev", "/dev/console:/dev/console", "/lib/firmware:/lib/firmware:ro"},
+					Actions: []v1alpha1.Action{
+						{
+							Name:    "stream-debian-image",
+							Image:   "quay.io/tinkerbell-actions/image2disk:v1.0.0",
+							Timeout: 600,
+							Environment: map[string]string{
+								"COMPRESSED": "true",
+								"DEST_DISK":  "/dev/nvme0n1",
+								"IMG_URL":    "http://10.1.1.11:8080/debian-10-openstack-amd64.raw.gz",
+							},
+							State: v1alpha1.WorkflowStatePending,
+						},
+						{
+							Name:    "action to test templating",
+							Image:   "alpine",
+							Timeout: 600,
+							Environment: map[string]string{
+								"USER_DATA":   "",
+								"VENDOR_DATA": "",
+								"METADATA":    "",
+							},
+							State: v1alpha1.WorkflowStatePending,
+						},
+					},
+				},
+			},
+		},
+		"inline template": {
+			req: DryRunRequest{Namespace: "default", Template: minimalTemplate, HardwareMap: map[string]string{"device_1": "3c:ec:ef:4c:4f:54"}},
+			wantTasks: []v1alpha1.Task{
+				{
+					Name:    "os-installation",
+					AgentID: "3c:ec:ef:4c:4f:54",
+					Volumes: []string{"/dev:/dev", "/dev/console:/dev/console", "/lib/firmware:/lib/firmware:ro"},
+					Actions: []v1alpha1.Action{
+						{
+							Name:    "stream-debian-image",
+							Image:   "quay.io/tinkerbell-actions/image2disk:v1.0.0",
+							Timeout: 600,
+							Environment: map[string]string{
+								"COMPRESSED": "true",
+								"DEST_DISK":  "/dev/nvme0n1",
+								"IMG_URL":    "http://10.1.1.11:8080/debian-10-openstack-amd64.raw.gz",
+							},
+							State: v1alpha1.WorkflowStatePending,
+						},
+					},
+				},
+			},
+		},
+		"validation error": {
+			req:        DryRunRequest{Namespace: "default", Template: invalid, HardwareMap: map[string]string{"device_1": "3c:ec:ef:4c:4f:54"}},
+			wantRender: true,
+		},
+		"render error": {
+			req:        DryRunRequest{Namespace: "default", Template: minimalTemplate + "\n{{ .missing.field }}"},
+			wantRender: true,
+		},
+		"template not found": {
+			req:          DryRunRequest{Namespace: "default", TemplateRef: "missing"},
+			wantNotFound: true,
+		},
+		"hardware not found": {
+			req:          DryRunRequest{Namespace: "default", Template: minimalTemplate, HardwareRef: "missing"},
+			wantNotFound: true,
+		},
+		"invalid request": {
+			req:     DryRunRequest{Namespace: "default", TemplateRef: "debian", Template: minimalTemplate},
+			wantErr: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := NewReconciler(GetFakeClientBuilder().WithObjects(hw, tpl).Build(), &fakeDynamicClient{})
+			got, err := r.DryRun(context.Background(), logr.Discard(), tt.req)
+			if errors.Is(err, ErrRender) != tt.wantRender {
+				t.Fatalf("DryRun() error = %v, want render error %v", err, tt.wantRender)
+			}
+			if kerrors.IsNotFound(err) != tt.wantNotFound {
+				t.Fatalf("DryRun() error = %v, want not found %v", err, tt.wantNotFound)
+			}
+			if tt.wantErr && err == nil {
+				t.Fatal("DryRun() expected error, got nil")
+			}
+			if err == nil && (tt.wantRender || tt.wantNotFound) {
+				t.Fatal("DryRun() expected error, got nil")
+			}
+			if diff := cmp.Diff(tt.wantTasks, got, cmpopts.IgnoreFields(v1alpha1.Task{}, "ID"), cmpopts.IgnoreFields(v1alpha1.Action{}, "ID")); diff != "" {
+				t.Errorf("DryRun() tasks mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	v1alpha1 "github.com/tinkerbell/tinkerbell/api/v1alpha1/tinkerbell"
+	corev1 "k8s.io/api/core/v1"
+	toolscache "k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+)
+
+// eventAction is the action set on the Kubernetes Events emitted for state transitions.
+const eventAction = "StateChange"
+
+// transitionKind is the kind of object whose state changed.
+type transitionKind string
+
+const (
+	transitionWorkflow transitionKind = "workflow"
+	transitionAction   transitionKind = "action"
+)
+
+// transition is a change in the state of a Workflow or of one of its Actions.
+type transition struct {
+	Kind     transitionKind
+	TaskName string
+	// ActionName is empty for Workflow transitions.
+	ActionName string
+	AgentID    string
+	From       v1alpha1.WorkflowState
+	To         v1alpha1.WorkflowState
+	Message    string
+}
+
+// EventNotifier emits a Kubernetes Event for every Workflow and Action state transition.
+// When a Webhook is configured, transitions are also posted to it as CloudEvents.
+// State is changed by both the Workflow reconciler and the Tink Server, so transitions are
+// detected from Workflow updates seen by the informer instead of in the reconciler.
+type EventNotifier struct {
+	informers cache.Informers
+	recorder  events.EventRecorder
+	webhook   *Webhook
+	log       logr.Logger
+}
+
+// NewEventNotifier returns an EventNotifier. webhook is optional.
+func NewEventNotifier(informers cache.Informers, recorder events.EventRecorder, webhook *Webhook, log logr.Logger) *EventNotifier {
+	return &EventNotifier{
+		informers: informers,
+		recorder:  recorder,
+		webhook:   webhook,
+		log:       log,
+	}
+}
+
+// Start watches Workflow updates until ctx is done. It implements manager.Runnable.
+// Only the leader emits events, so that each transition is only notified once.
+func (n *EventNotifier) Start(ctx context.Context) error {
+	inf, err := n.informers.GetInformer(ctx, &v1alpha1.Workflow{})
+	if err != nil {
+		return fmt.Errorf("error getting Workflow informer: %w", err)
+	}
+	reg, err := inf.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			o, ok := oldObj.(*v1alpha1.Workflow)
+			if !ok {
+				return
+			}
+			w, ok := newObj.(*v1alpha1.Workflow)
+			if !ok {
+				return
+			}
+			n.notify(ctx, o, w)
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("error adding Workflow event handler: %w", err)
+	}
+	if n.webhook != nil {
+		go n.webhook.run(ctx, n.log)
+	}
+
+	<-ctx.Done()
+	if err := inf.RemoveEventHandler(reg); err != nil {
+		n.log.V(1).Info("error removing Workflow event handler", "error", err)
+	}
+
+	return nil
+}
+
+// notify emits the events for all transitions between two versions of a Workflow.
+func (n *EventNotifier) notify(ctx context.Context, old, updated *v1alpha1.Workflow) {
+	for i, t := range transitions(old, updated) {
+		if n.recorder != nil {
+			n.recorder.Eventf(updated, nil, eventType(t.To), eventReason(t), eventAction, "%s", eventNote(t))
+		}
+		if n.webhook != nil {
+			n.webhook.enqueue(ctx, n.log, updated, i, t)
+		}
+	}
+}
+
+// transitions returns the Workflow and Action state transitions between two versions of a Workflow.
+// Actions that did not have a state before, for example when the Template is first rendered, are not transitions.
+func transitions(old, updated *v1alpha1.Workflow) []transition {
+	var ts []transition
+	if updated.Status.State != "" && old.Status.State != updated.Status.State {
+		t := transition{
+			Kind:    transitionWorkflow,
+			AgentID: updated.Status.AgentID,
+			From:    old.Status.State,
+			To:      updated.Status.State,
+		}
+		if cs := updated.Status.CurrentState; cs != nil {
+			t.TaskName = cs.TaskName
+			t.AgentID = cs.AgentID
+		}
+		ts = append(ts, t)
+	}
+
+	previous := map[string]v1alpha1.WorkflowState{}
+	for _, task := range old.Status.Tasks {
+		for _, a := range task.Actions {
+			previous[task.ID+"/"+a.ID] = a.State
+		}
+	}
+	for _, task := range updated.Status.Tasks {
+		for _, a := range task.Actions {
+			from, ok := previous[task.ID+"/"+a.ID]
+			if !ok || from == "" || from == a.State {
+				continue
+			}
+			ts = append(ts, transition{
+				Kind:       transitionAction,
+				TaskName:   task.Name,
+				ActionName: a.Name,
+				AgentID:    task.AgentID,
+				From:       from,
+				To:         a.State,
+				Message:    a.Message,
+			})
+		}
+	}
+
+	return ts
+}
+
+// eventType returns the Kubernetes Event type for a state.
+func eventType(s v1alpha1.WorkflowState) string {
+	switch s {
+	case v1alpha1.WorkflowStateFailed, v1alpha1.WorkflowStateTimeout, v1alpha1.WorkflowStateCanceled:
+		return corev1.EventTypeWarning
+	default:
+		return corev1.EventTypeNormal
+	}
+}
+
+// eventReason returns the Kubernetes Event reason for a transition. For example, WorkflowRunning or ActionFailed.
+func eventReason(t transition) string {
+	s := strings.ToLower(string(t.To))
+	if s != "" {
+		s = strings.ToUpper(s[:1]) + s[1:]
+	}
+	if t.Kind == transitionAction {
+		return "Action" + s
+	}
+	return "Workflow" + s
+}
+
+// eventNote returns the human readable description of a transition.
+func eventNote(t transition) string {
+	var note string
+	if t.Kind == transitionAction {
+		note = fmt.Sprintf("Action %q in Task %q changed from %s to %s", t.ActionName, t.TaskName, t.From, t.To)
+	} else {
+		from := string(t.From)
+		if from == "" {
+			from = "new"
+		}
+		note = fmt.Sprintf("Workflow changed from %s to %s", from, t.To)
+	}
+	if t.Message != "" {
+		note = fmt.Sprintf("%s: %s", note, t.Message)
+	}
+
+	return note
+}
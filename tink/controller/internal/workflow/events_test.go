@@ -0,0 +1,99 @@
+package workflow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
+	v1alpha1 "github.com/tinkerbell/tinkerbell/api/v1alpha1/tinkerbell"
+	"k8s.io/client-go/tools/events"
+)
+
+func TestTransitions(t *testing.T) {
+	status := func(wf v1alpha1.WorkflowState, actions ...v1alpha1.WorkflowState) v1alpha1.WorkflowStatus {
+		s := v1alpha1.WorkflowStatus{
+			State:   wf,
+			AgentID: "agent1",
+			Tasks:   []v1alpha1.Task{{ID: "task1", Name: "provision", AgentID: "agent1"}},
+		}
+		names := []string{"wipe", "stream"}
+		for i, a := range actions {
+			s.Tasks[0].Actions = append(s.Tasks[0].Actions, v1alpha1.Action{ID: names[i], Name: names[i], State: a})
+		}
+		return s
+	}
+	tests := map[string]struct {
+		old     v1alpha1.WorkflowStatus
+		updated v1alpha1.WorkflowStatus
+		want    []transition
+	}{
+		"no change": {
+			old:     status(v1alpha1.WorkflowStateRunning, v1alpha1.WorkflowStateRunning),
+			updated: status(v1alpha1.WorkflowStateRunning, v1alpha1.WorkflowStateRunning),
+		},
+		"template rendered": {
+			old:     v1alpha1.WorkflowStatus{},
+			updated: status(v1alpha1.WorkflowStatePending, v1alpha1.WorkflowStatePending, v1alpha1.WorkflowStatePending),
+			want: []transition{
+				{Kind: transitionWorkflow, AgentID: "agent1", To: v1alpha1.WorkflowStatePending},
+			},
+		},
+		"workflow started": {
+			old:     status(v1alpha1.WorkflowStatePending, v1alpha1.WorkflowStatePending),
+			updated: status(v1alpha1.WorkflowStateRunning, v1alpha1.WorkflowStateRunning),
+			want: []transition{
+				{Kind: transitionWorkflow, AgentID: "agent1", From: v1alpha1.WorkflowStatePending, To: v1alpha1.WorkflowStateRunning},
+				{Kind: transitionAction, TaskName: "provision", ActionName: "wipe", AgentID: "agent1", From: v1alpha1.WorkflowStatePending, To: v1alpha1.WorkflowStateRunning},
+			},
+		},
+		"action failed": {
+			old: status(v1alpha1.WorkflowStateRunning, v1alpha1.WorkflowStateSuccess, v1alpha1.WorkflowStateRunning),
+			updated: func() v1alpha1.WorkflowStatus {
+				s := status(v1alpha1.WorkflowStateFailed, v1alpha1.WorkflowStateSuccess, v1alpha1.WorkflowStateFailed)
+				s.Tasks[0].Actions[1].Message = "exit code 1"
+				s.CurrentState = &v1alpha1.CurrentState{AgentID: "agent1", TaskName: "provision", ActionName: "stream"}
+				return s
+			}(),
+			want: []transition{
+				{Kind: transitionWorkflow, TaskName: "provision", AgentID: "agent1", From: v1alpha1.WorkflowStateRunning, To: v1alpha1.WorkflowStateFailed},
+				{Kind: transitionAction, TaskName: "provision", ActionName: "stream", AgentID: "agent1", From: v1alpha1.WorkflowStateRunning, To: v1alpha1.WorkflowStateFailed, Message: "exit code 1"},
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := transitions(&v1alpha1.Workflow{Status: tt.old}, &v1alpha1.Workflow{Status: tt.updated})
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("transitions() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNotify(t *testing.T) {
+	old := &v1alpha1.Workflow{Status: v1alpha1.WorkflowStatus{
+		State: v1alpha1.WorkflowStateRunning,
+		Tasks: []v1alpha1.Task{{ID: "task1", Name: "provision", Actions: []v1alpha1.Action{{ID: "a1", Name: "stream", State: v1alpha1.WorkflowStateRunning}}}},
+	}}
+	updated := old.DeepCopy()
+	updated.Status.State = v1alpha1.WorkflowStateTimeout
+	updated.Status.Tasks[0].Actions[0].State = v1alpha1.WorkflowStateTimeout
+
+	recorder := events.NewFakeRecorder(10)
+	n := NewEventNotifier(nil, recorder, nil, logr.Discard())
+	n.notify(context.Background(), old, updated)
+	close(recorder.Events)
+
+	var got []string
+	for e := range recorder.Events {
+		got = append(got, e)
+	}
+	want := []string{
+		"Warning WorkflowTimeout Workflow changed from RUNNING to TIMEOUT",
+		`Warning ActionTimeout Action "stream" in Task "provision" changed from RUNNING to TIMEOUT`,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected events (-want +got):\n%s", diff)
+	}
+}
@@ -27,9 +27,10 @@ type source struct {
 	Namespace string `json:"namespace,omitempty"`
 }
 
-// evaluate checks if the data matches any rules defined.
+// Evaluate checks if the data matches any rules defined.
+// The data is marshalled to JSON and used as the Quamina event.
 // It returns a boolean indicating if at least one rule was matched, the rule that matched for the decision, and an error if any occurred.
-func evaluate(_ context.Context, rules []string, data evaluationData) (bool, string, error) {
+func Evaluate(_ context.Context, rules []string, data interface{}) (bool, string, error) {
 	q, err := quamina.New()
 	if err != nil {
 		return false, "", fmt.Errorf("error creating rule evaluation engine: %w", err)
@@ -40,7 +41,7 @@ func evaluate(_ context.Context, rules []string, data evaluationData) (bool, str
 		}
 	}
 
-	jsonEvent, err := json.Marshal(&data)
+	jsonEvent, err := json.Marshal(data)
 	if err != nil {
 		return false, "", fmt.Errorf("error while marshalling data: %w", err)
 	}
@@ -125,7 +125,7 @@ func TestMatch(t *testing.T) {
 
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
-			got, rules, err := evaluate(context.TODO(), test.rules, test.data)
+			got, rules, err := Evaluate(context.TODO(), test.rules, test.data)
 			if err != nil && !test.expectedErr {
 				t.Fatalf("match() error = %v", err)
 			}
@@ -0,0 +1,170 @@
+package workflow
+
+import (
+	"fmt"
+	"time"
+
+	v1alpha1 "github.com/tinkerbell/tinkerbell/api/v1alpha1/tinkerbell"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// isFinalState reports whether a Workflow in the given state will not run any more Actions.
+func isFinalState(s v1alpha1.WorkflowState) bool {
+	switch s {
+	case v1alpha1.WorkflowStateSuccess, v1alpha1.WorkflowStateFailed, v1alpha1.WorkflowStateTimeout, v1alpha1.WorkflowStateCanceled:
+		return true
+	default:
+		return false
+	}
+}
+
+// cancelWorkflow moves a Workflow that has not reached a final state to the CANCELED state.
+// tink-server tells the Agent running the current Action to stop it.
+// It returns false when the Workflow is already in a final state.
+func cancelWorkflow(wf *v1alpha1.Workflow) bool {
+	if isFinalState(wf.Status.State) {
+		return false
+	}
+	wf.Status.State = v1alpha1.WorkflowStateCanceled
+	if wf.Status.CurrentState != nil {
+		wf.Status.CurrentState.State = v1alpha1.WorkflowStateCanceled
+	}
+	wf.Status.SetCondition(v1alpha1.WorkflowCondition{
+		Type:    v1alpha1.WorkflowCanceled,
+		Status:  metav1.ConditionTrue,
+		Reason:  "Canceled",
+		Message: "workflow canceled",
+		Time:    &metav1.Time{Time: metav1.Now().UTC()},
+	})
+
+	return true
+}
+
+// shouldResume reports whether the Workflow has a resume request that has not been applied yet.
+func shouldResume(wf *v1alpha1.Workflow) bool {
+	if wf.Spec.Resume == nil || wf.Spec.Cancel || !isFinalState(wf.Status.State) {
+		return false
+	}
+	return wf.Status.LastResume == nil || *wf.Status.LastResume != *wf.Spec.Resume
+}
+
+// resumeWorkflow resets the Action named in spec.resume, and all Actions after it, to PENDING
+// and moves the Workflow back to the RUNNING state.
+// The result is recorded in the WorkflowResumed condition.
+func resumeWorkflow(wf *v1alpha1.Workflow, now time.Time) {
+	rs := wf.Spec.Resume
+	wf.Status.LastResume = rs.DeepCopy()
+
+	ti, ai, ok := findAction(wf.Status.Tasks, rs.TaskName, rs.ActionName)
+	if !ok {
+		wf.Status.SetCondition(v1alpha1.WorkflowCondition{
+			Type:    v1alpha1.WorkflowResumed,
+			Status:  metav1.ConditionFalse,
+			Reason:  reasonError,
+			Message: fmt.Sprintf("action not found: task=%q action=%q", rs.TaskName, rs.ActionName),
+			Time:    &metav1.Time{Time: metav1.Now().UTC()},
+		})
+		return
+	}
+
+	for i := ti; i < len(wf.Status.Tasks); i++ {
+		start := 0
+		if i == ti {
+			start = ai
+		}
+		for j := start; j < len(wf.Status.Tasks[i].Actions); j++ {
+			resetAction(&wf.Status.Tasks[i].Actions[j])
+		}
+	}
+
+	task := wf.Status.Tasks[ti]
+	action := task.Actions[ai]
+	// A pending current state makes tink-server serve this Action next.
+	wf.Status.CurrentState = &v1alpha1.CurrentState{
+		AgentID:    task.AgentID,
+		TaskID:     task.ID,
+		ActionID:   action.ID,
+		State:      v1alpha1.WorkflowStatePending,
+		ActionName: action.Name,
+		TaskName:   task.Name,
+	}
+	wf.Status.AgentID = task.AgentID
+	wf.Status.State = v1alpha1.WorkflowStateRunning
+	wf.Status.GlobalExecutionStop = &metav1.Time{Time: now.Add(time.Duration(wf.Status.GlobalTimeout) * time.Second)}
+	wf.Status.SetCondition(v1alpha1.WorkflowCondition{
+		Type:    v1alpha1.WorkflowResumed,
+		Status:  metav1.ConditionTrue,
+		Reason:  "Resumed",
+		Message: fmt.Sprintf("resumed from action %q in task %q", action.Name, task.Name),
+		Time:    &metav1.Time{Time: metav1.Now().UTC()},
+	})
+}
+
+// findAction returns the indexes of the Task and Action with the given names.
+// When taskName is empty the first Task with a matching Action is used.
+func findAction(tasks []v1alpha1.Task, taskName, actionName string) (int, int, bool) {
+	for ti, t := range tasks {
+		if taskName != "" && t.Name != taskName {
+			continue
+		}
+		for ai, a := range t.Actions {
+			if a.Name == actionName {
+				return ti, ai, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// resetAction clears the results of any previous run of an Action.
+func resetAction(a *v1alpha1.Action) {
+	a.State = v1alpha1.WorkflowStatePending
+	a.ExecutionStart = nil
+	a.ExecutionStop = nil
+	a.ExecutionDuration = ""
+	a.Message = ""
+	a.Hook = nil
+	a.Attempt = 0
+	a.Outputs = nil
+}
+
+// agentLost reports whether the Agent running wf has not sent a heartbeat, recorded in the status of hw, for longer than timeout.
+// When it has not, it returns how long until it does. Agents that have not sent a heartbeat since wf was created,
+// for example Agents that do not send heartbeats, are never lost.
+func agentLost(wf *v1alpha1.Workflow, hw *v1alpha1.Hardware, now time.Time, timeout time.Duration) (bool, time.Duration) {
+	a := hw.Status.Agent
+	if timeout <= 0 || a == nil || a.LastSeen == nil || a.ID != wf.Status.AgentID || !a.LastSeen.After(wf.CreationTimestamp.Time) {
+		return false, 0
+	}
+	silence := now.Sub(a.LastSeen.Time)
+	if silence > timeout {
+		return true, 0
+	}
+
+	return false, timeout - silence
+}
+
+// failAgentLost moves a Workflow whose Agent is lost, and its current Action, to the FAILED state.
+// The reason is recorded, at now, in the AgentLost condition.
+func failAgentLost(wf *v1alpha1.Workflow, agent *v1alpha1.AgentStatus, now time.Time) {
+	msg := fmt.Sprintf("agent %q not seen since %v", agent.ID, agent.LastSeen.UTC().Format(time.RFC3339))
+	wf.Status.State = v1alpha1.WorkflowStateFailed
+	if cs := wf.Status.CurrentState; cs != nil {
+		cs.State = v1alpha1.WorkflowStateFailed
+		for ti := range wf.Status.Tasks {
+			for ai := range wf.Status.Tasks[ti].Actions {
+				if a := &wf.Status.Tasks[ti].Actions[ai]; wf.Status.Tasks[ti].ID == cs.TaskID && a.ID == cs.ActionID {
+					a.State = v1alpha1.WorkflowStateFailed
+					a.Message = msg
+				}
+			}
+		}
+	}
+	wf.Status.SetCondition(v1alpha1.WorkflowCondition{
+		Type:    v1alpha1.AgentLost,
+		Status:  metav1.ConditionTrue,
+		Reason:  "AgentLost",
+		Message: msg,
+		Time:    &metav1.Time{Time: now.UTC()},
+	})
+}
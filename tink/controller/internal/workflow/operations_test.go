@@ -0,0 +1,273 @@
+package workflow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	v1alpha1 "github.com/tinkerbell/tinkerbell/api/v1alpha1/tinkerbell"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCancelWorkflow(t *testing.T) {
+	tests := map[string]struct {
+		state        v1alpha1.WorkflowState
+		wantCanceled bool
+		wantState    v1alpha1.WorkflowState
+	}{
+		"running":  {state: v1alpha1.WorkflowStateRunning, wantCanceled: true, wantState: v1alpha1.WorkflowStateCanceled},
+		"pending":  {state: v1alpha1.WorkflowStatePending, wantCanceled: true, wantState: v1alpha1.WorkflowStateCanceled},
+		"new":      {state: "", wantCanceled: true, wantState: v1alpha1.WorkflowStateCanceled},
+		"success":  {state: v1alpha1.WorkflowStateSuccess, wantState: v1alpha1.WorkflowStateSuccess},
+		"failed":   {state: v1alpha1.WorkflowStateFailed, wantState: v1alpha1.WorkflowStateFailed},
+		"canceled": {state: v1alpha1.WorkflowStateCanceled, wantState: v1alpha1.WorkflowStateCanceled},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			wf := &v1alpha1.Workflow{
+				Spec:   v1alpha1.WorkflowSpec{Cancel: true},
+				Status: v1alpha1.WorkflowStatus{State: tc.state},
+			}
+			if got := cancelWorkflow(wf); got != tc.wantCanceled {
+				t.Errorf("cancelWorkflow() = %v, want %v", got, tc.wantCanceled)
+			}
+			if wf.Status.State != tc.wantState {
+				t.Errorf("state = %v, want %v", wf.Status.State, tc.wantState)
+			}
+		})
+	}
+}
+
+func TestShouldResume(t *testing.T) {
+	resume := &v1alpha1.WorkflowResume{ActionName: "stream"}
+	tests := map[string]struct {
+		spec   v1alpha1.WorkflowSpec
+		status v1alpha1.WorkflowStatus
+		want   bool
+	}{
+		"no resume": {
+			status: v1alpha1.WorkflowStatus{State: v1alpha1.WorkflowStateFailed},
+		},
+		"failed workflow": {
+			spec:   v1alpha1.WorkflowSpec{Resume: resume},
+			status: v1alpha1.WorkflowStatus{State: v1alpha1.WorkflowStateFailed},
+			want:   true,
+		},
+		"running workflow": {
+			spec:   v1alpha1.WorkflowSpec{Resume: resume},
+			status: v1alpha1.WorkflowStatus{State: v1alpha1.WorkflowStateRunning},
+		},
+		"canceled spec": {
+			spec:   v1alpha1.WorkflowSpec{Resume: resume, Cancel: true},
+			status: v1alpha1.WorkflowStatus{State: v1alpha1.WorkflowStateCanceled},
+		},
+		"already applied": {
+			spec:   v1alpha1.WorkflowSpec{Resume: resume},
+			status: v1alpha1.WorkflowStatus{State: v1alpha1.WorkflowStateFailed, LastResume: &v1alpha1.WorkflowResume{ActionName: "stream"}},
+		},
+		"new resume id": {
+			spec:   v1alpha1.WorkflowSpec{Resume: &v1alpha1.WorkflowResume{ActionName: "stream", ID: "2"}},
+			status: v1alpha1.WorkflowStatus{State: v1alpha1.WorkflowStateSuccess, LastResume: &v1alpha1.WorkflowResume{ActionName: "stream", ID: "1"}},
+			want:   true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			wf := &v1alpha1.Workflow{Spec: tc.spec, Status: tc.status}
+			if got := shouldResume(wf); got != tc.want {
+				t.Errorf("shouldResume() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestResumeWorkflow(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	start := metav1.NewTime(now.Add(-time.Hour))
+	failedStatus := func() v1alpha1.WorkflowStatus {
+		return v1alpha1.WorkflowStatus{
+			State:         v1alpha1.WorkflowStateFailed,
+			AgentID:       "agent2",
+			GlobalTimeout: 600,
+			Tasks: []v1alpha1.Task{
+				{
+					ID: "task1", Name: "prepare", AgentID: "agent1",
+					Actions: []v1alpha1.Action{
+						{ID: "a1", Name: "wipe", State: v1alpha1.WorkflowStateSuccess, ExecutionStart: &start},
+						{ID: "a2", Name: "stream", State: v1alpha1.WorkflowStateFailed, ExecutionStart: &start, Message: "boom", Attempt: 3, Outputs: map[string]string{"device": "/dev/sda"}},
+						{ID: "a3", Name: "kexec", State: v1alpha1.WorkflowStatePending},
+					},
+				},
+				{
+					ID: "task2", Name: "finish", AgentID: "agent2",
+					Actions: []v1alpha1.Action{
+						{ID: "b1", Name: "stream", State: v1alpha1.WorkflowStateSuccess, ExecutionStart: &start},
+					},
+				},
+			},
+			CurrentState: &v1alpha1.CurrentState{AgentID: "agent1", TaskID: "task1", ActionID: "a2", State: v1alpha1.WorkflowStateFailed},
+		}
+	}
+
+	tests := map[string]struct {
+		resume        v1alpha1.WorkflowResume
+		want          v1alpha1.WorkflowStatus
+		wantCondition v1alpha1.WorkflowCondition
+	}{
+		"resume from failed action": {
+			resume: v1alpha1.WorkflowResume{ActionName: "stream"},
+			want: v1alpha1.WorkflowStatus{
+				State:               v1alpha1.WorkflowStateRunning,
+				AgentID:             "agent1",
+				GlobalTimeout:       600,
+				GlobalExecutionStop: &metav1.Time{Time: now.Add(600 * time.Second)},
+				LastResume:          &v1alpha1.WorkflowResume{ActionName: "stream"},
+				Tasks: []v1alpha1.Task{
+					{
+						ID: "task1", Name: "prepare", AgentID: "agent1",
+						Actions: []v1alpha1.Action{
+							{ID: "a1", Name: "wipe", State: v1alpha1.WorkflowStateSuccess, ExecutionStart: &start},
+							{ID: "a2", Name: "stream", State: v1alpha1.WorkflowStatePending},
+							{ID: "a3", Name: "kexec", State: v1alpha1.WorkflowStatePending},
+						},
+					},
+					{
+						ID: "task2", Name: "finish", AgentID: "agent2",
+						Actions: []v1alpha1.Action{
+							{ID: "b1", Name: "stream", State: v1alpha1.WorkflowStatePending},
+						},
+					},
+				},
+				CurrentState: &v1alpha1.CurrentState{
+					AgentID: "agent1", TaskID: "task1", ActionID: "a2", ActionName: "stream", TaskName: "prepare", State: v1alpha1.WorkflowStatePending,
+				},
+			},
+			wantCondition: v1alpha1.WorkflowCondition{Type: v1alpha1.WorkflowResumed, Status: metav1.ConditionTrue, Reason: "Resumed"},
+		},
+		"resume from action in named task": {
+			resume: v1alpha1.WorkflowResume{TaskName: "finish", ActionName: "stream"},
+			want: func() v1alpha1.WorkflowStatus {
+				s := failedStatus()
+				s.State = v1alpha1.WorkflowStateRunning
+				s.GlobalExecutionStop = &metav1.Time{Time: now.Add(600 * time.Second)}
+				s.LastResume = &v1alpha1.WorkflowResume{TaskName: "finish", ActionName: "stream"}
+				s.Tasks[1].Actions[0] = v1alpha1.Action{ID: "b1", Name: "stream", State: v1alpha1.WorkflowStatePending}
+				s.CurrentState = &v1alpha1.CurrentState{
+					AgentID: "agent2", TaskID: "task2", ActionID: "b1", ActionName: "stream", TaskName: "finish", State: v1alpha1.WorkflowStatePending,
+				}
+				return s
+			}(),
+			wantCondition: v1alpha1.WorkflowCondition{Type: v1alpha1.WorkflowResumed, Status: metav1.ConditionTrue, Reason: "Resumed"},
+		},
+		"action not found": {
+			resume: v1alpha1.WorkflowResume{ActionName: "missing"},
+			want: func() v1alpha1.WorkflowStatus {
+				s := failedStatus()
+				s.LastResume = &v1alpha1.WorkflowResume{ActionName: "missing"}
+				return s
+			}(),
+			wantCondition: v1alpha1.WorkflowCondition{Type: v1alpha1.WorkflowResumed, Status: metav1.ConditionFalse, Reason: reasonError},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			wf := &v1alpha1.Workflow{
+				Spec:   v1alpha1.WorkflowSpec{Resume: &tc.resume},
+				Status: failedStatus(),
+			}
+			resumeWorkflow(wf, now)
+
+			if len(wf.Status.Conditions) != 1 {
+				t.Fatalf("expected 1 condition, got %d", len(wf.Status.Conditions))
+			}
+			if diff := cmp.Diff(tc.wantCondition, wf.Status.Conditions[0], cmpopts.IgnoreFields(v1alpha1.WorkflowCondition{}, "Message", "Time")); diff != "" {
+				t.Errorf("unexpected condition (-want +got):\n%s", diff)
+			}
+			wf.Status.Conditions = nil
+			if diff := cmp.Diff(tc.want, wf.Status); diff != "" {
+				t.Errorf("unexpected status (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestAgentLost(t *testing.T) {
+	created := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	now := created.Add(time.Hour)
+	seen := func(id string, t time.Time) *v1alpha1.AgentStatus {
+		return &v1alpha1.AgentStatus{ID: id, LastSeen: &metav1.Time{Time: t}}
+	}
+	tests := map[string]struct {
+		agent         *v1alpha1.AgentStatus
+		timeout       time.Duration
+		wantLost      bool
+		wantRemaining time.Duration
+	}{
+		"silent too long":               {agent: seen("agent1", now.Add(-10*time.Minute)), timeout: 5 * time.Minute, wantLost: true},
+		"recently seen":                 {agent: seen("agent1", now.Add(-2*time.Minute)), timeout: 5 * time.Minute, wantRemaining: 3 * time.Minute},
+		"disabled":                      {agent: seen("agent1", now.Add(-10*time.Minute))},
+		"no heartbeats":                 {timeout: 5 * time.Minute},
+		"seen before workflow created":  {agent: seen("agent1", created.Add(-time.Minute)), timeout: 5 * time.Minute},
+		"heartbeats from another agent": {agent: seen("agent2", now.Add(-10*time.Minute)), timeout: 5 * time.Minute},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			wf := &v1alpha1.Workflow{
+				ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.Time{Time: created}},
+				Status:     v1alpha1.WorkflowStatus{State: v1alpha1.WorkflowStateRunning, AgentID: "agent1"},
+			}
+			hw := &v1alpha1.Hardware{Status: v1alpha1.HardwareStatus{Agent: tc.agent}}
+			lost, remaining := agentLost(wf, hw, now, tc.timeout)
+			if lost != tc.wantLost {
+				t.Errorf("agentLost() lost = %v, want %v", lost, tc.wantLost)
+			}
+			if remaining != tc.wantRemaining {
+				t.Errorf("agentLost() remaining = %v, want %v", remaining, tc.wantRemaining)
+			}
+		})
+	}
+}
+
+func TestFailAgentLost(t *testing.T) {
+	wf := &v1alpha1.Workflow{
+		Status: v1alpha1.WorkflowStatus{
+			State:        v1alpha1.WorkflowStateRunning,
+			AgentID:      "agent1",
+			CurrentState: &v1alpha1.CurrentState{TaskID: "task1", ActionID: "action2", State: v1alpha1.WorkflowStateRunning},
+			Tasks: []v1alpha1.Task{{
+				ID: "task1",
+				Actions: []v1alpha1.Action{
+					{ID: "action1", State: v1alpha1.WorkflowStateSuccess},
+					{ID: "action2", State: v1alpha1.WorkflowStateRunning},
+				},
+			}},
+		},
+	}
+	agent := &v1alpha1.AgentStatus{ID: "agent1", LastSeen: &metav1.Time{Time: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)}}
+	now := time.Date(2025, 1, 1, 12, 10, 0, 0, time.UTC)
+	failAgentLost(wf, agent, now)
+
+	if wf.Status.State != v1alpha1.WorkflowStateFailed || wf.Status.CurrentState.State != v1alpha1.WorkflowStateFailed {
+		t.Errorf("expected the workflow and current state to be failed, got %v and %v", wf.Status.State, wf.Status.CurrentState.State)
+	}
+	wantStates := []v1alpha1.WorkflowState{v1alpha1.WorkflowStateSuccess, v1alpha1.WorkflowStateFailed}
+	gotStates := []v1alpha1.WorkflowState{wf.Status.Tasks[0].Actions[0].State, wf.Status.Tasks[0].Actions[1].State}
+	if diff := cmp.Diff(wantStates, gotStates); diff != "" {
+		t.Errorf("unexpected action states (-want +got):\n%s", diff)
+	}
+	want := []v1alpha1.WorkflowCondition{{
+		Type:    v1alpha1.AgentLost,
+		Status:  metav1.ConditionTrue,
+		Reason:  "AgentLost",
+		Message: `agent "agent1" not seen since 2025-01-01T12:00:00Z`,
+		Time:    &metav1.Time{Time: now},
+	}}
+	if diff := cmp.Diff(want, wf.Status.Conditions); diff != "" {
+		t.Errorf("unexpected conditions (-want +got):\n%s", diff)
+	}
+}
@@ -242,7 +242,7 @@ func templateActions(actions []bmc.Action, hw *v1alpha1.Hardware) ([]bmc.Action,
 
 // templateString executes a Go template string with the provided data.
 func templateString(tmplStr string, data templateData) (string, error) {
-	rendered, err := renderTemplate("action", tmplStr, data)
+	rendered, err := RenderTemplate("action", tmplStr, data)
 	if err != nil {
 		return "", err
 	}
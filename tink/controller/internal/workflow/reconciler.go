@@ -51,6 +51,8 @@ type Reconciler struct {
 	backoff        *backoff.ExponentialBackOff
 	dynamicClient  dynamicClient
 	referenceRules ReferenceRules
+	// agentLostTimeout is how long the Agent of a running Workflow can go without a heartbeat before the Workflow fails.
+	agentLostTimeout time.Duration
 }
 
 type ReferenceRules struct {
@@ -74,6 +76,14 @@ func WithDenyReferenceRules(denylist []string) Option {
 	}
 }
 
+// WithAgentLostTimeout sets how long the Agent of a running Workflow can go without a heartbeat before the Workflow
+// is marked failed. Zero disables the check.
+func WithAgentLostTimeout(d time.Duration) Option {
+	return func(r *Reconciler) {
+		r.agentLostTimeout = d
+	}
+}
+
 // TODO(jacobweinstock): add functional arguments to the signature.
 // TODO(jacobweinstock): write functional argument for customizing the backoff.
 func NewReconciler(client ctrlclient.Client, dc dynamicClient, opts ...Option) *Reconciler {
@@ -115,6 +125,7 @@ type state struct {
 // +kubebuilder:rbac:groups=tinkerbell.org,resources=templates;templates/status,verbs=get;list;watch;update;patch
 // +kubebuilder:rbac:groups=tinkerbell.org,resources=workflows;workflows/status,verbs=get;list;watch;update;patch;delete
 // +kubebuilder:rbac:groups=bmc.tinkerbell.org,resources=job;job/status,verbs=get;list;watch;delete;create
+// +kubebuilder:rbac:groups="";events.k8s.io,resources=events,verbs=create;patch;update
 
 // Reconcile handles Workflow objects. This includes Template rendering, optional Hardware allowPXE toggling, and optional Hardware one-time netbooting.
 func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
@@ -143,6 +154,16 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 
 	wflow := stored.DeepCopy()
 
+	if wflow.Spec.Cancel && cancelWorkflow(wflow) {
+		journal.Log(ctx, "workflow canceled")
+		return reconcile.Result{}, mergePatchStatus(ctx, r.client, stored, wflow)
+	}
+	if shouldResume(wflow) {
+		journal.Log(ctx, "resuming workflow", "task", wflow.Spec.Resume.TaskName, "action", wflow.Spec.Resume.ActionName)
+		resumeWorkflow(wflow, r.nowFunc())
+		return reconcile.Result{}, mergePatchStatus(ctx, r.client, stored, wflow)
+	}
+
 	switch wflow.Status.State {
 	case "":
 		journal.Log(ctx, "new workflow")
@@ -177,6 +198,24 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 			return reconcile.Result{}, mergePatchStatus(ctx, r.client, stored, wflow)
 		}
 
+		// Check if the Agent stopped sending heartbeats.
+		var recheck time.Duration
+		if r.agentLostTimeout > 0 && wflow.Spec.HardwareRef != "" {
+			hw := &v1alpha1.Hardware{}
+			if err := r.client.Get(ctx, ctrlclient.ObjectKey{Name: wflow.Spec.HardwareRef, Namespace: wflow.Namespace}, hw); err != nil {
+				journal.Log(ctx, "unable to check agent heartbeats", "error", err)
+			} else {
+				now := r.nowFunc()
+				lost, remaining := agentLost(wflow, hw, now, r.agentLostTimeout)
+				if lost {
+					journal.Log(ctx, "agent lost", "agentID", hw.Status.Agent.ID, "lastSeen", hw.Status.Agent.LastSeen)
+					failAgentLost(wflow, hw.Status.Agent, now)
+					return reconcile.Result{}, mergePatchStatus(ctx, r.client, stored, wflow)
+				}
+				recheck = remaining
+			}
+		}
+
 		// Update AgentID if transitioning between tasks
 		if updateAgentIDIfNeeded(wflow) {
 			journal.Log(ctx, "updated workflow AgentID for task transition", "newAgentID", wflow.Status.AgentID)
@@ -185,7 +224,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		first := firstAction(wflow)
 		if wflow.Status.GlobalExecutionStop == nil && first != nil && wflow.Status.CurrentState != nil && first.ID == wflow.Status.CurrentState.ActionID {
 			if first.ExecutionStart == nil {
-				return reconcile.Result{}, nil
+				return reconcile.Result{RequeueAfter: recheck}, nil
 			}
 			now := r.nowFunc()
 			var skew time.Duration
@@ -196,10 +235,14 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 				Time: now.Add(time.Duration(wflow.Status.GlobalTimeout) * time.Second).Add(skew),
 			}
 			journal.Log(ctx, "global execution times set")
-			return reconcile.Result{RequeueAfter: time.Until(wflow.Status.GlobalExecutionStop.Time)}, mergePatchStatus(ctx, r.client, stored, wflow)
+			requeue := time.Until(wflow.Status.GlobalExecutionStop.Time)
+			if recheck > 0 {
+				requeue = min(requeue, recheck)
+			}
+			return reconcile.Result{RequeueAfter: requeue}, mergePatchStatus(ctx, r.client, stored, wflow)
 		}
 
-		return reconcile.Result{}, mergePatchStatus(ctx, r.client, stored, wflow)
+		return reconcile.Result{RequeueAfter: recheck}, mergePatchStatus(ctx, r.client, stored, wflow)
 	case v1alpha1.WorkflowStatePost:
 		journal.Log(ctx, "post actions")
 		s := &state{
@@ -210,7 +253,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		rc, err := s.postActions(ctx)
 
 		return rc, errors.Join(err, mergePatchStatus(ctx, r.client, stored, wflow))
-	case v1alpha1.WorkflowStatePending, v1alpha1.WorkflowStateTimeout, v1alpha1.WorkflowStateFailed, v1alpha1.WorkflowStateSuccess:
+	case v1alpha1.WorkflowStatePending, v1alpha1.WorkflowStateTimeout, v1alpha1.WorkflowStateFailed, v1alpha1.WorkflowStateSuccess, v1alpha1.WorkflowStateCanceled:
 		journal.Log(ctx, "controller will not trigger another reconcile", "state", wflow.Status.State)
 
 		return reconcile.Result{}, nil
@@ -304,8 +347,42 @@ func (r *Reconciler) processWorkflow(ctx context.Context, logger logr.Logger, st
 		)
 	}
 
+	data, refErr := r.templateData(ctx, logger, hardware, stored.Spec.HardwareMap)
+
+	tinkWf, err := renderTemplateHardware(stored.Name, pointerToValue(tpl.Spec.Data), data)
+	if err != nil {
+		journal.Log(ctx, "error rendering template")
+		stored.Status.TemplateRendering = v1alpha1.TemplateRenderingFailed
+		stored.Status.SetConditionIfDifferent(v1alpha1.WorkflowCondition{
+			Type:    v1alpha1.TemplateRenderedSuccess,
+			Status:  metav1.ConditionFalse,
+			Reason:  reasonError,
+			Message: fmt.Sprintf("error rendering template: %v", errors.Join(refErr, err)),
+			Time:    &metav1.Time{Time: metav1.Now().UTC()},
+		})
+
+		return err
+	}
+
+	// populate Task and Action data
+	stored.Status = *YAMLToStatus(tinkWf)
+	stored.Status.TemplateRendering = v1alpha1.TemplateRenderingSuccessful
+	stored.Status.SetCondition(v1alpha1.WorkflowCondition{
+		Type:    v1alpha1.TemplateRenderedSuccess,
+		Status:  metav1.ConditionTrue,
+		Reason:  "Complete",
+		Message: "template rendered successfully",
+		Time:    &metav1.Time{Time: metav1.Now().UTC()},
+	})
+
+	return nil
+}
+
+// templateData returns the data used to render a Template for a Hardware.
+// The returned error holds any errors getting Hardware references. References that could not be read are not in the data.
+func (r *Reconciler) templateData(ctx context.Context, logger logr.Logger, hardware v1alpha1.Hardware, hardwareMap map[string]string) (map[string]interface{}, error) {
 	data := make(map[string]interface{})
-	for key, val := range stored.Spec.HardwareMap {
+	for key, val := range hardwareMap {
 		data[key] = val
 	}
 	contract := toTemplateHardwareData(hardware)
@@ -331,13 +408,13 @@ func (r *Reconciler) processWorkflow(ctx context.Context, logger logr.Logger, st
 			},
 			Reference: rf,
 		}
-		denied, drules, err := evaluate(ctx, r.referenceRules.Denylist, ed)
+		denied, drules, err := Evaluate(ctx, r.referenceRules.Denylist, ed)
 		if err != nil {
 			refErr = errors.Join(refErr, err)
 			logger.V(1).Info("error applying denylist rules", "error", err, "denyRules", r.referenceRules.Denylist)
 			continue
 		}
-		allowed, arules, err := evaluate(ctx, r.referenceRules.Allowlist, ed)
+		allowed, arules, err := Evaluate(ctx, r.referenceRules.Allowlist, ed)
 		if err != nil {
 			refErr = errors.Join(refErr, err)
 			logger.V(1).Info("error applying allowlist rules", "error", err, "allowRules", r.referenceRules.Allowlist)
@@ -359,33 +436,7 @@ func (r *Reconciler) processWorkflow(ctx context.Context, logger logr.Logger, st
 	}
 	data[templateDataReferences] = references
 
-	tinkWf, err := renderTemplateHardware(stored.Name, pointerToValue(tpl.Spec.Data), data)
-	if err != nil {
-		journal.Log(ctx, "error rendering template")
-		stored.Status.TemplateRendering = v1alpha1.TemplateRenderingFailed
-		stored.Status.SetConditionIfDifferent(v1alpha1.WorkflowCondition{
-			Type:    v1alpha1.TemplateRenderedSuccess,
-			Status:  metav1.ConditionFalse,
-			Reason:  reasonError,
-			Message: fmt.Sprintf("error rendering template: %v", errors.Join(refErr, err)),
-			Time:    &metav1.Time{Time: metav1.Now().UTC()},
-		})
-
-		return err
-	}
-
-	// populate Task and Action data
-	stored.Status = *YAMLToStatus(tinkWf)
-	stored.Status.TemplateRendering = v1alpha1.TemplateRenderingSuccessful
-	stored.Status.SetCondition(v1alpha1.WorkflowCondition{
-		Type:    v1alpha1.TemplateRenderedSuccess,
-		Status:  metav1.ConditionTrue,
-		Reason:  "Complete",
-		Message: "template rendered successfully",
-		Time:    &metav1.Time{Time: metav1.Now().UTC()},
-	})
-
-	return nil
+	return data, refErr
 }
 
 func (r *Reconciler) processNewWorkflow(ctx context.Context, logger logr.Logger, stored *v1alpha1.Workflow) (reconcile.Result, error) {
@@ -462,7 +513,16 @@ func pointerToValue[V any](ptr *V) V {
 }
 
 // firstAction returns the first Action of the first Task in the Workflow.
+// When Tasks declare dependencies they can start in any order, so the first Action of the Task in the current state is returned.
 func firstAction(w *v1alpha1.Workflow) *v1alpha1.Action {
+	if w.Status.HasTaskDependencies() && w.Status.CurrentState != nil {
+		for i := range w.Status.Tasks {
+			if w.Status.Tasks[i].ID == w.Status.CurrentState.TaskID && len(w.Status.Tasks[i].Actions) > 0 {
+				return &w.Status.Tasks[i].Actions[0]
+			}
+		}
+		return nil
+	}
 	if len(w.Status.Tasks) > 0 {
 		if len(w.Status.Tasks[0].Actions) > 0 {
 			return &w.Status.Tasks[0].Actions[0]
@@ -478,6 +538,10 @@ func updateAgentIDIfNeeded(wf *v1alpha1.Workflow) bool {
 	if wf.Status.CurrentState == nil || len(wf.Status.Tasks) == 0 {
 		return false
 	}
+	// Tasks with dependencies are not run in order. The backend indexes these Workflows by the Agent IDs of all Tasks.
+	if wf.Status.HasTaskDependencies() {
+		return false
+	}
 
 	// Find the current task index
 	currentTaskIndex := -1
@@ -500,11 +564,9 @@ func updateAgentIDIfNeeded(wf *v1alpha1.Workflow) bool {
 
 	currentTask := wf.Status.Tasks[currentTaskIndex]
 	// Step 2: Check if the current task is complete
-	// A task is complete when all its actions are in SUCCESS state
-	for _, action := range currentTask.Actions {
-		if action.State != v1alpha1.WorkflowStateSuccess {
-			return false // Current task is not complete
-		}
+	// A task is complete when all its actions are in SUCCESS or SKIPPED state
+	if !currentTask.IsComplete() {
+		return false // Current task is not complete
 	}
 
 	nextTask := wf.Status.Tasks[currentTaskIndex+1]
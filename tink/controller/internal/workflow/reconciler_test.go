@@ -1445,6 +1445,36 @@ func TestUpdateAgentIDIfNeeded(t *testing.T) {
 			wantAgentID: "agent1",
 			description: "should handle case where currentTaskIndex+1 would be out of bounds",
 		},
+		"tasks with dependencies - no update": {
+			workflow: &v1alpha1.Workflow{
+				Status: v1alpha1.WorkflowStatus{
+					CurrentState: &v1alpha1.CurrentState{TaskID: "task1"},
+					AgentID:      "agent1",
+					Tasks: []v1alpha1.Task{
+						{
+							ID:      "task1",
+							Name:    "task1",
+							AgentID: "agent1",
+							Actions: []v1alpha1.Action{
+								{ID: "action1", State: v1alpha1.WorkflowStateSuccess},
+							},
+						},
+						{
+							ID:        "task2",
+							Name:      "task2",
+							AgentID:   "agent2",
+							DependsOn: []string{"task1"},
+							Actions: []v1alpha1.Action{
+								{ID: "action2", State: v1alpha1.WorkflowStatePending},
+							},
+						},
+					},
+				},
+			},
+			wantUpdate:  false,
+			wantAgentID: "agent1",
+			description: "should not update the AgentID when Tasks declare dependencies",
+		},
 	}
 
 	for name, tt := range tests {
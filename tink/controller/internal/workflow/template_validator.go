@@ -3,9 +3,13 @@ package workflow
 import (
 	"errors"
 	"fmt"
+	"path"
+	"regexp"
 
 	"github.com/distribution/reference"
+	v1alpha1 "github.com/tinkerbell/tinkerbell/api/v1alpha1/tinkerbell"
 	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 const (
@@ -30,7 +34,7 @@ func parse(yamlContent []byte) (*Workflow, error) {
 
 // renderTemplateHardware renders the workflow template and returns the Workflow and the interpolated bytes.
 func renderTemplateHardware(templateID, templateData string, hardware map[string]interface{}) (*Workflow, error) {
-	rendered, err := renderTemplate("workflow-template", templateData, hardware)
+	rendered, err := RenderTemplate("workflow-template", templateData, hardware)
 	if err != nil {
 		return nil, fmt.Errorf("%s: err: %w", fmt.Sprintf(errTemplateParsing, templateID), err)
 	}
@@ -76,8 +80,31 @@ func validate(wf *Workflow) error {
 				return fmt.Errorf(errInvalidLength, action.Name)
 			}
 
-			if err := validateImageName(action.Image); err != nil {
-				return fmt.Errorf("invalid action image (%s): %v", action.Image, err)
+			switch v1alpha1.ActionType(action.Type) {
+			case "", v1alpha1.ActionTypeContainer:
+				if err := validateImageName(action.Image); err != nil {
+					return fmt.Errorf("invalid action image (%s): %v", action.Image, err)
+				}
+			case v1alpha1.ActionTypeNative:
+				if len(action.Command) == 0 || !path.IsAbs(action.Command[0]) {
+					return fmt.Errorf("native action command must start with an absolute path: %s", action.Name)
+				}
+			default:
+				return fmt.Errorf("invalid action type (%s): %s", action.Type, action.Name)
+			}
+
+			if action.Retries < 0 {
+				return fmt.Errorf("action retries cannot be negative: %s", action.Name)
+			}
+
+			if action.RetryDelay < 0 {
+				return fmt.Errorf("action retry-delay cannot be negative: %s", action.Name)
+			}
+
+			if action.Sandbox != nil {
+				if err := validateSandbox(*action.Sandbox); err != nil {
+					return fmt.Errorf("invalid action sandbox (%s): %w", action.Name, err)
+				}
 			}
 
 			_, ok := actionNameMap[action.Name]
@@ -87,6 +114,88 @@ func validate(wf *Workflow) error {
 			actionNameMap[action.Name] = struct{}{}
 		}
 	}
+	return validateDependencies(wf.Tasks)
+}
+
+// capabilityName matches a Linux capability name, with or without the CAP_ prefix, or ALL.
+var capabilityName = regexp.MustCompile(`^(?i)(CAP_)?[A-Z_]+$`)
+
+// validateSandbox validates the resource limits and capabilities of an action sandbox.
+func validateSandbox(s ActionSandbox) error {
+	for name, v := range map[string]string{"cpu": s.Resources.CPU, "memory": s.Resources.Memory} {
+		if v == "" {
+			continue
+		}
+		q, err := resource.ParseQuantity(v)
+		if err != nil {
+			return fmt.Errorf("invalid %s limit %q: %w", name, v, err)
+		}
+		if q.Sign() <= 0 {
+			return fmt.Errorf("%s limit must be positive: %s", name, v)
+		}
+	}
+	if s.Resources.PIDs < 0 {
+		return errors.New("pids limit cannot be negative")
+	}
+	caps := append(append([]string{}, s.Capabilities.Add...), s.Capabilities.Drop...)
+	if len(caps) > 0 && (s.Privileged == nil || *s.Privileged) {
+		return errors.New("capabilities require privileged to be false")
+	}
+	for _, c := range caps {
+		if !capabilityName.MatchString(c) {
+			return fmt.Errorf("invalid capability: %q", c)
+		}
+	}
+
+	return nil
+}
+
+// validateDependencies validates that Task dependencies reference existing Tasks and do not form a cycle.
+func validateDependencies(tasks []Task) error {
+	deps := make(map[string][]string, len(tasks))
+	for _, task := range tasks {
+		deps[task.Name] = task.DependsOn
+	}
+	for _, task := range tasks {
+		for _, dep := range task.DependsOn {
+			if dep == task.Name {
+				return fmt.Errorf("task cannot depend on itself: %s", task.Name)
+			}
+			if _, ok := deps[dep]; !ok {
+				return fmt.Errorf("task %s depends on unknown task: %s", task.Name, dep)
+			}
+		}
+	}
+
+	// Depth first search for cycles. A Task is visiting while its dependencies are being walked.
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	seen := make(map[string]int, len(tasks))
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch seen[name] {
+		case visiting:
+			return fmt.Errorf("task dependencies form a cycle: %s", name)
+		case visited:
+			return nil
+		}
+		seen[name] = visiting
+		for _, dep := range deps[name] {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		seen[name] = visited
+		return nil
+	}
+	for _, task := range tasks {
+		if err := visit(task.Name); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -128,10 +128,91 @@ func TestValidateTemplate(t *testing.T) {
 			wf:            toWorkflow(withActionInvalidImage()),
 			expectedError: true,
 		},
+		{
+			name:          "action retries is negative",
+			wf:            toWorkflow(withActionNegativeRetries()),
+			expectedError: true,
+		},
+		{
+			name:          "action retry-delay is negative",
+			wf:            toWorkflow(withActionNegativeRetryDelay()),
+			expectedError: true,
+		},
+		{
+			name:          "task depends on itself",
+			wf:            toWorkflow(withTaskDependsOn("pre-installation")),
+			expectedError: true,
+		},
+		{
+			name:          "task depends on unknown task",
+			wf:            toWorkflow(withTaskDependsOn("missing")),
+			expectedError: true,
+		},
+		{
+			name:          "task dependencies form a cycle",
+			wf:            toWorkflow(withParallelTasks(), withTaskDependsOn("control-plane-2")),
+			expectedError: true,
+		},
 		{
 			name: "valid task name",
 			wf:   toWorkflow(),
 		},
+		{
+			name: "valid task dependencies",
+			wf:   toWorkflow(withParallelTasks()),
+		},
+		{
+			name: "valid action retries",
+			wf:   toWorkflow(withActionRetries()),
+		},
+		{
+			name: "valid native action",
+			wf:   toWorkflow(withActionNative("/usr/sbin/kexec", "-e")),
+		},
+		{
+			name:          "native action command is relative",
+			wf:            toWorkflow(withActionNative("kexec", "-e")),
+			expectedError: true,
+		},
+		{
+			name:          "native action has no command",
+			wf:            toWorkflow(withActionNative()),
+			expectedError: true,
+		},
+		{
+			name:          "action type is invalid",
+			wf:            toWorkflow(func(wf *Workflow) { wf.Tasks[0].Actions[0].Type = "vm" }),
+			expectedError: true,
+		},
+		{
+			name: "valid sandbox",
+			wf: toWorkflow(withActionSandbox(ActionSandbox{
+				Resources:              ActionResources{CPU: "500m", Memory: "512Mi", PIDs: 256},
+				Capabilities:           ActionCapabilities{Add: []string{"SYS_ADMIN"}, Drop: []string{"ALL"}},
+				ReadOnlyRootFilesystem: true,
+				Privileged:             new(bool),
+			})),
+		},
+		{
+			name:          "sandbox cpu is invalid",
+			wf:            toWorkflow(withActionSandbox(ActionSandbox{Resources: ActionResources{CPU: "half"}})),
+			expectedError: true,
+		},
+		{
+			name:          "sandbox memory is not positive",
+			wf:            toWorkflow(withActionSandbox(ActionSandbox{Resources: ActionResources{Memory: "0"}})),
+			expectedError: true,
+		},
+		{
+			name:          "sandbox capabilities on a privileged container",
+			wf:            toWorkflow(withActionSandbox(ActionSandbox{Capabilities: ActionCapabilities{Drop: []string{"NET_RAW"}}})),
+			expectedError: true,
+		},
+		{
+			name:          "sandbox capability is invalid",
+			wf:            toWorkflow(withActionSandbox(ActionSandbox{Capabilities: ActionCapabilities{Add: []string{"net admin"}}, Privileged: new(bool)})),
+			expectedError: true,
+		},
 	}
 	for _, test := range testCases {
 		t.Run(test.name, func(t *testing.T) {
@@ -218,6 +299,26 @@ func withTaskDuplicateName() workflowModifier {
 	return func(wf *Workflow) { wf.Tasks = append(wf.Tasks, wf.Tasks[0]) }
 }
 
+func withTaskDependsOn(names ...string) workflowModifier {
+	return func(wf *Workflow) { wf.Tasks[0].DependsOn = names }
+}
+
+// valid task modifiers
+
+// withParallelTasks adds two Tasks on different workers that both depend on the first Task.
+func withParallelTasks() workflowModifier {
+	return func(wf *Workflow) {
+		for _, name := range []string{"control-plane-1", "control-plane-2"} {
+			wf.Tasks = append(wf.Tasks, Task{
+				Name:       name,
+				WorkerAddr: "08:00:27:00:00:0" + name[len(name)-1:],
+				DependsOn:  []string{wf.Tasks[0].Name},
+				Actions:    []Action{{Name: "kubeadm", Image: "kubeadm", Timeout: 600}},
+			})
+		}
+	}
+}
+
 // invalid action modifiers
 
 func withActionInvalidName() workflowModifier {
@@ -238,6 +339,31 @@ func withActionInvalidImage() workflowModifier {
 	return func(wf *Workflow) { wf.Tasks[0].Actions[0].Image = "action-image-with-$#@-" }
 }
 
+func withActionNegativeRetries() workflowModifier {
+	return func(wf *Workflow) { wf.Tasks[0].Actions[0].Retries = -1 }
+}
+
+func withActionNegativeRetryDelay() workflowModifier {
+	return func(wf *Workflow) { wf.Tasks[0].Actions[0].RetryDelay = -1 }
+}
+
+// valid action modifiers
+
+func withActionRetries() workflowModifier {
+	return func(wf *Workflow) {
+		wf.Tasks[0].Actions[0].Retries = 3
+		wf.Tasks[0].Actions[0].RetryDelay = 10
+	}
+}
+
+func withActionNative(command ...string) workflowModifier {
+	return func(wf *Workflow) {
+		wf.Tasks[0].Actions[0].Type = "native"
+		wf.Tasks[0].Actions[0].Image = ""
+		wf.Tasks[0].Actions[0].Command = command
+	}
+}
+
 // invalid template modifiers
 
 func withTemplateInvalidName() workflowModifier {
@@ -374,3 +500,7 @@ tasks:
 	_, err := renderTemplateHardware("test-oversized", tmpl, map[string]interface{}{"device_1": "08:00:27:00:00:01"})
 	assert.ErrorContains(t, err, "exceeds")
 }
+
+func withActionSandbox(sb ActionSandbox) workflowModifier {
+	return func(wf *Workflow) { wf.Tasks[0].Actions[0].Sandbox = &sb }
+}
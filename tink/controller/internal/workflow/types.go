@@ -16,6 +16,8 @@ type Task struct {
 	Actions     []Action          `yaml:"actions"`
 	Volumes     []string          `yaml:"volumes,omitempty"`
 	Environment map[string]string `yaml:"environment,omitempty"`
+	// DependsOn is a list of Task names that must complete before this Task is started.
+	DependsOn []string `yaml:"depends-on,omitempty"`
 }
 
 // Action is the basic executional unit for a workflow.
@@ -26,10 +28,40 @@ type Action struct {
 	Command     []string          `yaml:"command,omitempty"`
 	OnTimeout   []string          `yaml:"on-timeout,omitempty"`
 	OnFailure   []string          `yaml:"on-failure,omitempty"`
+	Retries     int64             `yaml:"retries,omitempty"`
+	RetryDelay  int64             `yaml:"retry-delay,omitempty"`
 	Volumes     []string          `yaml:"volumes,omitempty"`
 	Environment map[string]string `yaml:"environment,omitempty"`
 	Pid         string            `yaml:"pid,omitempty"`
 	Namespaces  ActionNamespaces  `yaml:"namespaces,omitempty"`
+	// If determines whether the Action is run. See the v1alpha1 Action If field for the syntax.
+	If string `yaml:"if,omitempty"`
+	// Type is how the Action runs, "container", the default, or "native". Native Actions run Command on the host and have no Image.
+	Type string `yaml:"type,omitempty"`
+	// Sandbox restricts what the Action container can do and use. See the v1alpha1 Action Sandbox field.
+	Sandbox *ActionSandbox `yaml:"sandbox,omitempty"`
+}
+
+// ActionSandbox restricts what an action container can do and use.
+type ActionSandbox struct {
+	Resources              ActionResources    `yaml:"resources,omitempty"`
+	Capabilities           ActionCapabilities `yaml:"capabilities,omitempty"`
+	ReadOnlyRootFilesystem bool               `yaml:"read-only-root-filesystem,omitempty"`
+	// Privileged is nil when not set, so that the runtime's default is used.
+	Privileged *bool `yaml:"privileged,omitempty"`
+}
+
+// ActionResources are the resource limits of an action container. CPU and Memory are quantities, like 500m and 512Mi.
+type ActionResources struct {
+	CPU    string `yaml:"cpu,omitempty"`
+	Memory string `yaml:"memory,omitempty"`
+	PIDs   int64  `yaml:"pids,omitempty"`
+}
+
+// ActionCapabilities are the Linux capabilities added to, and dropped from, an action container.
+type ActionCapabilities struct {
+	Add  []string `yaml:"add,omitempty"`
+	Drop []string `yaml:"drop,omitempty"`
 }
 
 // ActionNamespaces defines the Linux namespaces an action container runs in.
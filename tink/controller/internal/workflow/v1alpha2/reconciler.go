@@ -0,0 +1,139 @@
+// Package v1alpha2 reconciles v1alpha2 Workflow objects.
+// It resolves the Tasks a Workflow references, renders them into the Workflow status,
+// and rolls up the Action states reported by Tink Server into the Task and Workflow metadata.
+package v1alpha2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/tinkerbell/tinkerbell/api/v1alpha2/tinkerbell"
+	"github.com/tinkerbell/tinkerbell/pkg/journal"
+	"k8s.io/apimachinery/pkg/api/equality"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// reasonError is the condition Reason set when a workflow step fails.
+	reasonError = "Error"
+)
+
+type dynamicClient interface {
+	DynamicRead(ctx context.Context, gvr schema.GroupVersionResource, name, namespace string) (map[string]interface{}, error)
+}
+
+// Reconciler is a type for managing v1alpha2 Workflows.
+type Reconciler struct {
+	client        ctrlclient.Client
+	nowFunc       func() time.Time
+	dynamicClient dynamicClient
+}
+
+// NewReconciler returns a v1alpha2 Workflow Reconciler.
+func NewReconciler(client ctrlclient.Client, dc dynamicClient) *Reconciler {
+	return &Reconciler{
+		client:        client,
+		nowFunc:       time.Now,
+		dynamicClient: dc,
+	}
+}
+
+func (r *Reconciler) SetupWithManager(mgr manager.Manager, opts controller.Options) error {
+	return ctrl.
+		NewControllerManagedBy(mgr).
+		WithOptions(opts).
+		For(&tinkerbell.Workflow{}).
+		Complete(r)
+}
+
+// +kubebuilder:rbac:groups=tinkerbell.org,resources=hardware,verbs=get;list;watch
+// +kubebuilder:rbac:groups=tinkerbell.org,resources=tasks,verbs=get;list;watch
+// +kubebuilder:rbac:groups=tinkerbell.org,resources=policies,verbs=get;list;watch
+// +kubebuilder:rbac:groups=tinkerbell.org,resources=workflows;workflows/status,verbs=get;list;watch;update;patch
+
+// Reconcile handles v1alpha2 Workflow objects. New Workflows have their Tasks rendered into the status.
+// Pending and Running Workflows have their Task and Workflow metadata updated from the Action states and
+// have their timeouts enforced.
+func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	ctx = journal.New(ctx)
+	logger := ctrl.LoggerFrom(ctx)
+	defer func() {
+		logger.V(1).Info("Reconcile code flow journal", "journal", journal.Journal(ctx))
+	}()
+	logger.Info("Reconcile")
+	journal.Log(ctx, "starting reconcile")
+
+	stored := &tinkerbell.Workflow{}
+	if err := r.client.Get(ctx, req.NamespacedName, stored); err != nil {
+		if kerrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	if !stored.DeletionTimestamp.IsZero() {
+		return reconcile.Result{}, nil
+	}
+
+	wflow := stored.DeepCopy()
+
+	if wflow.Spec.Disabled != nil && *wflow.Spec.Disabled {
+		journal.Log(ctx, "workflow disabled")
+		wflow.Status.SetConditionIfDifferent(tinkerbell.WorkflowCondition{
+			Type:    tinkerbell.DisabledCondition,
+			Status:  metav1.ConditionTrue,
+			Reason:  "Disabled",
+			Message: "workflow is disabled",
+			Time:    &metav1.Time{Time: r.nowFunc().UTC()},
+		})
+		return reconcile.Result{}, mergePatchStatus(ctx, r.client, stored, wflow)
+	}
+	if wflow.Status.HasCondition(tinkerbell.DisabledCondition, metav1.ConditionTrue) {
+		journal.Log(ctx, "workflow enabled")
+		wflow.Status.SetCondition(tinkerbell.WorkflowCondition{
+			Type:    tinkerbell.DisabledCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  "Enabled",
+			Message: "workflow is enabled",
+			Time:    &metav1.Time{Time: r.nowFunc().UTC()},
+		})
+	}
+
+	switch wflow.Status.Metadata.Workflow.State {
+	case 0:
+		journal.Log(ctx, "new workflow")
+		err := r.renderWorkflow(ctx, logger, wflow)
+
+		return reconcile.Result{}, errors.Join(err, mergePatchStatus(ctx, r.client, stored, wflow))
+	case tinkerbell.WorkflowStatePending, tinkerbell.WorkflowStateRunning:
+		journal.Log(ctx, "process workflow", "state", wflow.Status.Metadata.Workflow.State)
+		rc := r.progress(ctx, wflow)
+
+		return rc, mergePatchStatus(ctx, r.client, stored, wflow)
+	default:
+		journal.Log(ctx, "controller will not trigger another reconcile", "state", wflow.Status.Metadata.Workflow.State)
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// mergePatchStatus merges an updated Workflow with an original Workflow and patches the Status object via the client (cc).
+// The patch uses optimistic locking as Tink Server writes Action states to the same status concurrently.
+func mergePatchStatus(ctx context.Context, cc ctrlclient.Client, original, updated *tinkerbell.Workflow) error {
+	if !equality.Semantic.DeepEqual(updated.Status, original.Status) {
+		journal.Log(ctx, "patching status")
+		if err := cc.Status().Patch(ctx, updated, ctrlclient.MergeFromWithOptions(original, ctrlclient.MergeFromWithOptimisticLock{})); err != nil {
+			return fmt.Errorf("error patching status of workflow: %s, error: %w", updated.Name, err)
+		}
+	}
+	return nil
+}
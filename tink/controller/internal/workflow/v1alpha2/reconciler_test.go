@@ -0,0 +1,315 @@
+package v1alpha2
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tinkerbell/tinkerbell/api/v1alpha2/tinkerbell"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var runtimescheme = runtime.NewScheme()
+
+func init() {
+	_ = tinkerbell.AddToScheme(runtimescheme)
+}
+
+type fakeDynamicClient struct {
+	objs map[string]map[string]interface{}
+}
+
+func (f *fakeDynamicClient) DynamicRead(_ context.Context, _ schema.GroupVersionResource, name, namespace string) (map[string]interface{}, error) {
+	if o, ok := f.objs[namespace+"/"+name]; ok {
+		return o, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func newClient(objs ...client.Object) client.Client {
+	return fake.NewClientBuilder().
+		WithScheme(runtimescheme).
+		WithObjects(objs...).
+		WithStatusSubresource(&tinkerbell.Workflow{}).
+		Build()
+}
+
+func testTask() *tinkerbell.Task {
+	return &tinkerbell.Task{
+		ObjectMeta: metav1.ObjectMeta{Name: "provision", Namespace: "default"},
+		Spec: tinkerbell.TaskSpec{
+			Env:  map[string]string{"DEST_DISK": "/dev/sda"},
+			Vars: map[string]string{"tag": "v0.0.1"},
+			Actions: []tinkerbell.Action{
+				{
+					Name:  "stream",
+					Image: "quay.io/tinkerbell-actions/image2disk:{{ .vars.tag }}",
+					Env:   map[string]string{"HOSTNAME": "{{ .hardware.metadata.name }}"},
+				},
+				{
+					Name:  "skipped",
+					If:    `{{ eq .vars.tag "never" }}`,
+					Image: "quay.io/tinkerbell-actions/kexec:v1.0.0",
+				},
+				{
+					Name:  "kexec",
+					Image: "quay.io/tinkerbell-actions/kexec:v1.0.0",
+				},
+			},
+		},
+	}
+}
+
+func testHardware() *tinkerbell.Hardware {
+	return &tinkerbell.Hardware{
+		ObjectMeta: metav1.ObjectMeta{Name: "machine1", Namespace: "default"},
+		Spec:       tinkerbell.HardwareSpec{AgentID: "3c:ec:ef:4c:4f:54"},
+	}
+}
+
+func testWorkflow() *tinkerbell.Workflow {
+	return &tinkerbell.Workflow{
+		ObjectMeta: metav1.ObjectMeta{Name: "wf1", Namespace: "default"},
+		Spec: tinkerbell.WorkflowSpec{
+			TimeoutSeconds: toPtr(int64(600)),
+			Vars:           map[string]string{"tag": "v1.0.0"},
+			Tasks: []tinkerbell.WorkflowTask{
+				{
+					TaskRef:  tinkerbell.SimpleReference{Name: "provision"},
+					Hardware: &tinkerbell.WorkflowHardware{HardwareRef: &tinkerbell.SimpleReference{Name: "machine1"}},
+				},
+			},
+		},
+	}
+}
+
+func TestReconcileRender(t *testing.T) {
+	tests := map[string]struct {
+		objs        []client.Object
+		wantState   tinkerbell.State
+		wantErr     bool
+		wantActions []tinkerbell.Action
+		wantAgentID string
+	}{
+		"rendered": {
+			objs:      []client.Object{testWorkflow(), testTask(), testHardware()},
+			wantState: tinkerbell.WorkflowStatePending,
+			wantActions: []tinkerbell.Action{
+				{
+					Name:  "stream",
+					Image: "quay.io/tinkerbell-actions/image2disk:v1.0.0",
+					Env:   map[string]string{"HOSTNAME": "machine1"},
+				},
+				{
+					Name:  "kexec",
+					Image: "quay.io/tinkerbell-actions/kexec:v1.0.0",
+				},
+			},
+			wantAgentID: "3c:ec:ef:4c:4f:54",
+		},
+		"missing task": {
+			objs:    []client.Object{testWorkflow(), testHardware()},
+			wantErr: true,
+		},
+		"missing hardware": {
+			objs:    []client.Object{testWorkflow(), testTask()},
+			wantErr: true,
+		},
+		"no agent ID": {
+			objs: []client.Object{testWorkflow(), testTask(), func() *tinkerbell.Hardware {
+				hw := testHardware()
+				hw.Spec.AgentID = ""
+				return hw
+			}()},
+			wantErr: true,
+		},
+		"workflow reference error": {
+			objs: []client.Object{func() *tinkerbell.Workflow {
+				wf := testWorkflow()
+				wf.Spec.References = map[string]tinkerbell.Reference{"cfg": {Name: "missing", Namespace: "default", Resource: "configmaps", Version: "v1"}}
+				return wf
+			}(), testTask(), testHardware()},
+			wantErr: true,
+		},
+		"task reference error": {
+			objs: []client.Object{testWorkflow(), func() *tinkerbell.Task {
+				task := testTask()
+				task.Spec.References = map[string]tinkerbell.Reference{"cfg": {Name: "missing", Namespace: "default", Resource: "configmaps", Version: "v1"}}
+				return task
+			}(), testHardware()},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			cc := newClient(tc.objs...)
+			r := NewReconciler(cc, &fakeDynamicClient{})
+			_, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "wf1", Namespace: "default"}})
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got := &tinkerbell.Workflow{}
+			if err := cc.Get(context.Background(), types.NamespacedName{Name: "wf1", Namespace: "default"}, got); err != nil {
+				t.Fatal(err)
+			}
+			if tc.wantErr {
+				if !got.Status.HasCondition(tinkerbell.TemplateRenderedSuccess, metav1.ConditionFalse) {
+					t.Errorf("expected %s condition to be false, got: %+v", tinkerbell.TemplateRenderedSuccess, got.Status.Conditions)
+				}
+				if got.Status.Metadata.Workflow.State != 0 {
+					t.Errorf("expected no state, got: %v", got.Status.Metadata.Workflow.State)
+				}
+				return
+			}
+
+			if got.Status.Metadata.Workflow.State != tc.wantState {
+				t.Errorf("unexpected state: got %v, want %v", got.Status.Metadata.Workflow.State, tc.wantState)
+			}
+			if got.Status.GlobalTimeout != 600 {
+				t.Errorf("unexpected global timeout: %v", got.Status.GlobalTimeout)
+			}
+			if len(got.Status.RenderedTasks) != 1 {
+				t.Fatalf("expected 1 rendered task, got %d", len(got.Status.RenderedTasks))
+			}
+			rt := got.Status.RenderedTasks[0]
+			if rt.Metadata.AgentID != tc.wantAgentID {
+				t.Errorf("unexpected agent ID: got %v, want %v", rt.Metadata.AgentID, tc.wantAgentID)
+			}
+			if diff := cmp.Diff(map[string]string{"DEST_DISK": "/dev/sda"}, rt.Env); diff != "" {
+				t.Errorf("unexpected task env (-want +got):\n%s", diff)
+			}
+			var actions []tinkerbell.Action
+			for _, a := range rt.Actions {
+				actions = append(actions, a.Action)
+				if a.Metadata.ID == "" || a.Metadata.State != tinkerbell.ActionStatePending || a.Metadata.AgentID != tc.wantAgentID {
+					t.Errorf("unexpected action metadata: %+v", a.Metadata)
+				}
+			}
+			if diff := cmp.Diff(tc.wantActions, actions); diff != "" {
+				t.Errorf("unexpected actions (-want +got):\n%s", diff)
+			}
+			if got.Status.Metadata.Action.ID != rt.Actions[0].Metadata.ID {
+				t.Errorf("expected the current action to be the first action, got: %+v", got.Status.Metadata.Action)
+			}
+		})
+	}
+}
+
+func TestReconcileProgress(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 10, 0, 0, time.UTC)
+	start := metav1.NewTime(now.Add(-5 * time.Minute))
+	end := metav1.NewTime(now.Add(-time.Minute))
+
+	rendered := func(timeout int64, actions ...tinkerbell.Metadata) *tinkerbell.Workflow {
+		wf := testWorkflow()
+		wf.Status.GlobalTimeout = timeout
+		wf.Status.Metadata.Workflow = tinkerbell.Metadata{ID: "wf", Name: "wf1", State: tinkerbell.WorkflowStatePending}
+		task := tinkerbell.TaskWithMetadata{
+			Name:     "provision",
+			Metadata: tinkerbell.Metadata{ID: "task", Name: "provision", State: tinkerbell.TaskStatePending},
+		}
+		for _, a := range actions {
+			task.Actions = append(task.Actions, tinkerbell.ActionWithMetadata{Action: tinkerbell.Action{Name: a.Name}, Metadata: a})
+		}
+		wf.Status.RenderedTasks = []tinkerbell.TaskWithMetadata{task}
+		return wf
+	}
+
+	tests := map[string]struct {
+		workflow    *tinkerbell.Workflow
+		wantState   tinkerbell.State
+		wantMessage string
+		wantRequeue time.Duration
+	}{
+		"running": {
+			workflow: rendered(600,
+				tinkerbell.Metadata{ID: "a1", Name: "stream", State: tinkerbell.ActionStateSuccess, StartTime: &start, EndTime: &end},
+				tinkerbell.Metadata{ID: "a2", Name: "kexec", State: tinkerbell.ActionStatePending},
+			),
+			wantState:   tinkerbell.WorkflowStateRunning,
+			wantRequeue: 5 * time.Minute,
+		},
+		"success": {
+			workflow: rendered(600,
+				tinkerbell.Metadata{ID: "a1", Name: "stream", State: tinkerbell.ActionStateSuccess, StartTime: &start, EndTime: &end},
+			),
+			wantState: tinkerbell.WorkflowStateSuccess,
+		},
+		"failed": {
+			workflow: rendered(600,
+				tinkerbell.Metadata{ID: "a1", Name: "stream", State: tinkerbell.ActionStateFailed, StartTime: &start, EndTime: &end, Message: "exit code 1"},
+				tinkerbell.Metadata{ID: "a2", Name: "kexec", State: tinkerbell.ActionStatePending},
+			),
+			wantState:   tinkerbell.WorkflowStateFailed,
+			wantMessage: "exit code 1",
+		},
+		"global timeout": {
+			workflow: rendered(60,
+				tinkerbell.Metadata{ID: "a1", Name: "stream", State: tinkerbell.ActionStateRunning, StartTime: &start},
+			),
+			wantState:   tinkerbell.WorkflowStateTimeout,
+			wantMessage: "workflow timed out",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			cc := newClient(tc.workflow)
+			r := NewReconciler(cc, &fakeDynamicClient{})
+			r.nowFunc = func() time.Time { return now }
+			res, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "wf1", Namespace: "default"}})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if res.RequeueAfter != tc.wantRequeue {
+				t.Errorf("unexpected requeue: got %v, want %v", res.RequeueAfter, tc.wantRequeue)
+			}
+
+			got := &tinkerbell.Workflow{}
+			if err := cc.Get(context.Background(), types.NamespacedName{Name: "wf1", Namespace: "default"}, got); err != nil {
+				t.Fatal(err)
+			}
+			if got.Status.Metadata.Workflow.State != tc.wantState {
+				t.Errorf("unexpected state: got %v, want %v", got.Status.Metadata.Workflow.State, tc.wantState)
+			}
+			if got.Status.Metadata.Workflow.Message != tc.wantMessage {
+				t.Errorf("unexpected message: got %q, want %q", got.Status.Metadata.Workflow.Message, tc.wantMessage)
+			}
+		})
+	}
+}
+
+func TestReconcileDisabled(t *testing.T) {
+	wf := testWorkflow()
+	wf.Spec.Disabled = toPtr(true)
+	cc := newClient(wf, testTask(), testHardware())
+	r := NewReconciler(cc, &fakeDynamicClient{})
+	if _, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "wf1", Namespace: "default"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := &tinkerbell.Workflow{}
+	if err := cc.Get(context.Background(), types.NamespacedName{Name: "wf1", Namespace: "default"}, got); err != nil {
+		t.Fatal(err)
+	}
+	if !got.Status.HasCondition(tinkerbell.DisabledCondition, metav1.ConditionTrue) {
+		t.Errorf("expected %s condition to be true, got: %+v", tinkerbell.DisabledCondition, got.Status.Conditions)
+	}
+	if len(got.Status.RenderedTasks) != 0 {
+		t.Errorf("expected no rendered tasks, got: %d", len(got.Status.RenderedTasks))
+	}
+}
+
+func toPtr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,190 @@
+package v1alpha2
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/go-logr/logr"
+	"github.com/tinkerbell/tinkerbell/api/v1alpha2/tinkerbell"
+	"github.com/tinkerbell/tinkerbell/tink/controller/internal/workflow"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// denyAll is the deny rule used when no Policy defines deny rules, so that References must be explicitly allowed.
+const denyAll = `{"reference": {"name": [{"wildcard": "*"}]}}`
+
+// evaluationData is the data structure used for evaluating Policy reference access rules.
+// In Quamina, this is called the "event".
+type evaluationData struct {
+	// Source is the Object that contains the references.
+	Source source `json:"source,omitempty"`
+	// Reference is a reference to another Object from the source.
+	Reference tinkerbell.Reference `json:"reference,omitempty"`
+}
+
+// source is the Object that contains the references.
+type source struct {
+	// Name is the name of the source object.
+	Name string `json:"name,omitempty"`
+	// Namespace is the namespace of the source object.
+	Namespace string `json:"namespace,omitempty"`
+	// Labels of the source object in "key=value" form so that they can be matched by a FieldPattern.
+	Labels []string `json:"labels,omitempty"`
+}
+
+// ruleList holds the Quamina patterns of a Policy RuleLists.
+type ruleList struct {
+	allow []string
+	deny  []string
+}
+
+// accessRules are the reference access rules, by source object kind, from all Policies in a namespace.
+type accessRules struct {
+	hardware ruleList
+	task     ruleList
+	workflow ruleList
+}
+
+// accessRules collects the reference access rules from all Policies in namespace.
+func (r *Reconciler) accessRules(ctx context.Context, namespace string) (accessRules, error) {
+	var rules accessRules
+	policies := &tinkerbell.PolicyList{}
+	if err := r.client.List(ctx, policies, ctrlclient.InNamespace(namespace)); err != nil {
+		return rules, err
+	}
+
+	var errs error
+	for _, p := range policies.Items {
+		ra := p.Spec.Rules.ReferenceAccess
+		if ra == nil {
+			continue
+		}
+		for _, rl := range []struct {
+			from *tinkerbell.RuleLists
+			to   *ruleList
+		}{
+			{from: ra.Hardware, to: &rules.hardware},
+			{from: ra.Task, to: &rules.task},
+			{from: ra.Workflow, to: &rules.workflow},
+		} {
+			if rl.from == nil {
+				continue
+			}
+			for _, ar := range rl.from.Allow {
+				pattern, err := toPattern(ar)
+				if err != nil {
+					errs = errors.Join(errs, fmt.Errorf("policy %s/%s: %w", p.Namespace, p.Name, err))
+					continue
+				}
+				rl.to.allow = append(rl.to.allow, pattern)
+			}
+			for _, ar := range rl.from.Deny {
+				pattern, err := toPattern(ar)
+				if err != nil {
+					errs = errors.Join(errs, fmt.Errorf("policy %s/%s: %w", p.Namespace, p.Name, err))
+					continue
+				}
+				rl.to.deny = append(rl.to.deny, pattern)
+			}
+		}
+	}
+
+	return rules, errs
+}
+
+// toPattern converts an AccessRule to a Quamina pattern.
+func toPattern(ar tinkerbell.AccessRule) (string, error) {
+	p := map[string]map[string][]json.RawMessage{}
+	add := func(section, field string, fp tinkerbell.FieldPattern) {
+		for _, v := range fp {
+			if len(v.Raw) == 0 {
+				continue
+			}
+			if p[section] == nil {
+				p[section] = map[string][]json.RawMessage{}
+			}
+			p[section][field] = append(p[section][field], json.RawMessage(v.Raw))
+		}
+	}
+	if ar.Source != nil {
+		add("source", "name", ar.Source.Name)
+		add("source", "namespace", ar.Source.Namespace)
+		add("source", "labels", ar.Source.Labels)
+	}
+	if ar.Reference != nil {
+		add("reference", "name", ar.Reference.Name)
+		add("reference", "namespace", ar.Reference.Namespace)
+		add("reference", "group", ar.Reference.Group)
+		add("reference", "version", ar.Reference.Version)
+		add("reference", "resource", ar.Reference.Resource)
+	}
+	if len(p) == 0 {
+		return "", errors.New("access rule has no patterns")
+	}
+
+	b, err := json.Marshal(p)
+	if err != nil {
+		return "", fmt.Errorf("error marshalling access rule: %w", err)
+	}
+	return string(b), nil
+}
+
+// references returns the allowed References of an object, read via the dynamic client, keyed by their reference name.
+// A Reference is allowed when it matches an allow rule or does not match any deny rule.
+// When no deny rules are defined all References are denied unless explicitly allowed.
+func (r *Reconciler) references(ctx context.Context, logger logr.Logger, obj metav1.Object, refs map[string]tinkerbell.Reference, rules ruleList) (map[string]interface{}, error) {
+	resp := make(map[string]interface{})
+	if len(refs) == 0 {
+		return resp, nil
+	}
+	deny := rules.deny
+	if len(deny) == 0 {
+		deny = []string{denyAll}
+	}
+	src := source{
+		Name:      obj.GetName(),
+		Namespace: obj.GetNamespace(),
+	}
+	for k, v := range obj.GetLabels() {
+		src.Labels = append(src.Labels, k+"="+v)
+	}
+	sort.Strings(src.Labels)
+
+	var refErr error
+	for refName, rf := range refs {
+		ed := evaluationData{Source: src, Reference: rf}
+		denied, drules, err := workflow.Evaluate(ctx, deny, ed)
+		if err != nil {
+			refErr = errors.Join(refErr, err)
+			logger.V(1).Info("error applying deny rules", "error", err, "denyRules", deny)
+			continue
+		}
+		allowed, arules, err := workflow.Evaluate(ctx, rules.allow, ed)
+		if err != nil {
+			refErr = errors.Join(refErr, err)
+			logger.V(1).Info("error applying allow rules", "error", err, "allowRules", rules.allow)
+			continue
+		}
+		if denied && !allowed {
+			refErr = errors.Join(refErr, fmt.Errorf("reference denied: %s", refName))
+			logger.V(1).Info("reference denied", "referenceName", refName, "denyRules", drules, "allowRules", arules)
+			continue
+		}
+		logger.V(1).Info("reference allowed", "referenceName", refName, "denyRules", drules, "allowRules", arules)
+		gvr := schema.GroupVersionResource{Group: rf.Group, Version: rf.Version, Resource: rf.Resource}
+		v, err := r.dynamicClient.DynamicRead(ctx, gvr, rf.Name, rf.Namespace)
+		if err != nil {
+			refErr = errors.Join(refErr, err)
+			logger.V(1).Info("error getting reference", "referenceName", rf.Name, "namespace", rf.Namespace, "gvr", gvr, "error", err)
+			continue
+		}
+		resp[refName] = v
+	}
+
+	return resp, refErr
+}
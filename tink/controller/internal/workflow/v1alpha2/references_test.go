@@ -0,0 +1,108 @@
+package v1alpha2
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
+	"github.com/tinkerbell/tinkerbell/api/v1alpha2/tinkerbell"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func pattern(raw string) tinkerbell.PatternValue {
+	return tinkerbell.PatternValue(apiextensionsv1.JSON{Raw: []byte(raw)})
+}
+
+func TestToPattern(t *testing.T) {
+	tests := map[string]struct {
+		rule    tinkerbell.AccessRule
+		want    string
+		wantErr bool
+	}{
+		"source and reference": {
+			rule: tinkerbell.AccessRule{
+				Source: &tinkerbell.SourcePattern{
+					Namespace: tinkerbell.FieldPattern{pattern(`"tinkerbell"`)},
+				},
+				Reference: &tinkerbell.ReferencePattern{
+					Name:     tinkerbell.FieldPattern{pattern(`"config"`), pattern(`{"prefix": "config-"}`)},
+					Resource: tinkerbell.FieldPattern{pattern(`"configmaps"`)},
+				},
+			},
+			want: `{"reference":{"name":["config",{"prefix":"config-"}],"resource":["configmaps"]},"source":{"namespace":["tinkerbell"]}}`,
+		},
+		"empty": {
+			rule:    tinkerbell.AccessRule{},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := toPattern(tc.rule)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("unexpected pattern (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReferences(t *testing.T) {
+	configMap := map[string]interface{}{"data": map[string]interface{}{"key": "value"}}
+	refs := map[string]tinkerbell.Reference{
+		"config": {Name: "config", Namespace: "tinkerbell", Resource: "configmaps", Version: "v1"},
+	}
+	allowConfig := &tinkerbell.Policy{
+		ObjectMeta: metav1.ObjectMeta{Name: "allow", Namespace: "default"},
+		Spec: tinkerbell.PolicySpec{
+			Rules: tinkerbell.Rules{
+				ReferenceAccess: &tinkerbell.ReferenceRules{
+					Workflow: &tinkerbell.RuleLists{
+						Allow: []tinkerbell.AccessRule{
+							{Reference: &tinkerbell.ReferencePattern{Name: tinkerbell.FieldPattern{pattern(`"config"`)}}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	tests := map[string]struct {
+		policies []client.Object
+		want     map[string]interface{}
+		wantErr  bool
+	}{
+		"denied by default": {
+			want:    map[string]interface{}{},
+			wantErr: true,
+		},
+		"allowed by policy": {
+			policies: []client.Object{allowConfig},
+			want:     map[string]interface{}{"config": configMap},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := NewReconciler(newClient(tc.policies...), &fakeDynamicClient{objs: map[string]map[string]interface{}{"tinkerbell/config": configMap}})
+			wf := testWorkflow()
+			rules, err := r.accessRules(context.Background(), wf.Namespace)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := r.references(context.Background(), logr.Discard(), wf, refs, rules.workflow)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("unexpected references (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,376 @@
+package v1alpha2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/distribution/reference"
+	"github.com/go-logr/logr"
+	"github.com/oklog/ulid/v2"
+	"github.com/tinkerbell/tinkerbell/api/v1alpha2/tinkerbell"
+	"github.com/tinkerbell/tinkerbell/pkg/journal"
+	"github.com/tinkerbell/tinkerbell/tink/controller/internal/workflow"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// templateDataHardware is the key used to access the Hardware data in the template data.
+	templateDataHardware = "hardware"
+	// templateDataReferences is the key used to access the references in the template data.
+	templateDataReferences = "references"
+	// templateDataVars is the key used to access the Task and Workflow vars in the template data.
+	templateDataVars = "vars"
+)
+
+// renderWorkflow resolves and renders every Task of the Workflow into Status.RenderedTasks.
+// On success the Workflow moves to the Pending state. On failure the TemplateRenderedSuccess
+// condition is set to false and the state is left unset so that the rendering is retried.
+func (r *Reconciler) renderWorkflow(ctx context.Context, logger logr.Logger, wf *tinkerbell.Workflow) error {
+	if len(wf.Spec.Tasks) == 0 {
+		journal.Log(ctx, "no tasks defined")
+		setRenderFailed(wf, "workflow must have at least one task defined")
+		return errors.New("workflow must have at least one task defined")
+	}
+
+	rules, err := r.accessRules(ctx, wf.Namespace)
+	if err != nil {
+		journal.Log(ctx, "error getting policies")
+		setRenderFailed(wf, fmt.Sprintf("error getting policies: %v", err))
+		return err
+	}
+	wfRefs, err := r.references(ctx, logger, wf, wf.Spec.References, rules.workflow)
+	if err != nil {
+		journal.Log(ctx, "error resolving workflow references")
+		setRenderFailed(wf, fmt.Sprintf("error resolving workflow references: %v", err))
+		return err
+	}
+
+	tasks := make([]tinkerbell.TaskWithMetadata, 0, len(wf.Spec.Tasks))
+	for idx, wt := range wf.Spec.Tasks {
+		t, err := r.renderTask(ctx, logger, wf, wt, rules, wfRefs)
+		if err != nil {
+			journal.Log(ctx, "error rendering task", "index", idx)
+			err = fmt.Errorf("error rendering task %d (%s): %w", idx, wt.TaskRef.Name, err)
+			setRenderFailed(wf, err.Error())
+			return err
+		}
+		tasks = append(tasks, t)
+	}
+
+	wf.Status.RenderedTasks = tasks
+	wf.Status.GlobalTimeout = pointerToValue(wf.Spec.TimeoutSeconds)
+	wf.Status.Metadata = tinkerbell.WorkflowMetadata{
+		Workflow: tinkerbell.Metadata{
+			ID:    newID(),
+			Name:  wf.Name,
+			State: tinkerbell.WorkflowStatePending,
+		},
+	}
+	setCurrent(wf)
+	wf.Status.SetCondition(tinkerbell.WorkflowCondition{
+		Type:    tinkerbell.TemplateRenderedSuccess,
+		Status:  metav1.ConditionTrue,
+		Reason:  "Complete",
+		Message: "tasks rendered successfully",
+		Time:    &metav1.Time{Time: r.nowFunc().UTC()},
+	})
+
+	return nil
+}
+
+// renderTask resolves the Task and Hardware referenced by a WorkflowTask and renders the Task Actions.
+// Env, volumes, and template map values are merged in order of the Task, the Workflow Globals, and the WorkflowTask Extra,
+// with later values taking precedence. Vars are merged from the Task and then the Workflow.
+func (r *Reconciler) renderTask(ctx context.Context, logger logr.Logger, wf *tinkerbell.Workflow, wt tinkerbell.WorkflowTask, rules accessRules, wfRefs map[string]interface{}) (tinkerbell.TaskWithMetadata, error) {
+	task := &tinkerbell.Task{}
+	tkey := ctrlclient.ObjectKey{Name: wt.TaskRef.Name, Namespace: namespaceOrDefault(wt.TaskRef.Namespace, wf.Namespace)}
+	if err := r.client.Get(ctx, tkey, task); err != nil {
+		return tinkerbell.TaskWithMetadata{}, fmt.Errorf("error getting task %s: %w", tkey, err)
+	}
+
+	var hw *tinkerbell.Hardware
+	if wt.Hardware != nil && wt.Hardware.HardwareRef != nil && wt.Hardware.HardwareRef.Name != "" {
+		hw = &tinkerbell.Hardware{}
+		hkey := ctrlclient.ObjectKey{Name: wt.Hardware.HardwareRef.Name, Namespace: namespaceOrDefault(wt.Hardware.HardwareRef.Namespace, wf.Namespace)}
+		if err := r.client.Get(ctx, hkey, hw); err != nil {
+			return tinkerbell.TaskWithMetadata{}, fmt.Errorf("error getting hardware %s: %w", hkey, err)
+		}
+	}
+
+	// References from the Workflow take precedence over the Task, which take precedence over the Hardware.
+	refs := map[string]interface{}{}
+	var refErr error
+	if hw != nil {
+		hwRefs, err := r.references(ctx, logger, hw, hw.Spec.References, rules.hardware)
+		refErr = err
+		maps.Copy(refs, hwRefs)
+	}
+	taskRefs, err := r.references(ctx, logger, task, task.Spec.References, rules.task)
+	refErr = err
+	maps.Copy(refs, taskRefs)
+	maps.Copy(refs, wfRefs)
+	if refErr != nil {
+		return tinkerbell.TaskWithMetadata{}, fmt.Errorf("error resolving references: %w", refErr)
+	}
+
+	data, err := templateData(hw, refs, mergeMaps(task.Spec.Vars, wf.Spec.Vars), wf.Spec.Globals, wt.Extra)
+	if err != nil {
+		return tinkerbell.TaskWithMetadata{}, err
+	}
+
+	rt := tinkerbell.TaskWithMetadata{
+		Name:    task.Name,
+		Env:     mergeMaps(task.Spec.Env, extraEnv(wf.Spec.Globals), extraEnv(wt.Extra)),
+		Volumes: mergeVolumes(task.Spec.Volumes, extraVolumes(wf.Spec.Globals), extraVolumes(wt.Extra)),
+		Metadata: tinkerbell.Metadata{
+			AgentID: wt.AgentID,
+			ID:      newID(),
+			Name:    task.Name,
+			State:   tinkerbell.TaskStatePending,
+		},
+	}
+	if hw != nil {
+		rt.Metadata.Hardware = hw.Name
+		if rt.Metadata.AgentID == "" {
+			rt.Metadata.AgentID = hw.Spec.AgentID
+		}
+	}
+	if rt.Metadata.AgentID == "" {
+		return tinkerbell.TaskWithMetadata{}, fmt.Errorf("no agent ID defined for task %s, set the agent ID in the Workflow or the Hardware", task.Name)
+	}
+	if err := renderEnv(task.Name, rt.Env, data); err != nil {
+		return tinkerbell.TaskWithMetadata{}, err
+	}
+	if rt.Volumes, err = renderVolumes(task.Name, rt.Volumes, data); err != nil {
+		return tinkerbell.TaskWithMetadata{}, err
+	}
+
+	names := map[string]struct{}{}
+	for _, a := range task.Spec.Actions {
+		if _, ok := names[a.Name]; ok {
+			return tinkerbell.TaskWithMetadata{}, fmt.Errorf("two actions in a task cannot have same name: %s", a.Name)
+		}
+		names[a.Name] = struct{}{}
+
+		ra, run, err := renderAction(task.Name, a, data)
+		if err != nil {
+			return tinkerbell.TaskWithMetadata{}, err
+		}
+		if !run {
+			logger.V(1).Info("action not included, if condition is false", "task", task.Name, "action", a.Name)
+			continue
+		}
+		rt.Actions = append(rt.Actions, tinkerbell.ActionWithMetadata{
+			Action: ra,
+			Metadata: tinkerbell.Metadata{
+				AgentID:  rt.Metadata.AgentID,
+				ID:       newID(),
+				Name:     ra.Name,
+				Hardware: rt.Metadata.Hardware,
+				State:    tinkerbell.ActionStatePending,
+			},
+		})
+	}
+	if rt.Actions == nil {
+		rt.Actions = []tinkerbell.ActionWithMetadata{}
+	}
+
+	return rt, nil
+}
+
+// templateData builds the data available to the Task templates.
+// TemplateMap values are top level keys. The hardware, references, and vars keys are reserved
+// and take precedence over TemplateMap keys with the same name.
+func templateData(hw *tinkerbell.Hardware, references map[string]interface{}, vars map[string]string, extras ...*tinkerbell.Extra) (map[string]interface{}, error) {
+	data := map[string]interface{}{}
+	for _, e := range extras {
+		if e == nil {
+			continue
+		}
+		for k, v := range e.TemplateMap {
+			data[k] = v
+		}
+	}
+
+	data[templateDataHardware] = map[string]interface{}{}
+	if hw != nil {
+		// The unstructured form is used so that fields are accessible in templates by their json names.
+		// For example, {{ .hardware.spec.instance.id }} instead of {{ .hardware.Spec.Instance.ID }}.
+		h, err := runtime.DefaultUnstructuredConverter.ToUnstructured(hw)
+		if err != nil {
+			return nil, fmt.Errorf("error converting hardware for use in template data: %w", err)
+		}
+		data[templateDataHardware] = h
+	}
+	data[templateDataReferences] = references
+	if vars == nil {
+		vars = map[string]string{}
+	}
+	data[templateDataVars] = vars
+
+	return data, nil
+}
+
+// renderAction renders all templated fields of an Action.
+// The returned bool is false when the rendered If field evaluates to false and the Action should not run.
+func renderAction(taskName string, a tinkerbell.Action, data map[string]interface{}) (tinkerbell.Action, bool, error) {
+	name := taskName + "/" + a.Name
+	ra := *a.DeepCopy()
+
+	cond, err := renderString(name+"/if", a.If, data)
+	if err != nil {
+		return tinkerbell.Action{}, false, err
+	}
+	if !isTrue(cond) {
+		return tinkerbell.Action{}, false, nil
+	}
+	ra.If = cond
+
+	if ra.Image, err = renderString(name+"/image", a.Image, data); err != nil {
+		return tinkerbell.Action{}, false, err
+	}
+	if _, err := reference.ParseNormalizedNamed(ra.Image); err != nil {
+		return tinkerbell.Action{}, false, fmt.Errorf("invalid action image (%s): %w", ra.Image, err)
+	}
+	if ra.Command, err = renderString(name+"/command", a.Command, data); err != nil {
+		return tinkerbell.Action{}, false, err
+	}
+	for i, arg := range a.Args {
+		if ra.Args[i], err = renderString(fmt.Sprintf("%s/args/%d", name, i), arg, data); err != nil {
+			return tinkerbell.Action{}, false, err
+		}
+	}
+	if err := renderEnv(name, ra.Env, data); err != nil {
+		return tinkerbell.Action{}, false, err
+	}
+	if ra.Volumes, err = renderVolumes(name, ra.Volumes, data); err != nil {
+		return tinkerbell.Action{}, false, err
+	}
+
+	return ra, true, nil
+}
+
+// renderEnv renders the values of env in place.
+func renderEnv(name string, env map[string]string, data map[string]interface{}) error {
+	for k, v := range env {
+		rv, err := renderString(name+"/env/"+k, v, data)
+		if err != nil {
+			return err
+		}
+		env[k] = rv
+	}
+	return nil
+}
+
+func renderVolumes(name string, vols []tinkerbell.Volume, data map[string]interface{}) ([]tinkerbell.Volume, error) {
+	if vols == nil {
+		return nil, nil
+	}
+	resp := make([]tinkerbell.Volume, 0, len(vols))
+	for i, v := range vols {
+		rv, err := renderString(fmt.Sprintf("%s/volumes/%d", name, i), string(v), data)
+		if err != nil {
+			return nil, err
+		}
+		resp = append(resp, tinkerbell.Volume(rv))
+	}
+	return resp, nil
+}
+
+// renderString renders a single templated string. Strings without template actions are returned as is.
+func renderString(name, s string, data map[string]interface{}) (string, error) {
+	if !strings.Contains(s, "{{") {
+		return s, nil
+	}
+	b, err := workflow.RenderTemplate(name, s, data)
+	if err != nil {
+		return "", fmt.Errorf("error rendering %s: %w", name, err)
+	}
+	return string(b), nil
+}
+
+// isTrue reports whether a rendered If value allows an Action to run.
+// Empty and "true" (case-insensitive) are true, everything else is false.
+func isTrue(s string) bool {
+	s = strings.TrimSpace(s)
+	return s == "" || strings.EqualFold(s, "true")
+}
+
+// mergeMaps returns a new map with all key/values from ms. Later maps take precedence.
+// nil is returned when there are no key/values.
+func mergeMaps(ms ...map[string]string) map[string]string {
+	var resp map[string]string
+	for _, m := range ms {
+		if len(m) == 0 {
+			continue
+		}
+		if resp == nil {
+			resp = make(map[string]string, len(m))
+		}
+		maps.Copy(resp, m)
+	}
+	return resp
+}
+
+// mergeVolumes returns all volumes, in order, with duplicates removed.
+func mergeVolumes(vs ...[]tinkerbell.Volume) []tinkerbell.Volume {
+	var resp []tinkerbell.Volume
+	for _, v := range vs {
+		for _, vol := range v {
+			if !slices.Contains(resp, vol) {
+				resp = append(resp, vol)
+			}
+		}
+	}
+	return resp
+}
+
+func extraEnv(e *tinkerbell.Extra) map[string]string {
+	if e == nil {
+		return nil
+	}
+	return e.Env
+}
+
+func extraVolumes(e *tinkerbell.Extra) []tinkerbell.Volume {
+	if e == nil {
+		return nil
+	}
+	return e.Volumes
+}
+
+func setRenderFailed(wf *tinkerbell.Workflow, msg string) {
+	wf.Status.SetConditionIfDifferent(tinkerbell.WorkflowCondition{
+		Type:    tinkerbell.TemplateRenderedSuccess,
+		Status:  metav1.ConditionFalse,
+		Reason:  reasonError,
+		Message: msg,
+		Time:    &metav1.Time{Time: metav1.Now().UTC()},
+	})
+}
+
+func namespaceOrDefault(ns, def string) string {
+	if ns == "" {
+		return def
+	}
+	return ns
+}
+
+func newID() types.UID {
+	return types.UID(ulid.Make().String())
+}
+
+func pointerToValue[V any](ptr *V) V {
+	if ptr == nil {
+		var zero V
+		return zero
+	}
+	return *ptr
+}
@@ -0,0 +1,166 @@
+package v1alpha2
+
+import (
+	"context"
+	"time"
+
+	"github.com/tinkerbell/tinkerbell/api/v1alpha2/tinkerbell"
+	"github.com/tinkerbell/tinkerbell/pkg/journal"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// progress rolls up the Action states, which are written by Tink Server, into the Task and Workflow metadata
+// and enforces the Task and Workflow timeouts. The returned Result requeues for the nearest timeout.
+func (r *Reconciler) progress(ctx context.Context, wf *tinkerbell.Workflow) reconcile.Result {
+	now := r.nowFunc()
+	var requeue time.Duration
+	requeueAt := func(t time.Time) {
+		if d := t.Sub(now); d > 0 && (requeue == 0 || d < requeue) {
+			requeue = d
+		}
+	}
+
+	tasks := make([]*tinkerbell.Metadata, 0, len(wf.Status.RenderedTasks))
+	for idx := range wf.Status.RenderedTasks {
+		task := &wf.Status.RenderedTasks[idx]
+		actions := make([]*tinkerbell.Metadata, 0, len(task.Actions))
+		for ai := range task.Actions {
+			actions = append(actions, &task.Actions[ai].Metadata)
+		}
+		rollup(&task.Metadata, actions, now)
+
+		// Enforce the Task timeout.
+		if idx < len(wf.Spec.Tasks) && pointerToValue(wf.Spec.Tasks[idx].TimeoutSeconds) > 0 && task.Metadata.State == tinkerbell.TaskStateRunning && task.Metadata.StartTime != nil {
+			stop := task.Metadata.StartTime.Add(time.Duration(*wf.Spec.Tasks[idx].TimeoutSeconds) * time.Second)
+			if now.After(stop) {
+				journal.Log(ctx, "task timeout reached", "task", task.Name)
+				task.Metadata.State = tinkerbell.TaskStateTimeout
+				task.Metadata.Message = "task timed out"
+				setEnd(&task.Metadata, now)
+			} else {
+				requeueAt(stop)
+			}
+		}
+		tasks = append(tasks, &task.Metadata)
+	}
+	rollup(&wf.Status.Metadata.Workflow, tasks, now)
+
+	// Enforce the Workflow timeout.
+	if wf.Status.GlobalTimeout > 0 && wf.Status.Metadata.Workflow.StartTime != nil && wf.Status.GlobalExecutionStop == nil {
+		journal.Log(ctx, "global execution times set")
+		wf.Status.GlobalExecutionStop = &metav1.Time{Time: wf.Status.Metadata.Workflow.StartTime.Add(time.Duration(wf.Status.GlobalTimeout) * time.Second)}
+	}
+	if wf.Status.GlobalExecutionStop != nil && !isDone(wf.Status.Metadata.Workflow.State) {
+		if now.After(wf.Status.GlobalExecutionStop.Time) {
+			journal.Log(ctx, "global timeout reached")
+			wf.Status.Metadata.Workflow.State = tinkerbell.WorkflowStateTimeout
+			wf.Status.Metadata.Workflow.Message = "workflow timed out"
+			setEnd(&wf.Status.Metadata.Workflow, now)
+		} else {
+			requeueAt(wf.Status.GlobalExecutionStop.Time)
+		}
+	}
+	setCurrent(wf)
+
+	if isDone(wf.Status.Metadata.Workflow.State) {
+		return reconcile.Result{}
+	}
+	return reconcile.Result{RequeueAfter: requeue}
+}
+
+// rollup sets the state and execution times of parent from its children.
+// A parent without children is successful. A failed or timed out child fails or times out the parent.
+// All children successful makes the parent successful. Otherwise the parent is running once any child
+// has started and pending before that.
+func rollup(parent *tinkerbell.Metadata, children []*tinkerbell.Metadata, now time.Time) {
+	if isDone(parent.State) {
+		return
+	}
+
+	state := tinkerbell.StateSuccess
+	started := false
+	var msg string
+	for _, c := range children {
+		if parent.StartTime == nil && c.StartTime != nil {
+			parent.StartTime = c.StartTime.DeepCopy()
+		}
+		switch c.State {
+		case tinkerbell.StateFailed, tinkerbell.StateTimeout:
+			if state != tinkerbell.StateFailed && state != tinkerbell.StateTimeout {
+				state = c.State
+				msg = c.Message
+			}
+		case tinkerbell.StateSuccess:
+			started = true
+		case tinkerbell.StateRunning:
+			started = true
+			if state == tinkerbell.StateSuccess {
+				state = tinkerbell.StateRunning
+			}
+		default:
+			if state == tinkerbell.StateSuccess {
+				state = tinkerbell.StatePending
+			}
+		}
+	}
+	if state == tinkerbell.StatePending && started {
+		state = tinkerbell.StateRunning
+	}
+
+	parent.State = state
+	if isDone(state) {
+		parent.Message = msg
+		// Use the latest end time of the children, falling back to now when none have one.
+		var end *metav1.Time
+		for _, c := range children {
+			if c.EndTime != nil && (end == nil || c.EndTime.After(end.Time)) {
+				end = c.EndTime
+			}
+		}
+		if end == nil {
+			end = &metav1.Time{Time: now}
+		}
+		setEnd(parent, end.Time)
+	}
+}
+
+// setCurrent sets the Task and Action metadata of the Workflow to the first Task and Action
+// that has not completed successfully, or the last ones when all have completed successfully.
+func setCurrent(wf *tinkerbell.Workflow) {
+	if len(wf.Status.RenderedTasks) == 0 {
+		return
+	}
+	task := &wf.Status.RenderedTasks[len(wf.Status.RenderedTasks)-1]
+	for idx := range wf.Status.RenderedTasks {
+		if wf.Status.RenderedTasks[idx].Metadata.State != tinkerbell.TaskStateSuccess {
+			task = &wf.Status.RenderedTasks[idx]
+			break
+		}
+	}
+	wf.Status.Metadata.Task = task.Metadata
+	if len(task.Actions) == 0 {
+		wf.Status.Metadata.Action = tinkerbell.Metadata{}
+		return
+	}
+	action := &task.Actions[len(task.Actions)-1]
+	for idx := range task.Actions {
+		if task.Actions[idx].Metadata.State != tinkerbell.ActionStateSuccess {
+			action = &task.Actions[idx]
+			break
+		}
+	}
+	wf.Status.Metadata.Action = action.Metadata
+}
+
+func setEnd(m *tinkerbell.Metadata, end time.Time) {
+	m.EndTime = &metav1.Time{Time: end}
+	if m.StartTime != nil {
+		m.ExecutionDuration = end.Sub(m.StartTime.Time).String()
+	}
+}
+
+// isDone reports whether s is a terminal state.
+func isDone(s tinkerbell.State) bool {
+	return s == tinkerbell.StateSuccess || s == tinkerbell.StateFailed || s == tinkerbell.StateTimeout
+}
@@ -0,0 +1,114 @@
+package v1alpha2
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tinkerbell/tinkerbell/api/v1alpha2/tinkerbell"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRollup(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 10, 0, 0, time.UTC)
+	start := metav1.NewTime(now.Add(-5 * time.Minute))
+	end := metav1.NewTime(now.Add(-time.Minute))
+
+	tests := map[string]struct {
+		parent   tinkerbell.Metadata
+		children []*tinkerbell.Metadata
+		want     tinkerbell.Metadata
+	}{
+		"no children": {
+			parent: tinkerbell.Metadata{State: tinkerbell.StatePending},
+			want: tinkerbell.Metadata{
+				State:   tinkerbell.StateSuccess,
+				EndTime: &metav1.Time{Time: now},
+			},
+		},
+		"all pending": {
+			parent:   tinkerbell.Metadata{State: tinkerbell.StatePending},
+			children: []*tinkerbell.Metadata{{State: tinkerbell.StatePending}, {State: tinkerbell.StatePending}},
+			want:     tinkerbell.Metadata{State: tinkerbell.StatePending},
+		},
+		"first running": {
+			parent:   tinkerbell.Metadata{State: tinkerbell.StatePending},
+			children: []*tinkerbell.Metadata{{State: tinkerbell.StateRunning, StartTime: &start}, {State: tinkerbell.StatePending}},
+			want:     tinkerbell.Metadata{State: tinkerbell.StateRunning, StartTime: &start},
+		},
+		"first successful": {
+			parent:   tinkerbell.Metadata{State: tinkerbell.StatePending},
+			children: []*tinkerbell.Metadata{{State: tinkerbell.StateSuccess, StartTime: &start, EndTime: &end}, {State: tinkerbell.StatePending}},
+			want:     tinkerbell.Metadata{State: tinkerbell.StateRunning, StartTime: &start},
+		},
+		"all successful": {
+			parent:   tinkerbell.Metadata{State: tinkerbell.StateRunning},
+			children: []*tinkerbell.Metadata{{State: tinkerbell.StateSuccess, StartTime: &start, EndTime: &start}, {State: tinkerbell.StateSuccess, StartTime: &start, EndTime: &end}},
+			want: tinkerbell.Metadata{
+				State:             tinkerbell.StateSuccess,
+				StartTime:         &start,
+				EndTime:           &end,
+				ExecutionDuration: "4m0s",
+			},
+		},
+		"failed": {
+			parent: tinkerbell.Metadata{State: tinkerbell.StateRunning},
+			children: []*tinkerbell.Metadata{
+				{State: tinkerbell.StateFailed, StartTime: &start, EndTime: &end, Message: "exit code 1"},
+				{State: tinkerbell.StateTimeout, Message: "timed out"},
+			},
+			want: tinkerbell.Metadata{
+				State:             tinkerbell.StateFailed,
+				StartTime:         &start,
+				EndTime:           &end,
+				ExecutionDuration: "4m0s",
+				Message:           "exit code 1",
+			},
+		},
+		"already done": {
+			parent:   tinkerbell.Metadata{State: tinkerbell.StateTimeout},
+			children: []*tinkerbell.Metadata{{State: tinkerbell.StateRunning, StartTime: &start}},
+			want:     tinkerbell.Metadata{State: tinkerbell.StateTimeout},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := tc.parent
+			rollup(&got, tc.children, now)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("unexpected metadata (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSetCurrent(t *testing.T) {
+	wf := &tinkerbell.Workflow{
+		Status: tinkerbell.WorkflowStatus{
+			RenderedTasks: []tinkerbell.TaskWithMetadata{
+				{
+					Metadata: tinkerbell.Metadata{ID: "t1", State: tinkerbell.TaskStateSuccess},
+					Actions: []tinkerbell.ActionWithMetadata{
+						{Metadata: tinkerbell.Metadata{ID: "a1", State: tinkerbell.ActionStateSuccess}},
+					},
+				},
+				{
+					Metadata: tinkerbell.Metadata{ID: "t2", State: tinkerbell.TaskStateRunning},
+					Actions: []tinkerbell.ActionWithMetadata{
+						{Metadata: tinkerbell.Metadata{ID: "a2", State: tinkerbell.ActionStateSuccess}},
+						{Metadata: tinkerbell.Metadata{ID: "a3", State: tinkerbell.ActionStateRunning}},
+					},
+				},
+			},
+		},
+	}
+
+	setCurrent(wf)
+	if wf.Status.Metadata.Task.ID != "t2" {
+		t.Errorf("unexpected current task: %v", wf.Status.Metadata.Task.ID)
+	}
+	if wf.Status.Metadata.Action.ID != "a3" {
+		t.Errorf("unexpected current action: %v", wf.Status.Metadata.Action.ID)
+	}
+}
@@ -0,0 +1,198 @@
+package workflow
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/cenkalti/backoff/v5"
+	"github.com/go-logr/logr"
+	v1alpha1 "github.com/tinkerbell/tinkerbell/api/v1alpha1/tinkerbell"
+)
+
+const (
+	// cloudEventsContentType is the content type of a CloudEvent in structured mode.
+	cloudEventsContentType = "application/cloudevents+json"
+	cloudEventsSpecVersion = "1.0"
+	// cloudEventsSource is the source of all CloudEvents posted by the Tink Controller.
+	cloudEventsSource = "tinkerbell.org/tink-controller"
+
+	defaultWebhookTimeout   = 10 * time.Second
+	defaultWebhookQueueSize = 1000
+	webhookMaxTries         = 5
+)
+
+// CloudEvent types posted to the Webhook.
+const (
+	EventTypeWorkflowStarted   = "org.tinkerbell.workflow.started"
+	EventTypeWorkflowSucceeded = "org.tinkerbell.workflow.succeeded"
+	EventTypeWorkflowFailed    = "org.tinkerbell.workflow.failed"
+	EventTypeWorkflowTimeout   = "org.tinkerbell.workflow.timeout"
+	EventTypeWorkflowCanceled  = "org.tinkerbell.workflow.canceled"
+	EventTypeActionSucceeded   = "org.tinkerbell.action.succeeded"
+	EventTypeActionFailed      = "org.tinkerbell.action.failed"
+	EventTypeActionTimeout     = "org.tinkerbell.action.timeout"
+)
+
+// Webhook posts Workflow lifecycle events to a URL as CloudEvents in structured mode.
+// Events are posted in the background, in order. An event that cannot be delivered after retrying is dropped.
+type Webhook struct {
+	URL    string
+	Client *http.Client
+	queue  chan cloudEvent
+	// retryInterval is the initial interval between retries of posting an event.
+	retryInterval time.Duration
+}
+
+// cloudEvent is a CloudEvent, https://github.com/cloudevents/spec/blob/v1.0.2/cloudevents/spec.md.
+type cloudEvent struct {
+	SpecVersion     string           `json:"specversion"`
+	ID              string           `json:"id"`
+	Source          string           `json:"source"`
+	Type            string           `json:"type"`
+	Subject         string           `json:"subject"`
+	Time            time.Time        `json:"time"`
+	DataContentType string           `json:"datacontenttype"`
+	Data            webhookEventData `json:"data"`
+}
+
+// webhookEventData is the data of the CloudEvents posted to the Webhook.
+type webhookEventData struct {
+	Workflow      string `json:"workflow"`
+	Namespace     string `json:"namespace"`
+	HardwareRef   string `json:"hardwareRef,omitempty"`
+	State         string `json:"state"`
+	PreviousState string `json:"previousState,omitempty"`
+	Task          string `json:"task,omitempty"`
+	Action        string `json:"action,omitempty"`
+	AgentID       string `json:"agentID,omitempty"`
+	Message       string `json:"message,omitempty"`
+}
+
+// NewWebhook returns a Webhook that posts to url.
+func NewWebhook(url string) *Webhook {
+	return &Webhook{
+		URL:           url,
+		Client:        &http.Client{Timeout: defaultWebhookTimeout},
+		queue:         make(chan cloudEvent, defaultWebhookQueueSize),
+		retryInterval: backoff.DefaultInitialInterval,
+	}
+}
+
+// webhookEventType returns the CloudEvent type for a transition. Not all transitions are posted to the Webhook.
+func webhookEventType(t transition) (string, bool) {
+	if t.Kind == transitionAction {
+		switch t.To {
+		case v1alpha1.WorkflowStateSuccess:
+			return EventTypeActionSucceeded, true
+		case v1alpha1.WorkflowStateFailed:
+			return EventTypeActionFailed, true
+		case v1alpha1.WorkflowStateTimeout:
+			return EventTypeActionTimeout, true
+		}
+		return "", false
+	}
+	switch t.To {
+	case v1alpha1.WorkflowStateRunning:
+		return EventTypeWorkflowStarted, true
+	case v1alpha1.WorkflowStateSuccess:
+		return EventTypeWorkflowSucceeded, true
+	case v1alpha1.WorkflowStateFailed:
+		return EventTypeWorkflowFailed, true
+	case v1alpha1.WorkflowStateTimeout:
+		return EventTypeWorkflowTimeout, true
+	case v1alpha1.WorkflowStateCanceled:
+		return EventTypeWorkflowCanceled, true
+	}
+	return "", false
+}
+
+// toCloudEvent returns the CloudEvent for a transition of a Workflow.
+// i is the index of the transition among the transitions of the same Workflow update and makes the ID unique.
+func toCloudEvent(w *v1alpha1.Workflow, i int, t transition, eventType string, now time.Time) cloudEvent {
+	return cloudEvent{
+		SpecVersion:     cloudEventsSpecVersion,
+		ID:              fmt.Sprintf("%s-%s-%d", w.UID, w.ResourceVersion, i),
+		Source:          cloudEventsSource,
+		Type:            eventType,
+		Subject:         w.Namespace + "/" + w.Name,
+		Time:            now.UTC(),
+		DataContentType: "application/json",
+		Data: webhookEventData{
+			Workflow:      w.Name,
+			Namespace:     w.Namespace,
+			HardwareRef:   w.Spec.HardwareRef,
+			State:         string(t.To),
+			PreviousState: string(t.From),
+			Task:          t.TaskName,
+			Action:        t.ActionName,
+			AgentID:       t.AgentID,
+			Message:       t.Message,
+		},
+	}
+}
+
+// enqueue queues the CloudEvent for a transition, if the transition is one that is posted.
+// Events are dropped when the queue is full so that Workflow processing is never blocked by the Webhook.
+func (wh *Webhook) enqueue(ctx context.Context, log logr.Logger, w *v1alpha1.Workflow, i int, t transition) {
+	et, ok := webhookEventType(t)
+	if !ok {
+		return
+	}
+	ev := toCloudEvent(w, i, t, et, time.Now())
+	select {
+	case <-ctx.Done():
+	case wh.queue <- ev:
+	default:
+		log.Info("webhook queue full, dropping event", "type", ev.Type, "subject", ev.Subject)
+	}
+}
+
+// run posts queued events until ctx is done.
+func (wh *Webhook) run(ctx context.Context, log logr.Logger) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev := <-wh.queue:
+			op := func() (any, error) {
+				return nil, wh.post(ctx, ev)
+			}
+			bo := backoff.NewExponentialBackOff()
+			bo.InitialInterval = wh.retryInterval
+			if _, err := backoff.Retry(ctx, op, backoff.WithBackOff(bo), backoff.WithMaxTries(webhookMaxTries)); err != nil {
+				log.Info("error posting event to webhook, dropping event", "type", ev.Type, "subject", ev.Subject, "error", err)
+			}
+		}
+	}
+}
+
+// post sends a single CloudEvent to the Webhook URL.
+func (wh *Webhook) post(ctx context.Context, ev cloudEvent) error {
+	b, err := json.Marshal(ev)
+	if err != nil {
+		return backoff.Permanent(fmt.Errorf("error marshaling event: %w", err))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, wh.URL, bytes.NewReader(b))
+	if err != nil {
+		return backoff.Permanent(fmt.Errorf("error creating request: %w", err))
+	}
+	req.Header.Set("Content-Type", cloudEventsContentType)
+	resp, err := wh.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		err := fmt.Errorf("unexpected response status: %s", resp.Status)
+		if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+			return backoff.Permanent(err)
+		}
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,131 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
+	v1alpha1 "github.com/tinkerbell/tinkerbell/api/v1alpha1/tinkerbell"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestWebhookEventType(t *testing.T) {
+	tests := map[string]struct {
+		transition transition
+		want       string
+		wantOK     bool
+	}{
+		"workflow started":  {transition: transition{Kind: transitionWorkflow, To: v1alpha1.WorkflowStateRunning}, want: EventTypeWorkflowStarted, wantOK: true},
+		"workflow success":  {transition: transition{Kind: transitionWorkflow, To: v1alpha1.WorkflowStateSuccess}, want: EventTypeWorkflowSucceeded, wantOK: true},
+		"workflow pending":  {transition: transition{Kind: transitionWorkflow, To: v1alpha1.WorkflowStatePending}},
+		"action succeeded":  {transition: transition{Kind: transitionAction, To: v1alpha1.WorkflowStateSuccess}, want: EventTypeActionSucceeded, wantOK: true},
+		"action failed":     {transition: transition{Kind: transitionAction, To: v1alpha1.WorkflowStateFailed}, want: EventTypeActionFailed, wantOK: true},
+		"action timeout":    {transition: transition{Kind: transitionAction, To: v1alpha1.WorkflowStateTimeout}, want: EventTypeActionTimeout, wantOK: true},
+		"action running":    {transition: transition{Kind: transitionAction, To: v1alpha1.WorkflowStateRunning}},
+		"workflow canceled": {transition: transition{Kind: transitionWorkflow, To: v1alpha1.WorkflowStateCanceled}, want: EventTypeWorkflowCanceled, wantOK: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, ok := webhookEventType(tt.transition)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("webhookEventType() = %q, %v, want %q, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestWebhook(t *testing.T) {
+	tests := map[string]struct {
+		statuses  []int
+		wantCalls int32
+		wantEvent bool
+	}{
+		"delivered":             {statuses: []int{http.StatusOK}, wantCalls: 1, wantEvent: true},
+		"retried on 5xx":        {statuses: []int{http.StatusServiceUnavailable, http.StatusAccepted}, wantCalls: 2, wantEvent: true},
+		"not retried on 4xx":    {statuses: []int{http.StatusBadRequest, http.StatusOK}, wantCalls: 1},
+		"dropped after retries": {statuses: []int{500, 500, 500, 500, 500, 500}, wantCalls: webhookMaxTries},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var calls atomic.Int32
+			received := make(chan cloudEvent, 1)
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := calls.Add(1)
+				if ct := r.Header.Get("Content-Type"); ct != cloudEventsContentType {
+					t.Errorf("unexpected content type: %s", ct)
+				}
+				code := tt.statuses[n-1]
+				w.WriteHeader(code)
+				if code < 300 {
+					var ev cloudEvent
+					if err := json.NewDecoder(r.Body).Decode(&ev); err != nil {
+						t.Errorf("error decoding event: %v", err)
+					}
+					received <- ev
+				}
+			}))
+			defer srv.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			wh := NewWebhook(srv.URL)
+			wh.retryInterval = time.Millisecond
+			go wh.run(ctx, logr.Discard())
+
+			wf := &v1alpha1.Workflow{
+				ObjectMeta: metav1.ObjectMeta{Name: "wf1", Namespace: "tink", UID: "1234", ResourceVersion: "7"},
+				Spec:       v1alpha1.WorkflowSpec{HardwareRef: "machine1"},
+			}
+			tr := transition{Kind: transitionAction, TaskName: "provision", ActionName: "stream", AgentID: "agent1", From: v1alpha1.WorkflowStateRunning, To: v1alpha1.WorkflowStateSuccess}
+			wh.enqueue(ctx, logr.Discard(), wf, 1, tr)
+
+			if !tt.wantEvent {
+				deadline := time.After(5 * time.Second)
+				for calls.Load() < tt.wantCalls {
+					select {
+					case <-deadline:
+						t.Fatalf("expected %d calls, got %d", tt.wantCalls, calls.Load())
+					case <-time.After(10 * time.Millisecond):
+					}
+				}
+				time.Sleep(50 * time.Millisecond)
+				if got := calls.Load(); got != tt.wantCalls {
+					t.Fatalf("expected %d calls, got %d", tt.wantCalls, got)
+				}
+				return
+			}
+
+			var got cloudEvent
+			select {
+			case got = <-received:
+			case <-ctx.Done():
+				t.Fatal("timed out waiting for event")
+			}
+			if c := calls.Load(); c != tt.wantCalls {
+				t.Errorf("expected %d calls, got %d", tt.wantCalls, c)
+			}
+			want := webhookEventData{
+				Workflow:      "wf1",
+				Namespace:     "tink",
+				HardwareRef:   "machine1",
+				State:         "SUCCESS",
+				PreviousState: "RUNNING",
+				Task:          "provision",
+				Action:        "stream",
+				AgentID:       "agent1",
+			}
+			if diff := cmp.Diff(want, got.Data); diff != "" {
+				t.Errorf("unexpected event data (-want +got):\n%s", diff)
+			}
+			if got.ID != "1234-7-1" || got.Type != EventTypeActionSucceeded || got.Subject != "tink/wf1" || got.SpecVersion != "1.0" {
+				t.Errorf("unexpected event attributes: %+v", got)
+			}
+		})
+	}
+}
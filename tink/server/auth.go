@@ -0,0 +1,34 @@
+package server
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ReadAgentTokens reads Agent bootstrap tokens, one "<agent-id> <token>" pair per line.
+// Empty lines and lines starting with # are ignored. It returns the tokens mapped to the Agent IDs, as AgentAuth.Tokens.
+func ReadAgentTokens(r io.Reader) (map[string]string, error) {
+	tokens := map[string]string{}
+	s := bufio.NewScanner(r)
+	for n := 1; s.Scan(); n++ {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("line %d: expected an agent ID and a token", n)
+		}
+		if _, ok := tokens[fields[1]]; ok {
+			return nil, fmt.Errorf("line %d: token already used by another agent", n)
+		}
+		tokens[fields[1]] = fields[0]
+	}
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("error reading agent tokens: %w", err)
+	}
+
+	return tokens, nil
+}
@@ -0,0 +1,36 @@
+package server
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestReadAgentTokens(t *testing.T) {
+	tests := map[string]struct {
+		input   string
+		want    map[string]string
+		wantErr bool
+	}{
+		"tokens": {
+			input: "# agent-id token\n3c:ec:ef:4c:4f:54 s3cr3t\n\n  machine2\tt0k3n  \n",
+			want:  map[string]string{"s3cr3t": "3c:ec:ef:4c:4f:54", "t0k3n": "machine2"},
+		},
+		"empty":           {want: map[string]string{}},
+		"missing token":   {input: "machine1\n", wantErr: true},
+		"too many fields": {input: "machine1 s3cr3t extra\n", wantErr: true},
+		"shared token":    {input: "machine1 s3cr3t\nmachine2 s3cr3t\n", wantErr: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ReadAgentTokens(strings.NewReader(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReadAgentTokens() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); !tt.wantErr && diff != "" {
+				t.Errorf("unexpected tokens (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
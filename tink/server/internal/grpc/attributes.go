@@ -0,0 +1,176 @@
+package grpc
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ccoveille/go-safecast/v2"
+	"github.com/tinkerbell/tinkerbell/api/v1alpha1/tinkerbell"
+	"github.com/tinkerbell/tinkerbell/pkg/data"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// inBandCollectionMethod is the CollectionMethod of the attributes reported by an Agent.
+const inBandCollectionMethod = "agent"
+
+// inBandAttributes converts the attributes reported by an Agent to the in-band Hardware attributes.
+// Sizes are reported by Agents rounded up to their unit (e.g. "16GB"), so the byte counts are approximate.
+func inBandAttributes(a *data.AgentAttributes, now time.Time) *tinkerbell.Attributes {
+	if a == nil {
+		return nil
+	}
+	attrs := &tinkerbell.Attributes{
+		LastUpdated:      &metav1.Time{Time: now},
+		CollectionMethod: inBandCollectionMethod,
+	}
+	if a.CPU != nil {
+		attrs.CPU = &tinkerbell.CPU{TotalCores: deref(a.CPU.TotalCores), TotalThreads: deref(a.CPU.TotalThreads)}
+		for _, p := range a.CPU.Processors {
+			if p == nil {
+				continue
+			}
+			attrs.CPU.Sockets = append(attrs.CPU.Sockets, tinkerbell.CPUSocket{
+				Vendor:       deref(p.Vendor),
+				Model:        deref(p.Model),
+				Cores:        deref(p.Cores),
+				Threads:      deref(p.Threads),
+				Capabilities: p.Capabilities,
+			})
+		}
+	}
+	if a.Memory != nil {
+		attrs.Memory = &tinkerbell.Memory{TotalBytes: parseByteSize(deref(a.Memory.Total)), UsableBytes: parseByteSize(deref(a.Memory.Usable))}
+	}
+	for _, b := range a.BlockDevices {
+		if b == nil {
+			continue
+		}
+		attrs.BlockDevices = append(attrs.BlockDevices, tinkerbell.BlockDevice{
+			Name:                   deref(b.Name),
+			ControllerType:         deref(b.ControllerType),
+			DriveType:              deref(b.DriveType),
+			Vendor:                 deref(b.Vendor),
+			Model:                  deref(b.Model),
+			SerialNumber:           deref(b.SerialNumber),
+			WWN:                    deref(b.WWN),
+			SizeBytes:              parseByteSize(deref(b.Size)),
+			PhysicalBlockSizeBytes: parseByteSize(deref(b.PhysicalBlockSize)),
+			SmartStatus:            deref(b.SmartStatus),
+		})
+	}
+	for _, n := range a.NetworkInterfaces {
+		if n == nil {
+			continue
+		}
+		port := tinkerbell.NetworkPort{
+			MAC:                 deref(n.Mac),
+			SpeedMbps:           parseSpeedMbps(deref(n.Speed)),
+			LinkStatus:          deref(n.LinkState),
+			EnabledCapabilities: n.EnabledCapabilities,
+		}
+		for _, nb := range n.LLDPNeighbors {
+			if nb == nil {
+				continue
+			}
+			port.LLDPNeighbors = append(port.LLDPNeighbors, tinkerbell.LLDPNeighbor{
+				ChassisID:         deref(nb.ChassisID),
+				PortID:            deref(nb.PortID),
+				PortDescription:   deref(nb.PortDescription),
+				SystemName:        deref(nb.SystemName),
+				SystemDescription: deref(nb.SystemDescription),
+			})
+		}
+		attrs.NetworkInterfaces = append(attrs.NetworkInterfaces, tinkerbell.NetworkInterface{Name: deref(n.Name), Ports: []tinkerbell.NetworkPort{port}})
+	}
+	for _, g := range a.GPUDevices {
+		if g == nil {
+			continue
+		}
+		attrs.GPUDevices = append(attrs.GPUDevices, tinkerbell.GPUDevice{Vendor: deref(g.Vendor), Model: deref(g.Product), Class: deref(g.Class), Driver: deref(g.Driver)})
+	}
+	for _, p := range a.PCIDevices {
+		if p == nil {
+			continue
+		}
+		attrs.PCIDevices = append(attrs.PCIDevices, tinkerbell.PCIDevice{Vendor: deref(p.Vendor), Model: deref(p.Product), Class: deref(p.Class), Driver: deref(p.Driver)})
+	}
+	if c := a.Chassis; c != nil {
+		attrs.Chassis = &tinkerbell.Chassis{Vendor: deref(c.Vendor), SerialNumber: deref(c.Serial)}
+	}
+	if b := a.Baseboard; b != nil {
+		attrs.Baseboard = &tinkerbell.Baseboard{Vendor: deref(b.Vendor), Model: deref(b.Product), SerialNumber: deref(b.SerialNumber)}
+	}
+	if b := a.BIOS; b != nil {
+		attrs.BIOS = &tinkerbell.BIOS{Vendor: deref(b.Vendor), FirmwareVersion: deref(b.Version), ReleaseDate: deref(b.ReleaseDate)}
+	}
+	if p := a.Product; p != nil {
+		attrs.Product = &tinkerbell.Product{Name: deref(p.Name), Vendor: deref(p.Vendor), SerialNumber: deref(p.SerialNumber)}
+	}
+	if t := a.TPM; t != nil {
+		// Redfish names TPM interfaces TPM1_2 and TPM2_0, use the same names in-band.
+		attrs.TPMs = []tinkerbell.TPM{{InterfaceType: tpmInterfaceType(deref(t.Version))}}
+	}
+
+	return attrs
+}
+
+// tpmInterfaceType returns the interface type of a TPM of the version, e.g. "2.0" -> "TPM2_0".
+func tpmInterfaceType(version string) string {
+	if version == "" {
+		return ""
+	}
+
+	return "TPM" + strings.ReplaceAll(version, ".", "_")
+}
+
+// byteUnits are the units of the sizes reported by Agents, in powers of 1024.
+var byteUnits = []string{"B", "KB", "MB", "GB", "TB", "PB", "EB"}
+
+// parseByteSize parses a size reported by an Agent (e.g. "16GB") to bytes. It returns 0 for a size it can't parse.
+func parseByteSize(s string) int64 {
+	for i := len(byteUnits) - 1; i >= 0; i-- {
+		num, ok := strings.CutSuffix(s, byteUnits[i])
+		if !ok {
+			continue
+		}
+		n, err := strconv.ParseInt(num, 10, 64)
+		if err != nil || n < 0 || n > (1<<63-1)>>(10*i) {
+			return 0
+		}
+		return n << (10 * i)
+	}
+
+	return 0
+}
+
+// parseSpeedMbps parses a link speed reported by an Agent (e.g. "1000Mb/s") to Mbps. It returns 0 for a speed it can't parse.
+func parseSpeedMbps(s string) uint32 {
+	multiplier := uint64(1)
+	num, ok := strings.CutSuffix(s, "Mb/s")
+	if !ok {
+		if num, ok = strings.CutSuffix(s, "Gb/s"); !ok {
+			return 0
+		}
+		multiplier = 1000
+	}
+	n, err := strconv.ParseUint(num, 10, 32)
+	if err != nil {
+		return 0
+	}
+	mbps, err := safecast.Convert[uint32](n * multiplier)
+	if err != nil {
+		return 0
+	}
+
+	return mbps
+}
+
+func deref[T any](v *T) T {
+	if v == nil {
+		var zero T
+		return zero
+	}
+
+	return *v
+}
@@ -0,0 +1,151 @@
+package grpc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tinkerbell/tinkerbell/api/v1alpha1/tinkerbell"
+	"github.com/tinkerbell/tinkerbell/pkg/data"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestInBandAttributes(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := map[string]struct {
+		attrs *data.AgentAttributes
+		want  *tinkerbell.Attributes
+	}{
+		"nil": {},
+		"empty": {
+			attrs: data.NewAgentAttributes(),
+			want: &tinkerbell.Attributes{
+				LastUpdated:      &metav1.Time{Time: now},
+				CollectionMethod: "agent",
+				CPU:              &tinkerbell.CPU{},
+				Memory:           &tinkerbell.Memory{},
+				Chassis:          &tinkerbell.Chassis{},
+				Baseboard:        &tinkerbell.Baseboard{},
+				BIOS:             &tinkerbell.BIOS{},
+				Product:          &tinkerbell.Product{},
+			},
+		},
+		"full": {
+			attrs: &data.AgentAttributes{
+				CPU: &data.CPU{
+					TotalCores:   toPtr(uint32(8)),
+					TotalThreads: toPtr(uint32(16)),
+					Processors:   []*data.Processor{{ID: toPtr(uint32(0)), Cores: toPtr(uint32(8)), Threads: toPtr(uint32(16)), Vendor: toPtr("GenuineIntel"), Model: toPtr("Xeon"), Capabilities: []string{"sse4_2"}}},
+				},
+				Memory: &data.Memory{Total: toPtr("64GB"), Usable: toPtr("63GB")},
+				BlockDevices: []*data.Block{{
+					Name:              toPtr("sda"),
+					ControllerType:    toPtr("SCSI"),
+					DriveType:         toPtr("SSD"),
+					Size:              toPtr("447GB"),
+					PhysicalBlockSize: toPtr("4KB"),
+					Vendor:            toPtr("ATA"),
+					Model:             toPtr("SAMSUNG"),
+					WWN:               toPtr("0x5002538e40000000"),
+					SerialNumber:      toPtr("S4"),
+					SmartStatus:       toPtr("passed"),
+				}},
+				NetworkInterfaces: []*data.Network{{
+					Name:                toPtr("eno1"),
+					Mac:                 toPtr("3c:ec:ef:00:00:01"),
+					Speed:               toPtr("25000Mb/s"),
+					EnabledCapabilities: []string{"tx-checksumming"},
+					LinkState:           toPtr("up"),
+					LLDPNeighbors:       []*data.LLDPNeighbor{{ChassisID: toPtr("3c:ec:ef:00:00:ff"), PortID: toPtr("Ethernet1/12"), SystemName: toPtr("tor1")}},
+				}},
+				PCIDevices: []*data.PCI{{Vendor: toPtr("Intel"), Product: toPtr("X710"), Class: toPtr("Ethernet controller"), Driver: toPtr("i40e")}},
+				GPUDevices: []*data.GPU{{Vendor: toPtr("NVIDIA"), Product: toPtr("A100"), Class: toPtr("3D controller"), Driver: toPtr("nvidia")}},
+				Chassis:    &data.Chassis{Serial: toPtr("C1"), Vendor: toPtr("Supermicro")},
+				BIOS:       &data.BIOS{Vendor: toPtr("AMI"), Version: toPtr("2.1"), ReleaseDate: toPtr("12/13/2021")},
+				Baseboard:  &data.Baseboard{Vendor: toPtr("Supermicro"), Product: toPtr("X11"), Version: toPtr("1.01"), SerialNumber: toPtr("B1")},
+				Product:    &data.Product{Name: toPtr("SYS-1029"), Vendor: toPtr("Supermicro"), SerialNumber: toPtr("P1")},
+				TPM:        &data.TPM{Version: toPtr("2.0")},
+			},
+			want: &tinkerbell.Attributes{
+				LastUpdated:      &metav1.Time{Time: now},
+				CollectionMethod: "agent",
+				CPU: &tinkerbell.CPU{
+					TotalCores:   8,
+					TotalThreads: 16,
+					Sockets:      []tinkerbell.CPUSocket{{Vendor: "GenuineIntel", Model: "Xeon", Cores: 8, Threads: 16, Capabilities: []string{"sse4_2"}}},
+				},
+				Memory: &tinkerbell.Memory{TotalBytes: 64 << 30, UsableBytes: 63 << 30},
+				BlockDevices: []tinkerbell.BlockDevice{{
+					Name:                   "sda",
+					ControllerType:         "SCSI",
+					DriveType:              "SSD",
+					Vendor:                 "ATA",
+					Model:                  "SAMSUNG",
+					SerialNumber:           "S4",
+					WWN:                    "0x5002538e40000000",
+					SizeBytes:              447 << 30,
+					PhysicalBlockSizeBytes: 4 << 10,
+					SmartStatus:            "passed",
+				}},
+				NetworkInterfaces: []tinkerbell.NetworkInterface{{
+					Name: "eno1",
+					Ports: []tinkerbell.NetworkPort{{
+						MAC:                 "3c:ec:ef:00:00:01",
+						SpeedMbps:           25000,
+						LinkStatus:          "up",
+						EnabledCapabilities: []string{"tx-checksumming"},
+						LLDPNeighbors:       []tinkerbell.LLDPNeighbor{{ChassisID: "3c:ec:ef:00:00:ff", PortID: "Ethernet1/12", SystemName: "tor1"}},
+					}},
+				}},
+				GPUDevices: []tinkerbell.GPUDevice{{Vendor: "NVIDIA", Model: "A100", Class: "3D controller", Driver: "nvidia"}},
+				PCIDevices: []tinkerbell.PCIDevice{{Vendor: "Intel", Model: "X710", Class: "Ethernet controller", Driver: "i40e"}},
+				Chassis:    &tinkerbell.Chassis{Vendor: "Supermicro", SerialNumber: "C1"},
+				Baseboard:  &tinkerbell.Baseboard{Vendor: "Supermicro", Model: "X11", SerialNumber: "B1"},
+				BIOS:       &tinkerbell.BIOS{Vendor: "AMI", FirmwareVersion: "2.1", ReleaseDate: "12/13/2021"},
+				Product:    &tinkerbell.Product{Name: "SYS-1029", Vendor: "Supermicro", SerialNumber: "P1"},
+				TPMs:       []tinkerbell.TPM{{InterfaceType: "TPM2_0"}},
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := inBandAttributes(tt.attrs, now)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("unexpected attributes (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseByteSize(t *testing.T) {
+	tests := map[string]int64{
+		"512B":  512,
+		"1KB":   1 << 10,
+		"16GB":  16 << 30,
+		"2TB":   2 << 40,
+		"":      0,
+		"16 GB": 0,
+		"16GiB": 0,
+		"-1GB":  0,
+		"8EB":   0,
+	}
+	for in, want := range tests {
+		if got := parseByteSize(in); got != want {
+			t.Errorf("parseByteSize(%q) = %d, want %d", in, got, want)
+		}
+	}
+}
+
+func TestParseSpeedMbps(t *testing.T) {
+	tests := map[string]uint32{
+		"1000Mb/s": 1000,
+		"100Gb/s":  100000,
+		"Unknown!": 0,
+		"":         0,
+	}
+	for in, want := range tests {
+		if got := parseSpeedMbps(in); got != want {
+			t.Errorf("parseSpeedMbps(%q) = %d, want %d", in, got, want)
+		}
+	}
+}
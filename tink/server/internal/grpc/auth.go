@@ -0,0 +1,133 @@
+package grpc
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"net"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// TokenMetadataKey is the gRPC metadata key Agents send their bootstrap token in, as "Bearer <token>".
+const TokenMetadataKey = "authorization"
+
+// AgentAuth authenticates Agents and rejects requests an Agent makes for another Agent.
+// An Agent is identified by the verified TLS client certificate of its connection or, without one, by its bootstrap token.
+// A client certificate identifies the Agents named by its Common Name and DNS Subject Alternative Names.
+// Agent IDs that are MAC addresses match regardless of their case and separators.
+type AgentAuth struct {
+	// Tokens are the bootstrap tokens, each mapped to the ID of the Agent it identifies.
+	Tokens map[string]string
+}
+
+// UnaryServerInterceptor rejects unary requests whose Agent ID is not the authenticated Agent's.
+func (a *AgentAuth) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		id, err := a.authenticate(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if err := id.authorize(req); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor rejects stream messages whose Agent ID is not the authenticated Agent's.
+func (a *AgentAuth) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		id, err := a.authenticate(ss.Context())
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &authorizedStream{ServerStream: ss, identity: id})
+	}
+}
+
+// identity is the names of an authenticated Agent.
+type identity []string
+
+// authorize returns a PermissionDenied error if msg is a request for an Agent that isn't id.
+// Messages without an Agent ID, like the heartbeats of a Connect stream, are left to the handlers.
+func (id identity) authorize(msg any) error {
+	r, ok := msg.(interface{ GetAgentId() string })
+	if !ok || r.GetAgentId() == "" {
+		return nil
+	}
+	for _, name := range id {
+		if sameAgent(name, r.GetAgentId()) {
+			return nil
+		}
+	}
+
+	return status.Errorf(codes.PermissionDenied, "not authorized for agent %q", r.GetAgentId())
+}
+
+// authenticate returns the identity of the Agent making the request, from its client certificate or its bootstrap token.
+func (a *AgentAuth) authenticate(ctx context.Context) (identity, error) {
+	if p, ok := peer.FromContext(ctx); ok {
+		if info, ok := p.AuthInfo.(credentials.TLSInfo); ok && len(info.State.VerifiedChains) > 0 && len(info.State.VerifiedChains[0]) > 0 {
+			cert := info.State.VerifiedChains[0][0]
+			id := identity{}
+			if cert.Subject.CommonName != "" {
+				id = append(id, cert.Subject.CommonName)
+			}
+			return append(id, cert.DNSNames...), nil
+		}
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, v := range md.Get(TokenMetadataKey) {
+		token, ok := strings.CutPrefix(v, "Bearer ")
+		if !ok {
+			continue
+		}
+		for t, agentID := range a.Tokens {
+			if subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1 {
+				return identity{agentID}, nil
+			}
+		}
+	}
+
+	return nil, status.Error(codes.Unauthenticated, "a client certificate or a bootstrap token is required")
+}
+
+// sameAgent reports whether the Agent IDs a and b are the same, as MAC addresses when both are.
+func sameAgent(a, b string) bool {
+	if a == b {
+		return true
+	}
+	ma, err := net.ParseMAC(a)
+	if err != nil {
+		return false
+	}
+	mb, err := net.ParseMAC(b)
+	if err != nil {
+		return false
+	}
+
+	return bytes.Equal(ma, mb)
+}
+
+// authorizedStream rejects received messages for another Agent than identity.
+type authorizedStream struct {
+	grpc.ServerStream
+	identity identity
+}
+
+func (s *authorizedStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+
+	return s.identity.authorize(m)
+}
@@ -0,0 +1,130 @@
+package grpc
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"testing"
+
+	"github.com/tinkerbell/tinkerbell/pkg/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// certContext returns a context for a connection with a verified client certificate for cn and dnsNames.
+func certContext(cn string, dnsNames ...string) context.Context {
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: cn}, DNSNames: dnsNames}
+	info := credentials.TLSInfo{State: tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}}
+	return peer.NewContext(context.Background(), &peer.Peer{AuthInfo: info})
+}
+
+func tokenContext(token string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(TokenMetadataKey, "Bearer "+token))
+}
+
+func TestAgentAuthUnary(t *testing.T) {
+	auth := &AgentAuth{Tokens: map[string]string{"s3cr3t": "machine1"}}
+	tests := map[string]struct {
+		ctx     context.Context
+		req     any
+		wantErr codes.Code
+	}{
+		"certificate common name": {
+			ctx: certContext("machine1"),
+			req: &proto.ActionRequest{AgentId: toPtr("machine1")},
+		},
+		"certificate dns name": {
+			ctx: certContext("agent", "machine1"),
+			req: &proto.ActionStatusRequest{AgentId: toPtr("machine1")},
+		},
+		"certificate mac address": {
+			ctx: certContext("3C-EC-EF-4C-4F-54"),
+			req: &proto.ActionRequest{AgentId: toPtr("3c:ec:ef:4c:4f:54")},
+		},
+		"certificate of another agent": {
+			ctx:     certContext("machine2"),
+			req:     &proto.ActionRequest{AgentId: toPtr("machine1")},
+			wantErr: codes.PermissionDenied,
+		},
+		"token": {
+			ctx: tokenContext("s3cr3t"),
+			req: &proto.ActionRequest{AgentId: toPtr("machine1")},
+		},
+		"token of another agent": {
+			ctx:     tokenContext("s3cr3t"),
+			req:     &proto.CheckActionRequest{AgentId: toPtr("machine2")},
+			wantErr: codes.PermissionDenied,
+		},
+		"unknown token": {
+			ctx:     tokenContext("guess"),
+			req:     &proto.ActionRequest{AgentId: toPtr("machine1")},
+			wantErr: codes.Unauthenticated,
+		},
+		"unauthenticated": {
+			ctx:     context.Background(),
+			req:     &proto.ActionRequest{AgentId: toPtr("machine1")},
+			wantErr: codes.Unauthenticated,
+		},
+		"no agent id": {
+			ctx: certContext("machine1"),
+			req: &proto.ActionRequest{},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			called := false
+			handler := func(context.Context, any) (any, error) {
+				called = true
+				return nil, nil
+			}
+			_, err := auth.UnaryServerInterceptor()(tc.ctx, tc.req, &grpc.UnaryServerInfo{}, handler)
+			if got := status.Code(err); got != tc.wantErr {
+				t.Fatalf("code = %v, want %v (error: %v)", got, tc.wantErr, err)
+			}
+			if called != (tc.wantErr == codes.OK) {
+				t.Errorf("handler called = %v, want %v", called, tc.wantErr == codes.OK)
+			}
+		})
+	}
+}
+
+// mockRecvStream is a server stream whose messages are given to RecvMsg already decoded.
+type mockRecvStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (m *mockRecvStream) Context() context.Context {
+	return m.ctx
+}
+
+func (m *mockRecvStream) RecvMsg(any) error {
+	return nil
+}
+
+func TestAgentAuthStream(t *testing.T) {
+	auth := &AgentAuth{}
+	handler := func(_ any, ss grpc.ServerStream) error {
+		if err := ss.RecvMsg(&proto.ConnectRequest{AgentId: toPtr("machine1")}); err != nil {
+			return err
+		}
+		// Heartbeats don't carry the Agent ID.
+		if err := ss.RecvMsg(&proto.ConnectRequest{}); err != nil {
+			return err
+		}
+		return ss.RecvMsg(&proto.ConnectRequest{AgentId: toPtr("machine2")})
+	}
+	err := auth.StreamServerInterceptor()(nil, &mockRecvStream{ctx: certContext("machine1")}, &grpc.StreamServerInfo{}, handler)
+	if got := status.Code(err); got != codes.PermissionDenied {
+		t.Errorf("code = %v, want %v (error: %v)", got, codes.PermissionDenied, err)
+	}
+	err = auth.StreamServerInterceptor()(nil, &mockRecvStream{ctx: context.Background()}, &grpc.StreamServerInfo{}, handler)
+	if got := status.Code(err); got != codes.Unauthenticated {
+		t.Errorf("code = %v, want %v (error: %v)", got, codes.Unauthenticated, err)
+	}
+}
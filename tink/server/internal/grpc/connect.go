@@ -0,0 +1,210 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/tinkerbell/tinkerbell/pkg/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// defaultConnectResyncInterval is how often a Connect stream looks for an Action without being notified of a Workflow change.
+const defaultConnectResyncInterval = 30 * time.Second
+
+// WorkflowWatcher notifies about Workflow changes.
+type WorkflowWatcher interface {
+	// WatchWorkflows calls notify with the Agent IDs of a Workflow every time the Workflow is added or updated.
+	WatchWorkflows(ctx context.Context, notify func(agentIDs []string)) error
+}
+
+// WatchWorkflows starts notifying the Connect streams of Agents when their Workflows change.
+// Without it, Connect streams only look for Actions every ConnectResyncInterval.
+// It must be called before the Handler serves any requests.
+func (h *Handler) WatchWorkflows(ctx context.Context, w WorkflowWatcher) error {
+	n := &agentNotifier{}
+	if err := w.WatchWorkflows(ctx, n.notify); err != nil {
+		return err
+	}
+	h.notifier = n
+
+	return nil
+}
+
+// Connect pushes Actions to an Agent as soon as they are runnable. The first request must identify the Agent,
+// later requests are heartbeats that are recorded in the status of the Agent's Hardware, or Action statuses.
+// The server acknowledges the stream with a response without an Action, an Action is only pushed once per stream.
+// Action statuses are recorded as with ReportActionStatus, each is answered with its result in the order received.
+func (h *Handler) Connect(stream grpc.BidiStreamingServer[proto.ConnectRequest, proto.ConnectResponse]) error {
+	ctx := stream.Context()
+	first, err := stream.Recv()
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if first.GetAgentId() == "" {
+		return status.Errorf(codes.InvalidArgument, "invalid Agent ID")
+	}
+	log := h.Logger.WithValues("agent", first.GetAgentId())
+	req := &proto.ActionRequest{AgentId: first.AgentId, AgentAttributes: first.GetAgentAttributes()}
+
+	wake, unsubscribe := h.notifier.subscribe(first.GetAgentId())
+	defer unsubscribe()
+	// Actions are pushed and Action statuses are answered from different goroutines.
+	var sendMu sync.Mutex
+	send := func(resp *proto.ConnectResponse) error {
+		sendMu.Lock()
+		defer sendMu.Unlock()
+		return stream.Send(resp)
+	}
+	// Later requests are heartbeats and Action statuses, receiving them is also how a closed stream is noticed.
+	recvErr := make(chan error, 1)
+	go func() {
+		for {
+			r, err := stream.Recv()
+			if err != nil {
+				recvErr <- err
+				return
+			}
+			if as := r.GetActionStatus(); as != nil {
+				if err := send(&proto.ConnectResponse{ActionStatus: h.connectActionStatus(ctx, first.GetAgentId(), as)}); err != nil {
+					recvErr <- err
+					return
+				}
+			}
+			// Heartbeats are recorded in the v1alpha1 Hardware status, which v1alpha2 backends do not have.
+			if hb := r.GetHeartbeat(); hb != nil && h.BackendV1Alpha2 == nil {
+				if err := h.recordHeartbeat(ctx, first.GetAgentId(), hb); err != nil {
+					log.V(1).Info("error recording heartbeat", "error", err)
+				}
+			}
+		}
+	}()
+
+	if err := send(&proto.ConnectResponse{}); err != nil {
+		return err
+	}
+	log.V(1).Info("agent connected")
+	interval := h.ConnectResyncInterval
+	if interval <= 0 {
+		interval = defaultConnectResyncInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	// sent is the Action last pushed. Running Actions are served again by GetAction, they must not be pushed again.
+	var sent string
+	for {
+		ar, err := h.NextAction(ctx, req)
+		switch {
+		case err == nil:
+			if key := ar.GetWorkflowId() + "/" + ar.GetTaskId() + "/" + ar.GetActionId(); key != sent {
+				if err := send(&proto.ConnectResponse{Action: ar}); err != nil {
+					return err
+				}
+				sent = key
+			}
+		case isNoAction(err):
+			sent = ""
+		default:
+			log.V(1).Info("error getting action for connected agent", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-recvErr:
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		case <-wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// connectActionStatus records an Action status received on the Connect stream of agentID and returns its result.
+// The status is authorized against the Agent the stream was opened for.
+func (h *Handler) connectActionStatus(ctx context.Context, agentID string, req *proto.ActionStatusRequest) *proto.ActionStatusResult {
+	var err error
+	if sameAgent(req.GetAgentId(), agentID) {
+		_, err = h.ReportActionStatus(ctx, req)
+	} else {
+		err = status.Errorf(codes.PermissionDenied, "not authorized for agent %q", req.GetAgentId())
+	}
+	s := status.Convert(err)
+
+	return &proto.ActionStatusResult{ActionId: req.ActionId, Code: toPtr(int32(s.Code())), Message: toPtr(s.Message())}
+}
+
+// NextAction is a single attempt of GetAction, for callers that retry when the Workflow changes again.
+// Unlike GetAction it never auto discovers or auto enrolls the Agent. An Agent being connected is not
+// the Agent asking for work, only GetAction does that.
+func (h *Handler) NextAction(ctx context.Context, req *proto.ActionRequest) (*proto.ActionResponse, error) {
+	if h.BackendV1Alpha2 != nil {
+		return h.doGetActionV1Alpha2(ctx, req)
+	}
+	return h.doGetAction(ctx, req, options{})
+}
+
+// isNoAction reports whether err is GetAction telling the Agent that there is no Action for it.
+func isNoAction(err error) bool {
+	switch status.Code(err) { //nolint:exhaustive // only the codes GetAction uses for no Action.
+	case codes.NotFound, codes.FailedPrecondition:
+		return true
+	}
+	return false
+}
+
+// agentNotifier wakes up the Connect streams of Agents. A nil *agentNotifier never wakes anyone up.
+type agentNotifier struct {
+	mu      sync.Mutex
+	streams map[string]map[chan struct{}]struct{}
+}
+
+// subscribe returns a channel that receives a value when the Workflows of agentID change, and a func that unsubscribes.
+func (n *agentNotifier) subscribe(agentID string) (<-chan struct{}, func()) {
+	if n == nil {
+		return nil, func() {}
+	}
+	ch := make(chan struct{}, 1)
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.streams == nil {
+		n.streams = map[string]map[chan struct{}]struct{}{}
+	}
+	if n.streams[agentID] == nil {
+		n.streams[agentID] = map[chan struct{}]struct{}{}
+	}
+	n.streams[agentID][ch] = struct{}{}
+
+	return ch, func() {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		delete(n.streams[agentID], ch)
+		if len(n.streams[agentID]) == 0 {
+			delete(n.streams, agentID)
+		}
+	}
+}
+
+// notify wakes up the streams of agentIDs. Notifications for a stream that has not woken up yet are coalesced.
+func (n *agentNotifier) notify(agentIDs []string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for _, id := range agentIDs {
+		for ch := range n.streams[id] {
+			select {
+			case ch <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
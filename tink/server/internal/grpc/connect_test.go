@@ -0,0 +1,272 @@
+package grpc
+
+import (
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
+	"github.com/tinkerbell/tinkerbell/api/v1alpha1/tinkerbell"
+	"github.com/tinkerbell/tinkerbell/pkg/data"
+	"github.com/tinkerbell/tinkerbell/pkg/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// mockConnectStream is a Connect server stream. The first Recv returns first, later ones return the requests
+// sent on reqs until recv is closed.
+type mockConnectStream struct {
+	grpc.ServerStream
+	ctx   context.Context
+	first *proto.ConnectRequest
+	reqs  chan *proto.ConnectRequest
+	recv  chan struct{}
+	sent  chan *proto.ConnectResponse
+	once  sync.Once
+}
+
+func (m *mockConnectStream) Context() context.Context {
+	return m.ctx
+}
+
+func (m *mockConnectStream) Recv() (*proto.ConnectRequest, error) {
+	var first *proto.ConnectRequest
+	m.once.Do(func() { first = m.first })
+	if first != nil {
+		return first, nil
+	}
+	select {
+	case r := <-m.reqs:
+		return r, nil
+	case <-m.recv:
+		return nil, io.EOF
+	}
+}
+
+func (m *mockConnectStream) Send(resp *proto.ConnectResponse) error {
+	m.sent <- resp
+	return nil
+}
+
+// lockedBackend guards the Workflow of a mockBackendReadWriter so that it can be changed while a stream is connected.
+// Workflows are read as copies, like from a real backend, so that Action statuses can be recorded while a stream looks for Actions.
+type lockedBackend struct {
+	mu sync.Mutex
+	*mockBackendReadWriter
+}
+
+func (l *lockedBackend) ListWorkflows(ctx context.Context, opts data.WorkflowFilter) ([]tinkerbell.Workflow, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	wfs, err := l.mockBackendReadWriter.ListWorkflows(ctx, opts)
+	for i := range wfs {
+		wfs[i] = *wfs[i].DeepCopy()
+	}
+	return wfs, err
+}
+
+func (l *lockedBackend) ReadWorkflow(ctx context.Context, name, namespace string) (*tinkerbell.Workflow, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	wf, err := l.mockBackendReadWriter.ReadWorkflow(ctx, name, namespace)
+	return wf.DeepCopy(), err
+}
+
+func (l *lockedBackend) UpdateWorkflow(ctx context.Context, wf *tinkerbell.Workflow, opts data.UpdateOptions) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.mockBackendReadWriter.UpdateWorkflow(ctx, wf, opts)
+}
+
+func (l *lockedBackend) setWorkflow(wf *tinkerbell.Workflow) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.workflow = wf
+}
+
+func TestConnect(t *testing.T) {
+	workflow := func(firstState tinkerbell.WorkflowState) *tinkerbell.Workflow {
+		wf := &tinkerbell.Workflow{
+			ObjectMeta: metav1.ObjectMeta{Name: "machine1", Namespace: "default"},
+			Status: tinkerbell.WorkflowStatus{
+				State: tinkerbell.WorkflowStatePending,
+				Tasks: []tinkerbell.Task{
+					{
+						ID:      "provision",
+						Name:    "provision",
+						AgentID: "agent1",
+						Actions: []tinkerbell.Action{
+							{ID: "stream", Name: "stream", State: firstState},
+							{ID: "kexec", Name: "kexec", State: tinkerbell.WorkflowStatePending},
+						},
+					},
+				},
+			},
+		}
+		if firstState == tinkerbell.WorkflowStateSuccess {
+			wf.Status.State = tinkerbell.WorkflowStateRunning
+			wf.Status.CurrentState = &tinkerbell.CurrentState{AgentID: "agent1", TaskID: "provision", ActionID: "stream", State: firstState}
+		}
+		return wf
+	}
+	backend := &lockedBackend{mockBackendReadWriter: &mockBackendReadWriter{}}
+	h := &Handler{Logger: logr.Discard(), Backend: backend, ConnectResyncInterval: time.Hour}
+	watcher := &mockWorkflowWatcher{}
+	if err := h.WatchWorkflows(context.Background(), watcher); err != nil {
+		t.Fatal(err)
+	}
+	stream := &mockConnectStream{
+		ctx:   context.Background(),
+		first: &proto.ConnectRequest{AgentId: toPtr("agent1")},
+		recv:  make(chan struct{}),
+		sent:  make(chan *proto.ConnectResponse),
+	}
+	done := make(chan error)
+	go func() { done <- h.Connect(stream) }()
+
+	next := func() string {
+		t.Helper()
+		select {
+		case resp := <-stream.sent:
+			return resp.GetAction().GetActionId()
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for a response")
+			return ""
+		}
+	}
+	var got []string
+	// The stream is acknowledged without an Action as the Agent has no Workflow yet.
+	got = append(got, next())
+	backend.setWorkflow(workflow(tinkerbell.WorkflowStatePending))
+	watcher.notify([]string{"agent1"})
+	got = append(got, next())
+	// The running Action is not pushed again.
+	watcher.notify([]string{"agent1"})
+	backend.setWorkflow(workflow(tinkerbell.WorkflowStateSuccess))
+	watcher.notify([]string{"agent2", "agent1"})
+	got = append(got, next())
+
+	close(stream.recv)
+	if err := <-done; err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	if diff := cmp.Diff([]string{"", "stream", "kexec"}, got); diff != "" {
+		t.Errorf("unexpected pushed actions (-want +got):\n%s", diff)
+	}
+}
+
+func TestConnectActionStatus(t *testing.T) {
+	backend := &lockedBackend{mockBackendReadWriter: &mockBackendReadWriter{
+		workflow: &tinkerbell.Workflow{
+			ObjectMeta: metav1.ObjectMeta{Name: "machine1", Namespace: "default"},
+			Status: tinkerbell.WorkflowStatus{
+				State: tinkerbell.WorkflowStateRunning,
+				Tasks: []tinkerbell.Task{{
+					ID:      "provision",
+					AgentID: "agent1",
+					Actions: []tinkerbell.Action{{ID: "stream", Name: "stream", State: tinkerbell.WorkflowStateRunning}},
+				}},
+			},
+		},
+	}}
+	h := &Handler{Logger: logr.Discard(), Backend: backend, ConnectResyncInterval: time.Hour}
+	stream := &mockConnectStream{
+		ctx:   context.Background(),
+		first: &proto.ConnectRequest{AgentId: toPtr("agent1")},
+		reqs:  make(chan *proto.ConnectRequest),
+		recv:  make(chan struct{}),
+		sent:  make(chan *proto.ConnectResponse),
+	}
+	done := make(chan error)
+	go func() { done <- h.Connect(stream) }()
+
+	// result sends an Action status of agentID and returns the result sent back, skipping pushed Actions.
+	result := func(agentID string) *proto.ActionStatusResult {
+		t.Helper()
+		stream.reqs <- &proto.ConnectRequest{ActionStatus: &proto.ActionStatusRequest{
+			WorkflowId:  toPtr("default/machine1"),
+			AgentId:     toPtr(agentID),
+			TaskId:      toPtr("provision"),
+			ActionId:    toPtr("stream"),
+			ActionState: proto.ActionStatusRequest_SUCCESS.Enum(),
+		}}
+		for {
+			select {
+			case resp := <-stream.sent:
+				if resp.GetActionStatus() != nil {
+					return resp.GetActionStatus()
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("timed out waiting for an action status result")
+				return nil
+			}
+		}
+	}
+
+	denied := result("agent2")
+	if codes.Code(denied.GetCode()) != codes.PermissionDenied {
+		t.Errorf("code of a status for another agent = %v, want %v", codes.Code(denied.GetCode()), codes.PermissionDenied)
+	}
+	recorded := result("agent1")
+	if codes.Code(recorded.GetCode()) != codes.OK || recorded.GetActionId() != "stream" {
+		t.Errorf("unexpected result = %v", recorded)
+	}
+
+	close(stream.recv)
+	// Drain responses pushed while the stream closes.
+	go func() {
+		for range stream.sent {
+		}
+	}()
+	if err := <-done; err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	if got := backend.updatedWorkflow.Status.Tasks[0].Actions[0].State; got != tinkerbell.WorkflowStateSuccess {
+		t.Errorf("action state = %v, want %v", got, tinkerbell.WorkflowStateSuccess)
+	}
+}
+
+func TestConnectInvalidAgent(t *testing.T) {
+	h := &Handler{Logger: logr.Discard(), Backend: &mockBackendReadWriter{}}
+	stream := &mockConnectStream{ctx: context.Background(), first: &proto.ConnectRequest{}, recv: make(chan struct{})}
+
+	err := h.Connect(stream)
+	compareErrors(t, err, status.Errorf(codes.InvalidArgument, "invalid Agent ID"))
+}
+
+func TestNextActionSkipsAutoCapabilities(t *testing.T) {
+	called := false
+	auto := &mockAutoCapabilities{
+		ListWorkflowRuleSetsFunc: func(_ context.Context, _ data.WorkflowFilter) ([]tinkerbell.WorkflowRuleSet, error) {
+			called = true
+			return nil, nil
+		},
+	}
+	h := &Handler{
+		Logger:  logr.Discard(),
+		Backend: &mockBackendReadWriter{},
+		AutoCapabilities: AutoCapabilities{
+			Enrollment: AutoEnrollment{Enabled: true, WorkflowRuleSetLister: auto, WorkflowCreator: auto},
+		},
+	}
+
+	_, err := h.NextAction(context.Background(), &proto.ActionRequest{AgentId: toPtr("agent1")})
+	compareErrors(t, err, status.Error(codes.NotFound, "no Workflows found"))
+	if called {
+		t.Error("expected NextAction not to auto enroll the agent")
+	}
+}
+
+type mockWorkflowWatcher struct {
+	notify func(agentIDs []string)
+}
+
+func (m *mockWorkflowWatcher) WatchWorkflows(_ context.Context, notify func(agentIDs []string)) error {
+	m.notify = notify
+	return nil
+}
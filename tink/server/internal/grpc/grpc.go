@@ -77,17 +77,35 @@ type HardwareUpdater interface {
 	UpdateHardware(ctx context.Context, hw *tinkerbell.Hardware, opts data.UpdateOptions) error
 }
 
+// ActionLogWriter persists the tail of the output of an Action.
+type ActionLogWriter interface {
+	WriteActionLogs(ctx context.Context, wf *tinkerbell.Workflow, actionID string, logs []byte) error
+}
+
 type HardwareCreator interface {
 	CreateHardware(ctx context.Context, hw *tinkerbell.Hardware) error
 }
 
 // Handler is a server that implements a workflow API.
 type Handler struct {
-	Logger           logr.Logger
-	Backend          Backend
+	Logger  logr.Logger
+	Backend Backend
+	// BackendV1Alpha2, when set, is used to serve Actions from v1alpha2 Workflows instead of Backend.
+	BackendV1Alpha2 BackendV1Alpha2
+	// ActionLogWriter, when set, persists the Action output streamed by Agents with StreamActionLogs.
+	ActionLogWriter  ActionLogWriter
 	NowFunc          func() time.Time
 	AutoCapabilities AutoCapabilities
 	RetryOptions     []backoff.RetryOption
+	// ConnectResyncInterval is how often a Connect stream looks for an Action without being notified of a Workflow change.
+	// Defaults to 30 seconds.
+	ConnectResyncInterval time.Duration
+	// NativeCommands are the host commands, as absolute paths or path.Match patterns, that native Actions may run.
+	// Native Actions are failed instead of served when their command is not in the list.
+	NativeCommands []string
+
+	// notifier wakes up Connect streams, it is set by WatchWorkflows.
+	notifier *agentNotifier
 
 	proto.UnimplementedWorkflowServiceServer
 }
@@ -110,6 +128,9 @@ func NewHandler(opts ...Option) *Handler {
 
 func (h *Handler) GetAction(ctx context.Context, req *proto.ActionRequest) (*proto.ActionResponse, error) {
 	operation := func() (*proto.ActionResponse, error) {
+		if h.BackendV1Alpha2 != nil {
+			return h.doGetActionV1Alpha2(ctx, req)
+		}
 		opts := options{
 			AutoCapabilities: h.AutoCapabilities,
 		}
@@ -215,53 +236,50 @@ func (h *Handler) doGetAction(ctx context.Context, req *proto.ActionRequest, opt
 		return nil, status.Error(codes.NotFound, "no Tasks found in Workflow")
 	}
 
-	var task *tinkerbell.Task
-	if isFirstAction(wf.Status.Tasks[0]) {
-		task = &wf.Status.Tasks[0]
-		journal.Log(ctx, "first Task, first Action")
-		h.resolveAndAnnotateHardware(ctx, log, hwRef, wf.Spec.HardwareRef, wf.Namespace, attrs)
-	} else {
-		for _, t := range wf.Status.Tasks {
-			// check if all actions have been run successfully in this task.
-			// if so continue to the next task.
-			if isTaskSuccessful(t) {
-				continue
-			}
-			task = &t
-			journal.Log(ctx, "found Task", "taskID", t.ID)
-			break
+	task, action, err := h.selectAction(ctx, log, &wf, req.GetAgentId(), hwRef, attrs)
+	skipped := false
+	for err == nil {
+		run, cerr := shouldRun(&wf.Status, task, action)
+		if cerr != nil {
+			journal.Log(ctx, "error evaluating Action if condition", "actionID", action.ID, "error", cerr)
+			return nil, h.failAction(ctx, &wf, req.GetAgentId(), task, action, cerr)
 		}
-		if task == nil {
-			journal.Log(ctx, "no Tasks found")
-			return nil, status.Error(codes.NotFound, "no Tasks found")
+		if run {
+			break
 		}
+		journal.Log(ctx, "skipping Action, if condition is false", "actionID", action.ID)
+		log.Info("skipping action, if condition is false", "actionID", action.ID, "actionName", action.Name)
+		action.State = tinkerbell.WorkflowStateSkipped
+		setCurrentState(&wf, req.GetAgentId(), task, action)
+		skipped = true
+		task, action, err = h.selectAction(ctx, log, &wf, req.GetAgentId(), hwRef, attrs)
 	}
-
-	if len(task.Actions) == 0 {
-		journal.Log(ctx, "no Actions found")
-		return nil, status.Error(codes.NotFound, "no Actions found")
+	if err != nil {
+		if skipped {
+			// Skipped Actions are persisted even when there is no Action to serve.
+			if isWorkflowComplete(wf.Status.Tasks) {
+				wf.Status.State = tinkerbell.WorkflowStatePost
+			}
+			if uerr := h.Backend.UpdateWorkflow(ctx, &wf, data.UpdateOptions{StatusOnly: true}); uerr != nil {
+				return nil, errors.Join(ErrBackendWrite, status.Errorf(codes.Internal, "error writing current state: %v", uerr))
+			}
+		}
+		return nil, err
 	}
 
-	action, err := resolveAction(ctx, task, wf.Status.CurrentState)
+	env, err := renderEnvironment(&wf.Status, task, action)
 	if err != nil {
-		return nil, err
+		journal.Log(ctx, "error rendering Action environment", "actionID", action.ID, "error", err)
+		return nil, h.failAction(ctx, &wf, req.GetAgentId(), task, action, err)
 	}
-	// This check goes after the action is found, so that multi task Workflows can be handled.
-	if task.AgentID != req.GetAgentId() {
-		journal.Log(ctx, "Task not assigned to Agent")
-		return nil, status.Error(codes.NotFound, "Task not assigned to Agent")
+	if err := h.allowNative(action); err != nil {
+		journal.Log(ctx, "native Action not allowed", "actionID", action.ID, "error", err)
+		return nil, h.failAction(ctx, &wf, req.GetAgentId(), task, action, err)
 	}
 
 	// update the current state
 	// populate the current state and then send the action to the client.
-	wf.Status.CurrentState = &tinkerbell.CurrentState{
-		AgentID:    req.GetAgentId(),
-		TaskID:     task.ID,
-		ActionID:   action.ID,
-		State:      action.State,
-		ActionName: action.Name,
-		TaskName:   task.Name,
-	}
+	setCurrentState(&wf, req.GetAgentId(), task, action)
 
 	if err := h.Backend.UpdateWorkflow(ctx, &wf, data.UpdateOptions{StatusOnly: true}); err != nil {
 		return nil, errors.Join(ErrBackendWrite, status.Errorf(codes.Internal, "error writing current state: %v", err))
@@ -278,18 +296,26 @@ func (h *Handler) doGetAction(ctx context.Context, req *proto.ActionRequest, opt
 		Command:    action.Command,
 		Volumes:    append(task.Volumes, action.Volumes...),
 		Environment: func() []string {
-			// add task environment variables to the action environment variables.
-			joined := map[string]string{}
-			maps.Copy(joined, task.Environment)
-			maps.Copy(joined, action.Environment)
 			resp := []string{}
-			for k, v := range joined {
+			for k, v := range env {
 				resp = append(resp, fmt.Sprintf("%s=%s", k, v))
 			}
 			sort.Strings(resp)
 			return resp
 		}(),
-		Pid: toPtr(action.Pid), //nolint:staticcheck // intentionally read the deprecated top-level Pid for backward compatibility; namespaces.pid overrides it below when set
+		Pid:       toPtr(action.Pid), //nolint:staticcheck // intentionally read the deprecated top-level Pid for backward compatibility; namespaces.pid overrides it below when set
+		OnTimeout: action.OnTimeout,
+		OnFailure: action.OnFailure,
+	}
+	if action.Type == tinkerbell.ActionTypeNative {
+		ar.Type = toPtr(proto.ActionType_ACTION_TYPE_NATIVE)
+	}
+	ar.Sandbox = sandboxToProto(action.Sandbox)
+	if action.Retries > 0 {
+		ar.Retries = toPtr(action.Retries)
+	}
+	if action.RetryDelay > 0 {
+		ar.RetryDelay = toPtr(action.RetryDelay)
 	}
 	if action.Namespaces != nil {
 		// Pass the namespace values through to the agent as-is. The container
@@ -305,11 +331,118 @@ func (h *Handler) doGetAction(ctx context.Context, req *proto.ActionRequest, opt
 		}
 	}
 
+	ar.RemainingActions = remainingActions(&wf, req.GetAgentId(), task, action)
+
 	log.Info("sending action", "action", ar, "actionID", action.ID)
 	journal.Log(ctx, "sending Action", "action", ar)
 	return ar, nil
 }
 
+// remainingActions returns the Actions of wf, assigned to agentID, that have not run yet, except action of task.
+func remainingActions(wf *tinkerbell.Workflow, agentID string, task *tinkerbell.Task, action *tinkerbell.Action) []*proto.RemainingAction {
+	var ras []*proto.RemainingAction
+	for _, t := range wf.Status.Tasks {
+		if t.AgentID != agentID {
+			continue
+		}
+		for _, a := range t.Actions {
+			if t.ID == task.ID && a.ID == action.ID {
+				continue
+			}
+			if a.State != "" && a.State != tinkerbell.WorkflowStatePending {
+				continue
+			}
+			ras = append(ras, &proto.RemainingAction{
+				TaskId:   toPtr(t.ID),
+				ActionId: toPtr(a.ID),
+				Name:     toPtr(a.Name),
+				Image:    toPtr(a.Image),
+			})
+		}
+	}
+
+	return ras
+}
+
+// failAction marks the Action and the Workflow as failed, because the Action could not be served, and persists the Workflow.
+// The returned error is the one to send to the Agent.
+func (h *Handler) failAction(ctx context.Context, wf *tinkerbell.Workflow, agentID string, task *tinkerbell.Task, action *tinkerbell.Action, err error) error {
+	action.State = tinkerbell.WorkflowStateFailed
+	action.Message = err.Error()
+	wf.Status.State = tinkerbell.WorkflowStateFailed
+	setCurrentState(wf, agentID, task, action)
+	if uerr := h.Backend.UpdateWorkflow(ctx, wf, data.UpdateOptions{StatusOnly: true}); uerr != nil {
+		return errors.Join(ErrBackendWrite, status.Errorf(codes.Internal, "error writing current state: %v", uerr))
+	}
+
+	return status.Errorf(codes.FailedPrecondition, "Action %s: %v", action.Name, err)
+}
+
+// selectAction determines the Task and Action to serve to an Agent.
+func (h *Handler) selectAction(ctx context.Context, log logr.Logger, wf *tinkerbell.Workflow, agentID string, hwRef *tinkerbell.Hardware, attrs *data.AgentAttributes) (*tinkerbell.Task, *tinkerbell.Action, error) {
+	if wf.Status.HasTaskDependencies() {
+		task, action, err := resolveDependentAction(ctx, &wf.Status, agentID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if task.ID == wf.Status.Tasks[0].ID && isFirstAction(*task) {
+			h.resolveAndAnnotateHardware(ctx, log, hwRef, wf.Spec.HardwareRef, wf.Namespace, attrs)
+		}
+		return task, action, nil
+	}
+
+	var task *tinkerbell.Task
+	if isFirstAction(wf.Status.Tasks[0]) {
+		task = &wf.Status.Tasks[0]
+		journal.Log(ctx, "first Task, first Action")
+		h.resolveAndAnnotateHardware(ctx, log, hwRef, wf.Spec.HardwareRef, wf.Namespace, attrs)
+	} else {
+		for _, t := range wf.Status.Tasks {
+			// check if all actions have been run successfully in this task.
+			// if so continue to the next task.
+			if isTaskSuccessful(t) {
+				continue
+			}
+			task = &t
+			journal.Log(ctx, "found Task", "taskID", t.ID)
+			break
+		}
+		if task == nil {
+			journal.Log(ctx, "no Tasks found")
+			return nil, nil, status.Error(codes.NotFound, "no Tasks found")
+		}
+	}
+
+	if len(task.Actions) == 0 {
+		journal.Log(ctx, "no Actions found")
+		return nil, nil, status.Error(codes.NotFound, "no Actions found")
+	}
+
+	action, err := resolveAction(ctx, task, wf.Status.CurrentState)
+	if err != nil {
+		return nil, nil, err
+	}
+	// This check goes after the action is found, so that multi task Workflows can be handled.
+	if task.AgentID != agentID {
+		journal.Log(ctx, "Task not assigned to Agent")
+		return nil, nil, status.Error(codes.NotFound, "Task not assigned to Agent")
+	}
+
+	return task, action, nil
+}
+
+// setCurrentState sets the current state of the Workflow to the given Action.
+func setCurrentState(wf *tinkerbell.Workflow, agentID string, task *tinkerbell.Task, action *tinkerbell.Action) {
+	wf.Status.CurrentState = &tinkerbell.CurrentState{
+		AgentID:    agentID,
+		TaskID:     task.ID,
+		ActionID:   action.ID,
+		State:      action.State,
+		ActionName: action.Name,
+		TaskName:   task.Name,
+	}
+}
+
 // resolveAction determines which action to serve for the given task based on the workflow's current state.
 func resolveAction(ctx context.Context, task *tinkerbell.Task, currentState *tinkerbell.CurrentState) (*tinkerbell.Action, error) {
 	switch {
@@ -337,7 +470,7 @@ func resolveAction(ctx context.Context, task *tinkerbell.Task, currentState *tin
 			journal.Log(ctx, "no current state available")
 			return nil, status.Error(codes.FailedPrecondition, "no current state available")
 		}
-		if currentState.State != tinkerbell.WorkflowStateSuccess {
+		if currentState.State != tinkerbell.WorkflowStateSuccess && currentState.State != tinkerbell.WorkflowStateSkipped {
 			journal.Log(ctx, "current Action not in success state")
 			return nil, status.Error(codes.FailedPrecondition, "current Action not in success state")
 		}
@@ -356,6 +489,39 @@ func resolveAction(ctx context.Context, task *tinkerbell.Task, currentState *tin
 	}
 }
 
+// resolveDependentAction determines which Task and Action to serve to an Agent when the Workflow's Tasks declare dependencies.
+// The first incomplete Task assigned to the Agent whose dependencies are complete is chosen.
+// The current state is not used as Tasks on different Agents run at the same time, instead the Action states of the Task are.
+func resolveDependentAction(ctx context.Context, ws *tinkerbell.WorkflowStatus, agentID string) (*tinkerbell.Task, *tinkerbell.Action, error) {
+	for ti := range ws.Tasks {
+		task := &ws.Tasks[ti]
+		if task.AgentID != agentID || task.IsComplete() {
+			continue
+		}
+		if !ws.DependenciesComplete(*task) {
+			journal.Log(ctx, "Task waiting on dependencies", "taskID", task.ID, "dependsOn", task.DependsOn)
+			continue
+		}
+		for ai := range task.Actions {
+			action := &task.Actions[ai]
+			switch action.State {
+			case tinkerbell.WorkflowStateSuccess, tinkerbell.WorkflowStateSkipped:
+				continue
+			case tinkerbell.WorkflowStatePending, tinkerbell.WorkflowStateRunning:
+				// A running Action is re-served to handle server and Agent restarts.
+				journal.Log(ctx, "found Action", "taskID", task.ID, "actionID", action.ID, "state", action.State)
+				return task, action, nil
+			default:
+				journal.Log(ctx, "Action not in a runnable state", "actionID", action.ID, "state", action.State)
+				return nil, nil, status.Errorf(codes.FailedPrecondition, "Action not in a runnable state: %s", action.State)
+			}
+		}
+	}
+
+	journal.Log(ctx, "no Tasks ready for Agent")
+	return nil, nil, status.Error(codes.NotFound, "no Tasks ready for Agent")
+}
+
 // isFirstAction checks if the Task is at the first Action.
 func isFirstAction(t tinkerbell.Task) bool {
 	if len(t.Actions) == 0 {
@@ -371,14 +537,32 @@ func isTaskSuccessful(t tinkerbell.Task) bool {
 	if len(t.Actions) == 0 {
 		return true
 	}
-	if t.Actions[len(t.Actions)-1].State == tinkerbell.WorkflowStateSuccess {
+	if last := t.Actions[len(t.Actions)-1].State; last == tinkerbell.WorkflowStateSuccess || last == tinkerbell.WorkflowStateSkipped {
 		return true
 	}
 	return false
 }
 
+// isWorkflowComplete checks if all Actions in all Tasks have completed successfully.
+func isWorkflowComplete(tasks []tinkerbell.Task) bool {
+	for _, t := range tasks {
+		if !t.IsComplete() {
+			return false
+		}
+	}
+	return true
+}
+
+// isWorkflowFailed checks if the Workflow state is a failed final state.
+func isWorkflowFailed(s tinkerbell.WorkflowState) bool {
+	return s == tinkerbell.WorkflowStateFailed || s == tinkerbell.WorkflowStateTimeout || s == tinkerbell.WorkflowStateCanceled
+}
+
 func (h *Handler) ReportActionStatus(ctx context.Context, req *proto.ActionStatusRequest) (*proto.ActionStatusResponse, error) {
 	operation := func() (*proto.ActionStatusResponse, error) {
+		if h.BackendV1Alpha2 != nil {
+			return h.doReportActionStatusV1Alpha2(ctx, req)
+		}
 		return h.doReportActionStatus(ctx, req)
 	}
 	if len(h.RetryOptions) == 0 {
@@ -393,6 +577,13 @@ func (h *Handler) ReportActionStatus(ctx context.Context, req *proto.ActionStatu
 	if err != nil {
 		return nil, err
 	}
+	// Reporting status is also a heartbeat, Agents that are not connected with Connect send no other.
+	if h.BackendV1Alpha2 == nil {
+		hb := &proto.Heartbeat{WorkflowId: req.WorkflowId, TaskId: req.TaskId, ActionId: req.ActionId}
+		if err := h.recordHeartbeat(ctx, req.GetAgentId(), hb); err != nil {
+			h.Logger.V(1).Info("error recording heartbeat", "agent", req.GetAgentId(), "error", err)
+		}
+	}
 
 	return resp, nil
 }
@@ -424,15 +615,32 @@ func (h *Handler) doReportActionStatus(ctx context.Context, req *proto.ActionSta
 				wf.Status.Tasks[ti].Actions[ai].ExecutionStop = &metav1.Time{Time: req.GetExecutionStop().AsTime()}
 				wf.Status.Tasks[ti].Actions[ai].ExecutionDuration = req.GetExecutionDuration()
 				wf.Status.Tasks[ti].Actions[ai].Message = req.GetMessage().GetMessage()
+				wf.Status.Tasks[ti].Actions[ai].Attempt = req.GetAttempt()
+				if outputs := req.GetOutputs(); len(outputs) > 0 {
+					wf.Status.Tasks[ti].Actions[ai].Outputs = maps.Clone(outputs)
+				}
+				if hook := req.GetHook(); hook != nil {
+					wf.Status.Tasks[ti].Actions[ai].Hook = &tinkerbell.ActionHook{
+						Name:    tinkerbell.ActionHookName(hook.GetName()),
+						State:   tinkerbell.WorkflowState(hook.GetState().String()),
+						Message: hook.GetMessage(),
+					}
+				}
 
 				// 4. Write the updated workflow
-				if req.GetActionState() != proto.ActionStatusRequest_SUCCESS {
+				// Tasks with dependencies can run on multiple Agents at the same time. A failed Task fails the Workflow
+				// and reports from other Agents must not move the Workflow back to running.
+				if req.GetActionState() != proto.ActionStatusRequest_SUCCESS && !isWorkflowFailed(wf.Status.State) {
 					wf.Status.State = wf.Status.Tasks[ti].Actions[ai].State
 				}
-				if len(wf.Status.Tasks) == ti+1 && len(task.Actions) == ai+1 && req.GetActionState() == proto.ActionStatusRequest_SUCCESS {
-					// This is the last action in the last task
+				if req.GetActionState() == proto.ActionStatusRequest_SUCCESS && isWorkflowComplete(wf.Status.Tasks) {
+					// This is the last action in the last task, or the last action to complete when Tasks have dependencies.
 					wf.Status.State = tinkerbell.WorkflowStatePost
 				}
+				if wf.Spec.Cancel {
+					// A canceled Workflow must not be moved back out of the canceled state by a late report from the Agent.
+					wf.Status.State = tinkerbell.WorkflowStateCanceled
+				}
 
 				// update the status current state
 				wf.Status.CurrentState = &tinkerbell.CurrentState{
@@ -454,6 +662,51 @@ func (h *Handler) doReportActionStatus(ctx context.Context, req *proto.ActionSta
 	return &proto.ActionStatusResponse{}, status.Error(codes.NotFound, "action not found")
 }
 
+// CheckAction tells the Agent whether the Action it is currently running should be canceled.
+func (h *Handler) CheckAction(ctx context.Context, req *proto.CheckActionRequest) (*proto.CheckActionResponse, error) {
+	if h.BackendV1Alpha2 != nil {
+		return h.doCheckActionV1Alpha2(ctx, req)
+	}
+	resp, err := h.doCheckAction(ctx, req)
+	if err != nil {
+		return resp, err
+	}
+
+	// Agents check for cancellation while an Action runs, which makes it the heartbeat of Agents that poll
+	// and would otherwise send none until the Action ends.
+	hb := &proto.Heartbeat{WorkflowId: req.WorkflowId, TaskId: req.TaskId, ActionId: req.ActionId}
+	if err := h.recordHeartbeat(ctx, req.GetAgentId(), hb); err != nil {
+		h.Logger.V(1).Info("error recording heartbeat", "agent", req.GetAgentId(), "error", err)
+	}
+
+	return resp, nil
+}
+
+func (h *Handler) doCheckAction(ctx context.Context, req *proto.CheckActionRequest) (*proto.CheckActionResponse, error) {
+	if req.GetWorkflowId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, errInvalidWorkflowID)
+	}
+	if req.GetTaskId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, errInvalidTaskName)
+	}
+	if req.GetActionId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, errInvalidActionName)
+	}
+	namespace, name, _ := strings.Cut(req.GetWorkflowId(), "/")
+	wf, err := h.Backend.ReadWorkflow(ctx, name, namespace)
+	if err != nil {
+		return nil, errors.Join(ErrBackendRead, status.Errorf(codes.Internal, "error getting workflow: %v", err))
+	}
+	if !hasAgentAction(wf.Status.Tasks, req.GetAgentId(), req.GetActionId()) {
+		return nil, status.Errorf(codes.PermissionDenied, "action is not assigned to agent %q", req.GetAgentId())
+	}
+	if wf.Spec.Cancel {
+		journal.Log(ctx, "Workflow canceled, telling Agent to stop Action", "workflow", req.GetWorkflowId(), "action", req.GetActionId())
+	}
+
+	return &proto.CheckActionResponse{Cancel: toPtr(wf.Spec.Cancel)}, nil
+}
+
 // resolveAndAnnotateHardware resolves the Hardware object for a Workflow and persists agent attributes
 // as an annotation. This is only called on the very first action to avoid unnecessary backend reads.
 func (h *Handler) resolveAndAnnotateHardware(ctx context.Context, log logr.Logger, hwRef *tinkerbell.Hardware, hardwareRef, namespace string, attrs *data.AgentAttributes) {
@@ -476,21 +729,35 @@ func (h *Handler) resolveAndAnnotateHardware(ctx context.Context, log logr.Logge
 	}
 }
 
-// updateHardwareWithAttributes updates the Hardware with the given attributes annotation if it doesn't already have it.
+// updateHardwareWithAttributes updates the status of the Hardware with the in-band attributes, and the Hardware
+// with the given attributes annotation if it doesn't already have the annotation.
+// The status is written first and regardless of the annotation, so that a failed status update is retried on the
+// next Workflow instead of being skipped because the annotation was already written.
 // It uses a merge-patch (rather than a full update) to avoid conflicts with other controllers that may be
 // modifying the same Hardware object concurrently (e.g. the workflow controller toggling allowPXE).
 func (h *Handler) updateHardwareWithAttributes(ctx context.Context, log logr.Logger, hw *tinkerbell.Hardware, attrs *data.AgentAttributes) error {
-	if hw == nil || attrs == nil || hw.Annotations[constant.AttributesAnnotation] != "" {
+	if hw == nil || attrs == nil {
 		return nil
 	}
 
+	// The in-band attributes are in the status, which is updated separately from the annotation.
 	// Take a snapshot before mutation so the backend can compute a minimal merge-patch.
+	now := time.Now
+	if h.NowFunc != nil {
+		now = h.NowFunc
+	}
 	original := hw.DeepCopy()
-
-	if hw.Annotations == nil {
-		hw.Annotations = make(map[string]string)
+	if hw.Status.Attributes == nil {
+		hw.Status.Attributes = &tinkerbell.HardwareAttributes{}
+	}
+	hw.Status.Attributes.InBand = inBandAttributes(attrs, now())
+	if err := h.Backend.UpdateHardware(ctx, hw, data.UpdateOptions{StatusOnly: true, PatchFrom: original}); err != nil {
+		return fmt.Errorf("error updating Hardware with in-band attributes: %w", err)
 	}
 
+	if hw.Annotations[constant.AttributesAnnotation] != "" {
+		return nil
+	}
 	a, err := json.Marshal(attrs)
 	if err != nil {
 		return fmt.Errorf("error marshaling attributes for annotation: %w", err)
@@ -499,6 +766,10 @@ func (h *Handler) updateHardwareWithAttributes(ctx context.Context, log logr.Log
 		return fmt.Errorf("agent attributes annotation exceeds %dKB limit (%d bytes)", maxAnnotationSize/1024, len(a))
 	}
 
+	original = hw.DeepCopy()
+	if hw.Annotations == nil {
+		hw.Annotations = make(map[string]string)
+	}
 	hw.Annotations[constant.AttributesAnnotation] = string(a)
 	if err := h.Backend.UpdateHardware(ctx, hw, data.UpdateOptions{PatchFrom: original}); err != nil {
 		return fmt.Errorf("error updating Hardware with attributes annotation: %w", err)
@@ -506,6 +777,7 @@ func (h *Handler) updateHardwareWithAttributes(ctx context.Context, log logr.Log
 
 	journal.Log(ctx, "updated Hardware with attributes annotation", "hardware", hw.Name)
 	log.Info("updated Hardware with attributes annotation", "hardware", hw.Name)
+
 	return nil
 }
 
@@ -65,11 +65,15 @@ func TestGetAction(t *testing.T) {
 									ID:                "stream",
 								},
 								{
-									Name:    "kexec",
-									Image:   "quay.io/tinkerbell-actions/kexec:v1.0.0",
-									Timeout: 5,
-									State:   tinkerbell.WorkflowStatePending,
-									ID:      "kexec",
+									Name:       "kexec",
+									Image:      "quay.io/tinkerbell-actions/kexec:v1.0.0",
+									Timeout:    5,
+									State:      tinkerbell.WorkflowStatePending,
+									ID:         "kexec",
+									OnTimeout:  []string{"/bin/sh", "-c", "echo timeout"},
+									OnFailure:  []string{"/bin/sh", "-c", "echo failure"},
+									Retries:    3,
+									RetryDelay: 10,
 								},
 							},
 						},
@@ -86,6 +90,10 @@ func TestGetAction(t *testing.T) {
 				Timeout:     toPtr(int64(5)),
 				Environment: []string{},
 				Pid:         new(string),
+				OnTimeout:   []string{"/bin/sh", "-c", "echo timeout"},
+				OnFailure:   []string{"/bin/sh", "-c", "echo failure"},
+				Retries:     toPtr(int64(3)),
+				RetryDelay:  toPtr(int64(10)),
 			},
 			wantErr: nil,
 		},
@@ -305,6 +313,9 @@ func TestGetAction(t *testing.T) {
 				Timeout:     toPtr(int64(300)),
 				Environment: []string{},
 				Pid:         new(string),
+				RemainingActions: []*proto.RemainingAction{
+					{TaskId: toPtr("provision"), ActionId: toPtr("kexec"), Name: toPtr("kexec"), Image: toPtr("quay.io/tinkerbell-actions/kexec:v1.0.0")},
+				},
 			},
 			wantErr: nil,
 		},
@@ -479,13 +490,261 @@ func TestGetAction(t *testing.T) {
 				return
 			}
 
-			if diff := cmp.Diff(resp, tc.want, cmpopts.IgnoreUnexported(proto.ActionResponse{}, proto.Namespaces{})); diff != "" {
+			if diff := cmp.Diff(resp, tc.want, cmpopts.IgnoreUnexported(proto.ActionResponse{}, proto.Namespaces{}, proto.RemainingAction{})); diff != "" {
 				t.Errorf("unexpected difference:\n%v", diff)
 			}
 		})
 	}
 }
 
+// dependentTasksWorkflow returns a Workflow where two control plane Tasks, on different Agents,
+// depend on an init Task and a final Task depends on both control plane Tasks.
+func dependentTasksWorkflow(modifiers ...func(*tinkerbell.WorkflowStatus)) *tinkerbell.Workflow {
+	wf := &tinkerbell.Workflow{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default"},
+		Status: tinkerbell.WorkflowStatus{
+			State:   tinkerbell.WorkflowStateRunning,
+			AgentID: "agent1",
+			CurrentState: &tinkerbell.CurrentState{
+				AgentID:  "agent2",
+				TaskID:   "cp2",
+				ActionID: "cp2-join",
+				State:    tinkerbell.WorkflowStateRunning,
+			},
+			Tasks: []tinkerbell.Task{
+				{
+					Name: "init", ID: "init", AgentID: "agent1",
+					Actions: []tinkerbell.Action{{ID: "init-run", Name: "init", Image: "init", State: tinkerbell.WorkflowStateSuccess}},
+				},
+				{
+					Name: "cp2", ID: "cp2", AgentID: "agent2", DependsOn: []string{"init"},
+					Actions: []tinkerbell.Action{{ID: "cp2-join", Name: "join", Image: "kubeadm", State: tinkerbell.WorkflowStateRunning}},
+				},
+				{
+					Name: "cp3", ID: "cp3", AgentID: "agent3", DependsOn: []string{"init"},
+					Actions: []tinkerbell.Action{{ID: "cp3-join", Name: "join", Image: "kubeadm", State: tinkerbell.WorkflowStatePending}},
+				},
+				{
+					Name: "finish", ID: "finish", AgentID: "agent1", DependsOn: []string{"cp2", "cp3"},
+					Actions: []tinkerbell.Action{{ID: "finish-run", Name: "finish", Image: "finish", State: tinkerbell.WorkflowStatePending}},
+				},
+			},
+		},
+	}
+	for _, m := range modifiers {
+		m(&wf.Status)
+	}
+	return wf
+}
+
+func TestGetActionTaskDependencies(t *testing.T) {
+	cases := map[string]struct {
+		workflow   *tinkerbell.Workflow
+		agentID    string
+		wantAction string
+		wantErr    error
+	}{
+		"task runs while another agent's task is running": {
+			workflow:   dependentTasksWorkflow(),
+			agentID:    "agent3",
+			wantAction: "cp3-join",
+		},
+		"running action is re-served": {
+			workflow:   dependentTasksWorkflow(),
+			agentID:    "agent2",
+			wantAction: "cp2-join",
+		},
+		"task waits on its dependencies": {
+			workflow: dependentTasksWorkflow(),
+			agentID:  "agent1",
+			wantErr:  status.Error(codes.NotFound, "no Tasks ready for Agent"),
+		},
+		"task runs once its dependencies are complete": {
+			workflow: dependentTasksWorkflow(func(ws *tinkerbell.WorkflowStatus) {
+				ws.Tasks[1].Actions[0].State = tinkerbell.WorkflowStateSuccess
+				ws.Tasks[2].Actions[0].State = tinkerbell.WorkflowStateSuccess
+			}),
+			agentID:    "agent1",
+			wantAction: "finish-run",
+		},
+		"failed action is not served": {
+			workflow: dependentTasksWorkflow(func(ws *tinkerbell.WorkflowStatus) {
+				ws.Tasks[2].Actions[0].State = tinkerbell.WorkflowStateFailed
+			}),
+			agentID: "agent3",
+			wantErr: status.Error(codes.FailedPrecondition, "Action not in a runnable state: FAILED"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			server := &Handler{
+				Logger:       logr.Discard(),
+				Backend:      &mockBackendReadWriter{workflow: tc.workflow},
+				RetryOptions: []backoff.RetryOption{backoff.WithMaxTries(1)},
+			}
+
+			resp, err := server.GetAction(context.Background(), &proto.ActionRequest{AgentId: toPtr(tc.agentID)})
+			compareErrors(t, err, tc.wantErr)
+			if got := resp.GetActionId(); got != tc.wantAction {
+				t.Errorf("unexpected action: got %q, want %q", got, tc.wantAction)
+			}
+		})
+	}
+}
+
+func TestReportActionStatusTaskDependencies(t *testing.T) {
+	cases := map[string]struct {
+		workflow  *tinkerbell.Workflow
+		request   *proto.ActionStatusRequest
+		wantState tinkerbell.WorkflowState
+	}{
+		"workflow is not complete until all tasks are": {
+			workflow: dependentTasksWorkflow(),
+			request: &proto.ActionStatusRequest{
+				WorkflowId:  toPtr("default/cluster"),
+				AgentId:     toPtr("agent2"),
+				TaskId:      toPtr("cp2"),
+				ActionId:    toPtr("cp2-join"),
+				ActionState: toPtr(proto.ActionStatusRequest_SUCCESS),
+			},
+			wantState: tinkerbell.WorkflowStateRunning,
+		},
+		"last action to complete moves workflow to post": {
+			workflow: dependentTasksWorkflow(func(ws *tinkerbell.WorkflowStatus) {
+				ws.Tasks[1].Actions[0].State = tinkerbell.WorkflowStateSuccess
+				ws.Tasks[3].Actions[0].State = tinkerbell.WorkflowStateSuccess
+			}),
+			request: &proto.ActionStatusRequest{
+				WorkflowId:  toPtr("default/cluster"),
+				AgentId:     toPtr("agent3"),
+				TaskId:      toPtr("cp3"),
+				ActionId:    toPtr("cp3-join"),
+				ActionState: toPtr(proto.ActionStatusRequest_SUCCESS),
+			},
+			wantState: tinkerbell.WorkflowStatePost,
+		},
+		"report from another agent does not resume failed workflow": {
+			workflow: dependentTasksWorkflow(func(ws *tinkerbell.WorkflowStatus) {
+				ws.State = tinkerbell.WorkflowStateFailed
+			}),
+			request: &proto.ActionStatusRequest{
+				WorkflowId:  toPtr("default/cluster"),
+				AgentId:     toPtr("agent3"),
+				TaskId:      toPtr("cp3"),
+				ActionId:    toPtr("cp3-join"),
+				ActionState: toPtr(proto.ActionStatusRequest_RUNNING),
+			},
+			wantState: tinkerbell.WorkflowStateFailed,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			handler := &Handler{
+				Backend:      &mockBackendReadWriter{workflow: tc.workflow},
+				RetryOptions: []backoff.RetryOption{backoff.WithMaxTries(1)},
+			}
+			if _, err := handler.ReportActionStatus(context.Background(), tc.request); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tc.workflow.Status.State != tc.wantState {
+				t.Errorf("unexpected workflow state: got %v, want %v", tc.workflow.Status.State, tc.wantState)
+			}
+		})
+	}
+}
+
+func TestGetActionIf(t *testing.T) {
+	newWorkflow := func(secondIf, thirdIf string) *tinkerbell.Workflow {
+		return &tinkerbell.Workflow{
+			ObjectMeta: metav1.ObjectMeta{Name: "machine1", Namespace: "default"},
+			Status: tinkerbell.WorkflowStatus{
+				State: tinkerbell.WorkflowStateRunning,
+				CurrentState: &tinkerbell.CurrentState{
+					AgentID:  "machine-mac-1",
+					TaskID:   "provision",
+					ActionID: "wipe",
+					State:    tinkerbell.WorkflowStateSuccess,
+				},
+				Tasks: []tinkerbell.Task{
+					{
+						Name: "provision", ID: "provision", AgentID: "machine-mac-1",
+						Actions: []tinkerbell.Action{
+							{ID: "wipe", Name: "wipe", Image: "wipe", State: tinkerbell.WorkflowStateSuccess},
+							{ID: "stream", Name: "stream", Image: "stream", State: tinkerbell.WorkflowStatePending, If: secondIf},
+							{ID: "kexec", Name: "kexec", Image: "kexec", State: tinkerbell.WorkflowStatePending, If: thirdIf},
+						},
+					},
+				},
+			},
+		}
+	}
+	cases := map[string]struct {
+		workflow          *tinkerbell.Workflow
+		wantAction        string
+		wantErr           error
+		wantActionStates  []tinkerbell.WorkflowState
+		wantWorkflowState tinkerbell.WorkflowState
+	}{
+		"condition on prior action is true": {
+			workflow:          newWorkflow(`[[ eq .actions.wipe.state "SUCCESS" ]]`, ""),
+			wantAction:        "stream",
+			wantActionStates:  []tinkerbell.WorkflowState{tinkerbell.WorkflowStateSuccess, tinkerbell.WorkflowStatePending, tinkerbell.WorkflowStatePending},
+			wantWorkflowState: tinkerbell.WorkflowStateRunning,
+		},
+		"false condition is skipped": {
+			workflow:          newWorkflow("false", ""),
+			wantAction:        "kexec",
+			wantActionStates:  []tinkerbell.WorkflowState{tinkerbell.WorkflowStateSuccess, tinkerbell.WorkflowStateSkipped, tinkerbell.WorkflowStatePending},
+			wantWorkflowState: tinkerbell.WorkflowStateRunning,
+		},
+		"skipping the last actions completes the workflow": {
+			workflow:          newWorkflow("false", `[[ eq .actions.stream.state "SUCCESS" ]]`),
+			wantErr:           status.Error(codes.NotFound, "no Tasks found"),
+			wantActionStates:  []tinkerbell.WorkflowState{tinkerbell.WorkflowStateSuccess, tinkerbell.WorkflowStateSkipped, tinkerbell.WorkflowStateSkipped},
+			wantWorkflowState: tinkerbell.WorkflowStatePost,
+		},
+		"invalid condition fails the workflow": {
+			workflow:          newWorkflow(`[[ .actions.missing.state ]]`, ""),
+			wantErr:           status.Error(codes.FailedPrecondition, `Action stream: error evaluating if condition: template: stream:1:11: executing "stream" at <.actions.missing.state>: map has no entry for key "missing"`),
+			wantActionStates:  []tinkerbell.WorkflowState{tinkerbell.WorkflowStateSuccess, tinkerbell.WorkflowStateFailed, tinkerbell.WorkflowStatePending},
+			wantWorkflowState: tinkerbell.WorkflowStateFailed,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			backend := &mockBackendReadWriter{workflow: tc.workflow}
+			server := &Handler{
+				Logger:       logr.Discard(),
+				Backend:      backend,
+				RetryOptions: []backoff.RetryOption{backoff.WithMaxTries(1)},
+			}
+
+			resp, err := server.GetAction(context.Background(), &proto.ActionRequest{AgentId: toPtr("machine-mac-1")})
+			compareErrors(t, err, tc.wantErr)
+			if got := resp.GetActionId(); got != tc.wantAction {
+				t.Errorf("unexpected action: got %q, want %q", got, tc.wantAction)
+			}
+			updated := backend.updatedWorkflow
+			if updated == nil {
+				t.Fatal("expected the workflow to be updated")
+			}
+			got := []tinkerbell.WorkflowState{}
+			for _, a := range updated.Status.Tasks[0].Actions {
+				got = append(got, a.State)
+			}
+			if diff := cmp.Diff(tc.wantActionStates, got); diff != "" {
+				t.Errorf("unexpected action states (-want +got):\n%s", diff)
+			}
+			if updated.Status.State != tc.wantWorkflowState {
+				t.Errorf("unexpected workflow state: got %v, want %v", updated.Status.State, tc.wantWorkflowState)
+			}
+		})
+	}
+}
+
 // compareErrors is a helper function for comparing an error value and a desired error.
 func compareErrors(t *testing.T, got, want error) {
 	t.Helper()
@@ -509,7 +768,8 @@ type mockBackendReadWriter struct {
 	hardwareErr error
 
 	updatedHardware *tinkerbell.Hardware // captures the hardware passed to UpdateHardware
-	updateOpts      data.UpdateOptions   // captures the options passed to UpdateHardware
+	updateOpts      []data.UpdateOptions // captures the options passed to each UpdateHardware call
+	updatedWorkflow *tinkerbell.Workflow // captures the workflow passed to UpdateWorkflow
 }
 
 func (m *mockBackendReadWriter) ReadWorkflow(_ context.Context, _ string, _ string) (*tinkerbell.Workflow, error) {
@@ -526,7 +786,8 @@ func (m *mockBackendReadWriter) ListWorkflows(_ context.Context, _ data.Workflow
 	return []tinkerbell.Workflow{}, nil
 }
 
-func (m *mockBackendReadWriter) UpdateWorkflow(_ context.Context, _ *tinkerbell.Workflow, _ data.UpdateOptions) error {
+func (m *mockBackendReadWriter) UpdateWorkflow(_ context.Context, wf *tinkerbell.Workflow, _ data.UpdateOptions) error {
+	m.updatedWorkflow = wf
 	return m.writeErr
 }
 
@@ -552,7 +813,7 @@ func (m *mockBackendReadWriter) FilterHardware(_ context.Context, _ data.Hardwar
 
 func (m *mockBackendReadWriter) UpdateHardware(_ context.Context, hw *tinkerbell.Hardware, opts data.UpdateOptions) error {
 	m.updatedHardware = hw
-	m.updateOpts = opts
+	m.updateOpts = append(m.updateOpts, opts)
 	return nil
 }
 
@@ -592,6 +853,7 @@ func TestGetActionHardwareAttributes(t *testing.T) {
 		hardware       *tinkerbell.Hardware
 		request        *proto.ActionRequest
 		wantAnnotation bool
+		wantStatusOnly bool
 		wantNoHWUpdate bool
 	}{
 		"first action with HardwareRef and no existing annotation": {
@@ -621,7 +883,7 @@ func TestGetActionHardwareAttributes(t *testing.T) {
 			},
 			wantNoHWUpdate: true,
 		},
-		"first action with HardwareRef and existing annotation": {
+		"first action with HardwareRef and existing annotation only updates the status": {
 			workflow: baseWorkflow("my-hw"),
 			hardware: &tinkerbell.Hardware{
 				ObjectMeta: metav1.ObjectMeta{
@@ -636,7 +898,7 @@ func TestGetActionHardwareAttributes(t *testing.T) {
 				AgentId:         toPtr("machine-mac-1"),
 				AgentAttributes: &proto.AgentAttributes{Cpu: &proto.CPU{TotalCores: toPtr(uint32(4))}},
 			},
-			wantNoHWUpdate: true,
+			wantStatusOnly: true,
 		},
 		"first action with no HardwareRef": {
 			workflow: baseWorkflow(""),
@@ -721,15 +983,32 @@ func TestGetActionHardwareAttributes(t *testing.T) {
 
 			_, _ = server.GetAction(context.Background(), tc.request)
 
-			if tc.wantAnnotation {
+			if tc.wantAnnotation || tc.wantStatusOnly {
 				if mock.updatedHardware == nil {
-					t.Fatal("expected Hardware to be updated with attributes annotation, but UpdateHardware was not called")
+					t.Fatal("expected Hardware to be updated with attributes, but UpdateHardware was not called")
+				}
+				// The status is written first, so a failed status update is retried even when the annotation exists.
+				if len(mock.updateOpts) == 0 || !mock.updateOpts[0].StatusOnly || mock.updateOpts[0].PatchFrom == nil {
+					t.Fatalf("expected the first update to be a status merge-patch, got %+v", mock.updateOpts)
 				}
+				if a := mock.updatedHardware.Status.Attributes; a == nil || a.InBand == nil {
+					t.Fatal("expected the in-band attributes to be set with a status update")
+				}
+			}
+			if tc.wantAnnotation {
 				if mock.updatedHardware.Annotations[constant.AttributesAnnotation] == "" {
 					t.Fatal("expected attributes annotation to be set, but it was empty")
 				}
-				if mock.updateOpts.PatchFrom == nil {
-					t.Fatal("expected PatchFrom to be set in UpdateOptions for merge-patch, but it was nil")
+				if len(mock.updateOpts) != 2 || mock.updateOpts[1].StatusOnly || mock.updateOpts[1].PatchFrom == nil {
+					t.Fatalf("expected the annotation to be written with a merge-patch after the status, got %+v", mock.updateOpts)
+				}
+			}
+			if tc.wantStatusOnly {
+				if len(mock.updateOpts) != 1 {
+					t.Fatalf("expected only the status to be updated, got %+v", mock.updateOpts)
+				}
+				if got := mock.updatedHardware.Annotations[constant.AttributesAnnotation]; got != `{"cpu":{}}` {
+					t.Fatalf("expected the existing attributes annotation to be kept, got %q", got)
 				}
 			}
 			if tc.wantNoHWUpdate {
@@ -743,12 +1022,167 @@ func TestGetActionHardwareAttributes(t *testing.T) {
 
 func TestReportActionStatus(t *testing.T) {
 	tests := map[string]struct {
-		request      *proto.ActionStatusRequest
-		workflow     *tinkerbell.Workflow
-		writeErr     error
-		expectedResp *proto.ActionStatusResponse
-		expectedErr  error
+		request         *proto.ActionStatusRequest
+		workflow        *tinkerbell.Workflow
+		writeErr        error
+		expectedResp    *proto.ActionStatusResponse
+		expectedErr     error
+		expectedHook    *tinkerbell.ActionHook
+		expectedAttempt int64
+		expectedState   tinkerbell.WorkflowState
+		expectedOutputs map[string]string
 	}{
+		"success with outputs": {
+			request: &proto.ActionStatusRequest{
+				WorkflowId:        toPtr("default/workflow1"),
+				TaskId:            toPtr("task1"),
+				ActionId:          toPtr("action1"),
+				ActionState:       toPtr(proto.ActionStatusRequest_SUCCESS),
+				ExecutionStart:    timestamppb.New(time.Now()),
+				ExecutionDuration: toPtr("5s"),
+				Message: &proto.ActionMessage{
+					Message: toPtr("action completed"),
+				},
+				Outputs: map[string]string{"device": "/dev/nvme0n1"},
+			},
+			workflow: &tinkerbell.Workflow{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "workflow1",
+					Namespace: "default",
+				},
+				Status: tinkerbell.WorkflowStatus{
+					Tasks: []tinkerbell.Task{
+						{
+							ID: "task1",
+							Actions: []tinkerbell.Action{
+								{
+									ID:    "action1",
+									State: tinkerbell.WorkflowStateRunning,
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedResp:    &proto.ActionStatusResponse{},
+			expectedOutputs: map[string]string{"device": "/dev/nvme0n1"},
+		},
+		"late report on canceled workflow": {
+			request: &proto.ActionStatusRequest{
+				WorkflowId:        toPtr("default/workflow1"),
+				TaskId:            toPtr("task1"),
+				ActionId:          toPtr("action1"),
+				ActionState:       toPtr(proto.ActionStatusRequest_RUNNING),
+				ExecutionStart:    timestamppb.New(time.Now()),
+				ExecutionDuration: toPtr("0s"),
+				Message: &proto.ActionMessage{
+					Message: toPtr("running action"),
+				},
+			},
+			workflow: &tinkerbell.Workflow{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "workflow1",
+					Namespace: "default",
+				},
+				Spec: tinkerbell.WorkflowSpec{Cancel: true},
+				Status: tinkerbell.WorkflowStatus{
+					State: tinkerbell.WorkflowStateCanceled,
+					Tasks: []tinkerbell.Task{
+						{
+							ID: "task1",
+							Actions: []tinkerbell.Action{
+								{
+									ID:    "action1",
+									State: tinkerbell.WorkflowStatePending,
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedResp:  &proto.ActionStatusResponse{},
+			expectedState: tinkerbell.WorkflowStateCanceled,
+		},
+		"failed attempt being retried": {
+			request: &proto.ActionStatusRequest{
+				WorkflowId:        toPtr("default/workflow1"),
+				TaskId:            toPtr("task1"),
+				ActionId:          toPtr("action1"),
+				ActionState:       toPtr(proto.ActionStatusRequest_RUNNING),
+				ExecutionStart:    timestamppb.New(time.Now()),
+				ExecutionDuration: toPtr("30s"),
+				Message: &proto.ActionMessage{
+					Message: toPtr("attempt 1 of 3 failed, retrying"),
+				},
+				Attempt: toPtr(int64(1)),
+			},
+			workflow: &tinkerbell.Workflow{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "workflow1",
+					Namespace: "default",
+				},
+				Status: tinkerbell.WorkflowStatus{
+					Tasks: []tinkerbell.Task{
+						{
+							ID: "task1",
+							Actions: []tinkerbell.Action{
+								{
+									ID:      "action1",
+									State:   tinkerbell.WorkflowStateRunning,
+									Retries: 3,
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedResp:    &proto.ActionStatusResponse{},
+			expectedAttempt: 1,
+		},
+		"failure with hook": {
+			request: &proto.ActionStatusRequest{
+				WorkflowId:        toPtr("default/workflow1"),
+				TaskId:            toPtr("task1"),
+				ActionId:          toPtr("action1"),
+				ActionState:       toPtr(proto.ActionStatusRequest_FAILED),
+				ExecutionStart:    timestamppb.New(time.Now()),
+				ExecutionDuration: toPtr("30s"),
+				Message: &proto.ActionMessage{
+					Message: toPtr("action completed"),
+				},
+				Hook: &proto.ActionHook{
+					Name:    toPtr("on-failure"),
+					State:   toPtr(proto.ActionStatusRequest_SUCCESS),
+					Message: toPtr("hook completed"),
+				},
+			},
+			workflow: &tinkerbell.Workflow{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "workflow1",
+					Namespace: "default",
+				},
+				Status: tinkerbell.WorkflowStatus{
+					Tasks: []tinkerbell.Task{
+						{
+							ID: "task1",
+							Actions: []tinkerbell.Action{
+								{
+									ID:        "action1",
+									State:     tinkerbell.WorkflowStateRunning,
+									OnFailure: []string{"/bin/cleanup"},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedResp: &proto.ActionStatusResponse{},
+			expectedHook: &tinkerbell.ActionHook{
+				Name:    tinkerbell.ActionHookOnFailure,
+				State:   tinkerbell.WorkflowStateSuccess,
+				Message: "hook completed",
+			},
+		},
 		"success": {
 			request: &proto.ActionStatusRequest{
 				WorkflowId:        toPtr("default/workflow1"),
@@ -843,6 +1277,149 @@ func TestReportActionStatus(t *testing.T) {
 			} else if err != nil {
 				t.Errorf("unexpected error: %v", err)
 			}
+
+			if diff := cmp.Diff(tc.expectedHook, tc.workflow.Status.Tasks[0].Actions[0].Hook); diff != "" {
+				t.Errorf("unexpected hook (-want +got):\n%s", diff)
+			}
+			if got := tc.workflow.Status.Tasks[0].Actions[0].Attempt; got != tc.expectedAttempt {
+				t.Errorf("unexpected attempt: got %d, want %d", got, tc.expectedAttempt)
+			}
+			if tc.expectedState != "" && tc.workflow.Status.State != tc.expectedState {
+				t.Errorf("unexpected workflow state: got %v, want %v", tc.workflow.Status.State, tc.expectedState)
+			}
+			if diff := cmp.Diff(tc.expectedOutputs, tc.workflow.Status.Tasks[0].Actions[0].Outputs); diff != "" {
+				t.Errorf("unexpected outputs (-want +got):\n%s", diff)
+			}
 		})
 	}
 }
+
+func TestCheckAction(t *testing.T) {
+	workflow := func(cancel bool) *tinkerbell.Workflow {
+		return &tinkerbell.Workflow{
+			ObjectMeta: metav1.ObjectMeta{Name: "workflow1", Namespace: "default"},
+			Spec:       tinkerbell.WorkflowSpec{Cancel: cancel},
+			Status: tinkerbell.WorkflowStatus{
+				Tasks: []tinkerbell.Task{
+					{ID: "task1", AgentID: "agent1", Actions: []tinkerbell.Action{{ID: "action1"}}},
+				},
+			},
+		}
+	}
+	tests := map[string]struct {
+		request      *proto.CheckActionRequest
+		workflow     *tinkerbell.Workflow
+		expectedResp *proto.CheckActionResponse
+		expectedErr  error
+	}{
+		"not canceled": {
+			request: &proto.CheckActionRequest{
+				WorkflowId: toPtr("default/workflow1"),
+				TaskId:     toPtr("task1"),
+				ActionId:   toPtr("action1"),
+				AgentId:    toPtr("agent1"),
+			},
+			workflow:     workflow(false),
+			expectedResp: &proto.CheckActionResponse{Cancel: toPtr(false)},
+		},
+		"canceled": {
+			request: &proto.CheckActionRequest{
+				WorkflowId: toPtr("default/workflow1"),
+				TaskId:     toPtr("task1"),
+				ActionId:   toPtr("action1"),
+				AgentId:    toPtr("agent1"),
+			},
+			workflow:     workflow(true),
+			expectedResp: &proto.CheckActionResponse{Cancel: toPtr(true)},
+		},
+		"action of another agent": {
+			request: &proto.CheckActionRequest{
+				WorkflowId: toPtr("default/workflow1"),
+				TaskId:     toPtr("task1"),
+				ActionId:   toPtr("action1"),
+				AgentId:    toPtr("agent2"),
+			},
+			workflow:    workflow(true),
+			expectedErr: status.Errorf(codes.PermissionDenied, "action is not assigned to agent %q", "agent2"),
+		},
+		"missing agent id": {
+			request: &proto.CheckActionRequest{
+				WorkflowId: toPtr("default/workflow1"),
+				TaskId:     toPtr("task1"),
+				ActionId:   toPtr("action1"),
+			},
+			workflow:    workflow(true),
+			expectedErr: status.Errorf(codes.PermissionDenied, "action is not assigned to agent %q", ""),
+		},
+		"missing workflow id": {
+			request: &proto.CheckActionRequest{
+				TaskId:   toPtr("task1"),
+				ActionId: toPtr("action1"),
+			},
+			expectedErr: status.Errorf(codes.InvalidArgument, errInvalidWorkflowID),
+		},
+		"missing action id": {
+			request: &proto.CheckActionRequest{
+				WorkflowId: toPtr("default/workflow1"),
+				TaskId:     toPtr("task1"),
+			},
+			expectedErr: status.Errorf(codes.InvalidArgument, errInvalidActionName),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			handler := &Handler{
+				Backend: &mockBackendReadWriter{workflow: tc.workflow},
+			}
+
+			resp, err := handler.CheckAction(context.Background(), tc.request)
+			if diff := cmp.Diff(tc.expectedResp, resp, protocmp.Transform()); diff != "" {
+				t.Errorf("unexpected response (-want +got):\n%s", diff)
+			}
+			compareErrors(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestRemainingActions(t *testing.T) {
+	wf := &tinkerbell.Workflow{
+		Status: tinkerbell.WorkflowStatus{
+			Tasks: []tinkerbell.Task{
+				{
+					ID:      "provision",
+					AgentID: "machine-mac-1",
+					Actions: []tinkerbell.Action{
+						{ID: "stream", Name: "stream", Image: "image2disk", State: tinkerbell.WorkflowStateSuccess},
+						{ID: "cexec", Name: "cexec", Image: "cexec", State: tinkerbell.WorkflowStateRunning},
+						{ID: "skip", Name: "skip", Image: "skip", State: tinkerbell.WorkflowStateSkipped},
+						{ID: "kexec", Name: "kexec", Image: "kexec", State: tinkerbell.WorkflowStatePending},
+					},
+				},
+				{
+					ID:      "other",
+					AgentID: "machine-mac-2",
+					Actions: []tinkerbell.Action{
+						{ID: "other", Name: "other", Image: "other", State: tinkerbell.WorkflowStatePending},
+					},
+				},
+				{
+					ID:      "reboot",
+					AgentID: "machine-mac-1",
+					Actions: []tinkerbell.Action{
+						{ID: "reboot", Name: "reboot", Image: "reboot"},
+					},
+				},
+			},
+		},
+	}
+	want := []*proto.RemainingAction{
+		{TaskId: toPtr("provision"), ActionId: toPtr("kexec"), Name: toPtr("kexec"), Image: toPtr("kexec")},
+		{TaskId: toPtr("reboot"), ActionId: toPtr("reboot"), Name: toPtr("reboot"), Image: toPtr("reboot")},
+	}
+
+	got := remainingActions(wf, "machine-mac-1", &wf.Status.Tasks[0], &wf.Status.Tasks[0].Actions[1])
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("unexpected remaining actions (-want +got):\n%s", diff)
+	}
+}
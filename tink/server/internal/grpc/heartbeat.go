@@ -0,0 +1,62 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tinkerbell/tinkerbell/api/v1alpha1/tinkerbell"
+	"github.com/tinkerbell/tinkerbell/pkg/data"
+	"github.com/tinkerbell/tinkerbell/pkg/proto"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// heartbeatRecordInterval is the minimum interval between recording two heartbeats of an Agent that runs the same Action.
+// Agents send heartbeats more often, this bounds the writes to the backend.
+const heartbeatRecordInterval = time.Minute
+
+// recordHeartbeat records, in the status of the Hardware the Agent runs on, that the Agent is alive and the Action it runs.
+func (h *Handler) recordHeartbeat(ctx context.Context, agentID string, hb *proto.Heartbeat) error {
+	hw, err := h.heartbeatHardware(ctx, agentID, hb.GetWorkflowId())
+	if err != nil {
+		return fmt.Errorf("error getting hardware of agent: %w", err)
+	}
+	now := time.Now
+	if h.NowFunc != nil {
+		now = h.NowFunc
+	}
+	t := now()
+	if a := hw.Status.Agent; a != nil && a.LastSeen != nil && a.ID == agentID && a.ActionID == hb.GetActionId() && t.Sub(a.LastSeen.Time) < heartbeatRecordInterval {
+		return nil
+	}
+
+	original := hw.DeepCopy()
+	hw.Status.Agent = &tinkerbell.AgentStatus{
+		ID:       agentID,
+		LastSeen: &metav1.Time{Time: t},
+		ActionID: hb.GetActionId(),
+	}
+	if err := h.Backend.UpdateHardware(ctx, hw, data.UpdateOptions{StatusOnly: true, PatchFrom: original}); err != nil {
+		return fmt.Errorf("error recording heartbeat: %w", err)
+	}
+
+	return nil
+}
+
+// heartbeatHardware returns the Hardware an Agent runs on. That is the Hardware of the Workflow the Agent runs an Action of,
+// when the Workflow is for the Agent, otherwise the Hardware with the Agent's ID.
+func (h *Handler) heartbeatHardware(ctx context.Context, agentID, workflowID string) (*tinkerbell.Hardware, error) {
+	if workflowID != "" {
+		namespace, name, _ := strings.Cut(workflowID, "/")
+		wf, err := h.Backend.ReadWorkflow(ctx, name, namespace)
+		if err != nil {
+			return nil, err
+		}
+		if wf.Status.AgentID == agentID && wf.Spec.HardwareRef != "" {
+			return h.Backend.ReadHardware(ctx, wf.Spec.HardwareRef, wf.Namespace)
+		}
+	}
+
+	return h.hardware(ctx, agentID)
+}
@@ -0,0 +1,173 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
+	"github.com/tinkerbell/tinkerbell/api/v1alpha1/tinkerbell"
+	"github.com/tinkerbell/tinkerbell/pkg/proto"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRecordHeartbeat(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	wf := &tinkerbell.Workflow{
+		ObjectMeta: metav1.ObjectMeta{Name: "wf1", Namespace: "default"},
+		Spec:       tinkerbell.WorkflowSpec{HardwareRef: "hw1"},
+		Status:     tinkerbell.WorkflowStatus{AgentID: "agent1"},
+	}
+	hardware := func(agent *tinkerbell.AgentStatus) *tinkerbell.Hardware {
+		return &tinkerbell.Hardware{
+			ObjectMeta: metav1.ObjectMeta{Name: "hw1", Namespace: "default"},
+			Status:     tinkerbell.HardwareStatus{Agent: agent},
+		}
+	}
+	seen := func(ago time.Duration, actionID string) *tinkerbell.AgentStatus {
+		return &tinkerbell.AgentStatus{ID: "agent1", LastSeen: &metav1.Time{Time: now.Add(-ago)}, ActionID: actionID}
+	}
+	running := &proto.Heartbeat{WorkflowId: toPtr("default/wf1"), TaskId: toPtr("task1"), ActionId: toPtr("action1")}
+	tests := map[string]struct {
+		hardware    *tinkerbell.Hardware
+		hardwareErr error
+		heartbeat   *proto.Heartbeat
+		want        *tinkerbell.AgentStatus
+		wantErr     bool
+	}{
+		"first heartbeat": {
+			hardware:  hardware(nil),
+			heartbeat: running,
+			want:      seen(0, "action1"),
+		},
+		"recently recorded": {
+			hardware:  hardware(seen(10*time.Second, "action1")),
+			heartbeat: running,
+		},
+		"recently recorded, new action": {
+			hardware:  hardware(seen(10*time.Second, "action0")),
+			heartbeat: running,
+			want:      seen(0, "action1"),
+		},
+		"recorded a while ago": {
+			hardware:  hardware(seen(2*time.Minute, "action1")),
+			heartbeat: running,
+			want:      seen(0, "action1"),
+		},
+		"idle agent": {
+			hardware:  hardware(seen(2*time.Minute, "action1")),
+			heartbeat: &proto.Heartbeat{},
+			want:      seen(0, ""),
+		},
+		"no hardware": {
+			hardwareErr: errors.New("not found"),
+			heartbeat:   &proto.Heartbeat{},
+			wantErr:     true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			backend := &mockBackendReadWriter{workflow: wf, hardware: tt.hardware, hardwareErr: tt.hardwareErr}
+			h := &Handler{Backend: backend, NowFunc: func() time.Time { return now }}
+
+			err := h.recordHeartbeat(context.Background(), "agent1", tt.heartbeat)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("recordHeartbeat() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.want == nil {
+				if backend.updatedHardware != nil {
+					t.Errorf("expected no hardware update, got %v", backend.updatedHardware.Status.Agent)
+				}
+				return
+			}
+			if backend.updatedHardware == nil {
+				t.Fatal("expected a hardware update")
+			}
+			if len(backend.updateOpts) != 1 || !backend.updateOpts[0].StatusOnly || backend.updateOpts[0].PatchFrom == nil {
+				t.Errorf("expected a status patch, got %+v", backend.updateOpts)
+			}
+			if diff := cmp.Diff(tt.want, backend.updatedHardware.Status.Agent); diff != "" {
+				t.Errorf("unexpected agent status (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReportActionStatusRecordsHeartbeat(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	backend := &mockBackendReadWriter{
+		workflow: &tinkerbell.Workflow{
+			ObjectMeta: metav1.ObjectMeta{Name: "wf1", Namespace: "default"},
+			Spec:       tinkerbell.WorkflowSpec{HardwareRef: "hw1"},
+			Status: tinkerbell.WorkflowStatus{
+				AgentID: "agent1",
+				State:   tinkerbell.WorkflowStateRunning,
+				Tasks: []tinkerbell.Task{{
+					ID:      "task1",
+					AgentID: "agent1",
+					Actions: []tinkerbell.Action{{ID: "action1", State: tinkerbell.WorkflowStateRunning}},
+				}},
+			},
+		},
+		hardware: &tinkerbell.Hardware{ObjectMeta: metav1.ObjectMeta{Name: "hw1", Namespace: "default"}},
+	}
+	h := &Handler{Logger: logr.Discard(), Backend: backend, NowFunc: func() time.Time { return now }}
+
+	_, err := h.ReportActionStatus(context.Background(), &proto.ActionStatusRequest{
+		WorkflowId:  toPtr("default/wf1"),
+		TaskId:      toPtr("task1"),
+		ActionId:    toPtr("action1"),
+		AgentId:     toPtr("agent1"),
+		ActionState: proto.ActionStatusRequest_SUCCESS.Enum(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if backend.updatedHardware == nil {
+		t.Fatal("expected reporting action status to record a heartbeat")
+	}
+	want := &tinkerbell.AgentStatus{ID: "agent1", LastSeen: &metav1.Time{Time: now}, ActionID: "action1"}
+	if diff := cmp.Diff(want, backend.updatedHardware.Status.Agent); diff != "" {
+		t.Errorf("unexpected agent status (-want +got):\n%s", diff)
+	}
+}
+
+func TestCheckActionRecordsHeartbeat(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	backend := &mockBackendReadWriter{
+		workflow: &tinkerbell.Workflow{
+			ObjectMeta: metav1.ObjectMeta{Name: "wf1", Namespace: "default"},
+			Spec:       tinkerbell.WorkflowSpec{HardwareRef: "hw1"},
+			Status: tinkerbell.WorkflowStatus{
+				AgentID: "agent1",
+				State:   tinkerbell.WorkflowStateRunning,
+				Tasks: []tinkerbell.Task{{
+					ID:      "task1",
+					AgentID: "agent1",
+					Actions: []tinkerbell.Action{{ID: "action1", State: tinkerbell.WorkflowStateRunning}},
+				}},
+			},
+		},
+		hardware: &tinkerbell.Hardware{ObjectMeta: metav1.ObjectMeta{Name: "hw1", Namespace: "default"}},
+	}
+	h := &Handler{Logger: logr.Discard(), Backend: backend, NowFunc: func() time.Time { return now }}
+
+	_, err := h.CheckAction(context.Background(), &proto.CheckActionRequest{
+		WorkflowId: toPtr("default/wf1"),
+		TaskId:     toPtr("task1"),
+		ActionId:   toPtr("action1"),
+		AgentId:    toPtr("agent1"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if backend.updatedHardware == nil {
+		t.Fatal("expected checking an action to record a heartbeat")
+	}
+	want := &tinkerbell.AgentStatus{ID: "agent1", LastSeen: &metav1.Time{Time: now}, ActionID: "action1"}
+	if diff := cmp.Diff(want, backend.updatedHardware.Status.Agent); diff != "" {
+		t.Errorf("unexpected agent status (-want +got):\n%s", diff)
+	}
+}
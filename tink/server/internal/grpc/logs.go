@@ -0,0 +1,110 @@
+package grpc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/tinkerbell/tinkerbell/api/v1alpha1/tinkerbell"
+	"github.com/tinkerbell/tinkerbell/pkg/constant"
+	"github.com/tinkerbell/tinkerbell/pkg/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// actionLogWriteTimeout bounds how long persisting the logs may take after the stream has ended.
+const actionLogWriteTimeout = 10 * time.Second
+
+// StreamActionLogs receives the output of a running Action from an Agent and persists the tail of it
+// with the ActionLogWriter once the stream ends. The first request must identify the Action.
+// The logs are also persisted when the Agent goes away without closing the stream, for example when the machine reboots.
+func (h *Handler) StreamActionLogs(stream grpc.ClientStreamingServer[proto.ActionLogsRequest, proto.ActionLogsResponse]) error {
+	if h.BackendV1Alpha2 != nil || h.ActionLogWriter == nil {
+		return status.Error(codes.Unimplemented, "action logs are not supported by this server")
+	}
+	ctx := stream.Context()
+	first, err := stream.Recv()
+	if errors.Is(err, io.EOF) {
+		return stream.SendAndClose(&proto.ActionLogsResponse{})
+	}
+	if err != nil {
+		return err
+	}
+	if first.GetWorkflowId() == "" {
+		return status.Errorf(codes.InvalidArgument, errInvalidWorkflowID)
+	}
+	if first.GetTaskId() == "" {
+		return status.Errorf(codes.InvalidArgument, errInvalidTaskName)
+	}
+	if first.GetActionId() == "" {
+		return status.Errorf(codes.InvalidArgument, errInvalidActionName)
+	}
+	namespace, name, _ := strings.Cut(first.GetWorkflowId(), "/")
+	wf, err := h.Backend.ReadWorkflow(ctx, name, namespace)
+	if err != nil {
+		return errors.Join(ErrBackendRead, status.Errorf(codes.Internal, "error getting workflow: %v", err))
+	}
+	if !hasAgentAction(wf.Status.Tasks, first.GetAgentId(), first.GetActionId()) {
+		return status.Error(codes.NotFound, "action not found")
+	}
+
+	tail := appendTail(nil, first.GetData(), constant.MaxActionLogSize)
+	var recvErr error
+	for {
+		req, err := stream.Recv()
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				recvErr = err
+			}
+			break
+		}
+		tail = appendTail(tail, req.GetData(), constant.MaxActionLogSize)
+	}
+
+	// The stream context is done when the Agent went away, the logs are still worth keeping.
+	wctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), actionLogWriteTimeout)
+	defer cancel()
+	if err := h.ActionLogWriter.WriteActionLogs(wctx, wf, first.GetActionId(), tail); err != nil {
+		h.Logger.Info("error writing action logs", "workflow", first.GetWorkflowId(), "action", first.GetActionId(), "error", err)
+		return errors.Join(ErrBackendWrite, status.Errorf(codes.Internal, "error writing action logs: %v", err))
+	}
+	if recvErr != nil {
+		return recvErr
+	}
+
+	return stream.SendAndClose(&proto.ActionLogsResponse{})
+}
+
+// hasAgentAction reports whether the Action with actionID is part of a Task run by agentID.
+func hasAgentAction(tasks []tinkerbell.Task, agentID, actionID string) bool {
+	for _, task := range tasks {
+		if task.AgentID != agentID {
+			continue
+		}
+		for _, action := range task.Actions {
+			if action.ID == actionID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// appendTail appends p to b, keeping at most the last limit bytes.
+// When bytes are dropped, the tail starts at the beginning of a line if there is one.
+func appendTail(b, p []byte, limit int) []byte {
+	b = append(b, p...)
+	if len(b) <= limit {
+		return b
+	}
+	b = b[len(b)-limit:]
+	if i := bytes.IndexByte(b, '\n'); i >= 0 && i < len(b)-1 {
+		b = b[i+1:]
+	}
+	// Copy so that the dropped bytes can be garbage collected.
+	return append(make([]byte, 0, len(b)), b...)
+}
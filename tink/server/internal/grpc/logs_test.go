@@ -0,0 +1,175 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
+	"github.com/tinkerbell/tinkerbell/api/v1alpha1/tinkerbell"
+	"github.com/tinkerbell/tinkerbell/pkg/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// mockLogStream is a StreamActionLogs server stream that returns reqs and then recvErr, or io.EOF.
+type mockLogStream struct {
+	grpc.ServerStream
+	ctx     context.Context
+	reqs    []*proto.ActionLogsRequest
+	recvErr error
+	closed  bool
+}
+
+func (m *mockLogStream) Context() context.Context {
+	return m.ctx
+}
+
+func (m *mockLogStream) Recv() (*proto.ActionLogsRequest, error) {
+	if len(m.reqs) == 0 {
+		if m.recvErr != nil {
+			return nil, m.recvErr
+		}
+		return nil, io.EOF
+	}
+	req := m.reqs[0]
+	m.reqs = m.reqs[1:]
+	return req, nil
+}
+
+func (m *mockLogStream) SendAndClose(*proto.ActionLogsResponse) error {
+	m.closed = true
+	return nil
+}
+
+type mockActionLogWriter struct {
+	actionID string
+	logs     string
+	err      error
+}
+
+func (m *mockActionLogWriter) WriteActionLogs(_ context.Context, _ *tinkerbell.Workflow, actionID string, logs []byte) error {
+	m.actionID = actionID
+	m.logs = string(logs)
+	return m.err
+}
+
+func TestStreamActionLogs(t *testing.T) {
+	wf := &tinkerbell.Workflow{
+		ObjectMeta: metav1.ObjectMeta{Name: "workflow1", Namespace: "default"},
+		Status: tinkerbell.WorkflowStatus{
+			Tasks: []tinkerbell.Task{
+				{ID: "task1", AgentID: "agent1", Actions: []tinkerbell.Action{{ID: "action1"}}},
+			},
+		},
+	}
+	first := &proto.ActionLogsRequest{
+		WorkflowId: toPtr("default/workflow1"),
+		AgentId:    toPtr("agent1"),
+		TaskId:     toPtr("task1"),
+		ActionId:   toPtr("action1"),
+	}
+	tests := map[string]struct {
+		reqs        []*proto.ActionLogsRequest
+		recvErr     error
+		writeErr    error
+		noWriter    bool
+		wantLogs    string
+		wantWritten bool
+		wantClosed  bool
+		wantErr     error
+	}{
+		"logs are written": {
+			reqs: []*proto.ActionLogsRequest{
+				first,
+				{Stream: toPtr("stdout"), Data: []byte("wiping /dev/sda\n")},
+				{Stream: toPtr("stderr"), Data: []byte("error: device busy\n")},
+			},
+			wantLogs:    "wiping /dev/sda\nerror: device busy\n",
+			wantWritten: true,
+			wantClosed:  true,
+		},
+		"logs are written when the agent goes away": {
+			reqs:        []*proto.ActionLogsRequest{first, {Data: []byte("rebooting\n")}},
+			recvErr:     status.Error(codes.Canceled, "context canceled"),
+			wantLogs:    "rebooting\n",
+			wantWritten: true,
+			wantErr:     status.Error(codes.Canceled, "context canceled"),
+		},
+		"empty stream": {
+			wantClosed: true,
+		},
+		"missing action id": {
+			reqs:    []*proto.ActionLogsRequest{{WorkflowId: toPtr("default/workflow1"), TaskId: toPtr("task1")}},
+			wantErr: status.Errorf(codes.InvalidArgument, errInvalidActionName),
+		},
+		"action of another agent": {
+			reqs:    []*proto.ActionLogsRequest{{WorkflowId: toPtr("default/workflow1"), AgentId: toPtr("agent2"), TaskId: toPtr("task1"), ActionId: toPtr("action1")}},
+			wantErr: status.Error(codes.NotFound, "action not found"),
+		},
+		"write error": {
+			reqs:        []*proto.ActionLogsRequest{first},
+			writeErr:    errors.New("forbidden"),
+			wantWritten: true,
+			wantErr:     errors.Join(ErrBackendWrite, status.Errorf(codes.Internal, "error writing action logs: forbidden")),
+		},
+		"no log writer": {
+			reqs:     []*proto.ActionLogsRequest{first},
+			noWriter: true,
+			wantErr:  status.Error(codes.Unimplemented, "action logs are not supported by this server"),
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			lw := &mockActionLogWriter{err: tt.writeErr}
+			h := &Handler{
+				Logger:          logr.Discard(),
+				Backend:         &mockBackendReadWriter{workflow: wf},
+				ActionLogWriter: lw,
+			}
+			if tt.noWriter {
+				h.ActionLogWriter = nil
+			}
+			stream := &mockLogStream{ctx: context.Background(), reqs: tt.reqs, recvErr: tt.recvErr}
+
+			err := h.StreamActionLogs(stream)
+			compareErrors(t, err, tt.wantErr)
+			if stream.closed != tt.wantClosed {
+				t.Errorf("expected closed %v, got %v", tt.wantClosed, stream.closed)
+			}
+			if written := lw.actionID != ""; written != tt.wantWritten {
+				t.Fatalf("expected logs written %v, got %v", tt.wantWritten, written)
+			}
+			if diff := cmp.Diff(tt.wantLogs, lw.logs); diff != "" {
+				t.Errorf("unexpected logs (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestAppendTail(t *testing.T) {
+	tests := map[string]struct {
+		b, p  string
+		limit int
+		want  string
+	}{
+		"under limit":         {b: "one\n", p: "two\n", limit: 10, want: "one\ntwo\n"},
+		"starts at a line":    {b: "one\n", p: "two\nthree\n", limit: 10, want: "three\n"},
+		"no newline":          {b: "abcdef", p: "ghij", limit: 4, want: "ghij"},
+		"trailing newline":    {b: "abc", p: "def\n", limit: 4, want: "def\n"},
+		"large single append": {p: strings.Repeat("a", 20) + "\nend\n", limit: 8, want: "end\n"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := appendTail([]byte(tt.b), []byte(tt.p), tt.limit)
+			if diff := cmp.Diff(tt.want, string(got)); diff != "" {
+				t.Errorf("appendTail() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
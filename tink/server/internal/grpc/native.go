@@ -0,0 +1,41 @@
+package grpc
+
+import (
+	"errors"
+	"fmt"
+	"path"
+
+	"github.com/tinkerbell/tinkerbell/api/v1alpha1/tinkerbell"
+)
+
+// allowNative returns an error when action is a native Action whose command, or hook command, is not in NativeCommands.
+func (h *Handler) allowNative(action *tinkerbell.Action) error {
+	if action.Type != tinkerbell.ActionTypeNative {
+		return nil
+	}
+	if len(action.Command) == 0 {
+		return errors.New("native Action has no command")
+	}
+	for _, cmd := range [][]string{action.Command, action.OnTimeout, action.OnFailure} {
+		if len(cmd) > 0 && !nativeCommandAllowed(h.NativeCommands, cmd[0]) {
+			return fmt.Errorf("native command %q is not in the allowlist", cmd[0])
+		}
+	}
+
+	return nil
+}
+
+// nativeCommandAllowed reports whether command, an absolute path, matches one of the path.Match patterns of allowlist.
+func nativeCommandAllowed(allowlist []string, command string) bool {
+	if !path.IsAbs(command) {
+		return false
+	}
+	command = path.Clean(command)
+	for _, pattern := range allowlist {
+		if ok, _ := path.Match(pattern, command); ok {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,90 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cenkalti/backoff/v5"
+	"github.com/go-logr/logr"
+	"github.com/tinkerbell/tinkerbell/api/v1alpha1/tinkerbell"
+	"github.com/tinkerbell/tinkerbell/pkg/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestAllowNative(t *testing.T) {
+	allowlist := []string{"/usr/sbin/*", "/bin/sh"}
+	tests := map[string]struct {
+		action  tinkerbell.Action
+		wantErr bool
+	}{
+		"container":            {action: tinkerbell.Action{Command: []string{"/sbin/reboot"}}},
+		"allowed":              {action: tinkerbell.Action{Type: tinkerbell.ActionTypeNative, Command: []string{"/usr/sbin/mkfs.ext4", "/dev/sda1"}}},
+		"allowed hooks":        {action: tinkerbell.Action{Type: tinkerbell.ActionTypeNative, Command: []string{"/bin/sh"}, OnFailure: []string{"/usr/sbin/wipefs"}}},
+		"not in the allowlist": {action: tinkerbell.Action{Type: tinkerbell.ActionTypeNative, Command: []string{"/sbin/reboot"}}, wantErr: true},
+		"hook not allowed":     {action: tinkerbell.Action{Type: tinkerbell.ActionTypeNative, Command: []string{"/bin/sh"}, OnTimeout: []string{"/sbin/reboot"}}, wantErr: true},
+		"relative path":        {action: tinkerbell.Action{Type: tinkerbell.ActionTypeNative, Command: []string{"sh"}}, wantErr: true},
+		"path traversal":       {action: tinkerbell.Action{Type: tinkerbell.ActionTypeNative, Command: []string{"/usr/sbin/../../sbin/reboot"}}, wantErr: true},
+		"no command":           {action: tinkerbell.Action{Type: tinkerbell.ActionTypeNative}, wantErr: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			h := &Handler{NativeCommands: allowlist}
+			if err := h.allowNative(&tt.action); (err != nil) != tt.wantErr {
+				t.Errorf("allowNative() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetActionNative(t *testing.T) {
+	workflow := func() *tinkerbell.Workflow {
+		return &tinkerbell.Workflow{
+			ObjectMeta: metav1.ObjectMeta{Name: "machine1", Namespace: "default"},
+			Status: tinkerbell.WorkflowStatus{
+				State: tinkerbell.WorkflowStatePending,
+				Tasks: []tinkerbell.Task{{
+					Name: "provision", ID: "provision", AgentID: "machine-mac-1",
+					Actions: []tinkerbell.Action{{
+						ID: "wipe", Name: "wipe", Type: tinkerbell.ActionTypeNative,
+						Command: []string{"/usr/sbin/wipefs", "-a", "/dev/sda"}, State: tinkerbell.WorkflowStatePending,
+					}},
+				}},
+			},
+		}
+	}
+	tests := map[string]struct {
+		allowlist []string
+		wantErr   error
+		wantState tinkerbell.WorkflowState
+	}{
+		"allowed": {
+			allowlist: []string{"/usr/sbin/wipefs"},
+			wantState: tinkerbell.WorkflowStatePending,
+		},
+		"not allowed": {
+			wantErr:   status.Error(codes.FailedPrecondition, `Action wipe: native command "/usr/sbin/wipefs" is not in the allowlist`),
+			wantState: tinkerbell.WorkflowStateFailed,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			backend := &mockBackendReadWriter{workflow: workflow()}
+			h := &Handler{
+				Logger:         logr.Discard(),
+				Backend:        backend,
+				RetryOptions:   []backoff.RetryOption{backoff.WithMaxTries(1)},
+				NativeCommands: tt.allowlist,
+			}
+			resp, err := h.GetAction(context.Background(), &proto.ActionRequest{AgentId: toPtr("machine-mac-1")})
+			compareErrors(t, err, tt.wantErr)
+			if err == nil && resp.GetType() != proto.ActionType_ACTION_TYPE_NATIVE {
+				t.Errorf("unexpected Action type: got %v, want %v", resp.GetType(), proto.ActionType_ACTION_TYPE_NATIVE)
+			}
+			if got := backend.workflow.Status.Tasks[0].Actions[0].State; got != tt.wantState {
+				t.Errorf("unexpected Action state: got %v, want %v", got, tt.wantState)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package grpc
 
 import (
+	"github.com/tinkerbell/tinkerbell/api/v1alpha1/tinkerbell"
 	"github.com/tinkerbell/tinkerbell/pkg/data"
 	"github.com/tinkerbell/tinkerbell/pkg/proto"
 )
@@ -42,16 +43,28 @@ func convert(pAttr *proto.AgentAttributes) *data.AgentAttributes {
 			Model:             block.Model,
 			WWN:               block.Wwn,
 			SerialNumber:      block.SerialNumber,
+			SmartStatus:       block.SmartStatus,
 		})
 	}
 	// NetworkInterfaces
 	for _, network := range pAttr.Network {
-		dAttr.NetworkInterfaces = append(dAttr.NetworkInterfaces, &data.Network{
+		n := &data.Network{
 			Name:                network.Name,
 			Mac:                 network.Mac,
 			Speed:               network.Speed,
 			EnabledCapabilities: network.EnabledCapabilities,
-		})
+			LinkState:           network.LinkState,
+		}
+		for _, nb := range network.LldpNeighbors {
+			n.LLDPNeighbors = append(n.LLDPNeighbors, &data.LLDPNeighbor{
+				ChassisID:         nb.ChassisId,
+				PortID:            nb.PortId,
+				PortDescription:   nb.PortDescription,
+				SystemName:        nb.SystemName,
+				SystemDescription: nb.SystemDescription,
+			})
+		}
+		dAttr.NetworkInterfaces = append(dAttr.NetworkInterfaces, n)
 	}
 	// PCIDevices
 	for _, pci := range pAttr.Pci {
@@ -95,6 +108,41 @@ func convert(pAttr *proto.AgentAttributes) *data.AgentAttributes {
 		dAttr.Product.Vendor = pAttr.Product.Vendor
 		dAttr.Product.SerialNumber = pAttr.Product.SerialNumber
 	}
+	// TPM, only set when the Agent's machine has one.
+	if pAttr.Tpm != nil {
+		dAttr.TPM = &data.TPM{Version: pAttr.Tpm.Version}
+	}
 
 	return dAttr
 }
+
+// sandboxToProto converts the sandbox of an Action. Privileged is only set when the Action sets it,
+// so that the Agent's runtime default is used otherwise.
+func sandboxToProto(s *tinkerbell.ActionSandbox) *proto.Sandbox {
+	if s == nil {
+		return nil
+	}
+	ps := &proto.Sandbox{
+		Privileged: s.Privileged,
+	}
+	if s.ReadOnlyRootFilesystem {
+		ps.ReadOnlyRootFilesystem = toPtr(true)
+	}
+	if r := s.Resources; r != nil {
+		if r.CPU != "" {
+			ps.Cpu = toPtr(r.CPU)
+		}
+		if r.Memory != "" {
+			ps.Memory = toPtr(r.Memory)
+		}
+		if r.PIDs > 0 {
+			ps.Pids = toPtr(r.PIDs)
+		}
+	}
+	if c := s.Capabilities; c != nil {
+		ps.CapabilitiesAdd = c.Add
+		ps.CapabilitiesDrop = c.Drop
+	}
+
+	return ps
+}
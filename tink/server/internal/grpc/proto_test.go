@@ -4,8 +4,10 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/tinkerbell/tinkerbell/api/v1alpha1/tinkerbell"
 	"github.com/tinkerbell/tinkerbell/pkg/data"
 	"github.com/tinkerbell/tinkerbell/pkg/proto"
+	"google.golang.org/protobuf/testing/protocmp"
 )
 
 func TestConvert(t *testing.T) {
@@ -52,6 +54,7 @@ func TestConvert(t *testing.T) {
 						Model:             toPtr("KINGSTON ABCDEF-01"),
 						Wwn:               toPtr("18b6db91-d83b-4172-97d4-27c38550cce6"),
 						SerialNumber:      toPtr("1234567890"),
+						SmartStatus:       toPtr("passed"),
 					},
 				},
 				Network: []*proto.Network{
@@ -60,6 +63,10 @@ func TestConvert(t *testing.T) {
 						Mac:                 toPtr("de:ad:be:ef:00:00"),
 						Speed:               toPtr("1000Mb/s"),
 						EnabledCapabilities: []string{"auto-negotiation"},
+						LinkState:           toPtr("up"),
+						LldpNeighbors: []*proto.LLDPNeighbor{
+							{ChassisId: toPtr("3c:ec:ef:00:00:01"), PortId: toPtr("Ethernet1/12"), SystemName: toPtr("tor1")},
+						},
 					},
 				},
 				Pci: []*proto.PCI{
@@ -98,6 +105,7 @@ func TestConvert(t *testing.T) {
 					Vendor:       toPtr("example vendor"),
 					SerialNumber: toPtr("xyz-123"),
 				},
+				Tpm: &proto.TPM{Version: toPtr("2.0")},
 			},
 			want: &data.AgentAttributes{
 				CPU: &data.CPU{
@@ -129,6 +137,7 @@ func TestConvert(t *testing.T) {
 						Model:             toPtr("KINGSTON ABCDEF-01"),
 						WWN:               toPtr("18b6db91-d83b-4172-97d4-27c38550cce6"),
 						SerialNumber:      toPtr("1234567890"),
+						SmartStatus:       toPtr("passed"),
 					},
 				},
 				NetworkInterfaces: []*data.Network{
@@ -137,6 +146,10 @@ func TestConvert(t *testing.T) {
 						Mac:                 toPtr("de:ad:be:ef:00:00"),
 						Speed:               toPtr("1000Mb/s"),
 						EnabledCapabilities: []string{"auto-negotiation"},
+						LinkState:           toPtr("up"),
+						LLDPNeighbors: []*data.LLDPNeighbor{
+							{ChassisID: toPtr("3c:ec:ef:00:00:01"), PortID: toPtr("Ethernet1/12"), SystemName: toPtr("tor1")},
+						},
 					},
 				},
 				PCIDevices: []*data.PCI{
@@ -175,6 +188,7 @@ func TestConvert(t *testing.T) {
 					Vendor:       toPtr("example vendor"),
 					SerialNumber: toPtr("xyz-123"),
 				},
+				TPM: &data.TPM{Version: toPtr("2.0")},
 			},
 		},
 		"partial input - only CPU": {
@@ -254,3 +268,39 @@ func TestConvert(t *testing.T) {
 		})
 	}
 }
+
+func TestSandboxToProto(t *testing.T) {
+	tests := map[string]struct {
+		input *tinkerbell.ActionSandbox
+		want  *proto.Sandbox
+	}{
+		"nil input": {},
+		"privileged not set": {
+			input: &tinkerbell.ActionSandbox{ReadOnlyRootFilesystem: true},
+			want:  &proto.Sandbox{ReadOnlyRootFilesystem: toPtr(true)},
+		},
+		"all fields": {
+			input: &tinkerbell.ActionSandbox{
+				Resources:    &tinkerbell.ActionResources{CPU: "500m", Memory: "512Mi", PIDs: 128},
+				Capabilities: &tinkerbell.ActionCapabilities{Add: []string{"SYS_ADMIN"}, Drop: []string{"ALL"}},
+				Privileged:   toPtr(false),
+			},
+			want: &proto.Sandbox{
+				Cpu:              toPtr("500m"),
+				Memory:           toPtr("512Mi"),
+				Pids:             toPtr(int64(128)),
+				CapabilitiesAdd:  []string{"SYS_ADMIN"},
+				CapabilitiesDrop: []string{"ALL"},
+				Privileged:       toPtr(false),
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, sandboxToProto(tc.input), protocmp.Transform()); diff != "" {
+				t.Errorf("sandboxToProto() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
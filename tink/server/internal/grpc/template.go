@@ -0,0 +1,120 @@
+package grpc
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"strings"
+	"text/template"
+
+	"github.com/tinkerbell/tinkerbell/api/v1alpha1/tinkerbell"
+)
+
+const (
+	templateLeftDelim  = "[["
+	templateRightDelim = "]]"
+)
+
+// shouldRun evaluates the If condition of an Action against the results of the Actions in the Workflow.
+// An empty condition, or one that renders to "true" (case-insensitive), runs the Action.
+func shouldRun(ws *tinkerbell.WorkflowStatus, task *tinkerbell.Task, action *tinkerbell.Action) (bool, error) {
+	if strings.TrimSpace(action.If) == "" {
+		return true, nil
+	}
+	s, err := render(action.Name, action.If, templateData(ws, task))
+	if err != nil {
+		return false, fmt.Errorf("error evaluating if condition: %w", err)
+	}
+	s = strings.TrimSpace(s)
+
+	return s == "" || strings.EqualFold(s, "true"), nil
+}
+
+// renderEnvironment returns the Task and Action environment variables, Action values take precedence.
+// Values are rendered against the results of the Actions in the Workflow so that outputs of earlier
+// Actions can be passed to the Action as environment variables. Values that are not valid templates,
+// for example a shell test such as "[[ -f /etc/os-release ]]", are passed through unchanged.
+// A literal delimiter in a valid template is written as [[ "[[" ]].
+func renderEnvironment(ws *tinkerbell.WorkflowStatus, task *tinkerbell.Task, action *tinkerbell.Action) (map[string]string, error) {
+	joined := map[string]string{}
+	maps.Copy(joined, task.Environment)
+	maps.Copy(joined, action.Environment)
+
+	var td map[string]interface{}
+	for k, v := range joined {
+		if !strings.Contains(v, templateLeftDelim) {
+			continue
+		}
+		t, err := parse(k, v)
+		if err != nil {
+			continue
+		}
+		if td == nil {
+			td = templateData(ws, task)
+		}
+		s, err := execute(t, td)
+		if err != nil {
+			return nil, fmt.Errorf("error rendering environment variable %s: %w", k, err)
+		}
+		joined[k] = s
+	}
+
+	return joined, nil
+}
+
+// render executes text as a template using the "[[" and "]]" delimiters.
+// These delimiters are used so that the text is not rendered with the rest of the Template.
+func render(name, text string, td map[string]interface{}) (string, error) {
+	t, err := parse(name, text)
+	if err != nil {
+		return "", fmt.Errorf("error parsing template: %w", err)
+	}
+
+	return execute(t, td)
+}
+
+// parse parses text as a template using the "[[" and "]]" delimiters.
+func parse(name, text string) (*template.Template, error) {
+	return template.New(name).
+		Delims(templateLeftDelim, templateRightDelim).
+		Option("missingkey=error").
+		Parse(text)
+}
+
+// execute executes t with td and returns the result.
+func execute(t *template.Template, td map[string]interface{}) (string, error) {
+	var b bytes.Buffer
+	if err := t.Execute(&b, td); err != nil {
+		return "", err
+	}
+
+	return b.String(), nil
+}
+
+// templateData returns the data available to If conditions and environment variables.
+func templateData(ws *tinkerbell.WorkflowStatus, task *tinkerbell.Task) map[string]interface{} {
+	tasks := map[string]interface{}{}
+	for _, t := range ws.Tasks {
+		tasks[t.Name] = actionResults(t)
+	}
+
+	return map[string]interface{}{
+		"actions": actionResults(*task),
+		"tasks":   tasks,
+	}
+}
+
+// actionResults returns the results of the Actions in a Task keyed by Action name.
+func actionResults(t tinkerbell.Task) map[string]interface{} {
+	results := map[string]interface{}{}
+	for _, a := range t.Actions {
+		outputs := map[string]string{}
+		maps.Copy(outputs, a.Outputs)
+		results[a.Name] = map[string]interface{}{
+			"state":   string(a.State),
+			"message": a.Message,
+			"outputs": outputs,
+		}
+	}
+	return results
+}
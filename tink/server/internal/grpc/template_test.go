@@ -0,0 +1,116 @@
+package grpc
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tinkerbell/tinkerbell/api/v1alpha1/tinkerbell"
+)
+
+func TestShouldRun(t *testing.T) {
+	ws := &tinkerbell.WorkflowStatus{
+		Tasks: []tinkerbell.Task{
+			{
+				Name: "inventory",
+				Actions: []tinkerbell.Action{
+					{Name: "detect-disk", State: tinkerbell.WorkflowStateSuccess, Message: "nvme", Outputs: map[string]string{"device": "/dev/nvme0n1"}},
+				},
+			},
+			{
+				Name: "provision",
+				Actions: []tinkerbell.Action{
+					{Name: "wipe", State: tinkerbell.WorkflowStateFailed},
+					{Name: "stream"},
+				},
+			},
+		},
+	}
+	tests := map[string]struct {
+		cond    string
+		want    bool
+		wantErr bool
+	}{
+		"empty":                      {cond: "", want: true},
+		"true":                       {cond: "True", want: true},
+		"false":                      {cond: "false", want: false},
+		"not a boolean":              {cond: "yes", want: false},
+		"action in same task":        {cond: `[[ eq .actions.wipe.state "SUCCESS" ]]`, want: false},
+		"action in other task":       {cond: `[[ eq (index .tasks.inventory "detect-disk").state "SUCCESS" ]]`, want: true},
+		"action message":             {cond: `[[ eq (index .tasks.inventory "detect-disk").message "nvme" ]]`, want: true},
+		"action output":              {cond: `[[ eq (index .tasks.inventory "detect-disk").outputs.device "/dev/nvme0n1" ]]`, want: true},
+		"curly braces not evaluated": {cond: `{{ true }}`, want: false},
+		"unknown action":             {cond: `[[ .actions.missing.state ]]`, wantErr: true},
+		"invalid template":           {cond: `[[ eq ]`, wantErr: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			task := &ws.Tasks[1]
+			action := &tinkerbell.Action{Name: "stream", If: tt.cond}
+			got, err := shouldRun(ws, task, action)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("shouldRun() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("shouldRun() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderEnvironment(t *testing.T) {
+	ws := &tinkerbell.WorkflowStatus{
+		Tasks: []tinkerbell.Task{
+			{
+				Name: "provision",
+				Actions: []tinkerbell.Action{
+					{Name: "detect-disk", State: tinkerbell.WorkflowStateSuccess, Outputs: map[string]string{"device": "/dev/nvme0n1"}},
+					{Name: "stream"},
+				},
+				Environment: map[string]string{"HTTP_PROXY": "http://proxy", "DEST_DISK": "/dev/sda"},
+			},
+		},
+	}
+	tests := map[string]struct {
+		env     map[string]string
+		want    map[string]string
+		wantErr bool
+	}{
+		"no templates": {
+			env:  map[string]string{"IMG_URL": "http://image"},
+			want: map[string]string{"HTTP_PROXY": "http://proxy", "DEST_DISK": "/dev/sda", "IMG_URL": "http://image"},
+		},
+		"action output": {
+			env:  map[string]string{"DEST_DISK": `[[ (index .actions "detect-disk").outputs.device ]]`},
+			want: map[string]string{"HTTP_PROXY": "http://proxy", "DEST_DISK": "/dev/nvme0n1"},
+		},
+		"literal delimiter": {
+			env:  map[string]string{"PASSWORD": "p[[ss"},
+			want: map[string]string{"HTTP_PROXY": "http://proxy", "DEST_DISK": "/dev/sda", "PASSWORD": "p[[ss"},
+		},
+		"shell test": {
+			env:  map[string]string{"CHECK": "[[ -f /etc/os-release ]] && echo ok"},
+			want: map[string]string{"HTTP_PROXY": "http://proxy", "DEST_DISK": "/dev/sda", "CHECK": "[[ -f /etc/os-release ]] && echo ok"},
+		},
+		"escaped delimiter": {
+			env:  map[string]string{"CHECK": `[[ "[[" ]] -b [[ (index .actions "detect-disk").outputs.device ]] ]]`},
+			want: map[string]string{"HTTP_PROXY": "http://proxy", "DEST_DISK": "/dev/sda", "CHECK": "[[ -b /dev/nvme0n1 ]]"},
+		},
+		"unknown output": {
+			env:     map[string]string{"DEST_DISK": `[[ (index .actions "detect-disk").outputs.missing ]]`},
+			wantErr: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			task := &ws.Tasks[0]
+			action := &tinkerbell.Action{Name: "stream", Environment: tt.env}
+			got, err := renderEnvironment(ws, task, action)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("renderEnvironment() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("renderEnvironment() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
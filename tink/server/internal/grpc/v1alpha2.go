@@ -0,0 +1,329 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"sort"
+	"strings"
+
+	v1alpha2 "github.com/tinkerbell/tinkerbell/api/v1alpha2/tinkerbell"
+	"github.com/tinkerbell/tinkerbell/pkg/data"
+	"github.com/tinkerbell/tinkerbell/pkg/journal"
+	"github.com/tinkerbell/tinkerbell/pkg/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// BackendV1Alpha2 is the backend used to serve Actions from v1alpha2 Workflows.
+type BackendV1Alpha2 interface {
+	WorkflowV1Alpha2Reader
+	WorkflowV1Alpha2Lister
+	WorkflowV1Alpha2Updater
+}
+
+type WorkflowV1Alpha2Reader interface {
+	ReadWorkflowV1Alpha2(ctx context.Context, name, namespace string) (*v1alpha2.Workflow, error)
+}
+
+type WorkflowV1Alpha2Lister interface {
+	ListWorkflowsV1Alpha2(ctx context.Context, opts data.WorkflowFilter) ([]v1alpha2.Workflow, error)
+}
+
+type WorkflowV1Alpha2Updater interface {
+	UpdateWorkflowV1Alpha2(ctx context.Context, wf *v1alpha2.Workflow, opts data.UpdateOptions) error
+}
+
+// doGetActionV1Alpha2 serves the next Action, from the rendered Tasks of a v1alpha2 Workflow, that is assigned to the Agent.
+// The Task and Workflow states are rolled up from the Action states by the v1alpha2 Workflow controller.
+func (h *Handler) doGetActionV1Alpha2(ctx context.Context, req *proto.ActionRequest) (*proto.ActionResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, status.Error(codes.Unavailable, "server shutting down")
+	default:
+	}
+
+	ctx = journal.New(ctx)
+	log := h.Logger.WithValues("agent", req.GetAgentId())
+	defer func() {
+		log.V(1).Info("GetAction code flow journal", "journal", journal.Journal(ctx))
+	}()
+	if req.GetAgentId() == "" {
+		journal.Log(ctx, "invalid Agent ID")
+		return nil, status.Errorf(codes.InvalidArgument, "invalid Agent ID")
+	}
+
+	wfs, err := h.BackendV1Alpha2.ListWorkflowsV1Alpha2(ctx, data.WorkflowFilter{ByAgentID: req.GetAgentId()})
+	if err != nil {
+		journal.Log(ctx, "error getting Workflows", "error", err)
+		return nil, errors.Join(ErrBackendRead, status.Errorf(codes.Internal, "error getting workflows: %v", err))
+	}
+	if len(wfs) == 0 {
+		journal.Log(ctx, "no Workflow found")
+		return nil, status.Error(codes.NotFound, "no Workflows found")
+	}
+
+	journal.Log(ctx, "found Workflows", "workflows", len(wfs))
+	for _, wf := range wfs {
+		if wf.Spec.Disabled != nil && *wf.Spec.Disabled {
+			journal.Log(ctx, "Workflow is disabled", "workflow", wf.Name)
+			continue
+		}
+		if wf.Status.Metadata.Workflow.State != v1alpha2.WorkflowStatePending && wf.Status.Metadata.Workflow.State != v1alpha2.WorkflowStateRunning {
+			journal.Log(ctx, "Workflow not in pending or running state", "workflow", wf.Name)
+			continue
+		}
+		ti, ai, ok := nextAction(wf.Status.RenderedTasks)
+		if !ok {
+			journal.Log(ctx, "no Action available", "workflow", wf.Name)
+			continue
+		}
+		task := &wf.Status.RenderedTasks[ti]
+		if task.Metadata.AgentID != req.GetAgentId() {
+			journal.Log(ctx, "Task not assigned to Agent", "workflow", wf.Name, "task", task.Name)
+			continue
+		}
+		action := &task.Actions[ai]
+
+		if wf.Status.Metadata.Action.ID != action.Metadata.ID {
+			wf.Status.Metadata.Task = task.Metadata
+			wf.Status.Metadata.Action = action.Metadata
+			if err := h.BackendV1Alpha2.UpdateWorkflowV1Alpha2(ctx, &wf, data.UpdateOptions{StatusOnly: true}); err != nil {
+				return nil, errors.Join(ErrBackendWrite, status.Errorf(codes.Internal, "error writing current state: %v", err))
+			}
+		}
+
+		ar := toActionResponse(&wf, task, action)
+		ar.RemainingActions = remainingActionsV1Alpha2(wf.Status.RenderedTasks, req.GetAgentId(), ti, ai)
+		log.Info("sending action", "action", ar, "actionID", action.Metadata.ID)
+		journal.Log(ctx, "sending Action", "action", ar)
+		return ar, nil
+	}
+
+	journal.Log(ctx, "no Action found")
+	return nil, status.Error(codes.NotFound, "no Actions found")
+}
+
+// nextAction returns the indexes of the first Task and Action that have not completed successfully.
+// It returns false when all Actions are complete or when the next Action has failed or timed out.
+func nextAction(tasks []v1alpha2.TaskWithMetadata) (int, int, bool) {
+	for ti, task := range tasks {
+		for ai, action := range task.Actions {
+			switch action.Metadata.State {
+			case v1alpha2.ActionStateSuccess:
+				continue
+			case v1alpha2.ActionStatePending, v1alpha2.ActionStateRunning:
+				// A running Action is served again, this handles Agent and server restarts.
+				return ti, ai, true
+			default:
+				return 0, 0, false
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// remainingActionsV1Alpha2 returns the pending Actions, assigned to agentID, that run after the Action at ai in the Task at ti.
+func remainingActionsV1Alpha2(tasks []v1alpha2.TaskWithMetadata, agentID string, ti, ai int) []*proto.RemainingAction {
+	var ras []*proto.RemainingAction
+	for i := ti; i < len(tasks); i++ {
+		if tasks[i].Metadata.AgentID != agentID {
+			continue
+		}
+		start := 0
+		if i == ti {
+			start = ai + 1
+		}
+		for _, a := range tasks[i].Actions[start:] {
+			if a.Metadata.State != v1alpha2.ActionStatePending {
+				continue
+			}
+			ras = append(ras, &proto.RemainingAction{
+				TaskId:   toPtr(string(tasks[i].Metadata.ID)),
+				ActionId: toPtr(string(a.Metadata.ID)),
+				Name:     toPtr(a.Name),
+				Image:    toPtr(a.Image),
+			})
+		}
+	}
+
+	return ras
+}
+
+func toActionResponse(wf *v1alpha2.Workflow, task *v1alpha2.TaskWithMetadata, action *v1alpha2.ActionWithMetadata) *proto.ActionResponse {
+	ar := &proto.ActionResponse{
+		WorkflowId: toPtr(wf.Namespace + "/" + wf.Name),
+		TaskId:     toPtr(string(task.Metadata.ID)),
+		AgentId:    toPtr(task.Metadata.AgentID),
+		ActionId:   toPtr(string(action.Metadata.ID)),
+		Name:       toPtr(action.Name),
+		Image:      toPtr(action.Image),
+		Entrypoint: toPtr(action.Command),
+		Command:    action.Args,
+		Volumes: func() []string {
+			vols := []string{}
+			for _, v := range task.Volumes {
+				vols = append(vols, string(v))
+			}
+			for _, v := range action.Volumes {
+				vols = append(vols, string(v))
+			}
+			return vols
+		}(),
+		Environment: func() []string {
+			// add task environment variables to the action environment variables.
+			joined := map[string]string{}
+			maps.Copy(joined, task.Env)
+			maps.Copy(joined, action.Env)
+			resp := []string{}
+			for k, v := range joined {
+				resp = append(resp, fmt.Sprintf("%s=%s", k, v))
+			}
+			sort.Strings(resp)
+			return resp
+		}(),
+	}
+	if action.TimeoutSeconds != nil {
+		ar.Timeout = toPtr(*action.TimeoutSeconds)
+	}
+	if action.Retries > 0 {
+		ar.Retries = toPtr(int64(action.Retries))
+	}
+	if action.Namespaces.Network != "" || action.Namespaces.PID != "" {
+		ar.Namespaces = &proto.Namespaces{
+			Network: toPtr(action.Namespaces.Network),
+			Pid:     toPtr(action.Namespaces.PID),
+		}
+	}
+	ar.Sandbox = sandboxToProto(action.Sandbox.ToV1Alpha1())
+
+	return ar
+}
+
+func (h *Handler) doReportActionStatusV1Alpha2(ctx context.Context, req *proto.ActionStatusRequest) (*proto.ActionStatusResponse, error) {
+	if req.GetWorkflowId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, errInvalidWorkflowID)
+	}
+	if req.GetTaskId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, errInvalidTaskName)
+	}
+	if req.GetActionId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, errInvalidActionName)
+	}
+	state, ok := toState(req.GetActionState())
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid action state: %v", req.GetActionState())
+	}
+
+	namespace, name, _ := strings.Cut(req.GetWorkflowId(), "/")
+	wf, err := h.BackendV1Alpha2.ReadWorkflowV1Alpha2(ctx, name, namespace)
+	if err != nil {
+		return nil, errors.Join(ErrBackendRead, status.Errorf(codes.Internal, "error getting workflow: %v", err))
+	}
+
+	for ti := range wf.Status.RenderedTasks {
+		task := &wf.Status.RenderedTasks[ti]
+		if string(task.Metadata.ID) != req.GetTaskId() || task.Metadata.AgentID != req.GetAgentId() {
+			continue
+		}
+		for ai := range task.Actions {
+			md := &task.Actions[ai].Metadata
+			if string(md.ID) != req.GetActionId() {
+				continue
+			}
+			md.State = state
+			md.StartTime = toMetaTime(req.GetExecutionStart())
+			md.EndTime = toMetaTime(req.GetExecutionStop())
+			md.ExecutionDuration = req.GetExecutionDuration()
+			md.Message = req.GetMessage().GetMessage()
+			wf.Status.Metadata.Task = task.Metadata
+			wf.Status.Metadata.Action = *md
+
+			if err := h.BackendV1Alpha2.UpdateWorkflowV1Alpha2(ctx, wf, data.UpdateOptions{StatusOnly: true}); err != nil {
+				return nil, status.Errorf(codes.Internal, "error writing report status: %v", err)
+			}
+			return &proto.ActionStatusResponse{}, nil
+		}
+	}
+
+	return &proto.ActionStatusResponse{}, status.Error(codes.NotFound, "action not found")
+}
+
+// doCheckActionV1Alpha2 tells the Agent to stop its Action once the Task or the Workflow has failed or timed out.
+// v1alpha2 Workflows have no cancel field, so these states are the only reasons to stop.
+func (h *Handler) doCheckActionV1Alpha2(ctx context.Context, req *proto.CheckActionRequest) (*proto.CheckActionResponse, error) {
+	if req.GetWorkflowId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, errInvalidWorkflowID)
+	}
+	if req.GetTaskId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, errInvalidTaskName)
+	}
+	if req.GetActionId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, errInvalidActionName)
+	}
+
+	namespace, name, _ := strings.Cut(req.GetWorkflowId(), "/")
+	wf, err := h.BackendV1Alpha2.ReadWorkflowV1Alpha2(ctx, name, namespace)
+	if err != nil {
+		return nil, errors.Join(ErrBackendRead, status.Errorf(codes.Internal, "error getting workflow: %v", err))
+	}
+
+	for _, task := range wf.Status.RenderedTasks {
+		if task.Metadata.AgentID != req.GetAgentId() {
+			continue
+		}
+		for _, action := range task.Actions {
+			if string(action.Metadata.ID) != req.GetActionId() {
+				continue
+			}
+			cancel := stopped(wf.Status.Metadata.Workflow.State) || stopped(task.Metadata.State)
+			if cancel {
+				journal.Log(ctx, "Workflow stopped, telling Agent to stop Action", "workflow", req.GetWorkflowId(), "action", req.GetActionId())
+			}
+			return &proto.CheckActionResponse{Cancel: toPtr(cancel)}, nil
+		}
+	}
+
+	return nil, status.Errorf(codes.PermissionDenied, "action is not assigned to agent %q", req.GetAgentId())
+}
+
+// stopped reports whether s is a state in which no more Actions should run.
+func stopped(s v1alpha2.State) bool {
+	return s == v1alpha2.StateFailed || s == v1alpha2.StateTimeout
+}
+
+// toState converts an Agent reported Action state to a v1alpha2 State.
+func toState(s proto.ActionStatusRequest_StateType) (v1alpha2.State, bool) {
+	switch s {
+	case proto.ActionStatusRequest_PENDING:
+		return v1alpha2.ActionStatePending, true
+	case proto.ActionStatusRequest_RUNNING:
+		return v1alpha2.ActionStateRunning, true
+	case proto.ActionStatusRequest_SUCCESS:
+		return v1alpha2.ActionStateSuccess, true
+	case proto.ActionStatusRequest_FAILED:
+		return v1alpha2.ActionStateFailed, true
+	case proto.ActionStatusRequest_TIMEOUT:
+		return v1alpha2.ActionStateTimeout, true
+	case proto.ActionStatusRequest_CANCELED:
+		// v1alpha2 has no canceled state.
+		return v1alpha2.ActionStateFailed, true
+	default:
+		return 0, false
+	}
+}
+
+// toMetaTime converts a protobuf timestamp to a metav1.Time. Unset and zero timestamps return nil.
+func toMetaTime(ts *timestamppb.Timestamp) *metav1.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.AsTime()
+	if t.IsZero() {
+		return nil
+	}
+	return &metav1.Time{Time: t}
+}
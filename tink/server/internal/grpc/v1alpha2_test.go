@@ -0,0 +1,473 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff/v5"
+	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	v1alpha2 "github.com/tinkerbell/tinkerbell/api/v1alpha2/tinkerbell"
+	"github.com/tinkerbell/tinkerbell/pkg/data"
+	"github.com/tinkerbell/tinkerbell/pkg/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func v1alpha2Workflow(state v1alpha2.State, actions ...v1alpha2.State) *v1alpha2.Workflow {
+	wf := &v1alpha2.Workflow{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "machine1",
+			Namespace: "default",
+		},
+		Status: v1alpha2.WorkflowStatus{
+			Metadata: v1alpha2.WorkflowMetadata{
+				Workflow: v1alpha2.Metadata{ID: "wf-id", Name: "machine1", State: state},
+			},
+			RenderedTasks: []v1alpha2.TaskWithMetadata{
+				{
+					Name:    "provision",
+					Env:     map[string]string{"TASK": "task", "OVERRIDE": "task"},
+					Volumes: []v1alpha2.Volume{"/dev:/dev"},
+					Metadata: v1alpha2.Metadata{
+						AgentID: "machine-mac-1",
+						ID:      "task-id",
+						Name:    "provision",
+						State:   v1alpha2.TaskStatePending,
+					},
+				},
+			},
+		},
+	}
+	names := []string{"stream", "kexec"}
+	for idx, s := range actions {
+		wf.Status.RenderedTasks[0].Actions = append(wf.Status.RenderedTasks[0].Actions, v1alpha2.ActionWithMetadata{
+			Action: v1alpha2.Action{
+				Name:           names[idx],
+				Image:          "quay.io/tinkerbell-actions/" + names[idx] + ":v1.0.0",
+				Command:        "/bin/" + names[idx],
+				Args:           []string{"--verbose"},
+				Env:            map[string]string{"OVERRIDE": "action"},
+				Volumes:        []v1alpha2.Volume{"/tmp:/tmp"},
+				TimeoutSeconds: toPtr(int64(300)),
+			},
+			Metadata: v1alpha2.Metadata{
+				AgentID: "machine-mac-1",
+				ID:      types.UID("action-id-" + names[idx]),
+				Name:    names[idx],
+				State:   s,
+			},
+		})
+	}
+	return wf
+}
+
+func TestGetActionV1Alpha2(t *testing.T) {
+	cases := map[string]struct {
+		workflow *v1alpha2.Workflow
+		request  *proto.ActionRequest
+		want     *proto.ActionResponse
+		wantErr  error
+	}{
+		"first Action": {
+			request:  &proto.ActionRequest{AgentId: toPtr("machine-mac-1")},
+			workflow: v1alpha2Workflow(v1alpha2.WorkflowStatePending, v1alpha2.ActionStatePending, v1alpha2.ActionStatePending),
+			want: &proto.ActionResponse{
+				WorkflowId:  toPtr("default/machine1"),
+				TaskId:      toPtr("task-id"),
+				AgentId:     toPtr("machine-mac-1"),
+				ActionId:    toPtr("action-id-stream"),
+				Name:        toPtr("stream"),
+				Image:       toPtr("quay.io/tinkerbell-actions/stream:v1.0.0"),
+				Timeout:     toPtr(int64(300)),
+				Entrypoint:  toPtr("/bin/stream"),
+				Command:     []string{"--verbose"},
+				Volumes:     []string{"/dev:/dev", "/tmp:/tmp"},
+				Environment: []string{"OVERRIDE=action", "TASK=task"},
+				RemainingActions: []*proto.RemainingAction{
+					{TaskId: toPtr("task-id"), ActionId: toPtr("action-id-kexec"), Name: toPtr("kexec"), Image: toPtr("quay.io/tinkerbell-actions/kexec:v1.0.0")},
+				},
+			},
+		},
+		"second Action": {
+			request:  &proto.ActionRequest{AgentId: toPtr("machine-mac-1")},
+			workflow: v1alpha2Workflow(v1alpha2.WorkflowStateRunning, v1alpha2.ActionStateSuccess, v1alpha2.ActionStatePending),
+			want: &proto.ActionResponse{
+				WorkflowId:  toPtr("default/machine1"),
+				TaskId:      toPtr("task-id"),
+				AgentId:     toPtr("machine-mac-1"),
+				ActionId:    toPtr("action-id-kexec"),
+				Name:        toPtr("kexec"),
+				Image:       toPtr("quay.io/tinkerbell-actions/kexec:v1.0.0"),
+				Timeout:     toPtr(int64(300)),
+				Entrypoint:  toPtr("/bin/kexec"),
+				Command:     []string{"--verbose"},
+				Volumes:     []string{"/dev:/dev", "/tmp:/tmp"},
+				Environment: []string{"OVERRIDE=action", "TASK=task"},
+			},
+		},
+		"running Action is served again": {
+			request:  &proto.ActionRequest{AgentId: toPtr("machine-mac-1")},
+			workflow: v1alpha2Workflow(v1alpha2.WorkflowStateRunning, v1alpha2.ActionStateRunning, v1alpha2.ActionStatePending),
+			want: &proto.ActionResponse{
+				WorkflowId:  toPtr("default/machine1"),
+				TaskId:      toPtr("task-id"),
+				AgentId:     toPtr("machine-mac-1"),
+				ActionId:    toPtr("action-id-stream"),
+				Name:        toPtr("stream"),
+				Image:       toPtr("quay.io/tinkerbell-actions/stream:v1.0.0"),
+				Timeout:     toPtr(int64(300)),
+				Entrypoint:  toPtr("/bin/stream"),
+				Command:     []string{"--verbose"},
+				Volumes:     []string{"/dev:/dev", "/tmp:/tmp"},
+				Environment: []string{"OVERRIDE=action", "TASK=task"},
+				RemainingActions: []*proto.RemainingAction{
+					{TaskId: toPtr("task-id"), ActionId: toPtr("action-id-kexec"), Name: toPtr("kexec"), Image: toPtr("quay.io/tinkerbell-actions/kexec:v1.0.0")},
+				},
+			},
+		},
+		"previous Action failed": {
+			request:  &proto.ActionRequest{AgentId: toPtr("machine-mac-1")},
+			workflow: v1alpha2Workflow(v1alpha2.WorkflowStateRunning, v1alpha2.ActionStateFailed, v1alpha2.ActionStatePending),
+			wantErr:  status.Error(codes.NotFound, "no Actions found"),
+		},
+		"all Actions successful": {
+			request:  &proto.ActionRequest{AgentId: toPtr("machine-mac-1")},
+			workflow: v1alpha2Workflow(v1alpha2.WorkflowStateRunning, v1alpha2.ActionStateSuccess, v1alpha2.ActionStateSuccess),
+			wantErr:  status.Error(codes.NotFound, "no Actions found"),
+		},
+		"workflow not rendered": {
+			request:  &proto.ActionRequest{AgentId: toPtr("machine-mac-1")},
+			workflow: v1alpha2Workflow(0),
+			wantErr:  status.Error(codes.NotFound, "no Actions found"),
+		},
+		"workflow disabled": {
+			request: &proto.ActionRequest{AgentId: toPtr("machine-mac-1")},
+			workflow: func() *v1alpha2.Workflow {
+				wf := v1alpha2Workflow(v1alpha2.WorkflowStatePending, v1alpha2.ActionStatePending)
+				wf.Spec.Disabled = toPtr(true)
+				return wf
+			}(),
+			wantErr: status.Error(codes.NotFound, "no Actions found"),
+		},
+		"task assigned to another Agent": {
+			request:  &proto.ActionRequest{AgentId: toPtr("machine-mac-2")},
+			workflow: v1alpha2Workflow(v1alpha2.WorkflowStatePending, v1alpha2.ActionStatePending),
+			wantErr:  status.Error(codes.NotFound, "no Actions found"),
+		},
+		"no workflows": {
+			request: &proto.ActionRequest{AgentId: toPtr("machine-mac-1")},
+			wantErr: status.Error(codes.NotFound, "no Workflows found"),
+		},
+		"invalid Agent ID": {
+			request: &proto.ActionRequest{},
+			wantErr: status.Error(codes.InvalidArgument, "invalid Agent ID"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			server := &Handler{
+				Logger:          logr.FromSlogHandler(slog.NewJSONHandler(os.Stdout, nil)),
+				BackendV1Alpha2: &mockBackendV1Alpha2{workflow: tc.workflow},
+				NowFunc:         func() time.Time { return time.Time{} },
+				RetryOptions:    []backoff.RetryOption{backoff.WithMaxTries(1)},
+			}
+
+			resp, gotErr := server.GetAction(context.Background(), tc.request)
+			compareErrors(t, gotErr, tc.wantErr)
+			if tc.want == nil {
+				return
+			}
+
+			if diff := cmp.Diff(resp, tc.want, cmpopts.IgnoreUnexported(proto.ActionResponse{}, proto.Namespaces{}, proto.RemainingAction{})); diff != "" {
+				t.Errorf("unexpected difference:\n%v", diff)
+			}
+		})
+	}
+}
+
+func TestReportActionStatusV1Alpha2(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	stop := start.Add(30 * time.Second)
+
+	tests := map[string]struct {
+		workflow     *v1alpha2.Workflow
+		request      *proto.ActionStatusRequest
+		writeErr     error
+		wantMetadata *v1alpha2.Metadata
+		wantErr      error
+	}{
+		"action success": {
+			workflow: v1alpha2Workflow(v1alpha2.WorkflowStateRunning, v1alpha2.ActionStateRunning, v1alpha2.ActionStatePending),
+			request: &proto.ActionStatusRequest{
+				WorkflowId:        toPtr("default/machine1"),
+				AgentId:           toPtr("machine-mac-1"),
+				TaskId:            toPtr("task-id"),
+				ActionId:          toPtr("action-id-stream"),
+				ActionName:        toPtr("stream"),
+				ActionState:       proto.ActionStatusRequest_SUCCESS.Enum(),
+				ExecutionStart:    timestamppb.New(start),
+				ExecutionStop:     timestamppb.New(stop),
+				ExecutionDuration: toPtr("30s"),
+				Message:           &proto.ActionMessage{Message: toPtr("done")},
+			},
+			wantMetadata: &v1alpha2.Metadata{
+				AgentID:           "machine-mac-1",
+				ID:                "action-id-stream",
+				Name:              "stream",
+				State:             v1alpha2.ActionStateSuccess,
+				StartTime:         &metav1.Time{Time: start},
+				EndTime:           &metav1.Time{Time: stop},
+				ExecutionDuration: "30s",
+				Message:           "done",
+			},
+		},
+		"action running without stop time": {
+			workflow: v1alpha2Workflow(v1alpha2.WorkflowStatePending, v1alpha2.ActionStatePending),
+			request: &proto.ActionStatusRequest{
+				WorkflowId:     toPtr("default/machine1"),
+				AgentId:        toPtr("machine-mac-1"),
+				TaskId:         toPtr("task-id"),
+				ActionId:       toPtr("action-id-stream"),
+				ActionState:    proto.ActionStatusRequest_RUNNING.Enum(),
+				ExecutionStart: timestamppb.New(start),
+				ExecutionStop:  timestamppb.New(time.Time{}),
+			},
+			wantMetadata: &v1alpha2.Metadata{
+				AgentID:   "machine-mac-1",
+				ID:        "action-id-stream",
+				Name:      "stream",
+				State:     v1alpha2.ActionStateRunning,
+				StartTime: &metav1.Time{Time: start},
+			},
+		},
+		"unknown action": {
+			workflow: v1alpha2Workflow(v1alpha2.WorkflowStateRunning, v1alpha2.ActionStateRunning),
+			request: &proto.ActionStatusRequest{
+				WorkflowId:  toPtr("default/machine1"),
+				AgentId:     toPtr("machine-mac-1"),
+				TaskId:      toPtr("task-id"),
+				ActionId:    toPtr("unknown"),
+				ActionState: proto.ActionStatusRequest_SUCCESS.Enum(),
+			},
+			wantErr: status.Error(codes.NotFound, "action not found"),
+		},
+		"wrong agent": {
+			workflow: v1alpha2Workflow(v1alpha2.WorkflowStateRunning, v1alpha2.ActionStateRunning),
+			request: &proto.ActionStatusRequest{
+				WorkflowId:  toPtr("default/machine1"),
+				AgentId:     toPtr("machine-mac-2"),
+				TaskId:      toPtr("task-id"),
+				ActionId:    toPtr("action-id-stream"),
+				ActionState: proto.ActionStatusRequest_SUCCESS.Enum(),
+			},
+			wantErr: status.Error(codes.NotFound, "action not found"),
+		},
+		"unspecified state": {
+			workflow: v1alpha2Workflow(v1alpha2.WorkflowStateRunning, v1alpha2.ActionStateRunning),
+			request: &proto.ActionStatusRequest{
+				WorkflowId:  toPtr("default/machine1"),
+				AgentId:     toPtr("machine-mac-1"),
+				TaskId:      toPtr("task-id"),
+				ActionId:    toPtr("action-id-stream"),
+				ActionState: proto.ActionStatusRequest_UNSPECIFIED.Enum(),
+			},
+			wantErr: status.Error(codes.InvalidArgument, "invalid action state: UNSPECIFIED"),
+		},
+		"missing workflow id": {
+			request: &proto.ActionStatusRequest{
+				AgentId:     toPtr("machine-mac-1"),
+				TaskId:      toPtr("task-id"),
+				ActionId:    toPtr("action-id-stream"),
+				ActionState: proto.ActionStatusRequest_SUCCESS.Enum(),
+			},
+			wantErr: status.Error(codes.InvalidArgument, errInvalidWorkflowID),
+		},
+		"write error": {
+			workflow: v1alpha2Workflow(v1alpha2.WorkflowStateRunning, v1alpha2.ActionStateRunning),
+			request: &proto.ActionStatusRequest{
+				WorkflowId:  toPtr("default/machine1"),
+				AgentId:     toPtr("machine-mac-1"),
+				TaskId:      toPtr("task-id"),
+				ActionId:    toPtr("action-id-stream"),
+				ActionState: proto.ActionStatusRequest_SUCCESS.Enum(),
+			},
+			writeErr: errors.New("write failed"),
+			wantErr:  status.Error(codes.Internal, "error writing report status: write failed"),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			backend := &mockBackendV1Alpha2{workflow: tc.workflow, writeErr: tc.writeErr}
+			handler := &Handler{
+				BackendV1Alpha2: backend,
+				RetryOptions:    []backoff.RetryOption{backoff.WithMaxTries(1)},
+			}
+
+			_, err := handler.ReportActionStatus(context.Background(), tc.request)
+			compareErrors(t, err, tc.wantErr)
+			if tc.wantMetadata == nil {
+				return
+			}
+
+			if backend.updated == nil {
+				t.Fatal("expected the workflow to be updated")
+			}
+			if !backend.updateOpts.StatusOnly {
+				t.Error("expected a status only update")
+			}
+			if diff := cmp.Diff(*tc.wantMetadata, backend.updated.Status.RenderedTasks[0].Actions[0].Metadata); diff != "" {
+				t.Errorf("unexpected action metadata (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(*tc.wantMetadata, backend.updated.Status.Metadata.Action); diff != "" {
+				t.Errorf("unexpected current action metadata (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCheckActionV1Alpha2(t *testing.T) {
+	request := func(agentID string) *proto.CheckActionRequest {
+		return &proto.CheckActionRequest{
+			WorkflowId: toPtr("default/machine1"),
+			TaskId:     toPtr("task-id"),
+			ActionId:   toPtr("action-id-stream"),
+			AgentId:    toPtr(agentID),
+		}
+	}
+	timedOutTask := v1alpha2Workflow(v1alpha2.WorkflowStateRunning, v1alpha2.ActionStateRunning)
+	timedOutTask.Status.RenderedTasks[0].Metadata.State = v1alpha2.TaskStateTimeout
+
+	tests := map[string]struct {
+		workflow *v1alpha2.Workflow
+		request  *proto.CheckActionRequest
+		want     *proto.CheckActionResponse
+		wantErr  error
+	}{
+		"running": {
+			workflow: v1alpha2Workflow(v1alpha2.WorkflowStateRunning, v1alpha2.ActionStateRunning),
+			request:  request("machine-mac-1"),
+			want:     &proto.CheckActionResponse{Cancel: toPtr(false)},
+		},
+		"workflow failed": {
+			workflow: v1alpha2Workflow(v1alpha2.WorkflowStateFailed, v1alpha2.ActionStateRunning),
+			request:  request("machine-mac-1"),
+			want:     &proto.CheckActionResponse{Cancel: toPtr(true)},
+		},
+		"workflow timed out": {
+			workflow: v1alpha2Workflow(v1alpha2.WorkflowStateTimeout, v1alpha2.ActionStateRunning),
+			request:  request("machine-mac-1"),
+			want:     &proto.CheckActionResponse{Cancel: toPtr(true)},
+		},
+		"task timed out": {
+			workflow: timedOutTask,
+			request:  request("machine-mac-1"),
+			want:     &proto.CheckActionResponse{Cancel: toPtr(true)},
+		},
+		"action of another agent": {
+			workflow: v1alpha2Workflow(v1alpha2.WorkflowStateFailed, v1alpha2.ActionStateRunning),
+			request:  request("machine-mac-2"),
+			wantErr:  status.Errorf(codes.PermissionDenied, "action is not assigned to agent %q", "machine-mac-2"),
+		},
+		"missing agent id": {
+			workflow: v1alpha2Workflow(v1alpha2.WorkflowStateFailed, v1alpha2.ActionStateRunning),
+			request:  request(""),
+			wantErr:  status.Errorf(codes.PermissionDenied, "action is not assigned to agent %q", ""),
+		},
+		"missing workflow id": {
+			request: &proto.CheckActionRequest{
+				TaskId:   toPtr("task-id"),
+				ActionId: toPtr("action-id-stream"),
+				AgentId:  toPtr("machine-mac-1"),
+			},
+			wantErr: status.Error(codes.InvalidArgument, errInvalidWorkflowID),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			handler := &Handler{BackendV1Alpha2: &mockBackendV1Alpha2{workflow: tc.workflow}}
+
+			got, err := handler.CheckAction(context.Background(), tc.request)
+			compareErrors(t, err, tc.wantErr)
+			if diff := cmp.Diff(tc.want, got, protocmp.Transform()); diff != "" {
+				t.Errorf("unexpected response (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+type mockBackendV1Alpha2 struct {
+	workflow *v1alpha2.Workflow
+	writeErr error
+
+	updated    *v1alpha2.Workflow // captures the workflow passed to UpdateWorkflowV1Alpha2
+	updateOpts data.UpdateOptions // captures the options passed to UpdateWorkflowV1Alpha2
+}
+
+func (m *mockBackendV1Alpha2) ReadWorkflowV1Alpha2(_ context.Context, _ string, _ string) (*v1alpha2.Workflow, error) {
+	if m.workflow == nil {
+		return nil, errors.New("workflow not found")
+	}
+	return m.workflow, nil
+}
+
+func (m *mockBackendV1Alpha2) ListWorkflowsV1Alpha2(_ context.Context, _ data.WorkflowFilter) ([]v1alpha2.Workflow, error) {
+	if m.workflow != nil {
+		return []v1alpha2.Workflow{*m.workflow}, nil
+	}
+	return []v1alpha2.Workflow{}, nil
+}
+
+func (m *mockBackendV1Alpha2) UpdateWorkflowV1Alpha2(_ context.Context, wf *v1alpha2.Workflow, opts data.UpdateOptions) error {
+	m.updated = wf
+	m.updateOpts = opts
+	return m.writeErr
+}
+
+func TestSandboxV1Alpha2ToProto(t *testing.T) {
+	tests := map[string]struct {
+		input *v1alpha2.Sandbox
+		want  *proto.Sandbox
+	}{
+		"nil input": {},
+		"privileged not set": {
+			input: &v1alpha2.Sandbox{ReadOnlyRootFilesystem: true},
+			want:  &proto.Sandbox{ReadOnlyRootFilesystem: toPtr(true)},
+		},
+		"all fields": {
+			input: &v1alpha2.Sandbox{
+				Resources:    &v1alpha2.SandboxResources{CPU: "500m", Memory: "512Mi", PIDs: 128},
+				Capabilities: &v1alpha2.SandboxCapabilities{Add: []string{"SYS_ADMIN"}, Drop: []string{"ALL"}},
+				Privileged:   toPtr(false),
+			},
+			want: &proto.Sandbox{
+				Cpu:              toPtr("500m"),
+				Memory:           toPtr("512Mi"),
+				Pids:             toPtr(int64(128)),
+				CapabilitiesAdd:  []string{"SYS_ADMIN"},
+				CapabilitiesDrop: []string{"ALL"},
+				Privileged:       toPtr(false),
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, sandboxToProto(tc.input.ToV1Alpha1()), protocmp.Transform()); diff != "" {
+				t.Errorf("sandboxToProto() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package nats
+
+import (
+	"strings"
+	"time"
+
+	"github.com/tinkerbell/tinkerbell/pkg/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// action is an Action in the JSON encoding the Agent's NATS transport reads.
+// Actions are published as a list, the Agent runs them in order.
+type action struct {
+	AgentID           string     `json:"agent_id"`
+	TaskID            string     `json:"task_id"`
+	WorkflowID        string     `json:"workflow_id"`
+	ID                string     `json:"id"`
+	Name              string     `json:"name"`
+	Image             string     `json:"image"`
+	Type              string     `json:"type,omitempty"`
+	Cmd               string     `json:"cmd,omitempty"`
+	Args              []string   `json:"args,omitempty"`
+	Env               []env      `json:"env,omitempty"`
+	Volumes           []string   `json:"volumes,omitempty"`
+	Namespaces        namespaces `json:"namespaces,omitzero"`
+	Sandbox           sandbox    `json:"sandbox,omitzero"`
+	Retries           int        `json:"retries,omitempty"`
+	TimeoutSeconds    int        `json:"timeoutSeconds,omitempty"`
+	RetryDelaySeconds int        `json:"retryDelaySeconds,omitempty"`
+	Attempt           int        `json:"attempt,omitempty"`
+	ExecutionStart    time.Time  `json:"executionStart,omitzero"`
+	ExecutionStop     time.Time  `json:"executionStop,omitzero"`
+	ExecutionDuration string     `json:"executionDuration,omitempty"`
+	OnTimeout         []string   `json:"onTimeout,omitempty"`
+	OnFailure         []string   `json:"onFailure,omitempty"`
+	RemainingImages   []string   `json:"remainingImages,omitempty"`
+}
+
+type env struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type sandbox struct {
+	CPU                    string   `json:"cpu,omitempty"`
+	Memory                 string   `json:"memory,omitempty"`
+	PIDs                   int64    `json:"pids,omitempty"`
+	CapAdd                 []string `json:"capAdd,omitempty"`
+	CapDrop                []string `json:"capDrop,omitempty"`
+	ReadOnlyRootFilesystem bool     `json:"readOnlyRootFilesystem,omitempty"`
+	Privileged             *bool    `json:"privileged,omitempty"`
+}
+
+type namespaces struct {
+	Network string `json:"network,omitempty"`
+	PID     string `json:"pid,omitempty"`
+}
+
+// event is the status of an Action in the JSON encoding the Agent's NATS transport publishes.
+type event struct {
+	Action  action            `json:"action"`
+	Message string            `json:"message,omitempty"`
+	State   string            `json:"state"`
+	Hook    *hook             `json:"hook,omitempty"`
+	Outputs map[string]string `json:"outputs,omitempty"`
+}
+
+type hook struct {
+	Name    string `json:"name"`
+	State   string `json:"state"`
+	Message string `json:"message,omitempty"`
+}
+
+// actionFromProto converts an Action served by GetAction to the Agent's encoding.
+func actionFromProto(ar *proto.ActionResponse) action {
+	a := action{
+		AgentID:           ar.GetAgentId(),
+		TaskID:            ar.GetTaskId(),
+		WorkflowID:        ar.GetWorkflowId(),
+		ID:                ar.GetActionId(),
+		Name:              ar.GetName(),
+		Image:             ar.GetImage(),
+		Cmd:               ar.GetEntrypoint(),
+		Args:              ar.GetCommand(),
+		Volumes:           ar.GetVolumes(),
+		Retries:           int(ar.GetRetries()),
+		TimeoutSeconds:    int(ar.GetTimeout()),
+		RetryDelaySeconds: int(ar.GetRetryDelay()),
+		OnTimeout:         ar.GetOnTimeout(),
+		OnFailure:         ar.GetOnFailure(),
+		Namespaces:        namespaces{PID: ar.GetPid()},
+	}
+	if ar.GetType() == proto.ActionType_ACTION_TYPE_NATIVE {
+		a.Type = "native"
+	}
+	if sb := ar.GetSandbox(); sb != nil {
+		a.Sandbox = sandbox{
+			CPU:                    sb.GetCpu(),
+			Memory:                 sb.GetMemory(),
+			PIDs:                   sb.GetPids(),
+			CapAdd:                 sb.GetCapabilitiesAdd(),
+			CapDrop:                sb.GetCapabilitiesDrop(),
+			ReadOnlyRootFilesystem: sb.GetReadOnlyRootFilesystem(),
+			Privileged:             sb.Privileged,
+		}
+	}
+	for _, ra := range ar.GetRemainingActions() {
+		a.RemainingImages = append(a.RemainingImages, ra.GetImage())
+	}
+	for _, v := range ar.GetEnvironment() {
+		k, val, _ := strings.Cut(v, "=")
+		a.Env = append(a.Env, env{Key: k, Value: val})
+	}
+	if ns := ar.GetNamespaces(); ns != nil {
+		a.Namespaces.Network = ns.GetNetwork()
+		if ns.GetPid() != "" {
+			a.Namespaces.PID = ns.GetPid()
+		}
+	}
+
+	return a
+}
+
+// toProto converts an event published by agentID to the request the Agent's gRPC transport sends for it.
+func (e event) toProto(agentID string) *proto.ActionStatusRequest {
+	if e.Action.AgentID != "" {
+		agentID = e.Action.AgentID
+	}
+	r := &proto.ActionStatusRequest{
+		WorkflowId:        toPtr(e.Action.WorkflowID),
+		AgentId:           toPtr(agentID),
+		TaskId:            toPtr(e.Action.TaskID),
+		ActionId:          toPtr(e.Action.ID),
+		ActionName:        toPtr(e.Action.Name),
+		ActionState:       stateToProto(e.State),
+		ExecutionStart:    timestamppb.New(e.Action.ExecutionStart),
+		ExecutionStop:     timestamppb.New(e.Action.ExecutionStop),
+		ExecutionDuration: toPtr(e.Action.ExecutionDuration),
+		Message:           &proto.ActionMessage{Message: toPtr(e.Message)},
+		Outputs:           e.Outputs,
+	}
+	if e.Action.Attempt > 0 {
+		r.Attempt = toPtr(int64(e.Action.Attempt))
+	}
+	if e.Hook != nil {
+		r.Hook = &proto.ActionHook{
+			Name:    toPtr(e.Hook.Name),
+			State:   stateToProto(e.Hook.State),
+			Message: toPtr(e.Hook.Message),
+		}
+	}
+
+	return r
+}
+
+// stateToProto converts the state of an event.
+func stateToProto(s string) *proto.ActionStatusRequest_StateType {
+	switch s {
+	case "running":
+		return toPtr(proto.ActionStatusRequest_RUNNING)
+	case "success":
+		return toPtr(proto.ActionStatusRequest_SUCCESS)
+	case "failure":
+		return toPtr(proto.ActionStatusRequest_FAILED)
+	case "timeout":
+		return toPtr(proto.ActionStatusRequest_TIMEOUT)
+	case "canceled":
+		return toPtr(proto.ActionStatusRequest_CANCELED)
+	default:
+		return toPtr(proto.ActionStatusRequest_UNSPECIFIED)
+	}
+}
+
+func toPtr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,211 @@
+// Package nats bridges Workflows to Agents that use the NATS transport.
+// Actions are published to <stream>.<agentID>.<actions> and the events Agents publish to
+// <stream>.<agentID>.<events> are reported back as Action statuses.
+package nats
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/nats-io/nats.go"
+	"github.com/tinkerbell/tinkerbell/pkg/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	DefaultStreamName     = "tinkerbell"
+	DefaultActionsSubject = "workflow_actions"
+	DefaultEventsSubject  = "workflow_status"
+
+	defaultResyncInterval = 30 * time.Second
+)
+
+// ActionServer serves Actions and records their status, the same way it does for Agents using gRPC.
+type ActionServer interface {
+	// NextAction returns the next Action of an Agent, without running auto capabilities for unknown Agents.
+	// It returns a NotFound or FailedPrecondition status when there is none.
+	NextAction(ctx context.Context, req *proto.ActionRequest) (*proto.ActionResponse, error)
+	ReportActionStatus(ctx context.Context, req *proto.ActionStatusRequest) (*proto.ActionStatusResponse, error)
+}
+
+// WorkflowWatcher notifies about Workflow changes.
+type WorkflowWatcher interface {
+	// WatchWorkflows calls notify with the Agent IDs of a Workflow every time the Workflow is added or updated.
+	WatchWorkflows(ctx context.Context, notify func(agentIDs []string)) error
+}
+
+// Config is a bridge between Workflows and Agents that use the NATS transport.
+type Config struct {
+	// URL is the URL of the NATS server, for example nats://192.168.2.50:4222.
+	URL            string
+	StreamName     string
+	ActionsSubject string
+	EventsSubject  string
+	// ResyncInterval is how often Agents that publish events on NATS are published their next Action without a Workflow change.
+	// Defaults to 30 seconds.
+	ResyncInterval time.Duration
+	Log            logr.Logger
+	Server         ActionServer
+	Watcher        WorkflowWatcher
+
+	mu      sync.Mutex
+	pending map[string]struct{}
+	// active are the Agents that published events on NATS and still have a Workflow.
+	active map[string]struct{}
+	wake   chan struct{}
+}
+
+// Start connects to the NATS server and bridges Workflows until ctx is done.
+func (c *Config) Start(ctx context.Context) error {
+	c.wake = make(chan struct{}, 1)
+	nc, err := nats.Connect(c.URL, nats.Name("tink-server"), nats.RetryOnFailedConnect(true), nats.MaxReconnects(-1))
+	if err != nil {
+		return fmt.Errorf("error connecting to NATS: %w", err)
+	}
+	defer nc.Close()
+
+	sub, err := nc.Subscribe(fmt.Sprintf("%v.*.%v", c.StreamName, c.EventsSubject), func(m *nats.Msg) {
+		c.handleEvent(ctx, m)
+	})
+	if err != nil {
+		return fmt.Errorf("error subscribing to events: %w", err)
+	}
+	defer func() {
+		_ = sub.Unsubscribe()
+	}()
+	if err := c.Watcher.WatchWorkflows(ctx, c.notify); err != nil {
+		return fmt.Errorf("error watching workflows: %w", err)
+	}
+	c.Log.Info("bridging workflows to NATS", "url", c.URL, "stream", c.StreamName)
+
+	interval := c.ResyncInterval
+	if interval <= 0 {
+		interval = defaultResyncInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	// sent is the key of the Action last published to each Agent.
+	// Running Actions are served again by NextAction, they must not be published again.
+	// Only active Agents are resynced, Agents using gRPC are woken by the same Workflows but never publish events.
+	sent := map[string]string{}
+	for {
+		var agents map[string]struct{}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-c.wake:
+			// Workflows wake Agents using gRPC too, only Agents that published on NATS are published Actions.
+			c.mu.Lock()
+			agents = make(map[string]struct{}, len(c.pending))
+			for id := range c.pending {
+				if _, ok := c.active[id]; ok {
+					agents[id] = struct{}{}
+				}
+			}
+			c.pending = nil
+			c.mu.Unlock()
+		case <-ticker.C:
+			c.mu.Lock()
+			agents = make(map[string]struct{}, len(c.active))
+			for id := range c.active {
+				agents[id] = struct{}{}
+			}
+			c.mu.Unlock()
+		}
+		for id := range agents {
+			key, err := c.publish(ctx, nc, id, sent[id])
+			if err != nil {
+				c.Log.V(1).Info("error publishing action", "agent", id, "error", err)
+				continue
+			}
+			if key == "" {
+				// The Agent has no Workflow left, stop resyncing it until it publishes again.
+				delete(sent, id)
+				c.mu.Lock()
+				delete(c.active, id)
+				c.mu.Unlock()
+				continue
+			}
+			sent[id] = key
+		}
+	}
+}
+
+// publish publishes the next Action of agentID, unless its key is sent. It returns the key of the Action
+// the Agent has, which is empty when there is none.
+func (c *Config) publish(ctx context.Context, nc *nats.Conn, agentID, sent string) (string, error) {
+	ar, err := c.Server.NextAction(ctx, &proto.ActionRequest{AgentId: toPtr(agentID)})
+	if err != nil {
+		switch status.Code(err) { //nolint:exhaustive // only the codes NextAction uses for no Action.
+		case codes.NotFound, codes.FailedPrecondition:
+			return "", nil
+		}
+		return sent, err
+	}
+	key := ar.GetWorkflowId() + "/" + ar.GetTaskId() + "/" + ar.GetActionId()
+	if key == sent {
+		return sent, nil
+	}
+	data, err := json.Marshal([]action{actionFromProto(ar)})
+	if err != nil {
+		return sent, err
+	}
+	if err := nc.Publish(fmt.Sprintf("%v.%v.%v", c.StreamName, agentID, c.ActionsSubject), data); err != nil {
+		return sent, err
+	}
+	c.Log.V(1).Info("published action", "agent", agentID, "action", key)
+
+	return key, nil
+}
+
+// handleEvent reports the status of an Action published by an Agent and looks for the Agent's next Action.
+// Agents publish an event without an Action when they start, so only Agents using NATS are published Actions.
+func (c *Config) handleEvent(ctx context.Context, m *nats.Msg) {
+	// The subject is <stream>.<agentID>.<events>.
+	parts := strings.Split(m.Subject, ".")
+	if len(parts) != 3 {
+		return
+	}
+	agentID := parts[1]
+	var e event
+	if err := json.Unmarshal(m.Data, &e); err != nil {
+		c.Log.Info("error decoding event", "agent", agentID, "error", err)
+		return
+	}
+	// An event without an Action announces the Agent, it has nothing to report.
+	if e.Action.ID != "" {
+		if _, err := c.Server.ReportActionStatus(ctx, e.toProto(agentID)); err != nil {
+			c.Log.Info("error reporting action status", "agent", agentID, "action", e.Action.ID, "error", err)
+		}
+	}
+	c.mu.Lock()
+	if c.active == nil {
+		c.active = map[string]struct{}{}
+	}
+	c.active[agentID] = struct{}{}
+	c.mu.Unlock()
+	c.notify([]string{agentID})
+}
+
+// notify queues agentIDs to be published their next Action.
+func (c *Config) notify(agentIDs []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.pending == nil {
+		c.pending = map[string]struct{}{}
+	}
+	for _, id := range agentIDs {
+		c.pending[id] = struct{}{}
+	}
+	select {
+	case c.wake <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,230 @@
+package nats
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/tinkerbell/tinkerbell/pkg/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// runServer starts an embedded NATS server on a random port.
+func runServer(t *testing.T) *server.Server {
+	t.Helper()
+	s, err := server.NewServer(&server.Options{Host: "127.0.0.1", Port: server.RANDOM_PORT, NoLog: true, NoSigs: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	go s.Start()
+	if !s.ReadyForConnections(5 * time.Second) {
+		t.Fatal("NATS server not ready")
+	}
+	t.Cleanup(s.Shutdown)
+
+	return s
+}
+
+// mockActionServer serves action to agent1 until it is reported complete.
+type mockActionServer struct {
+	mu       sync.Mutex
+	action   *proto.ActionResponse
+	reported chan *proto.ActionStatusRequest
+	// calls counts the NextAction calls for each Agent.
+	calls map[string]int
+}
+
+func (m *mockActionServer) NextAction(_ context.Context, req *proto.ActionRequest) (*proto.ActionResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.calls == nil {
+		m.calls = map[string]int{}
+	}
+	m.calls[req.GetAgentId()]++
+	if req.GetAgentId() != "agent1" || m.action == nil {
+		return nil, status.Error(codes.NotFound, "no workflow")
+	}
+	return m.action, nil
+}
+
+func (m *mockActionServer) ReportActionStatus(_ context.Context, req *proto.ActionStatusRequest) (*proto.ActionStatusResponse, error) {
+	m.mu.Lock()
+	if req.GetActionState() != proto.ActionStatusRequest_RUNNING {
+		m.action = nil
+	}
+	m.mu.Unlock()
+	m.reported <- req
+	return &proto.ActionStatusResponse{}, nil
+}
+
+func (m *mockActionServer) nextActionCalls(agentID string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.calls[agentID]
+}
+
+type mockWatcher struct {
+	agentIDs []string
+}
+
+func (m *mockWatcher) WatchWorkflows(_ context.Context, notify func(agentIDs []string)) error {
+	notify(m.agentIDs)
+	return nil
+}
+
+func TestBridge(t *testing.T) {
+	s := runServer(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The Agent's side of the bridge.
+	agent, err := nats.Connect(s.ClientURL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer agent.Close()
+	actions, err := agent.SubscribeSync("tinkerbell.agent1.workflow_actions")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	as := &mockActionServer{
+		action: &proto.ActionResponse{
+			WorkflowId:  toPtr("default/wf1"),
+			TaskId:      toPtr("task1"),
+			AgentId:     toPtr("agent1"),
+			ActionId:    toPtr("action1"),
+			Name:        toPtr("stream"),
+			Image:       toPtr("quay.io/tinkerbell/actions/image2disk"),
+			Environment: []string{"IMG_URL=http://10.1.1.11/image.raw", "COMPRESSED"},
+			Timeout:     toPtr(int64(600)),
+		},
+		reported: make(chan *proto.ActionStatusRequest, 1),
+	}
+	c := &Config{
+		URL:            s.ClientURL(),
+		StreamName:     DefaultStreamName,
+		ActionsSubject: DefaultActionsSubject,
+		EventsSubject:  DefaultEventsSubject,
+		ResyncInterval: 10 * time.Millisecond,
+		Log:            logr.Discard(),
+		Server:         as,
+		Watcher:        &mockWatcher{agentIDs: []string{"agent1", "agent2"}},
+	}
+	done := make(chan error)
+	go func() { done <- c.Start(ctx) }()
+
+	// Nothing is published to agent1 until it announces itself on NATS.
+	if msg, err := actions.NextMsg(100 * time.Millisecond); err == nil {
+		t.Fatalf("expected no action before agent1 announced itself, got %s", msg.Data)
+	}
+	if err := agent.Publish("tinkerbell.agent1.workflow_status", []byte(`{"action":{"agent_id":"agent1"}}`)); err != nil {
+		t.Fatal(err)
+	}
+	msg, err := actions.NextMsg(5 * time.Second)
+	if err != nil {
+		t.Fatalf("expected an action to be published: %v", err)
+	}
+	var got []action
+	if err := json.Unmarshal(msg.Data, &got); err != nil {
+		t.Fatal(err)
+	}
+	want := []action{{
+		AgentID:        "agent1",
+		TaskID:         "task1",
+		WorkflowID:     "default/wf1",
+		ID:             "action1",
+		Name:           "stream",
+		Image:          "quay.io/tinkerbell/actions/image2disk",
+		Env:            []env{{Key: "IMG_URL", Value: "http://10.1.1.11/image.raw"}, {Key: "COMPRESSED"}},
+		TimeoutSeconds: 600,
+	}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected action (-want +got):\n%s", diff)
+	}
+	if msg, err := actions.NextMsg(100 * time.Millisecond); err == nil {
+		t.Errorf("expected the action to be published once, got %s", msg.Data)
+	}
+	// Agents that never published an event, like agent2 using gRPC, are never published Actions.
+	if got := as.nextActionCalls("agent2"); got != 0 {
+		t.Errorf("expected agent2 not to be looked up, got %d", got)
+	}
+	select {
+	case req := <-as.reported:
+		t.Fatalf("expected the announcement not to be reported, got %v", req)
+	default:
+	}
+
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	ev := `{"action":{"agent_id":"agent1","task_id":"task1","workflow_id":"default/wf1","id":"action1","name":"stream",` +
+		`"image":"quay.io/tinkerbell/actions/image2disk","executionStart":"2025-01-01T12:00:00Z","executionStop":"2025-01-01T12:01:00Z",` +
+		`"executionDuration":"1m0s","attempt":1},"message":"done","state":"success","outputs":{"disk":"/dev/sda"}}`
+	if err := agent.Publish("tinkerbell.agent1.workflow_status", []byte(ev)); err != nil {
+		t.Fatal(err)
+	}
+	var req *proto.ActionStatusRequest
+	select {
+	case req = <-as.reported:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the event to be reported")
+	}
+	wantReq := &proto.ActionStatusRequest{
+		WorkflowId:        toPtr("default/wf1"),
+		AgentId:           toPtr("agent1"),
+		TaskId:            toPtr("task1"),
+		ActionId:          toPtr("action1"),
+		ActionName:        toPtr("stream"),
+		ActionState:       toPtr(proto.ActionStatusRequest_SUCCESS),
+		ExecutionStart:    timestamppb.New(start),
+		ExecutionStop:     timestamppb.New(start.Add(time.Minute)),
+		ExecutionDuration: toPtr("1m0s"),
+		Message:           &proto.ActionMessage{Message: toPtr("done")},
+		Attempt:           toPtr(int64(1)),
+		Outputs:           map[string]string{"disk": "/dev/sda"},
+	}
+	if diff := cmp.Diff(wantReq, req, protocmp.Transform()); diff != "" {
+		t.Errorf("unexpected action status (-want +got):\n%s", diff)
+	}
+	// agent1 is resynced once it published, until it has no Action left.
+	deadline := time.Now().Add(5 * time.Second)
+	for as.nextActionCalls("agent1") < 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	calls := as.nextActionCalls("agent1")
+	time.Sleep(100 * time.Millisecond)
+	if got := as.nextActionCalls("agent1"); got != calls {
+		t.Errorf("expected agent1 to stop being resynced after its last action, got %d lookups, want %d", got, calls)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+}
+
+func TestStateToProto(t *testing.T) {
+	tests := map[string]proto.ActionStatusRequest_StateType{
+		"running":  proto.ActionStatusRequest_RUNNING,
+		"success":  proto.ActionStatusRequest_SUCCESS,
+		"failure":  proto.ActionStatusRequest_FAILED,
+		"timeout":  proto.ActionStatusRequest_TIMEOUT,
+		"canceled": proto.ActionStatusRequest_CANCELED,
+		"unknown":  proto.ActionStatusRequest_UNSPECIFIED,
+	}
+	for state, want := range tests {
+		t.Run(state, func(t *testing.T) {
+			if got := stateToProto(state); *got != want {
+				t.Errorf("stateToProto(%q) = %v, want %v", state, *got, want)
+			}
+		})
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/tinkerbell/tinkerbell/pkg/proto"
 	grpcinternal "github.com/tinkerbell/tinkerbell/tink/server/internal/grpc"
+	natsinternal "github.com/tinkerbell/tinkerbell/tink/server/internal/nats"
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
@@ -32,11 +33,33 @@ func init() {
 }
 
 type Config struct {
-	Backend      grpcinternal.Backend
-	BindAddrPort netip.AddrPort
-	Logger       logr.Logger
-	Auto         AutoCapabilities
-	TLS          TLS
+	Backend grpcinternal.Backend
+	// BackendV1Alpha2, when set, is used to serve Actions from v1alpha2 Workflows instead of v1alpha1 Workflows.
+	BackendV1Alpha2 grpcinternal.BackendV1Alpha2
+	// ActionLogWriter, when set, persists the output of Actions streamed by Agents.
+	ActionLogWriter grpcinternal.ActionLogWriter
+	// WorkflowWatcher, when set, is used to push Actions to connected Agents as soon as their v1alpha1 Workflows change.
+	WorkflowWatcher grpcinternal.WorkflowWatcher
+	BindAddrPort    netip.AddrPort
+	Logger          logr.Logger
+	Auto            AutoCapabilities
+	TLS             TLS
+	// AgentAuth, when enabled, authenticates Agents and rejects requests an Agent makes for another Agent. It requires TLS.
+	AgentAuth AgentAuth
+	// NATS bridges v1alpha1 Workflows to Agents that use the NATS transport. It requires WorkflowWatcher.
+	NATS NATS
+	// NativeCommands are the host commands, as absolute paths or path.Match patterns, that native Actions may run.
+	// No native Action is served when empty.
+	NativeCommands []string
+}
+
+// NATS configures the bridge to Agents that use the NATS transport.
+type NATS struct {
+	// URL is the URL of the NATS server. The bridge is disabled when empty.
+	URL            string
+	StreamName     string
+	ActionsSubject string
+	EventsSubject  string
 }
 
 type AutoCapabilities struct {
@@ -62,6 +85,18 @@ type TLS struct {
 	Cert credentials.TransportCredentials
 }
 
+// AgentAuth configures how Agents are authenticated. It is enabled when either field is set.
+type AgentAuth struct {
+	// ClientCertificates identifies Agents by their TLS client certificate. TLS.Cert must verify client certificates.
+	ClientCertificates bool
+	// Tokens are bootstrap tokens, each mapped to the ID of the Agent it identifies, for Agents without a client certificate.
+	Tokens map[string]string
+}
+
+func (a AgentAuth) enabled() bool {
+	return a.ClientCertificates || len(a.Tokens) > 0
+}
+
 // Option is a functional option type.
 type Option func(*Config)
 
@@ -86,6 +121,13 @@ func WithBackend(b grpcinternal.Backend) Option {
 	}
 }
 
+// WithBackendV1Alpha2 sets the v1alpha2 backend for the server.
+func WithBackendV1Alpha2(b grpcinternal.BackendV1Alpha2) Option {
+	return func(c *Config) {
+		c.BackendV1Alpha2 = b
+	}
+}
+
 // WithBindAddrPort sets the bind address and port for the server.
 func WithBindAddrPort(addrPort netip.AddrPort) Option {
 	return func(c *Config) {
@@ -108,7 +150,13 @@ func WithTLSCert(cert credentials.TransportCredentials) Option {
 }
 
 func NewConfig(opts ...Option) *Config {
-	c := &Config{}
+	c := &Config{
+		NATS: NATS{
+			StreamName:     natsinternal.DefaultStreamName,
+			ActionsSubject: natsinternal.DefaultActionsSubject,
+			EventsSubject:  natsinternal.DefaultEventsSubject,
+		},
+	}
 	for _, opt := range opts {
 		opt(c)
 	}
@@ -116,10 +164,16 @@ func NewConfig(opts ...Option) *Config {
 }
 
 func (c *Config) Start(ctx context.Context, log logr.Logger) error {
+	if c.AgentAuth.enabled() && c.TLS.Cert == nil {
+		return fmt.Errorf("agent authentication requires TLS")
+	}
 	s := &grpcinternal.Handler{
-		Backend: c.Backend,
-		Logger:  log,
-		NowFunc: time.Now,
+		Backend:         c.Backend,
+		BackendV1Alpha2: c.BackendV1Alpha2,
+		ActionLogWriter: c.ActionLogWriter,
+		NativeCommands:  c.NativeCommands,
+		Logger:          log,
+		NowFunc:         time.Now,
 		AutoCapabilities: grpcinternal.AutoCapabilities{
 			Enrollment: grpcinternal.AutoEnrollment{
 				Enabled:               c.Auto.Enrollment.Enabled,
@@ -136,10 +190,45 @@ func (c *Config) Start(ctx context.Context, log logr.Logger) error {
 		},
 	}
 
+	if c.WorkflowWatcher != nil && c.BackendV1Alpha2 == nil {
+		if err := s.WatchWorkflows(ctx, c.WorkflowWatcher); err != nil {
+			// Connected Agents still get their Actions, only later.
+			log.Error(err, "failed to watch workflows, actions will not be pushed to connected agents as soon as they are runnable")
+		}
+	}
+
+	if c.NATS.URL != "" {
+		if c.WorkflowWatcher == nil || c.BackendV1Alpha2 != nil {
+			return fmt.Errorf("the NATS bridge requires v1alpha1 Workflows and a backend that watches them")
+		}
+		bridge := &natsinternal.Config{
+			URL:            c.NATS.URL,
+			StreamName:     c.NATS.StreamName,
+			ActionsSubject: c.NATS.ActionsSubject,
+			EventsSubject:  c.NATS.EventsSubject,
+			Log:            log.WithName("nats"),
+			Server:         s,
+			Watcher:        c.WorkflowWatcher,
+		}
+		go func() {
+			if err := bridge.Start(ctx); err != nil {
+				log.Error(err, "NATS bridge stopped, actions are not published to agents using the NATS transport")
+			}
+		}()
+	}
+
+	unary := []grpc.UnaryServerInterceptor{grpcServerMetrics.UnaryServerInterceptor()}
+	stream := []grpc.StreamServerInterceptor{grpcServerMetrics.StreamServerInterceptor()}
+	if c.AgentAuth.enabled() {
+		auth := &grpcinternal.AgentAuth{Tokens: c.AgentAuth.Tokens}
+		unary = append(unary, auth.UnaryServerInterceptor())
+		stream = append(stream, auth.StreamServerInterceptor())
+		log.Info("agent authentication enabled", "clientCertificates", c.AgentAuth.ClientCertificates, "tokens", len(c.AgentAuth.Tokens))
+	}
 	params := []grpc.ServerOption{
 		grpc.StatsHandler(otelgrpc.NewServerHandler()),
-		grpc.UnaryInterceptor(grpcServerMetrics.UnaryServerInterceptor()),
-		grpc.StreamInterceptor(grpcServerMetrics.StreamServerInterceptor()),
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
 	}
 	if c.TLS.Cert != nil {
 		params = append(params, grpc.Creds(c.TLS.Cert))
@@ -185,6 +274,8 @@ type allInterfaces interface {
 	grpcinternal.HardwareFilterer
 	grpcinternal.WorkflowRuleSetLister
 	grpcinternal.WorkflowCreator
+	grpcinternal.ActionLogWriter
+	grpcinternal.WorkflowWatcher
 }
 
 // SetBackends is a helper function to set a single backend implementation for all backend interfaces.
@@ -195,4 +286,6 @@ func (c *Config) SetBackends(b allInterfaces) {
 	c.Auto.Discovery.HardwareFilterer = b
 	c.Auto.Enrollment.WorkflowRuleSetLister = b
 	c.Auto.Enrollment.WorkflowCreator = b
+	c.ActionLogWriter = b
+	c.WorkflowWatcher = b
 }
@@ -237,17 +237,18 @@ func HandleHardwareDetail(c *gin.Context, log logr.Logger) {
 	}
 
 	hwDetail := templates.HardwareDetail{
-		Name:            hw.Name,
-		Namespace:       hw.Namespace,
-		Interfaces:      GetHardwareInterfaces(*hw),
-		Status:          GetHardwareStatus(*hw),
-		CreatedAt:       hw.GetCreationTimestamp().Format("2006-01-02 15:04:05"),
-		Labels:          hw.Labels,
-		Annotations:     hw.Annotations,
-		AgentAttributes: agentAttrs,
-		SpecYAML:        string(specYAML),
-		StatusYAML:      string(statusYAML),
-		YAML:            string(yamlBytes),
+		Name:             hw.Name,
+		Namespace:        hw.Namespace,
+		Interfaces:       GetHardwareInterfaces(*hw),
+		Status:           GetHardwareStatus(*hw),
+		CreatedAt:        hw.GetCreationTimestamp().Format("2006-01-02 15:04:05"),
+		Labels:           hw.Labels,
+		Annotations:      hw.Annotations,
+		AgentAttributes:  agentAttrs,
+		DHCPTransactions: GetHardwareDHCPTransactions(c, *hw),
+		SpecYAML:         string(specYAML),
+		StatusYAML:       string(statusYAML),
+		YAML:             string(yamlBytes),
 	}
 
 	cfg := templates.PageConfig{
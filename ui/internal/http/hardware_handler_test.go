@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/gin-gonic/gin"
+	"github.com/tinkerbell/tinkerbell/pkg/data"
 )
 
 func TestHandleHardwareList_Empty(t *testing.T) {
@@ -128,6 +129,49 @@ func TestHandleHardwareDetail_Found(t *testing.T) {
 	}
 }
 
+func TestHandleHardwareDetail_DHCPTransactions(t *testing.T) {
+	kubeClient := newFakeKubeClient(
+		newTestNamespace("default"),
+		newTestHardware("hw-1", "default", "aa:bb:cc:dd:ee:01", "192.168.1.1"),
+	)
+
+	c, w := setupTestContext("/hardware/default/hw-1", kubeClient)
+	c.Params = gin.Params{
+		{Key: "namespace", Value: "default"},
+		{Key: "name", Value: "hw-1"},
+	}
+	var gotMACs []string
+	c.Set(ContextKeyDHCPTransactions, func(macs ...string) []data.DHCPTransaction {
+		gotMACs = macs
+		return []data.DHCPTransaction{{
+			MAC:         "aa:bb:cc:dd:ee:01",
+			MessageType: "DISCOVER",
+			Mode:        "reservation",
+			ClientArch:  "EFI x86-64",
+			ReplyType:   "OFFER",
+			IPAddress:   "192.168.1.1",
+			Bootfile:    "ipxe.efi",
+			NextServer:  "192.168.1.254",
+		}}
+	})
+
+	HandleHardwareDetail(c, testLog)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if len(gotMACs) != 1 || gotMACs[0] != "aa:bb:cc:dd:ee:01" {
+		t.Errorf("looked up MACs = %v, want [aa:bb:cc:dd:ee:01]", gotMACs)
+	}
+	body := w.Body.String()
+	if !contains(body, "DHCP Transactions") {
+		t.Error("response should contain the DHCP transactions section")
+	}
+	if !contains(body, "ipxe.efi @ 192.168.1.254") {
+		t.Error("response should contain the bootfile and next server")
+	}
+}
+
 func TestHandleHardwareDetail_NotFound(t *testing.T) {
 	kubeClient := newFakeKubeClient(
 		newTestNamespace("default"),
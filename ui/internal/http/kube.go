@@ -12,6 +12,8 @@ import (
 	"github.com/go-logr/logr"
 	bmcv1alpha1 "github.com/tinkerbell/tinkerbell/api/v1alpha1/bmc"
 	tinkv1alpha1 "github.com/tinkerbell/tinkerbell/api/v1alpha1/tinkerbell"
+	"github.com/tinkerbell/tinkerbell/pkg/constant"
+	"github.com/tinkerbell/tinkerbell/pkg/data"
 	"github.com/tinkerbell/tinkerbell/ui/templates"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -32,6 +34,8 @@ const (
 	htmxRequestTrue     = "true"
 	// ContextKeyBaseURL is the key used to store the URL prefix in Gin context.
 	ContextKeyBaseURL = "baseURL"
+	// ContextKeyDHCPTransactions is the key used to store the DHCP transaction lookup in Gin context.
+	ContextKeyDHCPTransactions = "dhcpTransactions"
 
 	// Kubernetes API groups and RBAC identifiers for Tinkerbell resources.
 	groupTinkerbell = "tinkerbell.org"
@@ -76,6 +80,17 @@ func GetBaseURL(c *gin.Context) string {
 	return ""
 }
 
+// GetDHCPTransactions returns the recent DHCP transactions of the MAC addresses from the lookup in the Gin context.
+// Returns nil if no lookup is set, for example when Smee is not enabled.
+func GetDHCPTransactions(c *gin.Context, macs ...string) []data.DHCPTransaction {
+	if v, exists := c.Get(ContextKeyDHCPTransactions); exists {
+		if f, ok := v.(func(macs ...string) []data.DHCPTransaction); ok && len(macs) > 0 {
+			return f(macs...)
+		}
+	}
+	return nil
+}
+
 // KubeClient wraps a controller-runtime client for Kubernetes operations.
 type KubeClient struct {
 	client.Client
@@ -186,6 +201,15 @@ func (k *KubeClient) GetWorkflow(ctx context.Context, namespace, name string) (*
 	return &wf, nil
 }
 
+// GetWorkflowActionLogs returns the tail of the output of each Action of a workflow, keyed by Action ID.
+func (k *KubeClient) GetWorkflowActionLogs(ctx context.Context, namespace, name string) (map[string]string, error) {
+	var cm corev1.ConfigMap
+	if err := k.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name + constant.ActionLogsConfigMapSuffix}, &cm); err != nil {
+		return nil, fmt.Errorf("failed to get workflow action logs: %w", err)
+	}
+	return cm.Data, nil
+}
+
 // ListTemplates returns all template resources, optionally filtered by namespace.
 func (k *KubeClient) ListTemplates(ctx context.Context, namespace string) (*tinkv1alpha1.TemplateList, error) {
 	var tplList tinkv1alpha1.TemplateList
@@ -410,6 +434,50 @@ func GetHardwareInterfaces(hw tinkv1alpha1.Hardware) []templates.HardwareInterfa
 	return interfaces
 }
 
+// GetHardwareDHCPTransactions returns the recent DHCP transactions of the MAC addresses of a hardware, newest first.
+func GetHardwareDHCPTransactions(c *gin.Context, hw tinkv1alpha1.Hardware) []templates.DHCPTransaction {
+	var macs []string
+	for _, iface := range hw.Spec.Interfaces {
+		if iface.DHCP != nil && iface.DHCP.MAC != "" {
+			macs = append(macs, iface.DHCP.MAC)
+		}
+	}
+	txs := GetDHCPTransactions(c, macs...)
+	if len(txs) == 0 {
+		return nil
+	}
+
+	rows := make([]templates.DHCPTransaction, 0, len(txs))
+	for _, tx := range txs {
+		reason := tx.IgnoredReason
+		if reason == "" {
+			reason = tx.NetbootError
+		}
+		rows = append(rows, templates.DHCPTransaction{
+			Time:        tx.Time.Format("2006-01-02 15:04:05"),
+			MAC:         tx.MAC,
+			Mode:        tx.Mode,
+			MessageType: tx.MessageType,
+			Client:      joinNonEmpty(" / ", tx.VendorClass, tx.UserClass, tx.ClientArch),
+			Reply:       joinNonEmpty(" ", tx.ReplyType, tx.IPAddress),
+			Boot:        joinNonEmpty(" @ ", tx.Bootfile, tx.NextServer),
+			Reason:      reason,
+		})
+	}
+	return rows
+}
+
+// joinNonEmpty joins the non-empty values with sep.
+func joinNonEmpty(sep string, values ...string) string {
+	var parts []string
+	for _, v := range values {
+		if v != "" {
+			parts = append(parts, v)
+		}
+	}
+	return strings.Join(parts, sep)
+}
+
 // GetHardwareStatus determines hardware status.
 func GetHardwareStatus(hw tinkv1alpha1.Hardware) string {
 	if hw.Status.State == "provisioning" {
@@ -7,6 +7,7 @@ import (
 	"github.com/go-logr/logr"
 
 	"github.com/gin-gonic/gin"
+	tinkv1alpha1 "github.com/tinkerbell/tinkerbell/api/v1alpha1/tinkerbell"
 	"github.com/tinkerbell/tinkerbell/ui/templates"
 	"sigs.k8s.io/yaml"
 )
@@ -201,6 +202,12 @@ func HandleWorkflowDetail(c *gin.Context, log logr.Logger) {
 		agent = wf.Status.CurrentState.AgentID
 	}
 
+	// Action logs are optional, they only exist once an Agent has streamed them and the user may not be allowed to read them.
+	logs, err := client.GetWorkflowActionLogs(ctx, namespace, name)
+	if err != nil {
+		log.V(1).Info("Failed to fetch workflow action logs", "namespace", namespace, "name", name, "error", err)
+	}
+
 	wfDetail := templates.WorkflowDetail{
 		Name:              wf.Name,
 		Namespace:         wf.Namespace,
@@ -217,6 +224,7 @@ func HandleWorkflowDetail(c *gin.Context, log logr.Logger) {
 		SpecYAML:          string(specYAML),
 		StatusYAML:        string(statusYAML),
 		YAML:              string(yamlBytes),
+		ActionLogs:        actionLogs(wf.Status.Tasks, logs),
 	}
 
 	cfg := templates.PageConfig{
@@ -227,3 +235,23 @@ func HandleWorkflowDetail(c *gin.Context, log logr.Logger) {
 	c.Header("Content-Type", "text/html")
 	RenderComponent(c.Request.Context(), c.Writer, component, log)
 }
+
+// actionLogs returns the logs of the Actions that have them, in the order the Actions are in the Workflow status.
+func actionLogs(tasks []tinkv1alpha1.Task, logs map[string]string) []templates.ActionLog {
+	var out []templates.ActionLog
+	for _, task := range tasks {
+		for _, action := range task.Actions {
+			l, ok := logs[action.ID]
+			if !ok {
+				continue
+			}
+			out = append(out, templates.ActionLog{
+				Task:   task.Name,
+				Action: action.Name,
+				State:  string(action.State),
+				Logs:   l,
+			})
+		}
+	}
+	return out
+}
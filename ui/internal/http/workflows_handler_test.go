@@ -6,6 +6,8 @@ import (
 
 	"github.com/gin-gonic/gin"
 	tinkv1alpha1 "github.com/tinkerbell/tinkerbell/api/v1alpha1/tinkerbell"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestHandleWorkflowList_Empty(t *testing.T) {
@@ -80,6 +82,40 @@ func TestHandleWorkflowDetail_Found(t *testing.T) {
 	}
 }
 
+func TestHandleWorkflowDetail_ActionLogs(t *testing.T) {
+	wf := newTestWorkflow("wf-1", "template-1", tinkv1alpha1.WorkflowStateRunning)
+	wf.Status.Tasks = []tinkv1alpha1.Task{
+		{Name: "provision", Actions: []tinkv1alpha1.Action{{ID: "action-1", Name: "stream-image", State: tinkv1alpha1.WorkflowStateSuccess}}},
+	}
+	kubeClient := newFakeKubeClient(
+		newTestNamespace("default"),
+		wf,
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "wf-1-action-logs", Namespace: "default"},
+			Data:       map[string]string{"action-1": "wrote 2048 MiB to /dev/sda"},
+		},
+	)
+
+	c, w := setupTestContext("/workflows/default/wf-1", kubeClient)
+	c.Params = gin.Params{
+		{Key: "namespace", Value: "default"},
+		{Key: "name", Value: "wf-1"},
+	}
+
+	HandleWorkflowDetail(c, testLog)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	body := w.Body.String()
+	if !contains(body, "stream-image") {
+		t.Error("response should contain the action name")
+	}
+	if !contains(body, "wrote 2048 MiB to /dev/sda") {
+		t.Error("response should contain the action logs")
+	}
+}
+
 func TestHandleWorkflowDetail_NotFound(t *testing.T) {
 	kubeClient := newFakeKubeClient(
 		newTestNamespace("default"),
@@ -263,17 +263,34 @@ type AgentAttributes struct {
 
 // HardwareDetail is the data for the hardware detail page.
 type HardwareDetail struct {
-	Name            string
-	Namespace       string
-	Interfaces      []HardwareInterface
-	Status          string
-	CreatedAt       string
-	Labels          map[string]string
-	Annotations     map[string]string
-	AgentAttributes *AgentAttributes
-	SpecYAML        string
-	StatusYAML      string
-	YAML            string
+	Name             string
+	Namespace        string
+	Interfaces       []HardwareInterface
+	Status           string
+	CreatedAt        string
+	Labels           map[string]string
+	Annotations      map[string]string
+	AgentAttributes  *AgentAttributes
+	DHCPTransactions []DHCPTransaction
+	SpecYAML         string
+	StatusYAML       string
+	YAML             string
+}
+
+// DHCPTransaction is a DHCP message received by Smee for one of the MAC addresses of a Hardware.
+type DHCPTransaction struct {
+	Time        string
+	MAC         string
+	Mode        string
+	MessageType string
+	// Client is the vendor class, user class and architecture sent by the client.
+	Client string
+	// Reply is the message type and IP address of the reply.
+	Reply string
+	// Boot is the boot file name and next server of the reply.
+	Boot string
+	// Reason is why no reply, or no netboot options, were sent.
+	Reason string
 }
 
 // WorkflowDetail is the data for the workflow detail page.
@@ -293,6 +310,15 @@ type WorkflowDetail struct {
 	SpecYAML          string
 	StatusYAML        string
 	YAML              string
+	ActionLogs        []ActionLog
+}
+
+// ActionLog is the tail of the output of a single Workflow Action.
+type ActionLog struct {
+	Task   string
+	Action string
+	State  string
+	Logs   string
 }
 
 // TemplateDetail is the data for the template detail page.
@@ -11,6 +11,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-logr/logr"
+	"github.com/tinkerbell/tinkerbell/pkg/data"
 	"github.com/tinkerbell/tinkerbell/ui/assets"
 	webhttp "github.com/tinkerbell/tinkerbell/ui/internal/http"
 	"k8s.io/client-go/rest"
@@ -34,6 +35,9 @@ type Config struct {
 	AutoLoginRestConfig *rest.Config
 	// AutoLoginNamespace is the namespace to use for namespace-scoped fallbacks when EnableAutoLogin is true.
 	AutoLoginNamespace string
+	// DHCPTransactions returns the recent DHCP transactions of the MAC addresses, newest first.
+	// When nil, the hardware detail page does not show DHCP transactions.
+	DHCPTransactions func(macs ...string) []data.DHCPTransaction
 }
 
 type Option func(*Config)
@@ -103,6 +107,9 @@ func (c *Config) Handler(log logr.Logger) (http.Handler, error) {
 	// Set baseURL in context for all routes under base
 	base.Use(func(gc *gin.Context) {
 		gc.Set(webhttp.ContextKeyBaseURL, templateBaseURL)
+		if c.DHCPTransactions != nil {
+			gc.Set(webhttp.ContextKeyDHCPTransactions, c.DHCPTransactions)
+		}
 		gc.Next()
 	})
 